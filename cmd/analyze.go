@@ -74,7 +74,7 @@ func init() {
 	analyzeCmd.Flags().StringVar(&analyzeReportsDirFlag, "reports-dir", "", "Directory containing reports (overrides config)")
 	analyzeCmd.Flags().StringVarP(&analyzeFormatFlag, "format", "f", "text", "Output format: text (default) or json")
 	analyzeCmd.Flags().StringVarP(&analyzeOutputFlag, "output", "o", "", "[deprecated] Alias for --format")
-	analyzeCmd.Flags().StringVarP(&analyzeEngineFlag, "engine", "e", "codex", "Engine to use (claude, codex, pi)")
+	analyzeCmd.Flags().StringVarP(&analyzeEngineFlag, "engine", "e", "codex", "Engine to use (claude, codex, gemini, pi)")
 	analyzeCmd.Flags().BoolVar(&analyzeJSONFlag, "json", false, "Output as JSON (shorthand for --format json)")
 	rootCmd.AddCommand(analyzeCmd)
 }
@@ -23,6 +23,8 @@ func TestNoArgsCommandsRejectExtraArgsWithValidationCode(t *testing.T) {
 		{name: "archive list", cmd: archiveListCmd},
 		{name: "standards list", cmd: standardsListCmd},
 		{name: "standards discover", cmd: standardsDiscoverCmd},
+		{name: "standards check", cmd: standardsCheckCmd},
+		{name: "learnings consolidate", cmd: learningsConsolidateCmd},
 		{name: "sandbox setup", cmd: sandboxSetupCmd},
 		{name: "sandbox create", cmd: sandboxCreateCmd},
 	}
@@ -194,7 +194,7 @@ func init() {
 	autoCmd.Flags().IntVar(&autoReviewStreakFlag, "review-streak", 0, "Consecutive clean review cycles required (default from mode/config)")
 	autoCmd.Flags().IntVar(&autoReviewMaxFlag, "review-max", 0, "Maximum review cycles before failing (default from mode/config)")
 	autoCmd.Flags().StringVar(&autoReportFlag, "report", "", "Specific report file (overrides markdown auto-discovery, skips find latest)")
-	autoCmd.Flags().StringVarP(&autoEngineFlag, "engine", "e", "codex", "Engine to use (claude, codex, pi)")
+	autoCmd.Flags().StringVarP(&autoEngineFlag, "engine", "e", "codex", "Engine to use (claude, codex, gemini, pi)")
 	autoCmd.Flags().StringVarP(&autoBaseFlag, "base", "b", "", "Base branch for new work branch and PR target (default: current branch, or HEAD when detached)")
 	autoCmd.Flags().BoolVar(&autoJSONFlag, "json", false, "Output machine-readable JSON result")
 	rootCmd.AddCommand(autoCmd)
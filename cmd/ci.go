@@ -4,15 +4,18 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"slices"
 	"strings"
 	"time"
 
 	ci "github.com/jywlabs/hal/internal/ci"
 	"github.com/jywlabs/hal/internal/engine"
+	"github.com/jywlabs/hal/internal/template"
 	"github.com/spf13/cobra"
 )
 
@@ -62,6 +65,8 @@ var ciPushCmd = &cobra.Command{
 	Long: `Push the current branch to origin and create or reuse an open pull request.
 
 By default, this command delegates to the shared CI core operation.
+When .hal/prd.json links GitHub issues (hal plan --from-issue), a new pull
+request closes them and each issue gets a comment as stories pass.
 Use --dry-run to preview behavior with no remote side effects.
 Use --json for machine-readable output.`,
 	Example: `  hal ci push
@@ -129,7 +134,7 @@ func init() {
 	ciStatusCmd.Flags().BoolVar(&ciStatusJSONFlag, "json", false, "Output machine-readable JSON result")
 
 	ciFixCmd.Flags().IntVar(&ciFixMaxAttemptsFlag, "max-attempts", 3, "Max fix attempts before stopping")
-	ciFixCmd.Flags().StringVarP(&ciFixEngineFlag, "engine", "e", "codex", "Engine to use (claude, codex, pi)")
+	ciFixCmd.Flags().StringVarP(&ciFixEngineFlag, "engine", "e", "codex", "Engine to use (claude, codex, gemini, pi)")
 	ciFixCmd.Flags().BoolVar(&ciFixJSONFlag, "json", false, "Output machine-readable JSON result")
 
 	ciMergeCmd.Flags().StringVar(&ciMergeStrategyFlag, "strategy", "squash", "Merge strategy (squash, merge, rebase)")
@@ -146,13 +151,56 @@ func init() {
 }
 
 type ciPushDeps struct {
-	pushAndCreatePR func(context.Context, ci.PushOptions) (ci.PushResult, error)
-	currentBranch   func(context.Context) (string, error)
+	pushAndCreatePR     func(context.Context, ci.PushOptions) (ci.PushResult, error)
+	currentBranch       func(context.Context) (string, error)
+	loadPRD             func() (*engine.PRD, error)
+	savePRD             func(*engine.PRD) error
+	reportIssueProgress func(context.Context, *engine.PRD, string) []ci.IssueProgress
 }
 
 var defaultCIPushDeps = ciPushDeps{
-	pushAndCreatePR: ci.PushAndCreatePR,
-	currentBranch:   ciCurrentBranch,
+	pushAndCreatePR:     ci.PushAndCreatePR,
+	currentBranch:       ciCurrentBranch,
+	loadPRD:             ciLoadLinkedPRD,
+	savePRD:             ciSaveLinkedPRD,
+	reportIssueProgress: ci.ReportIssueProgress,
+}
+
+// ciLoadLinkedPRD loads .hal/prd.json for issue links. A missing PRD is not
+// an error; pushes work without one.
+func ciLoadLinkedPRD() (*engine.PRD, error) {
+	prd, err := engine.LoadPRD(template.HalDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return prd, err
+}
+
+// ciSaveLinkedPRD records the stories reported on each linked issue. The PRD
+// is re-read first and only reportedStories changes, so edits made while the
+// push ran (for example by a hal run loop) are kept.
+func ciSaveLinkedPRD(prd *engine.PRD) error {
+	current, err := engine.LoadPRD(template.HalDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for i := range current.Issues {
+		ref := &current.Issues[i]
+		for _, updated := range prd.Issues {
+			if updated.Repo != ref.Repo || updated.Number != ref.Number {
+				continue
+			}
+			for _, id := range updated.ReportedStories {
+				if !slices.Contains(ref.ReportedStories, id) {
+					ref.ReportedStories = append(ref.ReportedStories, id)
+				}
+			}
+		}
+	}
+	return engine.SavePRD(template.HalDir, current)
 }
 
 type ciPushRunOptions struct {
@@ -335,6 +383,26 @@ func runCIPushWithDeps(ctx context.Context, opts ciPushRunOptions, out io.Writer
 	if deps.currentBranch == nil {
 		deps.currentBranch = defaultCIPushDeps.currentBranch
 	}
+	if deps.loadPRD == nil {
+		deps.loadPRD = ciLoadLinkedPRD
+	}
+	if deps.savePRD == nil {
+		deps.savePRD = ciSaveLinkedPRD
+	}
+	if deps.reportIssueProgress == nil {
+		deps.reportIssueProgress = ci.ReportIssueProgress
+	}
+
+	// Issues linked by 'hal plan --from-issue' are closed by the pull request
+	// and get progress comments. An unreadable PRD only disables that.
+	linkedPRD, prdErr := deps.loadPRD()
+	if prdErr != nil && !opts.JSON {
+		fmt.Fprintf(out, "%s\n", engine.StyleWarning.Render(fmt.Sprintf("warning: could not read PRD issue links: %v", prdErr)))
+	}
+	var linkedIssues []engine.IssueRef
+	if linkedPRD != nil {
+		linkedIssues = linkedPRD.Issues
+	}
 
 	if !opts.JSON {
 		headerContext := "push current branch and create or reuse a pull request"
@@ -367,10 +435,18 @@ func runCIPushWithDeps(ctx context.Context, opts ciPushRunOptions, out io.Writer
 			Summary: fmt.Sprintf("dry-run: would push branch %s and create or reuse a pull request", branch),
 		}
 	} else {
-		result, err = deps.pushAndCreatePR(ctx, ci.PushOptions{})
+		result, err = deps.pushAndCreatePR(ctx, ci.PushOptions{CloseIssues: linkedIssues})
 		if err != nil {
 			return err
 		}
+		if len(linkedIssues) > 0 {
+			result.IssueProgress = deps.reportIssueProgress(ctx, linkedPRD, result.PullRequest.URL)
+			if ciIssueProgressCommented(result.IssueProgress) {
+				if err := deps.savePRD(linkedPRD); err != nil && !opts.JSON {
+					fmt.Fprintf(out, "%s\n", engine.StyleWarning.Render(fmt.Sprintf("warning: could not record reported stories: %v", err)))
+				}
+			}
+		}
 	}
 
 	if opts.JSON {
@@ -390,6 +466,9 @@ func runCIPushWithDeps(ctx context.Context, opts ciPushRunOptions, out io.Writer
 			prMode = "Create or reuse ready-for-review pull request"
 		}
 		ciWriteField(out, "PR:", engine.StyleMuted.Render(prMode))
+		if len(linkedIssues) > 0 {
+			ciWriteField(out, "Issues:", engine.StyleMuted.Render("Closes "+ciIssueList(linkedIssues)))
+		}
 		fmt.Fprintln(out)
 		fmt.Fprintf(out, "%s\n", engine.StyleMuted.Render("Would push branch and create or reuse a pull request."))
 		return nil
@@ -423,9 +502,35 @@ func runCIPushWithDeps(ctx context.Context, opts ciPushRunOptions, out io.Writer
 		prDetail += " · New"
 	}
 	fmt.Fprintf(out, "%s%s\n", strings.Repeat(" ", ciFieldValueColumn), engine.StyleMuted.Render(prDetail))
+
+	for _, progress := range result.IssueProgress {
+		switch {
+		case progress.Error != "":
+			ciWriteField(out, "Issue:", engine.StyleWarning.Render(fmt.Sprintf("⚠ %s progress comment failed: %s", progress.Issue, progress.Error)))
+		case progress.Commented:
+			ciWriteField(out, "Issue:", engine.StyleSuccess.Render(fmt.Sprintf("✓ %s commented (%s)", progress.Issue, strings.Join(progress.Stories, ", "))))
+		}
+	}
 	return nil
 }
 
+func ciIssueProgressCommented(progress []ci.IssueProgress) bool {
+	for _, p := range progress {
+		if p.Commented {
+			return true
+		}
+	}
+	return false
+}
+
+func ciIssueList(issues []engine.IssueRef) string {
+	refs := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		refs = append(refs, fmt.Sprintf("%s#%d", issue.Repo, issue.Number))
+	}
+	return strings.Join(refs, ", ")
+}
+
 func runCIStatus(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 	if cmd != nil && cmd.Context() != nil {
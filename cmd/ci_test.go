@@ -15,6 +15,7 @@ import (
 
 	ci "github.com/jywlabs/hal/internal/ci"
 	"github.com/jywlabs/hal/internal/engine"
+	"github.com/jywlabs/hal/internal/template"
 	"github.com/spf13/cobra"
 )
 
@@ -1616,3 +1617,92 @@ func TestRunCIMerge_UsesCommandFlagValues(t *testing.T) {
 		t.Fatal("dryRun = true, want false")
 	}
 }
+
+func TestRunCIPushWithDeps_LinkedIssues(t *testing.T) {
+	prd := &engine.PRD{
+		BranchName:  "hal/export",
+		UserStories: []engine.UserStory{{ID: "US-001", Title: "CSV export", Passes: true}},
+		Issues:      []engine.IssueRef{{Repo: "acme/repo", Number: 42}},
+	}
+	var gotOpts ci.PushOptions
+	saved := false
+
+	var buf bytes.Buffer
+	err := runCIPushWithDeps(context.Background(), ciPushRunOptions{}, &buf, ciPushDeps{
+		pushAndCreatePR: func(_ context.Context, opts ci.PushOptions) (ci.PushResult, error) {
+			gotOpts = opts
+			return ci.PushResult{
+				Branch:      "hal/export",
+				Pushed:      true,
+				PullRequest: ci.PullRequest{Number: 7, URL: "https://github.com/acme/repo/pull/7", BaseRef: "main"},
+			}, nil
+		},
+		currentBranch: func(context.Context) (string, error) { return "hal/export", nil },
+		loadPRD:       func() (*engine.PRD, error) { return prd, nil },
+		savePRD: func(p *engine.PRD) error {
+			saved = p == prd
+			return nil
+		},
+		reportIssueProgress: func(_ context.Context, p *engine.PRD, prURL string) []ci.IssueProgress {
+			if prURL != "https://github.com/acme/repo/pull/7" {
+				t.Fatalf("prURL = %q", prURL)
+			}
+			p.Issues[0].ReportedStories = []string{"US-001"}
+			return []ci.IssueProgress{{Issue: "acme/repo#42", Stories: []string{"US-001"}, Commented: true}}
+		},
+	})
+	if err != nil {
+		t.Fatalf("runCIPushWithDeps() error = %v", err)
+	}
+	if !reflect.DeepEqual(gotOpts.CloseIssues, prd.Issues) {
+		t.Fatalf("CloseIssues = %+v, want PRD issues", gotOpts.CloseIssues)
+	}
+	if !saved {
+		t.Fatal("expected PRD to be saved after a progress comment")
+	}
+	if !strings.Contains(buf.String(), "acme/repo#42 commented (US-001)") {
+		t.Fatalf("output missing issue progress:\n%s", buf.String())
+	}
+}
+
+func TestCISaveLinkedPRD_KeepsConcurrentEdits(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.MkdirAll(template.HalDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	pushed := &engine.PRD{
+		UserStories: []engine.UserStory{{ID: "US-001", Title: "Export", Passes: true}},
+		Issues:      []engine.IssueRef{{Repo: "acme/repo", Number: 42}},
+	}
+	// While the push ran, hal run completed another story.
+	onDisk := &engine.PRD{
+		UserStories: []engine.UserStory{
+			{ID: "US-001", Title: "Export", Passes: true},
+			{ID: "US-002", Title: "Import", Passes: true},
+		},
+		Issues: []engine.IssueRef{{Repo: "acme/repo", Number: 42, ReportedStories: []string{"US-000"}}},
+	}
+	if err := engine.SavePRD(template.HalDir, onDisk); err != nil {
+		t.Fatal(err)
+	}
+
+	pushed.Issues[0].ReportedStories = []string{"US-001"}
+	if err := ciSaveLinkedPRD(pushed); err != nil {
+		t.Fatalf("ciSaveLinkedPRD() error = %v", err)
+	}
+
+	got, err := engine.LoadPRD(template.HalDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.UserStories) != 2 || !got.UserStories[1].Passes {
+		t.Errorf("stories = %+v, want the concurrent edit kept", got.UserStories)
+	}
+	if want := []string{"US-000", "US-001"}; !reflect.DeepEqual(got.Issues[0].ReportedStories, want) {
+		t.Errorf("reportedStories = %v, want %v", got.Issues[0].ReportedStories, want)
+	}
+	entries, _ := os.ReadDir(template.HalDir)
+	if len(entries) != 1 {
+		t.Errorf(".hal entries = %v, want only prd.json (no temp files left)", entries)
+	}
+}
@@ -237,7 +237,7 @@ Describe what this rule is for.
 }
 
 func printDefaults(out io.Writer) {
-	fmt.Fprintln(out, "  engine: codex           # Options: claude, codex, pi")
+	fmt.Fprintln(out, "  engine: codex           # Options: claude, codex, gemini, pi")
 	fmt.Fprintln(out, "  maxIterations: 10")
 	fmt.Fprintln(out, "  retryDelay: 30s")
 	fmt.Fprintln(out, "  maxRetries: 3")
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	display "github.com/jywlabs/hal/internal/engine"
+	"github.com/jywlabs/hal/internal/repomap"
+	"github.com/spf13/cobra"
+)
+
+var (
+	contextShowFlag   bool
+	contextJSONFlag   bool
+	contextBudgetFlag int
+	contextFocusFlag  string
+)
+
+// ContextSection summarizes one section of the context pack.
+type ContextSection struct {
+	Name      string `json:"name"`
+	Title     string `json:"title"`
+	Bytes     int    `json:"bytes"`
+	Truncated bool   `json:"truncated,omitempty"`
+	Content   string `json:"content,omitempty"`
+}
+
+// ContextResult is the machine-readable output of hal context --json.
+type ContextResult struct {
+	ContractVersion int              `json:"contractVersion"`
+	Budget          int              `json:"budget"`
+	Bytes           int              `json:"bytes"`
+	Sections        []ContextSection `json:"sections"`
+}
+
+var contextCmd = &cobra.Command{
+	Use:   "context",
+	Short: "Inspect the codebase context pack sent to engines",
+	Args:  noArgsValidation(),
+	Long: `Inspect the codebase context pack hal includes in plan, convert,
+analyze and report prompts.
+
+The pack is a size-budgeted repo map built from:
+  - Stack markers (go.mod, package.json, Cargo.toml, ...)
+  - Directory tree with file counts
+  - Exported symbols of the most relevant Go packages
+  - README.md and AGENTS.md excerpts
+  - The .hal/standards index
+  - Recent commit subjects
+  - Existing .hal PRDs
+
+Without flags, prints a summary of each section and its size.
+Use --show to print the pack exactly as engines receive it, and --focus
+to rank packages the way 'hal plan' does for a feature description.`,
+	Example: `  hal context
+  hal context --show
+  hal context --show --focus "sandbox reconcile"
+  hal context --budget 8000 --json`,
+	RunE: runContext,
+}
+
+func init() {
+	contextCmd.Flags().BoolVar(&contextShowFlag, "show", false, "Print the full context pack")
+	contextCmd.Flags().BoolVar(&contextJSONFlag, "json", false, "Output machine-readable JSON")
+	contextCmd.Flags().IntVar(&contextBudgetFlag, "budget", repomap.DefaultBudget, "Maximum pack size in bytes")
+	contextCmd.Flags().StringVar(&contextFocusFlag, "focus", "", "Feature description used to rank packages")
+	rootCmd.AddCommand(contextCmd)
+}
+
+func runContext(cmd *cobra.Command, args []string) error {
+	out := io.Writer(os.Stdout)
+	if cmd != nil {
+		out = cmd.OutOrStdout()
+	}
+	if contextBudgetFlag <= 0 {
+		return exitWithCode(cmd, ExitCodeValidation, fmt.Errorf("--budget must be greater than zero"))
+	}
+	return runContextFn(".", out, repomap.Options{Budget: contextBudgetFlag, Focus: contextFocusFlag}, contextShowFlag, contextJSONFlag)
+}
+
+func runContextFn(dir string, out io.Writer, opts repomap.Options, show, jsonMode bool) error {
+	pack := repomap.Build(dir, opts)
+	rendered := pack.Render()
+
+	if jsonMode {
+		result := ContextResult{ContractVersion: 1, Budget: pack.Budget, Bytes: len(rendered)}
+		for _, s := range pack.Sections {
+			section := ContextSection{Name: s.Name, Title: s.Title, Bytes: len(s.Content), Truncated: s.Truncated}
+			if show {
+				section.Content = s.Content
+			}
+			result.Sections = append(result.Sections, section)
+		}
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal context: %w", err)
+		}
+		fmt.Fprintln(out, string(data))
+		return nil
+	}
+
+	if show {
+		fmt.Fprint(out, rendered)
+		return nil
+	}
+
+	display.NewDisplay(out).ShowCommandHeader("Context", fmt.Sprintf("%d of %d bytes", len(rendered), pack.Budget), display.HeaderContext{})
+	if len(pack.Sections) == 0 {
+		fmt.Fprintf(out, "  %s\n", display.StyleMuted.Render("No codebase information found."))
+		return nil
+	}
+	for _, s := range pack.Sections {
+		line := fmt.Sprintf("  %-20s %6d bytes", s.Title, len(s.Content))
+		if s.Truncated {
+			line += " " + display.StyleWarning.Render("(truncated)")
+		}
+		fmt.Fprintln(out, line)
+	}
+	fmt.Fprintf(out, "\n  %s\n", display.StyleMuted.Render("Run hal context --show to print the full pack."))
+	return nil
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jywlabs/hal/internal/repomap"
+)
+
+func writeContextFixture(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":             "module example.com/shop\n",
+		"README.md":          "# Shop\n\nAn online shop.\n",
+		"billing/billing.go": "package billing\n\nfunc Export() {}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestRunContextFn_Summary(t *testing.T) {
+	dir := writeContextFixture(t)
+
+	var buf bytes.Buffer
+	if err := runContextFn(dir, &buf, repomap.Options{}, false, false); err != nil {
+		t.Fatalf("runContextFn() error = %v", err)
+	}
+	for _, want := range []string{"Stack", "Exported Symbols", "README (excerpt)", "hal context --show"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, buf.String())
+		}
+	}
+	if strings.Contains(buf.String(), "Export()") {
+		t.Errorf("summary should not print section content:\n%s", buf.String())
+	}
+}
+
+func TestRunContextFn_Show(t *testing.T) {
+	dir := writeContextFixture(t)
+
+	var buf bytes.Buffer
+	if err := runContextFn(dir, &buf, repomap.Options{}, true, false); err != nil {
+		t.Fatalf("runContextFn() error = %v", err)
+	}
+	for _, want := range []string{"#### Stack", "Go module example.com/shop", "billing: Export()", "An online shop."} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestRunContextFn_JSON(t *testing.T) {
+	dir := writeContextFixture(t)
+
+	var buf bytes.Buffer
+	if err := runContextFn(dir, &buf, repomap.Options{Budget: 5000}, false, true); err != nil {
+		t.Fatalf("runContextFn() error = %v", err)
+	}
+	var result ContextResult
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("JSON unmarshal error: %v\noutput: %s", err, buf.String())
+	}
+	if result.ContractVersion != 1 || result.Budget != 5000 || result.Bytes == 0 {
+		t.Fatalf("result = %+v", result)
+	}
+	for _, s := range result.Sections {
+		if s.Content != "" {
+			t.Errorf("section %q content should be omitted without --show", s.Name)
+		}
+	}
+}
@@ -79,9 +79,17 @@ func runContinueFn(dir string, jsonMode bool, out io.Writer) error {
 	statusResult := status.Get(dir)
 
 	engine, _ := compound.LoadDefaultEngine(dir)
+	// Reuse fresh hal doctor --deep results; probing here would make continue
+	// slow. Cached failures come back as warnings so they never block.
+	engines, _ := compound.ConfiguredEngines(dir)
 	doctorResult := doctor.Run(doctor.Options{
-		Dir:    dir,
-		Engine: engine,
+		Dir:          dir,
+		Engine:       engine,
+		CachedProbes: true,
+		Engines:      engines,
+		EngineConfig: func(name string) *ui.EngineConfig {
+			return compound.LoadEngineConfig(dir, name)
+		},
 	})
 
 	// Determine what to do: only doctor failures should block workflow progress.
@@ -5,9 +5,12 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/jywlabs/hal/internal/doctor"
 	"github.com/jywlabs/hal/internal/skills"
 	"github.com/jywlabs/hal/internal/status"
 	"github.com/jywlabs/hal/internal/template"
@@ -355,6 +358,33 @@ func TestRunContinueFn_WarningsDoNotBlockReadiness(t *testing.T) {
 	}
 }
 
+func TestRunContinueFn_CachedProbeFailureDoesNotBlock(t *testing.T) {
+	dir := t.TempDir()
+	halDir := setupHealthyContinueRepo(t, dir)
+	cache := map[string]any{"probes": map[string]doctor.EngineProbe{
+		"pi": {Engine: "pi", Installed: true, Version: "0.9", Auth: doctor.AuthFailed, Error: "not logged in", ProbedAt: time.Now()},
+	}}
+	data, _ := json.Marshal(cache)
+	if err := os.WriteFile(filepath.Join(halDir, template.EngineProbeFile), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := runContinueFn(dir, true, &buf); err != nil {
+		t.Fatalf("runContinueFn() error = %v", err)
+	}
+	var result ContinueResult
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("JSON unmarshal error: %v\noutput: %s", err, buf.String())
+	}
+	if !result.Ready || result.Doctor.OverallStatus != "warn" {
+		t.Fatalf("ready = %v, doctor.overallStatus = %q; want ready with a warning", result.Ready, result.Doctor.OverallStatus)
+	}
+	if !slices.Contains(result.Doctor.Warnings, "pi_auth") {
+		t.Errorf("doctor.warnings = %v, want pi_auth", result.Doctor.Warnings)
+	}
+}
+
 func TestRunContinueFn_NoRedundantThen(t *testing.T) {
 	// When doctor fix == workflow next, don't show "Then:"
 	dir := t.TempDir()
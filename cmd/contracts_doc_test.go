@@ -24,12 +24,25 @@ func TestContractDocsExist(t *testing.T) {
 		{"status-v1", "../docs/contracts/status-v1.md"},
 		{"doctor-v1", "../docs/contracts/doctor-v1.md"},
 		{"continue-v1", "../docs/contracts/continue-v1.md"},
+		{"context-v1", "../docs/contracts/context-v1.md"},
+		{"plan-questions-v1", "../docs/contracts/plan-questions-v1.md"},
+		{"prd-story-v1", "../docs/contracts/prd-story-v1.md"},
 		{"sandbox-list-v1", "../docs/contracts/sandbox-list-v1.md"},
+		{"sandbox-cost-v1", "../docs/contracts/sandbox-cost-v1.md"},
+		{"sandbox-reap-v1", "../docs/contracts/sandbox-reap-v1.md"},
+		{"sandbox-reconcile-v1", "../docs/contracts/sandbox-reconcile-v1.md"},
+		{"sandbox-providers-v1", "../docs/contracts/sandbox-providers-v1.md"},
+		{"hal-provider-v1", "../docs/contracts/hal-provider-v1.md"},
 		{"auto-v2", "../docs/contracts/auto-v2.md"},
 		{"ci-push-v1", "../docs/contracts/ci-push-v1.md"},
 		{"ci-status-v1", "../docs/contracts/ci-status-v1.md"},
 		{"ci-fix-v1", "../docs/contracts/ci-fix-v1.md"},
 		{"ci-merge-v1", "../docs/contracts/ci-merge-v1.md"},
+		{"standards-selection-v1", "../docs/contracts/standards-selection-v1.md"},
+		{"standards-check-v1", "../docs/contracts/standards-check-v1.md"},
+		{"standards-update-v1", "../docs/contracts/standards-update-v1.md"},
+		{"learnings-consolidate-v1", "../docs/contracts/learnings-consolidate-v1.md"},
+		{"skills-v1", "../docs/contracts/skills-v1.md"},
 	}
 
 	for _, doc := range requiredDocs {
@@ -21,6 +21,7 @@ var (
 	convertForceFlag    bool
 	convertGranularFlag bool
 	convertBranchFlag   string
+	convertMergeFlag    bool
 	convertJSONFlag     bool
 )
 
@@ -30,7 +31,9 @@ type ConvertResult struct {
 	OK              bool   `json:"ok"`
 	OutputPath      string `json:"outputPath"`
 	Valid           *bool  `json:"valid,omitempty"`
-	Summary         string `json:"summary"`
+	// Merge is the story diff of a --merge conversion.
+	Merge   *prd.MergeReport `json:"merge,omitempty"`
+	Summary string           `json:"summary"`
 }
 
 var convertCmd = &cobra.Command{
@@ -50,6 +53,15 @@ Safety controls:
 - --archive is only supported when output is canonical .hal/prd.json.
 - Canonical writes are protected from branchName switches; use --archive or --force to override.
 
+Merging an edited PRD:
+- --merge regenerates stories but keeps progress from the existing output file.
+- Stories are matched by title, then by ID with a similar title; matched stories
+  keep their ID, passes, skipped flag, notes and hand-edited verify commands,
+  and the existing branchName is kept.
+- Completed stories whose acceptance criteria changed are reset for
+  re-verification; skipped ones are un-skipped.
+- The added, removed and changed stories are printed before writing.
+
 Examples:
   hal convert                                # Auto-discover source (no archive)
   hal convert .hal/prd-auth.md              # Explicit source path
@@ -57,6 +69,7 @@ Examples:
   hal convert .hal/prd.md --force           # Override branch mismatch guard
   hal convert .hal/prd.md --branch hal/my-feature
   hal convert .hal/prd.md --granular        # 8-15 atomic T-XXX tasks
+  hal convert .hal/prd.md --merge           # Keep progress after editing the PRD
   hal convert .hal/prd.md -o custom.json    # Custom output path (no archive)
   hal convert .hal/prd.md --validate        # Also validate after conversion
   hal convert .hal/prd.md -e claude         # Use Claude engine
@@ -65,6 +78,7 @@ Examples:
   hal convert --json
   hal convert --archive
   hal convert --granular
+  hal convert --merge
   hal convert --branch hal/my-feature
   hal convert .hal/prd-auth.md --validate
   hal convert .hal/prd-auth.md --force
@@ -74,13 +88,14 @@ Examples:
 }
 
 func init() {
-	convertCmd.Flags().StringVarP(&convertEngineFlag, "engine", "e", "codex", "Engine to use (claude, codex, pi)")
+	convertCmd.Flags().StringVarP(&convertEngineFlag, "engine", "e", "codex", "Engine to use (claude, codex, gemini, pi)")
 	convertCmd.Flags().StringVarP(&convertOutputFlag, "output", "o", "", "Output path (default: .hal/prd.json)")
 	convertCmd.Flags().BoolVar(&convertValidateFlag, "validate", false, "Validate PRD after conversion")
 	convertCmd.Flags().BoolVar(&convertArchiveFlag, "archive", false, "Archive existing feature state before writing canonical .hal/prd.json")
 	convertCmd.Flags().BoolVar(&convertForceFlag, "force", false, "Allow canonical overwrite without archive when branch mismatch protection would block")
 	convertCmd.Flags().BoolVar(&convertGranularFlag, "granular", false, "Decompose into 8-15 atomic tasks (T-XXX IDs) for autonomous execution")
 	convertCmd.Flags().StringVar(&convertBranchFlag, "branch", "", "Pin generated branchName (overrides markdown-derived branch)")
+	convertCmd.Flags().BoolVar(&convertMergeFlag, "merge", false, "Merge into the existing PRD, keeping story progress (passes, notes)")
 	convertCmd.Flags().BoolVar(&convertJSONFlag, "json", false, "Output machine-readable JSON result")
 	rootCmd.AddCommand(convertCmd)
 }
@@ -112,6 +127,10 @@ func runConvertWithDeps(cmd *cobra.Command, args []string, deps convertDeps) err
 	}
 	// mdPath = "" means auto-discover via skill
 
+	if convertMergeFlag && convertArchiveFlag {
+		return fmt.Errorf("--merge cannot be combined with --archive")
+	}
+
 	// Determine output path
 	outPath := convertOutputFlag
 	if outPath == "" {
@@ -145,6 +164,11 @@ func runConvertWithDeps(cmd *cobra.Command, args []string, deps convertDeps) err
 		Force:      convertForceFlag,
 		Granular:   convertGranularFlag,
 		BranchName: convertBranchFlag,
+		Merge:      convertMergeFlag,
+	}
+	var mergeReport prd.MergeReport
+	if convertMergeFlag {
+		opts.MergeReport = &mergeReport
 	}
 
 	// Convert
@@ -160,6 +184,9 @@ func runConvertWithDeps(cmd *cobra.Command, args []string, deps convertDeps) err
 			OutputPath:      outPath,
 			Summary:         fmt.Sprintf("Conversion complete. Output: %s", outPath),
 		}
+		if convertMergeFlag {
+			jr.Merge = &mergeReport
+		}
 
 		// Optionally validate in JSON mode
 		if convertValidateFlag {
@@ -42,6 +42,7 @@ func preserveConvertFlags(t *testing.T) {
 	origForce := convertForceFlag
 	origGranular := convertGranularFlag
 	origBranch := convertBranchFlag
+	origMerge := convertMergeFlag
 	origJSON := convertJSONFlag
 	t.Cleanup(func() {
 		convertEngineFlag = origEngine
@@ -51,6 +52,7 @@ func preserveConvertFlags(t *testing.T) {
 		convertForceFlag = origForce
 		convertGranularFlag = origGranular
 		convertBranchFlag = origBranch
+		convertMergeFlag = origMerge
 		convertJSONFlag = origJSON
 	})
 }
@@ -90,6 +92,7 @@ func TestConvertUsageIncludesSafetyAndBranchFlags(t *testing.T) {
 		"--force",
 		"--granular",
 		"--branch",
+		"--merge",
 		"Archive existing feature state before writing canonical .hal/prd.json",
 		"Allow canonical overwrite without archive when branch mismatch protection would block",
 		"Decompose into 8-15 atomic tasks (T-XXX IDs) for autonomous execution",
@@ -416,3 +419,21 @@ func TestRunConvertWithDeps_ExplicitSourceMustExist(t *testing.T) {
 		t.Fatal("convertWithEngine should not be called when markdown source is missing")
 	}
 }
+
+func TestRunConvertWithDeps_MergeRejectsArchive(t *testing.T) {
+	preserveConvertFlags(t)
+
+	convertMergeFlag = true
+	convertArchiveFlag = true
+	deps := convertDeps{
+		newEngine: func(string) (engine.Engine, error) {
+			t.Fatal("newEngine should not be called")
+			return nil, nil
+		},
+	}
+
+	err := runConvertWithDeps(nil, nil, deps)
+	if err == nil || !strings.Contains(err.Error(), "--merge cannot be combined with --archive") {
+		t.Fatalf("error = %v, want merge/archive conflict", err)
+	}
+}
@@ -0,0 +1,240 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jywlabs/hal/internal/engine"
+	"github.com/jywlabs/hal/internal/loop"
+	"github.com/jywlabs/hal/internal/prd"
+	"github.com/jywlabs/hal/internal/runstatus"
+	"github.com/jywlabs/hal/internal/template"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ctlToFlag     int
+	ctlBeforeFlag string
+	ctlAfterFlag  string
+)
+
+// hal ctl actions.
+const (
+	ctlPause            = "pause"
+	ctlResume           = "resume"
+	ctlSkip             = "skip"
+	ctlStopAfterCurrent = "stop-after-current"
+	ctlReprioritize     = "reprioritize"
+)
+
+var ctlCmd = &cobra.Command{
+	Use:   "ctl",
+	Short: "Control a running hal run loop",
+	Long: `Send requests to a hal run loop running in another terminal.
+
+Requests are written to .hal/run-control.json and applied by the loop between
+iterations, so the engine is never interrupted mid-story:
+
+  pause               Hold the loop before its next iteration
+  resume              Continue a paused loop
+  skip [story-id]     Skip a story (default: the story in progress)
+  stop-after-current  Stop once the current iteration ends, committing any
+                      work left uncommitted
+  reprioritize <id>   Move a story (default: to the front of the queue)
+
+'hal status --json' shows the run and its pending requests under "run".
+Requests are refused when the loop's process has exited or it has not
+refreshed .hal/run-status.json for 5 minutes.`,
+	Example: `  hal ctl pause
+  hal ctl resume
+  hal ctl skip US-004
+  hal ctl stop-after-current
+  hal ctl reprioritize US-007 --before US-005`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var ctlPauseCmd = &cobra.Command{
+	Use:     ctlPause,
+	Short:   "Pause the loop before its next iteration",
+	Long:    "Pause the running loop once the current iteration ends. The loop waits until 'hal ctl resume'.",
+	Args:    noArgsValidation(),
+	Example: "  hal ctl pause",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runCtl(cmd, ".", ctlPause, "", prd.MoveTarget{})
+	},
+}
+
+var ctlResumeCmd = &cobra.Command{
+	Use:     ctlResume,
+	Short:   "Resume a paused loop",
+	Long:    "Resume a loop paused with 'hal ctl pause'.",
+	Args:    noArgsValidation(),
+	Example: "  hal ctl resume",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runCtl(cmd, ".", ctlResume, "", prd.MoveTarget{})
+	},
+}
+
+var ctlSkipCmd = &cobra.Command{
+	Use:   "skip [story-id]",
+	Short: "Skip a story",
+	Long: `Mark a story as skipped once the current iteration ends. Without a story ID
+the story in progress is skipped. Skipped stories stay in the PRD; bring them
+back with 'hal prd story reset'. A run started with --story works only that
+story, so it cannot be skipped; use 'hal ctl stop-after-current' instead.`,
+	Args:    maxArgsValidation(1),
+	Example: "  hal ctl skip\n  hal ctl skip US-004",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		storyID := ""
+		if len(args) > 0 {
+			storyID = args[0]
+		}
+		return runCtl(cmd, ".", ctlSkip, storyID, prd.MoveTarget{})
+	},
+}
+
+var ctlStopCmd = &cobra.Command{
+	Use:   ctlStopAfterCurrent,
+	Short: "Stop the loop after the current iteration",
+	Long: `Stop the loop once the current iteration ends. Changes the engine left
+uncommitted are committed so the working tree is clean when hal run exits.`,
+	Args:    noArgsValidation(),
+	Example: "  hal ctl stop-after-current",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runCtl(cmd, ".", ctlStopAfterCurrent, "", prd.MoveTarget{})
+	},
+}
+
+var ctlReprioritizeCmd = &cobra.Command{
+	Use:   "reprioritize <story-id>",
+	Short: "Move a story in the run queue",
+	Long: `Move a story before the next iteration. Without flags the story moves to
+priority 1 so it is worked next. Priorities are renumbered as with
+'hal prd story move'.`,
+	Args:    exactArgsValidation(1),
+	Example: "  hal ctl reprioritize US-007\n  hal ctl reprioritize US-007 --to 3\n  hal ctl reprioritize US-007 --after US-002",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		target := prd.MoveTarget{Position: ctlToFlag, Before: ctlBeforeFlag, After: ctlAfterFlag}
+		return runCtl(cmd, ".", ctlReprioritize, args[0], target)
+	},
+}
+
+func init() {
+	ctlReprioritizeCmd.Flags().IntVar(&ctlToFlag, "to", 0, "New 1-based position in priority order (default 1)")
+	ctlReprioritizeCmd.Flags().StringVar(&ctlBeforeFlag, "before", "", "Place the story before this story")
+	ctlReprioritizeCmd.Flags().StringVar(&ctlAfterFlag, "after", "", "Place the story after this story")
+
+	ctlCmd.AddCommand(ctlPauseCmd, ctlResumeCmd, ctlSkipCmd, ctlStopCmd, ctlReprioritizeCmd)
+	rootCmd.AddCommand(ctlCmd)
+}
+
+// runCtl validates a hal ctl request against the running loop and records it
+// in the control file.
+func runCtl(cmd *cobra.Command, dir, action, storyID string, target prd.MoveTarget) error {
+	out := io.Writer(os.Stdout)
+	if cmd != nil {
+		out = cmd.OutOrStdout()
+	}
+	halDir := filepath.Join(dir, template.HalDir)
+
+	status, err := runstatus.Load(halDir)
+	if err != nil {
+		return err
+	}
+	if reason := status.StaleReason(time.Now()); reason != "" {
+		return exitWithCode(cmd, ExitCodeValidation, fmt.Errorf("%s; no hal run is reading requests (start a new one with hal run)", reason))
+	}
+	if !status.Controllable() {
+		return exitWithCode(cmd, ExitCodeValidation, fmt.Errorf("no hal run in progress"))
+	}
+
+	var update func(*loop.RunControl)
+	var message string
+	switch action {
+	case ctlPause:
+		update = func(c *loop.RunControl) { c.Paused = true }
+		message = "Pause requested; the loop pauses before its next iteration."
+	case ctlResume:
+		update = func(c *loop.RunControl) { c.Paused = false }
+		message = "Resume requested."
+	case ctlStopAfterCurrent:
+		update = func(c *loop.RunControl) { c.StopAfterCurrent = true }
+		message = "Stop requested; the loop stops after the current iteration."
+	case ctlSkip:
+		if storyID == "" {
+			storyID = status.StoryID
+		}
+		if storyID == "" {
+			return exitWithCode(cmd, ExitCodeValidation, fmt.Errorf("no story in progress; pass a story ID"))
+		}
+		story, err := findRunStory(halDir, status, storyID)
+		if err != nil {
+			return exitWithCode(cmd, ExitCodeValidation, err)
+		}
+		if story.Passes {
+			return exitWithCode(cmd, ExitCodeValidation, fmt.Errorf("story %s already passes", story.ID))
+		}
+		if status.TargetStoryID != "" && story.ID == status.TargetStoryID {
+			return exitWithCode(cmd, ExitCodeValidation, fmt.Errorf("the run works only %s (hal run --story); use hal ctl stop-after-current instead", story.ID))
+		}
+		update = func(c *loop.RunControl) { c.Skip = append(c.Skip, story.ID) }
+		message = fmt.Sprintf("Skip of %s requested; it applies after the current iteration.", story.ID)
+	case ctlReprioritize:
+		set := 0
+		for _, given := range []bool{target.Position != 0, target.Before != "", target.After != ""} {
+			if given {
+				set++
+			}
+		}
+		if set > 1 {
+			return exitWithCode(cmd, ExitCodeValidation, fmt.Errorf("use only one of --to, --before and --after"))
+		}
+		if set == 0 {
+			target.Position = 1
+		}
+		story, err := findRunStory(halDir, status, storyID)
+		if err != nil {
+			return exitWithCode(cmd, ExitCodeValidation, err)
+		}
+		for _, anchor := range []string{target.Before, target.After} {
+			if anchor != "" {
+				if _, err := findRunStory(halDir, status, anchor); err != nil {
+					return exitWithCode(cmd, ExitCodeValidation, err)
+				}
+			}
+		}
+		move := loop.StoryMove{StoryID: story.ID, Position: target.Position, Before: target.Before, After: target.After}
+		update = func(c *loop.RunControl) { c.Moves = append(c.Moves, move) }
+		message = fmt.Sprintf("Reprioritize of %s requested; it applies after the current iteration.", story.ID)
+	default:
+		return fmt.Errorf("unknown hal ctl action %q", action)
+	}
+
+	if err := loop.UpdateRunControl(halDir, update); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "%s %s\n", engine.StyleSuccess.Render("✓"), message)
+	return nil
+}
+
+// findRunStory looks up a story in the PRD the running loop works on.
+func findRunStory(halDir string, status *runstatus.Status, storyID string) (*engine.UserStory, error) {
+	prdFile := status.PRDFile
+	if prdFile == "" {
+		prdFile = template.PRDFile
+	}
+	doc, err := engine.LoadPRDFile(halDir, prdFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", prdFile, err)
+	}
+	story := doc.FindStoryByID(storyID)
+	if story == nil {
+		return nil, fmt.Errorf("story not found: %s", storyID)
+	}
+	return story, nil
+}
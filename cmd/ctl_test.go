@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jywlabs/hal/internal/loop"
+	"github.com/jywlabs/hal/internal/prd"
+	"github.com/jywlabs/hal/internal/runstatus"
+	"github.com/jywlabs/hal/internal/template"
+)
+
+func setupCtlDir(t *testing.T, runState string) string {
+	t.Helper()
+	dir := t.TempDir()
+	halDir := filepath.Join(dir, template.HalDir)
+	os.MkdirAll(halDir, 0755)
+	files := map[string]string{
+		template.PRDFile: `{"branchName":"hal/demo","userStories":[
+  {"id":"US-001","title":"Schema","priority":1,"passes":true},
+  {"id":"US-002","title":"Endpoint","priority":2},
+  {"id":"US-003","title":"Docs","priority":3}]}`,
+	}
+	if runState != "" {
+		files[template.RunStatusFile] = `{"state":"` + runState + `","prdFile":"prd.json","iteration":2,"maxIterations":10,"storyId":"US-002"}`
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(halDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func loadCtlControl(t *testing.T, dir string) *loop.RunControl {
+	t.Helper()
+	control, err := loop.LoadRunControl(filepath.Join(dir, template.HalDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return control
+}
+
+func TestRunCtl_RequiresRunningLoop(t *testing.T) {
+	for _, state := range []string{"", runstatus.StateComplete} {
+		dir := setupCtlDir(t, state)
+		err := runCtl(nil, dir, ctlPause, "", prd.MoveTarget{})
+		var exitErr *ExitCodeError
+		if !errors.As(err, &exitErr) || exitErr.Code != ExitCodeValidation || !strings.Contains(err.Error(), "no hal run in progress") {
+			t.Fatalf("state %q: err = %v, want validation error", state, err)
+		}
+	}
+}
+
+func TestRunCtl_RejectsDeadLoop(t *testing.T) {
+	dir := setupCtlDir(t, runstatus.StateRunning)
+	statusPath := filepath.Join(dir, template.HalDir, template.RunStatusFile)
+	stale := `{"state":"running","prdFile":"prd.json","iteration":2,"maxIterations":10,"storyId":"US-002","updatedAt":"2020-01-01T00:00:00Z"}`
+	if err := os.WriteFile(statusPath, []byte(stale), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := runCtl(nil, dir, ctlPause, "", prd.MoveTarget{})
+	var exitErr *ExitCodeError
+	if !errors.As(err, &exitErr) || exitErr.Code != ExitCodeValidation || !strings.Contains(err.Error(), "no hal run is reading requests") {
+		t.Fatalf("err = %v, want stale run validation error", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, template.HalDir, template.RunControlFile)); !os.IsNotExist(err) {
+		t.Fatal("request for a dead loop was recorded")
+	}
+}
+
+func TestRunCtl_RejectsSkipOfSingleStoryRun(t *testing.T) {
+	dir := setupCtlDir(t, runstatus.StateRunning)
+	statusPath := filepath.Join(dir, template.HalDir, template.RunStatusFile)
+	pinned := `{"state":"running","prdFile":"prd.json","targetStoryId":"US-002","iteration":2,"maxIterations":10,"storyId":"US-002"}`
+	if err := os.WriteFile(statusPath, []byte(pinned), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := runCtl(nil, dir, ctlSkip, "", prd.MoveTarget{})
+	if err == nil || !strings.Contains(err.Error(), "hal run --story") {
+		t.Fatalf("err = %v, want --story rejection", err)
+	}
+	if err := runCtl(nil, dir, ctlSkip, "US-003", prd.MoveTarget{}); err != nil {
+		t.Fatalf("skip of another story: %v", err)
+	}
+}
+
+func TestRunCtl_RecordsRequests(t *testing.T) {
+	dir := setupCtlDir(t, runstatus.StateRunning)
+
+	for _, action := range []string{ctlPause, ctlStopAfterCurrent} {
+		if err := runCtl(nil, dir, action, "", prd.MoveTarget{}); err != nil {
+			t.Fatalf("%s: %v", action, err)
+		}
+	}
+	if err := runCtl(nil, dir, ctlSkip, "", prd.MoveTarget{}); err != nil {
+		t.Fatalf("skip: %v", err)
+	}
+	if err := runCtl(nil, dir, ctlReprioritize, "US-003", prd.MoveTarget{}); err != nil {
+		t.Fatalf("reprioritize: %v", err)
+	}
+
+	control := loadCtlControl(t, dir)
+	if !control.Paused || !control.StopAfterCurrent {
+		t.Fatalf("control = %+v, want paused and stop-after-current", control)
+	}
+	if len(control.Skip) != 1 || control.Skip[0] != "US-002" {
+		t.Fatalf("skip = %v, want the story in progress", control.Skip)
+	}
+	if len(control.Moves) != 1 || control.Moves[0] != (loop.StoryMove{StoryID: "US-003", Position: 1}) {
+		t.Fatalf("moves = %+v, want US-003 to position 1", control.Moves)
+	}
+
+	if err := runCtl(nil, dir, ctlResume, "", prd.MoveTarget{}); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if loadCtlControl(t, dir).Paused {
+		t.Fatal("resume should clear paused")
+	}
+}
+
+func TestRunCtl_RejectsInvalidStoryRequests(t *testing.T) {
+	dir := setupCtlDir(t, runstatus.StatePaused)
+
+	tests := []struct {
+		name    string
+		action  string
+		storyID string
+		target  prd.MoveTarget
+		want    string
+	}{
+		{name: "unknown story", action: ctlSkip, storyID: "US-009", want: "story not found"},
+		{name: "passing story", action: ctlSkip, storyID: "US-001", want: "already passes"},
+		{name: "conflicting targets", action: ctlReprioritize, storyID: "US-003", target: prd.MoveTarget{Position: 1, Before: "US-002"}, want: "only one of"},
+		{name: "unknown anchor", action: ctlReprioritize, storyID: "US-003", target: prd.MoveTarget{After: "US-009"}, want: "story not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runCtl(nil, dir, tt.action, tt.storyID, tt.target)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+	if control := loadCtlControl(t, dir); len(control.Skip)+len(control.Moves) != 0 {
+		t.Fatalf("rejected requests were recorded: %+v", control)
+	}
+}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jywlabs/hal/internal/dashboard"
+	display "github.com/jywlabs/hal/internal/engine"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// dashboardRedrawEvery forces a redraw without file changes so clocks,
+// elapsed times and new commits stay current.
+const dashboardRedrawEvery = 5 * time.Second
+
+var (
+	dashboardOnceFlag      bool
+	dashboardSandboxesFlag bool
+	dashboardIntervalFlag  time.Duration
+)
+
+var dashboardCmd = &cobra.Command{
+	Use:   "dashboard",
+	Short: "Show a live view of runs, pipelines and sandboxes",
+	Args:  noArgsValidation(),
+	Long: `Show a full-screen, live-updating view of the current project.
+
+Panes:
+  Stories    PRD stories with pass/skip state and the current story
+  Run        The live hal run loop: iteration, story, tokens, elapsed time
+  Pipeline   The hal auto step, branch, review and CI state, latest report
+  Progress   The newest entries of .hal/progress.txt
+  Commits    The latest commits on the current branch
+  Sandboxes  The global sandbox registry (with --sandboxes)
+
+The dashboard watches .hal/prd.json, .hal/auto-prd.json, .hal/auto-state.json,
+.hal/progress.txt, .hal/run-status.json and .hal/reports/ and redraws when
+any of them change. hal run writes .hal/run-status.json at every iteration,
+so run the dashboard in a second terminal next to hal run or hal auto.
+
+Press q or Ctrl-C to quit and r to redraw. When output is not a terminal,
+or with --once, a single frame is printed instead.`,
+	Example: `  hal dashboard
+  hal dashboard --sandboxes
+  hal dashboard --once`,
+	RunE: runDashboard,
+}
+
+func init() {
+	dashboardCmd.Flags().BoolVar(&dashboardOnceFlag, "once", false, "Print a single frame and exit")
+	dashboardCmd.Flags().BoolVar(&dashboardSandboxesFlag, "sandboxes", false, "Show the global sandbox registry")
+	dashboardCmd.Flags().DurationVar(&dashboardIntervalFlag, "interval", time.Second, "How often to check watched files for changes")
+	rootCmd.AddCommand(dashboardCmd)
+}
+
+func runDashboard(cmd *cobra.Command, args []string) error {
+	if dashboardIntervalFlag <= 0 {
+		return exitWithCode(cmd, ExitCodeValidation, fmt.Errorf("--interval must be positive"))
+	}
+	in := io.Reader(os.Stdin)
+	out := io.Writer(os.Stdout)
+	if cmd != nil {
+		in = cmd.InOrStdin()
+		out = cmd.OutOrStdout()
+	}
+	opts := dashboard.Options{Sandboxes: dashboardSandboxesFlag}
+	return runDashboardFn(".", opts, dashboardOnceFlag, dashboardIntervalFlag, in, out)
+}
+
+func runDashboardFn(dir string, opts dashboard.Options, once bool, interval time.Duration, in io.Reader, out io.Writer) error {
+	inFile, inOK := in.(*os.File)
+	outFile, outOK := out.(*os.File)
+	if once || !inOK || !outOK || !term.IsTerminal(int(inFile.Fd())) || !term.IsTerminal(int(outFile.Fd())) {
+		width, _ := terminalSize(out)
+		fmt.Fprintln(out, dashboard.Render(dashboard.Collect(dir, opts), width, 0))
+		return nil
+	}
+	return watchDashboard(dir, opts, interval, inFile, outFile)
+}
+
+// watchDashboard draws the dashboard on the alternate screen and redraws it
+// when watched files change, until q or Ctrl-C is pressed.
+func watchDashboard(dir string, opts dashboard.Options, interval time.Duration, in, out *os.File) error {
+	state, err := term.MakeRaw(int(in.Fd()))
+	if err != nil {
+		return fmt.Errorf("failed to enter raw mode: %w", err)
+	}
+	defer term.Restore(int(in.Fd()), state)
+
+	fmt.Fprint(out, "\x1b[?1049h\x1b[?25l")
+	defer fmt.Fprint(out, "\x1b[?25h\x1b[?1049l")
+
+	keys := make(chan byte)
+	go func() {
+		buf := make([]byte, 1)
+		for {
+			if _, err := in.Read(buf); err != nil {
+				close(keys)
+				return
+			}
+			keys <- buf[0]
+		}
+	}()
+
+	paths := dashboard.WatchedPaths(dir, opts)
+	fingerprint := dashboard.Fingerprint(paths)
+	lastDraw := time.Now()
+	draw := func() {
+		width, height := terminalSize(out)
+		frame := dashboard.Render(dashboard.Collect(dir, opts), width, height) + "\n" + display.StyleMuted.Render("q quit · r redraw")
+		// Raw mode disables newline translation; clear each line as it is
+		// overwritten instead of clearing the screen to avoid flicker.
+		frame = strings.ReplaceAll(frame, "\n", "\x1b[K\r\n")
+		fmt.Fprint(out, "\x1b[H"+frame+"\x1b[K\x1b[J")
+		lastDraw = time.Now()
+	}
+	draw()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case key, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			switch key {
+			case 'q', 'Q', 3: // 3 is Ctrl-C in raw mode
+				return nil
+			case 'r', 'R':
+				draw()
+			}
+		case <-ticker.C:
+			current := dashboard.Fingerprint(paths)
+			if current != fingerprint || time.Since(lastDraw) >= dashboardRedrawEvery {
+				fingerprint = current
+				draw()
+			}
+		}
+	}
+}
+
+// terminalSize returns the size of out when it is a terminal, else 80x0.
+func terminalSize(out io.Writer) (int, int) {
+	if f, ok := out.(*os.File); ok {
+		if width, height, err := term.GetSize(int(f.Fd())); err == nil && width > 0 {
+			return width, height
+		}
+	}
+	return 80, 0
+}
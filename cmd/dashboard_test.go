@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jywlabs/hal/internal/dashboard"
+	"github.com/jywlabs/hal/internal/template"
+)
+
+func TestRunDashboardFn_PrintsSingleFrameWithoutTerminal(t *testing.T) {
+	dir := t.TempDir()
+	halDir := filepath.Join(dir, template.HalDir)
+	os.MkdirAll(halDir, 0755)
+	prd := `{"branchName":"hal/demo","userStories":[{"id":"US-001","title":"Schema","priority":1,"passes":true},{"id":"US-002","title":"Endpoint","priority":2}]}`
+	if err := os.WriteFile(filepath.Join(halDir, template.PRDFile), []byte(prd), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := runDashboardFn(dir, dashboard.Options{}, false, time.Second, strings.NewReader(""), &out); err != nil {
+		t.Fatalf("runDashboardFn() error = %v", err)
+	}
+
+	output := out.String()
+	for _, want := range []string{"hal dashboard", "US-001", "US-002", "1/2 complete", "No run recorded"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "\x1b[?1049h") {
+		t.Error("non-terminal output should not switch to the alternate screen")
+	}
+}
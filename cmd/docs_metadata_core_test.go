@@ -72,6 +72,41 @@ func TestCoreCommandsHaveCompleteMetadata(t *testing.T) {
 			path:            []string{"status"},
 			exampleContains: "hal status",
 		},
+		{
+			name:            "ctl command",
+			path:            []string{"ctl"},
+			exampleContains: "hal ctl",
+		},
+		{
+			name:            "dashboard command",
+			path:            []string{"dashboard"},
+			exampleContains: "hal dashboard",
+		},
+		{
+			name:            "learnings consolidate command",
+			path:            []string{"learnings", "consolidate"},
+			exampleContains: "hal learnings consolidate",
+		},
+		{
+			name:            "prompt render command",
+			path:            []string{"prompt", "render"},
+			exampleContains: "hal prompt render",
+		},
+		{
+			name:            "skills status command",
+			path:            []string{"skills", "status"},
+			exampleContains: "hal skills status",
+		},
+		{
+			name:            "skills diff command",
+			path:            []string{"skills", "diff"},
+			exampleContains: "hal skills diff",
+		},
+		{
+			name:            "skills upgrade command",
+			path:            []string{"skills", "upgrade"},
+			exampleContains: "hal skills upgrade --dry-run",
+		},
 		{
 			name:            "doctor command",
 			path:            []string{"doctor"},
@@ -13,8 +13,10 @@ import (
 )
 
 var (
-	doctorJSONFlag bool
-	doctorFixFlag  bool
+	doctorJSONFlag    bool
+	doctorFixFlag     bool
+	doctorDeepFlag    bool
+	doctorRefreshFlag bool
 )
 
 var doctorCmd = &cobra.Command{
@@ -38,19 +40,32 @@ the configured engine is not codex.
 
 Use --fix to auto-apply safe remediations (equivalent to 'hal repair').
 
+With --deep, hal sends a minimal prompt through each configured engine
+(the default engine plus any under engines: in .hal/config.yaml) and
+reports CLI version, auth status, accepted model, latency and whether
+the streaming parser recognized init and result events. Probe results
+are cached in .hal/engine-probe.json for 15 minutes; hal continue reports
+fresh cached results without probing, as warnings only. Use --refresh to
+probe again.
+
 Examples:
   hal doctor            # Human-readable check results
   hal doctor --json     # Machine-readable JSON contract
-  hal doctor --fix      # Check and auto-fix safe issues`,
+  hal doctor --fix      # Check and auto-fix safe issues
+  hal doctor --deep     # Also probe each configured engine`,
 	Example: `  hal doctor
   hal doctor --json
-  hal doctor --fix`,
+  hal doctor --fix
+  hal doctor --deep
+  hal doctor --deep --refresh`,
 	RunE: runDoctor,
 }
 
 func init() {
 	doctorCmd.Flags().BoolVar(&doctorJSONFlag, "json", false, "Output machine-readable JSON (v1 contract)")
 	doctorCmd.Flags().BoolVar(&doctorFixFlag, "fix", false, "Auto-fix safe issues (equivalent to hal repair)")
+	doctorCmd.Flags().BoolVar(&doctorDeepFlag, "deep", false, "Probe each configured engine with a minimal prompt")
+	doctorCmd.Flags().BoolVar(&doctorRefreshFlag, "refresh", false, "Ignore cached probe results (with --deep)")
 	rootCmd.AddCommand(doctorCmd)
 }
 
@@ -58,6 +73,8 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	out := io.Writer(os.Stdout)
 	jsonMode := doctorJSONFlag
 	fix := doctorFixFlag
+	deep := doctorDeepFlag
+	refresh := doctorRefreshFlag
 
 	if cmd != nil {
 		out = cmd.OutOrStdout()
@@ -75,22 +92,63 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 			}
 			fix = v
 		}
+		if cmd.Flags().Lookup("deep") != nil {
+			v, err := cmd.Flags().GetBool("deep")
+			if err != nil {
+				return err
+			}
+			deep = v
+		}
+		if cmd.Flags().Lookup("refresh") != nil {
+			v, err := cmd.Flags().GetBool("refresh")
+			if err != nil {
+				return err
+			}
+			refresh = v
+		}
+	}
+
+	if refresh && !deep {
+		return exitWithCode(cmd, ExitCodeValidation, fmt.Errorf("--refresh requires --deep"))
 	}
 
 	if fix {
 		return runRepairFn(".", false, jsonMode, out)
 	}
 
-	return runDoctorFn(".", jsonMode, out)
+	return runDoctorWithOptions(".", doctorRequest{JSON: jsonMode, Deep: deep, Refresh: refresh}, out)
+}
+
+type doctorRequest struct {
+	JSON    bool
+	Deep    bool
+	Refresh bool
 }
 
 func runDoctorFn(dir string, jsonMode bool, out io.Writer) error {
+	return runDoctorWithOptions(dir, doctorRequest{JSON: jsonMode}, out)
+}
+
+func runDoctorWithOptions(dir string, req doctorRequest, out io.Writer) error {
+	jsonMode := req.JSON
 	engine, _ := compound.LoadDefaultEngine(dir)
 
-	result := doctor.Run(doctor.Options{
+	opts := doctor.Options{
 		Dir:    dir,
 		Engine: engine,
-	})
+	}
+	if req.Deep {
+		opts.Deep = true
+		opts.Refresh = req.Refresh
+		opts.Engines, _ = compound.ConfiguredEngines(dir)
+		opts.EngineConfig = func(name string) *display.EngineConfig {
+			return compound.LoadEngineConfig(dir, name)
+		}
+		if !jsonMode {
+			fmt.Fprintf(out, "%s\n", display.StyleMuted.Render("Probing engines..."))
+		}
+	}
+	result := doctor.Run(opts)
 
 	if jsonMode {
 		data, err := json.MarshalIndent(result, "", "  ")
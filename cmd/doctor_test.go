@@ -149,3 +149,11 @@ func TestDoctorFix_DelegatesToRepair(t *testing.T) {
 		t.Fatal("should have applied at least one repair")
 	}
 }
+
+func TestRunDoctor_RefreshRequiresDeep(t *testing.T) {
+	doctorDeepFlag, doctorRefreshFlag = false, true
+	t.Cleanup(func() { doctorDeepFlag, doctorRefreshFlag = false, false })
+
+	err := runDoctor(nil, nil)
+	assertExitCodeError(t, err, ExitCodeValidation, "--refresh requires --deep")
+}
@@ -7,6 +7,7 @@ import (
 	// Register available engines.
 	_ "github.com/jywlabs/hal/internal/engine/claude"
 	_ "github.com/jywlabs/hal/internal/engine/codex"
+	_ "github.com/jywlabs/hal/internal/engine/gemini"
 	_ "github.com/jywlabs/hal/internal/engine/pi"
 )
 
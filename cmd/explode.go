@@ -61,7 +61,7 @@ Examples:
 
 func init() {
 	explodeCmd.Flags().StringVar(&explodeBranchFlag, "branch", "", "Branch name to pin in generated prd.json")
-	explodeCmd.Flags().StringVarP(&explodeEngineFlag, "engine", "e", "codex", "Engine to use (claude, codex, pi)")
+	explodeCmd.Flags().StringVarP(&explodeEngineFlag, "engine", "e", "codex", "Engine to use (claude, codex, gemini, pi)")
 	explodeCmd.Flags().BoolVar(&explodeJSONFlag, "json", false, "Output machine-readable JSON result")
 	rootCmd.AddCommand(explodeCmd)
 }
@@ -7,12 +7,15 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/jywlabs/hal/internal/compound"
 	ui "github.com/jywlabs/hal/internal/engine"
+	"github.com/jywlabs/hal/internal/profile"
+	"github.com/jywlabs/hal/internal/sandbox"
 	"github.com/jywlabs/hal/internal/skills"
 	"github.com/jywlabs/hal/internal/template"
 	"github.com/spf13/cobra"
@@ -43,19 +46,34 @@ Repo-local setup (always safe):
   .hal/skills/           Hal-managed skills (prd, hal, autospec, etc.)
   .hal/commands/         Agent-invocable commands
   .hal/standards/        Project standards (committed)
+  .hal/templates/prd/    Example PRD templates for 'hal plan --template'
 
 Engine-local links (project-scoped):
   .claude/skills/        Symlinks to .hal/skills/ for Claude Code
+  .gemini/skills/        Symlinks to .hal/skills/ for Gemini CLI
+  .gemini/commands/hal/  Generated command wrappers for Gemini CLI
   .pi/skills/            Symlinks to .hal/skills/ for Pi
 
 Global links (affects ~/.codex — only for Codex users):
   ~/.codex/skills/       Symlinks for Codex skill discovery
   ~/.codex/commands/     Symlinks for Codex commands
 
+Project profile:
+  On first init, hal detects the toolchain (Go modules, npm/pnpm/yarn
+  workspaces, Cargo, Poetry/uv, Makefile targets) and pre-populates
+  auto.qualityChecks, adds toolchain hints to prompt.md and seeds starter
+  standards. Existing settings and standards are never overwritten.
+  Use --profile to pick a profile explicitly (including user profiles
+  stored as <name>.yaml in the profiles/ dir of the global hal config dir),
+  and --list-profiles to see what is available.
+
 Use 'hal doctor' to check environment health.
 Use 'hal status' to check workflow state.`,
 	Example: `  hal init
   hal init --json
+  hal init --list-profiles
+  hal init --profile pnpm
+  hal init --profile generic
   hal init --refresh-templates
   hal init --refresh-templates --dry-run`,
 	RunE: runInit,
@@ -70,6 +88,8 @@ func addInitFlags(cmd *cobra.Command) {
 	cmd.Flags().Bool("refresh-templates", false, "Backup and overwrite core templates with latest embedded versions")
 	cmd.Flags().Bool("dry-run", false, "Preview template refresh actions (only applies with --refresh-templates; other init steps still run)")
 	cmd.Flags().Bool("json", false, "Output machine-readable JSON result")
+	cmd.Flags().String("profile", "", "Project profile to apply (default: detected from the toolchain; 'generic' for none)")
+	cmd.Flags().Bool("list-profiles", false, "List built-in and user-defined project profiles, then exit")
 }
 
 // InitResult is the machine-readable output of hal init --json.
@@ -78,12 +98,26 @@ type InitResult struct {
 	OK              bool     `json:"ok"`
 	Created         []string `json:"created,omitempty"`
 	Skipped         []string `json:"skipped,omitempty"`
+	Profile         string   `json:"profile,omitempty"`
+	QualityChecks   []string `json:"qualityChecks,omitempty"`
 	Summary         string   `json:"summary"`
 }
 
+// ProfileListResult is the machine-readable output of hal init --list-profiles --json.
+type ProfileListResult struct {
+	ContractVersion int               `json:"contractVersion"`
+	Detected        string            `json:"detected"`
+	UserDir         string            `json:"userDir"`
+	Profiles        []profile.Profile `json:"profiles"`
+}
+
+// gitignoreExceptions are the .hal/ subdirectories committed as shared
+// project knowledge.
+var gitignoreExceptions = []string{"!.hal/standards/", "!.hal/commands/", "!.hal/templates/"}
+
 // ensureGitignore configures .gitignore to ignore .hal/ runtime state but allow
-// .hal/standards/ and .hal/commands/ to be committed (shared project knowledge).
-// Creates .gitignore if it doesn't exist.
+// .hal/standards/, .hal/commands/ and .hal/templates/ to be committed (shared
+// project knowledge). Creates .gitignore if it doesn't exist.
 func ensureGitignore(projectDir string, w io.Writer) error {
 	gitignorePath := filepath.Join(projectDir, ".gitignore")
 
@@ -96,8 +130,7 @@ func ensureGitignore(projectDir string, w io.Writer) error {
 	lines := strings.Split(string(content), "\n")
 
 	hasHalStar := false
-	hasStandardsException := false
-	hasCommandsException := false
+	present := map[string]bool{}
 	oldHalIdx := -1
 
 	for i, line := range lines {
@@ -105,33 +138,31 @@ func ensureGitignore(projectDir string, w io.Writer) error {
 		switch trimmed {
 		case ".hal/*":
 			hasHalStar = true
-		case "!.hal/standards/":
-			hasStandardsException = true
-		case "!.hal/commands/":
-			hasCommandsException = true
 		case ".hal", ".hal/":
 			oldHalIdx = i
+		default:
+			present[trimmed] = true
+		}
+	}
+
+	var missing []string
+	for _, exception := range gitignoreExceptions {
+		if !present[exception] {
+			missing = append(missing, exception)
 		}
 	}
 
 	// Already correct
-	if hasHalStar && hasStandardsException && hasCommandsException {
+	if hasHalStar && len(missing) == 0 {
 		return nil
 	}
 
 	// Migrate: add missing exceptions to existing .hal/* pattern
-	if hasHalStar && (!hasStandardsException || !hasCommandsException) {
-		var additions []string
-		if !hasStandardsException {
-			additions = append(additions, "!.hal/standards/")
-		}
-		if !hasCommandsException {
-			additions = append(additions, "!.hal/commands/")
-		}
+	if hasHalStar {
 		// Insert after .hal/*
 		for i, line := range lines {
 			if strings.TrimSpace(line) == ".hal/*" {
-				rest := append(additions, lines[i+1:]...)
+				rest := append(missing, lines[i+1:]...)
 				lines = append(lines[:i+1], rest...)
 				break
 			}
@@ -146,17 +177,17 @@ func ensureGitignore(projectDir string, w io.Writer) error {
 
 	// Migrate old pattern (.hal/ → .hal/* with exceptions)
 	if oldHalIdx >= 0 {
-		lines[oldHalIdx] = ".hal/*\n!.hal/standards/\n!.hal/commands/"
+		lines[oldHalIdx] = ".hal/*\n" + strings.Join(missing, "\n")
 		newContent := strings.Join(lines, "\n")
 		if err := os.WriteFile(gitignorePath, []byte(newContent), 0644); err != nil {
 			return fmt.Errorf("failed to update .gitignore: %w", err)
 		}
-		fmt.Fprintf(w, "  %s Updated .gitignore: .hal/* (standards and commands are committed)\n", ui.StyleSuccess.Render("✓"))
+		fmt.Fprintf(w, "  %s Updated .gitignore: .hal/* (standards, commands and templates are committed)\n", ui.StyleSuccess.Render("✓"))
 		return nil
 	}
 
 	// Add new entries
-	halBlock := "# hal runtime config (standards and commands are committed)\n.hal/*\n!.hal/standards/\n!.hal/commands/\n"
+	halBlock := "# hal runtime config (standards, commands and templates are committed)\n.hal/*\n" + strings.Join(missing, "\n") + "\n"
 	var newContent string
 	if len(content) == 0 {
 		newContent = halBlock
@@ -172,7 +203,7 @@ func ensureGitignore(projectDir string, w io.Writer) error {
 		return fmt.Errorf("failed to update .gitignore: %w", err)
 	}
 
-	fmt.Fprintf(w, "  %s Added .hal/* to .gitignore %s\n", ui.StyleSuccess.Render("✓"), ui.StyleMuted.Render("(standards and commands are committed)"))
+	fmt.Fprintf(w, "  %s Added .hal/* to .gitignore %s\n", ui.StyleSuccess.Render("✓"), ui.StyleMuted.Render("(standards, commands and templates are committed)"))
 	return nil
 }
 
@@ -188,11 +219,33 @@ func runInitWithWriters(cmd *cobra.Command, args []string, out, errOut io.Writer
 	projectDir := "."
 
 	// Read flags (cmd may be nil in tests)
-	var doRefresh, dryRun, jsonMode bool
+	var doRefresh, dryRun, jsonMode, listProfiles bool
+	var profileName string
 	if cmd != nil {
 		doRefresh, _ = cmd.Flags().GetBool("refresh-templates")
 		dryRun, _ = cmd.Flags().GetBool("dry-run")
 		jsonMode, _ = cmd.Flags().GetBool("json")
+		listProfiles, _ = cmd.Flags().GetBool("list-profiles")
+		profileName, _ = cmd.Flags().GetString("profile")
+	}
+
+	userProfilesDir := sandbox.GlobalDir()
+	if listProfiles {
+		if profileName != "" {
+			return exitWithCode(cmd, ExitCodeValidation, fmt.Errorf("--list-profiles cannot be combined with --profile"))
+		}
+		return printProfiles(projectDir, userProfilesDir, jsonMode, out)
+	}
+
+	// Resolve the profile before writing anything so a bad --profile fails cleanly.
+	// Without cmd (e.g. hal repair), init never tailors the project.
+	var prof *profile.Profile
+	if cmd != nil {
+		p, err := profile.Resolve(projectDir, profileName, userProfilesDir)
+		if err != nil {
+			return exitWithCode(cmd, ExitCodeValidation, err)
+		}
+		prof = &p
 	}
 
 	// Auto-migrate .goralph/ to .hal/ if applicable
@@ -237,10 +290,49 @@ func runInitWithWriters(cmd *cobra.Command, args []string, out, errOut io.Writer
 		created = append(created, filename)
 	}
 
+	// Install example PRD templates, keeping any the user has edited
+	templatesDir := filepath.Join(configDir, template.PRDTemplatesDir)
+	if err := os.MkdirAll(templatesDir, 0755); err != nil {
+		return fmt.Errorf("failed to create templates directory: %w", err)
+	}
+	prdTemplates := template.DefaultPRDTemplates()
+	templateNames := make([]string, 0, len(prdTemplates))
+	for name := range prdTemplates {
+		templateNames = append(templateNames, name)
+	}
+	sort.Strings(templateNames)
+	for _, name := range templateNames {
+		filename := filepath.ToSlash(filepath.Join(template.PRDTemplatesDir, name))
+		filePath := filepath.Join(templatesDir, name)
+		if _, err := os.Stat(filePath); err == nil {
+			skipped = append(skipped, filename)
+			continue
+		}
+		if err := os.WriteFile(filePath, []byte(prdTemplates[name]), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", filename, err)
+		}
+		created = append(created, filename)
+	}
+
 	if err := migrateConfigYAML(configDir); err != nil {
 		return fmt.Errorf("failed to migrate config.yaml: %w", err)
 	}
 
+	// Tailor a new project for its toolchain, or any project when --profile
+	// is explicit. Apply never overwrites quality checks or standards.
+	var applied profile.ApplyResult
+	if prof != nil && (profileName != "" || slices.Contains(created, template.ConfigFile)) {
+		promptFresh := slices.Contains(created, template.PromptFile) || (doRefresh && !dryRun)
+		var err error
+		applied, err = profile.Apply(configDir, *prof, promptFresh || profileName != "")
+		if err != nil {
+			return fmt.Errorf("failed to apply profile %s: %w", prof.Name, err)
+		}
+		created = append(created, applied.Standards...)
+	} else {
+		prof = nil
+	}
+
 	// Create .gitkeep in archive only if it doesn't exist
 	gitkeepPath := filepath.Join(archiveDir, ".gitkeep")
 	if _, err := os.Stat(gitkeepPath); os.IsNotExist(err) {
@@ -294,6 +386,12 @@ func runInitWithWriters(cmd *cobra.Command, args []string, out, errOut io.Writer
 			Created:         created,
 			Skipped:         skipped,
 		}
+		if prof != nil {
+			jr.Profile = prof.Name
+			if applied.QualityChecks {
+				jr.QualityChecks = prof.QualityChecks
+			}
+		}
 		if len(created) > 0 {
 			jr.Summary = fmt.Sprintf("Initialized .hal/ with %d new file(s).", len(created))
 		} else {
@@ -310,6 +408,17 @@ func runInitWithWriters(cmd *cobra.Command, args []string, out, errOut io.Writer
 	fmt.Fprintf(out, "%s Initialized .hal/\n", ui.StyleSuccess.Render("[OK]"))
 	fmt.Fprintln(out)
 
+	if prof != nil && (prof.Name != profile.Generic || profileName != "") {
+		fmt.Fprintf(out, "%s %s %s\n", ui.StyleBold.Render("Profile:"), prof.Name, ui.StyleMuted.Render("("+prof.Source+")"))
+		if applied.QualityChecks {
+			fmt.Fprintf(out, "  quality checks: %s\n", strings.Join(prof.QualityChecks, ", "))
+		}
+		if applied.PromptHints {
+			fmt.Fprintf(out, "  prompt.md: added Project Toolchain section\n")
+		}
+		fmt.Fprintln(out)
+	}
+
 	if len(created) > 0 {
 		fmt.Fprintf(out, "%s\n", ui.StyleBold.Render("Created:"))
 		for _, f := range created {
@@ -324,7 +433,7 @@ func runInitWithWriters(cmd *cobra.Command, args []string, out, errOut io.Writer
 		}
 	}
 
-	refreshChanged := refresh.hasChanges()
+	refreshChanged := refresh.hasChanges() || applied.QualityChecks || applied.PromptHints
 	if len(created) == 0 && len(skipped) > 0 && !refreshChanged && !(doRefresh && dryRun) {
 		fmt.Fprintln(out)
 		fmt.Fprintln(out, ui.StyleMuted.Render("All files already exist. No changes made."))
@@ -339,6 +448,48 @@ func runInitWithWriters(cmd *cobra.Command, args []string, out, errOut io.Writer
 	return nil
 }
 
+// printProfiles lists built-in and user-defined profiles, marking the ones
+// detected in projectDir.
+func printProfiles(projectDir, userDir string, jsonMode bool, out io.Writer) error {
+	detected := profile.Detect(projectDir)
+	profiles := profile.List(projectDir, userDir)
+
+	if jsonMode {
+		data, err := json.MarshalIndent(ProfileListResult{
+			ContractVersion: 1,
+			Detected:        detected.Name,
+			UserDir:         filepath.Join(userDir, profile.ProfilesDir),
+			Profiles:        profiles,
+		}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal profiles: %w", err)
+		}
+		fmt.Fprintln(out, string(data))
+		return nil
+	}
+
+	matched := map[string]bool{}
+	for _, name := range strings.Split(detected.Name, "+") {
+		matched[name] = true
+	}
+	fmt.Fprintf(out, "%s\n", ui.StyleBold.Render("Profiles:"))
+	for _, p := range profiles {
+		marker := " "
+		if matched[p.Name] {
+			marker = ui.StyleSuccess.Render("*")
+		}
+		source := ""
+		if p.Source != profile.SourceBuiltin {
+			source = " " + ui.StyleMuted.Render("("+p.Source+")")
+		}
+		fmt.Fprintf(out, "  %s %-10s %s%s\n", marker, p.Name, p.Description, source)
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "Detected: %s\n", detected.Name)
+	fmt.Fprintf(out, "%s\n", ui.StyleMuted.Render("User profiles: "+filepath.Join(userDir, profile.ProfilesDir, "<name>.yaml")))
+	return nil
+}
+
 func sortedDefaultFiles() (map[string]string, []string) {
 	defaults := template.DefaultFiles()
 	names := make([]string, 0, len(defaults))
@@ -415,6 +566,17 @@ const migratedBrowserTestingSection = "## Browser Testing (Required for Frontend
 	"6. Take screenshots if helpful\n\n" +
 	"A frontend story is complete when browser verification passes, or when it is explicitly skipped because no dev server was running, no browser tools were available, or 3 attempts failed."
 
+// migratedCurrentStorySection matches the Current Story section of the
+// embedded prompt.md, which renders the target story and last iteration.
+const migratedCurrentStorySection = "{{if .Story}}## Current Story\n\n" +
+	"hal selected **{{.Story.ID}}: {{.Story.Title}}** for this iteration ({{.RemainingStories}} stories remaining{{if .Iteration}}, iteration {{.Iteration}} of {{.MaxIterations}}{{end}}). Work on this story unless it already passes.\n" +
+	"{{with .Previous}}{{if .Error}}\n" +
+	"Previous iteration {{.Iteration}} ({{.StoryID}}) ended as `{{.Outcome}}`: {{.Error}}\n" +
+	"{{end}}{{end}}{{with .DiffStat}}\n" +
+	"Changes since `{{$.BaseBranch}}`:\n\n" +
+	"```\n{{.}}\n```\n" +
+	"{{end}}\n{{end}}"
+
 const canonicalBranchGuidance = "3. Check you're on the correct branch from PRD `branchName`. If not, check it out or create it from `{{BASE_BRANCH}}` (never default to `main` unless `{{BASE_BRANCH}}` is `main`)."
 const legacyTaskOrderingBlock = "6. Run quality checks (e.g., typecheck, lint, test - use whatever your project requires)\n" +
 	"7. Update AGENTS.md files if you discover reusable patterns (see below)\n" +
@@ -483,6 +645,10 @@ func migratePromptTemplate(content string) string {
 			"You are an autonomous coding agent working on a software project.\n\n{{STANDARDS}}\n\n## Your Task", 1)
 	}
 
+	if !strings.Contains(content, "## Current Story") {
+		content = strings.Replace(content, "{{STANDARDS}}\n\n## Your Task", "{{STANDARDS}}\n\n"+migratedCurrentStorySection+"## Your Task", 1)
+	}
+
 	for _, old := range legacyBranchGuidance {
 		content = strings.ReplaceAll(content, old, canonicalBranchGuidance)
 	}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
 	"io"
 	"os"
 	"path/filepath"
@@ -278,6 +279,35 @@ func TestRunInit(t *testing.T) {
 		}
 	})
 
+	t.Run("installs PRD templates without overwriting edits", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Setenv("HOME", dir)
+		if err := os.Chdir(dir); err != nil {
+			t.Fatalf("Failed to chdir: %v", err)
+		}
+
+		if err := runInit(nil, nil); err != nil {
+			t.Fatalf("runInit() error: %v", err)
+		}
+		templatesDir := filepath.Join(dir, ".hal", "templates", "prd")
+		for name := range template.DefaultPRDTemplates() {
+			if _, err := os.Stat(filepath.Join(templatesDir, name)); err != nil {
+				t.Errorf("template %s not installed: %v", name, err)
+			}
+		}
+
+		edited := filepath.Join(templatesDir, "api-endpoint.md")
+		if err := os.WriteFile(edited, []byte("custom"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := runInit(nil, nil); err != nil {
+			t.Fatalf("second runInit() error: %v", err)
+		}
+		if data, _ := os.ReadFile(edited); string(data) != "custom" {
+			t.Errorf("edited template was overwritten: %q", data)
+		}
+	})
+
 	t.Run("second run does not overwrite existing config and migrates prompt branch guidance", func(t *testing.T) {
 		dir := t.TempDir()
 		t.Setenv("HOME", dir)
@@ -665,6 +695,21 @@ func TestMigratePromptTemplate_ReordersBookkeepingBeforeCommitAndHardensStopCond
 	}
 }
 
+func TestMigratePromptTemplate_AddsCurrentStorySection(t *testing.T) {
+	legacyPrompt := "# Hal Agent Instructions\n\nYou are an autonomous coding agent working on a software project.\n\n## Your Task\n\n1. Read the PRD\n"
+
+	got := migratePromptTemplate(legacyPrompt)
+	if !strings.Contains(got, "{{STANDARDS}}\n\n"+migratedCurrentStorySection+"## Your Task") {
+		t.Fatalf("migrated prompt should contain the Current Story section, got: %s", got)
+	}
+	if again := migratePromptTemplate(got); again != got {
+		t.Fatalf("migration should be idempotent, got: %s", again)
+	}
+	if !strings.Contains(template.DefaultPrompt, migratedCurrentStorySection) {
+		t.Fatal("embedded prompt.md should contain migratedCurrentStorySection")
+	}
+}
+
 func TestEnsureGitignore(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -676,48 +721,54 @@ func TestEnsureGitignore(t *testing.T) {
 		{
 			name:            "creates new gitignore",
 			existingContent: "",
-			wantContains:    []string{".hal/*", "!.hal/standards/", "!.hal/commands/"},
+			wantContains:    []string{".hal/*", "!.hal/standards/", "!.hal/commands/", "!.hal/templates/"},
 			wantMsgSubstr:   "Added .hal/*",
 		},
 		{
 			name:            "appends to existing",
 			existingContent: "node_modules/\n",
-			wantContains:    []string{".hal/*", "!.hal/standards/", "!.hal/commands/", "node_modules/"},
+			wantContains:    []string{".hal/*", "!.hal/standards/", "!.hal/commands/", "!.hal/templates/", "node_modules/"},
 			wantMsgSubstr:   "Added .hal/*",
 		},
 		{
 			name:            "appends to existing without trailing newline",
 			existingContent: "node_modules/",
-			wantContains:    []string{".hal/*", "!.hal/standards/", "!.hal/commands/", "node_modules/"},
+			wantContains:    []string{".hal/*", "!.hal/standards/", "!.hal/commands/", "!.hal/templates/", "node_modules/"},
 			wantMsgSubstr:   "Added .hal/*",
 		},
 		{
 			name:            "migrates old .hal/ to .hal/* with exceptions",
 			existingContent: ".hal/\n",
-			wantContains:    []string{".hal/*", "!.hal/standards/", "!.hal/commands/"},
+			wantContains:    []string{".hal/*", "!.hal/standards/", "!.hal/commands/", "!.hal/templates/"},
 			wantMsgSubstr:   "Updated .gitignore",
 		},
 		{
 			name:            "migrates old .hal (no slash) to .hal/* with exceptions",
 			existingContent: ".hal\n",
-			wantContains:    []string{".hal/*", "!.hal/standards/", "!.hal/commands/"},
+			wantContains:    []string{".hal/*", "!.hal/standards/", "!.hal/commands/", "!.hal/templates/"},
 			wantMsgSubstr:   "Updated .gitignore",
 		},
 		{
 			name:            "migrates .hal/ preserving other entries",
 			existingContent: "node_modules/\n.hal/\nbuild/\n",
-			wantContains:    []string{".hal/*", "!.hal/standards/", "!.hal/commands/", "node_modules/", "build/"},
+			wantContains:    []string{".hal/*", "!.hal/standards/", "!.hal/commands/", "!.hal/templates/", "node_modules/", "build/"},
 			wantMsgSubstr:   "Updated .gitignore",
 		},
 		{
 			name:            "migrates .hal/* with only standards exception to add commands",
 			existingContent: ".hal/*\n!.hal/standards/\n",
-			wantContains:    []string{".hal/*", "!.hal/standards/", "!.hal/commands/"},
+			wantContains:    []string{".hal/*", "!.hal/standards/", "!.hal/commands/", "!.hal/templates/"},
 			wantMsgSubstr:   "Updated .gitignore",
 		},
 		{
-			name:            "skips if already correct",
+			name:            "migrates .hal/* with standards and commands exceptions to add templates",
 			existingContent: ".hal/*\n!.hal/standards/\n!.hal/commands/\n",
+			wantContains:    []string{".hal/*\n!.hal/templates/\n!.hal/standards/\n!.hal/commands/\n"},
+			wantMsgSubstr:   "Updated .gitignore",
+		},
+		{
+			name:            "skips if already correct",
+			existingContent: ".hal/*\n!.hal/standards/\n!.hal/commands/\n!.hal/templates/\n",
 			wantSkip:        true,
 		},
 	}
@@ -838,6 +889,9 @@ func TestRunInitAddsGitignore(t *testing.T) {
 		if !strings.Contains(string(content), "!.hal/commands/") {
 			t.Errorf(".gitignore should contain !.hal/commands/, got: %q", string(content))
 		}
+		if !strings.Contains(string(content), "!.hal/templates/") {
+			t.Errorf(".gitignore should contain !.hal/templates/, got: %q", string(content))
+		}
 	})
 
 	t.Run("adds .hal/* to existing gitignore", func(t *testing.T) {
@@ -1390,3 +1444,92 @@ func TestRefreshTemplates(t *testing.T) {
 		})
 	}
 }
+
+func TestInitProfileCobra(t *testing.T) {
+	origDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+	t.Cleanup(func() { os.Chdir(origDir) })
+
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("HAL_CONFIG_HOME", filepath.Join(dir, "global"))
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Failed to chdir: %v", err)
+	}
+	writeFile(t, dir, "go.mod", "module example.com/x\n")
+	if err := os.MkdirAll(filepath.Join(dir, "global", "profiles"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dir, "global", "profiles"), "team.yaml", "description: Team\nextends: go\nqualityChecks:\n  - make ci\n")
+
+	run := func(args ...string) (string, error) {
+		var stdout, stderr bytes.Buffer
+		root := newInitTestRootCmd(&stdout, &stderr)
+		root.SetArgs(append([]string{"init"}, args...))
+		err := root.Execute()
+		return stdout.String(), err
+	}
+
+	t.Run("list profiles", func(t *testing.T) {
+		output, err := run("--list-profiles", "--json")
+		if err != nil {
+			t.Fatalf("init --list-profiles error: %v", err)
+		}
+		var result ProfileListResult
+		if err := json.Unmarshal([]byte(output), &result); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, output)
+		}
+		if result.Detected != "go" || result.Profiles[len(result.Profiles)-1].Name != "team" {
+			t.Errorf("result = %+v", result)
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".hal")); !os.IsNotExist(err) {
+			t.Errorf("--list-profiles should not create .hal/, err = %v", err)
+		}
+	})
+
+	t.Run("unknown profile fails before writing", func(t *testing.T) {
+		_, err := run("--profile", "nope")
+		assertExitCodeError(t, err, ExitCodeValidation, `unknown profile "nope"`)
+		if _, err := os.Stat(filepath.Join(dir, ".hal")); !os.IsNotExist(err) {
+			t.Errorf("failed init should not create .hal/, err = %v", err)
+		}
+	})
+
+	t.Run("detected profile on first init", func(t *testing.T) {
+		output, err := run()
+		if err != nil {
+			t.Fatalf("init error: %v", err)
+		}
+		if !strings.Contains(output, "Profile:") || !strings.Contains(output, "standards/go/errors.md") {
+			t.Errorf("output should report the go profile, got: %s", output)
+		}
+		config, _ := os.ReadFile(filepath.Join(dir, ".hal", template.ConfigFile))
+		if !strings.Contains(string(config), "    - go vet ./...\n") {
+			t.Errorf("config.yaml should list go quality checks:\n%s", config)
+		}
+		prompt, _ := os.ReadFile(filepath.Join(dir, ".hal", template.PromptFile))
+		if !strings.Contains(string(prompt), "## Project Toolchain") {
+			t.Errorf("prompt.md should have a toolchain section")
+		}
+	})
+
+	t.Run("re-init keeps existing settings", func(t *testing.T) {
+		output, err := run("--profile", "team", "--json")
+		if err != nil {
+			t.Fatalf("init --profile error: %v", err)
+		}
+		var result InitResult
+		if err := json.Unmarshal([]byte(output), &result); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, output)
+		}
+		if result.Profile != "team" || len(result.QualityChecks) != 0 {
+			t.Errorf("result = %+v, want team profile without quality check changes", result)
+		}
+		config, _ := os.ReadFile(filepath.Join(dir, ".hal", template.ConfigFile))
+		if strings.Contains(string(config), "make ci") {
+			t.Errorf("existing quality checks should be preserved:\n%s", config)
+		}
+	})
+}
@@ -0,0 +1,289 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jywlabs/hal/internal/compound"
+	"github.com/jywlabs/hal/internal/engine"
+	"github.com/spf13/cobra"
+)
+
+var learningsCmd = &cobra.Command{
+	Use:   "learnings",
+	Short: "Manage learnings recorded during hal runs",
+	Long: `Manage the learnings agents record while implementing features.
+
+Each hal run iteration adds reusable patterns to the Codebase Patterns
+section of .hal/progress.txt, and hal report appends patterns to AGENTS.md.
+Use 'hal learnings consolidate' to turn them into project standards.`,
+	Example: `  hal learnings consolidate
+  hal learnings consolidate --dry-run`,
+}
+
+var (
+	learningsConsolidateEngineFlag string
+	learningsConsolidateDryRunFlag bool
+	learningsConsolidateYesFlag    bool
+	learningsConsolidateJSONFlag   bool
+)
+
+var learningsConsolidateCmd = &cobra.Command{
+	Use:   "consolidate",
+	Short: "Propose standards from accumulated learnings",
+	Args:  noArgsValidation(),
+	Long: `Gather learnings from active and archived features, de-duplicate them with
+an engine pass, and propose new or updated .hal/standards/*.md files.
+
+Learnings are read from:
+  - The Codebase Patterns section of .hal/progress.txt and archived progress logs
+  - Pattern sections hal report appended to AGENTS.md
+  - Patterns Discovered in active and archived review reports
+
+Each proposal is shown with its rationale and diff and must be accepted
+before it is written. Without a terminal, proposals are only shown unless
+--yes accepts them all. Imported standards are never changed.
+
+With --json, outputs the learnings, proposals and accepted paths; proposals
+are written only with --yes.`,
+	Example: `  hal learnings consolidate
+  hal learnings consolidate --dry-run
+  hal learnings consolidate --engine claude
+  hal learnings consolidate --json
+  hal learnings consolidate --yes`,
+	RunE: runLearningsConsolidate,
+}
+
+func init() {
+	learningsConsolidateCmd.Flags().StringVarP(&learningsConsolidateEngineFlag, "engine", "e", "codex", "Engine to use (claude, codex, gemini, pi)")
+	learningsConsolidateCmd.Flags().BoolVar(&learningsConsolidateDryRunFlag, "dry-run", false, "List gathered learnings without calling the engine")
+	learningsConsolidateCmd.Flags().BoolVarP(&learningsConsolidateYesFlag, "yes", "y", false, "Accept all proposals without review")
+	learningsConsolidateCmd.Flags().BoolVar(&learningsConsolidateJSONFlag, "json", false, "Output machine-readable JSON result")
+	learningsCmd.AddCommand(learningsConsolidateCmd)
+	rootCmd.AddCommand(learningsCmd)
+}
+
+type learningsConsolidateDeps struct {
+	newEngine   func(name string) (engine.Engine, error)
+	consolidate func(ctx context.Context, eng engine.Engine, display *engine.Display, dir string, opts compound.ConsolidateOptions) (*compound.ConsolidationResult, error)
+	apply       func(dir string, p compound.StandardProposal) error
+	isTTY       func(r io.Reader) bool
+}
+
+var defaultLearningsConsolidateDeps = learningsConsolidateDeps{
+	newEngine:   newEngine,
+	consolidate: compound.ConsolidateLearnings,
+	apply:       compound.ApplyProposal,
+	isTTY:       isTTY,
+}
+
+type learningsConsolidateRequest struct {
+	Engine string
+	DryRun bool
+	Yes    bool
+	JSON   bool
+}
+
+// LearningsConsolidateResult is the machine-readable output of
+// hal learnings consolidate.
+type LearningsConsolidateResult struct {
+	ContractVersion int                         `json:"contractVersion"`
+	OK              bool                        `json:"ok"`
+	DryRun          bool                        `json:"dryRun"`
+	Learnings       []compound.Learning         `json:"learnings"`
+	Proposals       []compound.StandardProposal `json:"proposals"`
+	Dropped         []compound.DroppedLearning  `json:"dropped,omitempty"`
+	Accepted        []string                    `json:"accepted"`
+	Summary         string                      `json:"summary"`
+}
+
+func runLearningsConsolidate(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	in := io.Reader(os.Stdin)
+	out := io.Writer(os.Stdout)
+	if cmd != nil {
+		if cmd.Context() != nil {
+			ctx = cmd.Context()
+		}
+		in = cmd.InOrStdin()
+		out = cmd.OutOrStdout()
+	}
+
+	resolvedEngine, err := resolveEngine(cmd, "engine", learningsConsolidateEngineFlag, ".")
+	if err != nil {
+		return exitWithCode(cmd, ExitCodeValidation, err)
+	}
+
+	req := learningsConsolidateRequest{
+		Engine: resolvedEngine,
+		DryRun: learningsConsolidateDryRunFlag,
+		Yes:    learningsConsolidateYesFlag,
+		JSON:   learningsConsolidateJSONFlag,
+	}
+	return runLearningsConsolidateWithDeps(ctx, ".", req, in, out, defaultLearningsConsolidateDeps)
+}
+
+func runLearningsConsolidateWithDeps(ctx context.Context, dir string, req learningsConsolidateRequest, in io.Reader, out io.Writer, deps learningsConsolidateDeps) error {
+	if deps.newEngine == nil {
+		deps.newEngine = defaultLearningsConsolidateDeps.newEngine
+	}
+	if deps.consolidate == nil {
+		deps.consolidate = defaultLearningsConsolidateDeps.consolidate
+	}
+	if deps.apply == nil {
+		deps.apply = defaultLearningsConsolidateDeps.apply
+	}
+	if deps.isTTY == nil {
+		deps.isTTY = defaultLearningsConsolidateDeps.isTTY
+	}
+
+	displayOut := out
+	if req.JSON {
+		displayOut = io.Discard
+	}
+	display := engine.NewDisplay(displayOut)
+
+	var eng engine.Engine
+	if !req.DryRun {
+		var err error
+		eng, err = deps.newEngine(req.Engine)
+		if err != nil {
+			return fmt.Errorf("failed to create engine: %w", err)
+		}
+		display.ShowCommandHeader("Learnings", "consolidate into standards", buildHeaderCtx(req.Engine))
+	}
+
+	result, err := deps.consolidate(ctx, eng, display, dir, compound.ConsolidateOptions{DryRun: req.DryRun})
+	if err != nil {
+		return err
+	}
+
+	// Review step: nothing is written unless a proposal is accepted.
+	var accepted []string
+	interactive := !req.JSON && !req.Yes && deps.isTTY(in)
+	if !req.DryRun {
+		reader := bufio.NewReader(in)
+		for i, p := range result.Proposals {
+			if !req.JSON {
+				showStandardProposal(out, i+1, len(result.Proposals), p)
+			}
+			accept := req.Yes
+			if interactive {
+				var quit bool
+				accept, quit = promptAcceptProposal(reader, out)
+				if quit {
+					break
+				}
+			}
+			if !accept {
+				continue
+			}
+			if err := deps.apply(dir, p); err != nil {
+				return err
+			}
+			accepted = append(accepted, p.Path)
+			if !req.JSON {
+				fmt.Fprintf(out, "  %s wrote .hal/standards/%s\n", engine.StyleSuccess.Render("✓"), p.Path)
+			}
+		}
+	}
+
+	summary := consolidateSummary(result, accepted, req.DryRun)
+	if req.JSON {
+		jr := LearningsConsolidateResult{
+			ContractVersion: 1,
+			OK:              true,
+			DryRun:          req.DryRun,
+			Learnings:       result.Learnings,
+			Proposals:       result.Proposals,
+			Dropped:         result.Dropped,
+			Accepted:        accepted,
+			Summary:         summary,
+		}
+		if jr.Learnings == nil {
+			jr.Learnings = []compound.Learning{}
+		}
+		if jr.Proposals == nil {
+			jr.Proposals = []compound.StandardProposal{}
+		}
+		if jr.Accepted == nil {
+			jr.Accepted = []string{}
+		}
+		data, err := json.MarshalIndent(jr, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal learnings result: %w", err)
+		}
+		fmt.Fprintln(out, string(data))
+		return nil
+	}
+
+	if req.DryRun {
+		fmt.Fprintf(out, "%s %d learning(s)\n\n", engine.StyleTitle.Render("Learnings:"), len(result.Learnings))
+		for _, l := range result.Learnings {
+			fmt.Fprintf(out, "  %s %s\n", engine.StyleMuted.Render("•"), l.Text)
+			fmt.Fprintf(out, "    %s\n", engine.StyleMuted.Render(strings.Join(l.Sources, ", ")))
+		}
+		fmt.Fprintln(out)
+	}
+	fmt.Fprintln(out, summary)
+	if !req.DryRun && !interactive && !req.Yes && len(result.Proposals) > 0 {
+		fmt.Fprintf(out, "%s\n", engine.StyleMuted.Render("No terminal to review proposals; re-run interactively or pass --yes to accept them all."))
+	}
+	return nil
+}
+
+func consolidateSummary(result *compound.ConsolidationResult, accepted []string, dryRun bool) string {
+	switch {
+	case len(result.Learnings) == 0:
+		return "No learnings found."
+	case dryRun:
+		return fmt.Sprintf("%d learning(s) would be consolidated.", len(result.Learnings))
+	case len(result.Proposals) == 0:
+		return fmt.Sprintf("%d learning(s) consolidated; no standards changes proposed.", len(result.Learnings))
+	default:
+		return fmt.Sprintf("%d learning(s) consolidated; %d of %d proposal(s) accepted.", len(result.Learnings), len(accepted), len(result.Proposals))
+	}
+}
+
+func showStandardProposal(out io.Writer, n, total int, p compound.StandardProposal) {
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "%s %s %s\n", engine.StyleMuted.Render(fmt.Sprintf("[%d/%d]", n, total)), engine.StyleBold.Render(p.Action), p.Path)
+	if p.Rationale != "" {
+		fmt.Fprintf(out, "  %s\n", p.Rationale)
+	}
+	if len(p.Learnings) > 0 {
+		fmt.Fprintf(out, "  %s\n", engine.StyleMuted.Render(fmt.Sprintf("consolidates %d learning(s)", len(p.Learnings))))
+	}
+	body := p.Diff
+	if body == "" {
+		body = p.Content
+	}
+	fmt.Fprintln(out)
+	for _, line := range strings.Split(strings.TrimSuffix(body, "\n"), "\n") {
+		fmt.Fprintf(out, "    %s\n", line)
+	}
+	fmt.Fprintln(out)
+}
+
+// promptAcceptProposal asks whether to write a proposal. Returns quit when
+// the user stops reviewing or input ends.
+func promptAcceptProposal(reader *bufio.Reader, out io.Writer) (accept, quit bool) {
+	fmt.Fprint(out, "Accept? [y/N/q] ")
+	line, err := reader.ReadString('\n')
+	answer := strings.TrimSpace(strings.ToLower(line))
+	if err != nil && answer == "" {
+		return false, true
+	}
+	switch answer {
+	case "y", "yes":
+		return true, false
+	case "q", "quit":
+		return false, true
+	}
+	return false, false
+}
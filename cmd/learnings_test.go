@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jywlabs/hal/internal/compound"
+	"github.com/jywlabs/hal/internal/engine"
+)
+
+func testLearningsDeps(applied *[]string, tty bool) learningsConsolidateDeps {
+	return learningsConsolidateDeps{
+		newEngine: func(name string) (engine.Engine, error) { return nil, nil },
+		consolidate: func(ctx context.Context, eng engine.Engine, display *engine.Display, dir string, opts compound.ConsolidateOptions) (*compound.ConsolidationResult, error) {
+			result := &compound.ConsolidationResult{
+				Learnings: []compound.Learning{{Text: "Use table-driven tests", Sources: []string{".hal/progress.txt"}}},
+			}
+			if !opts.DryRun {
+				result.Proposals = []compound.StandardProposal{
+					{Path: "testing/tables.md", Action: compound.ProposalCreate, Rationale: "Repeated", Content: "# Tables\n"},
+					{Path: "go/errors.md", Action: compound.ProposalUpdate, Diff: "--- a/go/errors.md\n+++ b/go/errors.md\n+new\n", Content: "new\n"},
+				}
+			}
+			return result, nil
+		},
+		apply: func(dir string, p compound.StandardProposal) error {
+			*applied = append(*applied, p.Path)
+			return nil
+		},
+		isTTY: func(io.Reader) bool { return tty },
+	}
+}
+
+func TestRunLearningsConsolidate_Review(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         learningsConsolidateRequest
+		tty         bool
+		input       string
+		wantApplied []string
+		wantOutput  string
+	}{
+		{name: "accept first reject second", tty: true, input: "y\nn\n", wantApplied: []string{"testing/tables.md"}, wantOutput: "1 of 2 proposal(s) accepted"},
+		{name: "quit stops review", tty: true, input: "q\n", wantOutput: "0 of 2 proposal(s) accepted"},
+		{name: "no terminal writes nothing", input: "y\ny\n", wantOutput: "pass --yes"},
+		{name: "yes accepts all", req: learningsConsolidateRequest{Yes: true}, wantApplied: []string{"testing/tables.md", "go/errors.md"}, wantOutput: "2 of 2"},
+		{name: "dry run lists learnings", req: learningsConsolidateRequest{DryRun: true}, tty: true, wantOutput: "1 learning(s) would be consolidated"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var applied []string
+			var out bytes.Buffer
+			err := runLearningsConsolidateWithDeps(context.Background(), t.TempDir(), tt.req, strings.NewReader(tt.input), &out, testLearningsDeps(&applied, tt.tty))
+			if err != nil {
+				t.Fatalf("runLearningsConsolidateWithDeps() error: %v", err)
+			}
+			if strings.Join(applied, ",") != strings.Join(tt.wantApplied, ",") {
+				t.Errorf("applied = %v, want %v", applied, tt.wantApplied)
+			}
+			if !strings.Contains(out.String(), tt.wantOutput) {
+				t.Errorf("output missing %q:\n%s", tt.wantOutput, out.String())
+			}
+		})
+	}
+}
+
+func TestRunLearningsConsolidate_JSON(t *testing.T) {
+	var applied []string
+	var out bytes.Buffer
+	req := learningsConsolidateRequest{JSON: true}
+	if err := runLearningsConsolidateWithDeps(context.Background(), t.TempDir(), req, strings.NewReader("y\n"), &out, testLearningsDeps(&applied, true)); err != nil {
+		t.Fatalf("runLearningsConsolidateWithDeps() error: %v", err)
+	}
+	var result LearningsConsolidateResult
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	if result.ContractVersion != 1 || !result.OK || len(result.Proposals) != 2 || len(result.Accepted) != 0 {
+		t.Fatalf("result = %+v", result)
+	}
+	if len(applied) != 0 {
+		t.Fatalf("applied = %v, want nothing without --yes", applied)
+	}
+}
@@ -56,6 +56,7 @@ each AI engine can discover project skills. These links are:
 
   Project-local:
     .claude/skills/  → .hal/skills/   (Claude Code)
+    .gemini/skills/  → .hal/skills/   (Gemini CLI)
     .pi/skills/      → .hal/skills/   (Pi)
 
   Global (single-active-repo):
@@ -124,7 +125,7 @@ Use 'hal cleanup' for broader .hal/ file cleanup.`,
 
 func init() {
 	linksStatusCmd.Flags().BoolVar(&linksJSONFlag, "json", false, "Output machine-readable JSON")
-	linksStatusCmd.Flags().StringVarP(&linksEngineFlag, "engine", "e", "", "Filter to specific engine (claude, pi, codex)")
+	linksStatusCmd.Flags().StringVarP(&linksEngineFlag, "engine", "e", "", "Filter to specific engine (claude, gemini, pi, codex)")
 	linksCmd.AddCommand(linksStatusCmd)
 	linksCmd.AddCommand(linksRefreshCmd)
 	linksCmd.AddCommand(linksCleanCmd)
@@ -154,7 +155,7 @@ func runLinksStatusFn(dir string, jsonMode bool, engineFilter string, out io.Wri
 	var engineStatuses []LinkStatus
 
 	// Check each registered engine linker
-	for _, name := range []string{"claude", "pi", "codex"} {
+	for _, name := range []string{"claude", "gemini", "pi", "codex"} {
 		if engineFilter != "" && name != engineFilter {
 			continue
 		}
@@ -324,6 +325,7 @@ func runLinksCleanFn(dir string, out io.Writer) error {
 	// Remove broken symlinks from engine skill dirs
 	engineDirs := []string{
 		filepath.Join(dir, ".claude", "skills"),
+		filepath.Join(dir, ".gemini", "skills"),
 		filepath.Join(dir, ".pi", "skills"),
 	}
 	for _, skillsDir := range engineDirs {
@@ -374,7 +376,7 @@ func runLinksRefresh(cmd *cobra.Command, args []string) error {
 		engineName := strings.ToLower(strings.TrimSpace(args[0]))
 		linker := skills.GetLinker(engineName)
 		if linker == nil {
-			return fmt.Errorf("unknown engine %q (available: claude, pi, codex)", engineName)
+			return fmt.Errorf("unknown engine %q (available: claude, gemini, pi, codex)", engineName)
 		}
 
 		if err := linker.Link(projectDir, skills.ManagedSkillNames); err != nil {
@@ -39,12 +39,12 @@ func TestRunLinksStatusFn_JSONOutput(t *testing.T) {
 		t.Fatal("engines should not be empty")
 	}
 
-	// Check that all 3 engines are listed
+	// Check that all 4 engines are listed
 	engineNames := map[string]bool{}
 	for _, es := range result.Engines {
 		engineNames[es.Engine] = true
 	}
-	for _, name := range []string{"claude", "pi", "codex"} {
+	for _, name := range []string{"claude", "gemini", "pi", "codex"} {
 		if !engineNames[name] {
 			t.Errorf("missing engine %q", name)
 		}
@@ -3,20 +3,53 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"strings"
+	"unicode/utf8"
 
+	ci "github.com/jywlabs/hal/internal/ci"
 	"github.com/jywlabs/hal/internal/engine"
 	"github.com/jywlabs/hal/internal/prd"
 	"github.com/spf13/cobra"
 )
 
 var (
-	planEngineFlag string
-	planFormatFlag string
+	planEngineFlag        string
+	planFormatFlag        string
+	planAnswersFlag       string
+	planAutoAnswerFlag    string
+	planQuestionsOutFlag  string
+	planFromIssueFlag     string
+	planFromMilestoneFlag string
+	planTemplateFlag      string
+	planListTemplatesFlag bool
 )
 
+// Injectable GitHub lookups for --from-issue and --from-milestone.
+var (
+	planResolveRepo    = ci.ResolveGitHubRepository
+	planFetchIssue     = ci.FetchIssue
+	planFetchMilestone = ci.FetchMilestone
+)
+
+// planIssueCommentLimit caps each issue comment included in the description.
+const planIssueCommentLimit = 2000
+
+// truncateIssueComment cuts body to planIssueCommentLimit bytes, backing off
+// to a rune boundary so multi-byte characters are not split.
+func truncateIssueComment(body string) string {
+	if len(body) <= planIssueCommentLimit {
+		return body
+	}
+	cut := planIssueCommentLimit
+	for cut > 0 && !utf8.RuneStart(body[cut]) {
+		cut--
+	}
+	return body[:cut] + "…"
+}
+
 var planCmd = &cobra.Command{
 	Use:   "plan [feature-description]",
 	Short: "Generate a PRD interactively",
@@ -31,6 +64,39 @@ If no description is provided, your $EDITOR will open for you to write the spec.
 By default, the PRD is written as markdown to .hal/prd-[feature-name].md.
 Use --format json to output directly to .hal/prd.json for immediate use with 'hal run'.
 
+Non-interactive planning (CI, sandboxes, bots):
+  --answers FILE          Answer questions from a YAML/JSON file. Keys are
+                          question numbers or text matching one question;
+                          values are an option letter, an option label or
+                          free text.
+  --auto-answer MODE      Fill unanswered questions with the "recommended"
+                          option or let the "engine" choose.
+  --questions-out FILE    Write the generated questions as JSON and exit.
+                          Add an "answers" map to that file and pass it to
+                          --answers to resume with the same questions.
+
+The chosen answers are recorded in the generated PRD (a "Planning Answers"
+section in markdown, planningAnswers in prd.json).
+
+Planning from GitHub:
+  --from-issue REF        Seed the PRD with an issue's title, body, labels and
+                          comments. REF is a number, owner/repo#N or a URL.
+  --from-milestone NAME   Seed the PRD with every open issue in a milestone
+                          (title or number).
+
+Any description arguments are added as extra guidance. The issue links are
+stored in the PRD ("Source Issues" in markdown, issues in prd.json), so
+'hal ci push' can add "Closes #N" to the pull request and post progress
+comments on the issues.
+
+Templates:
+  --template NAME         Plan from .hal/templates/prd/NAME.md. Its front
+                          matter lists questions that are always asked,
+                          stories the PRD must contain and acceptance
+                          criteria every story must carry; the body guides
+                          the PRD. 'hal validate' checks these constraints.
+  --list-templates        List the available templates and exit.
+
 Examples:
   hal plan                            # Opens editor for full spec
   hal plan "user authentication"      # Interactive PRD generation
@@ -39,21 +105,88 @@ Examples:
 	Example: `  hal plan
   hal plan "user authentication"
   hal plan "add dark mode" --format json
-  hal plan "notifications" --engine codex`,
+  hal plan "notifications" --engine codex
+  hal plan "notifications" --auto-answer recommended
+  hal plan "notifications" --questions-out .hal/questions.json
+  hal plan --answers .hal/questions.json
+  hal plan --from-issue 42
+  hal plan --from-issue https://github.com/acme/shop/issues/42 --auto-answer engine
+  hal plan --from-milestone "v1.2" "focus on the API changes"
+  hal plan --list-templates
+  hal plan "orders endpoint" --template api-endpoint`,
 	Args: cobra.ArbitraryArgs,
 	RunE: runPlan,
 }
 
 func init() {
-	planCmd.Flags().StringVarP(&planEngineFlag, "engine", "e", "codex", "Engine to use (claude, codex, pi)")
+	planCmd.Flags().StringVarP(&planEngineFlag, "engine", "e", "codex", "Engine to use (claude, codex, gemini, pi)")
 	planCmd.Flags().StringVarP(&planFormatFlag, "format", "f", "markdown", "Output format: markdown, json")
+	planCmd.Flags().StringVar(&planAnswersFlag, "answers", "", "Answer clarifying questions from a YAML/JSON file")
+	planCmd.Flags().StringVar(&planAutoAnswerFlag, "auto-answer", "", "Answer remaining questions automatically: recommended, engine")
+	planCmd.Flags().StringVar(&planQuestionsOutFlag, "questions-out", "", "Write clarifying questions as JSON to this file and exit")
+	planCmd.Flags().StringVar(&planFromIssueFlag, "from-issue", "", "Plan from a GitHub issue (number, owner/repo#N or URL)")
+	planCmd.Flags().StringVar(&planFromMilestoneFlag, "from-milestone", "", "Plan from the open issues of a GitHub milestone (title or number)")
+	planCmd.Flags().StringVarP(&planTemplateFlag, "template", "t", "", "Plan from a template in .hal/templates/prd/")
+	planCmd.Flags().BoolVar(&planListTemplatesFlag, "list-templates", false, "List PRD templates and exit")
 	rootCmd.AddCommand(planCmd)
 }
 
 func runPlan(cmd *cobra.Command, args []string) error {
+	if planListTemplatesFlag {
+		return listPlanTemplates(cmd.OutOrStdout(), ".")
+	}
+
+	opts := prd.GenerateOptions{
+		Format:       planFormatFlag,
+		AutoAnswer:   strings.ToLower(strings.TrimSpace(planAutoAnswerFlag)),
+		QuestionsOut: planQuestionsOutFlag,
+	}
+	switch opts.AutoAnswer {
+	case "", prd.AutoAnswerRecommended, prd.AutoAnswerEngine:
+	default:
+		return exitWithCode(cmd, ExitCodeValidation, fmt.Errorf("invalid --auto-answer %q (valid: %s, %s)", planAutoAnswerFlag, prd.AutoAnswerRecommended, prd.AutoAnswerEngine))
+	}
+	if opts.QuestionsOut != "" && (planAnswersFlag != "" || opts.AutoAnswer != "") {
+		return exitWithCode(cmd, ExitCodeValidation, fmt.Errorf("--questions-out cannot be combined with --answers or --auto-answer"))
+	}
+	if planAnswersFlag != "" {
+		answers, err := prd.LoadAnswersFile(planAnswersFlag)
+		if err != nil {
+			return exitWithCode(cmd, ExitCodeValidation, err)
+		}
+		opts.Answers = answers
+	}
+
+	if planFromIssueFlag != "" && planFromMilestoneFlag != "" {
+		return exitWithCode(cmd, ExitCodeValidation, fmt.Errorf("--from-issue cannot be combined with --from-milestone"))
+	}
+	if planTemplateFlag != "" {
+		tmpl, err := prd.LoadTemplate(".", planTemplateFlag)
+		if err != nil {
+			return exitWithCode(cmd, ExitCodeValidation, err)
+		}
+		opts.Template = tmpl
+	}
+
 	var description string
+	headerContext := ""
 
-	if len(args) == 0 {
+	if planFromIssueFlag != "" || planFromMilestoneFlag != "" {
+		source, err := resolvePlanIssues(context.Background(), planFromIssueFlag, planFromMilestoneFlag)
+		if err != nil {
+			return err
+		}
+		description = source.description(strings.Join(args, " "))
+		headerContext = source.Name
+		opts.Issues = source.Refs
+		opts.FeatureName = source.Name
+	} else if len(args) > 0 {
+		description = strings.Join(args, " ")
+	} else if opts.Answers != nil && strings.TrimSpace(opts.Answers.Description) != "" {
+		description = strings.TrimSpace(opts.Answers.Description)
+	} else if opts.Answers != nil || opts.AutoAnswer != "" || opts.QuestionsOut != "" {
+		return exitWithCode(cmd, ExitCodeValidation, fmt.Errorf("a feature description is required for non-interactive planning"))
+	} else {
 		// No args - open editor
 		content, err := openEditorForInput()
 		if err != nil {
@@ -63,8 +196,6 @@ func runPlan(cmd *cobra.Command, args []string) error {
 		if description == "" {
 			return fmt.Errorf("no description provided")
 		}
-	} else {
-		description = strings.Join(args, " ")
 	}
 
 	engineName, err := resolveEngine(cmd, "engine", planEngineFlag, ".")
@@ -82,15 +213,27 @@ func runPlan(cmd *cobra.Command, args []string) error {
 	display := engine.NewDisplay(os.Stdout)
 
 	// Show command header
-	display.ShowCommandHeader("Plan", description, buildHeaderCtx(engineName))
+	if headerContext == "" {
+		headerContext = description
+	}
+	display.ShowCommandHeader("Plan", headerContext, buildHeaderCtx(engineName))
 
 	// Generate PRD
 	ctx := context.Background()
-	outputPath, err := prd.GenerateWithEngine(ctx, eng, description, planFormatFlag, display)
+	outputPath, err := prd.GenerateWithOptions(ctx, eng, description, opts, display)
 	if err != nil {
 		return fmt.Errorf("PRD generation failed: %w", err)
 	}
 
+	if opts.QuestionsOut != "" {
+		display.ShowCommandSuccess("Questions written", fmt.Sprintf("Path: %s", outputPath))
+		display.ShowNextSteps([]string{
+			fmt.Sprintf("Add an \"answers\" map to %s", outputPath),
+			fmt.Sprintf("hal plan --answers %s", outputPath),
+		})
+		return nil
+	}
+
 	// Show success
 	display.ShowCommandSuccess("PRD created", fmt.Sprintf("Path: %s", outputPath))
 
@@ -107,6 +250,120 @@ func runPlan(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// listPlanTemplates prints the PRD templates in .hal/templates/prd/.
+func listPlanTemplates(out io.Writer, dir string) error {
+	templates, err := prd.ListTemplates(dir)
+	if err != nil {
+		return err
+	}
+	if len(templates) == 0 {
+		fmt.Fprintf(out, "No PRD templates in %s\n", prd.TemplatesDir(dir))
+		fmt.Fprintf(out, "%s\n", engine.StyleMuted.Render("Run 'hal init' to install the examples, or add <name>.md files there."))
+		return nil
+	}
+	fmt.Fprintln(out, engine.StyleBold.Render("PRD templates:"))
+	for _, t := range templates {
+		fmt.Fprintf(out, "  %s", engine.StyleInfo.Render(t.Name))
+		if t.Description != "" {
+			fmt.Fprintf(out, "  %s", t.Description)
+		}
+		fmt.Fprintln(out)
+		var details []string
+		if n := len(t.Questions); n > 0 {
+			details = append(details, fmt.Sprintf("%d question(s)", n))
+		}
+		if n := len(t.RequiredStories); n > 0 {
+			details = append(details, fmt.Sprintf("%d required story(ies)", n))
+		}
+		if n := len(t.AcceptanceCriteria); n > 0 {
+			details = append(details, fmt.Sprintf("%d required criterion(a)", n))
+		}
+		if len(details) > 0 {
+			fmt.Fprintf(out, "    %s\n", engine.StyleMuted.Render(strings.Join(details, ", ")))
+		}
+	}
+	fmt.Fprintf(out, "\n%s\n", engine.StyleMuted.Render("Use: hal plan \"description\" --template <name>"))
+	return nil
+}
+
+// planIssueSource is the GitHub context a PRD is planned from.
+type planIssueSource struct {
+	Name   string
+	Issues []ci.Issue
+	Refs   []engine.IssueRef
+}
+
+// resolvePlanIssues loads the issue or milestone named by the plan flags.
+func resolvePlanIssues(ctx context.Context, fromIssue, fromMilestone string) (*planIssueSource, error) {
+	source := &planIssueSource{}
+	if fromIssue != "" {
+		repo, number, err := ci.ParseIssueRef(fromIssue)
+		if err != nil {
+			return nil, err
+		}
+		if repo.FullName() == "" {
+			if repo, err = planResolveRepo(ctx); err != nil {
+				return nil, err
+			}
+		}
+		issue, err := planFetchIssue(ctx, repo, number)
+		if err != nil {
+			return nil, err
+		}
+		source.Name = issue.Title
+		source.Issues = []ci.Issue{issue}
+	} else {
+		repo, err := planResolveRepo(ctx)
+		if err != nil {
+			return nil, err
+		}
+		milestone, err := planFetchMilestone(ctx, repo, fromMilestone)
+		if err != nil {
+			return nil, err
+		}
+		source.Name = milestone.Title
+		source.Issues = milestone.Issues
+	}
+	for _, issue := range source.Issues {
+		source.Refs = append(source.Refs, engine.IssueRef{
+			Repo:   issue.Repo.FullName(),
+			Number: issue.Number,
+			Title:  issue.Title,
+			URL:    issue.URL,
+		})
+	}
+	return source, nil
+}
+
+// description renders the issues as the feature description for planning.
+func (s *planIssueSource) description(guidance string) string {
+	var b strings.Builder
+	if len(s.Issues) > 1 {
+		fmt.Fprintf(&b, "Plan one PRD covering the %d open issues of GitHub milestone %q.\n", len(s.Issues), s.Name)
+	}
+	for _, issue := range s.Issues {
+		fmt.Fprintf(&b, "\n## GitHub issue %s#%d: %s\n", issue.Repo.FullName(), issue.Number, issue.Title)
+		if len(issue.Labels) > 0 {
+			fmt.Fprintf(&b, "Labels: %s\n", strings.Join(issue.Labels, ", "))
+		}
+		if issue.Body != "" {
+			fmt.Fprintf(&b, "\n%s\n", issue.Body)
+		}
+		if len(issue.Comments) > 0 {
+			b.WriteString("\nComments:\n")
+			for _, c := range issue.Comments {
+				body := c.Body
+				body = truncateIssueComment(body)
+				fmt.Fprintf(&b, "- @%s: %s\n", c.Author, strings.ReplaceAll(body, "\n", "\n  "))
+			}
+		}
+	}
+	if guidance = strings.TrimSpace(guidance); guidance != "" {
+		fmt.Fprintf(&b, "\n## Additional guidance\n\n%s\n", guidance)
+	}
+	return strings.TrimSpace(b.String())
+}
+
 func openEditorForInput() (string, error) {
 	// Create temp file with template
 	tmpfile, err := os.CreateTemp("", "hal-plan-*.md")
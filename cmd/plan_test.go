@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	ci "github.com/jywlabs/hal/internal/ci"
+	"github.com/jywlabs/hal/internal/prd"
+)
+
+func TestResolvePlanIssues_FromIssueUsesCurrentRepo(t *testing.T) {
+	origResolve, origFetch := planResolveRepo, planFetchIssue
+	t.Cleanup(func() { planResolveRepo, planFetchIssue = origResolve, origFetch })
+
+	repo := ci.GitHubRepository{Owner: "acme", Name: "repo"}
+	planResolveRepo = func(context.Context) (ci.GitHubRepository, error) { return repo, nil }
+	planFetchIssue = func(_ context.Context, got ci.GitHubRepository, number int) (ci.Issue, error) {
+		if got != repo || number != 42 {
+			t.Fatalf("fetch %s#%d, want acme/repo#42", got.FullName(), number)
+		}
+		return ci.Issue{
+			Repo:     repo,
+			Number:   42,
+			Title:    "Add CSV export",
+			Body:     "Users want CSV.",
+			URL:      "https://github.com/acme/repo/issues/42",
+			Labels:   []string{"feature"},
+			Comments: []ci.IssueComment{{Author: "octo", Body: "Also JSON"}},
+		}, nil
+	}
+
+	source, err := resolvePlanIssues(context.Background(), "#42", "")
+	if err != nil {
+		t.Fatalf("resolvePlanIssues() error = %v", err)
+	}
+	if source.Name != "Add CSV export" || len(source.Refs) != 1 || source.Refs[0].Repo != "acme/repo" || source.Refs[0].URL == "" {
+		t.Fatalf("source = %+v", source)
+	}
+
+	desc := source.description("Keep it small")
+	for _, want := range []string{
+		"## GitHub issue acme/repo#42: Add CSV export",
+		"Labels: feature",
+		"Users want CSV.",
+		"- @octo: Also JSON",
+		"## Additional guidance\n\nKeep it small",
+	} {
+		if !strings.Contains(desc, want) {
+			t.Errorf("description missing %q:\n%s", want, desc)
+		}
+	}
+}
+
+func TestResolvePlanIssues_FromMilestone(t *testing.T) {
+	origResolve, origFetch := planResolveRepo, planFetchMilestone
+	t.Cleanup(func() { planResolveRepo, planFetchMilestone = origResolve, origFetch })
+
+	repo := ci.GitHubRepository{Owner: "acme", Name: "repo"}
+	planResolveRepo = func(context.Context) (ci.GitHubRepository, error) { return repo, nil }
+	planFetchMilestone = func(_ context.Context, _ ci.GitHubRepository, milestone string) (ci.Milestone, error) {
+		return ci.Milestone{Number: 3, Title: milestone, Issues: []ci.Issue{
+			{Repo: repo, Number: 5, Title: "Invoices"},
+			{Repo: repo, Number: 6, Title: "Refunds"},
+		}}, nil
+	}
+
+	source, err := resolvePlanIssues(context.Background(), "", "Billing")
+	if err != nil {
+		t.Fatalf("resolvePlanIssues() error = %v", err)
+	}
+	if len(source.Refs) != 2 || source.Refs[1].Number != 6 {
+		t.Fatalf("refs = %+v", source.Refs)
+	}
+	if desc := source.description(""); !strings.HasPrefix(desc, `Plan one PRD covering the 2 open issues of GitHub milestone "Billing".`) {
+		t.Fatalf("description = %q", desc)
+	}
+}
+
+func TestListPlanTemplates(t *testing.T) {
+	dir := t.TempDir()
+	var out bytes.Buffer
+	if err := listPlanTemplates(&out, dir); err != nil {
+		t.Fatalf("listPlanTemplates() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "No PRD templates") {
+		t.Errorf("empty output = %q", out.String())
+	}
+
+	templatesDir := prd.TemplatesDir(dir)
+	if err := os.MkdirAll(templatesDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := "---\nname: db-migration\ndescription: Schema change\nrequiredStories:\n  - Add schema migration\n---\n\nBody\n"
+	if err := os.WriteFile(filepath.Join(templatesDir, "db-migration.md"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if err := listPlanTemplates(&out, dir); err != nil {
+		t.Fatalf("listPlanTemplates() error = %v", err)
+	}
+	for _, want := range []string{"db-migration", "Schema change", "1 required story(ies)"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestTruncateIssueComment_KeepsRunesWhole(t *testing.T) {
+	if got := truncateIssueComment("short"); got != "short" {
+		t.Fatalf("truncateIssueComment(short) = %q", got)
+	}
+	body := strings.Repeat("a", planIssueCommentLimit-1) + "é" + "tail"
+	got := truncateIssueComment(body)
+	if !utf8.ValidString(got) {
+		t.Fatalf("truncateIssueComment() = %q, want valid UTF-8", got[len(got)-8:])
+	}
+	if want := strings.Repeat("a", planIssueCommentLimit-1) + "…"; got != want {
+		t.Fatalf("truncateIssueComment() kept %d bytes, want the split rune dropped", len(got))
+	}
+}
@@ -42,9 +42,12 @@ var prdCmd = &cobra.Command{
 	Short: "Manage PRD files",
 	Long: `Inspect and manage Product Requirements Document files.
 
-Use 'hal prd audit' to check PRD health and detect drift.`,
+Use 'hal prd audit' to check PRD health and detect drift.
+Use 'hal prd story' to add, edit, reorder, reset or skip stories.`,
 	Example: `  hal prd audit
-  hal prd audit --json`,
+  hal prd audit --json
+  hal prd story show US-003
+  hal prd story reset US-003`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return cmd.Help()
 	},
@@ -141,7 +144,7 @@ func runPRDAuditFn(dir string, jsonMode bool, out io.Writer) error {
 
 	// Drift and migration detection
 	if jsonExists && markdownExists {
-		issues = append(issues, "both prd.json and markdown PRD exist — potential drift. Consider archiving one.")
+		issues = append(issues, "both prd.json and markdown PRD exist — potential drift. Run 'hal convert --merge' to resync prd.json with the markdown while keeping progress, or archive one.")
 	}
 	if !jsonExists && !markdownExists && len(legacyAutoPRDFiles) == 0 {
 		issues = append(issues, "no PRD files found. Run hal plan or create a PRD manually.")
@@ -0,0 +1,441 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jywlabs/hal/internal/engine"
+	"github.com/jywlabs/hal/internal/prd"
+	"github.com/jywlabs/hal/internal/template"
+	"github.com/spf13/cobra"
+)
+
+var (
+	prdStoryJSONFlag        bool
+	prdStoryIDFlag          string
+	prdStoryTitleFlag       string
+	prdStoryDescFlag        string
+	prdStoryNotesFlag       string
+	prdStoryCriteriaFlag    []string
+	prdStoryAddCriteriaFlag []string
+	prdStoryVerifyFlag      []string
+	prdStoryToFlag          int
+	prdStoryBeforeFlag      string
+	prdStoryAfterFlag       string
+	prdStoryReasonFlag      string
+)
+
+// prdStoryNow timestamps audit entries. Injectable for testing.
+var prdStoryNow = time.Now
+
+// PRDStoryResult is the machine-readable output of hal prd story --json.
+type PRDStoryResult struct {
+	ContractVersion int                   `json:"contractVersion"`
+	OK              bool                  `json:"ok"`
+	Action          string                `json:"action"`
+	Story           *engine.UserStory     `json:"story,omitempty"`
+	Changed         []string              `json:"changed,omitempty"`
+	Order           []string              `json:"order,omitempty"`
+	Validation      *prd.ValidationResult `json:"validation,omitempty"`
+	Summary         string                `json:"summary"`
+}
+
+var prdStoryCmd = &cobra.Command{
+	Use:   "story",
+	Short: "Add, edit, reorder and reset PRD stories",
+	Long: `Edit stories in .hal/prd.json without hand-editing JSON.
+
+Every change runs structural validation (unique IDs, titles, criteria) before
+the file is replaced atomically, and is recorded as an entry in
+.hal/progress.txt. Invalid results are rejected and nothing is written.
+
+Skipped stories stay in the PRD but are ignored by hal run and progress counts.
+Use 'hal prd story reset' to bring them back.`,
+	Example: `  hal prd story show US-003
+  hal prd story add --title "Add CSV export" --criteria "Typecheck passes"
+  hal prd story edit US-003 --add-criterion "Verify in browser"
+  hal prd story move US-005 --before US-002
+  hal prd story reset US-003
+  hal prd story skip US-004 --reason "blocked on API access"
+  hal prd story remove US-006 --json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var prdStoryShowCmd = &cobra.Command{
+	Use:     "show <story-id>",
+	Short:   "Show one story",
+	Long:    "Show a story's status, priority, description, acceptance criteria and notes.",
+	Args:    exactArgsValidation(1),
+	Example: "  hal prd story show US-003\n  hal prd story show US-003 --json",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPRDStoryShow(cmd, ".", args[0])
+	},
+}
+
+var prdStoryAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Append a story with the next free ID",
+	Long: `Append a story at the lowest priority. The ID defaults to the next free
+US-XXX (or T-XXX for granular PRDs). Use --before or --after to place it.`,
+	Args: noArgsValidation(),
+	Example: `  hal prd story add --title "Add CSV export" --criteria "Export downloads a CSV" --criteria "Typecheck passes"
+  hal prd story add --title "Fix login redirect" --before US-003`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fields := prd.StoryFields{ID: prdStoryIDFlag, Title: prdStoryTitleFlag, Description: prdStoryDescFlag, AcceptanceCriteria: prdStoryCriteriaFlag, Verify: prdStoryVerifyFlag}
+		target := prd.MoveTarget{Before: prdStoryBeforeFlag, After: prdStoryAfterFlag}
+		return runPRDStoryChange(cmd, ".", "add", func(p *engine.PRD) (prdStoryChange, error) {
+			story, err := prd.AddStory(p, fields)
+			if err != nil {
+				return prdStoryChange{}, err
+			}
+			change := prdStoryChange{StoryID: story.ID, Details: []string{"Added: " + story.Title}}
+			if target.Before != "" || target.After != "" {
+				if change.Order, err = prd.MoveStory(p, story.ID, target); err != nil {
+					return prdStoryChange{}, err
+				}
+			}
+			return change, nil
+		})
+	},
+}
+
+var prdStoryEditCmd = &cobra.Command{
+	Use:   "edit <story-id>",
+	Short: "Change a story's title, description, criteria or notes",
+	Long: `Change fields of a story. Only the given flags are applied.
+--criteria replaces all acceptance criteria; --add-criterion appends.
+--verify replaces the story's verify commands, which hal runs before
+accepting the story as passing.`,
+	Args: exactArgsValidation(1),
+	Example: `  hal prd story edit US-003 --title "Export as CSV"
+  hal prd story edit US-003 --add-criterion "Verify in browser"
+  hal prd story edit US-003 --criteria "CSV has headers" --criteria "Typecheck passes"
+  hal prd story edit US-003 --verify "go test ./internal/export/..."`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var edit prd.StoryEdit
+		flags := cmd.Flags()
+		if flags.Changed("title") {
+			edit.Title = &prdStoryTitleFlag
+		}
+		if flags.Changed("description") {
+			edit.Description = &prdStoryDescFlag
+		}
+		if flags.Changed("notes") {
+			edit.Notes = &prdStoryNotesFlag
+		}
+		if flags.Changed("criteria") {
+			edit.AcceptanceCriteria = append([]string{}, prdStoryCriteriaFlag...)
+		}
+		edit.AddCriteria = prdStoryAddCriteriaFlag
+		if flags.Changed("verify") {
+			edit.Verify = append([]string{}, prdStoryVerifyFlag...)
+		}
+		return runPRDStoryChange(cmd, ".", "edit", func(p *engine.PRD) (prdStoryChange, error) {
+			story, changed, err := prd.EditStory(p, args[0], edit)
+			if err != nil {
+				return prdStoryChange{}, err
+			}
+			if len(changed) == 0 {
+				return prdStoryChange{}, fmt.Errorf("no changes for %s", story.ID)
+			}
+			return prdStoryChange{StoryID: story.ID, Changed: changed, Details: []string{"Changed: " + strings.Join(changed, ", ")}}, nil
+		})
+	},
+}
+
+var prdStoryRemoveCmd = &cobra.Command{
+	Use:     "remove <story-id>",
+	Short:   "Remove a story",
+	Long:    "Remove a story from .hal/prd.json. Its ID is not reused by 'hal prd story add'.",
+	Args:    exactArgsValidation(1),
+	Example: "  hal prd story remove US-006",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPRDStoryChange(cmd, ".", "remove", func(p *engine.PRD) (prdStoryChange, error) {
+			story, err := prd.RemoveStory(p, args[0])
+			if err != nil {
+				return prdStoryChange{}, err
+			}
+			return prdStoryChange{StoryID: story.ID, Removed: &story, Details: []string{"Removed: " + story.Title}}, nil
+		})
+	},
+}
+
+var prdStoryMoveCmd = &cobra.Command{
+	Use:   "move <story-id>",
+	Short: "Reorder a story and renumber priorities",
+	Long: `Move a story to a position in priority order (--to, 1-based) or next to
+another story (--before/--after). Priorities are renumbered 1..n.`,
+	Args: exactArgsValidation(1),
+	Example: `  hal prd story move US-005 --to 1
+  hal prd story move US-005 --after US-002`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		set := 0
+		for _, name := range []string{"to", "before", "after"} {
+			if cmd.Flags().Changed(name) {
+				set++
+			}
+		}
+		if set != 1 {
+			return exitWithCode(cmd, ExitCodeValidation, fmt.Errorf("exactly one of --to, --before or --after is required"))
+		}
+		target := prd.MoveTarget{Position: prdStoryToFlag, Before: prdStoryBeforeFlag, After: prdStoryAfterFlag}
+		return runPRDStoryChange(cmd, ".", "move", func(p *engine.PRD) (prdStoryChange, error) {
+			order, err := prd.MoveStory(p, args[0], target)
+			if err != nil {
+				return prdStoryChange{}, err
+			}
+			return prdStoryChange{StoryID: args[0], Order: order, Details: []string{"Order: " + strings.Join(order, ", ")}}, nil
+		})
+	},
+}
+
+var prdStoryResetCmd = &cobra.Command{
+	Use:     "reset <story-id>",
+	Short:   "Mark a story as not passing (and not skipped)",
+	Long:    "Set passes to false and clear skipped so the next run picks the story up again.",
+	Args:    exactArgsValidation(1),
+	Example: "  hal prd story reset US-003",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPRDStoryChange(cmd, ".", "reset", func(p *engine.PRD) (prdStoryChange, error) {
+			story, err := prd.ResetStory(p, args[0])
+			if err != nil {
+				return prdStoryChange{}, err
+			}
+			return prdStoryChange{StoryID: story.ID, Details: []string{"Reset to pending"}}, nil
+		})
+	},
+}
+
+var prdStorySkipCmd = &cobra.Command{
+	Use:     "skip <story-id>",
+	Short:   "Skip a pending story so runs pass over it",
+	Long:    "Mark a pending story as skipped. Skipped stories are ignored by hal run and\nleft out of progress counts; --reason is added to the story notes.",
+	Args:    exactArgsValidation(1),
+	Example: "  hal prd story skip US-004 --reason \"blocked on API access\"",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runPRDStoryChange(cmd, ".", "skip", func(p *engine.PRD) (prdStoryChange, error) {
+			story, err := prd.SkipStory(p, args[0], prdStoryReasonFlag)
+			if err != nil {
+				return prdStoryChange{}, err
+			}
+			detail := "Skipped"
+			if reason := strings.TrimSpace(prdStoryReasonFlag); reason != "" {
+				detail += ": " + reason
+			}
+			return prdStoryChange{StoryID: story.ID, Details: []string{detail}}, nil
+		})
+	},
+}
+
+func init() {
+	prdStoryCmd.PersistentFlags().BoolVar(&prdStoryJSONFlag, "json", false, "Output machine-readable JSON result")
+
+	prdStoryAddCmd.Flags().StringVar(&prdStoryIDFlag, "id", "", "Story ID (default: next free ID)")
+	for _, c := range []*cobra.Command{prdStoryAddCmd, prdStoryEditCmd} {
+		c.Flags().StringVar(&prdStoryTitleFlag, "title", "", "Story title")
+		c.Flags().StringVar(&prdStoryDescFlag, "description", "", "Story description")
+		c.Flags().StringArrayVar(&prdStoryCriteriaFlag, "criteria", nil, "Acceptance criterion (repeatable; replaces existing on edit)")
+		c.Flags().StringArrayVar(&prdStoryVerifyFlag, "verify", nil, "Shell command that must pass before the story is accepted (repeatable; replaces existing on edit)")
+	}
+	prdStoryEditCmd.Flags().StringArrayVar(&prdStoryAddCriteriaFlag, "add-criterion", nil, "Append an acceptance criterion (repeatable)")
+	prdStoryEditCmd.Flags().StringVar(&prdStoryNotesFlag, "notes", "", "Replace story notes")
+	for _, c := range []*cobra.Command{prdStoryAddCmd, prdStoryMoveCmd} {
+		c.Flags().StringVar(&prdStoryBeforeFlag, "before", "", "Place before this story")
+		c.Flags().StringVar(&prdStoryAfterFlag, "after", "", "Place after this story")
+	}
+	prdStoryMoveCmd.Flags().IntVar(&prdStoryToFlag, "to", 0, "1-based position in priority order")
+	prdStorySkipCmd.Flags().StringVar(&prdStoryReasonFlag, "reason", "", "Why the story is skipped (added to notes)")
+
+	prdStoryCmd.AddCommand(prdStoryShowCmd, prdStoryAddCmd, prdStoryEditCmd, prdStoryRemoveCmd, prdStoryMoveCmd, prdStoryResetCmd, prdStorySkipCmd)
+	prdCmd.AddCommand(prdStoryCmd)
+}
+
+// prdStoryChange describes what a story subcommand changed.
+type prdStoryChange struct {
+	StoryID string
+	Removed *engine.UserStory
+	Changed []string
+	Order   []string
+	Details []string
+}
+
+func prdStoryOutput(cmd *cobra.Command) (io.Writer, bool) {
+	out := io.Writer(os.Stdout)
+	jsonMode := prdStoryJSONFlag
+	if cmd != nil {
+		out = cmd.OutOrStdout()
+		if f := cmd.Flags().Lookup("json"); f != nil {
+			jsonMode = f.Value.String() == "true"
+		}
+	}
+	return out, jsonMode
+}
+
+func loadStoryPRD(dir string) (*engine.PRD, error) {
+	p, err := engine.LoadPRD(filepath.Join(dir, template.HalDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%s not found; run 'hal convert' first", filepath.Join(template.HalDir, template.PRDFile))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", template.PRDFile, err)
+	}
+	return p, nil
+}
+
+func runPRDStoryShow(cmd *cobra.Command, dir, id string) error {
+	out, jsonMode := prdStoryOutput(cmd)
+	p, err := loadStoryPRD(dir)
+	if err != nil {
+		return err
+	}
+	story := p.FindStoryByID(strings.TrimSpace(id))
+	if story == nil {
+		return exitWithCode(cmd, ExitCodeValidation, fmt.Errorf("story not found: %s", id))
+	}
+	if jsonMode {
+		return writePRDStoryJSON(out, PRDStoryResult{ContractVersion: 1, OK: true, Action: "show", Story: story, Summary: fmt.Sprintf("%s: %s", story.ID, story.Title)})
+	}
+	writeStoryDetails(out, story)
+	return nil
+}
+
+// runPRDStoryChange loads .hal/prd.json, applies change, validates the
+// result and writes it atomically with an audit entry in progress.txt.
+func runPRDStoryChange(cmd *cobra.Command, dir, action string, change func(*engine.PRD) (prdStoryChange, error)) error {
+	out, jsonMode := prdStoryOutput(cmd)
+	p, err := loadStoryPRD(dir)
+	if err != nil {
+		return err
+	}
+
+	result, err := change(p)
+	if err != nil {
+		return exitWithCode(cmd, ExitCodeValidation, err)
+	}
+
+	validation := prd.ValidateStructure(p)
+	story := result.Removed
+	if story == nil {
+		story = p.FindStoryByID(result.StoryID)
+	}
+	jr := PRDStoryResult{
+		ContractVersion: 1,
+		OK:              validation.Valid,
+		Action:          action,
+		Story:           story,
+		Changed:         result.Changed,
+		Order:           result.Order,
+		Validation:      validation,
+	}
+
+	if !validation.Valid {
+		jr.Summary = fmt.Sprintf("%s %s rejected: PRD would fail validation; nothing written", action, result.StoryID)
+		if jsonMode {
+			if err := writePRDStoryJSON(out, jr); err != nil {
+				return err
+			}
+		} else {
+			engine.NewDisplay(out).ShowCommandError(jr.Summary, storyValidationIssues(validation.Errors), storyValidationIssues(validation.Warnings))
+		}
+		return exitWithCode(cmd, ExitCodeValidation, fmt.Errorf("story %s rejected by validation", action))
+	}
+
+	halDir := filepath.Join(dir, template.HalDir)
+	if err := engine.SavePRD(halDir, p); err != nil {
+		return fmt.Errorf("failed to write %s: %w", template.PRDFile, err)
+	}
+	if err := prd.AppendStoryAudit(halDir, result.StoryID, action, result.Details, prdStoryNow()); err != nil {
+		return err
+	}
+
+	jr.Summary = fmt.Sprintf("%s %s", storyActionPast(action), result.StoryID)
+	if jsonMode {
+		return writePRDStoryJSON(out, jr)
+	}
+	fmt.Fprintf(out, "%s %s\n", engine.StyleSuccess.Render("✓"), jr.Summary)
+	for _, d := range result.Details {
+		fmt.Fprintf(out, "  %s\n", engine.StyleMuted.Render(d))
+	}
+	for _, w := range validation.Warnings {
+		label := w.Message
+		if w.StoryID != "" {
+			label = w.StoryID + ": " + label
+		}
+		fmt.Fprintf(out, "  %s %s\n", engine.StyleWarning.Render("⚠"), label)
+	}
+	return nil
+}
+
+func storyActionPast(action string) string {
+	switch action {
+	case "add":
+		return "Added"
+	case "edit":
+		return "Edited"
+	case "remove":
+		return "Removed"
+	case "move":
+		return "Moved"
+	case "reset":
+		return "Reset"
+	case "skip":
+		return "Skipped"
+	}
+	return action
+}
+
+func storyValidationIssues(issues []prd.Issue) []engine.ValidationIssue {
+	out := make([]engine.ValidationIssue, len(issues))
+	for i, issue := range issues {
+		out[i] = engine.ValidationIssue{StoryID: issue.StoryID, Field: issue.Field, Message: issue.Message}
+	}
+	return out
+}
+
+func writeStoryDetails(out io.Writer, story *engine.UserStory) {
+	status := engine.StyleMuted.Render("pending")
+	switch {
+	case story.Passes:
+		status = engine.StyleSuccess.Render("passes")
+	case story.Skipped:
+		status = engine.StyleWarning.Render("skipped")
+	}
+	fmt.Fprintf(out, "%s %s\n", engine.StyleBold.Render(story.ID), story.Title)
+	fmt.Fprintf(out, "  Status:     %s\n", status)
+	fmt.Fprintf(out, "  Priority:   %d\n", story.Priority)
+	if story.Description != "" {
+		fmt.Fprintf(out, "  %s\n", story.Description)
+	}
+	if len(story.AcceptanceCriteria) > 0 {
+		fmt.Fprintln(out, "  Acceptance criteria:")
+		for _, c := range story.AcceptanceCriteria {
+			fmt.Fprintf(out, "    - %s\n", c)
+		}
+	}
+	if len(story.Verify) > 0 {
+		fmt.Fprintln(out, "  Verify:")
+		for _, c := range story.Verify {
+			fmt.Fprintf(out, "    $ %s\n", c)
+		}
+	}
+	if story.Notes != "" {
+		fmt.Fprintf(out, "  Notes:      %s\n", strings.ReplaceAll(story.Notes, "\n", "\n              "))
+	}
+}
+
+func writePRDStoryJSON(out io.Writer, result PRDStoryResult) error {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal story result: %w", err)
+	}
+	fmt.Fprintln(out, string(data))
+	return nil
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jywlabs/hal/internal/engine"
+	"github.com/jywlabs/hal/internal/prd"
+	"github.com/jywlabs/hal/internal/template"
+	"github.com/spf13/cobra"
+)
+
+func writeStoryTestPRD(t *testing.T, dir string) {
+	t.Helper()
+	halDir := filepath.Join(dir, template.HalDir)
+	if err := os.MkdirAll(halDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	p := &engine.PRD{
+		BranchName: "hal/export",
+		UserStories: []engine.UserStory{
+			{ID: "US-001", Title: "One", AcceptanceCriteria: []string{"a"}, Priority: 1, Passes: true},
+			{ID: "US-002", Title: "Two", AcceptanceCriteria: []string{"b"}, Priority: 2},
+		},
+	}
+	if err := engine.SavePRD(halDir, p); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newPRDStoryTestCmd(jsonMode bool) (*cobra.Command, *bytes.Buffer) {
+	var out bytes.Buffer
+	c := &cobra.Command{Use: "story"}
+	c.SetOut(&out)
+	c.Flags().Bool("json", jsonMode, "")
+	return c, &out
+}
+
+func TestRunPRDStoryChange_WritesAndAudits(t *testing.T) {
+	dir := t.TempDir()
+	writeStoryTestPRD(t, dir)
+	origNow := prdStoryNow
+	prdStoryNow = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC) }
+	t.Cleanup(func() { prdStoryNow = origNow })
+
+	c, out := newPRDStoryTestCmd(true)
+	err := runPRDStoryChange(c, dir, "reset", func(p *engine.PRD) (prdStoryChange, error) {
+		story, err := prd.ResetStory(p, "US-001")
+		if err != nil {
+			return prdStoryChange{}, err
+		}
+		return prdStoryChange{StoryID: story.ID, Details: []string{"Reset to pending"}}, nil
+	})
+	if err != nil {
+		t.Fatalf("runPRDStoryChange() error = %v", err)
+	}
+
+	var result PRDStoryResult
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out.String())
+	}
+	if !result.OK || result.Action != "reset" || result.Story == nil || result.Story.Passes || result.Summary != "Reset US-001" {
+		t.Fatalf("result = %+v", result)
+	}
+
+	p, err := engine.LoadPRD(filepath.Join(dir, template.HalDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.UserStories[0].Passes {
+		t.Error("prd.json was not updated")
+	}
+	progress, _ := os.ReadFile(filepath.Join(dir, template.HalDir, template.ProgressFile))
+	if !strings.Contains(string(progress), "## 2026-01-02 03:04 - US-001\n- hal prd story reset\n- Reset to pending\n---\n") {
+		t.Errorf("progress.txt missing audit entry:\n%s", progress)
+	}
+}
+
+func TestRunPRDStoryChange_RejectsInvalidResult(t *testing.T) {
+	dir := t.TempDir()
+	writeStoryTestPRD(t, dir)
+	prdPath := filepath.Join(dir, template.HalDir, template.PRDFile)
+	before, _ := os.ReadFile(prdPath)
+
+	c, out := newPRDStoryTestCmd(true)
+	err := runPRDStoryChange(c, dir, "edit", func(p *engine.PRD) (prdStoryChange, error) {
+		p.UserStories[1].ID = "US-001"
+		return prdStoryChange{StoryID: "US-001"}, nil
+	})
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+
+	var result PRDStoryResult
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	if result.OK || result.Validation == nil || len(result.Validation.Errors) == 0 {
+		t.Fatalf("result = %+v, want validation errors", result)
+	}
+	after, _ := os.ReadFile(prdPath)
+	if !bytes.Equal(before, after) {
+		t.Error("prd.json changed despite failed validation")
+	}
+	if _, err := os.Stat(filepath.Join(dir, template.HalDir, template.ProgressFile)); !os.IsNotExist(err) {
+		t.Error("progress.txt should not get an audit entry for a rejected change")
+	}
+}
+
+func TestRunPRDStoryShow_Human(t *testing.T) {
+	dir := t.TempDir()
+	writeStoryTestPRD(t, dir)
+
+	c, out := newPRDStoryTestCmd(false)
+	if err := runPRDStoryShow(c, dir, "US-002"); err != nil {
+		t.Fatalf("runPRDStoryShow() error = %v", err)
+	}
+	for _, want := range []string{"US-002", "Two", "pending", "- b"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+	if err := runPRDStoryShow(c, dir, "US-404"); err == nil {
+		t.Error("expected not found error")
+	}
+}
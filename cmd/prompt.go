@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jywlabs/hal/internal/compound"
+	display "github.com/jywlabs/hal/internal/engine"
+	"github.com/jywlabs/hal/internal/loop"
+	"github.com/jywlabs/hal/internal/template"
+	"github.com/spf13/cobra"
+)
+
+var promptCmd = &cobra.Command{
+	Use:   "prompt",
+	Short: "Inspect the agent prompt sent by hal run",
+	Long: `Inspect the agent prompt hal run sends to the engine each iteration.
+
+.hal/prompt.md is rendered with Go text/template. Besides the
+{{PRD_FILE}}, {{PROGRESS_FILE}}, {{BASE_BRANCH}} and {{STANDARDS}}
+placeholders, templates can use:
+  .Story              Target story (.Story.ID, .Story.Title, .Story.AcceptanceCriteria, ...)
+  .Iteration          Iteration number (1-based)
+  .MaxIterations      Iteration limit
+  .RemainingStories   Stories still pending
+  .Previous           Last iteration: .Previous.Outcome and .Previous.Error
+  .DiffStat           git diff --stat against the base branch
+  .Engine, .BaseBranch, .PRDFile, .ProgressFile, .Standards
+
+An optional .hal/prompt.<engine>.md overlay is parsed after prompt.md. It
+can redefine {{block}} sections of prompt.md, or replace the prompt and
+include the base with {{template "base" .}}.
+
+A prompt.md that does not parse as a template and has no {{.Field}} actions
+is rendered as before: only the placeholders are replaced, with a warning.`,
+	Example: `  hal prompt render
+  hal prompt render --story US-002
+  hal prompt render --engine claude`,
+}
+
+var (
+	promptRenderStoryFlag  string
+	promptRenderEngineFlag string
+	promptRenderBaseFlag   string
+)
+
+var promptRenderCmd = &cobra.Command{
+	Use:   "render",
+	Short: "Preview the prompt hal run would send",
+	Args:  noArgsValidation(),
+	Long: `Render the prompt exactly as hal run would send it on its first iteration.
+
+The target is --story, or else the next pending story in .hal/prd.json.
+The engine (and so the prompt.<engine>.md overlay) is --engine, or else the
+default engine from .hal/config.yaml.`,
+	Example: `  hal prompt render
+  hal prompt render --story US-002
+  hal prompt render --engine claude --base develop`,
+	RunE: runPromptRender,
+}
+
+func init() {
+	promptRenderCmd.Flags().StringVarP(&promptRenderStoryFlag, "story", "s", "", "Story ID to target (default: next pending story)")
+	promptRenderCmd.Flags().StringVarP(&promptRenderEngineFlag, "engine", "e", "codex", "Engine whose prompt overlay to use (claude, codex, gemini, pi)")
+	promptRenderCmd.Flags().StringVarP(&promptRenderBaseFlag, "base", "b", "", "Base branch (default: current branch, or HEAD when detached)")
+	promptCmd.AddCommand(promptRenderCmd)
+	rootCmd.AddCommand(promptCmd)
+}
+
+func runPromptRender(cmd *cobra.Command, args []string) error {
+	out := io.Writer(os.Stdout)
+	errOut := io.Writer(os.Stderr)
+	if cmd != nil {
+		out = cmd.OutOrStdout()
+		errOut = cmd.ErrOrStderr()
+	}
+
+	resolvedEngine, err := resolveEngine(cmd, "engine", promptRenderEngineFlag, ".")
+	if err != nil {
+		return exitWithCode(cmd, ExitCodeValidation, err)
+	}
+	baseBranch := compound.ResolveBaseBranch(
+		promptRenderBaseFlag,
+		compound.CurrentBranchOptional,
+		func(format string, args ...any) {
+			fmt.Fprintf(errOut, format, args...)
+		},
+	)
+	return runPromptRenderFn(".", loop.Config{
+		Engine:     resolvedEngine,
+		StoryID:    promptRenderStoryFlag,
+		BaseBranch: baseBranch,
+	}, out, errOut)
+}
+
+// runPromptRenderFn writes the rendered prompt to out and the files and
+// story it came from to errOut, so out can be piped as-is.
+func runPromptRenderFn(dir string, cfg loop.Config, out, errOut io.Writer) error {
+	halDir := filepath.Join(dir, template.HalDir)
+	if _, err := os.Stat(halDir); os.IsNotExist(err) {
+		return fmt.Errorf(".hal/ not found. Run 'hal init' first")
+	}
+
+	standardsCfg, err := compound.LoadStandardsConfig(dir)
+	if err != nil {
+		return exitWithCode(nil, ExitCodeValidation, fmt.Errorf("invalid standards config: %w", err))
+	}
+	cfg.Dir = halDir
+	cfg.StandardsBudget = standardsCfg.Budget
+	cfg.Logger = errOut
+
+	prompt, story, err := loop.PreviewPrompt(cfg)
+	if err != nil {
+		return exitWithCode(nil, ExitCodeValidation, err)
+	}
+
+	sources := loop.PromptSources(halDir, cfg.Engine)
+	target := "no pending story"
+	if story != nil {
+		target = story.ID + ": " + story.Title
+	}
+	for i, source := range sources {
+		sources[i], _ = filepath.Rel(dir, source)
+	}
+	fmt.Fprintln(errOut, display.StyleMuted.Render(fmt.Sprintf("# %s (engine %s, %s)", strings.Join(sources, ", "), cfg.Engine, target)))
+	fmt.Fprint(out, prompt)
+	return nil
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jywlabs/hal/internal/engine"
+	"github.com/jywlabs/hal/internal/loop"
+	"github.com/jywlabs/hal/internal/template"
+)
+
+func setupPromptRenderDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	halDir := filepath.Join(dir, template.HalDir)
+	if err := os.MkdirAll(halDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, halDir, template.PromptFile, "{{with .Story}}Target {{.ID}}: {{.Title}}{{end}} ({{.RemainingStories}} left) from {{BASE_BRANCH}}\n")
+	writeFile(t, halDir, loop.PromptOverlayFile("claude"), "{{template \"base\" .}}Claude extras\n")
+	prd := &engine.PRD{UserStories: []engine.UserStory{
+		{ID: "US-001", Title: "Schema", Priority: 1},
+		{ID: "US-002", Title: "Endpoint", Priority: 2},
+	}}
+	if err := engine.SavePRDFile(halDir, template.PRDFile, prd); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestRunPromptRenderFn(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        loop.Config
+		wantOut    string
+		wantSource string
+	}{
+		{
+			name:       "next pending story",
+			cfg:        loop.Config{Engine: "codex", BaseBranch: "main"},
+			wantOut:    "Target US-001: Schema (2 left) from main\n",
+			wantSource: ".hal/prompt.md (engine codex, US-001: Schema)",
+		},
+		{
+			name:       "story flag and engine overlay",
+			cfg:        loop.Config{Engine: "claude", StoryID: "US-002", BaseBranch: "develop"},
+			wantOut:    "Target US-002: Endpoint (2 left) from develop\nClaude extras\n",
+			wantSource: ".hal/prompt.md, .hal/prompt.claude.md (engine claude, US-002: Endpoint)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := setupPromptRenderDir(t)
+			var out, errOut bytes.Buffer
+			if err := runPromptRenderFn(dir, tt.cfg, &out, &errOut); err != nil {
+				t.Fatalf("runPromptRenderFn() error: %v", err)
+			}
+			if out.String() != tt.wantOut {
+				t.Errorf("output = %q, want %q", out.String(), tt.wantOut)
+			}
+			if !strings.Contains(errOut.String(), tt.wantSource) {
+				t.Errorf("stderr = %q, want %q", errOut.String(), tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestRunPromptRenderFn_UnknownStory(t *testing.T) {
+	dir := setupPromptRenderDir(t)
+	var out, errOut bytes.Buffer
+	err := runPromptRenderFn(dir, loop.Config{Engine: "codex", StoryID: "US-404"}, &out, &errOut)
+	assertExitCodeError(t, err, ExitCodeValidation, "story not found: US-404")
+}
+
+func TestRunPromptRenderFn_DefaultPromptNamesStory(t *testing.T) {
+	dir := setupPromptRenderDir(t)
+	writeFile(t, filepath.Join(dir, template.HalDir), template.PromptFile, template.DefaultPrompt)
+	var out, errOut bytes.Buffer
+	if err := runPromptRenderFn(dir, loop.Config{Engine: "codex", BaseBranch: "main"}, &out, &errOut); err != nil {
+		t.Fatalf("runPromptRenderFn() error: %v", err)
+	}
+	for _, want := range []string{"## Current Story", "**US-001: Schema**", "(2 stories remaining, iteration 1 of 10)", "create it from `main`"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("rendered default prompt missing %q:\n%s", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "{{") {
+		t.Errorf("rendered default prompt has unrendered actions:\n%s", out.String())
+	}
+}
@@ -1,15 +1,21 @@
 package cmd
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/jywlabs/hal/internal/compound"
 	"github.com/jywlabs/hal/internal/doctor"
 	ui "github.com/jywlabs/hal/internal/engine"
+	"github.com/jywlabs/hal/internal/repair"
+	"github.com/jywlabs/hal/internal/sandbox"
 	"github.com/jywlabs/hal/internal/skills"
 	"github.com/jywlabs/hal/internal/template"
 	"github.com/spf13/cobra"
@@ -18,22 +24,36 @@ import (
 var (
 	repairDryRunFlag bool
 	repairJSONFlag   bool
+	repairYesFlag    bool
+	repairUndoFlag   bool
 )
 
 // RepairResult is the machine-readable output of hal repair --json.
 type RepairResult struct {
 	ContractVersion int            `json:"contractVersion"`
 	OK              bool           `json:"ok"`
+	Plan            []RepairStep   `json:"plan,omitempty"`
 	Applied         []RepairAction `json:"applied,omitempty"`
 	Remaining       []string       `json:"remaining,omitempty"`
+	Journal         string         `json:"journal,omitempty"`
+	Restored        []string       `json:"restored,omitempty"`
 	Summary         string         `json:"summary"`
 }
 
+// RepairStep is one entry in the ordered repair plan.
+type RepairStep struct {
+	CheckIDs  []string `json:"checkIds"`
+	Command   string   `json:"command,omitempty"`
+	Safe      bool     `json:"safe"`
+	Automatic bool     `json:"automatic"` // false when the step must be done by hand
+	Reason    string   `json:"reason"`
+}
+
 // RepairAction describes a single repair step that was applied.
 type RepairAction struct {
 	CheckID string `json:"checkId"`
 	Command string `json:"command"`
-	Status  string `json:"status"` // "applied", "skipped", "failed"
+	Status  string `json:"status"` // "applied", "skipped", "declined", "failed"
 	Error   string `json:"error,omitempty"`
 }
 
@@ -41,22 +61,36 @@ var repairCmd = &cobra.Command{
 	Use:   "repair",
 	Short: "Auto-fix environment issues detected by doctor",
 	Args:  noArgsValidation(),
-	Long: `Automatically fix environment issues detected by hal doctor.
+	Long: `Fix environment issues detected by hal doctor with an ordered repair plan.
 
-Only applies safe remediations:
+The plan covers every failing or warning check. Safe steps run directly:
   - hal init (for missing .hal/ files, skills, commands)
   - hal cleanup (for legacy debris)
-  - hal links refresh (for stale engine links)
+  - hal sandbox migrate (for legacy .hal/sandbox.json state)
+  - hal links refresh / clean (for stale or broken engine links)
+
+Unsafe steps, such as hal init --refresh-templates, ask for confirmation
+first; --yes accepts them. Without a terminal they are skipped unless
+--yes is set. Steps hal cannot run (gh auth login, installing an engine
+CLI) are listed for you to do by hand.
+
+Every file a repair touches is journaled to .hal/repair-backups/<ts>/.
+Use --undo to restore the state before the most recent repair; run it
+again to walk back further.
 
-Use --dry-run to preview what would be fixed.
+Use --dry-run to preview the plan.
 Use --json for machine-readable output.
 
 Examples:
-  hal repair            # Fix all safe issues
-  hal repair --dry-run  # Preview fixes
+  hal repair            # Fix issues, confirming unsafe steps
+  hal repair --yes      # Also apply unsafe steps without asking
+  hal repair --dry-run  # Preview the plan
+  hal repair --undo     # Roll back the most recent repair
   hal repair --json     # Machine-readable result`,
 	Example: `  hal repair
+  hal repair --yes
   hal repair --dry-run
+  hal repair --undo
   hal repair --json`,
 	RunE: runRepair,
 }
@@ -64,33 +98,90 @@ Examples:
 func init() {
 	repairCmd.Flags().BoolVar(&repairDryRunFlag, "dry-run", false, "Preview repairs without applying")
 	repairCmd.Flags().BoolVar(&repairJSONFlag, "json", false, "Output machine-readable JSON result")
+	repairCmd.Flags().BoolVarP(&repairYesFlag, "yes", "y", false, "Apply unsafe repairs without confirmation")
+	repairCmd.Flags().BoolVar(&repairUndoFlag, "undo", false, "Restore files changed by the most recent repair")
 	rootCmd.AddCommand(repairCmd)
 }
 
+type repairRequest struct {
+	DryRun bool
+	JSON   bool
+	Yes    bool
+}
+
+type repairDeps struct {
+	runDoctor func(opts doctor.Options) doctor.DoctorResult
+	execute   func(dir, command string) error
+	isTTY     func(r io.Reader) bool
+	now       func() time.Time
+}
+
+var defaultRepairDeps = repairDeps{
+	runDoctor: doctor.Run,
+	execute:   executeRepairCommand,
+	isTTY:     isTTY,
+	now:       time.Now,
+}
+
 func runRepair(cmd *cobra.Command, args []string) error {
+	in := io.Reader(os.Stdin)
 	out := io.Writer(os.Stdout)
-	dryRun := repairDryRunFlag
-	jsonMode := repairJSONFlag
+	req := repairRequest{DryRun: repairDryRunFlag, JSON: repairJSONFlag, Yes: repairYesFlag}
+	undo := repairUndoFlag
 
 	if cmd != nil {
+		in = cmd.InOrStdin()
 		out = cmd.OutOrStdout()
 		if cmd.Flags().Lookup("dry-run") != nil {
 			v, _ := cmd.Flags().GetBool("dry-run")
-			dryRun = v
+			req.DryRun = v
 		}
 		if cmd.Flags().Lookup("json") != nil {
 			v, _ := cmd.Flags().GetBool("json")
-			jsonMode = v
+			req.JSON = v
+		}
+		if cmd.Flags().Lookup("yes") != nil {
+			v, _ := cmd.Flags().GetBool("yes")
+			req.Yes = v
+		}
+		if cmd.Flags().Lookup("undo") != nil {
+			v, _ := cmd.Flags().GetBool("undo")
+			undo = v
+		}
+	}
+
+	if undo {
+		if req.DryRun || req.Yes {
+			return exitWithCode(cmd, ExitCodeValidation, fmt.Errorf("--undo cannot be combined with --dry-run or --yes"))
 		}
+		return runRepairUndoFn(".", req.JSON, out)
 	}
 
-	return runRepairFn(".", dryRun, jsonMode, out)
+	return runRepairWithDeps(".", req, in, out, defaultRepairDeps)
 }
 
 func runRepairFn(dir string, dryRun bool, jsonMode bool, out io.Writer) error {
+	return runRepairWithDeps(dir, repairRequest{DryRun: dryRun, JSON: jsonMode}, os.Stdin, out, defaultRepairDeps)
+}
+
+func runRepairWithDeps(dir string, req repairRequest, in io.Reader, out io.Writer, deps repairDeps) error {
+	if deps.runDoctor == nil {
+		deps.runDoctor = defaultRepairDeps.runDoctor
+	}
+	if deps.execute == nil {
+		deps.execute = defaultRepairDeps.execute
+	}
+	if deps.isTTY == nil {
+		deps.isTTY = defaultRepairDeps.isTTY
+	}
+	if deps.now == nil {
+		deps.now = defaultRepairDeps.now
+	}
+	dryRun, jsonMode := req.DryRun, req.JSON
+
 	engine, _ := compound.LoadDefaultEngine(dir)
 
-	result := doctor.Run(doctor.Options{
+	result := deps.runDoctor(doctor.Options{
 		Dir:    dir,
 		Engine: engine,
 	})
@@ -110,118 +201,325 @@ func runRepairFn(dir string, dryRun bool, jsonMode bool, out io.Writer) error {
 		return nil
 	}
 
-	// Collect unique safe remediation commands
-	type repairStep struct {
-		checkID string
-		command string
-	}
-	seen := map[string]bool{}
-	var steps []repairStep
-
-	for _, c := range result.Checks {
-		if (c.Status == doctor.StatusFail || c.Status == doctor.StatusWarn) && c.Remediation != nil && c.Remediation.Safe {
-			if !seen[c.Remediation.Command] {
-				seen[c.Remediation.Command] = true
-				steps = append(steps, repairStep{checkID: c.ID, command: c.Remediation.Command})
-			}
+	plan := buildRepairPlan(result.Checks)
+	var automatic int
+	for _, step := range plan {
+		if step.Automatic {
+			automatic++
 		}
 	}
 
-	if len(steps) == 0 {
+	if automatic == 0 {
 		remaining := append(result.Failures, result.Warnings...)
 		if jsonMode {
 			jr := RepairResult{
 				ContractVersion: 1,
 				OK:              false,
+				Plan:            plan,
 				Remaining:       remaining,
-				Summary:         "Issues found but no safe auto-repairs available.",
+				Summary:         "Issues found but no automatic repairs available.",
 			}
 			data, _ := json.MarshalIndent(jr, "", "  ")
 			fmt.Fprintln(out, string(data))
 			return nil
 		}
-		fmt.Fprintf(out, "%s Issues found but no safe auto-repairs available.\n", ui.StyleWarning.Render("[!]"))
-		for _, r := range remaining {
-			fmt.Fprintf(out, "  %s %s\n", ui.StyleMuted.Render("•"), r)
-		}
+		fmt.Fprintf(out, "%s Issues found but no automatic repairs available.\n", ui.StyleWarning.Render("[!]"))
+		showManualRepairSteps(out, plan)
 		return nil
 	}
 
+	if !jsonMode {
+		fmt.Fprintf(out, "%s %d step(s)\n\n", ui.StyleTitle.Render("Repair plan:"), len(plan))
+	}
+
+	interactive := !jsonMode && !req.Yes && deps.isTTY(in)
+	reader := bufio.NewReader(in)
+	journal := repair.Begin(dir, deps.now())
+	if !dryRun {
+		// Snapshot everything the plan may touch before any step runs, so
+		// later steps do not journal state an earlier step already changed.
+		for _, step := range plan {
+			if err := journal.Track(repairTouchedPaths(dir, step.Command)...); err != nil {
+				return err
+			}
+		}
+	}
+
 	var applied []RepairAction
+	var ran int
+	for i, step := range plan {
+		if !step.Automatic {
+			continue
+		}
+		action := RepairAction{
+			CheckID: step.CheckIDs[0],
+			Command: step.Command,
+		}
+		label := step.Command
+		if !step.Safe {
+			label += " " + ui.StyleWarning.Render("(unsafe)")
+		}
 
-	for _, step := range steps {
 		if dryRun {
-			applied = append(applied, RepairAction{
-				CheckID: step.checkID,
-				Command: step.command,
-				Status:  "skipped",
-			})
+			action.Status = "skipped"
+			applied = append(applied, action)
 			if !jsonMode {
-				fmt.Fprintf(out, "  %s [dry-run] Would run: %s\n", ui.StyleWarning.Render("○"), step.command)
+				fmt.Fprintf(out, "  %s [dry-run] Would run: %s\n", ui.StyleWarning.Render("○"), label)
+				fmt.Fprintf(out, "      %s\n", ui.StyleMuted.Render(step.Reason))
 			}
 			continue
 		}
 
-		action := RepairAction{
-			CheckID: step.checkID,
-			Command: step.command,
+		if !step.Safe && !req.Yes {
+			accept := false
+			if interactive {
+				fmt.Fprintf(out, "  %s %s\n      %s\n", ui.StyleWarning.Render(fmt.Sprintf("[%d/%d]", i+1, len(plan))), label, ui.StyleMuted.Render(step.Reason))
+				accept = promptConfirmRepair(reader, out)
+			}
+			if !accept {
+				action.Status = "declined"
+				applied = append(applied, action)
+				if !jsonMode {
+					fmt.Fprintf(out, "  %s %s %s\n", ui.StyleMuted.Render("−"), step.Command, ui.StyleMuted.Render("(not confirmed)"))
+				}
+				continue
+			}
 		}
 
-		err := executeRepairCommand(dir, step.command)
-		if err != nil {
+		journal.Step(step.Command)
+		ran++
+
+		if err := deps.execute(dir, step.Command); err != nil {
 			action.Status = "failed"
 			action.Error = err.Error()
 			if !jsonMode {
-				fmt.Fprintf(out, "  %s %s: %v\n", ui.StyleError.Render("✗"), step.command, err)
+				fmt.Fprintf(out, "  %s %s: %v\n", ui.StyleError.Render("✗"), step.Command, err)
 			}
 		} else {
 			action.Status = "applied"
 			if !jsonMode {
-				fmt.Fprintf(out, "  %s %s\n", ui.StyleSuccess.Render("✓"), step.command)
+				fmt.Fprintf(out, "  %s %s\n", ui.StyleSuccess.Render("✓"), step.Command)
 			}
 		}
 		applied = append(applied, action)
 	}
 
+	var journalID string
+	if ran > 0 {
+		m, err := journal.Commit()
+		if err != nil {
+			return err
+		}
+		if m != nil {
+			journalID = m.ID
+		}
+	}
+
 	// Re-check to find remaining issues
-	recheck := doctor.Run(doctor.Options{Dir: dir, Engine: engine})
+	recheck := result
+	if !dryRun {
+		recheck = deps.runDoctor(doctor.Options{Dir: dir, Engine: engine})
+	}
 	var remaining []string
 	remaining = append(remaining, recheck.Failures...)
 	remaining = append(remaining, recheck.Warnings...)
 
 	allOK := recheck.OverallStatus == doctor.StatusPass
 
+	var summary string
+	switch {
+	case dryRun:
+		summary = fmt.Sprintf("Would apply %d repair(s).", automatic)
+	case allOK:
+		summary = fmt.Sprintf("Applied %d repair(s). Hal is now healthy.", ran)
+	default:
+		summary = fmt.Sprintf("Applied %d repair(s). %d issue(s) remain.", ran, len(remaining))
+	}
+
 	if jsonMode {
 		jr := RepairResult{
 			ContractVersion: 1,
 			OK:              allOK,
+			Plan:            plan,
 			Applied:         applied,
 			Remaining:       remaining,
-		}
-		if dryRun {
-			jr.Summary = fmt.Sprintf("Would apply %d repair(s).", len(steps))
-		} else if allOK {
-			jr.Summary = fmt.Sprintf("Applied %d repair(s). Hal is now healthy.", len(steps))
-		} else {
-			jr.Summary = fmt.Sprintf("Applied %d repair(s). %d issue(s) remain.", len(steps), len(remaining))
+			Journal:         journalID,
+			Summary:         summary,
 		}
 		data, _ := json.MarshalIndent(jr, "", "  ")
 		fmt.Fprintln(out, string(data))
 		return nil
 	}
 
+	showManualRepairSteps(out, plan)
+
 	fmt.Fprintln(out)
 	if dryRun {
-		fmt.Fprintf(out, "%s Would apply %d repair(s). Run without --dry-run to apply.\n",
-			ui.StyleWarning.Render("[!]"), len(steps))
+		fmt.Fprintf(out, "%s %s Run without --dry-run to apply.\n", ui.StyleWarning.Render("[!]"), summary)
 	} else if allOK {
-		fmt.Fprintf(out, "%s Applied %d repair(s). Hal is now healthy.\n",
-			ui.StyleSuccess.Render("[OK]"), len(steps))
+		fmt.Fprintf(out, "%s %s\n", ui.StyleSuccess.Render("[OK]"), summary)
 	} else {
-		fmt.Fprintf(out, "%s Applied %d repair(s). %d issue(s) remain. Run hal doctor for details.\n",
-			ui.StyleWarning.Render("[!]"), len(steps), len(remaining))
+		fmt.Fprintf(out, "%s %s Run hal doctor for details.\n", ui.StyleWarning.Render("[!]"), summary)
+	}
+	if journalID != "" {
+		fmt.Fprintf(out, "%s\n", ui.StyleMuted.Render(fmt.Sprintf("Backed up changes to %s/%s; undo with hal repair --undo.", filepath.ToSlash(filepath.Join(template.HalDir, template.RepairBackupsDir)), journalID)))
+	}
+
+	return nil
+}
+
+// repairCommandOrder ranks automatic repair commands so prerequisites run
+// first: .hal/ must exist before templates are refreshed or links created.
+var repairCommandOrder = map[string]int{
+	"hal init":                     1,
+	"hal init --refresh-templates": 2,
+	"hal cleanup":                  3,
+	"hal sandbox migrate":          4,
+	"hal links clean":              5,
+	"hal links refresh":            6,
+	"hal links refresh codex":      7,
+	"hal skills upgrade":           8,
+}
+
+// buildRepairPlan turns failing and warning checks into an ordered plan with
+// one step per remediation command. Checks hal cannot fix become manual steps
+// after the automatic ones.
+func buildRepairPlan(checks []doctor.Check) []RepairStep {
+	var plan []RepairStep
+	index := map[string]int{}
+	for _, c := range checks {
+		if c.Status != doctor.StatusFail && c.Status != doctor.StatusWarn {
+			continue
+		}
+		command := ""
+		safe := false
+		if c.Remediation != nil {
+			command = c.Remediation.Command
+			safe = c.Remediation.Safe
+		}
+		if i, ok := index[command]; ok && command != "" {
+			plan[i].CheckIDs = append(plan[i].CheckIDs, c.ID)
+			plan[i].Safe = plan[i].Safe && safe
+			continue
+		}
+		_, automatic := repairCommandOrder[command]
+		index[command] = len(plan)
+		plan = append(plan, RepairStep{
+			CheckIDs:  []string{c.ID},
+			Command:   command,
+			Safe:      safe,
+			Automatic: automatic,
+			Reason:    c.Message,
+		})
 	}
 
+	sort.SliceStable(plan, func(a, b int) bool {
+		return repairStepRank(plan[a]) < repairStepRank(plan[b])
+	})
+	return plan
+}
+
+func repairStepRank(step RepairStep) int {
+	if rank, ok := repairCommandOrder[step.Command]; ok {
+		return rank
+	}
+	return len(repairCommandOrder) + 1
+}
+
+func showManualRepairSteps(out io.Writer, plan []RepairStep) {
+	for _, step := range plan {
+		if step.Automatic {
+			continue
+		}
+		fmt.Fprintf(out, "  %s %s\n", ui.StyleMuted.Render("•"), step.Reason)
+		if step.Command != "" {
+			fmt.Fprintf(out, "      %s %s\n", ui.StyleMuted.Render("run:"), ui.StyleInfo.Render(step.Command))
+		}
+	}
+}
+
+// promptConfirmRepair asks whether to run an unsafe repair step.
+func promptConfirmRepair(reader *bufio.Reader, out io.Writer) bool {
+	fmt.Fprint(out, "      Apply? [y/N] ")
+	line, _ := reader.ReadString('\n')
+	answer := strings.TrimSpace(strings.ToLower(line))
+	return answer == "y" || answer == "yes"
+}
+
+// repairTouchedPaths lists the paths a repair command may change, so they
+// can be journaled before it runs.
+func repairTouchedPaths(dir, command string) []string {
+	halDir := template.HalDir
+	var linkDirs []string
+	for _, linker := range skills.Linkers() {
+		linkDirs = append(linkDirs, linker.SkillsDir())
+		if commandsDir := linker.CommandsDir(); commandsDir != "" {
+			linkDirs = append(linkDirs, commandsDir)
+		}
+	}
+
+	switch command {
+	case "hal init", "hal init --refresh-templates":
+		return append([]string{halDir, ".goralph", ".gitignore"}, linkDirs...)
+	case "hal cleanup":
+		return append([]string{halDir}, deprecatedSkillLinks(".")...)
+	case "hal sandbox migrate":
+		return []string{
+			filepath.Join(halDir, template.SandboxFile),
+			filepath.Join(halDir, template.ConfigFile),
+			sandbox.GlobalConfigPath(),
+			sandbox.SandboxesDir(),
+		}
+	case "hal links clean", "hal links refresh":
+		return linkDirs
+	case "hal links refresh codex":
+		if linker := skills.GetLinker("codex"); linker != nil {
+			return []string{linker.SkillsDir(), linker.CommandsDir()}
+		}
+	case "hal skills upgrade":
+		return []string{
+			filepath.Join(halDir, "skills"),
+			filepath.Join(halDir, template.CommandsDir),
+			filepath.Join(halDir, skills.LockFile),
+		}
+	}
+	return nil
+}
+
+func runRepairUndoFn(dir string, jsonMode bool, out io.Writer) error {
+	m, err := repair.Undo(dir)
+	if err != nil {
+		return err
+	}
+
+	jr := RepairResult{ContractVersion: 1, OK: true, Summary: "Nothing to undo."}
+	if m != nil {
+		jr.Journal = m.ID
+		for _, e := range m.Entries {
+			jr.Restored = append(jr.Restored, e.Path)
+		}
+		jr.Summary = fmt.Sprintf("Restored %d path(s) from repair %s.", len(m.Entries), m.ID)
+	}
+
+	if jsonMode {
+		data, _ := json.MarshalIndent(jr, "", "  ")
+		fmt.Fprintln(out, string(data))
+		return nil
+	}
+
+	if m == nil {
+		fmt.Fprintf(out, "%s %s\n", ui.StyleMuted.Render("−"), jr.Summary)
+		return nil
+	}
+	for _, e := range m.Entries {
+		verb := "restored"
+		if e.Change == repair.ChangeCreated {
+			verb = "removed"
+		}
+		fmt.Fprintf(out, "  %s %s %s\n", ui.StyleSuccess.Render("✓"), e.Path, ui.StyleMuted.Render(verb))
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "%s %s\n", ui.StyleSuccess.Render("[OK]"), jr.Summary)
 	return nil
 }
 
@@ -233,6 +531,9 @@ func executeRepairCommand(dir string, command string) error {
 	case "hal cleanup":
 		return runCleanupFn(filepath.Join(dir, template.HalDir), false, io.Discard)
 	case "hal init --refresh-templates":
+		if _, err := refreshTemplates(filepath.Join(dir, template.HalDir), false, io.Discard); err != nil {
+			return err
+		}
 		return runInitWithWriters(nil, nil, io.Discard, io.Discard)
 	case "hal links refresh":
 		// Link errors are non-fatal (some engines may not be available)
@@ -252,6 +553,8 @@ func executeRepairCommand(dir string, command string) error {
 			return err
 		}
 		return linker.LinkCommands(dir)
+	case "hal skills upgrade":
+		return runSkillsUpgradeFn(nil, dir, skills.UpgradeOptions{}, false, io.Discard)
 	default:
 		return fmt.Errorf("unknown repair command: %s", command)
 	}
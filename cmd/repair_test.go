@@ -3,6 +3,7 @@ package cmd
 import (
 	"bytes"
 	"encoding/json"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -267,3 +268,131 @@ func TestRunRepairFn_HealthyRepoJSON(t *testing.T) {
 		t.Fatalf("summary should say no repairs needed: %q", result.Summary)
 	}
 }
+
+func TestBuildRepairPlan_OrdersAndCoversEveryCheck(t *testing.T) {
+	checks := []doctor.Check{
+		{ID: "git_repo", Status: doctor.StatusPass},
+		{ID: "github_auth", Status: doctor.StatusWarn, Message: "gh not logged in", Remediation: &doctor.Remediation{Command: "gh auth login"}},
+		{ID: "broken_skill_links", Status: doctor.StatusWarn, Message: "broken", Remediation: &doctor.Remediation{Command: "hal links clean", Safe: true}},
+		{ID: "prompt_md", Status: doctor.StatusWarn, Message: "stale prompt", Remediation: &doctor.Remediation{Command: "hal init --refresh-templates"}},
+		{ID: "hal_skills", Status: doctor.StatusFail, Message: "missing skills", Remediation: &doctor.Remediation{Command: "hal init", Safe: true}},
+		{ID: "hal_commands", Status: doctor.StatusFail, Message: "missing commands", Remediation: &doctor.Remediation{Command: "hal init", Safe: true}},
+		{ID: "codex_stream", Status: doctor.StatusFail, Message: "no result event"},
+	}
+
+	plan := buildRepairPlan(checks)
+	var got []string
+	for _, step := range plan {
+		got = append(got, strings.Join(step.CheckIDs, "+")+"="+step.Command)
+	}
+	want := []string{
+		"hal_skills+hal_commands=hal init",
+		"prompt_md=hal init --refresh-templates",
+		"broken_skill_links=hal links clean",
+		"github_auth=gh auth login",
+		"codex_stream=",
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("plan = %v, want %v", got, want)
+	}
+	if plan[1].Safe || !plan[1].Automatic || plan[3].Automatic || plan[4].Automatic {
+		t.Fatalf("unexpected step flags: %+v", plan)
+	}
+}
+
+func unsafeRepairDeps(executed *[]string, tty bool) repairDeps {
+	return repairDeps{
+		runDoctor: func(opts doctor.Options) doctor.DoctorResult {
+			if len(*executed) > 0 {
+				return doctor.DoctorResult{OverallStatus: doctor.StatusPass}
+			}
+			return doctor.DoctorResult{
+				OverallStatus: doctor.StatusWarn,
+				Warnings:      []string{"prompt_md", "legacy_debris"},
+				Checks: []doctor.Check{
+					{ID: "prompt_md", Status: doctor.StatusWarn, Message: "stale prompt", Remediation: &doctor.Remediation{Command: "hal init --refresh-templates"}},
+					{ID: "legacy_debris", Status: doctor.StatusWarn, Message: "debris", Remediation: &doctor.Remediation{Command: "hal cleanup", Safe: true}},
+				},
+			}
+		},
+		execute: func(dir, command string) error {
+			*executed = append(*executed, command)
+			return os.WriteFile(filepath.Join(dir, template.HalDir, template.PromptFile), []byte("# refreshed\n"), 0644)
+		},
+		isTTY: func(io.Reader) bool { return tty },
+	}
+}
+
+func TestRunRepairWithDeps_UnsafeStepsNeedConfirmation(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      repairRequest
+		tty      bool
+		input    string
+		wantExec []string
+	}{
+		{name: "confirmed", tty: true, input: "y\n", wantExec: []string{"hal init --refresh-templates", "hal cleanup"}},
+		{name: "declined", tty: true, input: "n\n", wantExec: []string{"hal cleanup"}},
+		{name: "no terminal skips unsafe", input: "y\n", wantExec: []string{"hal cleanup"}},
+		{name: "yes applies unsafe", req: repairRequest{Yes: true}, wantExec: []string{"hal init --refresh-templates", "hal cleanup"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := setupHealthyDir(t)
+			var executed []string
+			var out bytes.Buffer
+			if err := runRepairWithDeps(dir, tt.req, strings.NewReader(tt.input), &out, unsafeRepairDeps(&executed, tt.tty)); err != nil {
+				t.Fatalf("runRepairWithDeps() error: %v", err)
+			}
+			if strings.Join(executed, ",") != strings.Join(tt.wantExec, ",") {
+				t.Fatalf("executed = %v, want %v\n%s", executed, tt.wantExec, out.String())
+			}
+		})
+	}
+}
+
+func TestRunRepair_JournalAndUndo(t *testing.T) {
+	dir := setupHealthyDir(t)
+	promptPath := filepath.Join(dir, template.HalDir, template.PromptFile)
+
+	var executed []string
+	var out bytes.Buffer
+	req := repairRequest{JSON: true, Yes: true}
+	if err := runRepairWithDeps(dir, req, strings.NewReader(""), &out, unsafeRepairDeps(&executed, false)); err != nil {
+		t.Fatalf("runRepairWithDeps() error: %v", err)
+	}
+	var result RepairResult
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("JSON unmarshal error: %v\n%s", err, out.String())
+	}
+	if result.Journal == "" || len(result.Plan) != 2 || !result.OK {
+		t.Fatalf("result = %+v", result)
+	}
+	if _, err := os.Stat(filepath.Join(dir, template.HalDir, template.RepairBackupsDir, result.Journal, "journal.json")); err != nil {
+		t.Fatalf("journal not written: %v", err)
+	}
+
+	out.Reset()
+	if err := runRepairUndoFn(dir, true, &out); err != nil {
+		t.Fatalf("runRepairUndoFn() error: %v", err)
+	}
+	var undo RepairResult
+	if err := json.Unmarshal(out.Bytes(), &undo); err != nil {
+		t.Fatalf("JSON unmarshal error: %v\n%s", err, out.String())
+	}
+	if undo.Journal != result.Journal || strings.Join(undo.Restored, ",") != ".hal/prompt.md" {
+		t.Fatalf("undo = %+v", undo)
+	}
+	data, _ := os.ReadFile(promptPath)
+	if string(data) != "# Agent\n" {
+		t.Fatalf("prompt.md = %q, want original restored", data)
+	}
+
+	out.Reset()
+	if err := runRepairUndoFn(dir, false, &out); err != nil {
+		t.Fatalf("runRepairUndoFn() error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Nothing to undo") {
+		t.Fatalf("second undo output = %q", out.String())
+	}
+}
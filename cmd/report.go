@@ -83,7 +83,7 @@ var defaultReportDeps = reportDeps{
 func init() {
 	reportCmd.Flags().BoolVar(&reportDryRunFlag, "dry-run", false, "Preview without executing")
 	reportCmd.Flags().BoolVar(&reportSkipAgentsFlag, "skip-agents", false, "Skip AGENTS.md update")
-	reportCmd.Flags().StringVarP(&reportEngineFlag, "engine", "e", "codex", "Engine to use (codex, claude, pi)")
+	reportCmd.Flags().StringVarP(&reportEngineFlag, "engine", "e", "codex", "Engine to use (codex, claude, gemini, pi)")
 	reportCmd.Flags().BoolVar(&reportJSONFlag, "json", false, "Output machine-readable JSON result")
 	rootCmd.AddCommand(reportCmd)
 }
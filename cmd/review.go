@@ -64,7 +64,7 @@ Use 'hal report' for legacy session reporting.`,
 func init() {
 	reviewCmd.Flags().StringVar(&reviewBaseFlag, "base", "", "Base branch to review against")
 	reviewCmd.Flags().IntVarP(&reviewIterationsFlag, "iterations", "i", 10, "Maximum review iterations")
-	reviewCmd.Flags().StringVarP(&reviewEngineFlag, "engine", "e", "codex", "Engine to use (claude, codex, pi)")
+	reviewCmd.Flags().StringVarP(&reviewEngineFlag, "engine", "e", "codex", "Engine to use (claude, codex, gemini, pi)")
 	reviewCmd.Flags().BoolVar(&reviewJSONFlag, "json", false, "Output machine-readable JSON result (skip terminal rendering)")
 	rootCmd.AddCommand(reviewCmd)
 }
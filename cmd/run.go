@@ -7,6 +7,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/jywlabs/hal/internal/compound"
@@ -43,11 +44,14 @@ type RunResult struct {
 	Engine          string         `json:"engine,omitempty"`
 	Iterations      int            `json:"iterations"`
 	Complete        bool           `json:"complete"`
+	Stopped         bool           `json:"stopped,omitempty"`
 	StoryID         string         `json:"storyId,omitempty"`
 	LastStoryID     string         `json:"lastStoryId,omitempty"`
 	DryRun          bool           `json:"dryRun,omitempty"`
 	Duration        string         `json:"duration,omitempty"`
 	PRD             *RunPRDInfo    `json:"prd,omitempty"`
+	Splits          []RunSplit     `json:"splits,omitempty"`
+	VerifyFailures  []RunVerify    `json:"verifyFailures,omitempty"`
 	NextAction      *RunNextAction `json:"nextAction,omitempty"`
 	Error           string         `json:"error,omitempty"`
 	Summary         string         `json:"summary"`
@@ -60,6 +64,19 @@ type RunPRDInfo struct {
 	TotalStories     int    `json:"totalStories"`
 }
 
+// RunSplit records a story split into sub-stories after it stalled.
+type RunSplit struct {
+	StoryID    string   `json:"storyId"`
+	Iterations int      `json:"iterations"`
+	SubStories []string `json:"subStories"`
+}
+
+// RunVerify records a story reset because a verify command failed.
+type RunVerify struct {
+	StoryID string `json:"storyId"`
+	Command string `json:"command"`
+}
+
 // RunNextAction suggests what to do after the run.
 type RunNextAction struct {
 	ID          string `json:"id"`
@@ -80,6 +97,13 @@ The loop spawns fresh AI instances that:
 5. Update prd.json to mark story complete
 6. Repeat until all stories pass or max iterations reached
 
+A story still pending after storySplit.afterIterations iterations (default 3,
+see .hal/config.yaml) is split into smaller sub-stories (US-004a, US-004b, ...)
+that keep its acceptance criteria, and the loop continues with them.
+
+While the loop runs, 'hal ctl' pauses, resumes or stops it, and skips or
+reprioritizes stories between iterations.
+
 With --json, outputs a stable machine-readable result contract suitable
 for agent orchestration and tooling integration.
 
@@ -106,7 +130,7 @@ Examples:
 
 func init() {
 	// Engine selection
-	runCmd.Flags().StringVarP(&engineFlag, "engine", "e", "codex", "Engine to use (claude, codex, pi)")
+	runCmd.Flags().StringVarP(&engineFlag, "engine", "e", "codex", "Engine to use (claude, codex, gemini, pi)")
 
 	// Execution control
 	runCmd.Flags().IntVar(&maxRetries, "retries", 3, "Max retries per iteration on failure")
@@ -280,19 +304,37 @@ func runRunWithWriter(cmd *cobra.Command, args []string, errOut io.Writer) error
 	if timeoutOverride > 0 {
 		engineCfg = withTimeoutOverride(engineCfg, timeoutOverride)
 	}
+	splitCfg, err := compound.LoadStorySplitConfig(".")
+	if err != nil {
+		err = fmt.Errorf("invalid storySplit config: %w", err)
+		if jsonMode {
+			return outputRunJSONError(out, err.Error())
+		}
+		return exitWithCode(cmd, ExitCodeValidation, err)
+	}
+	standardsCfg, err := compound.LoadStandardsConfig(".")
+	if err != nil {
+		err = fmt.Errorf("invalid standards config: %w", err)
+		if jsonMode {
+			return outputRunJSONError(out, err.Error())
+		}
+		return exitWithCode(cmd, ExitCodeValidation, err)
+	}
 
 	// Create and run the loop
 	runner, err := loop.New(loop.Config{
-		Dir:           halDir,
-		MaxIterations: iterations,
-		Engine:        resolvedEngine,
-		EngineConfig:  engineCfg,
-		Logger:        out,
-		RetryDelay:    delay,
-		MaxRetries:    retries,
-		DryRun:        dryRun,
-		StoryID:       story,
-		BaseBranch:    baseBranch,
+		Dir:             halDir,
+		MaxIterations:   iterations,
+		Engine:          resolvedEngine,
+		EngineConfig:    engineCfg,
+		Logger:          out,
+		RetryDelay:      delay,
+		MaxRetries:      retries,
+		DryRun:          dryRun,
+		StoryID:         story,
+		BaseBranch:      baseBranch,
+		SplitAfter:      splitCfg.SplitAfter(),
+		StandardsBudget: standardsCfg.Budget,
 	})
 	if err != nil {
 		if jsonMode {
@@ -327,6 +369,9 @@ func showRunSummary(out io.Writer, result loop.Result) {
 	} else if result.Error != nil {
 		fmt.Fprintf(out, "%s Failed after %d iteration(s).\n",
 			engine.StyleError.Render("✗"), result.Iterations)
+	} else if result.Stopped {
+		fmt.Fprintf(out, "%s Stopped by hal ctl after %d iteration(s).\n",
+			engine.StyleInfo.Render("■"), result.Iterations)
 	} else if result.Success {
 		fmt.Fprintf(out, "%s Completed %d iteration(s). Stories remain.\n",
 			engine.StyleInfo.Render("→"), result.Iterations)
@@ -347,6 +392,18 @@ func showRunSummary(out io.Writer, result loop.Result) {
 		fmt.Fprintf(out, "%s %s\n", engine.StyleBold.Render("Last story:"), storyLabel)
 	}
 
+	// Show stories split after stalling
+	for _, split := range result.Splits {
+		fmt.Fprintf(out, "%s %s → %s (after %d iterations)\n",
+			engine.StyleBold.Render("Split:"), split.StoryID, strings.Join(split.SubStories, ", "), split.Iterations)
+	}
+
+	// Show stories rejected by their verify commands
+	for _, failure := range result.VerifyFailures {
+		fmt.Fprintf(out, "%s %s reset: %s failed\n",
+			engine.StyleBold.Render("Verify:"), failure.StoryID, failure.Command)
+	}
+
 	// Show PRD progress from loop result
 	if result.TotalStories > 0 {
 		fmt.Fprintf(out, "%s Progress: %d/%d stories complete",
@@ -389,6 +446,7 @@ func outputRunJSON(out io.Writer, result loop.Result, storyID string, dryRun boo
 		StoryID:         storyID,
 		DryRun:          dryRun,
 		Complete:        result.Complete,
+		Stopped:         result.Stopped,
 	}
 	if result.Duration > 0 {
 		jr.Duration = result.Duration.Round(time.Second).String()
@@ -397,6 +455,17 @@ func outputRunJSON(out io.Writer, result loop.Result, storyID string, dryRun boo
 		jr.LastStoryID = result.LastStoryID
 	}
 
+	for _, split := range result.Splits {
+		jr.Splits = append(jr.Splits, RunSplit{
+			StoryID:    split.StoryID,
+			Iterations: split.Iterations,
+			SubStories: split.SubStories,
+		})
+	}
+	for _, failure := range result.VerifyFailures {
+		jr.VerifyFailures = append(jr.VerifyFailures, RunVerify{StoryID: failure.StoryID, Command: failure.Command})
+	}
+
 	// Story progress from loop result
 	if result.TotalStories > 0 {
 		jr.PRD = &RunPRDInfo{
@@ -417,6 +486,13 @@ func outputRunJSON(out io.Writer, result loop.Result, storyID string, dryRun boo
 			Command:     "hal report",
 			Description: "Generate a report for the completed work.",
 		}
+	} else if result.Stopped {
+		jr.Summary = fmt.Sprintf("Stopped by hal ctl after %d iteration(s).", result.Iterations)
+		jr.NextAction = &RunNextAction{
+			ID:          "run_manual",
+			Command:     "hal run",
+			Description: "Continue executing the remaining stories.",
+		}
 	} else if result.Success {
 		jr.Summary = fmt.Sprintf("Completed %d iteration(s). Stories remain.", result.Iterations)
 		jr.NextAction = &RunNextAction{
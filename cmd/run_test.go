@@ -395,6 +395,29 @@ func TestOutputRunJSON(t *testing.T) {
 	}
 }
 
+func TestOutputRunJSON_IncludesSplitsAndVerifyFailures(t *testing.T) {
+	result := loop.Result{
+		Success:        true,
+		Iterations:     4,
+		Splits:         []loop.StorySplit{{StoryID: "US-004", Iterations: 3, SubStories: []string{"US-004a", "US-004b"}}},
+		VerifyFailures: []loop.VerifyFailure{{StoryID: "US-002", Command: "go test ./...", Output: "FAIL"}},
+	}
+	var buf bytes.Buffer
+	if err := outputRunJSON(&buf, result, "", false, "codex"); err != nil {
+		t.Fatalf("outputRunJSON() error = %v", err)
+	}
+	var jr RunResult
+	if err := json.Unmarshal(buf.Bytes(), &jr); err != nil {
+		t.Fatalf("JSON unmarshal error: %v", err)
+	}
+	if len(jr.Splits) != 1 || jr.Splits[0].StoryID != "US-004" || len(jr.Splits[0].SubStories) != 2 {
+		t.Fatalf("splits = %+v", jr.Splits)
+	}
+	if len(jr.VerifyFailures) != 1 || jr.VerifyFailures[0].Command != "go test ./..." {
+		t.Fatalf("verifyFailures = %+v", jr.VerifyFailures)
+	}
+}
+
 func TestOutputRunJSONError(t *testing.T) {
 	var buf bytes.Buffer
 	if err := outputRunJSONError(&buf, "test error msg"); err != nil {
@@ -64,6 +64,17 @@ All values are saved to global sandbox config (~/.config/hal/sandbox-config.yaml
 unless HAL_CONFIG_HOME is set). Re-running setup lets you update individual
 values — press Enter to keep the current value.
 
+Env values may be secret references instead of plaintext; they are resolved
+only when a sandbox is created and redacted from all output. Only values
+starting with secret: are references; anything else is passed as-is:
+  secret:env:VAR                    read from the local environment
+  secret:file:~/.secrets/anthropic  read from a file (trailing newline trimmed)
+  secret:cmd:pass show anthropic    output of a shell command
+  secret:keyring:hal/GITHUB_TOKEN   OS keyring (macOS Keychain, Linux secret-tool)
+
+When an OS keyring is available, setup offers to store newly entered secrets
+there and save secret:keyring: references in the config file instead.
+
 After setup, 'hal sandbox create' injects all configured env vars automatically.`,
 	Example: `  hal sandbox setup`,
 	RunE:    runSandboxSetupCobra,
@@ -201,6 +212,7 @@ func providerConfigFromGlobal(cfg *sandbox.GlobalConfig) (string, sandbox.Provid
 		provCfg.LightsailBundle = cfg.Lightsail.Bundle
 		provCfg.LightsailKeyPairName = cfg.Lightsail.KeyPairName
 		provCfg.TailscaleLockdown = cfg.TailscaleLockdown
+		provCfg.Plugins = cfg.Plugins
 	}
 
 	return providerName, provCfg
@@ -257,6 +269,9 @@ type lookPathFunc func(file string) (string, error)
 // descriptor of the terminal. Returns the password bytes and any error.
 type passwordReader func(fd int) ([]byte, error)
 
+// sandboxSetupStoreSecret saves a secret in the OS keyring. Injectable for testing.
+var sandboxSetupStoreSecret = sandbox.StoreKeyringSecret
+
 // readPasswordFromTerminal reads a password from stdin with echo disabled.
 func readPasswordFromTerminal(fd int) ([]byte, error) {
 	return term.ReadPassword(fd)
@@ -515,6 +530,7 @@ func runSandboxSetupWithDeps(dir string, in io.Reader, out io.Writer, readPasswo
 	// ── API keys ──
 	fmt.Fprintln(out, "")
 	fmt.Fprintln(out, "  "+ui.StyleBold.Render("API Keys"))
+	fmt.Fprintln(out, "  "+ui.StyleMuted.Render("Values may be references: secret:env:VAR, secret:file:path, secret:cmd:command, secret:keyring:service/account"))
 	fmt.Fprintln(out, "")
 
 	for _, f := range sandboxEnvFields[:3] {
@@ -581,6 +597,10 @@ func runSandboxSetupWithDeps(dir string, in io.Reader, out io.Writer, readPasswo
 		}
 	}
 
+	if err := offerKeyringStorage(reader, out, lookPath, existingGlobal.Env, collected); err != nil {
+		return err
+	}
+
 	// ── Save ──
 	envVars := make(map[string]string)
 	for _, f := range sandboxEnvFields {
@@ -728,6 +748,42 @@ func saveLegacyProjectSandboxConfigIfPresent(
 	return nil
 }
 
+// offerKeyringStorage asks whether newly entered plaintext secrets should be
+// moved to the OS keyring. Accepted secrets are replaced in collected with
+// secret:keyring: references so only the reference reaches the config file.
+func offerKeyringStorage(reader *bufio.Reader, out io.Writer, lookPath lookPathFunc, existing, collected map[string]string) error {
+	var keys []string
+	for _, f := range sandboxEnvFields {
+		v := collected[f.key]
+		if f.secret && v != "" && v != existing[f.key] && !sandbox.IsSecretRef(v) {
+			keys = append(keys, f.key)
+		}
+	}
+	if len(keys) == 0 || !sandbox.KeyringAvailable(lookPath) {
+		return nil
+	}
+
+	fmt.Fprintln(out, "")
+	fmt.Fprintf(out, "  %s (y/n) [%s]: ", ui.StyleBold.Render(fmt.Sprintf("Store %d new secret(s) in the OS keyring instead of the config file?", len(keys))), ui.StyleMuted.Render("n"))
+	line, _ := reader.ReadString('\n')
+	switch v := strings.ToLower(strings.TrimSpace(strings.TrimRight(line, "\r\n"))); v {
+	case "", "n", "no":
+		return nil
+	case "y", "yes":
+	default:
+		return fmt.Errorf("invalid answer %q (expected y or n)", v)
+	}
+
+	for _, key := range keys {
+		if err := sandboxSetupStoreSecret(key, collected[key]); err != nil {
+			return err
+		}
+		collected[key] = sandbox.KeyringRef(key)
+		fmt.Fprintf(out, "  %s %s → %s\n", ui.StyleSuccess.Render("[OK]"), key, collected[key])
+	}
+	return nil
+}
+
 // promptField prompts the user for a single field value.
 // If current is non-empty, it's shown as the default (masked for secrets).
 // Returns the new value, or current if the user presses Enter.
@@ -742,7 +798,7 @@ func promptField(
 	// Build prompt
 	hint := ""
 	if current != "" {
-		if field.secret {
+		if field.secret && !sandbox.IsSecretRef(current) {
 			hint = maskSecret(current)
 		} else {
 			hint = current
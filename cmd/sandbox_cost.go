@@ -0,0 +1,356 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	display "github.com/jywlabs/hal/internal/engine"
+	"github.com/jywlabs/hal/internal/sandbox"
+	"github.com/spf13/cobra"
+)
+
+var sandboxCostJSONFlag bool
+
+var sandboxCostCmd = &cobra.Command{
+	Use:   "cost",
+	Short: "Report sandbox spend and budget",
+	Long: `Report estimated sandbox spend from the global registry.
+
+For each sandbox and each provider, shows the hourly rate, accrued cost since
+creation, month-to-date cost, and projected cost for the current month
+assuming the sandbox keeps existing until month end. Stopped sandboxes still
+accrue cost (cloud providers charge for allocated resources).
+
+Rates default to embedded list prices. Override them in the global sandbox
+config (~/.config/hal/sandbox-config.yaml unless HAL_CONFIG_HOME is set):
+
+  pricing:
+    rates:
+      hetzner:
+        cx22: 0.0075
+      daytona:
+        default: 0.08
+
+A monthly budget enables warnings in 'hal sandbox list' and, with
+blockCreate, refuses 'hal sandbox create' when the projection would exceed it:
+
+  budget:
+    monthly: 50
+    warnPercent: 80
+    blockCreate: true
+
+Use --json for machine-readable output following the sandbox-cost-v1 contract.`,
+	Args: noArgsValidation(),
+	Example: `  hal sandbox cost
+  hal sandbox cost --json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jsonMode := sandboxCostJSONFlag
+		out := io.Writer(os.Stdout)
+		errOut := io.Writer(os.Stderr)
+		if cmd != nil {
+			out = cmd.OutOrStdout()
+			errOut = cmd.ErrOrStderr()
+			if v, err := cmd.Flags().GetBool("json"); err == nil {
+				jsonMode = v
+			}
+		}
+		return runSandboxCobra(cmd, "Sandbox Cost failed", func() error {
+			return runSandboxCost(out, errOut, jsonMode)
+		})
+	},
+}
+
+func init() {
+	sandboxCostCmd.Flags().BoolVar(&sandboxCostJSONFlag, "json", false, "Output machine-readable JSON (sandbox-cost-v1 contract)")
+	sandboxCmd.AddCommand(sandboxCostCmd)
+}
+
+// sandboxCostLoadGlobalConfig loads pricing and budget settings. Package-level
+// var for test injection.
+var sandboxCostLoadGlobalConfig = sandbox.LoadGlobalConfig
+
+// SandboxCostResponse is the machine-readable JSON output for hal sandbox cost --json.
+// Follows the sandbox-cost-v1 contract.
+type SandboxCostResponse struct {
+	ContractVersion string                `json:"contractVersion"`
+	GeneratedAt     time.Time             `json:"generatedAt"`
+	Sandboxes       []SandboxCostEntry    `json:"sandboxes"`
+	Providers       []SandboxCostProvider `json:"providers"`
+	Totals          SandboxCostTotals     `json:"totals"`
+	Budget          *SandboxCostBudget    `json:"budget,omitempty"`
+}
+
+// SandboxCostEntry is the spend breakdown for one sandbox. Cost fields are
+// omitted when no rate is known for the sandbox's provider and size.
+type SandboxCostEntry struct {
+	Name             string   `json:"name"`
+	Provider         string   `json:"provider"`
+	Size             string   `json:"size,omitempty"`
+	Status           string   `json:"status"`
+	HourlyRate       *float64 `json:"hourlyRate,omitempty"`
+	Accrued          *float64 `json:"accrued,omitempty"`
+	MonthToDate      *float64 `json:"monthToDate,omitempty"`
+	ProjectedMonthly *float64 `json:"projectedMonthly,omitempty"`
+}
+
+// SandboxCostProvider aggregates spend for all sandboxes of one provider.
+type SandboxCostProvider struct {
+	Provider         string  `json:"provider"`
+	Sandboxes        int     `json:"sandboxes"`
+	Unpriced         int     `json:"unpriced,omitempty"`
+	Accrued          float64 `json:"accrued"`
+	MonthToDate      float64 `json:"monthToDate"`
+	ProjectedMonthly float64 `json:"projectedMonthly"`
+}
+
+// SandboxCostTotals aggregates spend across all priced sandboxes.
+type SandboxCostTotals struct {
+	Sandboxes        int     `json:"sandboxes"`
+	Unpriced         int     `json:"unpriced,omitempty"`
+	Accrued          float64 `json:"accrued"`
+	MonthToDate      float64 `json:"monthToDate"`
+	ProjectedMonthly float64 `json:"projectedMonthly"`
+}
+
+// SandboxCostBudget reports projected spend against the configured budget.
+type SandboxCostBudget struct {
+	Monthly     float64 `json:"monthly"`
+	WarnPercent int     `json:"warnPercent"`
+	BlockCreate bool    `json:"blockCreate"`
+	Status      string  `json:"status"`
+}
+
+func runSandboxCost(out, errOut io.Writer, jsonMode bool) error {
+	warnOut := out
+	if jsonMode {
+		// Keep machine-readable stdout clean when migration emits warnings.
+		warnOut = errOut
+	}
+	if err := runSandboxAutoMigrate(".", warnOut); err != nil {
+		return err
+	}
+
+	cfg, err := sandboxCostLoadGlobalConfig()
+	if err != nil {
+		return fmt.Errorf("loading global sandbox config: %w", err)
+	}
+
+	instances, err := sandboxListInstances()
+	if err != nil {
+		return fmt.Errorf("listing sandboxes: %w", err)
+	}
+
+	report := buildSandboxCostReport(instances, cfg.Rates(), cfg.Budget, sandboxListNow())
+	if jsonMode {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal sandbox cost report: %w", err)
+		}
+		fmt.Fprintln(out, string(data))
+		return nil
+	}
+
+	renderSandboxCostReport(out, report)
+	return nil
+}
+
+// buildSandboxCostReport computes per-sandbox and per-provider spend.
+func buildSandboxCostReport(instances []*sandbox.SandboxState, rates sandbox.RateTable, budget sandbox.BudgetConfig, now time.Time) SandboxCostResponse {
+	report := SandboxCostResponse{
+		ContractVersion: "sandbox-cost-v1",
+		GeneratedAt:     now,
+		Sandboxes:       make([]SandboxCostEntry, 0, len(instances)),
+		Providers:       []SandboxCostProvider{},
+	}
+
+	byProvider := map[string]*SandboxCostProvider{}
+	for _, inst := range instances {
+		if inst == nil {
+			continue
+		}
+		entry := SandboxCostEntry{
+			Name:     inst.Name,
+			Provider: inst.Provider,
+			Size:     inst.Size,
+			Status:   sandboxNormalizedStatus(inst),
+		}
+
+		agg, ok := byProvider[inst.Provider]
+		if !ok {
+			agg = &SandboxCostProvider{Provider: inst.Provider}
+			byProvider[inst.Provider] = agg
+		}
+		agg.Sandboxes++
+		report.Totals.Sandboxes++
+
+		estimate := rates.Estimate(inst, now)
+		if !estimate.Known {
+			agg.Unpriced++
+			report.Totals.Unpriced++
+			report.Sandboxes = append(report.Sandboxes, entry)
+			continue
+		}
+
+		entry.HourlyRate = costPtr(estimate.HourlyRate, 4)
+		entry.Accrued = costPtr(estimate.Accrued, 2)
+		entry.MonthToDate = costPtr(estimate.MonthToDate, 2)
+		entry.ProjectedMonthly = costPtr(estimate.ProjectedMonthly, 2)
+		report.Sandboxes = append(report.Sandboxes, entry)
+
+		agg.Accrued += estimate.Accrued
+		agg.MonthToDate += estimate.MonthToDate
+		agg.ProjectedMonthly += estimate.ProjectedMonthly
+		report.Totals.Accrued += estimate.Accrued
+		report.Totals.MonthToDate += estimate.MonthToDate
+		report.Totals.ProjectedMonthly += estimate.ProjectedMonthly
+	}
+
+	providers := make([]string, 0, len(byProvider))
+	for name := range byProvider {
+		providers = append(providers, name)
+	}
+	sort.Strings(providers)
+	for _, name := range providers {
+		agg := byProvider[name]
+		agg.Accrued = roundCost(agg.Accrued, 2)
+		agg.MonthToDate = roundCost(agg.MonthToDate, 2)
+		agg.ProjectedMonthly = roundCost(agg.ProjectedMonthly, 2)
+		report.Providers = append(report.Providers, *agg)
+	}
+
+	status := sandbox.EvaluateBudget(budget, report.Totals.ProjectedMonthly)
+	report.Totals.Accrued = roundCost(report.Totals.Accrued, 2)
+	report.Totals.MonthToDate = roundCost(report.Totals.MonthToDate, 2)
+	report.Totals.ProjectedMonthly = roundCost(report.Totals.ProjectedMonthly, 2)
+	if status.Level != sandbox.BudgetDisabled {
+		report.Budget = &SandboxCostBudget{
+			Monthly:     budget.Monthly,
+			WarnPercent: budget.WarnPercent,
+			BlockCreate: budget.BlockCreate,
+			Status:      status.Level,
+		}
+	}
+
+	return report
+}
+
+func renderSandboxCostReport(out io.Writer, report SandboxCostResponse) {
+	if len(report.Sandboxes) == 0 {
+		fmt.Fprintln(out, "No sandboxes found. Run 'hal sandbox create' to provision one.")
+		return
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "%s\n", display.StyleBold.Render("NAME\tPROVIDER\tSIZE\tSTATUS\tRATE/H\tACCRUED\tMONTH-TO-DATE\tPROJECTED"))
+	for _, entry := range report.Sandboxes {
+		size := entry.Size
+		if size == "" {
+			size = "—"
+		}
+		rate := "—"
+		if entry.HourlyRate != nil {
+			rate = fmt.Sprintf("$%.4f", *entry.HourlyRate)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			entry.Name,
+			entry.Provider,
+			size,
+			entry.Status,
+			rate,
+			formatCostPtr(entry.Accrued),
+			formatCostPtr(entry.MonthToDate),
+			formatCostPtr(entry.ProjectedMonthly),
+		)
+	}
+	w.Flush()
+
+	fmt.Fprintln(out)
+	w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "%s\n", display.StyleBold.Render("PROVIDER\tSANDBOXES\tACCRUED\tMONTH-TO-DATE\tPROJECTED"))
+	for _, agg := range report.Providers {
+		count := fmt.Sprintf("%d", agg.Sandboxes)
+		if agg.Unpriced > 0 {
+			count = fmt.Sprintf("%d (%d unpriced)", agg.Sandboxes, agg.Unpriced)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			agg.Provider,
+			count,
+			formatCost(agg.Accrued),
+			formatCost(agg.MonthToDate),
+			formatCost(agg.ProjectedMonthly),
+		)
+	}
+	w.Flush()
+
+	fmt.Fprintf(out, "\n%s  accrued %s  •  month-to-date %s  •  projected %s\n",
+		display.StyleBold.Render("Total:"),
+		formatCost(report.Totals.Accrued),
+		formatCost(report.Totals.MonthToDate),
+		display.StyleWarning.Render(formatCost(report.Totals.ProjectedMonthly)),
+	)
+	if report.Totals.Unpriced > 0 {
+		fmt.Fprintf(out, "%s\n", display.StyleMuted.Render(fmt.Sprintf("%d sandbox(es) have no known rate; add pricing.rates to the global sandbox config.", report.Totals.Unpriced)))
+	}
+
+	if report.Budget != nil {
+		budgetStatus := sandbox.BudgetStatus{
+			Level:     report.Budget.Status,
+			Monthly:   report.Budget.Monthly,
+			Projected: report.Totals.ProjectedMonthly,
+		}
+		if msg := formatBudgetWarning(budgetStatus); msg != "" {
+			fmt.Fprint(out, msg)
+		} else {
+			fmt.Fprintf(out, "%s\n", display.StyleMuted.Render(fmt.Sprintf("Budget: %s / %s per month", formatCost(report.Totals.ProjectedMonthly), formatCost(report.Budget.Monthly))))
+		}
+	}
+}
+
+// projectedMonthlySpend sums the projected monthly cost of all priced instances.
+func projectedMonthlySpend(instances []*sandbox.SandboxState, rates sandbox.RateTable, now time.Time) float64 {
+	total := 0.0
+	for _, inst := range instances {
+		if estimate := rates.Estimate(inst, now); estimate.Known {
+			total += estimate.ProjectedMonthly
+		}
+	}
+	return total
+}
+
+// formatBudgetWarning returns a warning line for budgets at or over their
+// warning threshold, or "" when no warning applies.
+func formatBudgetWarning(status sandbox.BudgetStatus) string {
+	switch status.Level {
+	case sandbox.BudgetExceeded:
+		return fmt.Sprintf("warning: projected monthly sandbox spend %s exceeds budget %s\n",
+			formatCost(status.Projected), formatCost(status.Monthly))
+	case sandbox.BudgetWarning:
+		return fmt.Sprintf("warning: projected monthly sandbox spend %s is %.0f%% of budget %s\n",
+			formatCost(status.Projected), status.Projected/status.Monthly*100, formatCost(status.Monthly))
+	default:
+		return ""
+	}
+}
+
+func costPtr(v float64, places int) *float64 {
+	rounded := roundCost(v, places)
+	return &rounded
+}
+
+func roundCost(v float64, places int) float64 {
+	scale := math.Pow(10, float64(places))
+	return math.Round(v*scale) / scale
+}
+
+func formatCostPtr(cost *float64) string {
+	if cost == nil {
+		return "—"
+	}
+	return formatCost(*cost)
+}
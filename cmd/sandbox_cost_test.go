@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jywlabs/hal/internal/sandbox"
+)
+
+func writeGlobalSandboxConfig(t *testing.T, mutate func(cfg *sandbox.GlobalConfig)) {
+	t.Helper()
+	cfg := sandbox.DefaultGlobalConfig()
+	mutate(&cfg)
+	if err := sandbox.SaveGlobalConfig(&cfg); err != nil {
+		t.Fatalf("SaveGlobalConfig: %v", err)
+	}
+}
+
+func TestBuildSandboxCostReport(t *testing.T) {
+	now := time.Date(2026, 4, 11, 0, 0, 0, 0, time.UTC)
+	rates := sandbox.RateTable{
+		"hetzner": {"cx22": 1},
+		"daytona": {sandbox.DefaultSizeKey: 2},
+	}
+	instances := []*sandbox.SandboxState{
+		{Name: "a", Provider: "hetzner", Size: "cx22", CreatedAt: now.Add(-24 * time.Hour)},
+		{Name: "b", Provider: "hetzner", Size: "cx22", CreatedAt: time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)},
+		{Name: "c", Provider: "daytona", CreatedAt: now.Add(-1 * time.Hour)},
+		{Name: "d", Provider: "hetzner", Size: "ccx63", CreatedAt: now.Add(-1 * time.Hour)},
+	}
+
+	report := buildSandboxCostReport(instances, rates, sandbox.BudgetConfig{Monthly: 1000, WarnPercent: 80}, now)
+
+	if report.ContractVersion != "sandbox-cost-v1" {
+		t.Fatalf("ContractVersion = %q, want sandbox-cost-v1", report.ContractVersion)
+	}
+	if len(report.Sandboxes) != 4 {
+		t.Fatalf("len(Sandboxes) = %d, want 4", len(report.Sandboxes))
+	}
+	if report.Sandboxes[3].Accrued != nil {
+		t.Errorf("unpriced sandbox should have no accrued cost, got %v", *report.Sandboxes[3].Accrued)
+	}
+
+	// b was created before the month started: accrued 11 days, MTD 10 days.
+	b := report.Sandboxes[1]
+	if *b.Accrued != 264 || *b.MonthToDate != 240 || *b.ProjectedMonthly != 720 {
+		t.Errorf("b = accrued %v mtd %v projected %v, want 264/240/720", *b.Accrued, *b.MonthToDate, *b.ProjectedMonthly)
+	}
+
+	if len(report.Providers) != 2 {
+		t.Fatalf("len(Providers) = %d, want 2", len(report.Providers))
+	}
+	if report.Providers[0].Provider != "daytona" || report.Providers[1].Provider != "hetzner" {
+		t.Fatalf("providers should be sorted, got %q, %q", report.Providers[0].Provider, report.Providers[1].Provider)
+	}
+	hetzner := report.Providers[1]
+	if hetzner.Sandboxes != 3 || hetzner.Unpriced != 1 {
+		t.Errorf("hetzner sandboxes = %d unpriced = %d, want 3/1", hetzner.Sandboxes, hetzner.Unpriced)
+	}
+	// a: 21 days to month end from Apr 10, b: full 30 days.
+	if hetzner.ProjectedMonthly != 21*24+720 {
+		t.Errorf("hetzner projected = %v, want %v", hetzner.ProjectedMonthly, 21*24+720)
+	}
+
+	if report.Totals.Unpriced != 1 {
+		t.Errorf("totals.unpriced = %d, want 1", report.Totals.Unpriced)
+	}
+	if report.Budget == nil || report.Budget.Status != sandbox.BudgetExceeded {
+		t.Fatalf("budget = %+v, want exceeded", report.Budget)
+	}
+}
+
+func TestBuildSandboxCostReport_BudgetDisabledOmitted(t *testing.T) {
+	now := time.Date(2026, 4, 11, 0, 0, 0, 0, time.UTC)
+	report := buildSandboxCostReport(nil, sandbox.DefaultRates(), sandbox.BudgetConfig{}, now)
+	if report.Budget != nil {
+		t.Fatalf("budget should be omitted when disabled, got %+v", report.Budget)
+	}
+}
+
+func TestRunSandboxCost_JSONUsesConfiguredRates(t *testing.T) {
+	setupListTest(t)
+
+	now := time.Date(2026, 4, 11, 0, 0, 0, 0, time.UTC)
+	sandboxListNow = func() time.Time { return now }
+	t.Cleanup(func() { sandboxListNow = func() time.Time { return time.Now() } })
+
+	writeGlobalSandboxConfig(t, func(cfg *sandbox.GlobalConfig) {
+		cfg.Pricing.Rates = sandbox.RateTable{"hetzner": {"cx22": 0.5}}
+	})
+	writeInstance(t, &sandbox.SandboxState{
+		ID:        "id-1",
+		Name:      "box",
+		Provider:  "hetzner",
+		Status:    sandbox.StatusRunning,
+		CreatedAt: now.Add(-10 * time.Hour),
+		Size:      "cx22",
+	})
+
+	var buf bytes.Buffer
+	if err := runSandboxCost(&buf, io.Discard, true); err != nil {
+		t.Fatalf("runSandboxCost: %v", err)
+	}
+
+	var resp SandboxCostResponse
+	if err := json.Unmarshal(buf.Bytes(), &resp); err != nil {
+		t.Fatalf("json.Unmarshal: %v\n%s", err, buf.String())
+	}
+	if len(resp.Sandboxes) != 1 || resp.Sandboxes[0].HourlyRate == nil || *resp.Sandboxes[0].HourlyRate != 0.5 {
+		t.Fatalf("sandboxes = %+v, want one entry at override rate 0.5", resp.Sandboxes)
+	}
+	if resp.Totals.Accrued != 5 {
+		t.Errorf("totals.accrued = %v, want 5", resp.Totals.Accrued)
+	}
+}
+
+func TestRunSandboxList_BudgetWarning(t *testing.T) {
+	setupListTest(t)
+
+	now := time.Date(2026, 4, 11, 0, 0, 0, 0, time.UTC)
+	sandboxListNow = func() time.Time { return now }
+	t.Cleanup(func() { sandboxListNow = func() time.Time { return time.Now() } })
+
+	writeGlobalSandboxConfig(t, func(cfg *sandbox.GlobalConfig) {
+		cfg.Budget.Monthly = 1
+	})
+	writeInstance(t, &sandbox.SandboxState{
+		ID:        "id-1",
+		Name:      "box",
+		Provider:  "hetzner",
+		Status:    sandbox.StatusRunning,
+		CreatedAt: now.Add(-10 * time.Hour),
+		Size:      "cx22",
+	})
+
+	var buf bytes.Buffer
+	if err := runSandboxList(&buf, false, false); err != nil {
+		t.Fatalf("runSandboxList: %v", err)
+	}
+	if !strings.Contains(buf.String(), "exceeds budget $1.00") {
+		t.Fatalf("expected budget warning, got: %s", buf.String())
+	}
+
+	var jsonOut, errOut bytes.Buffer
+	if err := runSandboxListWithWriters(&jsonOut, &errOut, true, false); err != nil {
+		t.Fatalf("runSandboxListWithWriters: %v", err)
+	}
+	if strings.Contains(jsonOut.String(), "warning") {
+		t.Fatalf("JSON output must not contain budget warnings: %s", jsonOut.String())
+	}
+	if !strings.Contains(errOut.String(), "exceeds budget") {
+		t.Fatalf("expected budget warning on stderr, got: %s", errOut.String())
+	}
+}
+
+func TestCheckSandboxCreateBudget(t *testing.T) {
+	now := time.Date(2026, 4, 11, 0, 0, 0, 0, time.UTC) // 20 days (480h) to month end
+	origNow := sandboxCreateNow
+	sandboxCreateNow = func() time.Time { return now }
+	t.Cleanup(func() { sandboxCreateNow = origNow })
+
+	existing := []*sandbox.SandboxState{
+		{Name: "old", Provider: "hetzner", Size: "cx22", CreatedAt: now},
+	}
+	origList := sandboxCreateListInstances
+	sandboxCreateListInstances = func() ([]*sandbox.SandboxState, error) { return existing, nil }
+	t.Cleanup(func() { sandboxCreateListInstances = origList })
+
+	cfgWith := func(monthly float64, block bool) *sandbox.GlobalConfig {
+		cfg := sandbox.DefaultGlobalConfig()
+		cfg.Pricing.Rates = sandbox.RateTable{"hetzner": {"cx22": 0.1}}
+		cfg.Budget.Monthly = monthly
+		cfg.Budget.BlockCreate = block
+		return &cfg
+	}
+
+	tests := []struct {
+		name         string
+		cfg          *sandbox.GlobalConfig
+		names        []string
+		ignoreBudget bool
+		wantErr      bool
+		wantOut      string
+	}{
+		{name: "budget disabled", cfg: cfgWith(0, true), names: []string{"new"}},
+		{name: "within budget", cfg: cfgWith(500, true), names: []string{"new"}},
+		{name: "blocks when projection exceeds", cfg: cfgWith(90, true), names: []string{"new"}, wantErr: true},
+		{name: "replacing existing name is not double counted", cfg: cfgWith(90, true), names: []string{"old"}},
+		{name: "warns without blockCreate", cfg: cfgWith(90, false), names: []string{"new"}, wantOut: "exceeds budget"},
+		{name: "ignore budget overrides block", cfg: cfgWith(90, true), names: []string{"new"}, ignoreBudget: true, wantOut: "exceeds budget"},
+		{name: "batch counts every target", cfg: cfgWith(130, true), names: []string{"w-01", "w-02"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := checkSandboxCreateBudget(tt.cfg, "hetzner", "cx22", tt.names, tt.ignoreBudget, &out)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkSandboxCreateBudget() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "--ignore-budget") {
+				t.Errorf("error %q should mention --ignore-budget", err.Error())
+			}
+			if err != nil && strings.Contains(err.Error(), "or stop") {
+				t.Errorf("error %q should not suggest stopping sandboxes, which still accrue cost", err.Error())
+			}
+			if tt.wantOut != "" && !strings.Contains(out.String(), tt.wantOut) {
+				t.Errorf("output %q should contain %q", out.String(), tt.wantOut)
+			}
+		})
+	}
+
+	// A replaced sandbox's spend so far this month still counts: 240h at
+	// $0.10 already spent plus 480h for its replacement exceeds $60.
+	existing = []*sandbox.SandboxState{
+		{Name: "old", Provider: "hetzner", Size: "cx22", CreatedAt: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	if err := checkSandboxCreateBudget(cfgWith(60, true), "hetzner", "cx22", []string{"old"}, false, io.Discard); err == nil {
+		t.Error("checkSandboxCreateBudget() error = nil, want the replaced sandbox's month-to-date spend counted")
+	}
+}
@@ -42,6 +42,10 @@ Use --repo to tag the sandbox with a repository label (informational only).
 
 Use --force to replace an existing sandbox with the same name (deletes the old one first).
 
+When a monthly budget with blockCreate is configured in global sandbox config,
+create refuses to provision sandboxes whose cost through month end would push
+the projected monthly spend over budget. Use --ignore-budget to override.
+
 Auto-shutdown injects HAL_AUTO_SHUTDOWN and HAL_IDLE_HOURS env vars into the sandbox
 so that cloud-init can configure idle timers. Defaults come from global sandbox config.
 
@@ -65,10 +69,11 @@ Human output redacts public cloud and Tailscale addresses by default. Use
 		repo, _ := cmd.Flags().GetString("repo")
 		envSlice, _ := cmd.Flags().GetStringArray("env")
 		envVars := parseEnvFlags(envSlice)
+		ignoreBudget, _ := cmd.Flags().GetBool("ignore-budget")
 		opts := autoShutdownOptsFromCommand(cmd)
 
 		return runSandboxCobra(cmd, "Sandbox Create failed", func() error {
-			return runSandboxCreate(".", name, count, countExplicit, force, size, repo, envVars, opts, cmd.OutOrStdout(), nil)
+			return runSandboxCreate(".", name, count, countExplicit, force, ignoreBudget, size, repo, envVars, opts, cmd.OutOrStdout(), nil)
 		})
 	},
 }
@@ -79,6 +84,12 @@ var sandboxCreateResolveProviderForForceDelete = func(dir, providerName string)
 }
 var newSandboxID = sandbox.NewV7
 
+// sandboxCreateListInstances lists active sandboxes for budget projection.
+var sandboxCreateListInstances = sandbox.ListActiveInstances
+
+// sandboxCreateNow is injectable for deterministic budget tests.
+var sandboxCreateNow = time.Now
+
 type sandboxCreatePendingRemoval interface {
 	Commit() error
 	Rollback() error
@@ -99,6 +110,7 @@ func init() {
 	sandboxCreateCmd.Flags().Bool("auto-shutdown", true, "enable auto-shutdown idle timer")
 	sandboxCreateCmd.Flags().Bool("no-auto-shutdown", false, "disable auto-shutdown idle timer")
 	sandboxCreateCmd.Flags().Int("idle-hours", 0, "hours before idle shutdown (default from global config)")
+	sandboxCreateCmd.Flags().Bool("ignore-budget", false, "create even when the projected monthly spend would exceed the budget")
 	sandboxCmd.AddCommand(sandboxCreateCmd)
 }
 
@@ -257,6 +269,7 @@ func runSandboxCreate(
 	count int,
 	countExplicit bool,
 	force bool,
+	ignoreBudget bool,
 	size, repo string,
 	envVars map[string]string,
 	shutdownOpts autoShutdownOpts,
@@ -269,7 +282,7 @@ func runSandboxCreate(
 		provider = deps.provider
 		getBranch = deps.getBranch
 	}
-	return runSandboxCreateWithDepsAndCountOption(dir, name, count, countExplicit, force, size, repo, envVars, shutdownOpts, out, provider, getBranch)
+	return runSandboxCreateWithDepsAndCountOption(dir, name, count, countExplicit, force, ignoreBudget, size, repo, envVars, shutdownOpts, out, provider, getBranch)
 }
 
 // runSandboxCreateWithDeps contains the testable logic for the sandbox create command.
@@ -291,16 +304,18 @@ func runSandboxCreateWithDeps(
 	provider sandbox.Provider,
 	getBranch branchResolver,
 ) error {
-	return runSandboxCreateWithDepsAndCountOption(dir, name, count, false, force, size, repo, envVars, shutdownOpts, out, provider, getBranch)
+	return runSandboxCreateWithDepsAndCountOption(dir, name, count, false, force, false, size, repo, envVars, shutdownOpts, out, provider, getBranch)
 }
 
 // runSandboxCreateWithDepsAndCountOption contains the sandbox create logic with
 // explicit count-flag semantics from the Cobra command layer.
+// ignoreBudget skips the blockCreate budget gate (warnings are still shown).
 func runSandboxCreateWithDepsAndCountOption(
 	dir, name string,
 	count int,
 	countExplicit bool,
 	force bool,
+	ignoreBudget bool,
 	size, repo string,
 	envVars map[string]string,
 	shutdownOpts autoShutdownOpts,
@@ -325,6 +340,10 @@ func runSandboxCreateWithDepsAndCountOption(
 		applySizeOverride(sandboxCfg, size)
 	}
 	resolvedSize := configuredSandboxSize(sandboxCfg)
+	pluginCfgs := pluginCreateConfigs(globalCfg, sandboxCfg.Provider, size)
+	if !sandbox.IsBuiltinProvider(sandboxCfg.Provider) {
+		resolvedSize = pluginConfiguredSize(pluginCfgs[sandboxCfg.Provider])
+	}
 
 	// Resolve provider if not injected
 	if provider == nil {
@@ -355,6 +374,7 @@ func runSandboxCreateWithDepsAndCountOption(
 			LightsailBundle:           sandboxCfg.Lightsail.Bundle,
 			LightsailKeyPairName:      sandboxCfg.Lightsail.KeyPairName,
 			TailscaleLockdown:         sandboxCfg.TailscaleLockdown,
+			Plugins:                   pluginCfgs,
 		}
 		provider, err = resolveSandboxProvider(sandboxCfg.Provider, provCfg)
 		if err != nil {
@@ -410,13 +430,84 @@ func runSandboxCreateWithDepsAndCountOption(
 		if err != nil {
 			return err
 		}
+		if err := checkSandboxCreateBudget(globalCfg, sandboxCfg.Provider, resolvedSize, targets, ignoreBudget, out); err != nil {
+			return err
+		}
 		return runBatchCreate(dir, targets, force, provider, sandboxCfg, mergedEnv, autoShutdown, idleHours, resolvedSize, repo, halDir, out)
 	}
 
 	// Single sandbox creation
+	if err := checkSandboxCreateBudget(globalCfg, sandboxCfg.Provider, resolvedSize, []string{name}, ignoreBudget, out); err != nil {
+		return err
+	}
 	return runSingleCreate(dir, name, force, provider, sandboxCfg, mergedEnv, autoShutdown, idleHours, resolvedSize, repo, halDir, out)
 }
 
+// checkSandboxCreateBudget projects monthly spend with the new sandboxes kept
+// until month end and compares it with the configured budget. Existing
+// sandboxes with a target name count only their month-to-date spend, since
+// create replaces them.
+// Exceeding a blockCreate budget fails unless ignoreBudget is set; otherwise
+// threshold crossings are reported as warnings.
+func checkSandboxCreateBudget(globalCfg *sandbox.GlobalConfig, providerName, size string, names []string, ignoreBudget bool, out io.Writer) error {
+	if globalCfg == nil || globalCfg.Budget.Monthly <= 0 {
+		return nil
+	}
+
+	rates := globalCfg.Rates()
+	now := sandboxCreateNow()
+	replaced := make(map[string]bool, len(names))
+	for _, name := range names {
+		replaced[name] = true
+	}
+
+	instances, err := sandboxCreateListInstances()
+	if err != nil {
+		return fmt.Errorf("checking sandbox budget: listing sandboxes: %w", err)
+	}
+	kept := make([]*sandbox.SandboxState, 0, len(instances))
+	projected := 0.0
+	for _, inst := range instances {
+		if inst == nil {
+			continue
+		}
+		if !replaced[inst.Name] {
+			kept = append(kept, inst)
+			continue
+		}
+		if estimate := rates.Estimate(inst, now); estimate.Known {
+			projected += estimate.MonthToDate
+		}
+	}
+	projected += projectedMonthlySpend(kept, rates, now)
+
+	rate, ok := rates.HourlyRate(providerName, size)
+	if !ok {
+		if out != nil {
+			fmt.Fprintf(out, "warning: no hourly rate for %s/%s; budget projection excludes the new sandbox\n", providerName, displaySize(size))
+		}
+	} else {
+		projected += sandbox.RemainingMonthCost(rate, now) * float64(len(names))
+	}
+
+	status := sandbox.EvaluateBudget(globalCfg.Budget, projected)
+	if status.Level == sandbox.BudgetExceeded && globalCfg.Budget.BlockCreate && !ignoreBudget {
+		return fmt.Errorf("projected monthly sandbox spend %s would exceed budget %s; delete unused sandboxes (stopped sandboxes still accrue cost), raise budget.monthly, or pass --ignore-budget",
+			formatCost(status.Projected), formatCost(status.Monthly))
+	}
+	if msg := formatBudgetWarning(status); msg != "" && out != nil {
+		fmt.Fprint(out, msg)
+	}
+	return nil
+}
+
+func displaySize(size string) string {
+	if strings.TrimSpace(size) == "" {
+		return sandbox.DefaultSizeKey
+	}
+	return size
+}
+
 func batchPreflight(base string, count int) ([]string, error) {
 	return batchPreflightWithOptions(base, count, false, nil, "", io.Discard)
 }
@@ -675,11 +766,16 @@ func runBatchCreate(
 	halDir string,
 	out io.Writer,
 ) error {
+	mergedEnv, secrets, err := sandbox.ResolveEnvSecrets(mergedEnv)
+	if err != nil {
+		return err
+	}
 	targets, err := prepareBatchCreateTargets(targetNames, mergedEnv)
 	if err != nil {
 		return err
 	}
 	redactor := sandboxRedactor(sandboxShowAddresses, mergedEnv)
+	redactor.KnownSecrets = append(redactor.KnownSecrets, secrets...)
 	for _, target := range targets {
 		redactor.KnownAddresses = append(redactor.KnownAddresses, target.Identity.TailscaleHostname)
 	}
@@ -826,12 +922,19 @@ func runSingleCreate(
 	halDir string,
 	out io.Writer,
 ) error {
+	// Secret references are resolved only now so plaintext values never
+	// touch config files, and every resolved value is redacted from output.
+	mergedEnv, secrets, err := sandbox.ResolveEnvSecrets(mergedEnv)
+	if err != nil {
+		return err
+	}
 	identity, err := prepareSandboxCreateIdentity(name, mergedEnv)
 	if err != nil {
 		return err
 	}
 
 	redactor := sandboxRedactor(sandboxShowAddresses, mergedEnv)
+	redactor.KnownSecrets = append(redactor.KnownSecrets, secrets...)
 	safeOut := sandboxRedactingWriter(out, redactor)
 	defer sandboxFlushRedactor(safeOut)
 	renderOut := io.Writer(safeOut)
@@ -1020,6 +1123,35 @@ func applySizeOverride(cfg *compound.SandboxConfig, size string) {
 	}
 }
 
+// pluginCreateConfigs returns the global plugin settings with the --size
+// override applied to the active plugin provider's "size" key. The global
+// config itself is left untouched.
+func pluginCreateConfigs(globalCfg *sandbox.GlobalConfig, providerName, size string) map[string]map[string]any {
+	if globalCfg == nil {
+		return nil
+	}
+	if sandbox.IsBuiltinProvider(providerName) || size == "" {
+		return globalCfg.Plugins
+	}
+	cfgs := make(map[string]map[string]any, len(globalCfg.Plugins)+1)
+	for name, settings := range globalCfg.Plugins {
+		cfgs[name] = settings
+	}
+	settings := make(map[string]any, len(cfgs[providerName])+1)
+	for key, value := range cfgs[providerName] {
+		settings[key] = value
+	}
+	settings["size"] = size
+	cfgs[providerName] = settings
+	return cfgs
+}
+
+// pluginConfiguredSize returns the plugin's "size" setting, if it is a string.
+func pluginConfiguredSize(settings map[string]any) string {
+	size, _ := settings["size"].(string)
+	return strings.TrimSpace(size)
+}
+
 // configuredSandboxSize returns the effective provider size after config/default merges and --size overrides.
 func configuredSandboxSize(cfg *compound.SandboxConfig) string {
 	switch cfg.Provider {
@@ -338,6 +338,46 @@ func TestRunSandboxCreate_StoresTailscaleHostnameWhenAuthConfigured(t *testing.T
 	}
 }
 
+func TestRunSandboxCreate_ResolvesSecretReferences(t *testing.T) {
+	dir := t.TempDir()
+	setupCreateTest(t, dir)
+	t.Setenv("HAL_TEST_DEPLOY_CRED", "s3cr3t-deploy-value")
+
+	mock := &mockProvider{
+		createResult: &sandbox.SandboxResult{Name: "dev", ID: "ws-123", IP: "10.0.0.1"},
+		createOutput: func(name string, env map[string]string) string {
+			return fmt.Sprintf("cloning with %s\n", env["DEPLOY_CRED"])
+		},
+	}
+
+	var out bytes.Buffer
+	err := runSandboxCreateWithDeps(dir, "dev", 0, false, "", "",
+		map[string]string{"DEPLOY_CRED": "secret:env:HAL_TEST_DEPLOY_CRED", "DATABASE_URL": "file:./dev.db"},
+		autoShutdownOpts{}, &out, mock, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := mock.createCalls[0].Env["DEPLOY_CRED"]; got != "s3cr3t-deploy-value" {
+		t.Fatalf("Create env DEPLOY_CRED = %q, want resolved secret", got)
+	}
+	if got := mock.createCalls[0].Env["DATABASE_URL"]; got != "file:./dev.db" {
+		t.Fatalf("Create env DATABASE_URL = %q, want the plain value unchanged", got)
+	}
+	if strings.Contains(out.String(), "s3cr3t-deploy-value") {
+		t.Fatalf("resolved secret should be redacted from output: %q", out.String())
+	}
+
+	err = runSandboxCreateWithDeps(dir, "dev2", 0, false, "", "",
+		map[string]string{"DEPLOY_CRED": "secret:env:HAL_TEST_UNSET_CRED"},
+		autoShutdownOpts{}, io.Discard, mock, nil)
+	if err == nil || !strings.Contains(err.Error(), "DEPLOY_CRED") {
+		t.Fatalf("unresolvable reference error = %v, want key name", err)
+	}
+	if len(mock.createCalls) != 1 {
+		t.Fatalf("Create should not run when a reference cannot be resolved, got %d calls", len(mock.createCalls))
+	}
+}
+
 func TestRunSandboxCreate_ExplicitName(t *testing.T) {
 	dir := t.TempDir()
 	setupCreateTest(t, dir)
@@ -1835,7 +1875,7 @@ func TestRunSandboxCreate_CountOneIsSingle(t *testing.T) {
 	}
 
 	var out bytes.Buffer
-	err := runSandboxCreateWithDepsAndCountOption(dir, "sb", 1, true, false, "", "", nil, autoShutdownOpts{}, &out, mock, nil)
+	err := runSandboxCreateWithDepsAndCountOption(dir, "sb", 1, true, false, false, "", "", nil, autoShutdownOpts{}, &out, mock, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -1871,7 +1911,7 @@ func TestRunSandboxCreate_CountZeroExplicitReturnsError(t *testing.T) {
 
 	mock := &mockProvider{}
 
-	err := runSandboxCreateWithDepsAndCountOption(dir, "sb", 0, true, false, "", "", nil, autoShutdownOpts{}, io.Discard, mock, nil)
+	err := runSandboxCreateWithDepsAndCountOption(dir, "sb", 0, true, false, false, "", "", nil, autoShutdownOpts{}, io.Discard, mock, nil)
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -2233,7 +2273,7 @@ func TestRunSandboxCreate_ViaRunSandboxCreate(t *testing.T) {
 	}
 
 	var out bytes.Buffer
-	err := runSandboxCreate(dir, "sb", 0, false, false, "cx42", "github.com/org/repo", nil, autoShutdownOpts{}, &out, deps)
+	err := runSandboxCreate(dir, "sb", 0, false, false, false, "cx42", "github.com/org/repo", nil, autoShutdownOpts{}, &out, deps)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -2260,7 +2300,7 @@ func TestRunSandboxCreate_ViaRunSandboxCreateNilDeps(t *testing.T) {
 
 	// With nil deps, runSandboxCreate passes nil provider and nil getBranch
 	// This should fail trying to resolve provider since no daytona config
-	err := runSandboxCreate(dir, "sb", 0, false, false, "", "", nil, autoShutdownOpts{}, io.Discard, nil)
+	err := runSandboxCreate(dir, "sb", 0, false, false, false, "", "", nil, autoShutdownOpts{}, io.Discard, nil)
 	// Expected: resolving provider errors because daytona config is incomplete
 	if err == nil {
 		t.Fatal("expected error with nil deps (no provider configured), got nil")
@@ -3008,7 +3048,7 @@ func TestRunSandboxCreate_ForceViaRunSandboxCreate(t *testing.T) {
 	deps := &sandboxCreateDeps{provider: mock}
 
 	var out bytes.Buffer
-	err := runSandboxCreate(dir, "sb", 0, false, true, "", "", nil, autoShutdownOpts{}, &out, deps)
+	err := runSandboxCreate(dir, "sb", 0, false, true, false, "", "", nil, autoShutdownOpts{}, &out, deps)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -34,9 +34,13 @@ The ACCESS column uses states like tailscale, tailscale pending, public fallback
 or unavailable instead of raw network addresses. With --show-addresses, the
 table also includes an ADDRESS column with the active SSH address.
 
-Estimated cost is based on embedded hourly rates and time since creation.
-Stopped sandboxes still accrue cost (cloud providers charge for allocated resources).
-A dash (—) is shown when rate data is unavailable (e.g., Daytona provider).
+Estimated cost is based on hourly rates and time since creation. Rates default
+to embedded list prices and can be overridden with pricing.rates in the global
+sandbox config. Stopped sandboxes still accrue cost (cloud providers charge for
+allocated resources). A dash (—) is shown when no rate is known for a size.
+
+When a monthly budget is configured, a warning is printed once the projected
+monthly spend reaches the warning threshold. See 'hal sandbox cost'.
 
 The default path reads local registry data only and does not call provider APIs.
 Use --live to fetch fresh status from each provider before rendering.
@@ -209,10 +213,15 @@ func runSandboxListWithWriters(out, errOut io.Writer, jsonMode, liveMode bool) e
 
 	now := sandboxListNow()
 	redactor := sandboxRedactor(sandboxShowAddresses, nil, instances...)
+	rates, budget := loadSandboxCostSettings(warnOut)
+	budgetWarning := formatBudgetWarning(sandbox.EvaluateBudget(budget, projectedMonthlySpend(instances, rates, now)))
 
 	if jsonMode {
 		renderLiveStatusWarnings(warnOut, liveWarnings, redactor)
-		return renderSandboxListJSON(out, instances, now)
+		if budgetWarning != "" {
+			fmt.Fprint(warnOut, budgetWarning)
+		}
+		return renderSandboxListJSON(out, instances, now, rates)
 	}
 
 	safeOut := sandboxRedactingWriter(out, redactor)
@@ -228,15 +237,32 @@ func runSandboxListWithWriters(out, errOut io.Writer, jsonMode, liveMode bool) e
 	}
 
 	// Render table
-	renderSandboxTable(renderOut, instances, now, sandboxShowAddresses)
+	renderSandboxTable(renderOut, instances, now, sandboxShowAddresses, rates)
 
 	// Render summary
-	renderSandboxSummary(renderOut, instances, now)
+	renderSandboxSummary(renderOut, instances, now, rates)
+	if budgetWarning != "" {
+		fmt.Fprint(renderOut, display.StyleWarning.Render(budgetWarning))
+	}
 	renderLiveStatusWarnings(renderOut, liveWarnings, redactor)
 
 	return nil
 }
 
+// loadSandboxCostSettings resolves rates and budget from global config.
+// Config errors never block listing; they fall back to embedded rates with a warning.
+func loadSandboxCostSettings(warnOut io.Writer) (sandbox.RateTable, sandbox.BudgetConfig) {
+	cfg, err := sandboxCostLoadGlobalConfig()
+	if err != nil {
+		if warnOut != nil {
+			fmt.Fprintf(warnOut, "warning: using embedded sandbox rates: loading global sandbox config: %v\n", err)
+		}
+		defaults := sandbox.DefaultGlobalConfig()
+		return sandbox.DefaultRates(), defaults.Budget
+	}
+	return cfg.Rates(), cfg.Budget
+}
+
 // queryLiveStatuses queries each active sandbox's provider for current status.
 // Instances are updated in-place. Each query has a 10s timeout.
 // Failures preserve the persisted status and are returned as warnings.
@@ -387,7 +413,7 @@ func sandboxListLooksLikeUUIDv7(value string) bool {
 }
 
 // renderSandboxListJSON renders the sandbox list as machine-readable JSON.
-func renderSandboxListJSON(out io.Writer, instances []*sandbox.SandboxState, now time.Time) error {
+func renderSandboxListJSON(out io.Writer, instances []*sandbox.SandboxState, now time.Time, rates sandbox.RateTable) error {
 	nowFn := func() time.Time { return now }
 
 	entries := make([]SandboxListEntry, 0, len(instances))
@@ -416,7 +442,7 @@ func renderSandboxListJSON(out io.Writer, instances []*sandbox.SandboxState, now
 			SnapshotID:        inst.SnapshotID,
 		}
 
-		cost := sandbox.EstimatedCost(inst, nowFn)
+		cost := rates.EstimatedCost(inst, nowFn)
 		if cost >= 0 {
 			c := math.Round(cost*100) / 100
 			entry.EstimatedCost = &c
@@ -460,7 +486,7 @@ func renderSandboxListJSON(out io.Writer, instances []*sandbox.SandboxState, now
 }
 
 // renderSandboxTable renders the sandbox list as a formatted table.
-func renderSandboxTable(out io.Writer, instances []*sandbox.SandboxState, now time.Time, showAddresses bool) {
+func renderSandboxTable(out io.Writer, instances []*sandbox.SandboxState, now time.Time, showAddresses bool, rates sandbox.RateTable) {
 	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
 	header := "NAME\tPROVIDER\tSTATUS\tACCESS\tAGE\tAUTO-OFF\tEST.COST"
 	if showAddresses {
@@ -482,7 +508,7 @@ func renderSandboxTable(out io.Writer, instances []*sandbox.SandboxState, now ti
 			autoOff = fmt.Sprintf("%dh", inst.IdleHours)
 		}
 
-		cost := formatCost(sandbox.EstimatedCost(inst, func() time.Time { return now }))
+		cost := formatCost(rates.EstimatedCost(inst, func() time.Time { return now }))
 
 		// Color-code status
 		statusStr := normalizedStatus
@@ -527,7 +553,7 @@ func renderSandboxTable(out io.Writer, instances []*sandbox.SandboxState, now ti
 }
 
 // renderSandboxSummary renders the summary line below the table.
-func renderSandboxSummary(out io.Writer, instances []*sandbox.SandboxState, now time.Time) {
+func renderSandboxSummary(out io.Writer, instances []*sandbox.SandboxState, now time.Time, rates sandbox.RateTable) {
 	total := len(instances)
 	running := 0
 	stopped := 0
@@ -542,7 +568,7 @@ func renderSandboxSummary(out io.Writer, instances []*sandbox.SandboxState, now
 			stopped++
 		}
 
-		cost := sandbox.EstimatedCost(inst, func() time.Time { return now })
+		cost := rates.EstimatedCost(inst, func() time.Time { return now })
 		if cost >= 0 {
 			totalCost += cost
 			hasKnownCost = true
@@ -524,10 +524,11 @@ func TestRunSandboxList_UnknownCostProvider(t *testing.T) {
 
 	writeInstance(t, &sandbox.SandboxState{
 		ID:        "id-1",
-		Name:      "daytona-dev",
-		Provider:  "daytona",
+		Name:      "hetzner-big",
+		Provider:  "hetzner",
 		Status:    sandbox.StatusRunning,
 		CreatedAt: now.Add(-10 * time.Hour),
+		Size:      "ccx63",
 	})
 
 	var buf bytes.Buffer
@@ -538,11 +539,11 @@ func TestRunSandboxList_UnknownCostProvider(t *testing.T) {
 
 	out := buf.String()
 
-	// Cost column should show "—" for daytona
+	// Cost column should show "—" for a size without a rate
 	lines := strings.Split(out, "\n")
 	foundDash := false
 	for _, line := range lines {
-		if strings.Contains(line, "daytona-dev") && strings.Contains(line, "—") {
+		if strings.Contains(line, "hetzner-big") && strings.Contains(line, "—") {
 			foundDash = true
 			break
 		}
@@ -553,7 +554,7 @@ func TestRunSandboxList_UnknownCostProvider(t *testing.T) {
 
 	// Summary total should also be "—" since all are unknown
 	if !strings.Contains(out, "Est. total: —") {
-		t.Errorf("expected Est. total: — for unknown provider, got: %s", out)
+		t.Errorf("expected Est. total: — for unknown size, got: %s", out)
 	}
 }
 
@@ -583,6 +584,15 @@ func TestRunSandboxList_MixedKnownAndUnknownCost(t *testing.T) {
 		IdleHours:    48,
 	})
 
+	writeInstance(t, &sandbox.SandboxState{
+		ID:        "id-3",
+		Name:      "hetzner-big",
+		Provider:  "hetzner",
+		Status:    sandbox.StatusRunning,
+		CreatedAt: now.Add(-10 * time.Hour),
+		Size:      "ccx63",
+	})
+
 	var buf bytes.Buffer
 	err := runSandboxList(&buf, false, false)
 	if err != nil {
@@ -591,10 +601,11 @@ func TestRunSandboxList_MixedKnownAndUnknownCost(t *testing.T) {
 
 	out := buf.String()
 
+	// daytona-dev: default 10h * 0.067 = $0.67
 	// hetzner-dev: cx22 10h * 0.007 = $0.07
-	// total should include only known costs
-	if !strings.Contains(out, "Est. total: $0.07") {
-		t.Errorf("expected Est. total: $0.07 for mixed costs, got: %s", out)
+	// hetzner-big: unknown size, excluded from total
+	if !strings.Contains(out, "Est. total: $0.74") {
+		t.Errorf("expected Est. total: $0.74 for mixed costs, got: %s", out)
 	}
 }
 
@@ -996,7 +1007,7 @@ func TestRunSandboxList_JSON_RequiredFieldKeys(t *testing.T) {
 	writeInstance(t, &sandbox.SandboxState{
 		ID:        "test-id",
 		Name:      "minimal",
-		Provider:  "daytona",
+		Provider:  "hetzner",
 		Status:    sandbox.StatusRunning,
 		CreatedAt: now.Add(-1 * time.Hour),
 	})
@@ -1051,7 +1062,7 @@ func TestRunSandboxList_JSON_OptionalFieldsOmitted(t *testing.T) {
 	writeInstance(t, &sandbox.SandboxState{
 		ID:        "test-id",
 		Name:      "minimal",
-		Provider:  "daytona",
+		Provider:  "hetzner",
 		Status:    sandbox.StatusRunning,
 		CreatedAt: now.Add(-1 * time.Hour),
 	})
@@ -1076,9 +1087,9 @@ func TestRunSandboxList_JSON_OptionalFieldsOmitted(t *testing.T) {
 		}
 	}
 
-	// Daytona has no cost data — estimatedCost should be omitted
+	// Sandboxes without a known size rate have no cost data — estimatedCost should be omitted
 	if _, ok := entry["estimatedCost"]; ok {
-		t.Error("expected estimatedCost to be omitted for unknown provider")
+		t.Error("expected estimatedCost to be omitted for unknown size")
 	}
 
 	// Totals estimatedCost should also be omitted when no known costs
@@ -1153,16 +1164,19 @@ func TestRunSandboxList_JSON_MultipleSandboxes(t *testing.T) {
 	// Estimated costs:
 	// api-backend: cx22 24h * 0.007 = $0.168 → $0.17
 	// frontend: s-2vcpu-4gb 48h * 0.036 = $1.728 → $1.73
-	// worker: daytona → unknown
-	// total = ~$1.90
+	// worker: daytona default 6h * 0.067 = $0.402 → $0.40
+	// total = ~$2.30
 	if resp.Totals.EstimatedCost == nil {
 		t.Fatal("totals.estimatedCost should not be nil (some known costs)")
 	}
+	if got := *resp.Totals.EstimatedCost; got != 2.3 {
+		t.Errorf("totals.estimatedCost = %v, want 2.3", got)
+	}
 
-	// Verify daytona sandbox has no cost
+	// Verify daytona sandbox is priced with the default size rate
 	for _, s := range resp.Sandboxes {
-		if s.Provider == "daytona" && s.EstimatedCost != nil {
-			t.Error("daytona sandbox should not have estimatedCost")
+		if s.Provider == "daytona" && (s.EstimatedCost == nil || *s.EstimatedCost != 0.4) {
+			t.Errorf("daytona sandbox estimatedCost = %v, want 0.4", s.EstimatedCost)
 		}
 	}
 }
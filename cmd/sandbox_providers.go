@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	display "github.com/jywlabs/hal/internal/engine"
+	"github.com/jywlabs/hal/internal/sandbox"
+	"github.com/spf13/cobra"
+)
+
+var sandboxProvidersCmd = &cobra.Command{
+	Use:   "providers",
+	Short: "List built-in and plugin sandbox providers",
+	Long: `List the sandbox providers hal can use.
+
+Built-in providers (daytona, hetzner, digitalocean, lightsail) are always
+available. Any executable named hal-provider-<name> on PATH adds a provider
+called <name>; select it with 'provider: <name>' in the global sandbox config.
+Plugin settings live under plugins.<name> and are passed through to the plugin
+verbatim:
+
+  provider: vultr
+  plugins:
+    vultr:
+      region: ewr
+      size: vc2-1c-2gb
+
+Each plugin is asked to describe itself using the hal-provider-v1 protocol so
+broken or incompatible plugins are reported here rather than at create time.
+
+Use --json for machine-readable output following the sandbox-providers-v1 contract.`,
+	Args: noArgsValidation(),
+	Example: `  hal sandbox providers
+  hal sandbox providers --json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jsonMode, _ := cmd.Flags().GetBool("json")
+		return runSandboxCobra(cmd, "Sandbox Providers failed", func() error {
+			return runSandboxProviders(cmd.OutOrStdout(), jsonMode)
+		})
+	},
+}
+
+func init() {
+	sandboxCmd.AddCommand(sandboxProvidersCmd)
+	sandboxProvidersCmd.Flags().Bool("json", false, "Output machine-readable JSON (sandbox-providers-v1 contract)")
+}
+
+// sandboxProvidersDescribeTimeout bounds each plugin describe call.
+const sandboxProvidersDescribeTimeout = 10 * time.Second
+
+// sandboxDiscoverPlugins is injectable for testing plugin discovery.
+var sandboxDiscoverPlugins = sandbox.DiscoverPlugins
+
+// sandboxDescribePlugin is injectable for testing plugin describe calls.
+var sandboxDescribePlugin = func(ctx context.Context, plugin sandbox.PluginInfo) (*sandbox.PluginDescribeResult, error) {
+	p := &sandbox.PluginProvider{Name: plugin.Name, Path: plugin.Path}
+	return p.Describe(ctx)
+}
+
+// SandboxProvidersResponse is the machine-readable JSON output for
+// hal sandbox providers --json. Follows the sandbox-providers-v1 contract.
+type SandboxProvidersResponse struct {
+	ContractVersion string                 `json:"contractVersion"`
+	Configured      string                 `json:"configured"`
+	Providers       []SandboxProviderEntry `json:"providers"`
+}
+
+// SandboxProviderEntry describes one built-in or plugin provider.
+type SandboxProviderEntry struct {
+	Name        string   `json:"name"`
+	Kind        string   `json:"kind"`
+	Path        string   `json:"path,omitempty"`
+	Protocol    string   `json:"protocol,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Sizes       []string `json:"sizes,omitempty"`
+	Error       string   `json:"error,omitempty"`
+}
+
+func runSandboxProviders(out io.Writer, jsonMode bool) error {
+	configured := sandbox.DefaultGlobalConfig().Provider
+	if cfg, err := sandbox.LoadGlobalConfig(); err == nil && strings.TrimSpace(cfg.Provider) != "" {
+		configured = strings.TrimSpace(cfg.Provider)
+	}
+
+	resp := SandboxProvidersResponse{
+		ContractVersion: "sandbox-providers-v1",
+		Configured:      configured,
+	}
+	for _, name := range sandbox.BuiltinProviders() {
+		resp.Providers = append(resp.Providers, SandboxProviderEntry{Name: name, Kind: "builtin"})
+	}
+	for _, plugin := range sandboxDiscoverPlugins() {
+		entry := SandboxProviderEntry{Name: plugin.Name, Kind: "plugin", Path: plugin.Path}
+		ctx, cancel := context.WithTimeout(context.Background(), sandboxProvidersDescribeTimeout)
+		described, err := sandboxDescribePlugin(ctx, plugin)
+		cancel()
+		if err != nil {
+			entry.Error = err.Error()
+		} else {
+			entry.Protocol = sandbox.PluginProtocolVersion
+			entry.Description = described.Description
+			entry.Sizes = described.Sizes
+		}
+		resp.Providers = append(resp.Providers, entry)
+	}
+
+	if jsonMode {
+		data, err := json.MarshalIndent(resp, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal sandbox providers: %w", err)
+		}
+		fmt.Fprintln(out, string(data))
+		return nil
+	}
+
+	d := display.NewDisplay(out)
+	d.ShowCommandHeader("Sandbox Providers", fmt.Sprintf("configured: %s", configured), display.HeaderContext{})
+	for _, entry := range resp.Providers {
+		marker := "  "
+		if entry.Name == configured {
+			marker = display.StyleSuccess.Render("* ")
+		}
+		switch {
+		case entry.Kind == "builtin":
+			fmt.Fprintf(out, "%s%-14s %s\n", marker, entry.Name, display.StyleMuted.Render("built-in"))
+		case entry.Error != "":
+			fmt.Fprintf(out, "%s%-14s %s %s\n", marker, entry.Name, display.StyleError.Render("plugin error:"), entry.Error)
+		default:
+			detail := entry.Path
+			if entry.Description != "" {
+				detail = entry.Description + " (" + entry.Path + ")"
+			}
+			fmt.Fprintf(out, "%s%-14s %s\n", marker, entry.Name, detail)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jywlabs/hal/internal/sandbox"
+)
+
+func TestRunSandboxProviders_JSON(t *testing.T) {
+	setupListTest(t)
+	writeGlobalSandboxConfig(t, func(cfg *sandbox.GlobalConfig) {
+		cfg.Provider = "vultr"
+	})
+
+	origDiscover, origDescribe := sandboxDiscoverPlugins, sandboxDescribePlugin
+	t.Cleanup(func() { sandboxDiscoverPlugins, sandboxDescribePlugin = origDiscover, origDescribe })
+	sandboxDiscoverPlugins = func() []sandbox.PluginInfo {
+		return []sandbox.PluginInfo{
+			{Name: "broken", Path: "/bin/hal-provider-broken"},
+			{Name: "vultr", Path: "/bin/hal-provider-vultr"},
+		}
+	}
+	sandboxDescribePlugin = func(_ context.Context, plugin sandbox.PluginInfo) (*sandbox.PluginDescribeResult, error) {
+		if plugin.Name == "broken" {
+			return nil, errors.New(`broken plugin speaks protocol ""`)
+		}
+		return &sandbox.PluginDescribeResult{Name: "vultr", Description: "Vultr cloud compute", Sizes: []string{"vc2-1c-2gb"}}, nil
+	}
+
+	var buf bytes.Buffer
+	if err := runSandboxProviders(&buf, true); err != nil {
+		t.Fatalf("runSandboxProviders: %v", err)
+	}
+	var resp SandboxProvidersResponse
+	if err := json.Unmarshal(buf.Bytes(), &resp); err != nil {
+		t.Fatalf("json.Unmarshal: %v\n%s", err, buf.String())
+	}
+	if resp.ContractVersion != "sandbox-providers-v1" || resp.Configured != "vultr" {
+		t.Fatalf("resp = %+v", resp)
+	}
+	if len(resp.Providers) != 6 || resp.Providers[0].Kind != "builtin" {
+		t.Fatalf("providers = %+v, want 4 built-ins then 2 plugins", resp.Providers)
+	}
+	broken, vultr := resp.Providers[4], resp.Providers[5]
+	if broken.Error == "" || broken.Protocol != "" {
+		t.Errorf("broken plugin = %+v, want error without protocol", broken)
+	}
+	if vultr.Protocol != sandbox.PluginProtocolVersion || vultr.Description != "Vultr cloud compute" {
+		t.Errorf("vultr plugin = %+v", vultr)
+	}
+
+	buf.Reset()
+	if err := runSandboxProviders(&buf, false); err != nil {
+		t.Fatalf("runSandboxProviders: %v", err)
+	}
+	if !strings.Contains(buf.String(), "plugin error:") || !strings.Contains(buf.String(), "Vultr cloud compute") {
+		t.Errorf("unexpected human output:\n%s", buf.String())
+	}
+}
+
+func TestPluginCreateConfigs_SizeOverride(t *testing.T) {
+	global := sandbox.DefaultGlobalConfig()
+	global.Plugins = map[string]map[string]any{"vultr": {"region": "ewr", "size": "vc2-1c-1gb"}}
+
+	cfgs := pluginCreateConfigs(&global, "vultr", "vc2-2c-4gb")
+	if got := pluginConfiguredSize(cfgs["vultr"]); got != "vc2-2c-4gb" {
+		t.Fatalf("size = %q, want override", got)
+	}
+	if cfgs["vultr"]["region"] != "ewr" {
+		t.Fatalf("override should keep other settings, got %+v", cfgs["vultr"])
+	}
+	if global.Plugins["vultr"]["size"] != "vc2-1c-1gb" {
+		t.Fatalf("global config must not be mutated, got %+v", global.Plugins["vultr"])
+	}
+	if got := pluginConfiguredSize(pluginCreateConfigs(&global, "vultr", "")["vultr"]); got != "vc2-1c-1gb" {
+		t.Fatalf("size without override = %q, want configured size", got)
+	}
+}
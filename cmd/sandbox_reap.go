@@ -0,0 +1,398 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	display "github.com/jywlabs/hal/internal/engine"
+	"github.com/jywlabs/hal/internal/sandbox"
+	"github.com/jywlabs/hal/internal/template"
+	"github.com/spf13/cobra"
+)
+
+var sandboxReapCmd = &cobra.Command{
+	Use:   "reap",
+	Short: "Stop or delete idle sandboxes",
+	Long: `Stop or delete running sandboxes that have been idle longer than their policy.
+
+Each running sandbox created with auto-shutdown and an idle timeout
+(--auto-shutdown, --idle-hours) is probed over the provider's exec channel for
+recent activity: logged-in SSH sessions, tmux session activity, terminal
+input, running hal processes, and the 1-minute load average. A sandbox is idle
+when it has no sessions or hal processes, load is below 0.5, and nothing has
+happened for at least its idle hours. Idle sandboxes are then stopped (the
+default) or deleted with --action delete.
+
+Every check is recorded on the sandbox's registry entry (lastReap), and every
+stop, delete, or failure is appended to reap.log in the global hal config
+directory so deletions stay auditable. A lock file prevents overlapping runs,
+which makes the command safe to schedule from cron:
+
+  */15 * * * * hal sandbox reap >> ~/.config/hal/reap.out 2>&1
+
+Use --dry-run to report what would happen without touching any sandbox.
+Use --json for machine-readable output following the sandbox-reap-v1 contract.
+Exits non-zero when any probe, stop, or delete fails.`,
+	Args: noArgsValidation(),
+	Example: `  hal sandbox reap --dry-run
+  hal sandbox reap
+  hal sandbox reap --action delete --json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		jsonMode, _ := cmd.Flags().GetBool("json")
+		action, _ := cmd.Flags().GetString("action")
+		return runSandboxCobra(cmd, "Sandbox Reap failed", func() error {
+			return runSandboxReap(cmd.OutOrStdout(), cmd.ErrOrStderr(), action, dryRun, jsonMode)
+		})
+	},
+}
+
+func init() {
+	sandboxCmd.AddCommand(sandboxReapCmd)
+	sandboxReapCmd.Flags().Bool("dry-run", false, "Report idle sandboxes without stopping or deleting them")
+	sandboxReapCmd.Flags().Bool("json", false, "Output machine-readable JSON (sandbox-reap-v1 contract)")
+	sandboxReapCmd.Flags().String("action", sandbox.ReapActionStop, "What to do with idle sandboxes: stop or delete")
+}
+
+// sandboxReapProbeTimeout bounds how long a single activity probe may run so
+// one unreachable sandbox cannot stall a cron run.
+const sandboxReapProbeTimeout = 30 * time.Second
+
+// sandboxReapListInstances is injectable for testing and resolves only active
+// registry entries so staged delete backups are never reaped.
+var sandboxReapListInstances = sandbox.ListActiveInstances
+
+// sandboxReapNow is injectable for deterministic tests.
+var sandboxReapNow = func() time.Time { return time.Now() }
+
+// sandboxReapResolveProvider is injectable for testing provider resolution.
+var sandboxReapResolveProvider = func(providerName string) (sandbox.Provider, error) {
+	return resolveProviderWithFallback(".", providerName)
+}
+
+// sandboxReapProbe runs the idle probe inside a sandbox and returns its raw
+// output. Package-level var for test injection.
+var sandboxReapProbe = runSandboxReapProbe
+
+// sandboxReapWriteInstance is injectable for testing registry updates.
+var sandboxReapWriteInstance = sandbox.ForceWriteInstance
+
+// sandboxReapAppendLog is injectable for testing reap history writes.
+var sandboxReapAppendLog = sandbox.AppendReapLog
+
+// sandboxReapAcquireLock is injectable for testing lock contention.
+var sandboxReapAcquireLock = sandbox.AcquireReapLock
+
+// SandboxReapResponse is the machine-readable JSON output for hal sandbox reap --json.
+// Follows the sandbox-reap-v1 contract.
+type SandboxReapResponse struct {
+	ContractVersion string             `json:"contractVersion"`
+	CheckedAt       time.Time          `json:"checkedAt"`
+	DryRun          bool               `json:"dryRun"`
+	Action          string             `json:"action"`
+	Sandboxes       []SandboxReapEntry `json:"sandboxes"`
+	Summary         SandboxReapSummary `json:"summary"`
+}
+
+// SandboxReapEntry reports the idle check and outcome for one sandbox.
+type SandboxReapEntry struct {
+	Name           string     `json:"name"`
+	Provider       string     `json:"provider"`
+	IdleHours      int        `json:"idleHours,omitempty"`
+	Idle           bool       `json:"idle"`
+	IdleSeconds    *int64     `json:"idleSeconds,omitempty"`
+	LastActivityAt *time.Time `json:"lastActivityAt,omitempty"`
+	Action         string     `json:"action"`
+	Reason         string     `json:"reason,omitempty"`
+	Error          string     `json:"error,omitempty"`
+}
+
+// SandboxReapSummary counts reap outcomes.
+type SandboxReapSummary struct {
+	Checked int `json:"checked"`
+	Idle    int `json:"idle"`
+	Reaped  int `json:"reaped"`
+	Skipped int `json:"skipped"`
+	Failed  int `json:"failed"`
+}
+
+func runSandboxReap(out, errOut io.Writer, action string, dryRun, jsonMode bool) error {
+	action = strings.ToLower(strings.TrimSpace(action))
+	if action != sandbox.ReapActionStop && action != sandbox.ReapActionDelete {
+		return fmt.Errorf("invalid --action %q: must be stop or delete", action)
+	}
+
+	warnOut := out
+	if jsonMode {
+		// Keep machine-readable stdout clean when migration emits warnings.
+		warnOut = errOut
+	}
+	if err := runSandboxAutoMigrate(".", warnOut); err != nil {
+		return err
+	}
+
+	now := sandboxReapNow()
+	if !dryRun {
+		release, err := sandboxReapAcquireLock()
+		if err != nil {
+			return err
+		}
+		defer release()
+	}
+
+	instances, err := sandboxReapListInstances()
+	if err != nil {
+		return fmt.Errorf("listing sandboxes: %w", err)
+	}
+
+	redactor := sandboxRedactor(sandboxShowAddresses, nil, instances...)
+	safeOut := sandboxRedactingWriter(out, redactor)
+	defer sandboxFlushRedactor(safeOut)
+	renderOut := io.Writer(safeOut)
+	if renderOut == nil {
+		renderOut = out
+	}
+
+	resp := SandboxReapResponse{
+		ContractVersion: "sandbox-reap-v1",
+		CheckedAt:       now,
+		DryRun:          dryRun,
+		Action:          action,
+		Sandboxes:       []SandboxReapEntry{},
+	}
+
+	targets := filterRunning(instances)
+	sortTargetsByName(targets)
+	for _, target := range targets {
+		entry := reapOneTarget(target, action, dryRun, now, warnOut)
+		switch {
+		case entry.Action == sandbox.ReapActionFailed:
+			resp.Summary.Failed++
+		case entry.Action == sandbox.ReapActionSkipped:
+			resp.Summary.Skipped++
+		default:
+			resp.Summary.Checked++
+		}
+		if entry.Idle {
+			resp.Summary.Idle++
+		}
+		if !dryRun && (entry.Action == sandbox.ReapActionStop || entry.Action == sandbox.ReapActionDelete) {
+			resp.Summary.Reaped++
+		}
+		resp.Sandboxes = append(resp.Sandboxes, entry)
+	}
+
+	if jsonMode {
+		data, err := json.MarshalIndent(resp, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal sandbox reap report: %w", err)
+		}
+		fmt.Fprintln(renderOut, string(data))
+	} else {
+		renderSandboxReapReport(renderOut, resp)
+	}
+
+	if resp.Summary.Failed > 0 {
+		return sandboxSanitizeError(fmt.Errorf("%d/%d sandbox reaps failed", resp.Summary.Failed, len(targets)), redactor)
+	}
+	return nil
+}
+
+// reapOneTarget probes a running sandbox, applies its idle policy, and acts
+// on it. Registry and history writes are skipped in dry-run mode.
+func reapOneTarget(target *sandbox.SandboxState, action string, dryRun bool, now time.Time, warnOut io.Writer) SandboxReapEntry {
+	entry := SandboxReapEntry{
+		Name:      target.Name,
+		Provider:  target.Provider,
+		IdleHours: target.IdleHours,
+	}
+
+	if !target.AutoShutdown || target.IdleHours <= 0 {
+		entry.Action = sandbox.ReapActionSkipped
+		entry.Reason = "no idle policy (auto-shutdown disabled)"
+		return entry
+	}
+
+	record := &sandbox.ReapRecord{CheckedAt: now, DryRun: dryRun}
+	fail := func(err error) SandboxReapEntry {
+		entry.Action = sandbox.ReapActionFailed
+		entry.Error = err.Error()
+		record.Action = entry.Action
+		record.Reason = entry.Error
+		if !dryRun {
+			recordSandboxReap(target, record, false, warnOut)
+		}
+		return entry
+	}
+
+	p, err := sandboxReapResolveProvider(target.Provider)
+	if err != nil {
+		return fail(fmt.Errorf("resolving provider: %w", err))
+	}
+
+	info := sandbox.ConnectInfoFromState(target)
+	output, err := sandboxReapProbe(p, info)
+	if err != nil {
+		return fail(fmt.Errorf("activity probe: %w", err))
+	}
+	report, err := sandbox.ParseActivityReport(output)
+	if err != nil {
+		return fail(err)
+	}
+
+	decision := sandbox.EvaluateIdle(report, target.IdleHours, sandbox.DefaultIdleLoadThreshold)
+	entry.Idle = decision.Idle
+	entry.Reason = decision.Reason
+	if !report.LastActivity.IsZero() {
+		lastActivity := report.LastActivity
+		idleSeconds := int64(decision.IdleFor / time.Second)
+		entry.LastActivityAt = &lastActivity
+		entry.IdleSeconds = &idleSeconds
+		record.LastActivityAt = &lastActivity
+	}
+	record.Reason = decision.Reason
+
+	if !decision.Idle {
+		entry.Action = sandbox.ReapActionNone
+		record.Action = entry.Action
+		if !dryRun {
+			recordSandboxReap(target, record, false, warnOut)
+		}
+		return entry
+	}
+
+	entry.Action = action
+	record.Action = action
+	if dryRun {
+		return entry
+	}
+
+	switch action {
+	case sandbox.ReapActionDelete:
+		if err := deleteOneTarget(target, ".", io.Discard, p); err != nil {
+			return fail(err)
+		}
+		recordSandboxReap(target, record, true, warnOut)
+	default:
+		if err := p.Stop(context.Background(), info, io.Discard); err != nil {
+			return fail(fmt.Errorf("sandbox stop failed for %q: %w", target.Name, err))
+		}
+		applyResolvedWorkspaceID(target, info)
+		target.LastReap = record
+		if err := persistStoppedState(target); err != nil {
+			return fail(fmt.Errorf("persisting stopped state for %q: %w", target.Name, err))
+		}
+		if err := syncMatchingLocalSandboxState(filepath.Join(".", template.HalDir), target); err != nil {
+			fmt.Fprintf(warnOut, "warning: failed to sync local sandbox state for %q: %v\n", target.Name, err)
+		}
+		recordSandboxReap(target, record, true, warnOut)
+	}
+	return entry
+}
+
+// recordSandboxReap stores the reap record on the registry entry and, for
+// actions and failures, appends it to the reap history. Recording problems
+// are warnings: they must not mask the outcome of the reap itself.
+func recordSandboxReap(target *sandbox.SandboxState, record *sandbox.ReapRecord, persisted bool, warnOut io.Writer) {
+	if !persisted {
+		target.LastReap = record
+		if err := sandboxReapWriteInstance(target); err != nil {
+			fmt.Fprintf(warnOut, "warning: failed to record reap check for %q: %v\n", target.Name, err)
+		}
+	}
+	if record.Action == sandbox.ReapActionNone {
+		return
+	}
+	entry := sandbox.ReapLogEntry{Name: target.Name, Provider: target.Provider, ReapRecord: *record}
+	if err := sandboxReapAppendLog(entry); err != nil {
+		fmt.Fprintf(warnOut, "warning: failed to append reap history for %q: %v\n", target.Name, err)
+	}
+}
+
+// runSandboxReapProbe runs the idle probe through the provider's exec channel
+// and kills it after sandboxReapProbeTimeout.
+func runSandboxReapProbe(p sandbox.Provider, info *sandbox.ConnectInfo) (string, error) {
+	cmd, err := p.Exec(info, sandbox.IdleProbeArgs())
+	if err != nil {
+		return "", err
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdin = nil
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return "", err
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return "", fmt.Errorf("%w: %s", err, msg)
+			}
+			return "", err
+		}
+		return stdout.String(), nil
+	case <-time.After(sandboxReapProbeTimeout):
+		if cmd.Process != nil {
+			_ = cmd.Process.Signal(os.Kill)
+		}
+		<-done
+		return "", errors.New("timed out waiting for sandbox")
+	}
+}
+
+func renderSandboxReapReport(out io.Writer, resp SandboxReapResponse) {
+	title := "Sandbox Reap"
+	if resp.DryRun {
+		title = "Sandbox Reap (dry run)"
+	}
+	d := display.NewDisplay(out)
+	d.ShowCommandHeader(title, fmt.Sprintf("action: %s", resp.Action), display.HeaderContext{})
+
+	if len(resp.Sandboxes) == 0 {
+		fmt.Fprintln(out, "No running sandboxes.")
+		return
+	}
+
+	for _, entry := range resp.Sandboxes {
+		var marker, verb string
+		switch entry.Action {
+		case sandbox.ReapActionFailed:
+			marker, verb = display.StyleError.Render("[!!]"), "Failed"
+		case sandbox.ReapActionSkipped:
+			marker, verb = display.StyleMuted.Render("[--]"), "Skipped"
+		case sandbox.ReapActionNone:
+			marker, verb = display.StyleMuted.Render("[--]"), "Active"
+		case sandbox.ReapActionDelete:
+			marker, verb = display.StyleWarning.Render("[OK]"), "Deleted"
+			if resp.DryRun {
+				verb = "Would delete"
+			}
+		default:
+			marker, verb = display.StyleSuccess.Render("[OK]"), "Stopped"
+			if resp.DryRun {
+				verb = "Would stop"
+			}
+		}
+		detail := entry.Reason
+		if entry.Error != "" {
+			detail = entry.Error
+		}
+		fmt.Fprintf(out, "%s %s %s: %s\n", marker, verb, entry.Name, detail)
+	}
+
+	s := resp.Summary
+	fmt.Fprintf(out, "\n%d checked, %d idle, %d reaped, %d skipped, %d failed\n", s.Checked, s.Idle, s.Reaped, s.Skipped, s.Failed)
+}
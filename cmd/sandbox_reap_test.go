@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jywlabs/hal/internal/sandbox"
+)
+
+func setupReapTest(t *testing.T, provider sandbox.Provider, probe map[string]string, instances []*sandbox.SandboxState) time.Time {
+	t.Helper()
+	setupStopGlobalRegistry(t, instances)
+	t.Chdir(t.TempDir())
+
+	now := time.Unix(1_800_000_000, 0).UTC()
+	origNow, origStopNow := sandboxReapNow, sandboxStopNow
+	origResolve, origProbe := sandboxReapResolveProvider, sandboxReapProbe
+	sandboxReapNow = func() time.Time { return now }
+	sandboxStopNow = func() time.Time { return now }
+	sandboxReapResolveProvider = func(string) (sandbox.Provider, error) { return provider, nil }
+	sandboxReapProbe = func(_ sandbox.Provider, info *sandbox.ConnectInfo) (string, error) {
+		out, ok := probe[info.Name]
+		if !ok {
+			return "", errors.New("connection refused")
+		}
+		return out, nil
+	}
+	t.Cleanup(func() {
+		sandboxReapNow, sandboxStopNow = origNow, origStopNow
+		sandboxReapResolveProvider, sandboxReapProbe = origResolve, origProbe
+	})
+	return now
+}
+
+func reapProbeOutput(now time.Time, idleFor time.Duration, extra string) string {
+	return fmt.Sprintf("now=%d\nuptime=999999\ntty_activity=%d\n%s", now.Unix(), now.Add(-idleFor).Unix(), extra)
+}
+
+func TestRunSandboxReap_StopsIdleSandboxes(t *testing.T) {
+	provider := &mockStopProvider{}
+	now := time.Unix(1_800_000_000, 0).UTC()
+	setupReapTest(t, provider, map[string]string{
+		"idle":   reapProbeOutput(now, 5*time.Hour, ""),
+		"busy":   reapProbeOutput(now, 5*time.Hour, "hal_processes=1"),
+		"recent": reapProbeOutput(now, time.Hour, ""),
+	}, []*sandbox.SandboxState{
+		{Name: "idle", Provider: "hetzner", Status: sandbox.StatusRunning, CreatedAt: now, AutoShutdown: true, IdleHours: 4},
+		{Name: "busy", Provider: "hetzner", Status: sandbox.StatusRunning, CreatedAt: now, AutoShutdown: true, IdleHours: 4},
+		{Name: "recent", Provider: "hetzner", Status: sandbox.StatusRunning, CreatedAt: now, AutoShutdown: true, IdleHours: 4},
+		{Name: "pinned", Provider: "hetzner", Status: sandbox.StatusRunning, CreatedAt: now},
+		{Name: "off", Provider: "hetzner", Status: sandbox.StatusStopped, CreatedAt: now, AutoShutdown: true, IdleHours: 4},
+	})
+
+	var out bytes.Buffer
+	if err := runSandboxReap(&out, io.Discard, "stop", false, false); err != nil {
+		t.Fatalf("runSandboxReap: %v\n%s", err, out.String())
+	}
+
+	if calls := provider.sortedStopCalls(); len(calls) != 1 || calls[0] != "idle" {
+		t.Fatalf("stop calls = %v, want [idle]", calls)
+	}
+	if !strings.Contains(out.String(), "Stopped idle") || !strings.Contains(out.String(), "Skipped pinned") {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+
+	idle, err := sandbox.LoadInstance("idle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if idle.Status != sandbox.StatusStopped || idle.LastReap == nil || idle.LastReap.Action != sandbox.ReapActionStop {
+		t.Fatalf("idle registry = status %q lastReap %+v, want stopped by reaper", idle.Status, idle.LastReap)
+	}
+	busy, err := sandbox.LoadInstance("busy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if busy.Status != sandbox.StatusRunning || busy.LastReap == nil || busy.LastReap.Action != sandbox.ReapActionNone {
+		t.Fatalf("busy registry = status %q lastReap %+v, want running with recorded check", busy.Status, busy.LastReap)
+	}
+
+	history, err := os.ReadFile(sandbox.ReapLogPath())
+	if err != nil {
+		t.Fatalf("read reap log: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(history)), "\n"); len(lines) != 1 || !strings.Contains(lines[0], `"name":"idle"`) {
+		t.Fatalf("reap log = %s, want single entry for idle", history)
+	}
+}
+
+func TestRunSandboxReap_DryRunJSON(t *testing.T) {
+	provider := &mockStopProvider{}
+	now := time.Unix(1_800_000_000, 0).UTC()
+	setupReapTest(t, provider, map[string]string{
+		"idle": reapProbeOutput(now, 5*time.Hour, ""),
+	}, []*sandbox.SandboxState{
+		{Name: "idle", Provider: "hetzner", Status: sandbox.StatusRunning, CreatedAt: now, AutoShutdown: true, IdleHours: 4},
+	})
+
+	var out bytes.Buffer
+	if err := runSandboxReap(&out, io.Discard, "delete", true, true); err != nil {
+		t.Fatalf("runSandboxReap: %v", err)
+	}
+
+	var resp SandboxReapResponse
+	if err := json.Unmarshal(out.Bytes(), &resp); err != nil {
+		t.Fatalf("json.Unmarshal: %v\n%s", err, out.String())
+	}
+	if resp.ContractVersion != "sandbox-reap-v1" || !resp.DryRun || resp.Action != "delete" {
+		t.Fatalf("resp header = %+v", resp)
+	}
+	if len(resp.Sandboxes) != 1 || !resp.Sandboxes[0].Idle || resp.Sandboxes[0].Action != "delete" {
+		t.Fatalf("sandboxes = %+v, want idle would-delete", resp.Sandboxes)
+	}
+	if resp.Sandboxes[0].IdleSeconds == nil || *resp.Sandboxes[0].IdleSeconds != 5*3600 {
+		t.Errorf("idleSeconds = %v, want 18000", resp.Sandboxes[0].IdleSeconds)
+	}
+	if resp.Summary.Reaped != 0 || resp.Summary.Idle != 1 {
+		t.Errorf("summary = %+v, want idle 1 reaped 0", resp.Summary)
+	}
+
+	if calls := provider.sortedStopCalls(); len(calls) != 0 {
+		t.Fatalf("dry run must not stop sandboxes, got %v", calls)
+	}
+	inst, err := sandbox.LoadInstance("idle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inst.LastReap != nil {
+		t.Fatalf("dry run must not write the registry, got %+v", inst.LastReap)
+	}
+}
+
+func TestRunSandboxReap_ProbeFailureExitsNonZero(t *testing.T) {
+	now := time.Unix(1_800_000_000, 0).UTC()
+	setupReapTest(t, &mockStopProvider{}, map[string]string{}, []*sandbox.SandboxState{
+		{Name: "gone", Provider: "hetzner", Status: sandbox.StatusRunning, CreatedAt: now, AutoShutdown: true, IdleHours: 4},
+	})
+
+	var out bytes.Buffer
+	err := runSandboxReap(&out, io.Discard, "stop", false, false)
+	if err == nil || !strings.Contains(err.Error(), "1/1 sandbox reaps failed") {
+		t.Fatalf("runSandboxReap error = %v, want failure count", err)
+	}
+	if !strings.Contains(out.String(), "connection refused") {
+		t.Errorf("output should include probe error, got:\n%s", out.String())
+	}
+}
+
+func TestRunSandboxReap_Validation(t *testing.T) {
+	if err := runSandboxReap(io.Discard, io.Discard, "hibernate", false, false); err == nil {
+		t.Fatal("expected invalid --action error")
+	}
+
+	setupReapTest(t, &mockStopProvider{}, nil, nil)
+	release, err := sandbox.AcquireReapLock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+	if err := runSandboxReap(io.Discard, io.Discard, "stop", false, false); !errors.Is(err, sandbox.ErrReapLocked) {
+		t.Fatalf("runSandboxReap error = %v, want ErrReapLocked", err)
+	}
+}
@@ -0,0 +1,407 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	display "github.com/jywlabs/hal/internal/engine"
+	"github.com/jywlabs/hal/internal/sandbox"
+	"github.com/jywlabs/hal/internal/template"
+	"github.com/spf13/cobra"
+)
+
+var sandboxReconcileCmd = &cobra.Command{
+	Use:   "reconcile",
+	Short: "Compare the sandbox registry with what providers actually run",
+	Long: `Compare the local sandbox registry with the instances each provider reports.
+
+Providers are the configured provider plus every provider referenced by a
+registry entry (or only --provider when given). Each one is asked for its
+inventory and compared with the registry:
+
+  orphan  running at the provider with hal's label but unknown to hal
+          (for example after a create crashed halfway) — it is still billing
+  ghost   in the registry but gone at the provider (for example deleted
+          from the web console)
+  drift   in both, but the registry's IP, status, or size is stale
+
+hal labels the machines it creates (managed-by=hal on Hetzner and Lightsail,
+the "hal" tag on DigitalOcean, the hal snapshot on Daytona), so unrelated
+machines in the same account are not reported as orphans. Unlabeled machines
+named like hal sandboxes ("sandbox", or <name>-NN when <name> or another
+<name>-NN is registered) are reported as unlabeled orphans. Plugin providers
+take part when they implement the optional list method.
+
+Reconcile only reports by default. --adopt registers orphans and refreshes
+drifted registry fields from the provider; --prune removes ghost entries from
+the registry. Neither flag creates, stops, or deletes any provider resource.
+
+Use --json for machine-readable output following the sandbox-reconcile-v1
+contract. Exits non-zero when a provider cannot be listed or an adopt/prune
+write fails.`,
+	Args: noArgsValidation(),
+	Example: `  hal sandbox reconcile
+  hal sandbox reconcile --adopt --prune
+  hal sandbox reconcile --provider hetzner --json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		adopt, _ := cmd.Flags().GetBool("adopt")
+		prune, _ := cmd.Flags().GetBool("prune")
+		jsonMode, _ := cmd.Flags().GetBool("json")
+		providerName, _ := cmd.Flags().GetString("provider")
+		return runSandboxCobra(cmd, "Sandbox Reconcile failed", func() error {
+			return runSandboxReconcile(cmd.OutOrStdout(), cmd.ErrOrStderr(), providerName, adopt, prune, jsonMode)
+		})
+	},
+}
+
+func init() {
+	sandboxCmd.AddCommand(sandboxReconcileCmd)
+	sandboxReconcileCmd.Flags().Bool("adopt", false, "Register orphaned instances and refresh drifted registry fields")
+	sandboxReconcileCmd.Flags().Bool("prune", false, "Remove registry entries whose instance no longer exists")
+	sandboxReconcileCmd.Flags().String("provider", "", "Only reconcile this provider")
+	sandboxReconcileCmd.Flags().Bool("json", false, "Output machine-readable JSON (sandbox-reconcile-v1 contract)")
+}
+
+// sandboxReconcileListTimeout bounds each provider inventory call.
+const sandboxReconcileListTimeout = 60 * time.Second
+
+// Reconcile actions reported per finding.
+const (
+	reconcileActionAdopted = "adopted"
+	reconcileActionUpdated = "updated"
+	reconcileActionPruned  = "pruned"
+	reconcileActionSkipped = "skipped"
+	reconcileActionFailed  = "failed"
+)
+
+// sandboxReconcileListInstances is injectable for testing and resolves only
+// active registry entries so staged delete backups are never pruned.
+var sandboxReconcileListInstances = sandbox.ListActiveInstances
+
+// sandboxReconcileConfiguredProvider is injectable for testing provider discovery.
+var sandboxReconcileConfiguredProvider = func() (string, error) {
+	providerName, _, err := resolveProviderConfig(".")
+	return providerName, err
+}
+
+// sandboxReconcileResolveProvider is injectable for testing provider resolution.
+var sandboxReconcileResolveProvider = func(providerName string) (sandbox.Provider, error) {
+	return resolveProviderWithFallback(".", providerName)
+}
+
+// sandboxReconcileNow is injectable for deterministic tests.
+var sandboxReconcileNow = func() time.Time { return time.Now() }
+
+// SandboxReconcileResponse is the machine-readable JSON output for
+// hal sandbox reconcile --json. Follows the sandbox-reconcile-v1 contract.
+type SandboxReconcileResponse struct {
+	ContractVersion string                   `json:"contractVersion"`
+	CheckedAt       time.Time                `json:"checkedAt"`
+	Adopt           bool                     `json:"adopt"`
+	Prune           bool                     `json:"prune"`
+	Providers       []SandboxReconcileSource `json:"providers"`
+	Findings        []SandboxReconcileEntry  `json:"findings"`
+	Summary         SandboxReconcileSummary  `json:"summary"`
+}
+
+// SandboxReconcileSource reports the inventory call for one provider.
+type SandboxReconcileSource struct {
+	Name      string `json:"name"`
+	Instances int    `json:"instances"`
+	Error     string `json:"error,omitempty"`
+}
+
+// SandboxReconcileEntry is one finding and what reconcile did about it.
+type SandboxReconcileEntry struct {
+	sandbox.ReconcileFinding
+	Action string `json:"action,omitempty"`
+	Reason string `json:"reason,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// SandboxReconcileSummary counts findings and applied actions.
+type SandboxReconcileSummary struct {
+	InSync  int `json:"inSync"`
+	Orphans int `json:"orphans"`
+	Ghosts  int `json:"ghosts"`
+	Drifted int `json:"drifted"`
+	Adopted int `json:"adopted"`
+	Updated int `json:"updated"`
+	Pruned  int `json:"pruned"`
+	Failed  int `json:"failed"`
+}
+
+func runSandboxReconcile(out, errOut io.Writer, onlyProvider string, adopt, prune, jsonMode bool) error {
+	warnOut := out
+	if jsonMode {
+		// Keep machine-readable stdout clean when migration emits warnings.
+		warnOut = errOut
+	}
+	if err := runSandboxAutoMigrate(".", warnOut); err != nil {
+		return err
+	}
+
+	instances, err := sandboxReconcileListInstances()
+	if err != nil {
+		return fmt.Errorf("listing sandboxes: %w", err)
+	}
+	providers, err := reconcileProviderNames(instances, onlyProvider)
+	if err != nil {
+		return err
+	}
+
+	resp := SandboxReconcileResponse{
+		ContractVersion: "sandbox-reconcile-v1",
+		CheckedAt:       sandboxReconcileNow(),
+		Adopt:           adopt,
+		Prune:           prune,
+		Providers:       []SandboxReconcileSource{},
+		Findings:        []SandboxReconcileEntry{},
+	}
+
+	registered := make(map[string]bool, len(instances))
+	for _, inst := range instances {
+		registered[inst.Name] = true
+	}
+
+	redactor := sandboxRedactor(sandboxShowAddresses, nil, instances...)
+	for _, providerName := range providers {
+		source := SandboxReconcileSource{Name: providerName}
+		remote, err := listReconcileProvider(providerName)
+		if err != nil {
+			source.Error = err.Error()
+			resp.Providers = append(resp.Providers, source)
+			continue
+		}
+		source.Instances = len(remote)
+		resp.Providers = append(resp.Providers, source)
+		for _, inst := range remote {
+			redactor.KnownAddresses = append(redactor.KnownAddresses, inst.IP)
+		}
+
+		for _, finding := range sandbox.Reconcile(providerName, instances, remote) {
+			entry := applyReconcileFinding(finding, adopt, prune, registered, resp.CheckedAt, warnOut)
+			countReconcileEntry(&resp.Summary, entry)
+			resp.Findings = append(resp.Findings, entry)
+		}
+	}
+
+	safeOut := sandboxRedactingWriter(out, redactor)
+	defer sandboxFlushRedactor(safeOut)
+	renderOut := io.Writer(safeOut)
+	if renderOut == nil {
+		renderOut = out
+	}
+
+	if jsonMode {
+		data, err := json.MarshalIndent(resp, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal sandbox reconcile report: %w", err)
+		}
+		fmt.Fprintln(renderOut, string(data))
+	} else {
+		renderSandboxReconcileReport(renderOut, resp)
+	}
+
+	var failedProviders []string
+	for _, source := range resp.Providers {
+		if source.Error != "" {
+			failedProviders = append(failedProviders, source.Name)
+		}
+	}
+	switch {
+	case len(failedProviders) > 0:
+		return sandboxSanitizeError(fmt.Errorf("could not list %s", strings.Join(failedProviders, ", ")), redactor)
+	case resp.Summary.Failed > 0:
+		return sandboxSanitizeError(fmt.Errorf("%d reconcile updates failed", resp.Summary.Failed), redactor)
+	}
+	return nil
+}
+
+// reconcileProviderNames returns the providers to inventory: onlyProvider
+// when set, otherwise the configured provider plus every provider that has
+// registry entries.
+func reconcileProviderNames(instances []*sandbox.SandboxState, onlyProvider string) ([]string, error) {
+	if name := strings.TrimSpace(onlyProvider); name != "" {
+		return []string{name}, nil
+	}
+	configured, err := sandboxReconcileConfiguredProvider()
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	var names []string
+	add := func(name string) {
+		name = strings.TrimSpace(name)
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	add(configured)
+	for _, inst := range instances {
+		add(inst.Provider)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func listReconcileProvider(providerName string) ([]sandbox.RemoteInstance, error) {
+	p, err := sandboxReconcileResolveProvider(providerName)
+	if err != nil {
+		return nil, fmt.Errorf("resolving provider: %w", err)
+	}
+	lister, ok := p.(sandbox.Lister)
+	if !ok {
+		return nil, fmt.Errorf("provider %q cannot list instances", providerName)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), sandboxReconcileListTimeout)
+	defer cancel()
+	return lister.List(ctx)
+}
+
+// applyReconcileFinding performs the requested adopt/prune action for one
+// finding. registered tracks names already in the registry so an adopted
+// orphan never overwrites an entry for another provider.
+func applyReconcileFinding(finding sandbox.ReconcileFinding, adopt, prune bool, registered map[string]bool, now time.Time, warnOut io.Writer) SandboxReconcileEntry {
+	entry := SandboxReconcileEntry{ReconcileFinding: finding}
+	fail := func(err error) SandboxReconcileEntry {
+		entry.Action = reconcileActionFailed
+		entry.Error = err.Error()
+		return entry
+	}
+
+	switch {
+	case finding.Kind == sandbox.ReconcileOrphan && adopt:
+		if err := sandbox.ValidateName(finding.Name); err != nil {
+			entry.Action = reconcileActionSkipped
+			entry.Reason = fmt.Sprintf("name is not a valid sandbox name: %v", err)
+			return entry
+		}
+		if registered[finding.Name] {
+			entry.Action = reconcileActionSkipped
+			entry.Reason = "a registry entry with this name already exists"
+			return entry
+		}
+		state := sandbox.AdoptedState(finding.Provider, *finding.Remote, now)
+		if err := sandbox.SaveInstance(state); err != nil {
+			return fail(fmt.Errorf("adopting %q: %w", finding.Name, err))
+		}
+		registered[finding.Name] = true
+		entry.Action = reconcileActionAdopted
+
+	case finding.Kind == sandbox.ReconcileDrift && adopt:
+		updated := *finding.Registry
+		sandbox.ApplyDrift(&updated, finding.Drift)
+		if err := sandbox.ForceWriteInstance(&updated); err != nil {
+			return fail(fmt.Errorf("updating %q: %w", finding.Name, err))
+		}
+		if err := syncMatchingLocalSandboxState(filepath.Join(".", template.HalDir), &updated); err != nil {
+			fmt.Fprintf(warnOut, "warning: failed to sync local sandbox state for %q: %v\n", finding.Name, err)
+		}
+		entry.Action = reconcileActionUpdated
+
+	case finding.Kind == sandbox.ReconcileGhost && prune:
+		if err := sandbox.RemoveInstance(finding.Name); err != nil {
+			return fail(fmt.Errorf("pruning %q: %w", finding.Name, err))
+		}
+		if err := removeMatchingLocalSandboxState(filepath.Join(".", template.HalDir), finding.Registry); err != nil {
+			fmt.Fprintf(warnOut, "warning: failed to remove local sandbox state for %q: %v\n", finding.Name, err)
+		}
+		entry.Action = reconcileActionPruned
+	}
+	return entry
+}
+
+func countReconcileEntry(s *SandboxReconcileSummary, entry SandboxReconcileEntry) {
+	switch entry.Kind {
+	case sandbox.ReconcileInSync:
+		s.InSync++
+	case sandbox.ReconcileOrphan:
+		s.Orphans++
+	case sandbox.ReconcileGhost:
+		s.Ghosts++
+	case sandbox.ReconcileDrift:
+		s.Drifted++
+	}
+	switch entry.Action {
+	case reconcileActionAdopted:
+		s.Adopted++
+	case reconcileActionUpdated:
+		s.Updated++
+	case reconcileActionPruned:
+		s.Pruned++
+	case reconcileActionFailed:
+		s.Failed++
+	}
+}
+
+func renderSandboxReconcileReport(out io.Writer, resp SandboxReconcileResponse) {
+	names := make([]string, 0, len(resp.Providers))
+	for _, source := range resp.Providers {
+		names = append(names, source.Name)
+	}
+	d := display.NewDisplay(out)
+	d.ShowCommandHeader("Sandbox Reconcile", strings.Join(names, ", "), display.HeaderContext{})
+
+	for _, source := range resp.Providers {
+		if source.Error != "" {
+			fmt.Fprintf(out, "%s %s: %s\n", display.StyleError.Render("[!!]"), source.Name, source.Error)
+		}
+	}
+
+	for _, entry := range resp.Findings {
+		var marker, detail string
+		switch entry.Kind {
+		case sandbox.ReconcileInSync:
+			marker, detail = display.StyleSuccess.Render("[OK]"), "in sync"
+		case sandbox.ReconcileOrphan:
+			marker, detail = display.StyleWarning.Render("[!!]"), "orphan: running at provider but not in registry"
+			if entry.Unlabeled {
+				detail += " (unlabeled; matched by name)"
+			}
+		case sandbox.ReconcileGhost:
+			marker, detail = display.StyleWarning.Render("[!!]"), "ghost: in registry but gone at provider"
+		default:
+			parts := make([]string, 0, len(entry.Drift))
+			for _, drift := range entry.Drift {
+				parts = append(parts, fmt.Sprintf("%s %s → %s", drift.Field, valueOrDash(drift.Registry), valueOrDash(drift.Provider)))
+			}
+			marker, detail = display.StyleWarning.Render("[~~]"), "drift: "+strings.Join(parts, ", ")
+		}
+		switch {
+		case entry.Error != "":
+			detail += " " + display.StyleError.Render("("+entry.Error+")")
+		case entry.Action == reconcileActionSkipped:
+			detail += " " + display.StyleMuted.Render("(not adopted: "+entry.Reason+")")
+		case entry.Action != "":
+			detail += " " + display.StyleMuted.Render("("+entry.Action+")")
+		}
+		fmt.Fprintf(out, "%s %s (%s): %s\n", marker, entry.Name, entry.Provider, detail)
+	}
+	if len(resp.Findings) == 0 {
+		fmt.Fprintln(out, "No sandboxes found in the registry or at any provider.")
+	}
+
+	s := resp.Summary
+	fmt.Fprintf(out, "\n%d in sync, %d orphans, %d ghosts, %d drifted\n", s.InSync, s.Orphans, s.Ghosts, s.Drifted)
+	if s.Adopted+s.Updated+s.Pruned+s.Failed > 0 {
+		fmt.Fprintf(out, "%d adopted, %d updated, %d pruned, %d failed\n", s.Adopted, s.Updated, s.Pruned, s.Failed)
+	}
+	if (!resp.Adopt && s.Orphans+s.Drifted > 0) || (!resp.Prune && s.Ghosts > 0) {
+		fmt.Fprintln(out, display.StyleMuted.Render("Run with --adopt to register orphans and refresh drift, --prune to drop ghosts."))
+	}
+}
+
+func valueOrDash(v string) string {
+	if strings.TrimSpace(v) == "" {
+		return "-"
+	}
+	return v
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jywlabs/hal/internal/sandbox"
+)
+
+// mockReconcileProvider lists a fixed inventory; other Provider methods are
+// never called by reconcile.
+type mockReconcileProvider struct {
+	sandbox.Provider
+	instances []sandbox.RemoteInstance
+	err       error
+}
+
+func (m *mockReconcileProvider) List(context.Context) ([]sandbox.RemoteInstance, error) {
+	return m.instances, m.err
+}
+
+func setupReconcileProviders(t *testing.T, configured string, providers map[string]sandbox.Provider) {
+	t.Helper()
+	origConfigured, origResolve := sandboxReconcileConfiguredProvider, sandboxReconcileResolveProvider
+	t.Cleanup(func() {
+		sandboxReconcileConfiguredProvider, sandboxReconcileResolveProvider = origConfigured, origResolve
+	})
+	sandboxReconcileConfiguredProvider = func() (string, error) { return configured, nil }
+	sandboxReconcileResolveProvider = func(name string) (sandbox.Provider, error) {
+		if p, ok := providers[name]; ok {
+			return p, nil
+		}
+		return nil, errors.New("unknown provider")
+	}
+}
+
+func TestRunSandboxReconcile_ReportOnly(t *testing.T) {
+	setupListTest(t)
+	writeInstance(t, &sandbox.SandboxState{Name: "dev", Provider: "hetzner", IP: "203.0.113.1", Status: sandbox.StatusRunning})
+	writeInstance(t, &sandbox.SandboxState{Name: "gone", Provider: "hetzner", IP: "203.0.113.2", Status: sandbox.StatusRunning})
+	setupReconcileProviders(t, "hetzner", map[string]sandbox.Provider{
+		"hetzner": &mockReconcileProvider{instances: []sandbox.RemoteInstance{
+			{ID: "1", Name: "dev", IP: "198.51.100.1", Status: sandbox.StatusRunning, Managed: true},
+			{ID: "2", Name: "half-made", IP: "198.51.100.2", Status: sandbox.StatusRunning, Managed: true},
+		}},
+	})
+
+	var buf bytes.Buffer
+	if err := runSandboxReconcile(&buf, io.Discard, "", false, false, true); err != nil {
+		t.Fatalf("runSandboxReconcile: %v", err)
+	}
+	var resp SandboxReconcileResponse
+	if err := json.Unmarshal(buf.Bytes(), &resp); err != nil {
+		t.Fatalf("json.Unmarshal: %v\n%s", err, buf.String())
+	}
+	if resp.ContractVersion != "sandbox-reconcile-v1" || len(resp.Providers) != 1 || resp.Providers[0].Instances != 2 {
+		t.Fatalf("resp = %+v", resp)
+	}
+	if s := resp.Summary; s.Orphans != 1 || s.Ghosts != 1 || s.Drifted != 1 || s.Adopted+s.Pruned+s.Updated != 0 {
+		t.Fatalf("summary = %+v", s)
+	}
+	if strings.Contains(buf.String(), "198.51.100.1") {
+		t.Errorf("provider addresses should be redacted by default:\n%s", buf.String())
+	}
+
+	instances, err := sandbox.ListInstances()
+	if err != nil || len(instances) != 2 || instances[0].IP != "203.0.113.1" {
+		t.Fatalf("report-only run must not touch the registry, got %+v, %v", instances, err)
+	}
+}
+
+func TestRunSandboxReconcile_AdoptAndPrune(t *testing.T) {
+	setupListTest(t)
+	writeInstance(t, &sandbox.SandboxState{Name: "dev", Provider: "hetzner", IP: "203.0.113.1", Status: sandbox.StatusRunning})
+	writeInstance(t, &sandbox.SandboxState{Name: "gone", Provider: "hetzner", Status: sandbox.StatusRunning})
+	writeInstance(t, &sandbox.SandboxState{Name: "taken", Provider: "digitalocean", WorkspaceID: "9", Status: sandbox.StatusRunning})
+	setupReconcileProviders(t, "hetzner", map[string]sandbox.Provider{
+		"hetzner": &mockReconcileProvider{instances: []sandbox.RemoteInstance{
+			{ID: "1", Name: "dev", IP: "198.51.100.1", Status: sandbox.StatusStopped, Managed: true},
+			{ID: "2", Name: "half-made", IP: "198.51.100.2", Status: sandbox.StatusRunning, Size: "cx22", Managed: true},
+			{ID: "3", Name: "taken", Status: sandbox.StatusRunning, Managed: true},
+		}},
+		"digitalocean": &mockReconcileProvider{err: errors.New("doctl: unauthorized")},
+	})
+
+	var buf bytes.Buffer
+	err := runSandboxReconcile(&buf, io.Discard, "", true, true, false)
+	if err == nil || !strings.Contains(err.Error(), "could not list digitalocean") {
+		t.Fatalf("error = %v, want provider listing failure", err)
+	}
+	for _, want := range []string{"half-made (hetzner): orphan", "(adopted)", "(pruned)", "(updated)", "not adopted: a registry entry with this name already exists", "doctl: unauthorized"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, buf.String())
+		}
+	}
+
+	adopted, err := sandbox.LoadInstance("half-made")
+	if err != nil || adopted.Provider != "hetzner" || adopted.WorkspaceID != "2" || adopted.Size != "cx22" || adopted.ID == "" {
+		t.Fatalf("adopted = %+v, %v", adopted, err)
+	}
+	dev, err := sandbox.LoadInstance("dev")
+	if err != nil || dev.IP != "198.51.100.1" || dev.Status != sandbox.StatusStopped {
+		t.Fatalf("dev = %+v, %v, want drift applied", dev, err)
+	}
+	if _, err := sandbox.LoadInstance("gone"); err == nil {
+		t.Fatal("ghost entry should be pruned")
+	}
+	// Listing digitalocean failed, so its entry is never treated as a ghost.
+	if taken, err := sandbox.LoadInstance("taken"); err != nil || taken.Provider != "digitalocean" {
+		t.Fatalf("taken = %+v, %v, want untouched", taken, err)
+	}
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/jywlabs/hal/internal/sandbox"
@@ -66,7 +67,7 @@ func TestResolveProviderConfig_UsesDefaultsWhenGlobalPathUnavailableAndLegacyMis
 	if providerName != "daytona" {
 		t.Fatalf("providerName = %q, want %q", providerName, "daytona")
 	}
-	if provCfg != (sandbox.ProviderConfig{}) {
+	if !reflect.DeepEqual(provCfg, sandbox.ProviderConfig{}) {
 		t.Fatalf("ProviderConfig = %#v, want zero-value config", provCfg)
 	}
 }
@@ -285,9 +285,11 @@ func renderSandboxDetail(out io.Writer, inst *sandbox.SandboxState, liveErr, liv
 	}
 
 	// Cost
-	cost := sandbox.EstimatedCost(inst, func() time.Time { return now })
-	if cost >= 0 {
-		fmt.Fprintf(out, "  Est. cost:     %s\n", display.StyleWarning.Render(fmt.Sprintf("$%.2f", cost)))
+	rates, _ := loadSandboxCostSettings(nil)
+	if estimate := rates.Estimate(inst, now); estimate.Known {
+		fmt.Fprintf(out, "  Est. cost:     %s %s\n",
+			display.StyleWarning.Render(fmt.Sprintf("$%.2f", estimate.Accrued)),
+			display.StyleMuted.Render(fmt.Sprintf("(%s this month, %s projected)", formatCost(estimate.MonthToDate), formatCost(estimate.ProjectedMonthly))))
 	}
 
 	// Labels
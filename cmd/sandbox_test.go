@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 
@@ -687,6 +688,52 @@ func TestRunSandboxSetup_PreservesExistingTailscaleHostname(t *testing.T) {
 	}
 }
 
+func TestRunSandboxSetup_StoresNewSecretsInKeyring(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("OS keyring backends are only supported on macOS and Linux")
+	}
+	dir := t.TempDir()
+	setGlobalConfigHomeForTest(t, dir)
+
+	cfg := sandbox.DefaultGlobalConfig()
+	cfg.Env["GITHUB_TOKEN"] = "secret:cmd:gh auth token"
+	if err := sandbox.SaveGlobalConfig(&cfg); err != nil {
+		t.Fatalf("SaveGlobalConfig() error: %v", err)
+	}
+
+	stored := map[string]string{}
+	orig := sandboxSetupStoreSecret
+	t.Cleanup(func() { sandboxSetupStoreSecret = orig })
+	sandboxSetupStoreSecret = func(account, value string) error {
+		stored[account] = value
+		return nil
+	}
+
+	// Daytona, then a new Anthropic key; every other prompt keeps its value.
+	in := strings.NewReader("1\ndaytona-key\n\nsk-ant-new\n" + emptyEnvInputs[1:] + "y\n")
+	var out bytes.Buffer
+	if err := runSandboxSetupWithDeps(dir, in, &out, noopPasswordReader, fakeLookPath); err != nil {
+		t.Fatalf("runSandboxSetupWithDeps() error: %v", err)
+	}
+
+	if !strings.Contains(out.String(), "[secret:cmd:gh auth token]") {
+		t.Errorf("secret references should be shown unmasked as the current value, got: %q", out.String())
+	}
+	if len(stored) != 1 || stored["ANTHROPIC_API_KEY"] != "sk-ant-new" {
+		t.Fatalf("stored = %v, want only the new Anthropic key", stored)
+	}
+	updated, err := sandbox.LoadGlobalConfig()
+	if err != nil {
+		t.Fatalf("LoadGlobalConfig() error: %v", err)
+	}
+	if got := updated.Env["ANTHROPIC_API_KEY"]; got != "secret:keyring:hal/ANTHROPIC_API_KEY" {
+		t.Errorf("ANTHROPIC_API_KEY = %q, want keyring reference", got)
+	}
+	if got := updated.Env["GITHUB_TOKEN"]; got != "secret:cmd:gh auth token" {
+		t.Errorf("GITHUB_TOKEN = %q, want existing reference kept", got)
+	}
+}
+
 func TestRunSandboxSetup_PromptOutput_Daytona(t *testing.T) {
 	dir := t.TempDir()
 	setGlobalConfigHomeForTest(t, dir)
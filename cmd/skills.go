@@ -0,0 +1,348 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	display "github.com/jywlabs/hal/internal/engine"
+	"github.com/jywlabs/hal/internal/skills"
+	"github.com/jywlabs/hal/internal/standards"
+	"github.com/jywlabs/hal/internal/template"
+	"github.com/spf13/cobra"
+)
+
+var skillsCmd = &cobra.Command{
+	Use:   "skills",
+	Short: "Inspect and upgrade installed skills and commands",
+	Long: `Inspect and upgrade the skills and commands hal init installs into
+.hal/skills/ and .hal/commands/.
+
+hal records the version and content of every installed file in
+.hal/skills-lock.json. When a newer hal ships updated skills, 'hal skills
+upgrade' replaces unmodified files and three-way merges local edits with
+the new version, using the recorded content as the common base.
+
+File states:
+  current     Matches the version embedded in this hal
+  outdated    Unmodified, and this hal embeds a newer version
+  modified    Edited locally; the embedded version is unchanged
+  diverged    Edited locally, and this hal embeds a newer version
+  missing     Not installed
+  untracked   Differs from the embedded version, with no recorded install`,
+	Example: `  hal skills status
+  hal skills diff prd
+  hal skills upgrade
+  hal skills upgrade prd --theirs`,
+}
+
+var skillsStatusJSONFlag bool
+
+var skillsStatusCmd = &cobra.Command{
+	Use:   "status [name...]",
+	Short: "Show whether installed skills are up to date",
+	Long: `Compare installed skills and commands with the versions embedded in this
+hal and the versions they were installed from.
+
+Names select skills (prd, review, ...) or commands (index-standards, ...);
+all are shown when none is given.`,
+	Example: `  hal skills status
+  hal skills status prd review
+  hal skills status --json`,
+	RunE: runSkillsStatus,
+}
+
+var skillsDiffCmd = &cobra.Command{
+	Use:   "diff [name...]",
+	Short: "Show how installed skills differ from this hal's versions",
+	Long: `Show a line diff from each installed skill or command file to the version
+embedded in this hal. Files that are current are omitted.`,
+	Example: `  hal skills diff
+  hal skills diff prd`,
+	RunE: runSkillsDiff,
+}
+
+var (
+	skillsUpgradeTheirsFlag bool
+	skillsUpgradeOursFlag   bool
+	skillsUpgradeDryRunFlag bool
+	skillsUpgradeJSONFlag   bool
+)
+
+var skillsUpgradeCmd = &cobra.Command{
+	Use:   "upgrade [name...]",
+	Short: "Upgrade installed skills, merging local edits",
+	Long: `Bring installed skills and commands up to the versions embedded in this hal.
+
+Outdated files are replaced and missing files are installed. Diverged files
+are three-way merged: hunks changed only locally or only upstream are taken
+from that side, and hunks changed on both sides become conflicts, written
+with <<<<<<< / ||||||| / ======= / >>>>>>> markers to resolve by hand.
+--theirs or --ours resolves conflicts with hal's or the local side instead.
+
+Untracked files (installed before hal recorded versions, then edited) have
+no common base and are left alone unless --theirs replaces them or --ours
+keeps them and records the current version as their base.
+
+Exits with code 2 when conflict markers were written.`,
+	Example: `  hal skills upgrade
+  hal skills upgrade --dry-run
+  hal skills upgrade prd --theirs
+  hal skills upgrade --ours --json`,
+	RunE: runSkillsUpgrade,
+}
+
+func init() {
+	skillsStatusCmd.Flags().BoolVar(&skillsStatusJSONFlag, "json", false, "Output as JSON")
+	skillsCmd.AddCommand(skillsStatusCmd)
+	skillsCmd.AddCommand(skillsDiffCmd)
+	skillsUpgradeCmd.Flags().BoolVar(&skillsUpgradeTheirsFlag, "theirs", false, "Resolve conflicts with hal's version")
+	skillsUpgradeCmd.Flags().BoolVar(&skillsUpgradeOursFlag, "ours", false, "Resolve conflicts with the local version")
+	skillsUpgradeCmd.Flags().BoolVar(&skillsUpgradeDryRunFlag, "dry-run", false, "Show what would change without writing")
+	skillsUpgradeCmd.Flags().BoolVar(&skillsUpgradeJSONFlag, "json", false, "Output as JSON")
+	skillsUpgradeCmd.MarkFlagsMutuallyExclusive("theirs", "ours")
+	skillsCmd.AddCommand(skillsUpgradeCmd)
+	rootCmd.AddCommand(skillsCmd)
+}
+
+// SkillsStatusResult is the machine-readable output of hal skills status.
+type SkillsStatusResult struct {
+	ContractVersion int                 `json:"contractVersion"`
+	HalVersion      string              `json:"halVersion"`
+	Files           []skills.FileStatus `json:"files"`
+	Summary         string              `json:"summary"`
+}
+
+// SkillsUpgradeResult is the machine-readable output of hal skills upgrade.
+type SkillsUpgradeResult struct {
+	ContractVersion int                    `json:"contractVersion"`
+	OK              bool                   `json:"ok"`
+	DryRun          bool                   `json:"dryRun"`
+	Files           []skills.UpgradeResult `json:"files"`
+	Summary         string                 `json:"summary"`
+}
+
+func runSkillsStatus(cmd *cobra.Command, args []string) error {
+	out := io.Writer(os.Stdout)
+	if cmd != nil {
+		out = cmd.OutOrStdout()
+	}
+	return runSkillsStatusFn(cmd, ".", args, skillsStatusJSONFlag, out)
+}
+
+// loadSkillsStatus returns the status of the named skills in dir.
+func loadSkillsStatus(cmd *cobra.Command, dir string, names []string) ([]skills.FileStatus, error) {
+	if _, err := os.Stat(filepath.Join(dir, template.HalDir)); os.IsNotExist(err) {
+		return nil, fmt.Errorf(".hal/ not found - run 'hal init' first")
+	}
+	statuses, err := skills.Status(dir)
+	if err != nil {
+		return nil, err
+	}
+	statuses, err = skills.FilterStatus(statuses, names)
+	if err != nil {
+		return nil, exitWithCode(cmd, ExitCodeValidation, err)
+	}
+	return statuses, nil
+}
+
+func runSkillsStatusFn(cmd *cobra.Command, dir string, names []string, jsonMode bool, out io.Writer) error {
+	statuses, err := loadSkillsStatus(cmd, dir, names)
+	if err != nil {
+		return err
+	}
+
+	pending := 0
+	for _, s := range statuses {
+		if s.State != skills.StateCurrent && s.State != skills.StateModified {
+			pending++
+		}
+	}
+	summary := "All skills are up to date."
+	if pending > 0 {
+		summary = fmt.Sprintf("%d file(s) can be upgraded; run 'hal skills upgrade'.", pending)
+	}
+
+	if jsonMode {
+		result := SkillsStatusResult{
+			ContractVersion: 1,
+			HalVersion:      skills.HalVersion,
+			Files:           statuses,
+			Summary:         summary,
+		}
+		if result.Files == nil {
+			result.Files = []skills.FileStatus{}
+		}
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal skills status: %w", err)
+		}
+		fmt.Fprintln(out, string(data))
+		return nil
+	}
+
+	for _, s := range statuses {
+		version := ""
+		if s.InstalledVersion != "" {
+			version = display.StyleMuted.Render("(installed from " + s.InstalledVersion + ")")
+		}
+		fmt.Fprintf(out, "  %s %-10s %s %s\n", skillStateIcon(s.State), s.State, s.Path, version)
+	}
+	if len(statuses) > 0 {
+		fmt.Fprintln(out)
+	}
+	if pending > 0 {
+		fmt.Fprintf(out, "%s %s\n", display.StyleWarning.Render("[!]"), summary)
+	} else {
+		fmt.Fprintf(out, "%s %s\n", display.StyleSuccess.Render("✓"), summary)
+	}
+	return nil
+}
+
+func skillStateIcon(state string) string {
+	switch state {
+	case skills.StateCurrent:
+		return display.StyleSuccess.Render("✓")
+	case skills.StateModified:
+		return display.StyleInfo.Render("•")
+	default:
+		return display.StyleWarning.Render("!")
+	}
+}
+
+func runSkillsDiff(cmd *cobra.Command, args []string) error {
+	out := io.Writer(os.Stdout)
+	if cmd != nil {
+		out = cmd.OutOrStdout()
+	}
+	return runSkillsDiffFn(cmd, ".", args, out)
+}
+
+func runSkillsDiffFn(cmd *cobra.Command, dir string, names []string, out io.Writer) error {
+	statuses, err := loadSkillsStatus(cmd, dir, names)
+	if err != nil {
+		return err
+	}
+
+	shown := 0
+	for _, s := range statuses {
+		diff := standards.LineDiff(s.Path, s.Local, s.Embedded)
+		if diff == "" {
+			continue
+		}
+		if shown > 0 {
+			fmt.Fprintln(out)
+		}
+		shown++
+		fmt.Fprintf(out, "%s %s\n", display.StyleBold.Render(s.Path), display.StyleMuted.Render("("+s.State+")"))
+		fmt.Fprint(out, diff)
+	}
+	if shown == 0 {
+		fmt.Fprintf(out, "%s All skills match hal %s.\n", display.StyleSuccess.Render("✓"), skills.HalVersion)
+	}
+	return nil
+}
+
+func runSkillsUpgrade(cmd *cobra.Command, args []string) error {
+	out := io.Writer(os.Stdout)
+	if cmd != nil {
+		out = cmd.OutOrStdout()
+	}
+	opts := skills.UpgradeOptions{Names: args, DryRun: skillsUpgradeDryRunFlag}
+	switch {
+	case skillsUpgradeTheirsFlag:
+		opts.Prefer = skills.PreferTheirs
+	case skillsUpgradeOursFlag:
+		opts.Prefer = skills.PreferOurs
+	}
+	return runSkillsUpgradeFn(cmd, ".", opts, skillsUpgradeJSONFlag, out)
+}
+
+func runSkillsUpgradeFn(cmd *cobra.Command, dir string, opts skills.UpgradeOptions, jsonMode bool, out io.Writer) error {
+	if _, err := loadSkillsStatus(cmd, dir, opts.Names); err != nil {
+		return err
+	}
+	results, err := skills.Upgrade(dir, opts)
+	if err != nil {
+		return err
+	}
+
+	changed, conflicts, skipped := 0, 0, 0
+	for _, r := range results {
+		switch r.Action {
+		case skills.ActionInstalled, skills.ActionUpdated, skills.ActionMerged:
+			changed++
+		case skills.ActionConflict:
+			conflicts++
+		case skills.ActionSkipped:
+			skipped++
+		}
+	}
+	verb := "upgraded"
+	if opts.DryRun {
+		verb = "would be upgraded"
+	}
+	var parts []string
+	switch {
+	case changed == 0 && conflicts == 0:
+		parts = append(parts, "All skills are up to date")
+	case changed > 0:
+		parts = append(parts, fmt.Sprintf("%d file(s) %s", changed, verb))
+	}
+	if conflicts > 0 {
+		parts = append(parts, fmt.Sprintf("%d file(s) with conflicts to resolve", conflicts))
+	}
+	if skipped > 0 {
+		parts = append(parts, fmt.Sprintf("%d untracked file(s) skipped; use --theirs or --ours", skipped))
+	}
+	summary := strings.Join(parts, "; ") + "."
+
+	if jsonMode {
+		result := SkillsUpgradeResult{
+			ContractVersion: 1,
+			OK:              conflicts == 0,
+			DryRun:          opts.DryRun,
+			Files:           results,
+			Summary:         summary,
+		}
+		if result.Files == nil {
+			result.Files = []skills.UpgradeResult{}
+		}
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal skills upgrade: %w", err)
+		}
+		fmt.Fprintln(out, string(data))
+	} else {
+		for _, r := range results {
+			if r.Action == skills.ActionUnchanged {
+				continue
+			}
+			icon := display.StyleSuccess.Render("✓")
+			if r.Action == skills.ActionConflict || r.Action == skills.ActionSkipped {
+				icon = display.StyleWarning.Render("!")
+			}
+			detail := ""
+			if r.Conflicts > 0 {
+				detail = display.StyleMuted.Render(fmt.Sprintf("(%d conflict(s))", r.Conflicts))
+			}
+			fmt.Fprintf(out, "  %s %-9s %s %s\n", icon, r.Action, r.Path, detail)
+		}
+		if changed+conflicts+skipped > 0 {
+			fmt.Fprintln(out)
+		}
+		if conflicts > 0 || skipped > 0 {
+			fmt.Fprintf(out, "%s %s\n", display.StyleWarning.Render("[!]"), summary)
+		} else {
+			fmt.Fprintf(out, "%s %s\n", display.StyleSuccess.Render("✓"), summary)
+		}
+	}
+
+	if conflicts > 0 && !opts.DryRun {
+		return exitWithCode(cmd, ExitCodeValidation, fmt.Errorf("%d skill file(s) have conflict markers to resolve", conflicts))
+	}
+	return nil
+}
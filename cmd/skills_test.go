@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jywlabs/hal/internal/skills"
+	"github.com/jywlabs/hal/internal/template"
+)
+
+// setupSkillsDir installs skills and commands, then rewinds the prd skill to
+// an older release with the given local content.
+func setupSkillsDir(t *testing.T, base, local string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := skills.InstallSkills(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := skills.InstallCommands(dir); err != nil {
+		t.Fatal(err)
+	}
+	halDir := filepath.Join(dir, template.HalDir)
+	lock, err := skills.LoadLock(halDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lock.Files["skills/prd/SKILL.md"] = skills.LockEntry{Version: "v0.1.0", Base: base}
+	if err := skills.SaveLock(halDir, lock); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(halDir, "skills", "prd"), "SKILL.md", local)
+	return dir
+}
+
+func TestRunSkillsStatusFn(t *testing.T) {
+	dir := setupSkillsDir(t, "old prd\n", "old prd\n")
+
+	var out bytes.Buffer
+	if err := runSkillsStatusFn(nil, dir, []string{"prd"}, true, &out); err != nil {
+		t.Fatalf("runSkillsStatusFn() error: %v", err)
+	}
+	var result SkillsStatusResult
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	if result.ContractVersion != 1 {
+		t.Errorf("contractVersion = %d, want 1", result.ContractVersion)
+	}
+	states := map[string]string{}
+	for _, f := range result.Files {
+		states[f.Path] = f.State
+	}
+	if states["skills/prd/SKILL.md"] != skills.StateOutdated {
+		t.Errorf("prd state = %q, want outdated (files: %+v)", states["skills/prd/SKILL.md"], result.Files)
+	}
+	if _, ok := states["skills/review/SKILL.md"]; ok {
+		t.Error("status for prd should not list the review skill")
+	}
+	if !strings.Contains(result.Summary, "hal skills upgrade") {
+		t.Errorf("summary = %q, want upgrade hint", result.Summary)
+	}
+}
+
+func TestRunSkillsStatusFn_UnknownName(t *testing.T) {
+	dir := setupSkillsDir(t, "old prd\n", "old prd\n")
+	var out bytes.Buffer
+	err := runSkillsStatusFn(nil, dir, []string{"nope"}, false, &out)
+	assertExitCodeError(t, err, ExitCodeValidation, "unknown skill or command: nope")
+}
+
+func TestRunSkillsDiffFn(t *testing.T) {
+	dir := setupSkillsDir(t, "old prd\n", "old prd\n")
+	var out bytes.Buffer
+	if err := runSkillsDiffFn(nil, dir, nil, &out); err != nil {
+		t.Fatalf("runSkillsDiffFn() error: %v", err)
+	}
+	got := out.String()
+	for _, want := range []string{"skills/prd/SKILL.md", "(outdated)", "--- a/skills/prd/SKILL.md", "-old prd"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("diff output missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "skills/review/SKILL.md") {
+		t.Errorf("diff output should omit current files:\n%s", got)
+	}
+}
+
+func TestRunSkillsUpgradeFn(t *testing.T) {
+	embedded, err := skills.LoadSkill("prd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	skillPath := func(dir string) string {
+		return filepath.Join(dir, template.HalDir, "skills", "prd", "SKILL.md")
+	}
+
+	t.Run("outdated skill is replaced", func(t *testing.T) {
+		dir := setupSkillsDir(t, "old prd\n", "old prd\n")
+		var out bytes.Buffer
+		if err := runSkillsUpgradeFn(nil, dir, skills.UpgradeOptions{}, false, &out); err != nil {
+			t.Fatalf("runSkillsUpgradeFn() error: %v", err)
+		}
+		data, _ := os.ReadFile(skillPath(dir))
+		if string(data) != embedded {
+			t.Errorf("prd skill not upgraded:\n%s", data)
+		}
+		if !strings.Contains(out.String(), "1 file(s) upgraded.") {
+			t.Errorf("output = %q, want upgrade summary", out.String())
+		}
+	})
+
+	t.Run("conflict exits with validation code", func(t *testing.T) {
+		dir := setupSkillsDir(t, embedded+"old line\n", embedded+"my line\n")
+		var out bytes.Buffer
+		err := runSkillsUpgradeFn(nil, dir, skills.UpgradeOptions{}, true, &out)
+		assertExitCodeError(t, err, ExitCodeValidation, "1 skill file(s) have conflict markers to resolve")
+
+		var result SkillsUpgradeResult
+		if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+		}
+		if result.OK || result.ContractVersion != 1 {
+			t.Errorf("result = %+v, want ok false and contractVersion 1", result)
+		}
+		data, _ := os.ReadFile(skillPath(dir))
+		if !strings.Contains(string(data), "<<<<<<< local\nmy line\n") {
+			t.Errorf("conflict markers missing:\n%s", data)
+		}
+	})
+
+	t.Run("theirs resolves the conflict", func(t *testing.T) {
+		dir := setupSkillsDir(t, embedded+"old line\n", embedded+"my line\n")
+		var out bytes.Buffer
+		if err := runSkillsUpgradeFn(nil, dir, skills.UpgradeOptions{Prefer: skills.PreferTheirs}, false, &out); err != nil {
+			t.Fatalf("runSkillsUpgradeFn() error: %v", err)
+		}
+		data, _ := os.ReadFile(skillPath(dir))
+		if string(data) != embedded {
+			t.Errorf("--theirs content:\n%s", data)
+		}
+	})
+
+	t.Run("missing .hal", func(t *testing.T) {
+		var out bytes.Buffer
+		err := runSkillsUpgradeFn(nil, t.TempDir(), skills.UpgradeOptions{}, false, &out)
+		if err == nil || !strings.Contains(err.Error(), "hal init") {
+			t.Fatalf("error = %v, want hal init hint", err)
+		}
+	})
+}
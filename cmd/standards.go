@@ -2,12 +2,15 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
+	"github.com/jywlabs/hal/internal/compound"
 	display "github.com/jywlabs/hal/internal/engine"
 	"github.com/jywlabs/hal/internal/standards"
 	"github.com/jywlabs/hal/internal/template"
@@ -20,16 +23,23 @@ var standardsCmd = &cobra.Command{
 	Long: `Manage project-specific standards that guide AI agents during hal run.
 
 Standards are concise, codebase-specific rules stored in .hal/standards/.
-They are automatically injected into the agent prompt on every hal run iteration,
+Each hal run iteration injects the standards relevant to the current story,
 ensuring consistent code quality and pattern adherence.
 
 Use 'hal standards discover' to interactively extract standards from your codebase.
-Use 'hal standards list' to see what's currently configured.`,
+Use 'hal standards list' to see what's currently configured.
+Use 'hal standards check' to verify the branch diff against rules in standards.
+Use 'hal standards import' to share standards packs across repositories.`,
 	Example: `  hal standards list
-  hal standards discover`,
+  hal standards discover
+  hal standards check --base main
+  hal standards import https://github.com/org/standards.git@v1 --prefix org/`,
 }
 
-var standardsListJSONFlag bool
+var (
+	standardsListJSONFlag bool
+	standardsListForFlag  string
+)
 
 var standardsListCmd = &cobra.Command{
 	Use:   "list",
@@ -39,10 +49,29 @@ var standardsListCmd = &cobra.Command{
 
 Reads .hal/standards/index.yml and displays the catalog of standards
 organized by domain. If no index exists, lists the .md files found.
+Standards imported with 'hal standards import' are listed with the pack
+they came from and its revision.
+
+With --for, shows which standards hal run would inject for a story and why.
+Standards declare their scope in front matter or in their index.yml entry:
+
+  ---
+  applies_to: ["internal/engine/**", "*.sql"]
+  tags: [migration]
+  priority: 10
+  ---
 
-With --json, outputs standards count and index as JSON.`,
+Standards without applies_to or tags apply to every story. Scoped standards
+apply when a glob matches a path mentioned in the story, a tag appears in it,
+or their name and description share words with it. Higher priorities are
+injected first, within the standards.budget characters set in config.yaml.
+
+With --json, outputs standards count, index and imported packs as JSON, or
+the selection for --for.`,
 	Example: `  hal standards list
-  hal standards list --json`,
+  hal standards list --json
+  hal standards list --for US-003
+  hal standards list --for US-003 --json`,
 	RunE: runStandardsList,
 }
 
@@ -70,8 +99,116 @@ The discovery flow:
 	RunE:    runStandardsDiscover,
 }
 
+var (
+	standardsCheckBaseFlag string
+	standardsCheckJSONFlag bool
+)
+
+var standardsCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check the branch diff against machine-checkable standards",
+	Args:  noArgsValidation(),
+	Long: `Evaluate the checks: rules declared in .hal/standards/ front matter against
+the changes since the merge base with --base, including uncommitted and
+untracked files.
+
+Rule kinds:
+  forbid          Regex that added lines must not match
+  require         Regex every changed file must contain
+  forbid_import   Package that added lines must not import (Go, JS/TS, Python)
+  companion       File every new file needs, e.g. "{dir}/{name}_test.go"
+
+Each rule applies to its paths globs (minus exclude), else to the standard's
+applies_to globs, else to every file:
+
+  ---
+  checks:
+    - forbid: 'fmt\.Print'
+      paths: ["internal/**/*.go"]
+      message: write output through the display package
+    - companion: "{dir}/{name}_test.go"
+      paths: ["**/*.go"]
+      exclude: ["**/*_test.go", "**/main.go"]
+  ---
+
+The review loop in hal review and hal auto feeds violations to the engine as
+pre-validated issues, and the review gate fails while any remain.
+
+Exits with code 2 when violations are found.`,
+	Example: `  hal standards check
+  hal standards check --base develop
+  hal standards check --json`,
+	RunE: runStandardsCheck,
+}
+
+var (
+	standardsImportPrefixFlag string
+	standardsImportForceFlag  bool
+)
+
+var standardsImportCmd = &cobra.Command{
+	Use:   "import <git-url|path>[@ref]",
+	Short: "Import a standards pack from another repository",
+	Args:  exactArgsValidation(1),
+	Long: `Copy the standards of a git repository or directory into .hal/standards/
+and record the pack in .hal/standards/standards-lock.yml.
+
+The pack's standards are read from its .hal/standards/ directory, then its
+standards/ directory, then its root. An optional @ref pins a branch, tag or
+commit. --prefix imports into a folder so packs from different teams do not
+collide.
+
+Imported standards are read-only by convention: edit them in the pack's
+repository and run 'hal standards update'. Existing local files are never
+overwritten without --force.`,
+	Example: `  hal standards import https://github.com/org/standards.git
+  hal standards import git@github.com:org/standards.git@v1.2.0 --prefix org/
+  hal standards import ../shared-standards`,
+	RunE: runStandardsImport,
+}
+
+var (
+	standardsUpdateForceFlag  bool
+	standardsUpdateDryRunFlag bool
+	standardsUpdateJSONFlag   bool
+)
+
+var standardsUpdateCmd = &cobra.Command{
+	Use:   "update [pack...]",
+	Short: "Update imported standards packs to their latest revision",
+	Long: `Fetch the latest revision of imported standards packs, show what changed
+and apply it.
+
+Packs are selected by source, source@ref or prefix; all packs are updated
+when none is given. Packs pinned to a tag or commit stay on it; packs that
+follow a branch move to its tip.
+
+Imported standards that were edited locally since the last import or
+update and also changed upstream block their pack's update until --force
+discards the edits. Local edits to files upstream did not change are kept,
+even with --force.
+
+Exits with code 2 when local edits block an update.`,
+	Example: `  hal standards update
+  hal standards update org/ --dry-run
+  hal standards update https://github.com/org/standards.git --force
+  hal standards update --json`,
+	RunE: runStandardsUpdate,
+}
+
 func init() {
+	standardsImportCmd.Flags().StringVar(&standardsImportPrefixFlag, "prefix", "", "Folder under .hal/standards/ to import into, e.g. org/")
+	standardsImportCmd.Flags().BoolVar(&standardsImportForceFlag, "force", false, "Overwrite existing local standards")
+	standardsCmd.AddCommand(standardsImportCmd)
+	standardsUpdateCmd.Flags().BoolVar(&standardsUpdateForceFlag, "force", false, "Discard local edits to imported standards that changed upstream")
+	standardsUpdateCmd.Flags().BoolVar(&standardsUpdateDryRunFlag, "dry-run", false, "Show changes without applying them")
+	standardsUpdateCmd.Flags().BoolVar(&standardsUpdateJSONFlag, "json", false, "Output as JSON")
+	standardsCmd.AddCommand(standardsUpdateCmd)
+	standardsCheckCmd.Flags().StringVar(&standardsCheckBaseFlag, "base", "main", "Base branch to diff against")
+	standardsCheckCmd.Flags().BoolVar(&standardsCheckJSONFlag, "json", false, "Output as JSON")
+	standardsCmd.AddCommand(standardsCheckCmd)
 	standardsListCmd.Flags().BoolVar(&standardsListJSONFlag, "json", false, "Output as JSON")
+	standardsListCmd.Flags().StringVar(&standardsListForFlag, "for", "", "Show the standards injected for this story ID")
 	standardsCmd.AddCommand(standardsListCmd)
 	standardsCmd.AddCommand(standardsDiscoverCmd)
 	rootCmd.AddCommand(standardsCmd)
@@ -82,12 +219,107 @@ func runStandardsList(cmd *cobra.Command, args []string) error {
 	if cmd != nil {
 		out = cmd.OutOrStdout()
 	}
+	if standardsListForFlag != "" {
+		return runStandardsListFor(cmd, ".", standardsListForFlag, standardsListJSONFlag, out)
+	}
 	if standardsListJSONFlag {
 		return runStandardsListJSON(template.HalDir, out)
 	}
 	return runStandardsListFn(template.HalDir, out)
 }
 
+// StandardsSelectionResult is the machine-readable output of
+// hal standards list --for.
+type StandardsSelectionResult struct {
+	ContractVersion int                  `json:"contractVersion"`
+	StoryID         string               `json:"storyId"`
+	StoryTitle      string               `json:"storyTitle"`
+	PRDFile         string               `json:"prdFile"`
+	Budget          int                  `json:"budget"`
+	Size            int                  `json:"size"`
+	Included        []standards.Selected `json:"included"`
+	Excluded        []standards.Excluded `json:"excluded"`
+}
+
+// runStandardsListFor shows the standards hal run would inject for a story.
+func runStandardsListFor(cmd *cobra.Command, dir, storyID string, jsonMode bool, out io.Writer) error {
+	halDir := filepath.Join(dir, template.HalDir)
+
+	var story *display.UserStory
+	prdFile := ""
+	for _, name := range []string{template.PRDFile, template.AutoPRDFile} {
+		doc, err := display.LoadPRDFile(halDir, name)
+		if err != nil {
+			continue
+		}
+		if s := doc.FindStoryByID(storyID); s != nil {
+			story, prdFile = s, name
+			break
+		}
+	}
+	if story == nil {
+		return exitWithCode(cmd, ExitCodeValidation, fmt.Errorf("story not found: %s", storyID))
+	}
+
+	cfg, err := compound.LoadStandardsConfig(dir)
+	if err != nil {
+		return exitWithCode(cmd, ExitCodeValidation, fmt.Errorf("invalid standards config: %w", err))
+	}
+	all, err := standards.LoadAll(halDir)
+	if err != nil {
+		return err
+	}
+	sel := standards.Select(all, standards.StoryContext(story), cfg.Budget)
+
+	if jsonMode {
+		result := StandardsSelectionResult{
+			ContractVersion: 1,
+			StoryID:         story.ID,
+			StoryTitle:      story.Title,
+			PRDFile:         prdFile,
+			Budget:          sel.Budget,
+			Size:            sel.Size,
+			Included:        sel.Included,
+			Excluded:        sel.Excluded,
+		}
+		if result.Included == nil {
+			result.Included = []standards.Selected{}
+		}
+		if result.Excluded == nil {
+			result.Excluded = []standards.Excluded{}
+		}
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal standards: %w", err)
+		}
+		fmt.Fprintln(out, string(data))
+		return nil
+	}
+
+	fmt.Fprintf(out, "%s %s %s\n", display.StyleTitle.Render("Standards for"), display.StyleBold.Render(story.ID), story.Title)
+	budget := "unlimited"
+	if sel.Budget > 0 {
+		budget = fmt.Sprintf("%d", sel.Budget)
+	}
+	fmt.Fprintf(out, "%s\n\n", display.StyleMuted.Render(fmt.Sprintf("%d of %s characters", sel.Size, budget)))
+
+	if len(sel.Included) == 0 {
+		fmt.Fprintln(out, "  No standards would be injected.")
+	}
+	for _, s := range sel.Included {
+		fmt.Fprintf(out, "  %s %s\n", display.StyleSuccess.Render("✓"), s.Key)
+		fmt.Fprintf(out, "    %s\n", display.StyleMuted.Render(strings.Join(s.Reasons, "; ")))
+	}
+	if len(sel.Excluded) > 0 {
+		fmt.Fprintln(out)
+		for _, e := range sel.Excluded {
+			fmt.Fprintf(out, "  %s %s\n", display.StyleMuted.Render("–"), e.Key)
+			fmt.Fprintf(out, "    %s\n", display.StyleMuted.Render(e.Reason))
+		}
+	}
+	return nil
+}
+
 func runStandardsListJSON(halDir string, out io.Writer) error {
 	count, _ := standards.Count(halDir)
 	index, _ := standards.ListIndex(halDir)
@@ -98,6 +330,9 @@ func runStandardsListJSON(halDir string, out io.Writer) error {
 	if index != "" {
 		result["index"] = index
 	}
+	if lock, err := standards.LoadLock(halDir); err == nil && len(lock.Packs) > 0 {
+		result["packs"] = lock.Packs
+	}
 
 	data, err := json.MarshalIndent(result, "", "  ")
 	if err != nil {
@@ -169,8 +404,236 @@ func runStandardsListFn(halDir string, w io.Writer) error {
 		}
 	}
 
+	lock, err := standards.LoadLock(halDir)
+	if err != nil {
+		return err
+	}
+	if len(lock.Packs) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "%s\n", display.StyleTitle.Render("Imported (read-only):"))
+		for _, p := range lock.Packs {
+			fmt.Fprintf(w, "  %s %s\n", display.StyleBold.Render(p.Name()), display.StyleMuted.Render("@ "+p.ShortRevision()))
+			for _, path := range sortedStandardPaths(p.Files) {
+				fmt.Fprintf(w, "    %s\n", path)
+			}
+		}
+	}
+
 	fmt.Fprintln(w)
-	fmt.Fprintf(w, "%s\n", display.StyleMuted.Render("Standards are injected into every 'hal run' iteration automatically."))
+	fmt.Fprintf(w, "%s\n", display.StyleMuted.Render("Relevant standards are injected into every 'hal run' iteration. See 'hal standards list --for <story-id>'."))
+	return nil
+}
+
+// StandardsCheckResult is the machine-readable output of hal standards check.
+type StandardsCheckResult struct {
+	ContractVersion int                   `json:"contractVersion"`
+	Base            string                `json:"base"`
+	OK              bool                  `json:"ok"`
+	Violations      []standards.Violation `json:"violations"`
+	Summary         string                `json:"summary"`
+}
+
+func runStandardsCheck(cmd *cobra.Command, args []string) error {
+	out := io.Writer(os.Stdout)
+	if cmd != nil {
+		out = cmd.OutOrStdout()
+	}
+	return runStandardsCheckFn(cmd, ".", standardsCheckBaseFlag, standardsCheckJSONFlag, out)
+}
+
+func runStandardsCheckFn(cmd *cobra.Command, dir, base string, jsonMode bool, out io.Writer) error {
+	base = strings.TrimSpace(base)
+	if base == "" {
+		return exitWithCode(cmd, ExitCodeValidation, fmt.Errorf("--base must not be empty"))
+	}
+
+	violations, err := standards.CheckBranch(dir, base)
+	if err != nil {
+		return err
+	}
+
+	summary := "No standards violations."
+	if len(violations) > 0 {
+		summary = fmt.Sprintf("%d standards violation(s).", len(violations))
+	}
+
+	if jsonMode {
+		result := StandardsCheckResult{
+			ContractVersion: 1,
+			Base:            base,
+			OK:              len(violations) == 0,
+			Violations:      violations,
+			Summary:         summary,
+		}
+		if result.Violations == nil {
+			result.Violations = []standards.Violation{}
+		}
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal standards check: %w", err)
+		}
+		fmt.Fprintln(out, string(data))
+	} else {
+		for _, v := range violations {
+			fmt.Fprintf(out, "%s %s\n", display.StyleError.Render("✗"), v.String())
+		}
+		if len(violations) > 0 {
+			fmt.Fprintln(out)
+			fmt.Fprintln(out, summary)
+		} else {
+			fmt.Fprintf(out, "%s %s\n", display.StyleSuccess.Render("✓"), summary)
+		}
+	}
+
+	if len(violations) > 0 {
+		return exitWithCode(cmd, ExitCodeValidation, fmt.Errorf("%s", strings.ToLower(strings.TrimSuffix(summary, "."))))
+	}
+	return nil
+}
+
+func sortedStandardPaths(files map[string]string) []string {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func runStandardsImport(cmd *cobra.Command, args []string) error {
+	out := io.Writer(os.Stdout)
+	if cmd != nil {
+		out = cmd.OutOrStdout()
+	}
+	return runStandardsImportFn(cmd, template.HalDir, args[0], standards.ImportOptions{
+		Prefix: standardsImportPrefixFlag,
+		Force:  standardsImportForceFlag,
+	}, out)
+}
+
+func runStandardsImportFn(cmd *cobra.Command, halDir, arg string, opts standards.ImportOptions, out io.Writer) error {
+	if _, err := os.Stat(halDir); os.IsNotExist(err) {
+		return fmt.Errorf(".hal/ not found - run 'hal init' first")
+	}
+
+	pack, err := standards.Import(halDir, arg, opts)
+	var conflict *standards.ConflictError
+	if errors.As(err, &conflict) {
+		return exitWithCode(cmd, ExitCodeValidation, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "%s Imported %d standard(s) from %s %s\n",
+		display.StyleSuccess.Render("✓"), len(pack.Files), pack.Name(), display.StyleMuted.Render("@ "+pack.ShortRevision()))
+	for _, path := range sortedStandardPaths(pack.Files) {
+		fmt.Fprintf(out, "  %s\n", path)
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "%s\n", display.StyleMuted.Render("Imported standards are read-only; run 'hal standards update' to pull newer revisions."))
+	return nil
+}
+
+// StandardsUpdateResult is the machine-readable output of
+// hal standards update.
+type StandardsUpdateResult struct {
+	ContractVersion int                      `json:"contractVersion"`
+	OK              bool                     `json:"ok"`
+	DryRun          bool                     `json:"dryRun"`
+	Packs           []standards.UpdateResult `json:"packs"`
+	Error           string                   `json:"error,omitempty"`
+	Summary         string                   `json:"summary"`
+}
+
+func runStandardsUpdate(cmd *cobra.Command, args []string) error {
+	out := io.Writer(os.Stdout)
+	if cmd != nil {
+		out = cmd.OutOrStdout()
+	}
+	return runStandardsUpdateFn(cmd, template.HalDir, args, standards.UpdateOptions{
+		Force:  standardsUpdateForceFlag,
+		DryRun: standardsUpdateDryRunFlag,
+	}, standardsUpdateJSONFlag, out)
+}
+
+func runStandardsUpdateFn(cmd *cobra.Command, halDir string, selectors []string, opts standards.UpdateOptions, jsonMode bool, out io.Writer) error {
+	results, err := standards.Update(halDir, selectors, opts)
+	var conflict *standards.ConflictError
+	if err != nil && !errors.As(err, &conflict) {
+		return err
+	}
+
+	changed := 0
+	for _, r := range results {
+		if !r.UpToDate() && (r.Applied || opts.DryRun && (len(r.LocalEdits) == 0 || opts.Force)) {
+			changed++
+		}
+	}
+	summary := "All standards packs are up to date."
+	switch {
+	case conflict != nil:
+		summary = fmt.Sprintf("%d imported standard(s) edited locally and changed upstream; use --force to discard the edits.", len(conflict.Paths))
+	case changed > 0 && opts.DryRun:
+		summary = fmt.Sprintf("%d pack(s) would be updated.", changed)
+	case changed > 0:
+		summary = fmt.Sprintf("%d pack(s) updated.", changed)
+	}
+
+	if jsonMode {
+		result := StandardsUpdateResult{
+			ContractVersion: 1,
+			OK:              conflict == nil,
+			DryRun:          opts.DryRun,
+			Packs:           results,
+			Summary:         summary,
+		}
+		if result.Packs == nil {
+			result.Packs = []standards.UpdateResult{}
+		}
+		if conflict != nil {
+			result.Error = conflict.Error()
+		}
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal standards update: %w", err)
+		}
+		fmt.Fprintln(out, string(data))
+	} else {
+		for _, r := range results {
+			revision := r.Pack.ShortRevision()
+			if r.OldRevision != r.Pack.Revision {
+				revision = (standards.Pack{Revision: r.OldRevision}).ShortRevision() + " → " + revision
+			}
+			fmt.Fprintf(out, "%s %s\n", display.StyleBold.Render(r.Pack.Name()), display.StyleMuted.Render(revision))
+			if r.UpToDate() {
+				fmt.Fprintf(out, "  %s\n", display.StyleMuted.Render("up to date"))
+			}
+			for _, c := range r.Changes {
+				fmt.Fprintf(out, "  %s %s\n", c.Kind, c.Path)
+				if c.Diff != "" {
+					for _, line := range strings.Split(strings.TrimSuffix(c.Diff, "\n"), "\n") {
+						fmt.Fprintf(out, "    %s\n", line)
+					}
+				}
+			}
+			for _, path := range r.LocalEdits {
+				fmt.Fprintf(out, "  %s %s\n", display.StyleWarning.Render("edited locally:"), path)
+			}
+		}
+		if len(results) > 0 {
+			fmt.Fprintln(out)
+		}
+		if conflict != nil {
+			fmt.Fprintf(out, "%s %s\n", display.StyleWarning.Render("[!]"), summary)
+		} else {
+			fmt.Fprintf(out, "%s %s\n", display.StyleSuccess.Render("✓"), summary)
+		}
+	}
+
+	if conflict != nil {
+		return exitWithCode(cmd, ExitCodeValidation, err)
+	}
 	return nil
 }
 
@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jywlabs/hal/internal/standards"
+	"github.com/jywlabs/hal/internal/template"
+)
+
+func setupStandardsForDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	halDir := filepath.Join(dir, template.HalDir)
+	files := map[string]string{
+		template.PRDFile: `{"branchName":"hal/demo","userStories":[
+  {"id":"US-001","title":"Retry","description":"Add retries in internal/engine/claude.go","priority":1},
+  {"id":"US-002","title":"Docs","description":"Document the README","priority":2}]}`,
+		"standards/global/naming.md":  "Use camelCase.",
+		"standards/engine/adapter.md": "---\napplies_to: [\"internal/engine/**\"]\n---\nEngines self-register.",
+	}
+	for name, content := range files {
+		path := filepath.Join(halDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestRunStandardsListFor_JSON(t *testing.T) {
+	dir := setupStandardsForDir(t)
+
+	var out bytes.Buffer
+	if err := runStandardsListFor(nil, dir, "US-002", true, &out); err != nil {
+		t.Fatalf("runStandardsListFor() error: %v", err)
+	}
+	var result StandardsSelectionResult
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	if result.ContractVersion != 1 || result.StoryID != "US-002" || result.PRDFile != template.PRDFile {
+		t.Fatalf("result = %+v", result)
+	}
+	if len(result.Included) != 1 || result.Included[0].Key != "global/naming" {
+		t.Fatalf("included = %+v, want only global/naming", result.Included)
+	}
+	if len(result.Excluded) != 1 || result.Excluded[0].Key != "engine/adapter" || result.Excluded[0].Reason == "" {
+		t.Fatalf("excluded = %+v, want engine/adapter with a reason", result.Excluded)
+	}
+}
+
+func TestRunStandardsListFor_Human(t *testing.T) {
+	dir := setupStandardsForDir(t)
+
+	var out bytes.Buffer
+	if err := runStandardsListFor(nil, dir, "US-001", false, &out); err != nil {
+		t.Fatalf("runStandardsListFor() error: %v", err)
+	}
+	for _, want := range []string{"US-001", "engine/adapter", "applies_to internal/engine/** matches internal/engine/claude.go", "global/naming"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestRunStandardsListFor_UnknownStory(t *testing.T) {
+	dir := setupStandardsForDir(t)
+
+	err := runStandardsListFor(nil, dir, "US-009", false, &bytes.Buffer{})
+	var exitErr *ExitCodeError
+	if !errors.As(err, &exitErr) || exitErr.Code != ExitCodeValidation {
+		t.Fatalf("err = %v, want validation error", err)
+	}
+}
+
+func TestRunStandardsCheckFn(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+
+	dir := t.TempDir()
+	runGit := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, string(out))
+		}
+	}
+	writeFile := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	runGit("init", "-b", "main")
+	runGit("config", "user.name", "tester")
+	runGit("config", "user.email", "tester@example.com")
+	runGit("config", "commit.gpgsign", "false")
+	writeFile(".hal/standards/go/output.md", "---\nchecks:\n  - forbid: 'fmt\\.Println'\n    paths: [\"**/*.go\"]\n---\nWrite output through the display package.")
+	writeFile("main.go", "package main\n")
+	runGit("add", "-A")
+	runGit("commit", "-m", "init")
+	runGit("checkout", "-b", "feature")
+	writeFile("main.go", "package main\n\nfunc main() { fmt.Println(\"hi\") }\n")
+	runGit("commit", "-am", "print")
+
+	var out bytes.Buffer
+	err := runStandardsCheckFn(nil, dir, "main", true, &out)
+	var exitErr *ExitCodeError
+	if !errors.As(err, &exitErr) || exitErr.Code != ExitCodeValidation {
+		t.Fatalf("err = %v, want validation exit for violations", err)
+	}
+	var result StandardsCheckResult
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	if result.ContractVersion != 1 || result.OK || len(result.Violations) != 1 {
+		t.Fatalf("result = %+v, want one violation", result)
+	}
+	if v := result.Violations[0]; v.File != "main.go" || v.Line != 3 || v.Standard != "go/output" {
+		t.Fatalf("violation = %+v", v)
+	}
+
+	writeFile("main.go", "package main\n\nfunc main() {}\n")
+	out.Reset()
+	if err := runStandardsCheckFn(nil, dir, "main", false, &out); err != nil {
+		t.Fatalf("clean tree err = %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "No standards violations") {
+		t.Fatalf("output = %q", out.String())
+	}
+}
+
+func TestRunStandardsImportAndUpdate(t *testing.T) {
+	pack := t.TempDir()
+	packFile := filepath.Join(pack, "go", "errors.md")
+	if err := os.MkdirAll(filepath.Dir(packFile), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(packFile, []byte("Wrap errors with %w.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	halDir := filepath.Join(t.TempDir(), template.HalDir)
+	if err := os.MkdirAll(halDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := runStandardsImportFn(nil, halDir, pack, standards.ImportOptions{Prefix: "org/"}, &out); err != nil {
+		t.Fatalf("runStandardsImportFn() error: %v", err)
+	}
+	if !strings.Contains(out.String(), "org/go/errors.md") {
+		t.Fatalf("import output = %q", out.String())
+	}
+
+	out.Reset()
+	if err := runStandardsListFn(halDir, &out); err != nil {
+		t.Fatalf("runStandardsListFn() error: %v", err)
+	}
+	for _, want := range []string{"Imported (read-only)", pack, "org/go/errors.md"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("list output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	if err := os.WriteFile(packFile, []byte("Wrap errors with %w.\nNever panic.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	localFile := filepath.Join(halDir, template.StandardsDir, "org", "go", "errors.md")
+	if err := os.WriteFile(localFile, []byte("Local tweak.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out.Reset()
+	err := runStandardsUpdateFn(nil, halDir, nil, standards.UpdateOptions{}, true, &out)
+	var exitErr *ExitCodeError
+	if !errors.As(err, &exitErr) || exitErr.Code != ExitCodeValidation {
+		t.Fatalf("err = %v, want validation exit for local edits", err)
+	}
+	var result StandardsUpdateResult
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	if result.ContractVersion != 1 || result.OK || len(result.Packs) != 1 || len(result.Packs[0].LocalEdits) != 1 {
+		t.Fatalf("result = %+v, want blocked update", result)
+	}
+
+	out.Reset()
+	if err := runStandardsUpdateFn(nil, halDir, nil, standards.UpdateOptions{Force: true}, false, &out); err != nil {
+		t.Fatalf("forced update err = %v\n%s", err, out.String())
+	}
+	for _, want := range []string{"modified org/go/errors.md", "+Never panic.", "1 pack(s) updated"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("update output missing %q:\n%s", want, out.String())
+		}
+	}
+	if data, _ := os.ReadFile(localFile); string(data) != "Wrap errors with %w.\nNever panic.\n" {
+		t.Fatalf("local file = %q", data)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	"github.com/jywlabs/hal/internal/compound"
 	display "github.com/jywlabs/hal/internal/engine"
@@ -154,6 +155,32 @@ func runStatusFn(dir string, jsonMode bool, out io.Writer) error {
 		fmt.Fprintln(out)
 	}
 
+	// Show the live run and pending hal ctl requests
+	if run := result.Run; run != nil && run.Controllable {
+		label := fmt.Sprintf("%s, iteration %d/%d", run.State, run.Iteration, run.MaxIterations)
+		if run.StoryID != "" {
+			label += ", " + run.StoryID
+		}
+		fmt.Fprintf(out, "Run:      %s\n", display.StyleInfo.Render(label))
+		var pending []string
+		if run.StopAfterCurrent {
+			pending = append(pending, "stop after current")
+		}
+		for _, id := range run.PendingSkips {
+			pending = append(pending, "skip "+id)
+		}
+		for _, m := range run.PendingMoves {
+			pending = append(pending, "reprioritize "+m.StoryID)
+		}
+		if len(pending) > 0 {
+			fmt.Fprintf(out, "Control:  %s\n", display.StyleWarning.Render(strings.Join(pending, ", ")))
+		}
+		fmt.Fprintln(out)
+	} else if run != nil && run.StaleReason != "" {
+		fmt.Fprintf(out, "Run:      %s\n", display.StyleWarning.Render(run.State+" (stale: "+run.StaleReason+"; hal ctl is unavailable)"))
+		fmt.Fprintln(out)
+	}
+
 	fmt.Fprintf(out, "%s\n", display.StyleBold.Render("Artifacts:"))
 	printArtifact(out, "  .hal/ directory", result.Artifacts.HalDir)
 	printArtifact(out, "  Markdown PRD", result.Artifacts.MarkdownPRD)
@@ -30,6 +30,9 @@ Checks:
   - UI stories have browser verification criteria
   - Acceptance criteria are verifiable (not vague)
 
+If the PRD was planned from a template (template in prd.json), the
+template's required stories and acceptance criteria are checked too.
+
 Examples:
   hal validate                    # Validate .hal/prd.json
   hal validate path/to/prd.json   # Validate specific file
@@ -44,7 +47,7 @@ Examples:
 }
 
 func init() {
-	validateCmd.Flags().StringVarP(&validateEngineFlag, "engine", "e", "codex", "Engine to use (claude, codex, pi)")
+	validateCmd.Flags().StringVarP(&validateEngineFlag, "engine", "e", "codex", "Engine to use (claude, codex, gemini, pi)")
 	validateCmd.Flags().BoolVar(&validateJSONFlag, "json", false, "Output machine-readable JSON result")
 	rootCmd.AddCommand(validateCmd)
 }
@@ -93,6 +96,9 @@ func runValidate(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("validation failed: %w", err)
 	}
+	if p, err := engine.LoadPRDFile(filepath.Dir(prdPath), filepath.Base(prdPath)); err == nil {
+		prd.ApplyTemplateChecks(".", p, result)
+	}
 
 	// JSON output — always exit 0, encode result in body
 	if validateJSONFlag {
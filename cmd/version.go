@@ -8,6 +8,7 @@ import (
 	"runtime"
 
 	display "github.com/jywlabs/hal/internal/engine"
+	"github.com/jywlabs/hal/internal/skills"
 	"github.com/spf13/cobra"
 )
 
@@ -72,6 +73,7 @@ var versionCmd = &cobra.Command{
 }
 
 func init() {
+	skills.HalVersion = Version
 	versionCmd.Flags().BoolVar(&versionJSONFlag, "json", false, "Output as JSON")
 	rootCmd.AddCommand(versionCmd)
 }
@@ -21,6 +21,7 @@ var featureStateFiles = []string{
 	template.AutoPRDFile,
 	template.ProgressFile,
 	template.AutoStateFile,
+	template.RunStatusFile,
 }
 
 const legacyAutoPRDPattern = "auto-prd.legacy-*.json"
@@ -0,0 +1,302 @@
+package ci
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jywlabs/hal/internal/engine"
+)
+
+// Issue is a GitHub issue with the context hal plan seeds PRDs from.
+type Issue struct {
+	Repo      GitHubRepository `json:"-"`
+	Number    int              `json:"number"`
+	Title     string           `json:"title"`
+	Body      string           `json:"body"`
+	URL       string           `json:"url"`
+	State     string           `json:"state"`
+	Labels    []string         `json:"labels,omitempty"`
+	Milestone string           `json:"milestone,omitempty"`
+	Comments  []IssueComment   `json:"comments,omitempty"`
+}
+
+// IssueComment is one comment on an issue.
+type IssueComment struct {
+	Author string `json:"author"`
+	Body   string `json:"body"`
+}
+
+// Milestone is a GitHub milestone and its open issues.
+type Milestone struct {
+	Number int     `json:"number"`
+	Title  string  `json:"title"`
+	Issues []Issue `json:"issues"`
+}
+
+type issueDeps struct {
+	api func(context.Context, githubAPIRequest, any) error
+}
+
+func defaultIssueAPI(ctx context.Context, req githubAPIRequest, out any) error {
+	client, err := SelectGitHubClient(ctx)
+	if err != nil {
+		return err
+	}
+	return ghAPIWithClient(ctx, client, req, out)
+}
+
+func (d issueDeps) withDefaults() issueDeps {
+	if d.api == nil {
+		d.api = defaultIssueAPI
+	}
+	return d
+}
+
+var (
+	issueURLPattern      = regexp.MustCompile(`^https?://github\.com/([^/\s]+)/([^/\s]+)/issues/(\d+)/?(?:[?#].*)?$`)
+	issueShortRefPattern = regexp.MustCompile(`^([^/\s#]+)/([^/\s#]+)#(\d+)$`)
+)
+
+// ParseIssueRef parses "42", "#42", "owner/repo#42" or an issue URL.
+// The returned repository is empty when the reference names no repository.
+func ParseIssueRef(ref string) (GitHubRepository, int, error) {
+	ref = strings.TrimSpace(ref)
+	var repo GitHubRepository
+	numStr := strings.TrimPrefix(ref, "#")
+	if m := issueURLPattern.FindStringSubmatch(ref); m != nil {
+		repo, numStr = GitHubRepository{Owner: m[1], Name: m[2]}, m[3]
+	} else if m := issueShortRefPattern.FindStringSubmatch(ref); m != nil {
+		repo, numStr = GitHubRepository{Owner: m[1], Name: m[2]}, m[3]
+	}
+	n, err := strconv.Atoi(numStr)
+	if err != nil || n <= 0 {
+		return GitHubRepository{}, 0, fmt.Errorf("invalid issue reference %q: use a number, owner/repo#N or an issue URL", ref)
+	}
+	return repo, n, nil
+}
+
+// IssueReference formats an issue for PR bodies and comments: "#N" within
+// base, "owner/repo#N" otherwise.
+func IssueReference(base, repo GitHubRepository, number int) string {
+	if repo.FullName() == "" || strings.EqualFold(repo.FullName(), base.FullName()) {
+		return fmt.Sprintf("#%d", number)
+	}
+	return fmt.Sprintf("%s#%d", repo.FullName(), number)
+}
+
+type ghIssue struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	Body    string `json:"body"`
+	HTMLURL string `json:"html_url"`
+	State   string `json:"state"`
+	Labels  []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	Milestone *struct {
+		Title string `json:"title"`
+	} `json:"milestone"`
+	PullRequest *struct{} `json:"pull_request"`
+}
+
+func (g ghIssue) toIssue(repo GitHubRepository) Issue {
+	issue := Issue{
+		Repo:   repo,
+		Number: g.Number,
+		Title:  strings.TrimSpace(g.Title),
+		Body:   strings.TrimSpace(g.Body),
+		URL:    strings.TrimSpace(g.HTMLURL),
+		State:  g.State,
+	}
+	for _, l := range g.Labels {
+		issue.Labels = append(issue.Labels, l.Name)
+	}
+	if g.Milestone != nil {
+		issue.Milestone = g.Milestone.Title
+	}
+	return issue
+}
+
+// FetchIssue loads an issue with its labels and comments.
+func FetchIssue(ctx context.Context, repo GitHubRepository, number int) (Issue, error) {
+	return fetchIssueWithDeps(ctx, repo, number, issueDeps{})
+}
+
+func fetchIssueWithDeps(ctx context.Context, repo GitHubRepository, number int, deps issueDeps) (Issue, error) {
+	deps = deps.withDefaults()
+	var raw ghIssue
+	endpoint := fmt.Sprintf("/repos/%s/%s/issues/%d", repo.Owner, repo.Name, number)
+	if err := deps.api(ctx, githubAPIRequest{Method: http.MethodGet, Endpoint: endpoint}, &raw); err != nil {
+		return Issue{}, fmt.Errorf("fetch issue %s#%d: %w", repo.FullName(), number, err)
+	}
+	if raw.PullRequest != nil {
+		return Issue{}, fmt.Errorf("%s#%d is a pull request, not an issue", repo.FullName(), number)
+	}
+	issue := raw.toIssue(repo)
+
+	var comments []struct {
+		Body string `json:"body"`
+		User struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	}
+	if err := deps.api(ctx, githubAPIRequest{Method: http.MethodGet, Endpoint: endpoint + "/comments?per_page=100"}, &comments); err != nil {
+		return Issue{}, fmt.Errorf("fetch comments for issue %s#%d: %w", repo.FullName(), number, err)
+	}
+	for _, c := range comments {
+		if body := strings.TrimSpace(c.Body); body != "" {
+			issue.Comments = append(issue.Comments, IssueComment{Author: c.User.Login, Body: body})
+		}
+	}
+	return issue, nil
+}
+
+// FetchMilestone loads the open issues of a milestone given by number or
+// title. Comments are not loaded for milestone issues.
+func FetchMilestone(ctx context.Context, repo GitHubRepository, milestone string) (Milestone, error) {
+	return fetchMilestoneWithDeps(ctx, repo, milestone, issueDeps{})
+}
+
+func fetchMilestoneWithDeps(ctx context.Context, repo GitHubRepository, milestone string, deps issueDeps) (Milestone, error) {
+	deps = deps.withDefaults()
+	milestone = strings.TrimSpace(milestone)
+
+	var milestones []struct {
+		Number int    `json:"number"`
+		Title  string `json:"title"`
+	}
+	endpoint := fmt.Sprintf("/repos/%s/%s/milestones?state=all&per_page=100", repo.Owner, repo.Name)
+	if err := deps.api(ctx, githubAPIRequest{Method: http.MethodGet, Endpoint: endpoint}, &milestones); err != nil {
+		return Milestone{}, fmt.Errorf("list milestones for %s: %w", repo.FullName(), err)
+	}
+	var found *Milestone
+	for _, m := range milestones {
+		if strconv.Itoa(m.Number) == milestone || strings.EqualFold(m.Title, milestone) {
+			found = &Milestone{Number: m.Number, Title: m.Title}
+			break
+		}
+	}
+	if found == nil {
+		return Milestone{}, fmt.Errorf("milestone %q not found in %s", milestone, repo.FullName())
+	}
+
+	query := url.Values{}
+	query.Set("milestone", strconv.Itoa(found.Number))
+	query.Set("state", "open")
+	query.Set("per_page", "100")
+	var raw []ghIssue
+	endpoint = fmt.Sprintf("/repos/%s/%s/issues?%s", repo.Owner, repo.Name, query.Encode())
+	if err := deps.api(ctx, githubAPIRequest{Method: http.MethodGet, Endpoint: endpoint}, &raw); err != nil {
+		return Milestone{}, fmt.Errorf("list issues for milestone %q: %w", found.Title, err)
+	}
+	for _, r := range raw {
+		if r.PullRequest == nil {
+			found.Issues = append(found.Issues, r.toIssue(repo))
+		}
+	}
+	if len(found.Issues) == 0 {
+		return Milestone{}, fmt.Errorf("milestone %q has no open issues", found.Title)
+	}
+	return *found, nil
+}
+
+// CommentOnIssue posts a comment on an issue.
+func CommentOnIssue(ctx context.Context, repo GitHubRepository, number int, body string) error {
+	return commentOnIssueWithDeps(ctx, repo, number, body, issueDeps{})
+}
+
+func commentOnIssueWithDeps(ctx context.Context, repo GitHubRepository, number int, body string, deps issueDeps) error {
+	deps = deps.withDefaults()
+	endpoint := fmt.Sprintf("/repos/%s/%s/issues/%d/comments", repo.Owner, repo.Name, number)
+	if err := deps.api(ctx, githubAPIRequest{Method: http.MethodPost, Endpoint: endpoint, Body: map[string]string{"body": body}}, nil); err != nil {
+		return fmt.Errorf("comment on issue %s#%d: %w", repo.FullName(), number, err)
+	}
+	return nil
+}
+
+// IssueProgress reports the progress comment posted on one linked issue.
+type IssueProgress struct {
+	Issue     string   `json:"issue"`
+	Stories   []string `json:"stories,omitempty"`
+	Commented bool     `json:"commented"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// ReportIssueProgress posts a progress comment on every issue linked from
+// prd when stories have passed since the last comment. Reported story IDs are
+// recorded in prd.Issues so each story is announced once; callers persist prd.
+func ReportIssueProgress(ctx context.Context, prd *engine.PRD, prURL string) []IssueProgress {
+	return reportIssueProgressWithDeps(ctx, prd, prURL, issueDeps{})
+}
+
+func reportIssueProgressWithDeps(ctx context.Context, prd *engine.PRD, prURL string, deps issueDeps) []IssueProgress {
+	if prd == nil || len(prd.Issues) == 0 {
+		return nil
+	}
+	stories := prd.UserStories
+	if len(stories) == 0 {
+		stories = prd.Tasks
+	}
+	passed := 0
+	for _, s := range stories {
+		if s.Passes {
+			passed++
+		}
+	}
+
+	var results []IssueProgress
+	for i := range prd.Issues {
+		ref := &prd.Issues[i]
+		label := fmt.Sprintf("%s#%d", ref.Repo, ref.Number)
+		reported := make(map[string]bool, len(ref.ReportedStories))
+		for _, id := range ref.ReportedStories {
+			reported[id] = true
+		}
+		var fresh []engine.UserStory
+		for _, s := range stories {
+			if s.Passes && !reported[s.ID] {
+				fresh = append(fresh, s)
+			}
+		}
+		if len(fresh) == 0 {
+			continue
+		}
+
+		result := IssueProgress{Issue: label}
+		for _, s := range fresh {
+			result.Stories = append(result.Stories, s.ID)
+		}
+		owner, name, ok := strings.Cut(ref.Repo, "/")
+		if !ok || owner == "" || name == "" {
+			result.Error = fmt.Sprintf("invalid issue repository %q", ref.Repo)
+			results = append(results, result)
+			continue
+		}
+		body := issueProgressComment(prd, fresh, passed, len(stories), prURL)
+		if err := commentOnIssueWithDeps(ctx, GitHubRepository{Owner: owner, Name: name}, ref.Number, body, deps); err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Commented = true
+			ref.ReportedStories = append(ref.ReportedStories, result.Stories...)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func issueProgressComment(prd *engine.PRD, fresh []engine.UserStory, passed, total int, prURL string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "**hal progress** on `%s`: %d/%d stories complete.\n\nNewly completed:\n", prd.BranchName, passed, total)
+	for _, s := range fresh {
+		fmt.Fprintf(&b, "- %s: %s\n", s.ID, s.Title)
+	}
+	if prURL = strings.TrimSpace(prURL); prURL != "" {
+		fmt.Fprintf(&b, "\nPull request: %s\n", prURL)
+	}
+	return b.String()
+}
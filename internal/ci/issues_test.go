@@ -0,0 +1,189 @@
+package ci
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jywlabs/hal/internal/engine"
+)
+
+func TestParseIssueRef(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		ref      string
+		wantRepo string
+		wantNum  int
+		wantErr  bool
+	}{
+		{ref: "42", wantNum: 42},
+		{ref: "#7", wantNum: 7},
+		{ref: "acme/repo#12", wantRepo: "acme/repo", wantNum: 12},
+		{ref: "https://github.com/acme/repo/issues/9", wantRepo: "acme/repo", wantNum: 9},
+		{ref: "https://github.com/acme/repo/issues/9#issuecomment-1", wantRepo: "acme/repo", wantNum: 9},
+		{ref: "https://github.com/acme/repo/pull/9", wantErr: true},
+		{ref: "0", wantErr: true},
+		{ref: "abc", wantErr: true},
+	}
+	for _, tt := range tests {
+		repo, n, err := ParseIssueRef(tt.ref)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseIssueRef(%q) expected error", tt.ref)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseIssueRef(%q) error = %v", tt.ref, err)
+			continue
+		}
+		if repo.FullName() != tt.wantRepo || n != tt.wantNum {
+			t.Errorf("ParseIssueRef(%q) = %q, %d; want %q, %d", tt.ref, repo.FullName(), n, tt.wantRepo, tt.wantNum)
+		}
+	}
+}
+
+// fakeIssueAPI answers GitHub API requests from a table of canned JSON
+// responses keyed by method and endpoint.
+type fakeIssueAPI struct {
+	responses map[string]string
+	requests  []githubAPIRequest
+}
+
+func (f *fakeIssueAPI) api(_ context.Context, req githubAPIRequest, out any) error {
+	f.requests = append(f.requests, req)
+	body, ok := f.responses[req.Method+" "+req.Endpoint]
+	if !ok {
+		return errors.New("unexpected request " + req.Method + " " + req.Endpoint)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal([]byte(body), out)
+}
+
+func TestFetchIssueWithDeps_LoadsLabelsAndComments(t *testing.T) {
+	t.Parallel()
+
+	fake := &fakeIssueAPI{responses: map[string]string{
+		"GET /repos/acme/repo/issues/42":                       `{"number":42,"title":" Add export ","body":"Users want CSV.","html_url":"https://github.com/acme/repo/issues/42","state":"open","labels":[{"name":"feature"}],"milestone":{"title":"v2"}}`,
+		"GET /repos/acme/repo/issues/42/comments?per_page=100": `[{"body":"Also JSON please","user":{"login":"octo"}},{"body":"  ","user":{"login":"empty"}}]`,
+	}}
+	issue, err := fetchIssueWithDeps(context.Background(), GitHubRepository{Owner: "acme", Name: "repo"}, 42, issueDeps{api: fake.api})
+	if err != nil {
+		t.Fatalf("fetchIssueWithDeps() error = %v", err)
+	}
+	if issue.Title != "Add export" || issue.Milestone != "v2" || len(issue.Labels) != 1 || issue.Labels[0] != "feature" {
+		t.Fatalf("issue = %+v", issue)
+	}
+	if len(issue.Comments) != 1 || issue.Comments[0].Author != "octo" {
+		t.Fatalf("comments = %+v, want one non-empty comment", issue.Comments)
+	}
+
+	fake.responses["GET /repos/acme/repo/issues/43"] = `{"number":43,"pull_request":{}}`
+	if _, err := fetchIssueWithDeps(context.Background(), GitHubRepository{Owner: "acme", Name: "repo"}, 43, issueDeps{api: fake.api}); err == nil || !strings.Contains(err.Error(), "pull request") {
+		t.Fatalf("error = %v, want pull request rejection", err)
+	}
+}
+
+func TestFetchMilestoneWithDeps_MatchesTitleAndSkipsPullRequests(t *testing.T) {
+	t.Parallel()
+
+	fake := &fakeIssueAPI{responses: map[string]string{
+		"GET /repos/acme/repo/milestones?state=all&per_page=100":          `[{"number":1,"title":"v1"},{"number":3,"title":"Billing"}]`,
+		"GET /repos/acme/repo/issues?milestone=3&per_page=100&state=open": `[{"number":5,"title":"Invoices"},{"number":6,"title":"PR","pull_request":{}}]`,
+	}}
+	m, err := fetchMilestoneWithDeps(context.Background(), GitHubRepository{Owner: "acme", Name: "repo"}, "billing", issueDeps{api: fake.api})
+	if err != nil {
+		t.Fatalf("fetchMilestoneWithDeps() error = %v", err)
+	}
+	if m.Number != 3 || len(m.Issues) != 1 || m.Issues[0].Number != 5 {
+		t.Fatalf("milestone = %+v", m)
+	}
+
+	if _, err := fetchMilestoneWithDeps(context.Background(), GitHubRepository{Owner: "acme", Name: "repo"}, "v9", issueDeps{api: fake.api}); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("error = %v, want not found", err)
+	}
+}
+
+func TestReportIssueProgressWithDeps_CommentsOncePerStory(t *testing.T) {
+	t.Parallel()
+
+	fake := &fakeIssueAPI{responses: map[string]string{
+		"POST /repos/acme/repo/issues/42/comments": `{}`,
+	}}
+	prd := &engine.PRD{
+		BranchName: "hal/export",
+		UserStories: []engine.UserStory{
+			{ID: "US-001", Title: "CSV export", Passes: true},
+			{ID: "US-002", Title: "JSON export", Passes: true},
+			{ID: "US-003", Title: "Docs"},
+		},
+		Issues: []engine.IssueRef{{Repo: "acme/repo", Number: 42, ReportedStories: []string{"US-001"}}},
+	}
+
+	results := reportIssueProgressWithDeps(context.Background(), prd, "https://github.com/acme/repo/pull/7", issueDeps{api: fake.api})
+	if len(results) != 1 || !results[0].Commented || len(results[0].Stories) != 1 || results[0].Stories[0] != "US-002" {
+		t.Fatalf("results = %+v, want one comment for US-002", results)
+	}
+	body := fake.requests[0].Body.(map[string]string)["body"]
+	for _, want := range []string{"`hal/export`: 2/3 stories complete", "- US-002: JSON export", "pull/7"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("comment missing %q:\n%s", want, body)
+		}
+	}
+	if got := prd.Issues[0].ReportedStories; len(got) != 2 || got[1] != "US-002" {
+		t.Fatalf("reportedStories = %v, want US-002 recorded", got)
+	}
+
+	if results := reportIssueProgressWithDeps(context.Background(), prd, "", issueDeps{api: fake.api}); len(results) != 0 {
+		t.Fatalf("second report = %+v, want no new comments", results)
+	}
+}
+
+func TestAppendClosingReferences(t *testing.T) {
+	t.Parallel()
+
+	got := appendClosingReferences("Summary\n", GitHubRepository{Owner: "acme", Name: "repo"}, []engine.IssueRef{
+		{Repo: "acme/repo", Number: 42},
+		{Repo: "acme/other", Number: 3},
+	})
+	want := "Summary\n\nCloses #42\nCloses acme/other#3"
+	if got != want {
+		t.Fatalf("appendClosingReferences() = %q, want %q", got, want)
+	}
+}
+
+func TestAddClosingReferencesWithDeps_AddsOnlyMissingReferences(t *testing.T) {
+	t.Parallel()
+
+	repo := GitHubRepository{Owner: "acme", Name: "repo"}
+	issues := []engine.IssueRef{{Repo: "acme/repo", Number: 42}, {Repo: "acme/other", Number: 3}}
+	fake := &fakeIssueAPI{responses: map[string]string{
+		"GET /repos/acme/repo/pulls/7":   `{"body":"Summary\n\nFixes #42"}`,
+		"PATCH /repos/acme/repo/pulls/7": `{}`,
+	}}
+	if err := addClosingReferencesWithDeps(context.Background(), repo, 7, issues, issueDeps{api: fake.api}); err != nil {
+		t.Fatalf("addClosingReferencesWithDeps() error = %v", err)
+	}
+	if len(fake.requests) != 2 {
+		t.Fatalf("requests = %+v, want GET and PATCH", fake.requests)
+	}
+	body := fake.requests[1].Body.(map[string]any)["body"]
+	if want := "Summary\n\nFixes #42\n\nCloses acme/other#3"; body != want {
+		t.Fatalf("patched body = %q, want %q", body, want)
+	}
+
+	fake = &fakeIssueAPI{responses: map[string]string{
+		"GET /repos/acme/repo/pulls/7": `{"body":"Closes #42\nCloses acme/other#3"}`,
+	}}
+	if err := addClosingReferencesWithDeps(context.Background(), repo, 7, issues, issueDeps{api: fake.api}); err != nil {
+		t.Fatalf("addClosingReferencesWithDeps() error = %v", err)
+	}
+	if len(fake.requests) != 1 {
+		t.Fatalf("requests = %+v, want no PATCH when every issue is referenced", fake.requests)
+	}
+}
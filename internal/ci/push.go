@@ -6,8 +6,11 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"regexp"
 	"strconv"
 	"strings"
+
+	"github.com/jywlabs/hal/internal/engine"
 )
 
 const defaultPushTitlePrefix = "hal ci: "
@@ -49,6 +52,9 @@ type PushOptions struct {
 	Title   string
 	Body    string
 	Draft   *bool
+	// CloseIssues are appended as "Closes #N" to the body of a new pull
+	// request, or of a reused one that does not reference them yet.
+	CloseIssues []engine.IssueRef
 }
 
 type createPullRequestOptions struct {
@@ -67,6 +73,7 @@ type pushDeps struct {
 	resolveBaseRef func(context.Context, GitHubRepository, string) (string, error)
 	findOpenPR    func(context.Context, GitHubRepository, string, string) (*PullRequest, error)
 	createPR      func(context.Context, createPullRequestOptions) (string, error)
+	closeIssues   func(context.Context, GitHubRepository, int, []engine.IssueRef) error
 }
 
 // PushAndCreatePR pushes the current branch and creates or reuses an open pull request.
@@ -117,6 +124,11 @@ func pushAndCreatePRWithDeps(ctx context.Context, opts PushOptions, deps pushDep
 	if deps.createPR == nil {
 		deps.createPR = createPullRequest
 	}
+	if deps.closeIssues == nil {
+		deps.closeIssues = func(ctx context.Context, repo GitHubRepository, number int, issues []engine.IssueRef) error {
+			return addClosingReferencesWithDeps(ctx, repo, number, issues, issueDeps{})
+		}
+	}
 
 	branch, err := deps.currentBranch(ctx)
 	if err != nil {
@@ -148,6 +160,11 @@ func pushAndCreatePRWithDeps(ctx context.Context, opts PushOptions, deps pushDep
 		if strings.TrimSpace(existing.HeadRef) == "" {
 			existing.HeadRef = branch
 		}
+		if len(opts.CloseIssues) > 0 && existing.Number > 0 {
+			if err := deps.closeIssues(ctx, repo, existing.Number, opts.CloseIssues); err != nil {
+				return PushResult{}, err
+			}
+		}
 		return buildPushResult(branch, existing), nil
 	}
 
@@ -161,6 +178,7 @@ func pushAndCreatePRWithDeps(ctx context.Context, opts PushOptions, deps pushDep
 
 	createOpts := defaultCreatePullRequestOptions(branch, opts)
 	createOpts.Repo = repo
+	createOpts.Body = appendClosingReferences(createOpts.Body, repo, opts.CloseIssues)
 	createOpts.BaseRef = baseRef
 	prURL, err := deps.createPR(ctx, createOpts)
 	if err != nil {
@@ -233,6 +251,68 @@ func defaultCreatePullRequestOptions(branch string, opts PushOptions) createPull
 	}
 }
 
+// appendClosingReferences adds a "Closes" line per linked issue so merging
+// the pull request closes them.
+func appendClosingReferences(body string, repo GitHubRepository, issues []engine.IssueRef) string {
+	if len(issues) == 0 {
+		return body
+	}
+	var b strings.Builder
+	b.WriteString(strings.TrimRight(body, "\n"))
+	b.WriteString("\n")
+	for _, issue := range issues {
+		issueRepo := GitHubRepository{}
+		if owner, name, ok := strings.Cut(issue.Repo, "/"); ok {
+			issueRepo = GitHubRepository{Owner: owner, Name: name}
+		}
+		fmt.Fprintf(&b, "\nCloses %s", IssueReference(repo, issueRepo, issue.Number))
+	}
+	return b.String()
+}
+
+// addClosingReferencesWithDeps appends "Closes" lines to the body of pull
+// request number for the issues it does not reference yet.
+func addClosingReferencesWithDeps(ctx context.Context, repo GitHubRepository, number int, issues []engine.IssueRef, deps issueDeps) error {
+	deps = deps.withDefaults()
+	endpoint := fmt.Sprintf("/repos/%s/%s/pulls/%d", repo.Owner, repo.Name, number)
+	var pull struct {
+		Body string `json:"body"`
+	}
+	if err := deps.api(ctx, githubAPIRequest{Method: http.MethodGet, Endpoint: endpoint}, &pull); err != nil {
+		return fmt.Errorf("read pull request #%d: %w", number, err)
+	}
+
+	var missing []engine.IssueRef
+	for _, issue := range issues {
+		if !hasClosingReference(pull.Body, repo, issue) {
+			missing = append(missing, issue)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	if err := deps.api(ctx, githubAPIRequest{
+		Method:   http.MethodPatch,
+		Endpoint: endpoint,
+		Body:     map[string]any{"body": appendClosingReferences(pull.Body, repo, missing)},
+	}, nil); err != nil {
+		return fmt.Errorf("link issues to pull request #%d: %w", number, err)
+	}
+	return nil
+}
+
+// hasClosingReference reports whether body already closes issue with one of
+// GitHub's closing keywords.
+func hasClosingReference(body string, repo GitHubRepository, issue engine.IssueRef) bool {
+	issueRepo := GitHubRepository{}
+	if owner, name, ok := strings.Cut(issue.Repo, "/"); ok {
+		issueRepo = GitHubRepository{Owner: owner, Name: name}
+	}
+	ref := IssueReference(repo, issueRepo, issue.Number)
+	pattern := regexp.MustCompile(`(?i)\b(?:close[sd]?|fix(?:e[sd])?|resolve[sd]?):?\s+` + regexp.QuoteMeta(ref) + `\b`)
+	return pattern.MatchString(body)
+}
+
 func defaultPushPRTitle(branch string) string {
 	return defaultPushTitlePrefix + branch
 }
@@ -4,7 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"reflect"
 	"testing"
+
+	"github.com/jywlabs/hal/internal/engine"
 )
 
 func TestPushAndCreatePRWithDeps_PushesCurrentBranchAndReusesExistingPRWithoutImplicitBaseFilter(t *testing.T) {
@@ -487,3 +490,35 @@ func TestDefaultPushPRTitleAndBody_Deterministic(t *testing.T) {
 		t.Fatalf("defaultPushPRBody(%q) = %q, want %q", branch, got, want)
 	}
 }
+
+func TestPushAndCreatePRWithDeps_ReusedPRGetsClosingReferences(t *testing.T) {
+	t.Parallel()
+
+	issues := []engine.IssueRef{{Repo: "acme/repo", Number: 42}}
+	var gotNumber int
+	var gotIssues []engine.IssueRef
+	_, err := pushAndCreatePRWithDeps(context.Background(), PushOptions{CloseIssues: issues}, pushDeps{
+		currentBranch: func(context.Context) (string, error) { return "hal/feature", nil },
+		pushBranch:    func(context.Context, string) error { return nil },
+		resolveRepo: func(context.Context) (GitHubRepository, error) {
+			return GitHubRepository{Owner: "acme", Name: "repo"}, nil
+		},
+		findOpenPR: func(context.Context, GitHubRepository, string, string) (*PullRequest, error) {
+			return &PullRequest{Number: 7, URL: "https://github.com/acme/repo/pull/7"}, nil
+		},
+		createPR: func(context.Context, createPullRequestOptions) (string, error) {
+			t.Fatal("createPR called for an existing pull request")
+			return "", nil
+		},
+		closeIssues: func(_ context.Context, _ GitHubRepository, number int, got []engine.IssueRef) error {
+			gotNumber, gotIssues = number, got
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("pushAndCreatePRWithDeps() error = %v", err)
+	}
+	if gotNumber != 7 || !reflect.DeepEqual(gotIssues, issues) {
+		t.Fatalf("closeIssues(%d, %+v), want #7 with the linked issues", gotNumber, gotIssues)
+	}
+}
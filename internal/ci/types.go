@@ -36,6 +36,8 @@ type PushResult struct {
 	DryRun          bool        `json:"dryRun"`
 	PullRequest     PullRequest `json:"pullRequest"`
 	Summary         string      `json:"summary"`
+	// IssueProgress lists progress comments posted on issues linked from prd.json.
+	IssueProgress []IssueProgress `json:"issueProgress,omitempty"`
 }
 
 // PullRequest contains PR metadata shared by CI operations.
@@ -11,10 +11,20 @@ import (
 	"time"
 
 	"github.com/jywlabs/hal/internal/engine"
+	"github.com/jywlabs/hal/internal/repomap"
 )
 
 var ErrNoReportsFound = errors.New("no reports found")
 
+// codebaseContextBudget caps the codebase map included in analyze and
+// review prompts.
+const codebaseContextBudget = 12000
+
+// codebaseMap renders the codebase map for dir. Injectable for testing.
+var codebaseMap = func(dir string) string {
+	return repomap.Build(dir, repomap.Options{Budget: codebaseContextBudget}).Render()
+}
+
 // FindLatestReport returns the most recently modified file in the reports directory.
 // Returns an error if the directory doesn't exist or contains no files.
 func FindLatestReport(reportsDir string) (string, error) {
@@ -119,7 +129,7 @@ func AnalyzeReport(ctx context.Context, eng engine.Engine, reportPath string, re
 	}
 
 	// Build the prompt
-	prompt := buildAnalysisPrompt(string(reportContent), recentPRDs)
+	prompt := buildAnalysisPrompt(string(reportContent), recentPRDs, codebaseMap("."))
 
 	// Call the engine
 	response, err := eng.Prompt(ctx, prompt)
@@ -137,7 +147,7 @@ func AnalyzeReport(ctx context.Context, eng engine.Engine, reportPath string, re
 }
 
 // buildAnalysisPrompt constructs the prompt for the analysis engine.
-func buildAnalysisPrompt(reportContent string, recentPRDs []string) string {
+func buildAnalysisPrompt(reportContent string, recentPRDs []string, codebase string) string {
 	var sb strings.Builder
 
 	sb.WriteString(`You are analyzing a product/engineering report to identify the single highest priority item to work on next.
@@ -169,6 +179,15 @@ func buildAnalysisPrompt(reportContent string, recentPRDs []string) string {
 `)
 	sb.WriteString(reportContent)
 
+	if strings.TrimSpace(codebase) != "" {
+		sb.WriteString(`
+
+## Codebase Map (prefer items that fit the existing architecture)
+
+`)
+		sb.WriteString(codebase)
+	}
+
 	sb.WriteString(`
 
 ## Required JSON Response Format
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -144,6 +145,29 @@ type rawDaytonaConfig struct {
 	ServerURL *string `yaml:"serverURL"`
 }
 
+// StorySplitConfig controls splitting stories that stall during a run.
+type StorySplitConfig struct {
+	Enabled         bool `yaml:"enabled"`
+	AfterIterations int  `yaml:"afterIterations"`
+}
+
+// rawStorySplitConfig is used for YAML unmarshaling to distinguish missing keys from explicit values.
+type rawStorySplitConfig struct {
+	Enabled         *bool `yaml:"enabled"`
+	AfterIterations *int  `yaml:"afterIterations"`
+}
+
+// StandardsConfig controls which standards are injected into each hal run
+// iteration.
+type StandardsConfig struct {
+	Budget int `yaml:"budget"`
+}
+
+// rawStandardsConfig is used for YAML unmarshaling to distinguish missing keys from explicit values.
+type rawStandardsConfig struct {
+	Budget *int `yaml:"budget"`
+}
+
 // RawEngineConfig holds per-engine settings from YAML.
 // Pointer fields distinguish "not set" (nil) from "set to empty string".
 type RawEngineConfig struct {
@@ -161,6 +185,8 @@ type Config struct {
 	Engines       map[string]*RawEngineConfig `yaml:"engines"`
 	Auto          rawAutoConfig               `yaml:"auto"`
 	Daytona       rawDaytonaConfig            `yaml:"daytona"`
+	StorySplit    rawStorySplitConfig         `yaml:"storySplit"`
+	Standards     rawStandardsConfig          `yaml:"standards"`
 }
 
 // DefaultAutoConfig returns sensible defaults for auto configuration.
@@ -338,6 +364,37 @@ func LoadDefaultEngine(dir string) (string, error) {
 	return engineName, nil
 }
 
+// ConfiguredEngines returns the default engine followed by any other engines
+// with settings under engines: in .hal/config.yaml, sorted by name.
+func ConfiguredEngines(dir string) ([]string, error) {
+	defaultEngine, err := LoadDefaultEngine(dir)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{defaultEngine}
+
+	data, err := os.ReadFile(filepath.Join(dir, template.HalDir, template.ConfigFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return names, nil
+		}
+		return nil, err
+	}
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	var others []string
+	for name := range config.Engines {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "" && name != defaultEngine {
+			others = append(others, name)
+		}
+	}
+	slices.Sort(others)
+	return append(names, slices.Compact(others)...), nil
+}
+
 // LoadEngineConfig reads per-engine configuration from .hal/config.yaml.
 // Returns nil if no engine-specific config is set (engine uses its own defaults).
 func LoadEngineConfig(dir, engineName string) *engine.EngineConfig {
@@ -420,6 +477,88 @@ func LoadDaytonaConfig(dir string) (*DaytonaConfig, error) {
 	return &cfg, nil
 }
 
+// DefaultStorySplitConfig returns the default story split settings: split a
+// story after three iterations without passing.
+func DefaultStorySplitConfig() StorySplitConfig {
+	return StorySplitConfig{Enabled: true, AfterIterations: 3}
+}
+
+// LoadStorySplitConfig reads the storySplit: section from .hal/config.yaml.
+// If the file or section is missing, defaults are returned (no error).
+func LoadStorySplitConfig(dir string) (*StorySplitConfig, error) {
+	configPath := filepath.Join(dir, template.HalDir, template.ConfigFile)
+
+	cfg := DefaultStorySplitConfig()
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &cfg, nil
+		}
+		return nil, err
+	}
+
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+
+	if config.StorySplit.Enabled != nil {
+		cfg.Enabled = *config.StorySplit.Enabled
+	}
+	if config.StorySplit.AfterIterations != nil {
+		if *config.StorySplit.AfterIterations < 1 {
+			return nil, fmt.Errorf("storySplit.afterIterations must be at least 1")
+		}
+		cfg.AfterIterations = *config.StorySplit.AfterIterations
+	}
+
+	return &cfg, nil
+}
+
+// SplitAfter returns the loop.Config.SplitAfter value for the settings:
+// the iteration threshold, or 0 when splitting is disabled.
+func (c *StorySplitConfig) SplitAfter() int {
+	if c == nil || !c.Enabled {
+		return 0
+	}
+	return c.AfterIterations
+}
+
+// DefaultStandardsConfig returns the default standards settings: inject at
+// most 24000 characters of standards per iteration.
+func DefaultStandardsConfig() StandardsConfig {
+	return StandardsConfig{Budget: 24000}
+}
+
+// LoadStandardsConfig reads the standards: section from .hal/config.yaml.
+// If the file or section is missing, defaults are returned.
+func LoadStandardsConfig(dir string) (*StandardsConfig, error) {
+	configPath := filepath.Join(dir, template.HalDir, template.ConfigFile)
+
+	cfg := DefaultStandardsConfig()
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &cfg, nil
+		}
+		return nil, err
+	}
+
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+
+	if config.Standards.Budget != nil {
+		if *config.Standards.Budget < 0 {
+			return nil, fmt.Errorf("standards.budget must be 0 (unlimited) or positive")
+		}
+		cfg.Budget = *config.Standards.Budget
+	}
+
+	return &cfg, nil
+}
+
 // LoadSandboxConfig reads the sandbox: section from .hal/config.yaml.
 // If the file or section is missing, a config with Provider defaulting to "daytona" is returned.
 func LoadSandboxConfig(dir string) (*SandboxConfig, error) {
@@ -450,6 +450,27 @@ func TestLoadEngineConfig_MissingFile(t *testing.T) {
 	}
 }
 
+func TestConfiguredEngines(t *testing.T) {
+	dir := t.TempDir()
+	got, err := ConfiguredEngines(dir)
+	if err != nil || strings.Join(got, ",") != "codex" {
+		t.Fatalf("ConfiguredEngines() without config = %v, %v", got, err)
+	}
+
+	halDir := filepath.Join(dir, ".hal")
+	if err := os.MkdirAll(halDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	config := "engine: pi\nengines:\n  codex:\n    model: gpt-5\n  pi:\n    provider: anthropic\n  claude:\n    model: sonnet\n"
+	if err := os.WriteFile(filepath.Join(halDir, "config.yaml"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err = ConfiguredEngines(dir)
+	if err != nil || strings.Join(got, ",") != "pi,claude,codex" {
+		t.Fatalf("ConfiguredEngines() = %v, %v; want [pi claude codex]", got, err)
+	}
+}
+
 func TestLoadConfig_InvalidYAML(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -771,6 +792,96 @@ func TestLoadDaytonaConfig_InvalidYAML(t *testing.T) {
 	}
 }
 
+func TestLoadStorySplitConfig(t *testing.T) {
+	tests := []struct {
+		name           string
+		yaml           string
+		wantSplitAfter int
+		wantErr        bool
+	}{
+		{name: "no section uses defaults", yaml: "engine: claude\n", wantSplitAfter: 3},
+		{name: "custom threshold", yaml: "storySplit:\n  afterIterations: 5\n", wantSplitAfter: 5},
+		{name: "disabled", yaml: "storySplit:\n  enabled: false\n  afterIterations: 5\n", wantSplitAfter: 0},
+		{name: "zero threshold rejected", yaml: "storySplit:\n  afterIterations: 0\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			halDir := filepath.Join(dir, ".hal")
+			if err := os.MkdirAll(halDir, 0755); err != nil {
+				t.Fatalf("Failed to create .hal dir: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(halDir, "config.yaml"), []byte(tt.yaml), 0644); err != nil {
+				t.Fatalf("Failed to write config.yaml: %v", err)
+			}
+
+			cfg, err := LoadStorySplitConfig(dir)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("LoadStorySplitConfig() expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadStorySplitConfig() unexpected error: %v", err)
+			}
+			if got := cfg.SplitAfter(); got != tt.wantSplitAfter {
+				t.Errorf("SplitAfter() = %d, want %d", got, tt.wantSplitAfter)
+			}
+		})
+	}
+
+	cfg, err := LoadStorySplitConfig(filepath.Join(t.TempDir(), "does-not-exist"))
+	if err != nil {
+		t.Fatalf("LoadStorySplitConfig() missing file error: %v", err)
+	}
+	if *cfg != DefaultStorySplitConfig() {
+		t.Errorf("missing file config = %+v, want defaults", *cfg)
+	}
+}
+
+func TestLoadStandardsConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		yaml       string
+		wantBudget int
+		wantErr    bool
+	}{
+		{name: "no section uses defaults", yaml: "engine: claude\n", wantBudget: 24000},
+		{name: "custom budget", yaml: "standards:\n  budget: 8000\n", wantBudget: 8000},
+		{name: "unlimited", yaml: "standards:\n  budget: 0\n", wantBudget: 0},
+		{name: "negative budget rejected", yaml: "standards:\n  budget: -1\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			halDir := filepath.Join(dir, ".hal")
+			if err := os.MkdirAll(halDir, 0755); err != nil {
+				t.Fatalf("Failed to create .hal dir: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(halDir, "config.yaml"), []byte(tt.yaml), 0644); err != nil {
+				t.Fatalf("Failed to write config.yaml: %v", err)
+			}
+
+			cfg, err := LoadStandardsConfig(dir)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("LoadStandardsConfig() expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadStandardsConfig() unexpected error: %v", err)
+			}
+			if cfg.Budget != tt.wantBudget {
+				t.Errorf("Budget = %d, want %d", cfg.Budget, tt.wantBudget)
+			}
+		})
+	}
+}
+
 func TestSaveConfig(t *testing.T) {
 	t.Run("creates config.yaml when none exists", func(t *testing.T) {
 		dir := t.TempDir()
@@ -0,0 +1,376 @@
+package compound
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jywlabs/hal/internal/engine"
+	"github.com/jywlabs/hal/internal/standards"
+	"github.com/jywlabs/hal/internal/template"
+)
+
+// Prompt budgets for learnings consolidation.
+const (
+	learningsPromptBudget = 30000
+	standardsPromptBudget = 20000
+)
+
+// Learning is one pattern recorded during a feature, with every place it
+// was found.
+type Learning struct {
+	Text    string   `json:"text"`
+	Sources []string `json:"sources"` // Paths relative to the project, e.g. ".hal/archive/2026-01-02-auth/progress.txt"
+}
+
+// GatherLearnings collects the Codebase Patterns sections of active and
+// archived progress logs, the pattern sections hal report appends to
+// AGENTS.md, and the patterns listed in active and archived review reports.
+// Identical patterns are merged; their sources are kept.
+func GatherLearnings(dir string) ([]Learning, error) {
+	halDir := filepath.Join(dir, template.HalDir)
+
+	var found []Learning
+	add := func(path string, patterns []string) {
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			rel = path
+		}
+		rel = filepath.ToSlash(rel)
+		for _, p := range patterns {
+			found = append(found, Learning{Text: p, Sources: []string{rel}})
+		}
+	}
+	read := func(path string) string {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return ""
+		}
+		return string(data)
+	}
+
+	featureDirs := []string{halDir}
+	archives, err := filepath.Glob(filepath.Join(halDir, "archive", "*"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(archives)
+	featureDirs = append(featureDirs, archives...)
+
+	for _, fd := range featureDirs {
+		progressPath := filepath.Join(fd, template.ProgressFile)
+		add(progressPath, sectionBullets(read(progressPath), func(h string) bool { return h == "Codebase Patterns" }))
+
+		reports, _ := filepath.Glob(filepath.Join(fd, "reports", "*.md"))
+		sort.Strings(reports)
+		for _, report := range reports {
+			add(report, sectionBullets(read(report), func(h string) bool { return h == "Patterns Discovered" }))
+		}
+	}
+
+	agentsPath := filepath.Join(dir, "AGENTS.md")
+	add(agentsPath, sectionBullets(read(agentsPath), func(h string) bool { return strings.HasPrefix(h, "Patterns from ") }))
+
+	return mergeLearnings(found), nil
+}
+
+// sectionBullets returns the "- " items of the level-two sections whose
+// heading matches. A section ends at the next heading or "---" rule.
+func sectionBullets(content string, match func(heading string) bool) []string {
+	var items []string
+	in := false
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "## "):
+			in = match(strings.TrimSpace(strings.TrimPrefix(trimmed, "## ")))
+		case strings.HasPrefix(trimmed, "#"), trimmed == "---":
+			in = false
+		case in && (strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* ")):
+			item := strings.TrimSpace(trimmed[2:])
+			if item != "" && !strings.HasPrefix(item, "Example:") {
+				items = append(items, item)
+			}
+		case in && len(items) > 0 && trimmed != "" && !strings.HasPrefix(trimmed, "("):
+			// Continuation of a wrapped bullet.
+			items[len(items)-1] += " " + trimmed
+		}
+	}
+	return items
+}
+
+// mergeLearnings folds patterns that differ only in case, spacing or
+// trailing punctuation into one learning.
+func mergeLearnings(found []Learning) []Learning {
+	var merged []Learning
+	index := map[string]int{}
+	for _, l := range found {
+		key := strings.TrimRight(strings.ToLower(strings.Join(strings.Fields(l.Text), " ")), ".;:")
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(merged)
+			merged = append(merged, l)
+			continue
+		}
+		for _, src := range l.Sources {
+			if !containsString(merged[i].Sources, src) {
+				merged[i].Sources = append(merged[i].Sources, src)
+			}
+		}
+	}
+	return merged
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Proposal actions.
+const (
+	ProposalCreate = "create"
+	ProposalUpdate = "update"
+)
+
+// StandardProposal is a new or updated .hal/standards file proposed from
+// learnings. Nothing is written until the proposal is accepted.
+type StandardProposal struct {
+	Path      string   `json:"path"`   // Path under .hal/standards, e.g. "testing/table-tests.md"
+	Action    string   `json:"action"` // create or update
+	Rationale string   `json:"rationale"`
+	Learnings []string `json:"learnings,omitempty"` // Learnings the proposal consolidates
+	Content   string   `json:"content"`
+	Diff      string   `json:"diff,omitempty"` // Line diff against the current file, for updates
+}
+
+// DroppedLearning is a learning the engine left out of every proposal.
+type DroppedLearning struct {
+	Text   string `json:"text"`
+	Reason string `json:"reason"`
+}
+
+// ConsolidationResult is the outcome of a learnings consolidation pass.
+type ConsolidationResult struct {
+	Learnings []Learning         `json:"learnings"`
+	Proposals []StandardProposal `json:"proposals"`
+	Dropped   []DroppedLearning  `json:"dropped,omitempty"`
+}
+
+// ConsolidateOptions controls learnings consolidation.
+type ConsolidateOptions struct {
+	DryRun bool // Gather learnings without calling the engine
+}
+
+// ConsolidateLearnings gathers learnings and asks the engine to de-duplicate
+// them into proposed standards. Proposals that target imported standards or
+// paths outside .hal/standards are discarded.
+func ConsolidateLearnings(ctx context.Context, eng engine.Engine, display *engine.Display, dir string, opts ConsolidateOptions) (*ConsolidationResult, error) {
+	learnings, err := GatherLearnings(dir)
+	if err != nil {
+		return nil, err
+	}
+	result := &ConsolidationResult{Learnings: learnings, Proposals: []StandardProposal{}}
+	if len(learnings) == 0 {
+		return result, nil
+	}
+	if opts.DryRun {
+		return result, nil
+	}
+
+	halDir := filepath.Join(dir, template.HalDir)
+	existing, err := standards.LoadAll(halDir)
+	if err != nil {
+		return nil, err
+	}
+	lock, err := standards.LoadLock(halDir)
+	if err != nil {
+		return nil, err
+	}
+
+	prompt := buildConsolidatePrompt(learnings, existing, lock)
+	display.StartSpinner("Consolidating learnings...")
+	response, err := eng.Prompt(ctx, prompt)
+	display.StopSpinner()
+	if err != nil {
+		return nil, fmt.Errorf("engine prompt failed: %w", err)
+	}
+
+	parsed, err := parseConsolidateResponse(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse consolidation response: %w", err)
+	}
+	result.Dropped = parsed.Dropped
+
+	standardsDir := filepath.Join(halDir, template.StandardsDir)
+	seen := map[string]bool{}
+	for _, p := range parsed.Proposals {
+		path, err := cleanProposalPath(p.Path)
+		if err != nil {
+			display.ShowInfo("   Skipping proposal: %s\n", err.Error())
+			continue
+		}
+		if origin := lock.Origin(strings.TrimSuffix(path, ".md")); origin != nil {
+			display.ShowInfo("   Skipping proposal for %s: imported from %s\n", path, origin.Name())
+			continue
+		}
+		if seen[path] || strings.TrimSpace(p.Content) == "" {
+			continue
+		}
+		seen[path] = true
+
+		p.Path = path
+		p.Content = strings.TrimSpace(p.Content) + "\n"
+		p.Action = ProposalCreate
+		p.Diff = ""
+		if current, err := os.ReadFile(filepath.Join(standardsDir, filepath.FromSlash(path))); err == nil {
+			if string(current) == p.Content {
+				continue
+			}
+			p.Action = ProposalUpdate
+			p.Diff = standards.LineDiff(path, current, []byte(p.Content))
+		}
+		result.Proposals = append(result.Proposals, p)
+	}
+	return result, nil
+}
+
+// cleanProposalPath validates a proposed path and returns it relative to
+// .hal/standards with a .md extension.
+func cleanProposalPath(path string) (string, error) {
+	path = strings.TrimSpace(filepath.ToSlash(path))
+	path = strings.TrimPrefix(path, template.HalDir+"/"+template.StandardsDir+"/")
+	path = strings.TrimPrefix(path, template.StandardsDir+"/")
+	if path == "" {
+		return "", fmt.Errorf("empty path")
+	}
+	if filepath.Ext(path) != ".md" {
+		path += ".md"
+	}
+	clean := filepath.ToSlash(filepath.Clean(path))
+	if filepath.IsAbs(path) || clean != path || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("invalid path %q", path)
+	}
+	return clean, nil
+}
+
+// ApplyProposal writes an accepted proposal into .hal/standards.
+func ApplyProposal(dir string, p StandardProposal) error {
+	path := filepath.Join(dir, template.HalDir, template.StandardsDir, filepath.FromSlash(p.Path))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create standards directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(p.Content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", p.Path, err)
+	}
+	return nil
+}
+
+// buildConsolidatePrompt constructs the prompt for the consolidation engine.
+func buildConsolidatePrompt(learnings []Learning, existing []standards.Standard, lock *standards.Lock) string {
+	var sb strings.Builder
+
+	sb.WriteString(`You are consolidating learnings that AI agents recorded while implementing features into project standards.
+
+## Instructions
+
+1. Read the learnings below. Many repeat each other, are specific to one story, or are stale.
+2. Merge learnings that say the same thing. Drop learnings that are story-specific, obvious, or contradicted by newer ones.
+3. Group the remaining learnings by domain and propose standards files:
+   - Update an existing standard when a learning belongs to it; return its full new content
+   - Otherwise create a new standard in a domain folder, e.g. "testing/table-tests.md"
+   - Never propose changes to imported (read-only) standards
+4. Each standard is concise markdown: a "# Title", a one-line summary, then rules as bullets.
+   Start new standards with front matter declaring a description, and applies_to globs when the rules only concern some paths:
+   ---
+   description: One line describing the standard
+   applies_to: ["internal/engine/**"]
+   ---
+5. Return ONLY a JSON object with the proposals
+
+## Learnings
+`)
+
+	var lb strings.Builder
+	for _, l := range learnings {
+		lb.WriteString(fmt.Sprintf("- %s (from %s)\n", l.Text, strings.Join(l.Sources, ", ")))
+	}
+	sb.WriteString(truncateContent(lb.String(), learningsPromptBudget))
+
+	sb.WriteString("\n## Existing Standards\n\n")
+	if len(existing) == 0 {
+		sb.WriteString("None\n")
+	} else {
+		var eb strings.Builder
+		for _, s := range existing {
+			readOnly := ""
+			if origin := lock.Origin(s.Key); origin != nil {
+				readOnly = " (imported, read-only)"
+			}
+			eb.WriteString(fmt.Sprintf("### %s.md%s\n\n%s\n\n", s.Key, readOnly, s.Content))
+		}
+		sb.WriteString(truncateContent(eb.String(), standardsPromptBudget))
+	}
+
+	sb.WriteString(`
+## Required JSON Response Format
+
+Return ONLY valid JSON (no markdown code fences, no explanation):
+
+{
+  "proposals": [
+    {
+      "path": "testing/table-tests.md",
+      "rationale": "Why this standard should exist or change",
+      "learnings": ["Learning text this proposal consolidates"],
+      "content": "Full markdown content of the standard file"
+    }
+  ],
+  "dropped": [
+    {"text": "Learning text", "reason": "Why it was not kept"}
+  ]
+}
+
+Notes:
+- path is relative to .hal/standards/ and ends in .md
+- content is the complete file, not a patch
+- return an empty proposals array when nothing is worth keeping
+`)
+
+	return sb.String()
+}
+
+// parsedConsolidation holds the parsed engine response.
+type parsedConsolidation struct {
+	Proposals []StandardProposal `json:"proposals"`
+	Dropped   []DroppedLearning  `json:"dropped"`
+}
+
+// parseConsolidateResponse extracts proposals from the engine response.
+func parseConsolidateResponse(response string) (*parsedConsolidation, error) {
+	response = strings.TrimSpace(response)
+
+	// Try to find JSON in the response (handle markdown code fences)
+	jsonStart := strings.Index(response, "{")
+	jsonEnd := strings.LastIndex(response, "}")
+
+	if jsonStart == -1 || jsonEnd == -1 || jsonEnd < jsonStart {
+		return nil, fmt.Errorf("no JSON object found in response")
+	}
+
+	var result parsedConsolidation
+	if err := json.Unmarshal([]byte(response[jsonStart:jsonEnd+1]), &result); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if result.Proposals == nil {
+		return nil, fmt.Errorf("missing required field: proposals")
+	}
+	return &result, nil
+}
@@ -0,0 +1,199 @@
+package compound
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jywlabs/hal/internal/engine"
+	"github.com/jywlabs/hal/internal/standards"
+)
+
+type learningsEngine struct {
+	response string
+	prompt   string
+}
+
+func (e *learningsEngine) Name() string { return "test" }
+
+func (e *learningsEngine) Execute(ctx context.Context, prompt string, display *engine.Display) engine.Result {
+	return engine.Result{}
+}
+
+func (e *learningsEngine) Prompt(ctx context.Context, prompt string) (string, error) {
+	e.prompt = prompt
+	return e.response, nil
+}
+
+func (e *learningsEngine) StreamPrompt(ctx context.Context, prompt string, display *engine.Display) (string, error) {
+	return e.Prompt(ctx, prompt)
+}
+
+func writeLearningFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func setupLearnings(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	writeLearningFile(t, dir, ".hal/progress.txt", `# Ralph Progress Log
+
+## Codebase Patterns
+
+(Add reusable patterns discovered during implementation here)
+- Use table-driven tests
+- Example: Use sql template for aggregations
+
+---
+
+## US-001
+- **Learnings for future iterations:**
+  - story-specific detail
+`)
+	writeLearningFile(t, dir, ".hal/archive/2026-01-02-auth/progress.txt", `## Codebase Patterns
+- use table-driven tests.
+- Wrap errors with %w so callers
+  can use errors.Is
+---
+`)
+	writeLearningFile(t, dir, ".hal/archive/2026-01-02-auth/reports/review-1.md", `# Review Report: hal/auth
+
+## Patterns Discovered
+
+These patterns have been added to AGENTS.md:
+
+- Engines self-register in init()
+`)
+	writeLearningFile(t, dir, "AGENTS.md", `# Repository Guidelines
+
+## Build
+
+- go build ./...
+
+## Patterns from hal/auth (2026-01-02)
+
+- Engines self-register in init()
+`)
+	return dir
+}
+
+func TestGatherLearnings(t *testing.T) {
+	dir := setupLearnings(t)
+
+	got, err := GatherLearnings(dir)
+	if err != nil {
+		t.Fatalf("GatherLearnings() error: %v", err)
+	}
+	want := []Learning{
+		{Text: "Use table-driven tests", Sources: []string{".hal/progress.txt", ".hal/archive/2026-01-02-auth/progress.txt"}},
+		{Text: "Wrap errors with %w so callers can use errors.Is", Sources: []string{".hal/archive/2026-01-02-auth/progress.txt"}},
+		{Text: "Engines self-register in init()", Sources: []string{".hal/archive/2026-01-02-auth/reports/review-1.md", "AGENTS.md"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("GatherLearnings() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestConsolidateLearnings(t *testing.T) {
+	dir := setupLearnings(t)
+	writeLearningFile(t, dir, ".hal/standards/testing/tables.md", "# Tables\n")
+	writeLearningFile(t, dir, ".hal/standards/org/go.md", "# Imported\n")
+	if err := standards.SaveLock(filepath.Join(dir, ".hal"), &standards.Lock{Packs: []standards.Pack{
+		{Source: "../org", Revision: "abc", Files: map[string]string{"org/go.md": "x"}},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	eng := &learningsEngine{response: "```json\n" + `{
+  "proposals": [
+    {"path": "testing/tables.md", "rationale": "Seen in two features", "content": "# Tables\n\n- Use table-driven tests"},
+    {"path": ".hal/standards/go/errors", "rationale": "New", "content": "# Errors\n\n- Wrap with %w"},
+    {"path": "org/go.md", "rationale": "Imported", "content": "# Changed"},
+    {"path": "../escape.md", "rationale": "Bad", "content": "x"}
+  ],
+  "dropped": [{"text": "story-specific", "reason": "not reusable"}]
+}` + "\n```"}
+
+	result, err := ConsolidateLearnings(context.Background(), eng, engine.NewDisplay(io.Discard), dir, ConsolidateOptions{})
+	if err != nil {
+		t.Fatalf("ConsolidateLearnings() error: %v", err)
+	}
+	if len(result.Learnings) != 3 || len(result.Dropped) != 1 {
+		t.Fatalf("result = %+v", result)
+	}
+	for _, want := range []string{"Use table-driven tests (from .hal/progress.txt, .hal/archive/2026-01-02-auth/progress.txt)", "### org/go.md (imported, read-only)"} {
+		if !strings.Contains(eng.prompt, want) {
+			t.Errorf("prompt missing %q", want)
+		}
+	}
+	if len(result.Proposals) != 2 {
+		t.Fatalf("proposals = %+v, want 2 (imported and escaping paths skipped)", result.Proposals)
+	}
+	update, create := result.Proposals[0], result.Proposals[1]
+	if update.Path != "testing/tables.md" || update.Action != ProposalUpdate || !strings.Contains(update.Diff, "+- Use table-driven tests") {
+		t.Errorf("update proposal = %+v", update)
+	}
+	if create.Path != "go/errors.md" || create.Action != ProposalCreate || create.Diff != "" {
+		t.Errorf("create proposal = %+v", create)
+	}
+
+	// Nothing is written until a proposal is applied.
+	if _, err := os.Stat(filepath.Join(dir, ".hal", "standards", "go", "errors.md")); !os.IsNotExist(err) {
+		t.Fatalf("proposal written before acceptance: %v", err)
+	}
+	if err := ApplyProposal(dir, create); err != nil {
+		t.Fatalf("ApplyProposal() error: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, ".hal", "standards", "go", "errors.md"))
+	if err != nil || string(data) != "# Errors\n\n- Wrap with %w\n" {
+		t.Fatalf("applied standard = %q, %v", data, err)
+	}
+}
+
+func TestConsolidateLearnings_DryRunAndEmpty(t *testing.T) {
+	dir := setupLearnings(t)
+	result, err := ConsolidateLearnings(context.Background(), nil, nil, dir, ConsolidateOptions{DryRun: true})
+	if err != nil || len(result.Learnings) != 3 || len(result.Proposals) != 0 {
+		t.Fatalf("dry run = %+v, %v", result, err)
+	}
+
+	result, err = ConsolidateLearnings(context.Background(), nil, nil, t.TempDir(), ConsolidateOptions{})
+	if err != nil || len(result.Learnings) != 0 {
+		t.Fatalf("empty = %+v, %v", result, err)
+	}
+}
+
+func TestParseConsolidateResponse(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		wantErr  string
+	}{
+		{name: "valid", response: `{"proposals": []}`},
+		{name: "no json", response: "nothing to do", wantErr: "no JSON object"},
+		{name: "missing proposals", response: `{"dropped": []}`, wantErr: "missing required field"},
+		{name: "invalid", response: `{"proposals": [}`, wantErr: "invalid JSON"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseConsolidateResponse(tt.response)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/jywlabs/hal/internal/loop"
 	"github.com/jywlabs/hal/internal/prd"
 	"github.com/jywlabs/hal/internal/skills"
+	"github.com/jywlabs/hal/internal/standards"
 	"github.com/jywlabs/hal/internal/template"
 )
 
@@ -41,8 +42,8 @@ var runLoopWithConfig = func(ctx context.Context, cfg loop.Config) (loop.Result,
 	return runner.Run(ctx), nil
 }
 
-// runReviewLoopWithDisplay points to RunReviewLoopWithDisplay and is overridden in tests.
-var runReviewLoopWithDisplay = RunReviewLoopWithDisplay
+// runReviewLoopWithDisplay points to runReviewLoopInDir and is overridden in tests.
+var runReviewLoopWithDisplay = runReviewLoopInDir
 
 // runReportWithEngine points to Review and is overridden in tests.
 var runReportWithEngine = Review
@@ -68,6 +69,9 @@ var gitAddAllInDirFn = defaultGitAddAllInDir
 // gitCommitInDirFn points to defaultGitCommitInDir and is overridden in tests.
 var gitCommitInDirFn = defaultGitCommitInDir
 
+// standardsCheckFn points to standards.CheckBranch and is overridden in tests.
+var standardsCheckFn = standards.CheckBranch
+
 const (
 	defaultReviewIterations      = 10
 	maxCIFixAttempts             = 3
@@ -248,6 +252,12 @@ func (p *Pipeline) clearState() error {
 	return err
 }
 
+// LoadPipelineState reads the auto pipeline state of the project in dir.
+// Returns nil if there is no readable state.
+func LoadPipelineState(dir string) *PipelineState {
+	return (&Pipeline{dir: dir}).loadState()
+}
+
 // HasState returns true if there is a saved state to resume from.
 func (p *Pipeline) HasState() bool {
 	return p.loadState() != nil
@@ -1014,16 +1024,31 @@ func (p *Pipeline) runLoopStep(ctx context.Context, state *PipelineState, opts R
 		return fmt.Errorf("failed to stat %s: %w", template.ProgressFile, err)
 	}
 
+	splitAfter := 0
+	if splitCfg, err := LoadStorySplitConfig(p.dir); err != nil {
+		p.display.ShowInfo("   ⚠ Story splitting disabled: %v\n", err)
+	} else {
+		splitAfter = splitCfg.SplitAfter()
+	}
+	standardsBudget := DefaultStandardsConfig().Budget
+	if standardsCfg, err := LoadStandardsConfig(p.dir); err != nil {
+		p.display.ShowInfo("   ⚠ Using default standards budget: %v\n", err)
+	} else {
+		standardsBudget = standardsCfg.Budget
+	}
+
 	loopConfig := loop.Config{
-		Dir:           filepath.Join(p.dir, template.HalDir),
-		PRDFile:       template.PRDFile,
-		ProgressFile:  template.ProgressFile,
-		BaseBranch:    state.BaseBranch,
-		MaxIterations: p.config.MaxIterations,
-		Engine:        p.engine.Name(),
-		EngineConfig:  p.engineConfig,
-		Logger:        p.display.Writer(),
-		MaxRetries:    3,
+		Dir:             filepath.Join(p.dir, template.HalDir),
+		PRDFile:         template.PRDFile,
+		ProgressFile:    template.ProgressFile,
+		BaseBranch:      state.BaseBranch,
+		MaxIterations:   p.config.MaxIterations,
+		Engine:          p.engine.Name(),
+		EngineConfig:    p.engineConfig,
+		Logger:          p.display.Writer(),
+		MaxRetries:      3,
+		SplitAfter:      splitAfter,
+		StandardsBudget: standardsBudget,
 	}
 
 	p.display.ShowInfo("   Running task loop...\n")
@@ -1118,7 +1143,7 @@ func (p *Pipeline) runReviewStep(ctx context.Context, state *PipelineState, opts
 	fixesAppliedDuringReview := false
 	for cycle := 1; cycle <= maxCycles; cycle++ {
 		p.display.ShowInfo("   Running review cycle %d/%d against %s...\n", cycle, maxCycles, baseBranch)
-		result, err := runReviewLoopWithDisplay(ctx, p.engine, p.display, baseBranch, 1)
+		result, err := runReviewLoopWithDisplay(ctx, p.engine, p.display, p.dir, baseBranch, 1)
 		if err != nil {
 			state.Review.Status = "failed"
 			return fmt.Errorf("failed to run review cycle %d: %w", cycle, err)
@@ -1255,11 +1280,20 @@ func (p *Pipeline) runFinalVerification(ctx context.Context, state *PipelineStat
 	if strings.TrimSpace(activeBranch) != strings.TrimSpace(state.BranchName) {
 		return fmt.Errorf("current branch %q does not match pipeline state branch %q", strings.TrimSpace(activeBranch), strings.TrimSpace(state.BranchName))
 	}
+	if base := strings.TrimSpace(state.BaseBranch); base != "" {
+		violations, err := standardsCheckFn(p.dir, base)
+		if err != nil {
+			return fmt.Errorf("standards check: %w", err)
+		}
+		if len(violations) > 0 {
+			return fmt.Errorf("standards check found %d violation(s): %s", len(violations), violations[0])
+		}
+	}
 	return nil
 }
 
 func (p *Pipeline) reviewVerificationChecks(state *PipelineState) []VerificationCheck {
-	checks := make([]VerificationCheck, 0, 5)
+	checks := make([]VerificationCheck, 0, 6)
 	if state != nil && state.Validation != nil {
 		status := strings.TrimSpace(state.Validation.Status)
 		output := fmt.Sprintf("status=%s attempts=%d", status, state.Validation.Attempts)
@@ -1281,6 +1315,9 @@ func (p *Pipeline) reviewVerificationChecks(state *PipelineState) []Verification
 		}
 		checks = append(checks, VerificationCheck{Name: "ci", OK: status == "passed" || status == "skipped", Output: output})
 	}
+	if state != nil && strings.TrimSpace(state.BaseBranch) != "" {
+		checks = append(checks, p.standardsVerificationCheck(strings.TrimSpace(state.BaseBranch)))
+	}
 	paths, err := workingTreeChangesInDirFn(p.dir)
 	if err != nil {
 		checks = append(checks, VerificationCheck{Name: "working_tree", OK: false, Output: fmt.Sprintf("inspect failed: %v", err)})
@@ -1294,6 +1331,22 @@ func (p *Pipeline) reviewVerificationChecks(state *PipelineState) []Verification
 	return checks
 }
 
+// standardsVerificationCheck runs hal standards check against base.
+func (p *Pipeline) standardsVerificationCheck(base string) VerificationCheck {
+	violations, err := standardsCheckFn(p.dir, base)
+	if err != nil {
+		return VerificationCheck{Name: "standards", OK: false, Output: fmt.Sprintf("check failed: %v", err)}
+	}
+	if len(violations) == 0 {
+		return VerificationCheck{Name: "standards", OK: true, Output: "no violations"}
+	}
+	lines := make([]string, 0, len(violations))
+	for _, v := range violations {
+		lines = append(lines, v.String())
+	}
+	return VerificationCheck{Name: "standards", OK: false, Output: fmt.Sprintf("%d violation(s): %s", len(violations), strings.Join(lines, "; "))}
+}
+
 // migrateAutoProgress migrates content from legacy auto-progress.txt to unified progress.txt.
 // If auto-progress.txt exists, its content is appended to progress.txt and the legacy file is deleted.
 func (p *Pipeline) migrateAutoProgress() error {
@@ -1685,7 +1738,7 @@ func buildTaskStatusSection(prd *engine.PRD, state *PipelineState, maxIterations
 func remainingStoryIDs(prd *engine.PRD) []string {
 	remaining := make([]string, 0)
 	for _, story := range prd.UserStories {
-		if story.Passes {
+		if story.Passes || story.Skipped {
 			continue
 		}
 		id := story.ID
@@ -1697,7 +1750,7 @@ func remainingStoryIDs(prd *engine.PRD) []string {
 		}
 	}
 	for _, task := range prd.Tasks {
-		if task.Passes {
+		if task.Passes || task.Skipped {
 			continue
 		}
 		id := task.ID
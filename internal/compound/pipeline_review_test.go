@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/jywlabs/hal/internal/engine"
+	"github.com/jywlabs/hal/internal/standards"
 	"github.com/jywlabs/hal/internal/template"
 )
 
@@ -29,7 +30,7 @@ func TestRun_ReviewFailureBlocksReportStep(t *testing.T) {
 	}
 
 	origReviewLoop := runReviewLoopWithDisplay
-	runReviewLoopWithDisplay = func(ctx context.Context, eng engine.Engine, display *engine.Display, baseBranch string, requestedIterations int) (*ReviewLoopResult, error) {
+	runReviewLoopWithDisplay = func(ctx context.Context, eng engine.Engine, display *engine.Display, dir, baseBranch string, requestedIterations int) (*ReviewLoopResult, error) {
 		return nil, errors.New("review loop exploded")
 	}
 	t.Cleanup(func() {
@@ -91,7 +92,7 @@ func TestRunReviewAndReportSteps_SuccessPersistsReportPath(t *testing.T) {
 	var gotBaseBranch string
 	var gotIterations int
 	origReviewLoop := runReviewLoopWithDisplay
-	runReviewLoopWithDisplay = func(ctx context.Context, eng engine.Engine, display *engine.Display, baseBranch string, requestedIterations int) (*ReviewLoopResult, error) {
+	runReviewLoopWithDisplay = func(ctx context.Context, eng engine.Engine, display *engine.Display, dir, baseBranch string, requestedIterations int) (*ReviewLoopResult, error) {
 		gotBaseBranch = baseBranch
 		gotIterations = requestedIterations
 		return &ReviewLoopResult{
@@ -204,7 +205,7 @@ func TestRunReviewStep_BlocksWhenValidIssuesRemain(t *testing.T) {
 	}
 
 	origReviewLoop := runReviewLoopWithDisplay
-	runReviewLoopWithDisplay = func(ctx context.Context, eng engine.Engine, display *engine.Display, baseBranch string, requestedIterations int) (*ReviewLoopResult, error) {
+	runReviewLoopWithDisplay = func(ctx context.Context, eng engine.Engine, display *engine.Display, dir, baseBranch string, requestedIterations int) (*ReviewLoopResult, error) {
 		return &ReviewLoopResult{
 			CompletedIterations: 1,
 			StopReason:          "single_iteration",
@@ -272,7 +273,7 @@ func TestRunReviewStep_PassesWhenReviewCycleHasNoValidIssues(t *testing.T) {
 	}
 
 	origReviewLoop := runReviewLoopWithDisplay
-	runReviewLoopWithDisplay = func(ctx context.Context, eng engine.Engine, display *engine.Display, baseBranch string, requestedIterations int) (*ReviewLoopResult, error) {
+	runReviewLoopWithDisplay = func(ctx context.Context, eng engine.Engine, display *engine.Display, dir, baseBranch string, requestedIterations int) (*ReviewLoopResult, error) {
 		return &ReviewLoopResult{
 			CompletedIterations: 1,
 			StopReason:          "single_iteration",
@@ -323,7 +324,7 @@ func TestRunReviewStep_RequiresConfiguredCleanStreak(t *testing.T) {
 
 	origReviewLoop := runReviewLoopWithDisplay
 	calls := 0
-	runReviewLoopWithDisplay = func(ctx context.Context, eng engine.Engine, display *engine.Display, baseBranch string, requestedIterations int) (*ReviewLoopResult, error) {
+	runReviewLoopWithDisplay = func(ctx context.Context, eng engine.Engine, display *engine.Display, dir, baseBranch string, requestedIterations int) (*ReviewLoopResult, error) {
 		calls++
 		return &ReviewLoopResult{
 			CompletedIterations: 1,
@@ -397,7 +398,7 @@ func TestRunReviewStep_CommitsReviewFixesBeforePassing(t *testing.T) {
 
 	origReviewLoop := runReviewLoopWithDisplay
 	calls := 0
-	runReviewLoopWithDisplay = func(ctx context.Context, eng engine.Engine, display *engine.Display, baseBranch string, requestedIterations int) (*ReviewLoopResult, error) {
+	runReviewLoopWithDisplay = func(ctx context.Context, eng engine.Engine, display *engine.Display, dir, baseBranch string, requestedIterations int) (*ReviewLoopResult, error) {
 		calls++
 		switch calls {
 		case 1:
@@ -489,7 +490,7 @@ func TestRunReviewStep_FinalizeReviewFixesFailure_BlocksGate(t *testing.T) {
 	}
 
 	origReviewLoop := runReviewLoopWithDisplay
-	runReviewLoopWithDisplay = func(ctx context.Context, eng engine.Engine, display *engine.Display, baseBranch string, requestedIterations int) (*ReviewLoopResult, error) {
+	runReviewLoopWithDisplay = func(ctx context.Context, eng engine.Engine, display *engine.Display, dir, baseBranch string, requestedIterations int) (*ReviewLoopResult, error) {
 		return &ReviewLoopResult{Iterations: []ReviewLoopIteration{{Iteration: 1, ValidIssues: 0, FixesApplied: 1}}}, nil
 	}
 	t.Cleanup(func() {
@@ -557,7 +558,7 @@ func TestRunReviewStep_FinalVerificationFailure_BlocksGate(t *testing.T) {
 	}
 
 	origReviewLoop := runReviewLoopWithDisplay
-	runReviewLoopWithDisplay = func(ctx context.Context, eng engine.Engine, display *engine.Display, baseBranch string, requestedIterations int) (*ReviewLoopResult, error) {
+	runReviewLoopWithDisplay = func(ctx context.Context, eng engine.Engine, display *engine.Display, dir, baseBranch string, requestedIterations int) (*ReviewLoopResult, error) {
 		return &ReviewLoopResult{Iterations: []ReviewLoopIteration{{Iteration: 1, ValidIssues: 0, FixesApplied: 0}}}, nil
 	}
 	t.Cleanup(func() {
@@ -591,6 +592,61 @@ func TestRunReviewStep_FinalVerificationFailure_BlocksGate(t *testing.T) {
 	}
 }
 
+func TestRunReviewStep_StandardsViolationsBlockGate(t *testing.T) {
+	dir := t.TempDir()
+	cfg := DefaultAutoConfig()
+	pipeline := NewPipeline(&cfg, runStepTestEngine{}, engine.NewDisplay(io.Discard), dir)
+	stubCleanReviewFinalVerification(t, pipeline, "hal/review-standards")
+
+	state := &PipelineState{
+		Step:       StepReview,
+		BaseBranch: "develop",
+		BranchName: "hal/review-standards",
+		StartedAt:  time.Now(),
+	}
+
+	var loopDir string
+	origReviewLoop := runReviewLoopWithDisplay
+	runReviewLoopWithDisplay = func(ctx context.Context, eng engine.Engine, display *engine.Display, repoDir, baseBranch string, requestedIterations int) (*ReviewLoopResult, error) {
+		loopDir = repoDir
+		return &ReviewLoopResult{Iterations: []ReviewLoopIteration{{Iteration: 1}}}, nil
+	}
+	t.Cleanup(func() { runReviewLoopWithDisplay = origReviewLoop })
+
+	var gotDir, gotBase string
+	origCheck := standardsCheckFn
+	standardsCheckFn = func(repoDir, base string) ([]standards.Violation, error) {
+		gotDir, gotBase = repoDir, base
+		return []standards.Violation{{Standard: "go/errors", Rule: "forbid", File: "api.go", Line: 3, Message: "use fmt.Errorf"}}, nil
+	}
+	t.Cleanup(func() { standardsCheckFn = origCheck })
+
+	err := pipeline.runReviewStep(context.Background(), state, RunOptions{})
+	if err == nil || !strings.Contains(err.Error(), "standards check found 1 violation") {
+		t.Fatalf("err = %v, want standards violation to block the gate", err)
+	}
+	if gotDir != dir || gotBase != "develop" {
+		t.Fatalf("standards check ran in %q against %q, want %q against develop", gotDir, gotBase, dir)
+	}
+	if loopDir != dir {
+		t.Fatalf("review loop ran in %q, want %q", loopDir, dir)
+	}
+
+	checks := pipeline.reviewVerificationChecks(state)
+	var found bool
+	for _, check := range checks {
+		if check.Name == "standards" {
+			found = true
+			if check.OK || !strings.Contains(check.Output, "api.go:3") {
+				t.Fatalf("standards check = %+v, want failing with the violation", check)
+			}
+		}
+	}
+	if !found {
+		t.Fatalf("verification checks = %+v, want a standards check", checks)
+	}
+}
+
 func stubCleanReviewFinalVerification(t *testing.T, pipeline *Pipeline, branch string) {
 	t.Helper()
 	origChanges := workingTreeChangesInDirFn
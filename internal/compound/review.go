@@ -25,6 +25,7 @@ type reviewContext struct {
 	PRDJSONContent  string
 	AutoPRDContent  string
 	BranchName      string
+	CodebaseMap     string
 	Warnings        []string
 }
 
@@ -182,6 +183,9 @@ func gatherReviewContext(dir string) (*reviewContext, error) {
 		return nil, fmt.Errorf("nothing to review: no progress log, commits, diff, or PRD found")
 	}
 
+	// The codebase map is background only; it never makes a session reviewable.
+	rc.CodebaseMap = codebaseMap(dir)
+
 	return rc, nil
 }
 
@@ -245,6 +249,12 @@ func buildReviewPrompt(rc *reviewContext, verification []VerificationCheck) stri
 		sb.WriteString("\n```\n\n")
 	}
 
+	if rc.CodebaseMap != "" {
+		sb.WriteString("### Codebase Map\n")
+		sb.WriteString(rc.CodebaseMap)
+		sb.WriteString("\n")
+	}
+
 	if len(rc.Warnings) > 0 {
 		sb.WriteString("### Notes\n")
 		for _, w := range rc.Warnings {
@@ -16,6 +16,7 @@ import (
 
 	"github.com/jywlabs/hal/internal/engine"
 	"github.com/jywlabs/hal/internal/skills"
+	"github.com/jywlabs/hal/internal/standards"
 )
 
 const (
@@ -54,11 +55,17 @@ func RunReviewLoop(ctx context.Context, eng engine.Engine, baseBranch string, re
 // RunReviewLoopWithDisplay executes the review loop with an optional display.
 // When display is provided, engine stream events are rendered through it.
 func RunReviewLoopWithDisplay(ctx context.Context, eng engine.Engine, display *engine.Display, baseBranch string, requestedIterations int) (*ReviewLoopResult, error) {
+	return runReviewLoopInDir(ctx, eng, display, "", baseBranch, requestedIterations)
+}
+
+// runReviewLoopInDir executes the review loop with hal standards check run
+// against the repository in dir ("" for the working directory).
+func runReviewLoopInDir(ctx context.Context, eng engine.Engine, display *engine.Display, dir, baseBranch string, requestedIterations int) (*ReviewLoopResult, error) {
 	if eng == nil {
 		return nil, fmt.Errorf("engine is required")
 	}
 
-	return runReviewLoop(ctx, baseBranch, requestedIterations, newReviewIterationDeps(eng, display))
+	return runReviewLoop(ctx, baseBranch, requestedIterations, newReviewIterationDeps(eng, display, dir))
 }
 
 // RunCodexReviewLoop is kept for compatibility with older callers.
@@ -79,7 +86,7 @@ func RunReviewIterationWithDisplay(ctx context.Context, eng engine.Engine, displ
 		return nil, fmt.Errorf("engine is required")
 	}
 
-	return runSingleReviewIteration(ctx, baseBranch, requestedIterations, newReviewIterationDeps(eng, display))
+	return runSingleReviewIteration(ctx, baseBranch, requestedIterations, newReviewIterationDeps(eng, display, ""))
 }
 
 // RunSingleReviewIteration is kept for compatibility with older callers.
@@ -87,8 +94,9 @@ func RunSingleReviewIteration(ctx context.Context, eng engine.Engine, baseBranch
 	return RunReviewIteration(ctx, eng, baseBranch, requestedIterations)
 }
 
-func newReviewIterationDeps(eng engine.Engine, display *engine.Display) reviewIterationDeps {
+func newReviewIterationDeps(eng engine.Engine, display *engine.Display, dir string) reviewIterationDeps {
 	deps := reviewIterationDeps{
+		dir:           dir,
 		now:           time.Now,
 		currentBranch: CurrentBranch,
 		branchContext: gitReviewBranchContext,
@@ -97,8 +105,9 @@ func newReviewIterationDeps(eng engine.Engine, display *engine.Display) reviewIt
 			// event handling behavior consistent with other command flows.
 			return eng.StreamPrompt(ctx, prompt, display)
 		},
-		maxRetries: reviewPromptMaxRetries,
-		retryDelay: reviewPromptBaseBackoff,
+		standardsIssues: standardsReviewIssues,
+		maxRetries:      reviewPromptMaxRetries,
+		retryDelay:      reviewPromptBaseBackoff,
 	}
 
 	if display != nil {
@@ -114,10 +123,12 @@ func newReviewIterationDeps(eng engine.Engine, display *engine.Display) reviewIt
 }
 
 type reviewIterationDeps struct {
+	dir                 string // Repository for hal standards check; "" is the working directory
 	now                 func() time.Time
 	currentBranch       func() (string, error)
 	branchContext       func(baseBranch, currentBranch string) (reviewBranchContext, error)
 	prompt              func(ctx context.Context, prompt string) (string, error)
+	standardsIssues     func(dir, baseBranch string) ([]reviewLoopIssue, error)
 	sleep               func(ctx context.Context, d time.Duration) error
 	onIterationStart    func(current, max int)
 	onIterationComplete func(current int)
@@ -156,6 +167,9 @@ type reviewLoopIssue struct {
 	Line         int    `json:"line"`
 	Rationale    string `json:"rationale"`
 	SuggestedFix string `json:"suggestedFix"`
+	// PreValidated issues come from hal standards check; they are always
+	// valid and only need fixing.
+	PreValidated bool `json:"preValidated,omitempty"`
 }
 
 type reviewLoopFixResponse struct {
@@ -330,6 +344,12 @@ func runReviewIteration(ctx context.Context, baseBranch, currentBranch string, d
 		return ReviewLoopIteration{}, fmt.Errorf("failed to parse review output: %w", err)
 	}
 
+	violations, err := deps.standardsIssues(deps.dir, baseBranch)
+	if err != nil {
+		return ReviewLoopIteration{}, fmt.Errorf("standards check failed: %w", err)
+	}
+	parsedReview.Issues = append(parsedReview.Issues, violations...)
+
 	issuesFound := len(parsedReview.Issues)
 	summary := strings.TrimSpace(parsedReview.Summary)
 	if summary == "" {
@@ -437,6 +457,9 @@ func normalizeReviewLoopDeps(baseBranch string, requestedIterations int, deps re
 	if deps.prompt == nil {
 		return "", deps, fmt.Errorf("prompt function is required")
 	}
+	if deps.standardsIssues == nil {
+		deps.standardsIssues = func(string, string) ([]reviewLoopIssue, error) { return nil, nil }
+	}
 	if deps.sleep == nil {
 		deps.sleep = sleepWithContext
 	}
@@ -696,6 +719,37 @@ func gitReviewBranchContext(baseBranch, currentBranch string) (reviewBranchConte
 	return ctx, nil
 }
 
+// standardsReviewIssues runs hal standards check in dir against baseBranch
+// and returns its violations as pre-validated review issues.
+func standardsReviewIssues(dir, baseBranch string) ([]reviewLoopIssue, error) {
+	if dir == "" {
+		dir = "."
+	}
+	violations, err := standardsCheckFn(dir, baseBranch)
+	if err != nil {
+		return nil, err
+	}
+	return violationIssues(violations), nil
+}
+
+// violationIssues converts standards violations into review issues.
+func violationIssues(violations []standards.Violation) []reviewLoopIssue {
+	issues := make([]reviewLoopIssue, 0, len(violations))
+	for i, v := range violations {
+		issues = append(issues, reviewLoopIssue{
+			ID:           fmt.Sprintf("STD-%03d", i+1),
+			Title:        fmt.Sprintf("Standard %s: %s", v.Standard, v.Message),
+			Severity:     "high",
+			File:         v.File,
+			Line:         v.Line,
+			Rationale:    fmt.Sprintf("hal standards check rule %q of .hal/standards/%s.md failed", v.Rule, v.Standard),
+			SuggestedFix: fmt.Sprintf("Change the code to follow .hal/standards/%s.md", v.Standard),
+			PreValidated: true,
+		})
+	}
+	return issues
+}
+
 func reviewLoopSkillPreamble() string {
 	content, err := skills.LoadSkill("review-loop")
 	if err != nil {
@@ -1108,6 +1162,7 @@ func buildReviewLoopFixPrompt(baseBranch, currentBranch string, issues []reviewL
 - Avoid broad or expensive commands (for example: avoid go test ./...).
 - Apply code changes only for valid issues.
 - Invalid issues must not be fixed.
+- Issues with "preValidated": true are violations found by hal standards check; they are valid, so fix them.
 - After applying fixes, run at most one focused check relevant to changed files/packages.
 - Do NOT ask for confirmation; apply fixes directly.
 - Return ONLY valid JSON (no markdown fences, no prose) with this schema:
@@ -1248,7 +1303,7 @@ func parseReviewLoopFixResponse(response string, reviewedIssues []reviewLoopIssu
 	}
 	for _, reviewed := range reviewedIssues {
 		fixIssue := fixByID[strings.TrimSpace(reviewed.ID)]
-		valid := fixIssue.Valid != nil && *fixIssue.Valid
+		valid := reviewed.PreValidated || (fixIssue.Valid != nil && *fixIssue.Valid)
 		fixed := valid && fixIssue.Fixed != nil && *fixIssue.Fixed
 
 		if valid {
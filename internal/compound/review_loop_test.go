@@ -7,6 +7,8 @@ import (
 	"testing"
 	"time"
 	"unicode/utf8"
+
+	"github.com/jywlabs/hal/internal/standards"
 )
 
 func testReviewBranchContext(baseBranch, currentBranch string) reviewBranchContext {
@@ -280,6 +282,55 @@ func TestRunReviewLoopStopsEarlyWhenNoValidIssues(t *testing.T) {
 	}
 }
 
+func TestRunSingleReviewIterationIncludesStandardsViolations(t *testing.T) {
+	var fixPrompt, standardsDir string
+	promptCalls := 0
+	deps := reviewIterationDeps{
+		dir:           "/repo",
+		currentBranch: func() (string, error) { return "hal/feature", nil },
+		branchContext: func(baseBranch, currentBranch string) (reviewBranchContext, error) {
+			return testReviewBranchContext(baseBranch, currentBranch), nil
+		},
+		standardsIssues: func(dir, baseBranch string) ([]reviewLoopIssue, error) {
+			standardsDir = dir
+			return violationIssues([]standards.Violation{{Standard: "go/errors", Rule: "forbid_import", File: "api.go", Line: 4, Message: "imports forbidden package github.com/pkg/errors"}}), nil
+		},
+		prompt: func(ctx context.Context, prompt string) (string, error) {
+			promptCalls++
+			switch promptCalls {
+			case 1:
+				return `{"summary":"Looks good","issues":[]}`, nil
+			case 2:
+				fixPrompt = prompt
+				// The engine disputes the violation; it stays valid.
+				return `{"summary":"Not an issue","issues":[{"id":"STD-001","valid":false,"reason":"disagree","fixed":false}]}`, nil
+			default:
+				t.Fatalf("prompt called %d times, want 2", promptCalls)
+				return "", nil
+			}
+		},
+	}
+
+	result, err := runSingleReviewIteration(context.Background(), "main", 1, deps)
+	if err != nil {
+		t.Fatalf("runSingleReviewIteration() unexpected error: %v", err)
+	}
+	if standardsDir != "/repo" {
+		t.Fatalf("standards check ran in %q, want /repo", standardsDir)
+	}
+
+	if !strings.Contains(fixPrompt, "STD-001") || !strings.Contains(fixPrompt, `"preValidated": true`) {
+		t.Fatalf("fix prompt missing pre-validated standards issue:\n%s", fixPrompt)
+	}
+	iteration := result.Iterations[0]
+	if iteration.IssuesFound != 1 || iteration.ValidIssues != 1 || iteration.InvalidIssues != 0 {
+		t.Fatalf("iteration = %+v, want the standards violation counted as valid", iteration)
+	}
+	if len(iteration.Issues) != 1 || iteration.Issues[0].File != "api.go" || !iteration.Issues[0].Valid {
+		t.Fatalf("issues = %+v", iteration.Issues)
+	}
+}
+
 func TestRunReviewLoopStopsAtMaxIterations(t *testing.T) {
 	branchContextCalls := 0
 	promptCalls := 0
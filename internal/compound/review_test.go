@@ -297,6 +297,7 @@ func TestBuildReviewPrompt(t *testing.T) {
 		GitDiff:         "diff content",
 		CommitHistory:   "commit history",
 		PRDContent:      "PRD content",
+		CodebaseMap:     "#### Stack\n\n- Go module\n",
 		Warnings:        []string{"Warning 1"},
 	}
 
@@ -313,6 +314,8 @@ func TestBuildReviewPrompt(t *testing.T) {
 		"commit history",
 		"### PRD Goals",
 		"PRD content",
+		"### Codebase Map",
+		"- Go module",
 		"### Notes",
 		"Warning 1",
 	}
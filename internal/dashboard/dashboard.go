@@ -0,0 +1,226 @@
+// Package dashboard collects hal's run, pipeline and sandbox state into a
+// snapshot and renders it for the hal dashboard terminal view.
+package dashboard
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jywlabs/hal/internal/compound"
+	"github.com/jywlabs/hal/internal/engine"
+	"github.com/jywlabs/hal/internal/runstatus"
+	"github.com/jywlabs/hal/internal/sandbox"
+	"github.com/jywlabs/hal/internal/status"
+	"github.com/jywlabs/hal/internal/template"
+)
+
+// Limits on the entries each pane shows.
+const (
+	maxCommits         = 5
+	maxProgressEntries = 5
+)
+
+// Options selects the optional parts of a snapshot.
+type Options struct {
+	Sandboxes bool // include the global sandbox registry
+}
+
+// Story is a PRD story as shown in the stories pane.
+type Story struct {
+	ID      string
+	Title   string
+	Passes  bool
+	Skipped bool
+	Current bool
+}
+
+// Snapshot is everything the dashboard shows at one point in time.
+type Snapshot struct {
+	Time         time.Time
+	Track        string
+	State        string
+	PRDFile      string
+	Branch       string
+	Stories      []Story
+	Completed    int
+	Total        int
+	Run          *runstatus.Status
+	Pipeline     *compound.PipelineState
+	Progress     []string
+	Commits      []string
+	LatestReport string
+	Sandboxes    []*sandbox.SandboxState
+	SandboxError string
+	ShowSandbox  bool
+}
+
+// recentCommits returns the latest commits of the repository in dir as
+// "<hash> <subject>" lines. Injectable for testing.
+var recentCommits = func(dir string, n int) []string {
+	cmd := exec.Command("git", "log", fmt.Sprintf("-n%d", n), "--format=%h %s")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	return nonEmptyLines(string(out))
+}
+
+// listSandboxes reads the global sandbox registry. Injectable for testing.
+var listSandboxes = sandbox.ListInstances
+
+// Collect reads the project state in dir. Missing files leave their part of
+// the snapshot empty.
+func Collect(dir string, opts Options) Snapshot {
+	halDir := filepath.Join(dir, template.HalDir)
+	st := status.Get(dir)
+	s := Snapshot{
+		Time:        time.Now(),
+		Track:       st.WorkflowTrack,
+		State:       st.State,
+		Pipeline:    compound.LoadPipelineState(dir),
+		ShowSandbox: opts.Sandboxes,
+	}
+	s.Run, _ = runstatus.Load(halDir)
+
+	s.PRDFile = activePRDFile(halDir, s.Run, s.Pipeline)
+	if prd, err := engine.LoadPRDFile(halDir, s.PRDFile); err == nil {
+		s.Branch = prd.BranchName
+		s.Completed, s.Total = prd.Progress()
+		current := prd.CurrentStory()
+		for _, stories := range [][]engine.UserStory{prd.UserStories, prd.Tasks} {
+			for _, us := range stories {
+				s.Stories = append(s.Stories, Story{
+					ID:      us.ID,
+					Title:   us.Title,
+					Passes:  us.Passes,
+					Skipped: us.Skipped,
+					Current: current != nil && current.ID == us.ID,
+				})
+			}
+		}
+	}
+
+	s.Progress = progressEntries(filepath.Join(halDir, template.ProgressFile), maxProgressEntries)
+	s.Commits = recentCommits(dir, maxCommits)
+	if report, err := compound.FindLatestReport(filepath.Join(halDir, "reports")); err == nil {
+		s.LatestReport = filepath.Base(report)
+	}
+
+	if opts.Sandboxes {
+		instances, err := listSandboxes()
+		if err != nil {
+			s.SandboxError = err.Error()
+		}
+		sort.Slice(instances, func(i, j int) bool { return instances[i].Name < instances[j].Name })
+		s.Sandboxes = instances
+	}
+	return s
+}
+
+// activePRDFile picks the PRD the dashboard follows: the one the live run
+// uses, auto-prd.json while a pipeline is in flight, else prd.json.
+func activePRDFile(halDir string, run *runstatus.Status, pipeline *compound.PipelineState) string {
+	if run != nil && run.State == runstatus.StateRunning && run.PRDFile != "" {
+		return run.PRDFile
+	}
+	if pipeline != nil && pipeline.Step != compound.StepDone {
+		if _, err := os.Stat(filepath.Join(halDir, template.AutoPRDFile)); err == nil {
+			return template.AutoPRDFile
+		}
+	}
+	if _, err := os.Stat(filepath.Join(halDir, template.PRDFile)); err != nil {
+		if _, err := os.Stat(filepath.Join(halDir, template.AutoPRDFile)); err == nil {
+			return template.AutoPRDFile
+		}
+	}
+	return template.PRDFile
+}
+
+// progressEntries returns the headings of the last n progress.txt entries,
+// newest first, without the leading "## ".
+func progressEntries(path string, n int) []string {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var headings []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "## ") {
+			headings = append(headings, strings.TrimPrefix(line, "## "))
+		}
+	}
+	var entries []string
+	for i := len(headings) - 1; i >= 0 && len(entries) < n; i-- {
+		entries = append(entries, headings[i])
+	}
+	return entries
+}
+
+// WatchedPaths returns the files whose changes trigger a dashboard refresh.
+func WatchedPaths(dir string, opts Options) []string {
+	halDir := filepath.Join(dir, template.HalDir)
+	paths := []string{
+		filepath.Join(halDir, template.PRDFile),
+		filepath.Join(halDir, template.AutoPRDFile),
+		filepath.Join(halDir, template.AutoStateFile),
+		filepath.Join(halDir, template.ProgressFile),
+		filepath.Join(halDir, template.RunStatusFile),
+		filepath.Join(halDir, "reports"),
+		filepath.Join(dir, ".git", "logs", "HEAD"),
+	}
+	if opts.Sandboxes {
+		if sandboxesDir := sandbox.SandboxesDir(); sandboxesDir != "" {
+			paths = append(paths, sandboxesDir)
+		}
+	}
+	return paths
+}
+
+// Fingerprint summarizes the size and modification time of paths. It
+// changes whenever one of the files, or an entry of a directory, changes.
+func Fingerprint(paths []string) string {
+	var b strings.Builder
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			b.WriteString("-;")
+			continue
+		}
+		fmt.Fprintf(&b, "%d:%d;", info.Size(), info.ModTime().UnixNano())
+		if !info.IsDir() {
+			continue
+		}
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entryInfo, err := entry.Info(); err == nil {
+				fmt.Fprintf(&b, "%s:%d:%d,", entry.Name(), entryInfo.Size(), entryInfo.ModTime().UnixNano())
+			}
+		}
+		b.WriteString(";")
+	}
+	return b.String()
+}
+
+func nonEmptyLines(s string) []string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,154 @@
+package dashboard
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jywlabs/hal/internal/sandbox"
+	"github.com/jywlabs/hal/internal/template"
+)
+
+func writeHalFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, template.HalDir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func stubCollectors(t *testing.T, commits []string, sandboxes []*sandbox.SandboxState) {
+	t.Helper()
+	origCommits, origSandboxes := recentCommits, listSandboxes
+	recentCommits = func(string, int) []string { return commits }
+	listSandboxes = func() ([]*sandbox.SandboxState, error) { return sandboxes, nil }
+	t.Cleanup(func() { recentCommits, listSandboxes = origCommits, origSandboxes })
+}
+
+const testPRD = `{
+  "project": "demo",
+  "branchName": "hal/demo",
+  "userStories": [
+    {"id": "US-001", "title": "Schema", "priority": 1, "passes": true},
+    {"id": "US-002", "title": "Endpoint", "priority": 2, "passes": false},
+    {"id": "US-003", "title": "Docs", "priority": 3, "passes": false, "skipped": true}
+  ]
+}`
+
+func TestCollect(t *testing.T) {
+	dir := t.TempDir()
+	stubCollectors(t, []string{"abc1234 Add schema"}, []*sandbox.SandboxState{{Name: "b"}, {Name: "a"}})
+	writeHalFile(t, dir, template.PRDFile, testPRD)
+	writeHalFile(t, dir, template.ProgressFile, "## 2026-10-01 10:00 - US-001\n- done\n---\n## 2026-10-01 11:00 - US-002\n- wip\n---\n")
+	writeHalFile(t, dir, template.RunStatusFile, `{"state":"running","engine":"codex","prdFile":"prd.json","iteration":2,"maxIterations":10,"storyId":"US-002","tokens":1500}`)
+	writeHalFile(t, dir, template.AutoStateFile, `{"step":"review","branchName":"hal/demo","review":{"status":"passed"}}`)
+
+	s := Collect(dir, Options{Sandboxes: true})
+
+	if s.PRDFile != template.PRDFile || s.Completed != 1 || s.Total != 2 {
+		t.Fatalf("prd = %s %d/%d, want prd.json 1/2", s.PRDFile, s.Completed, s.Total)
+	}
+	if len(s.Stories) != 3 || !s.Stories[1].Current || s.Stories[0].Current {
+		t.Fatalf("stories = %+v, want US-002 current", s.Stories)
+	}
+	if !s.Stories[2].Skipped {
+		t.Fatalf("US-003 should be skipped")
+	}
+	if s.Run == nil || s.Run.Engine != "codex" || s.Run.Tokens != 1500 {
+		t.Fatalf("run = %+v", s.Run)
+	}
+	if s.Pipeline == nil || s.Pipeline.Step != "review" || s.Pipeline.Review.Status != "passed" {
+		t.Fatalf("pipeline = %+v", s.Pipeline)
+	}
+	if got := strings.Join(s.Progress, "|"); got != "2026-10-01 11:00 - US-002|2026-10-01 10:00 - US-001" {
+		t.Fatalf("progress = %q, want newest first", got)
+	}
+	if len(s.Commits) != 1 {
+		t.Fatalf("commits = %v", s.Commits)
+	}
+	if len(s.Sandboxes) != 2 || s.Sandboxes[0].Name != "a" {
+		t.Fatalf("sandboxes = %v, want sorted by name", s.Sandboxes)
+	}
+}
+
+func TestCollect_FollowsAutoPRDDuringPipeline(t *testing.T) {
+	dir := t.TempDir()
+	stubCollectors(t, nil, nil)
+	writeHalFile(t, dir, template.PRDFile, testPRD)
+	writeHalFile(t, dir, template.AutoPRDFile, `{"branchName":"hal/auto","userStories":[{"id":"T-001","title":"Auto","priority":1}]}`)
+	writeHalFile(t, dir, template.AutoStateFile, `{"step":"run","branchName":"hal/auto"}`)
+
+	s := Collect(dir, Options{})
+
+	if s.PRDFile != template.AutoPRDFile || s.Branch != "hal/auto" {
+		t.Fatalf("prd = %s on %s, want auto-prd.json on hal/auto", s.PRDFile, s.Branch)
+	}
+	if s.Sandboxes != nil {
+		t.Fatal("sandboxes should not be listed without the option")
+	}
+}
+
+func TestRender(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+	stubCollectors(t, []string{"abc1234 Add schema"}, []*sandbox.SandboxState{{Name: "box", Provider: "hetzner", Status: "running", CreatedAt: now.Add(-time.Hour)}})
+	writeHalFile(t, dir, template.PRDFile, testPRD)
+	writeHalFile(t, dir, template.RunStatusFile, `{"state":"running","engine":"codex","prdFile":"prd.json","iteration":2,"maxIterations":10,"storyId":"US-002","storyTitle":"Endpoint","tokens":12300,"startedAt":"2026-10-18T11:45:00Z","updatedAt":"2026-10-18T11:59:50Z"}`)
+	writeHalFile(t, dir, template.AutoStateFile, `{"step":"ci","ci":{"status":"failing","fixAttempts":1}}`)
+
+	s := Collect(dir, Options{Sandboxes: true})
+	s.Time = now
+
+	for _, width := range []int{80, 140} {
+		frame := Render(s, width, 40)
+		for _, want := range []string{"hal dashboard", "US-001", "US-002", "(skipped)", "1/2 complete", "2/10", "12.3k", "failing (1 fix attempts)", "15m00s", "10s ago", "abc1234", "box", "hetzner"} {
+			if !strings.Contains(frame, want) {
+				t.Errorf("width %d: frame missing %q:\n%s", width, want, frame)
+			}
+		}
+	}
+}
+
+func TestStoryLines_KeepsCurrentStoryInView(t *testing.T) {
+	s := Snapshot{PRDFile: template.PRDFile}
+	for i := 1; i <= 20; i++ {
+		s.Stories = append(s.Stories, Story{ID: fmt.Sprintf("US-%03d", i), Passes: i < 15, Current: i == 15})
+	}
+
+	got := strings.Join(storyLines(s, 5), "\n")
+
+	for _, want := range []string{"12 earlier", "US-013", "US-015", "US-017", "3 more"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("story lines missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "US-012") || strings.Contains(got, "US-018") {
+		t.Errorf("story lines show stories outside the window:\n%s", got)
+	}
+}
+
+func TestFingerprint_ChangesWithWatchedFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeHalFile(t, dir, template.ProgressFile, "")
+	paths := WatchedPaths(dir, Options{})
+
+	before := Fingerprint(paths)
+	if again := Fingerprint(paths); again != before {
+		t.Fatal("fingerprint changed without file changes")
+	}
+	writeHalFile(t, dir, template.ProgressFile, "## entry\n")
+	if after := Fingerprint(paths); after == before {
+		t.Fatal("fingerprint did not change after progress.txt changed")
+	}
+	before = Fingerprint(paths)
+	writeHalFile(t, dir, filepath.Join("reports", "review.md"), "report")
+	if after := Fingerprint(paths); after == before {
+		t.Fatal("fingerprint did not change after a report was added")
+	}
+}
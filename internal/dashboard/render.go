@@ -0,0 +1,333 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jywlabs/hal/internal/runstatus"
+	"github.com/jywlabs/hal/internal/sandbox"
+	"github.com/jywlabs/hal/internal/ui"
+)
+
+// twoColumnWidth is the terminal width from which panes sit side by side.
+const twoColumnWidth = 100
+
+// minStoryRows is the fewest stories the stories pane shows.
+const minStoryRows = 5
+
+// Render draws s as a full-screen frame of the given terminal size. A height
+// of zero shows every story.
+func Render(s Snapshot, width, height int) string {
+	if width <= 0 {
+		width = 80
+	}
+
+	header := renderHeader(s, width)
+
+	var body string
+	if width >= twoColumnWidth {
+		left := width / 2
+		right := width - left
+		body = lipgloss.JoinHorizontal(lipgloss.Top,
+			lipgloss.JoinVertical(lipgloss.Left,
+				pane("Stories", storyLines(s, storyRows(height)), left),
+				pane("Progress", progressLines(s), left),
+			),
+			lipgloss.JoinVertical(lipgloss.Left,
+				pane("Run", runLines(s), right),
+				pane("Pipeline", pipelineLines(s), right),
+				pane("Commits", commitLines(s), right),
+			),
+		)
+	} else {
+		body = lipgloss.JoinVertical(lipgloss.Left,
+			pane("Stories", storyLines(s, storyRows(height)), width),
+			pane("Run", runLines(s), width),
+			pane("Pipeline", pipelineLines(s), width),
+			pane("Progress", progressLines(s), width),
+			pane("Commits", commitLines(s), width),
+		)
+	}
+	if s.ShowSandbox {
+		body = lipgloss.JoinVertical(lipgloss.Left, body, pane("Sandboxes", sandboxLines(s), width))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, header, body)
+}
+
+func renderHeader(s Snapshot, width int) string {
+	title := ui.StyleCommandIcon.String() + " " + ui.StyleTitle.Render("hal dashboard")
+	details := []string{}
+	if s.Track != "" {
+		details = append(details, s.Track)
+	}
+	if s.State != "" {
+		details = append(details, s.State)
+	}
+	if s.Branch != "" {
+		details = append(details, s.Branch)
+	}
+	left := title
+	if len(details) > 0 {
+		left += "  " + ui.StyleMuted.Render(strings.Join(details, " · "))
+	}
+	clock := ui.StyleMuted.Render(s.Time.Format("15:04:05"))
+	gap := width - lipgloss.Width(left) - lipgloss.Width(clock)
+	if gap < 1 {
+		gap = 1
+	}
+	return left + strings.Repeat(" ", gap) + clock
+}
+
+// pane draws lines in a titled box width columns wide.
+func pane(title string, lines []string, width int) string {
+	inner := width - 4
+	if inner < 10 {
+		inner = 10
+	}
+	clip := lipgloss.NewStyle().MaxWidth(inner)
+	rendered := []string{ui.StyleBold.Render(title)}
+	for _, line := range lines {
+		rendered = append(rendered, clip.Render(line))
+	}
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.ColorMuted).
+		Padding(0, 1).
+		Width(width - 2).
+		Render(strings.Join(rendered, "\n"))
+}
+
+// storyRows returns how many stories fit next to the other panes.
+func storyRows(height int) int {
+	if height <= 0 {
+		return 0
+	}
+	rows := height - 16
+	if rows < minStoryRows {
+		rows = minStoryRows
+	}
+	return rows
+}
+
+func storyLines(s Snapshot, rows int) []string {
+	if len(s.Stories) == 0 {
+		return []string{ui.StyleMuted.Render("No stories in " + s.PRDFile + ". Create one with hal plan and hal convert.")}
+	}
+	lines := []string{progressBar(s.Completed, s.Total) + ui.StyleMuted.Render(fmt.Sprintf(" %d/%d complete · %s", s.Completed, s.Total, s.PRDFile))}
+
+	start, end := 0, len(s.Stories)
+	if rows > 0 && end > rows {
+		// Keep the current story in view with two finished ones above it.
+		for i, story := range s.Stories {
+			if story.Current {
+				start = i - 2
+				break
+			}
+		}
+		if start < 0 {
+			start = 0
+		}
+		if start > len(s.Stories)-rows {
+			start = len(s.Stories) - rows
+		}
+		end = start + rows
+	}
+	if start > 0 {
+		lines = append(lines, ui.StyleMuted.Render(fmt.Sprintf("  … %d earlier", start)))
+	}
+	for _, story := range s.Stories[start:end] {
+		lines = append(lines, storyLine(story))
+	}
+	if end < len(s.Stories) {
+		lines = append(lines, ui.StyleMuted.Render(fmt.Sprintf("  … %d more", len(s.Stories)-end)))
+	}
+	return lines
+}
+
+func storyLine(story Story) string {
+	switch {
+	case story.Passes:
+		return ui.StyleSuccess.Render("✓ ") + story.ID + " " + ui.StyleMuted.Render(story.Title)
+	case story.Skipped:
+		return ui.StyleMuted.Render("⊘ " + story.ID + " " + story.Title + " (skipped)")
+	case story.Current:
+		return ui.StyleInfo.Render("▸ "+story.ID) + " " + ui.StyleBold.Render(story.Title)
+	default:
+		return ui.StyleMuted.Render("· ") + story.ID + " " + story.Title
+	}
+}
+
+func progressBar(done, total int) string {
+	const width = 20
+	filled := 0
+	if total > 0 {
+		filled = done * width / total
+	}
+	return ui.StyleProgressFilled.Render(strings.Repeat("█", filled)) +
+		ui.StyleProgressEmpty.Render(strings.Repeat("░", width-filled))
+}
+
+func runLines(s Snapshot) []string {
+	run := s.Run
+	if run == nil {
+		return []string{ui.StyleMuted.Render("No run recorded. Start one with hal run.")}
+	}
+	end := s.Time
+	if run.State != runstatus.StateRunning {
+		end = run.UpdatedAt
+	}
+	lines := []string{
+		label("State") + runStateStyle(run.State).Render(run.State),
+		label("Engine") + run.Engine,
+		label("Iteration") + fmt.Sprintf("%d/%d", run.Iteration, run.MaxIterations),
+	}
+	if run.StoryID != "" {
+		lines = append(lines, label("Story")+run.StoryID+" "+ui.StyleMuted.Render(run.StoryTitle))
+	}
+	lines = append(lines, label("Tokens")+formatTokens(run.Tokens))
+	if !run.StartedAt.IsZero() {
+		lines = append(lines, label("Elapsed")+formatDuration(end.Sub(run.StartedAt)))
+	}
+	if !run.UpdatedAt.IsZero() {
+		lines = append(lines, label("Updated")+formatDuration(s.Time.Sub(run.UpdatedAt))+" ago")
+	}
+	if run.Error != "" {
+		lines = append(lines, ui.StyleError.Render(run.Error))
+	}
+	return lines
+}
+
+func runStateStyle(state string) lipgloss.Style {
+	switch state {
+	case runstatus.StateRunning:
+		return ui.StyleInfo
+	case runstatus.StateComplete:
+		return ui.StyleSuccess
+	case runstatus.StateFailed:
+		return ui.StyleError
+	default:
+		return ui.StyleWarning
+	}
+}
+
+func pipelineLines(s Snapshot) []string {
+	p := s.Pipeline
+	var lines []string
+	if p == nil {
+		lines = append(lines, ui.StyleMuted.Render("No auto pipeline state."))
+	} else {
+		lines = append(lines, label("Step")+ui.StyleInfo.Render(p.Step))
+		if p.BranchName != "" {
+			lines = append(lines, label("Branch")+p.BranchName)
+		}
+		if p.Run != nil {
+			run := fmt.Sprintf("%d iterations", p.Run.Iterations)
+			if p.Run.Complete {
+				run += ", complete"
+			}
+			lines = append(lines, label("Run")+run)
+		}
+		review := "pending"
+		if p.Review != nil && p.Review.Status != "" {
+			review = p.Review.Status
+		}
+		lines = append(lines, label("Review")+review)
+		ci := "pending"
+		if p.CI != nil && p.CI.Status != "" {
+			ci = p.CI.Status
+			if p.CI.FixAttempts > 0 {
+				ci += fmt.Sprintf(" (%d fix attempts)", p.CI.FixAttempts)
+			}
+			if p.CI.Reason != "" {
+				ci += " · " + p.CI.Reason
+			}
+		}
+		lines = append(lines, label("CI")+ci)
+	}
+	if s.LatestReport != "" {
+		lines = append(lines, label("Report")+s.LatestReport)
+	}
+	return lines
+}
+
+func progressLines(s Snapshot) []string {
+	if len(s.Progress) == 0 {
+		return []string{ui.StyleMuted.Render("No progress entries yet.")}
+	}
+	return s.Progress
+}
+
+func commitLines(s Snapshot) []string {
+	if len(s.Commits) == 0 {
+		return []string{ui.StyleMuted.Render("No commits.")}
+	}
+	lines := make([]string, len(s.Commits))
+	for i, c := range s.Commits {
+		hash, subject, _ := strings.Cut(c, " ")
+		lines[i] = ui.StyleAccent.Render(hash) + " " + subject
+	}
+	return lines
+}
+
+func sandboxLines(s Snapshot) []string {
+	var lines []string
+	if s.SandboxError != "" {
+		lines = append(lines, ui.StyleError.Render(s.SandboxError))
+	}
+	if len(s.Sandboxes) == 0 {
+		return append(lines, ui.StyleMuted.Render("No sandboxes. Create one with hal sandbox create."))
+	}
+	for _, sb := range s.Sandboxes {
+		lines = append(lines, fmt.Sprintf("%s  %s  %s  %s",
+			sb.Name,
+			ui.StyleMuted.Render(sb.Provider),
+			sandboxStatusStyle(sb.Status).Render(sb.Status),
+			ui.StyleMuted.Render(formatDuration(s.Time.Sub(sb.CreatedAt))+" old"),
+		))
+	}
+	return lines
+}
+
+func sandboxStatusStyle(status string) lipgloss.Style {
+	switch status {
+	case sandbox.StatusRunning:
+		return ui.StyleSuccess
+	case sandbox.StatusStopped:
+		return ui.StyleMuted
+	default:
+		return ui.StyleWarning
+	}
+}
+
+func label(name string) string {
+	return ui.StyleMuted.Render(fmt.Sprintf("%-11s", name+":"))
+}
+
+func formatTokens(n int) string {
+	switch {
+	case n >= 1000000:
+		return fmt.Sprintf("%.1fM", float64(n)/1000000)
+	case n >= 1000:
+		return fmt.Sprintf("%.1fk", float64(n)/1000)
+	default:
+		return fmt.Sprintf("%d", n)
+	}
+}
+
+func formatDuration(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"strings"
 
 	"github.com/jywlabs/hal/internal/ci"
+	"github.com/jywlabs/hal/internal/engine"
 	"github.com/jywlabs/hal/internal/skills"
 	"github.com/jywlabs/hal/internal/template"
 	"gopkg.in/yaml.v3"
@@ -45,6 +46,7 @@ const (
 	RemediationRunHalInit        = "run_hal_init"
 	RemediationRefreshCodexLinks = "refresh_codex_links"
 	RemediationRunGHAuthLogin    = "run_gh_auth_login"
+	RemediationUpgradeSkills     = "upgrade_skills"
 )
 
 // DoctorResult is the v1 machine-readable doctor contract.
@@ -99,9 +101,21 @@ type Remediation struct {
 type Options struct {
 	// Dir is the project root directory.
 	Dir string
-	// Engine is the configured default engine name (e.g., "codex", "claude", "pi").
+	// Engine is the configured default engine name (e.g., "codex", "claude", "gemini", "pi").
 	// When non-empty, engine-specific checks are scoped appropriately.
 	Engine string
+	// Deep runs a minimal prompt through each engine in Engines and reports
+	// version, auth, model and streaming checks. Results are cached for ProbeTTL.
+	Deep bool
+	// Refresh ignores cached probe results when Deep is set.
+	Refresh bool
+	// CachedProbes reports fresh cached probe results without probing.
+	// Cached failures are reported as warnings.
+	CachedProbes bool
+	// Engines lists the engines to probe. Defaults to Engine.
+	Engines []string
+	// EngineConfig returns the configuration for an engine, or nil.
+	EngineConfig func(name string) *engine.EngineConfig
 }
 
 // Run inspects the environment and returns a DoctorResult.
@@ -205,41 +219,61 @@ func Run(opts Options) DoctorResult {
 		failures = append(failures, "hal_commands")
 	}
 
-	// 11. Engine-local skill links (.claude/skills, .pi/skills)
+	// 11. Installed skills match this hal's versions
+	skillsCurrentCheck := checkSkillsCurrent(dir)
+	checks = append(checks, skillsCurrentCheck)
+	if skillsCurrentCheck.Status == StatusWarn {
+		warnings = append(warnings, "skills_current")
+	}
+
+	// 12. Engine-local skill links (.claude/skills, .gemini/skills, .pi/skills)
 	localLinksCheck := checkLocalSkillLinks(dir)
 	checks = append(checks, localLinksCheck)
 	if localLinksCheck.Status == StatusWarn {
 		warnings = append(warnings, "local_skill_links")
 	}
 
-	// 12. Codex global links (only applicable for codex engine)
+	// 13. Codex global links (only applicable for codex engine)
 	codexCheck := checkCodexLinks(dir, engine)
 	checks = append(checks, codexCheck)
 	if codexCheck.Status == StatusWarn {
 		warnings = append(warnings, "codex_global_links")
 	}
 
-	// 13. Legacy migration debris
+	// 14. Legacy migration debris
 	legacyCheck := checkLegacyDebris(dir)
 	checks = append(checks, legacyCheck)
 	if legacyCheck.Status == StatusWarn {
 		warnings = append(warnings, "legacy_debris")
 	}
 
-	// 14. Legacy sandbox state (.hal/sandbox.json)
+	// 15. Legacy sandbox state (.hal/sandbox.json)
 	legacySandboxCheck := checkLegacySandboxState(halDir)
 	checks = append(checks, legacySandboxCheck)
 	if legacySandboxCheck.Status == StatusWarn {
 		warnings = append(warnings, "legacy_sandbox_state")
 	}
 
-	// 15. Broken symlinks in engine skill directories
+	// 16. Broken symlinks in engine skill directories
 	brokenCheck := checkBrokenSkillLinks(dir)
 	checks = append(checks, brokenCheck)
 	if brokenCheck.Status == StatusWarn {
 		warnings = append(warnings, "broken_skill_links")
 	}
 
+	// 17. Engine readiness probes (hal doctor --deep, or cached results)
+	if opts.Deep || opts.CachedProbes {
+		for _, c := range engineProbeChecks(halDir, opts, engine, opts.Deep, defaultProbeDeps) {
+			checks = append(checks, c)
+			switch c.Status {
+			case StatusFail:
+				failures = append(failures, c.ID)
+			case StatusWarn:
+				warnings = append(warnings, c.ID)
+			}
+		}
+	}
+
 	// Determine overall status
 	overall := StatusPass
 	if len(failures) > 0 {
@@ -265,7 +299,7 @@ func Run(opts Options) DoctorResult {
 		case "default_engine_cli":
 			c.Scope = ScopeRepo
 			c.Applicability = ApplicabilityRequired
-		case "prd_json":
+		case "prd_json", "skills_current":
 			c.Scope = ScopeRepo
 			c.Applicability = ApplicabilityOptional
 		case "local_skill_links":
@@ -297,6 +331,8 @@ func Run(opts Options) DoctorResult {
 	if overall == StatusFail {
 		if len(failures) == 1 && failures[0] == "hal_dir" {
 			summary = "Hal is not initialized. Run hal init."
+		} else if probeFailures := probeCheckIDs(failures, checks); len(probeFailures) == len(failures) {
+			summary = "Hal is not ready yet: " + probeSummaryPart(probeFailures, checks) + "."
 		} else {
 			summary = "Hal is not ready yet: run hal init."
 		}
@@ -366,7 +402,12 @@ func warningSummaryPart(warningID string, checks []Check) string {
 		return "run hal links clean"
 	case "local_skill_links":
 		return "run hal links refresh"
+	case "skills_current":
+		return "run hal skills upgrade"
 	default:
+		if ids := probeCheckIDs([]string{warningID}, checks); len(ids) == 1 {
+			return probeSummaryPart(ids, checks)
+		}
 		return warningID
 	}
 }
@@ -715,6 +756,74 @@ func checkCommands(dir string) Check {
 	}
 }
 
+// checkSkillsCurrent warns when installed skills or commands lag behind the
+// versions embedded in this hal. Files without a recorded version (installed
+// before hal kept a skills lock) are only mentioned: hal cannot tell whether
+// they are outdated or edited.
+func checkSkillsCurrent(dir string) Check {
+	statuses, err := skills.Status(dir)
+	if err != nil {
+		return Check{
+			ID:            "skills_current",
+			Status:        StatusWarn,
+			Severity:      SeverityWarn,
+			RemediationID: RemediationNone,
+			Message:       fmt.Sprintf("Could not compare installed skills with this hal: %v.", err),
+		}
+	}
+
+	var outdated, diverged []string
+	untracked := 0
+	for _, s := range statuses {
+		switch s.State {
+		case skills.StateOutdated:
+			outdated = appendName(outdated, s.Name)
+		case skills.StateDiverged:
+			diverged = appendName(diverged, s.Name)
+		case skills.StateUntracked:
+			untracked++
+		}
+	}
+
+	if len(outdated)+len(diverged) == 0 {
+		message := "Installed skills and commands match this hal version."
+		if untracked > 0 {
+			message = fmt.Sprintf("%d installed skill file(s) have no recorded version; see hal skills status.", untracked)
+		}
+		return Check{
+			ID:            "skills_current",
+			Status:        StatusPass,
+			Severity:      SeverityInfo,
+			RemediationID: RemediationNone,
+			Message:       message,
+		}
+	}
+
+	var parts []string
+	if len(outdated) > 0 {
+		parts = append(parts, "outdated: "+strings.Join(outdated, ", "))
+	}
+	if len(diverged) > 0 {
+		parts = append(parts, "edited locally: "+strings.Join(diverged, ", "))
+	}
+	return Check{
+		ID:            "skills_current",
+		Status:        StatusWarn,
+		Severity:      SeverityWarn,
+		RemediationID: RemediationUpgradeSkills,
+		Message:       "Newer skill versions are available (" + strings.Join(parts, "; ") + ").",
+		Remediation:   &Remediation{Command: "hal skills upgrade", Safe: false},
+	}
+}
+
+// appendName appends name unless it is already the last element.
+func appendName(names []string, name string) []string {
+	if len(names) > 0 && names[len(names)-1] == name {
+		return names
+	}
+	return append(names, name)
+}
+
 func checkCodexLinks(dir, engine string) Check {
 	// Skip Codex link check if engine is not codex
 	if engine != "codex" {
@@ -952,7 +1061,7 @@ func checkPromptMD(halDir string) Check {
 }
 
 func checkLocalSkillLinks(dir string) Check {
-	// Check that .claude/skills/ and .pi/skills/ have correct symlinks to .hal/skills/
+	// Check that .claude/skills/, .gemini/skills/ and .pi/skills/ have correct symlinks to .hal/skills/
 	type engineDir struct {
 		name      string
 		skillsDir string
@@ -960,6 +1069,7 @@ func checkLocalSkillLinks(dir string) Check {
 	}
 	engineDirs := []engineDir{
 		{name: "claude", skillsDir: filepath.Join(dir, ".claude", "skills"), prefix: filepath.Join("..", "..", template.HalDir, "skills")},
+		{name: "gemini", skillsDir: filepath.Join(dir, ".gemini", "skills"), prefix: filepath.Join("..", "..", template.HalDir, "skills")},
 		{name: "pi", skillsDir: filepath.Join(dir, ".pi", "skills"), prefix: filepath.Join("..", "..", template.HalDir, "skills")},
 	}
 
@@ -1035,6 +1145,7 @@ func checkBrokenSkillLinks(dir string) Check {
 	// Check project-local engine skill directories for broken symlinks
 	engineDirs := []string{
 		filepath.Join(dir, ".claude", "skills"),
+		filepath.Join(dir, ".gemini", "skills"),
 		filepath.Join(dir, ".pi", "skills"),
 	}
 
@@ -1088,6 +1199,8 @@ func engineCLIName(engine string) string {
 	switch strings.ToLower(engine) {
 	case "claude":
 		return "claude"
+	case "gemini":
+		return "gemini"
 	case "pi":
 		return "pi"
 	default:
@@ -445,6 +445,32 @@ func TestRun_BrokenSkillLinksDetected(t *testing.T) {
 	}
 }
 
+func TestRun_StaleGeminiSkillLinkDetected(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, ".git"), 0755)
+	setupHalDir(t, dir)
+	installSkills(t, dir)
+	installCommands(t, dir)
+
+	// A copied skill directory instead of a symlink to .hal/skills/prd
+	os.MkdirAll(filepath.Join(dir, ".gemini", "skills", "prd"), 0755)
+
+	result := Run(Options{Dir: dir, Engine: "gemini"})
+
+	for _, c := range result.Checks {
+		if c.ID == "local_skill_links" {
+			if c.Status != StatusWarn {
+				t.Fatalf("local_skill_links status = %q, want %q", c.Status, StatusWarn)
+			}
+			if !strings.Contains(c.Message, filepath.Join(".gemini", "skills", "prd")) {
+				t.Fatalf("local_skill_links message = %q, want .gemini/skills/prd", c.Message)
+			}
+			return
+		}
+	}
+	t.Fatal("local_skill_links check not found")
+}
+
 func TestRun_NoBrokenSkillLinks(t *testing.T) {
 	dir := t.TempDir()
 	os.MkdirAll(filepath.Join(dir, ".git"), 0755)
@@ -713,7 +739,7 @@ func TestRun_NoPRDJSON(t *testing.T) {
 }
 
 func TestRun_CheckCount(t *testing.T) {
-	// A fully healthy repo should have exactly 15 checks
+	// A fully healthy repo should have exactly 16 checks
 	dir := t.TempDir()
 	os.MkdirAll(filepath.Join(dir, ".git"), 0755)
 	halDir := setupHalDir(t, dir)
@@ -735,6 +761,7 @@ func TestRun_CheckCount(t *testing.T) {
 		"prd_json",
 		"hal_skills",
 		"hal_commands",
+		"skills_current",
 		"local_skill_links",
 		"codex_global_links",
 		"legacy_debris",
@@ -756,6 +783,49 @@ func TestRun_CheckCount(t *testing.T) {
 	}
 }
 
+func TestCheckSkillsCurrent(t *testing.T) {
+	dir := t.TempDir()
+	setupHalDir(t, dir)
+	if err := skills.InstallSkills(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := skills.InstallCommands(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	if check := checkSkillsCurrent(dir); check.Status != StatusPass {
+		t.Fatalf("fresh install: status = %q (%s), want pass", check.Status, check.Message)
+	}
+
+	// Rewind the prd skill to an older release.
+	halDir := filepath.Join(dir, template.HalDir)
+	lock, err := skills.LoadLock(halDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lock.Files["skills/prd/SKILL.md"] = skills.LockEntry{Version: "v0.1.0", Base: "old prd\n"}
+	if err := skills.SaveLock(halDir, lock); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(halDir, "skills", "prd", "SKILL.md"), []byte("old prd\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	check := checkSkillsCurrent(dir)
+	if check.Status != StatusWarn {
+		t.Fatalf("status = %q, want warn", check.Status)
+	}
+	if check.RemediationID != RemediationUpgradeSkills || check.Remediation == nil || check.Remediation.Command != "hal skills upgrade" {
+		t.Fatalf("remediation = %q %+v, want hal skills upgrade", check.RemediationID, check.Remediation)
+	}
+	if !strings.Contains(check.Message, "outdated: prd") {
+		t.Fatalf("message = %q, want outdated prd", check.Message)
+	}
+	if got := warningSummaryPart("skills_current", nil); got != "run hal skills upgrade" {
+		t.Fatalf("warning summary = %q", got)
+	}
+}
+
 func TestCheckGitHubAuth_NonGitDirectoryIsNotApplicable(t *testing.T) {
 	check := checkGitHubAuthWithDeps(t.TempDir(), githubAuthDeps{
 		originRemoteURL: func(string) (string, error) {
@@ -0,0 +1,430 @@
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jywlabs/hal/internal/engine"
+	"github.com/jywlabs/hal/internal/template"
+)
+
+// ScopeEngineProbe marks checks produced by hal doctor --deep.
+const ScopeEngineProbe = "engine_probe"
+
+// Remediation IDs for engine probe checks.
+const (
+	RemediationInstallEngineCLI = "install_engine_cli"
+	RemediationEngineLogin      = "engine_login"
+	RemediationSetEngineModel   = "set_engine_model"
+	RemediationUpdateEngineCLI  = "update_engine_cli"
+)
+
+// Auth values reported by an engine probe.
+const (
+	AuthOK      = "ok"
+	AuthFailed  = "failed"
+	AuthUnknown = "unknown"
+)
+
+// ProbeTTL is how long cached probe results are reused.
+const ProbeTTL = 15 * time.Minute
+
+// probeTimeout bounds a single probe prompt.
+const probeTimeout = 2 * time.Minute
+
+// probePrompt is the minimal prompt sent to each engine.
+const probePrompt = "Reply with the single word READY. Do not use any tools."
+
+// EngineProbe is the outcome of running a minimal prompt through an engine.
+type EngineProbe struct {
+	Engine          string    `json:"engine"`
+	Installed       bool      `json:"installed"`
+	Version         string    `json:"version,omitempty"`
+	ConfiguredModel string    `json:"configuredModel,omitempty"`
+	Model           string    `json:"model,omitempty"` // Model reported by the init event
+	LatencyMs       int64     `json:"latencyMs,omitempty"`
+	SawInit         bool      `json:"sawInit"`
+	SawResult       bool      `json:"sawResult"`
+	Auth            string    `json:"auth"`
+	ModelRejected   bool      `json:"modelRejected,omitempty"`
+	Error           string    `json:"error,omitempty"`
+	ProbedAt        time.Time `json:"probedAt"`
+}
+
+// OK reports whether the engine answered and its stream was understood.
+func (p EngineProbe) OK() bool {
+	return p.Installed && p.Error == "" && p.SawInit && p.SawResult
+}
+
+type probeDeps struct {
+	lookPath  func(file string) (string, error)
+	version   func(ctx context.Context, cli string) (string, error)
+	newEngine func(name string, cfg *engine.EngineConfig) (engine.Engine, error)
+	now       func() time.Time
+}
+
+var defaultProbeDeps = probeDeps{
+	lookPath:  exec.LookPath,
+	version:   cliVersion,
+	newEngine: engine.NewWithConfig,
+	now:       time.Now,
+}
+
+// ProbeEngine checks that the engine CLI is installed, reads its version and
+// runs a minimal prompt through engine.NewWithConfig, recording the events
+// its streaming parser recognized.
+func ProbeEngine(ctx context.Context, name string, cfg *engine.EngineConfig) EngineProbe {
+	return probeEngineWithDeps(ctx, name, cfg, defaultProbeDeps)
+}
+
+func probeEngineWithDeps(ctx context.Context, name string, cfg *engine.EngineConfig, deps probeDeps) EngineProbe {
+	probe := EngineProbe{Engine: name, Auth: AuthUnknown, ProbedAt: deps.now()}
+	if cfg != nil {
+		probe.ConfiguredModel = cfg.Model
+	}
+
+	cli := engineCLIName(name)
+	if _, err := deps.lookPath(cli); err != nil {
+		probe.Error = cli + " not found in PATH"
+		return probe
+	}
+	probe.Installed = true
+
+	if v, err := deps.version(ctx, cli); err == nil {
+		probe.Version = v
+	}
+
+	probeCfg := engine.EngineConfig{Timeout: probeTimeout}
+	if cfg != nil {
+		probeCfg = *cfg
+		probeCfg.Timeout = probeTimeout
+	}
+	eng, err := deps.newEngine(name, &probeCfg)
+	if err != nil {
+		probe.Error = err.Error()
+		return probe
+	}
+
+	var messages []string
+	display := engine.NewDisplay(io.Discard)
+	display.OnEvent(func(e *engine.Event) {
+		switch e.Type {
+		case engine.EventInit:
+			probe.SawInit = true
+			if e.Data.Model != "" {
+				probe.Model = e.Data.Model
+			}
+		case engine.EventResult:
+			probe.SawResult = true
+		case engine.EventError:
+			messages = append(messages, e.Data.Message, e.Detail)
+		}
+	})
+
+	start := deps.now()
+	_, err = eng.StreamPrompt(ctx, probePrompt, display)
+	probe.LatencyMs = deps.now().Sub(start).Milliseconds()
+	if err != nil {
+		messages = append(messages, err.Error())
+		probe.Error = strings.TrimSpace(err.Error())
+	} else if len(messages) > 0 && !probe.SawResult {
+		probe.Error = strings.TrimSpace(strings.Join(messages, " "))
+	}
+
+	detail := strings.ToLower(strings.Join(messages, " "))
+	switch {
+	case containsAny(detail, "not logged in", "unauthorized", "401", "403", "api key", "authentication", "auth method", "login", "credential", "expired token"):
+		probe.Auth = AuthFailed
+	case containsAny(detail, "model") && containsAny(detail, "not found", "invalid", "unknown", "not supported", "does not exist", "404", "unavailable"):
+		probe.ModelRejected = true
+		probe.Auth = AuthOK
+	case err == nil || probe.SawResult:
+		probe.Auth = AuthOK
+	}
+	return probe
+}
+
+func containsAny(s string, subs ...string) bool {
+	for _, sub := range subs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// cliVersion returns the first line of "<cli> --version".
+func cliVersion(ctx context.Context, cli string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, cli, "--version")
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		return "", err
+	}
+	line, _, _ := strings.Cut(strings.TrimSpace(stdout.String()), "\n")
+	return strings.TrimSpace(line), nil
+}
+
+// probeCache is the content of .hal/engine-probe.json.
+type probeCache struct {
+	Probes map[string]EngineProbe `json:"probes"`
+}
+
+func loadProbeCache(halDir string) probeCache {
+	cache := probeCache{Probes: map[string]EngineProbe{}}
+	data, err := os.ReadFile(filepath.Join(halDir, template.EngineProbeFile))
+	if err != nil {
+		return cache
+	}
+	if err := json.Unmarshal(data, &cache); err != nil || cache.Probes == nil {
+		return probeCache{Probes: map[string]EngineProbe{}}
+	}
+	return cache
+}
+
+func saveProbeCache(halDir string, cache probeCache) error {
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(halDir, template.EngineProbeFile), append(data, '\n'), 0644)
+}
+
+// cachedProbe returns a probe of name for the configured model that is
+// younger than ProbeTTL.
+func (c probeCache) cachedProbe(name, model string, now time.Time) (EngineProbe, bool) {
+	p, ok := c.Probes[name]
+	if !ok || p.ConfiguredModel != model || now.Sub(p.ProbedAt) > ProbeTTL || p.ProbedAt.After(now) {
+		return EngineProbe{}, false
+	}
+	return p, true
+}
+
+// engineProbeChecks returns the probe checks for each engine. With probe
+// unset only fresh cached results are reported, and their failures are
+// downgraded to warnings: the problem may have been fixed since, so a cached
+// result should not block. Otherwise stale or missing results are probed
+// and cached.
+func engineProbeChecks(halDir string, opts Options, defaultEngine string, probe bool, deps probeDeps) []Check {
+	engines := opts.Engines
+	if len(engines) == 0 {
+		engines = []string{defaultEngine}
+	}
+
+	cache := loadProbeCache(halDir)
+	now := deps.now()
+	changed := false
+	var checks []Check
+	for _, name := range engines {
+		var cfg *engine.EngineConfig
+		if opts.EngineConfig != nil {
+			cfg = opts.EngineConfig(name)
+		}
+		model := ""
+		if cfg != nil {
+			model = cfg.Model
+		}
+
+		p, ok := cache.cachedProbe(name, model, now)
+		if !ok || (probe && opts.Refresh) {
+			if !probe {
+				continue
+			}
+			p = probeEngineWithDeps(context.Background(), name, cfg, deps)
+			cache.Probes[name] = p
+			changed = true
+		}
+		for _, c := range probeToChecks(p, name == defaultEngine) {
+			if !probe && c.Status == StatusFail {
+				c.Status, c.Severity = StatusWarn, SeverityWarn
+				c.Message += " (cached; run hal doctor --deep to re-check)"
+			}
+			checks = append(checks, c)
+		}
+	}
+	if changed {
+		_ = saveProbeCache(halDir, cache)
+	}
+	return checks
+}
+
+// probeToChecks turns a probe into version, auth, model and stream checks.
+// Problems with the default engine fail; other engines only warn.
+func probeToChecks(p EngineProbe, isDefault bool) []Check {
+	problem, severity, applicability := StatusWarn, SeverityWarn, ApplicabilityOptional
+	if isDefault {
+		problem, severity, applicability = StatusFail, SeverityError, ApplicabilityRequired
+	}
+	check := func(aspect string) Check {
+		return Check{
+			ID:            p.Engine + "_" + aspect,
+			Status:        StatusPass,
+			Severity:      SeverityInfo,
+			Scope:         ScopeEngineProbe,
+			Applicability: applicability,
+			RemediationID: RemediationNone,
+		}
+	}
+	skip := func(c Check, reason string) Check {
+		c.Status, c.Message = StatusSkip, reason
+		return c
+	}
+	fail := func(c Check, msg, remediationID, command string) Check {
+		c.Status, c.Severity, c.Message, c.RemediationID = problem, severity, msg, remediationID
+		if command != "" {
+			c.Remediation = &Remediation{Command: command, Safe: false}
+		}
+		return c
+	}
+	cli := engineCLIName(p.Engine)
+	age := fmt.Sprintf(" (probed %s)", p.ProbedAt.Format("15:04:05"))
+
+	version := check("version")
+	auth := check("auth")
+	model := check("model")
+	stream := check("stream")
+
+	if !p.Installed {
+		version = fail(version, fmt.Sprintf("%s engine CLI (%s) was not found in PATH.", p.Engine, cli), RemediationInstallEngineCLI, engineInstallCommand(p.Engine))
+		reason := "Skipped: " + cli + " is not installed."
+		return []Check{version, skip(auth, reason), skip(model, reason), skip(stream, reason)}
+	}
+
+	if p.Version != "" {
+		version.Message = fmt.Sprintf("%s CLI version: %s.", p.Engine, p.Version)
+	} else {
+		version.Status, version.Severity = StatusWarn, SeverityWarn
+		version.Message = fmt.Sprintf("Could not read the %s CLI version (%s --version).", p.Engine, cli)
+	}
+
+	switch p.Auth {
+	case AuthOK:
+		auth.Message = fmt.Sprintf("%s credentials are valid.", p.Engine) + age
+	case AuthFailed:
+		auth = fail(auth, fmt.Sprintf("%s credentials were rejected: %s", p.Engine, truncateMessage(p.Error)), RemediationEngineLogin, engineLoginCommand(p.Engine))
+	default:
+		auth = skip(auth, fmt.Sprintf("Could not determine %s auth status.", p.Engine))
+	}
+
+	configured := p.ConfiguredModel
+	if configured == "" {
+		configured = "engine default"
+	}
+	switch {
+	case p.ModelRejected:
+		model = fail(model, fmt.Sprintf("%s rejected model %s; set engines.%s.model in .hal/config.yaml: %s", p.Engine, configured, p.Engine, truncateMessage(p.Error)), RemediationSetEngineModel, "")
+	case p.Model != "":
+		model.Message = fmt.Sprintf("%s accepted model %s.", p.Engine, p.Model)
+	case p.OK():
+		model.Message = fmt.Sprintf("%s accepted model %s.", p.Engine, configured)
+	default:
+		model = skip(model, fmt.Sprintf("Could not confirm the %s model (%s).", p.Engine, configured))
+	}
+
+	switch {
+	case p.OK():
+		stream.Message = fmt.Sprintf("%s answered a probe prompt in %s; init and result events recognized.", p.Engine, formatLatency(p.LatencyMs))
+	case p.Error != "" && (p.Auth == AuthFailed || p.ModelRejected):
+		stream = skip(stream, fmt.Sprintf("Skipped: %s did not complete the probe prompt.", p.Engine))
+	case p.Error != "":
+		stream = fail(stream, fmt.Sprintf("%s probe prompt failed after %s: %s", p.Engine, formatLatency(p.LatencyMs), truncateMessage(p.Error)), RemediationNone, "")
+	default:
+		var missing []string
+		if !p.SawInit {
+			missing = append(missing, "init")
+		}
+		if !p.SawResult {
+			missing = append(missing, "result")
+		}
+		stream = fail(stream, fmt.Sprintf("%s stream had no recognizable %s event; the %s CLI version may be incompatible.", p.Engine, strings.Join(missing, " or "), cli), RemediationUpdateEngineCLI, engineInstallCommand(p.Engine))
+	}
+
+	return []Check{version, auth, model, stream}
+}
+
+func formatLatency(ms int64) string {
+	return (time.Duration(ms) * time.Millisecond).Round(100 * time.Millisecond).String()
+}
+
+func truncateMessage(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if len(s) > 160 {
+		return s[:157] + "..."
+	}
+	return s
+}
+
+func engineInstallCommand(name string) string {
+	switch strings.ToLower(name) {
+	case "claude":
+		return "npm install -g @anthropic-ai/claude-code"
+	case "gemini":
+		return "npm install -g @google/gemini-cli"
+	case "pi":
+		return "npm install -g @mariozechner/pi-coding-agent"
+	default:
+		return "npm install -g @openai/codex"
+	}
+}
+
+func engineLoginCommand(name string) string {
+	switch strings.ToLower(name) {
+	case "claude":
+		return "claude /login"
+	case "gemini":
+		return "gemini /auth"
+	case "pi":
+		return "pi /login"
+	default:
+		return "codex login"
+	}
+}
+
+// probeCheckIDs returns the IDs that belong to engine probe checks.
+func probeCheckIDs(ids []string, checks []Check) []string {
+	var out []string
+	for _, id := range ids {
+		for _, c := range checks {
+			if c.ID == id && c.Scope == ScopeEngineProbe {
+				out = append(out, id)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// probeSummaryPart describes the action for the first failing probe check.
+func probeSummaryPart(ids []string, checks []Check) string {
+	for _, c := range checks {
+		if len(ids) == 0 || c.ID != ids[0] {
+			continue
+		}
+		name := c.ID
+		if i := strings.LastIndex(name, "_"); i > 0 {
+			name = name[:i]
+		}
+		switch c.RemediationID {
+		case RemediationInstallEngineCLI:
+			return "install the " + name + " CLI"
+		case RemediationEngineLogin:
+			return "log in to " + name
+		case RemediationSetEngineModel:
+			return "set a supported model for " + name
+		case RemediationUpdateEngineCLI:
+			return "update the " + name + " CLI"
+		}
+		return "review the " + name + " engine probe"
+	}
+	return "review engine probes"
+}
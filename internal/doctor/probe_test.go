@@ -0,0 +1,242 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jywlabs/hal/internal/engine"
+	"github.com/jywlabs/hal/internal/template"
+)
+
+type probeEngine struct {
+	events []engine.Event
+	err    error
+	calls  *int
+}
+
+func (e *probeEngine) Name() string { return "test" }
+
+func (e *probeEngine) Execute(ctx context.Context, prompt string, display *engine.Display) engine.Result {
+	return engine.Result{}
+}
+
+func (e *probeEngine) Prompt(ctx context.Context, prompt string) (string, error) {
+	return "READY", e.err
+}
+
+func (e *probeEngine) StreamPrompt(ctx context.Context, prompt string, display *engine.Display) (string, error) {
+	if e.calls != nil {
+		*e.calls++
+	}
+	for i := range e.events {
+		display.ShowEvent(&e.events[i])
+	}
+	return "READY", e.err
+}
+
+func testProbeDeps(eng *probeEngine, installed bool) probeDeps {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	return probeDeps{
+		lookPath: func(file string) (string, error) {
+			if !installed {
+				return "", errors.New("not found")
+			}
+			return "/usr/bin/" + file, nil
+		},
+		version: func(ctx context.Context, cli string) (string, error) { return "1.2.3", nil },
+		newEngine: func(name string, cfg *engine.EngineConfig) (engine.Engine, error) {
+			return eng, nil
+		},
+		now: func() time.Time { return now },
+	}
+}
+
+var healthyEvents = []engine.Event{
+	{Type: engine.EventInit, Data: engine.EventData{Model: "gpt-5"}},
+	{Type: engine.EventResult, Data: engine.EventData{Success: true}},
+}
+
+func probeCheck(t *testing.T, checks []Check, id string) Check {
+	t.Helper()
+	for _, c := range checks {
+		if c.ID == id {
+			return c
+		}
+	}
+	t.Fatalf("check %q not found in %+v", id, checks)
+	return Check{}
+}
+
+func TestProbeToChecks(t *testing.T) {
+	tests := []struct {
+		name      string
+		eng       *probeEngine
+		installed bool
+		isDefault bool
+		want      map[string]string
+		wantRemed string
+	}{
+		{
+			name: "healthy", eng: &probeEngine{events: healthyEvents}, installed: true, isDefault: true,
+			want: map[string]string{"codex_version": StatusPass, "codex_auth": StatusPass, "codex_model": StatusPass, "codex_stream": StatusPass},
+		},
+		{
+			name: "not installed", eng: &probeEngine{}, isDefault: true,
+			want:      map[string]string{"codex_version": StatusFail, "codex_auth": StatusSkip, "codex_stream": StatusSkip},
+			wantRemed: RemediationInstallEngineCLI,
+		},
+		{
+			name: "auth failure", eng: &probeEngine{err: errors.New("codex exited: 401 Unauthorized")}, installed: true, isDefault: true,
+			want:      map[string]string{"codex_auth": StatusFail, "codex_stream": StatusSkip},
+			wantRemed: RemediationEngineLogin,
+		},
+		{
+			name: "model rejected", eng: &probeEngine{events: []engine.Event{{Type: engine.EventError, Data: engine.EventData{Message: "model gpt-9 not found"}}}}, installed: true, isDefault: true,
+			want:      map[string]string{"codex_auth": StatusPass, "codex_model": StatusFail},
+			wantRemed: RemediationSetEngineModel,
+		},
+		{
+			name: "unrecognized stream", eng: &probeEngine{}, installed: true, isDefault: true,
+			want:      map[string]string{"codex_stream": StatusFail},
+			wantRemed: RemediationUpdateEngineCLI,
+		},
+		{
+			name: "non-default engine only warns", eng: &probeEngine{}, installed: true,
+			want: map[string]string{"codex_stream": StatusWarn},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := probeEngineWithDeps(context.Background(), "codex", &engine.EngineConfig{Model: "gpt-5"}, testProbeDeps(tt.eng, tt.installed))
+			checks := probeToChecks(p, tt.isDefault)
+			if len(checks) != 4 {
+				t.Fatalf("got %d checks, want 4", len(checks))
+			}
+			for id, status := range tt.want {
+				c := probeCheck(t, checks, id)
+				if c.Status != status {
+					t.Errorf("%s status = %q, want %q (%s)", id, c.Status, status, c.Message)
+				}
+				if c.Scope != ScopeEngineProbe {
+					t.Errorf("%s scope = %q", id, c.Scope)
+				}
+			}
+			if tt.wantRemed != "" {
+				found := false
+				for _, c := range checks {
+					found = found || c.RemediationID == tt.wantRemed
+				}
+				if !found {
+					t.Errorf("no check with remediation %q in %+v", tt.wantRemed, checks)
+				}
+			}
+		})
+	}
+}
+
+func TestProbeEngine_RecordsModelAndVersion(t *testing.T) {
+	p := probeEngineWithDeps(context.Background(), "claude", nil, testProbeDeps(&probeEngine{events: healthyEvents}, true))
+	if !p.OK() || p.Version != "1.2.3" || p.Model != "gpt-5" || p.Auth != AuthOK {
+		t.Fatalf("probe = %+v", p)
+	}
+	stream := probeCheck(t, probeToChecks(p, true), "claude_stream")
+	if !strings.Contains(stream.Message, "init and result events recognized") {
+		t.Errorf("stream message = %q", stream.Message)
+	}
+}
+
+func TestEngineProbeChecks_Cache(t *testing.T) {
+	dir := t.TempDir()
+	halDir := setupHalDir(t, dir)
+	calls := 0
+	deps := testProbeDeps(&probeEngine{events: healthyEvents, calls: &calls}, true)
+	opts := Options{Engines: []string{"codex", "claude"}}
+
+	// Cached-only mode never probes.
+	if checks := engineProbeChecks(halDir, opts, "codex", false, deps); len(checks) != 0 || calls != 0 {
+		t.Fatalf("cached-only checks = %d, calls = %d", len(checks), calls)
+	}
+
+	checks := engineProbeChecks(halDir, opts, "codex", true, deps)
+	if len(checks) != 8 || calls != 2 {
+		t.Fatalf("deep checks = %d, calls = %d", len(checks), calls)
+	}
+	if _, err := os.Stat(filepath.Join(halDir, template.EngineProbeFile)); err != nil {
+		t.Fatalf("probe cache not written: %v", err)
+	}
+	if probeCheck(t, checks, "claude_stream").Applicability != ApplicabilityOptional {
+		t.Errorf("non-default engine should be optional")
+	}
+
+	// Fresh results are reused by both modes.
+	if checks := engineProbeChecks(halDir, opts, "codex", false, deps); len(checks) != 8 {
+		t.Fatalf("cached checks = %d, want 8", len(checks))
+	}
+	engineProbeChecks(halDir, opts, "codex", true, deps)
+	if calls != 2 {
+		t.Fatalf("calls = %d, want cached results reused", calls)
+	}
+
+	// Refresh, a changed model, or an expired entry probe again.
+	engineProbeChecks(halDir, Options{Engines: []string{"codex"}, Refresh: true}, "codex", true, deps)
+	if calls != 3 {
+		t.Fatalf("calls = %d after refresh, want 3", calls)
+	}
+	withModel := Options{Engines: []string{"codex"}, EngineConfig: func(string) *engine.EngineConfig {
+		return &engine.EngineConfig{Model: "gpt-5-mini"}
+	}}
+	if checks := engineProbeChecks(halDir, withModel, "codex", false, deps); len(checks) != 0 {
+		t.Fatalf("cache should not apply after model change, got %d checks", len(checks))
+	}
+	later := deps
+	later.now = func() time.Time { return deps.now().Add(ProbeTTL + time.Minute) }
+	if checks := engineProbeChecks(halDir, opts, "codex", false, later); len(checks) != 0 {
+		t.Fatalf("expired cache should not be reported, got %d checks", len(checks))
+	}
+}
+
+func TestRun_CachedProbeFailureWarns(t *testing.T) {
+	dir := t.TempDir()
+	halDir := setupHalDir(t, dir)
+	installSkills(t, dir)
+	installCommands(t, dir)
+	if err := saveProbeCache(halDir, probeCache{Probes: map[string]EngineProbe{
+		"pi": {Engine: "pi", Installed: true, Version: "0.9", Auth: AuthFailed, Error: "not logged in", ProbedAt: time.Now()},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	result := Run(Options{Dir: dir, Engine: "pi"})
+	for _, c := range result.Checks {
+		if c.Scope == ScopeEngineProbe {
+			t.Fatalf("probe checks reported without Deep or CachedProbes: %+v", c)
+		}
+	}
+
+	result = Run(Options{Dir: dir, Engine: "pi", CachedProbes: true})
+	if result.OverallStatus != StatusWarn || len(result.Failures) != 0 {
+		t.Fatalf("overall = %q, failures = %v; want a warning only", result.OverallStatus, result.Failures)
+	}
+	if !strings.Contains(result.Summary, "log in to pi") {
+		t.Errorf("summary = %q", result.Summary)
+	}
+	for _, c := range result.Checks {
+		if c.ID == "pi_auth" && !strings.Contains(c.Message, "hal doctor --deep") {
+			t.Errorf("cached auth message = %q, want a re-check hint", c.Message)
+		}
+	}
+
+	// A real probe with the same result still fails.
+	result = Run(Options{Dir: dir, Engine: "pi", Deep: true})
+	if result.OverallStatus != StatusFail {
+		t.Fatalf("deep overall = %q, want fail", result.OverallStatus)
+	}
+	if result.PrimaryRemediation == nil || result.PrimaryRemediation.Command != "pi /login" {
+		t.Errorf("primary remediation = %+v", result.PrimaryRemediation)
+	}
+}
@@ -31,7 +31,7 @@ func randomHalWord(words []string) string {
 
 // HeaderContext holds engine/model/git info for command and loop headers.
 type HeaderContext struct {
-	Engine string // "pi", "claude", "codex"
+	Engine string // "pi", "claude", "codex", "gemini"
 	Model  string // from config, may be empty
 	Repo   string // git repo basename, may be empty
 	Branch string // git branch, may be empty
@@ -63,6 +63,9 @@ type Display struct {
 	// Thinking state — shows elapsed time while model reasons
 	thinkingStart time.Time
 	isThinking    bool
+
+	// onEvent observes every event passed to ShowEvent
+	onEvent func(*Event)
 }
 
 // NewDisplay creates a new display writer.
@@ -226,11 +229,20 @@ func (d *Display) StopSpinner() {
 	<-d.spinDone
 }
 
+// OnEvent registers fn to observe every event shown by the display.
+// Set it before the display is handed to an engine.
+func (d *Display) OnEvent(fn func(*Event)) {
+	d.onEvent = fn
+}
+
 // ShowEvent displays a normalized event.
 func (d *Display) ShowEvent(e *Event) {
 	if e == nil {
 		return
 	}
+	if d.onEvent != nil {
+		d.onEvent(e)
+	}
 
 	// Keep spinner continuity when updating active activity text.
 	keepSpinner := e.Type == EventTool || (e.Type == EventThinking && e.Data.Message == "delta")
@@ -800,6 +812,14 @@ func formatRepoBranch(repo, branch string) string {
 	return strings.Join(parts, " · ")
 }
 
+// TotalTokens returns the tokens reported by engine results since the last
+// header was shown.
+func (d *Display) TotalTokens() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.totalTokens
+}
+
 // Writer returns the underlying io.Writer for the display.
 func (d *Display) Writer() io.Writer {
 	return d.out
@@ -0,0 +1,406 @@
+package gemini
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/jywlabs/hal/internal/engine"
+)
+
+func init() {
+	engine.RegisterEngine("gemini", func(cfg *engine.EngineConfig) engine.Engine {
+		return New(cfg)
+	})
+}
+
+// Engine executes prompts using Google's Gemini CLI.
+type Engine struct {
+	Timeout time.Duration
+	model   string
+}
+
+// New creates a new Gemini engine. Gemini CLI selects its backend through
+// its own auth settings, so cfg.Provider is ignored.
+func New(cfg *engine.EngineConfig) *Engine {
+	e := &Engine{
+		Timeout: engine.DefaultTimeout,
+	}
+	if cfg != nil {
+		if cfg.Model != "" {
+			e.model = cfg.Model
+		}
+		if cfg.Timeout > 0 {
+			e.Timeout = cfg.Timeout
+		}
+	}
+	return e
+}
+
+// Name returns the engine identifier.
+func (e *Engine) Name() string {
+	return "gemini"
+}
+
+// CLICommand returns the CLI executable name.
+func (e *Engine) CLICommand() string {
+	return "gemini"
+}
+
+// BuildArgs returns the CLI arguments for streaming JSON execution.
+// The prompt is passed via stdin, not as a CLI argument, to avoid
+// OS argument length limits; gemini runs non-interactively when stdin
+// is not a terminal.
+func (e *Engine) BuildArgs() []string {
+	args := []string{
+		"--yolo",
+		"--output-format", "stream-json",
+	}
+	if e.model != "" {
+		args = append(args, "--model", e.model)
+	}
+	return args
+}
+
+// BuildArgsSimple returns CLI arguments for plain text output.
+// The prompt is passed via stdin.
+func (e *Engine) BuildArgsSimple() []string {
+	args := []string{
+		"--yolo",
+	}
+	if e.model != "" {
+		args = append(args, "--model", e.model)
+	}
+	return args
+}
+
+func contextRunError(ctx context.Context, timeout time.Duration, operation string) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		if ctxErr == context.DeadlineExceeded {
+			return fmt.Errorf("%s timed out after %s", operation, timeout)
+		}
+		return fmt.Errorf("%s canceled: %w", operation, ctxErr)
+	}
+
+	return nil
+}
+
+// Execute runs the prompt using gemini CLI with streaming JSON output.
+func (e *Engine) Execute(ctx context.Context, prompt string, display *engine.Display) engine.Result {
+	timeout := e.Timeout
+	if timeout == 0 {
+		timeout = engine.DefaultTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	startTime := time.Now()
+
+	// Build command — prompt is piped via stdin to avoid OS arg length limits.
+	args := e.BuildArgs()
+	cmd := exec.CommandContext(ctx, e.CLICommand(), args...)
+
+	// Pipe prompt via stdin.
+	cmd.Stdin = strings.NewReader(prompt)
+	cmd.SysProcAttr = newSysProcAttr()
+	setupProcessCleanup(cmd)
+
+	// Set up output capture with streaming parser
+	var stdout, stderr bytes.Buffer
+	parser := NewParser()
+	streamWriter := &streamHandler{
+		parser:  parser,
+		display: display,
+	}
+
+	cmd.Stdout = io.MultiWriter(streamWriter, &stdout)
+	cmd.Stderr = &stderr
+
+	// Run command
+	err := cmd.Run()
+	streamWriter.Flush()
+
+	output := stdout.String()
+	duration := time.Since(startTime)
+
+	// Handle errors
+	if err != nil {
+		if result, recovered := e.recoverExecuteResult(ctx, timeout, output, duration, parser.TotalTokens()); recovered {
+			return result
+		}
+		return engine.Result{
+			Success:  false,
+			Output:   output,
+			Duration: duration,
+			Error:    fmt.Errorf("execution failed: %w (stderr: %s)", err, stderr.String()),
+		}
+	}
+
+	// Parse success and completion from parser state
+	success := !parser.HasFailure()
+	complete := strings.Contains(output, "<promise>COMPLETE</promise>")
+
+	return engine.Result{
+		Success:  success,
+		Complete: complete,
+		Output:   output,
+		Duration: duration,
+		Tokens:   parser.TotalTokens(),
+		Error:    nil,
+	}
+}
+
+func (e *Engine) parseResultStatus(output string) (hasResult bool, success bool) {
+	parser := NewParser()
+	success = true
+
+	for _, line := range strings.Split(output, "\n") {
+		event := parser.ParseLine([]byte(line))
+		if event != nil && event.Type == engine.EventResult {
+			hasResult = true
+			success = event.Data.Success
+		}
+	}
+
+	return hasResult, success
+}
+
+// Prompt executes a single prompt and returns the text response.
+func (e *Engine) Prompt(ctx context.Context, prompt string) (string, error) {
+	timeout := e.Timeout
+	if timeout == 0 {
+		timeout = engine.DefaultTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Build command with plain text output — prompt piped via stdin.
+	args := e.BuildArgsSimple()
+	cmd := exec.CommandContext(ctx, e.CLICommand(), args...)
+	cmd.Stdin = strings.NewReader(prompt)
+	cmd.SysProcAttr = newSysProcAttr()
+	setupProcessCleanup(cmd)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", fmt.Errorf("prompt timed out after %s", timeout)
+		}
+		return "", fmt.Errorf("prompt failed: %w (stderr: %s)", err, stderr.String())
+	}
+
+	return stdout.String(), nil
+}
+
+// StreamPrompt executes a prompt with streaming display feedback.
+// It uses JSON mode to show progress via the display while collecting
+// the text response for return.
+func (e *Engine) StreamPrompt(ctx context.Context, prompt string, display *engine.Display) (string, error) {
+	timeout := e.Timeout
+	if timeout == 0 {
+		timeout = engine.DefaultTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Use streaming JSON args — prompt piped via stdin.
+	args := e.BuildArgs()
+	cmd := exec.CommandContext(ctx, e.CLICommand(), args...)
+	cmd.Stdin = strings.NewReader(prompt)
+	cmd.SysProcAttr = newSysProcAttr()
+	setupProcessCleanup(cmd)
+
+	var stdout, stderr bytes.Buffer
+	parser := NewParser()
+	collector := &textCollectingStreamHandler{
+		parser:  parser,
+		display: display,
+	}
+
+	cmd.Stdout = io.MultiWriter(collector, &stdout)
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	collector.Flush()
+
+	if display != nil {
+		display.StopSpinner()
+	}
+
+	if err != nil {
+		if text, recoverErr, recovered := e.recoverStreamPrompt(
+			ctx,
+			timeout,
+			err,
+			stdout.String(),
+			collector.Text(),
+			stderr.String(),
+		); recovered {
+			return text, recoverErr
+		}
+		return "", fmt.Errorf("prompt failed: %w (stderr: %s)", err, stderr.String())
+	}
+
+	return collector.Text(), nil
+}
+
+func (e *Engine) recoverExecuteResult(
+	ctx context.Context,
+	timeout time.Duration,
+	output string,
+	duration time.Duration,
+	tokens int,
+) (engine.Result, bool) {
+	if ctxErr := ctx.Err(); ctxErr == context.Canceled {
+		return engine.Result{
+			Success:  false,
+			Output:   output,
+			Duration: duration,
+			Error:    fmt.Errorf("execution canceled: %w", ctxErr),
+		}, true
+	}
+
+	if hasResult, success := e.parseResultStatus(output); hasResult && success {
+		complete := strings.Contains(output, "<promise>COMPLETE</promise>")
+		return engine.Result{
+			Success:  true,
+			Complete: complete,
+			Output:   output,
+			Duration: duration,
+			Tokens:   tokens,
+			Error:    nil,
+		}, true
+	}
+
+	if runErr := contextRunError(ctx, timeout, "execution"); runErr != nil {
+		return engine.Result{
+			Success:  false,
+			Output:   output,
+			Duration: duration,
+			Error:    runErr,
+		}, true
+	}
+
+	return engine.Result{}, false
+}
+
+func (e *Engine) recoverStreamPrompt(
+	ctx context.Context,
+	timeout time.Duration,
+	err error,
+	output string,
+	text string,
+	stderr string,
+) (string, error, bool) {
+	if ctxErr := ctx.Err(); ctxErr == context.Canceled {
+		return "", fmt.Errorf("prompt canceled: %w", ctxErr), true
+	}
+
+	if hasResult, success := e.parseResultStatus(output); hasResult && success {
+		if strings.TrimSpace(text) != "" {
+			return text, nil, true
+		}
+		return "", engine.NewOutputFallbackRequiredError(
+			fmt.Errorf("prompt failed: %w (stderr: %s)", err, stderr),
+		), true
+	}
+
+	if runErr := contextRunError(ctx, timeout, "prompt"); runErr != nil {
+		return "", runErr, true
+	}
+
+	return "", nil, false
+}
+
+// streamHandler processes output line by line for Execute.
+type streamHandler struct {
+	parser  *Parser
+	display *engine.Display
+	buffer  []byte
+}
+
+func (h *streamHandler) Write(p []byte) (n int, err error) {
+	h.buffer = append(h.buffer, p...)
+
+	for {
+		idx := bytes.IndexByte(h.buffer, '\n')
+		if idx == -1 {
+			break
+		}
+
+		line := h.buffer[:idx]
+		h.buffer = h.buffer[idx+1:]
+
+		event := h.parser.ParseLine(line)
+		if h.display != nil {
+			h.display.ShowEvent(event)
+		}
+	}
+
+	return len(p), nil
+}
+
+func (h *streamHandler) Flush() {
+	if len(h.buffer) > 0 {
+		event := h.parser.ParseLine(h.buffer)
+		if h.display != nil {
+			h.display.ShowEvent(event)
+		}
+		h.buffer = nil
+	}
+}
+
+// textCollectingStreamHandler streams events to the display while
+// collecting text content from assistant messages.
+type textCollectingStreamHandler struct {
+	parser  *Parser
+	display *engine.Display
+	buffer  []byte
+}
+
+func (h *textCollectingStreamHandler) Write(p []byte) (n int, err error) {
+	h.buffer = append(h.buffer, p...)
+
+	for {
+		idx := bytes.IndexByte(h.buffer, '\n')
+		if idx == -1 {
+			break
+		}
+
+		line := h.buffer[:idx]
+		h.buffer = h.buffer[idx+1:]
+
+		h.processLine(line)
+	}
+
+	return len(p), nil
+}
+
+func (h *textCollectingStreamHandler) processLine(line []byte) {
+	event := h.parser.ParseLine(line)
+	if h.display != nil {
+		h.display.ShowEvent(event)
+	}
+}
+
+func (h *textCollectingStreamHandler) Flush() {
+	if len(h.buffer) > 0 {
+		h.processLine(h.buffer)
+		h.buffer = nil
+	}
+}
+
+func (h *textCollectingStreamHandler) Text() string {
+	return h.parser.CollectedText()
+}
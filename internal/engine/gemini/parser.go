@@ -0,0 +1,356 @@
+package gemini
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/jywlabs/hal/internal/engine"
+)
+
+// Parser parses Gemini CLI's stream-json output format.
+//
+// Gemini emits JSONL with these top-level event types:
+//
+//	init        — session metadata with the resolved model (first line)
+//	message     — user or assistant content; assistant text streams as delta chunks
+//	thought     — model reasoning summaries
+//	tool_use    — tool call with tool_name, tool_id and parameters
+//	tool_result — tool outcome with status "success" or "error"
+//	error       — non-fatal warnings and fatal errors, tagged by severity
+//	result      — final status with aggregated token and timing stats
+type Parser struct {
+	totalTokens int
+	hasFailure  bool
+	text        strings.Builder
+	inText      bool // tracks whether an assistant text segment is streaming
+	isThinking  bool // tracks whether model is currently in a thinking block
+	toolNames   map[string]string
+	now         func() time.Time
+	runStart    time.Time
+}
+
+// NewParser creates a new Gemini output parser.
+func NewParser() *Parser {
+	now := time.Now
+	return &Parser{
+		toolNames: make(map[string]string),
+		now:       now,
+		runStart:  now(),
+	}
+}
+
+// TotalTokens returns accumulated token usage.
+func (p *Parser) TotalTokens() int {
+	return p.totalTokens
+}
+
+// HasFailure returns true if the run ended in error.
+func (p *Parser) HasFailure() bool {
+	return p.hasFailure
+}
+
+// CollectedText returns all assistant text accumulated during parsing.
+func (p *Parser) CollectedText() string {
+	return p.text.String()
+}
+
+// ParseLine parses a single JSON line from gemini's streaming output.
+func (p *Parser) ParseLine(line []byte) *engine.Event {
+	line = trimSpace(line)
+	if len(line) == 0 {
+		return nil
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(line, &raw); err != nil {
+		return nil
+	}
+
+	eventType, _ := raw["type"].(string)
+	if eventType != "message" {
+		p.inText = false
+	}
+	if eventType != "thought" {
+		p.isThinking = false
+	}
+
+	switch eventType {
+	case "init":
+		return p.parseInit(raw)
+	case "message":
+		return p.parseMessage(raw)
+	case "thought":
+		return p.parseThought()
+	case "tool_use":
+		return p.parseToolUse(raw)
+	case "tool_result":
+		return p.parseToolResult(raw)
+	case "error":
+		return p.parseError(raw)
+	case "result":
+		return p.parseResult(raw)
+	default:
+		return nil
+	}
+}
+
+func (p *Parser) parseInit(raw map[string]interface{}) *engine.Event {
+	p.markRunStart()
+
+	model, _ := raw["model"].(string)
+	return &engine.Event{
+		Type: engine.EventInit,
+		Data: engine.EventData{
+			Model: model,
+		},
+	}
+}
+
+func (p *Parser) parseMessage(raw map[string]interface{}) *engine.Event {
+	// User messages echo the prompt; only assistant output matters.
+	role, _ := raw["role"].(string)
+	if role != "assistant" {
+		p.inText = false
+		return nil
+	}
+
+	content, _ := raw["content"].(string)
+	if content == "" {
+		return nil
+	}
+	p.text.WriteString(content)
+
+	// Deltas stream a segment in small chunks. Show the segment once, when it
+	// starts, and keep the spinner running for the rest.
+	if delta, _ := raw["delta"].(bool); delta && p.inText {
+		return nil
+	}
+	p.inText = true
+
+	detail := strings.TrimSpace(content)
+	if idx := strings.IndexByte(detail, '\n'); idx != -1 {
+		detail = detail[:idx]
+	}
+	return &engine.Event{
+		Type:   engine.EventText,
+		Detail: truncate(detail, 80),
+	}
+}
+
+func (p *Parser) parseThought() *engine.Event {
+	message := "delta"
+	if !p.isThinking {
+		p.isThinking = true
+		message = "start"
+	}
+	return &engine.Event{
+		Type: engine.EventThinking,
+		Data: engine.EventData{
+			Message: message,
+		},
+	}
+}
+
+func (p *Parser) parseToolUse(raw map[string]interface{}) *engine.Event {
+	name, _ := raw["tool_name"].(string)
+	params, _ := raw["parameters"].(map[string]interface{})
+	if id, _ := raw["tool_id"].(string); id != "" {
+		p.toolNames[id] = name
+	}
+
+	event := &engine.Event{
+		Type: engine.EventTool,
+		Tool: strings.ToLower(name),
+	}
+
+	switch name {
+	case "read_file", "read_many_files":
+		event.Tool = "read"
+		event.Detail = shortPath(stringParam(params, "file_path", "absolute_path", "path"))
+	case "write_file":
+		event.Tool = "write"
+		event.Detail = shortPath(stringParam(params, "file_path", "path"))
+	case "replace":
+		event.Tool = "edit"
+		event.Detail = shortPath(stringParam(params, "file_path", "path"))
+	case "run_shell_command":
+		event.Tool = "run"
+		event.Detail = truncate(stringParam(params, "description", "command"), 50)
+	case "glob":
+		event.Detail = truncate(stringParam(params, "pattern"), 40)
+	case "search_file_content", "grep":
+		event.Tool = "grep"
+		event.Detail = truncate(stringParam(params, "pattern"), 40)
+	case "list_directory":
+		event.Tool = "ls"
+		event.Detail = shortPath(stringParam(params, "dir_path", "path"))
+	case "web_fetch":
+		event.Tool = "fetch"
+		event.Detail = truncate(stringParam(params, "prompt", "url"), 50)
+	case "google_web_search":
+		event.Tool = "search"
+		event.Detail = truncate(stringParam(params, "query"), 50)
+	}
+
+	return event
+}
+
+func (p *Parser) parseToolResult(raw map[string]interface{}) *engine.Event {
+	// Gemini feeds tool errors back to the model, which usually recovers,
+	// so they are shown but do not fail the run.
+	status, _ := raw["status"].(string)
+	if status != "error" {
+		return nil
+	}
+
+	id, _ := raw["tool_id"].(string)
+	message := p.toolNames[id]
+	if message == "" {
+		message = "tool"
+	}
+	message += " failed"
+	if errObj, ok := raw["error"].(map[string]interface{}); ok {
+		if text, _ := errObj["message"].(string); text != "" {
+			message = truncate(text, 80)
+		}
+	}
+
+	return &engine.Event{
+		Type: engine.EventError,
+		Data: engine.EventData{
+			Message: message,
+		},
+	}
+}
+
+func (p *Parser) parseError(raw map[string]interface{}) *engine.Event {
+	severity, _ := raw["severity"].(string)
+	if severity == "error" {
+		p.hasFailure = true
+	}
+
+	message, _ := raw["message"].(string)
+	if message == "" {
+		message = "gemini error"
+	}
+
+	return &engine.Event{
+		Type: engine.EventError,
+		Data: engine.EventData{
+			Message: truncate(message, 80),
+		},
+	}
+}
+
+func (p *Parser) parseResult(raw map[string]interface{}) *engine.Event {
+	status, _ := raw["status"].(string)
+	if status != "success" {
+		p.hasFailure = true
+	}
+
+	durationMs := p.elapsedRunDurationMs()
+	if stats, ok := raw["stats"].(map[string]interface{}); ok {
+		p.accumulateUsage(stats)
+		if v, ok := stats["duration_ms"].(float64); ok && v > 0 {
+			durationMs = v
+		}
+	}
+
+	return &engine.Event{
+		Type: engine.EventResult,
+		Data: engine.EventData{
+			Success:    !p.hasFailure,
+			Tokens:     p.totalTokens,
+			DurationMs: durationMs,
+		},
+	}
+}
+
+// markRunStart records the start time used for terminal duration reporting.
+func (p *Parser) markRunStart() {
+	if p.now == nil {
+		p.now = time.Now
+	}
+	p.runStart = p.now()
+}
+
+// elapsedRunDurationMs returns elapsed milliseconds since runStart.
+func (p *Parser) elapsedRunDurationMs() float64 {
+	if p.now == nil {
+		p.now = time.Now
+	}
+	if p.runStart.IsZero() {
+		p.runStart = p.now()
+	}
+
+	elapsed := p.now().Sub(p.runStart)
+	if elapsed < 0 {
+		return 0
+	}
+
+	return float64(elapsed.Milliseconds())
+}
+
+// accumulateUsage extracts token usage from the result stats object.
+func (p *Parser) accumulateUsage(stats map[string]interface{}) {
+	// Gemini stats fields: total_tokens, input_tokens, output_tokens
+	if total, ok := stats["total_tokens"].(float64); ok && total > 0 {
+		p.totalTokens = int(total)
+		return
+	}
+
+	// Fallback: sum individual fields
+	tokens := 0
+	if v, ok := stats["input_tokens"].(float64); ok {
+		tokens += int(v)
+	}
+	if v, ok := stats["output_tokens"].(float64); ok {
+		tokens += int(v)
+	}
+	if tokens > p.totalTokens {
+		p.totalTokens = tokens
+	}
+}
+
+// Helper functions
+
+// stringParam returns the first non-empty string parameter among keys.
+func stringParam(params map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if v, _ := params[key].(string); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func trimSpace(b []byte) []byte {
+	start, end := 0, len(b)
+	for start < end && isSpace(b[start]) {
+		start++
+	}
+	for end > start && isSpace(b[end-1]) {
+		end--
+	}
+	return b[start:end]
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
+
+func shortPath(path string) string {
+	parts := strings.Split(path, "/")
+	if len(parts) <= 2 {
+		return path
+	}
+	return ".../" + strings.Join(parts[len(parts)-2:], "/")
+}
+
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max-3] + "..."
+}
@@ -0,0 +1,275 @@
+package gemini
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jywlabs/hal/internal/engine"
+)
+
+func TestNew(t *testing.T) {
+	e := New(nil)
+	if e == nil {
+		t.Fatal("New(nil) returned nil")
+	}
+	if e.Timeout != engine.DefaultTimeout {
+		t.Errorf("expected Timeout=%v, got %v", engine.DefaultTimeout, e.Timeout)
+	}
+	if e.model != "" {
+		t.Errorf("expected empty model, got %q", e.model)
+	}
+}
+
+func TestNewWithConfig(t *testing.T) {
+	e := New(&engine.EngineConfig{Model: "gemini-2.5-pro", Timeout: time.Minute})
+	if e.model != "gemini-2.5-pro" {
+		t.Errorf("expected model=%q, got %q", "gemini-2.5-pro", e.model)
+	}
+	if e.Timeout != time.Minute {
+		t.Errorf("expected Timeout=%v, got %v", time.Minute, e.Timeout)
+	}
+}
+
+func TestNameAndCLICommand(t *testing.T) {
+	e := New(nil)
+	if e.Name() != "gemini" {
+		t.Errorf("expected Name()=\"gemini\", got %q", e.Name())
+	}
+	if e.CLICommand() != "gemini" {
+		t.Errorf("expected CLICommand()=\"gemini\", got %q", e.CLICommand())
+	}
+}
+
+func TestBuildArgs(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    *engine.EngineConfig
+		simple bool
+		want   []string
+	}{
+		{name: "defaults", want: []string{"--yolo", "--output-format", "stream-json"}},
+		{
+			name: "with model",
+			cfg:  &engine.EngineConfig{Model: "gemini-2.5-flash", Provider: "ignored"},
+			want: []string{"--yolo", "--output-format", "stream-json", "--model", "gemini-2.5-flash"},
+		},
+		{name: "simple", simple: true, want: []string{"--yolo"}},
+		{
+			name:   "simple with model",
+			cfg:    &engine.EngineConfig{Model: "gemini-2.5-flash"},
+			simple: true,
+			want:   []string{"--yolo", "--model", "gemini-2.5-flash"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := New(tt.cfg)
+			args := e.BuildArgs()
+			if tt.simple {
+				args = e.BuildArgsSimple()
+			}
+			if len(args) != len(tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, args)
+			}
+			for i := range args {
+				if args[i] != tt.want[i] {
+					t.Errorf("arg[%d]: expected %q, got %q", i, tt.want[i], args[i])
+				}
+			}
+		})
+	}
+}
+
+func TestEngineRegistration(t *testing.T) {
+	e, err := engine.New("gemini")
+	if err != nil {
+		t.Fatalf("engine.New(\"gemini\") failed: %v", err)
+	}
+	if e.Name() != "gemini" {
+		t.Errorf("expected Name()=\"gemini\", got %q", e.Name())
+	}
+}
+
+// Parser tests
+
+func TestParser_ParseLine_IgnoresNoise(t *testing.T) {
+	p := NewParser()
+	for _, line := range []string{"", "   ", "not json", `{"type":"unknown"}`} {
+		if event := p.ParseLine([]byte(line)); event != nil {
+			t.Errorf("ParseLine(%q) = %+v, want nil", line, event)
+		}
+	}
+}
+
+func TestParser_ParseLine_Init(t *testing.T) {
+	p := NewParser()
+	event := p.ParseLine([]byte(`{"type":"init","timestamp":"2026-10-01T10:00:00.000Z","session_id":"abc","model":"gemini-2.5-pro"}`))
+	if event == nil || event.Type != engine.EventInit {
+		t.Fatalf("expected EventInit, got %+v", event)
+	}
+	if event.Data.Model != "gemini-2.5-pro" {
+		t.Errorf("expected Model=%q, got %q", "gemini-2.5-pro", event.Data.Model)
+	}
+}
+
+func TestParser_ParseLine_Messages(t *testing.T) {
+	p := NewParser()
+
+	if event := p.ParseLine([]byte(`{"type":"message","role":"user","content":"do the task"}`)); event != nil {
+		t.Errorf("expected nil for user message, got %+v", event)
+	}
+
+	event := p.ParseLine([]byte(`{"type":"message","role":"assistant","content":"Reading the PRD first.\n","delta":true}`))
+	if event == nil || event.Type != engine.EventText {
+		t.Fatalf("expected EventText for first delta, got %+v", event)
+	}
+	if event.Detail != "Reading the PRD first." {
+		t.Errorf("expected first line as detail, got %q", event.Detail)
+	}
+
+	if event := p.ParseLine([]byte(`{"type":"message","role":"assistant","content":"Then the code.","delta":true}`)); event != nil {
+		t.Errorf("expected nil for continuing delta, got %+v", event)
+	}
+
+	// A tool call ends the segment, so the next delta is shown again.
+	p.ParseLine([]byte(`{"type":"tool_use","tool_name":"glob","tool_id":"t1","parameters":{"pattern":"*.go"}}`))
+	event = p.ParseLine([]byte(`{"type":"message","role":"assistant","content":"<promise>COMPLETE</promise>","delta":true}`))
+	if event == nil || event.Type != engine.EventText {
+		t.Fatalf("expected EventText for new segment, got %+v", event)
+	}
+
+	want := "Reading the PRD first.\nThen the code.<promise>COMPLETE</promise>"
+	if got := p.CollectedText(); got != want {
+		t.Errorf("CollectedText() = %q, want %q", got, want)
+	}
+}
+
+func TestParser_ParseLine_ToolUse(t *testing.T) {
+	tests := []struct {
+		line       string
+		wantTool   string
+		wantDetail string
+	}{
+		{`{"type":"tool_use","tool_name":"read_file","tool_id":"1","parameters":{"file_path":"/repo/internal/engine/gemini.go"}}`, "read", ".../engine/gemini.go"},
+		{`{"type":"tool_use","tool_name":"write_file","tool_id":"2","parameters":{"file_path":"notes.md","content":"x"}}`, "write", "notes.md"},
+		{`{"type":"tool_use","tool_name":"replace","tool_id":"3","parameters":{"file_path":"a/b/c.go"}}`, "edit", ".../b/c.go"},
+		{`{"type":"tool_use","tool_name":"run_shell_command","tool_id":"4","parameters":{"command":"go test ./..."}}`, "run", "go test ./..."},
+		{`{"type":"tool_use","tool_name":"run_shell_command","tool_id":"5","parameters":{"command":"make","description":"Build the binary"}}`, "run", "Build the binary"},
+		{`{"type":"tool_use","tool_name":"search_file_content","tool_id":"6","parameters":{"pattern":"func main"}}`, "grep", "func main"},
+		{`{"type":"tool_use","tool_name":"list_directory","tool_id":"7","parameters":{"dir_path":"internal"}}`, "ls", "internal"},
+		{`{"type":"tool_use","tool_name":"google_web_search","tool_id":"8","parameters":{"query":"cobra flags"}}`, "search", "cobra flags"},
+		{`{"type":"tool_use","tool_name":"Save_Memory","tool_id":"9","parameters":{}}`, "save_memory", ""},
+	}
+	for _, tt := range tests {
+		p := NewParser()
+		event := p.ParseLine([]byte(tt.line))
+		if event == nil || event.Type != engine.EventTool {
+			t.Fatalf("expected EventTool for %s, got %+v", tt.line, event)
+		}
+		if event.Tool != tt.wantTool || event.Detail != tt.wantDetail {
+			t.Errorf("got tool=%q detail=%q, want tool=%q detail=%q", event.Tool, event.Detail, tt.wantTool, tt.wantDetail)
+		}
+	}
+}
+
+func TestParser_ParseLine_ToolResult(t *testing.T) {
+	p := NewParser()
+	p.ParseLine([]byte(`{"type":"tool_use","tool_name":"read_file","tool_id":"r1","parameters":{"file_path":"missing.go"}}`))
+
+	if event := p.ParseLine([]byte(`{"type":"tool_result","tool_id":"r1","status":"success","output":"ok"}`)); event != nil {
+		t.Errorf("expected nil for successful tool result, got %+v", event)
+	}
+
+	event := p.ParseLine([]byte(`{"type":"tool_result","tool_id":"r1","status":"error","error":{"type":"file_not_found","message":"File not found: missing.go"}}`))
+	if event == nil || event.Type != engine.EventError {
+		t.Fatalf("expected EventError, got %+v", event)
+	}
+	if event.Data.Message != "File not found: missing.go" {
+		t.Errorf("unexpected message %q", event.Data.Message)
+	}
+
+	event = p.ParseLine([]byte(`{"type":"tool_result","tool_id":"r1","status":"error"}`))
+	if event == nil || event.Data.Message != "read_file failed" {
+		t.Errorf("expected tool name fallback, got %+v", event)
+	}
+
+	if p.HasFailure() {
+		t.Error("tool errors should not fail the run")
+	}
+}
+
+func TestParser_ParseLine_Thought(t *testing.T) {
+	p := NewParser()
+
+	event := p.ParseLine([]byte(`{"type":"thought","subject":"Planning","description":"Looking at the story"}`))
+	if event == nil || event.Type != engine.EventThinking || event.Data.Message != "start" {
+		t.Fatalf("expected thinking start, got %+v", event)
+	}
+	event = p.ParseLine([]byte(`{"type":"thought","subject":"Planning","description":"Still looking"}`))
+	if event == nil || event.Data.Message != "delta" {
+		t.Fatalf("expected thinking delta, got %+v", event)
+	}
+
+	p.ParseLine([]byte(`{"type":"message","role":"assistant","content":"Done thinking","delta":true}`))
+	event = p.ParseLine([]byte(`{"type":"thought","subject":"Again"}`))
+	if event == nil || event.Data.Message != "start" {
+		t.Fatalf("expected new thinking block to start, got %+v", event)
+	}
+}
+
+func TestParser_ParseLine_Error(t *testing.T) {
+	p := NewParser()
+
+	event := p.ParseLine([]byte(`{"type":"error","severity":"warning","message":"Loop detected, retrying"}`))
+	if event == nil || event.Type != engine.EventError || event.Data.Message != "Loop detected, retrying" {
+		t.Fatalf("expected warning EventError, got %+v", event)
+	}
+	if p.HasFailure() {
+		t.Error("warnings should not fail the run")
+	}
+
+	p.ParseLine([]byte(`{"type":"error","severity":"error","message":"Please set an Auth method"}`))
+	if !p.HasFailure() {
+		t.Error("fatal errors should fail the run")
+	}
+}
+
+func TestParser_ParseLine_Result(t *testing.T) {
+	p := NewParser()
+	event := p.ParseLine([]byte(`{"type":"result","status":"success","stats":{"total_tokens":1530,"input_tokens":1200,"output_tokens":330,"duration_ms":4200,"tool_calls":2}}`))
+	if event == nil || event.Type != engine.EventResult {
+		t.Fatalf("expected EventResult, got %+v", event)
+	}
+	if !event.Data.Success {
+		t.Error("expected Success=true")
+	}
+	if event.Data.Tokens != 1530 || p.TotalTokens() != 1530 {
+		t.Errorf("expected 1530 tokens, got event=%d parser=%d", event.Data.Tokens, p.TotalTokens())
+	}
+	if event.Data.DurationMs != 4200 {
+		t.Errorf("expected DurationMs=4200, got %v", event.Data.DurationMs)
+	}
+}
+
+func TestParser_ParseLine_ResultFallbacks(t *testing.T) {
+	base := time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC)
+	now := base
+	p := NewParser()
+	p.now = func() time.Time { return now }
+	p.ParseLine([]byte(`{"type":"init","model":"gemini-2.5-pro"}`))
+	now = base.Add(1500 * time.Millisecond)
+
+	event := p.ParseLine([]byte(`{"type":"result","status":"error","error":{"type":"FatalTurnLimitedError","message":"max turns"},"stats":{"input_tokens":100,"output_tokens":20}}`))
+	if event == nil || event.Type != engine.EventResult {
+		t.Fatalf("expected EventResult, got %+v", event)
+	}
+	if event.Data.Success {
+		t.Error("expected Success=false for error status")
+	}
+	if event.Data.Tokens != 120 {
+		t.Errorf("expected summed tokens 120, got %d", event.Data.Tokens)
+	}
+	if event.Data.DurationMs != 1500 {
+		t.Errorf("expected elapsed DurationMs=1500, got %v", event.Data.DurationMs)
+	}
+}
@@ -15,6 +15,31 @@ type PRD struct {
 	Description string      `json:"description"`
 	UserStories []UserStory `json:"userStories"`
 	Tasks       []UserStory `json:"tasks,omitempty"`
+	// PlanningAnswers records how hal plan's clarifying questions were answered.
+	PlanningAnswers []PlanningAnswer `json:"planningAnswers,omitempty"`
+	// Issues are the GitHub issues this PRD was planned from.
+	Issues []IssueRef `json:"issues,omitempty"`
+	// Template names the .hal/templates/prd template the PRD was planned
+	// from; hal validate checks its constraints.
+	Template string `json:"template,omitempty"`
+}
+
+// IssueRef links a PRD to a GitHub issue.
+type IssueRef struct {
+	Repo   string `json:"repo"` // owner/name
+	Number int    `json:"number"`
+	Title  string `json:"title,omitempty"`
+	URL    string `json:"url,omitempty"`
+	// ReportedStories lists story IDs already announced in a progress comment.
+	ReportedStories []string `json:"reportedStories,omitempty"`
+}
+
+// PlanningAnswer is one answered clarifying question from hal plan.
+type PlanningAnswer struct {
+	Number   int    `json:"number"`
+	Question string `json:"question"`
+	Answer   string `json:"answer"`
+	Source   string `json:"source"` // "interactive", "answers-file", "recommended" or "engine"
 }
 
 // UserStory represents a single user story in the PRD.
@@ -26,6 +51,11 @@ type UserStory struct {
 	Priority           int      `json:"priority"`
 	Passes             bool     `json:"passes"`
 	Notes              string   `json:"notes"`
+	// Skipped stories are left out of the run and of progress counts.
+	Skipped bool `json:"skipped,omitempty"`
+	// Verify lists shell commands hal runs after the story is marked
+	// passing; if any fails the story is reset to passes: false.
+	Verify []string `json:"verify,omitempty"`
 }
 
 // LoadPRD reads and parses the default prd.json file (manual flow).
@@ -49,8 +79,42 @@ func LoadPRDFile(dir, filename string) (*PRD, error) {
 	return &prd, nil
 }
 
+// SavePRD writes the default prd.json file (manual flow).
+func SavePRD(dir string, prd *PRD) error {
+	return SavePRDFile(dir, template.PRDFile, prd)
+}
+
+// SavePRDFile writes a PRD atomically: the JSON goes to a temporary file in
+// the same directory which then replaces the target.
+func SavePRDFile(dir, filename string, prd *PRD) error {
+	data, err := json.MarshalIndent(prd, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	tmp, err := os.CreateTemp(dir, "."+filename+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, filepath.Join(dir, filename))
+}
+
 // CurrentStory returns the highest priority story that hasn't passed yet.
-// Returns nil if all stories have passed.
+// Skipped stories are ignored. Returns nil if all stories have passed.
 // Checks UserStories first, then Tasks for backward compatibility.
 func (p *PRD) CurrentStory() *UserStory {
 	var current *UserStory
@@ -58,7 +122,7 @@ func (p *PRD) CurrentStory() *UserStory {
 	// Check UserStories first (backward compatible)
 	for i := range p.UserStories {
 		story := &p.UserStories[i]
-		if story.Passes {
+		if story.Passes || story.Skipped {
 			continue
 		}
 		if current == nil || story.Priority < current.Priority {
@@ -70,7 +134,7 @@ func (p *PRD) CurrentStory() *UserStory {
 	if current == nil {
 		for i := range p.Tasks {
 			story := &p.Tasks[i]
-			if story.Passes {
+			if story.Passes || story.Skipped {
 				continue
 			}
 			if current == nil || story.Priority < current.Priority {
@@ -84,18 +148,18 @@ func (p *PRD) CurrentStory() *UserStory {
 
 // Progress returns (completed, total) story counts.
 // Counts both UserStories and Tasks for dual-format support.
+// Skipped stories are not counted.
 func (p *PRD) Progress() (int, int) {
-	completed := 0
-	total := len(p.UserStories) + len(p.Tasks)
-
-	for _, story := range p.UserStories {
-		if story.Passes {
-			completed++
-		}
-	}
-	for _, task := range p.Tasks {
-		if task.Passes {
-			completed++
+	completed, total := 0, 0
+	for _, stories := range [][]UserStory{p.UserStories, p.Tasks} {
+		for _, story := range stories {
+			if story.Skipped {
+				continue
+			}
+			total++
+			if story.Passes {
+				completed++
+			}
 		}
 	}
 
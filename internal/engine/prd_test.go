@@ -274,3 +274,45 @@ func TestPRD_JSONSerialization_TasksOmitEmpty(t *testing.T) {
 		t.Error("expected 'tasks' to be omitted when empty, but it was present")
 	}
 }
+
+func TestPRD_SkippedStoriesAreIgnored(t *testing.T) {
+	prd := &PRD{
+		UserStories: []UserStory{
+			{ID: "US-001", Priority: 1, Skipped: true},
+			{ID: "US-002", Priority: 2, Passes: true},
+			{ID: "US-003", Priority: 3},
+		},
+	}
+
+	if story := prd.CurrentStory(); story == nil || story.ID != "US-003" {
+		t.Fatalf("CurrentStory() = %v, want US-003", story)
+	}
+	if completed, total := prd.Progress(); completed != 1 || total != 2 {
+		t.Errorf("Progress() = %d/%d, want 1/2 with skipped story excluded", completed, total)
+	}
+
+	prd.UserStories[2].Passes = true
+	if story := prd.CurrentStory(); story != nil {
+		t.Errorf("CurrentStory() = %s, want nil when only skipped stories remain", story.ID)
+	}
+}
+
+func TestSavePRD_RoundTripsAndLeavesNoTempFiles(t *testing.T) {
+	dir := t.TempDir()
+	prd := &PRD{BranchName: "hal/x", UserStories: []UserStory{{ID: "US-001", Title: "Story", Skipped: true}}}
+
+	if err := SavePRD(dir, prd); err != nil {
+		t.Fatalf("SavePRD() error = %v", err)
+	}
+	loaded, err := LoadPRD(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !loaded.UserStories[0].Skipped || loaded.BranchName != "hal/x" {
+		t.Errorf("loaded = %+v", loaded)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("dir has %d entries, want only %s", len(entries), template.PRDFile)
+	}
+}
@@ -0,0 +1,257 @@
+package loop
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jywlabs/hal/internal/engine"
+	"github.com/jywlabs/hal/internal/prd"
+	"github.com/jywlabs/hal/internal/runstatus"
+	"github.com/jywlabs/hal/internal/template"
+)
+
+// controlPollInterval is how often a paused loop re-reads the control file.
+// Injectable for testing.
+var controlPollInterval = time.Second
+
+// RunControl holds the hal ctl requests for a running loop, stored in
+// .hal/run-control.json. The loop applies them between iterations.
+type RunControl struct {
+	Paused           bool        `json:"paused,omitempty"`
+	StopAfterCurrent bool        `json:"stopAfterCurrent,omitempty"`
+	Skip             []string    `json:"skip,omitempty"`
+	Moves            []StoryMove `json:"moves,omitempty"`
+	UpdatedAt        time.Time   `json:"updatedAt"`
+}
+
+// StoryMove is a pending hal ctl reprioritize request. Position is 1-based;
+// Before and After name another story.
+type StoryMove struct {
+	StoryID  string `json:"storyId"`
+	Position int    `json:"position,omitempty"`
+	Before   string `json:"before,omitempty"`
+	After    string `json:"after,omitempty"`
+}
+
+// LoadRunControl reads .hal/run-control.json. A missing file yields an
+// empty control.
+func LoadRunControl(dir string) (*RunControl, error) {
+	data, err := os.ReadFile(filepath.Join(dir, template.RunControlFile))
+	if os.IsNotExist(err) {
+		return &RunControl{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", template.RunControlFile, err)
+	}
+	var control RunControl
+	if err := json.Unmarshal(data, &control); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", template.RunControlFile, err)
+	}
+	return &control, nil
+}
+
+// controlLockTimeout bounds how long UpdateRunControl waits for another
+// writer, and controlLockStaleAge is when a leftover lock from a crashed
+// writer is taken over.
+const (
+	controlLockTimeout  = 5 * time.Second
+	controlLockStaleAge = 30 * time.Second
+)
+
+// UpdateRunControl applies fn to the control file and writes it back
+// atomically. hal ctl and the loop both update the file, so the
+// read-modify-write runs under run-control.json.lock.
+func UpdateRunControl(dir string, fn func(*RunControl)) error {
+	unlock, err := lockRunControl(dir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	control, err := LoadRunControl(dir)
+	if err != nil {
+		return err
+	}
+	fn(control)
+	control.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(control, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, template.RunControlFile+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", template.RunControlFile, err)
+	}
+	_, err = tmp.Write(append(data, '\n'))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), filepath.Join(dir, template.RunControlFile))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write %s: %w", template.RunControlFile, err)
+	}
+	return nil
+}
+
+// lockRunControl takes the control file lock, waiting up to
+// controlLockTimeout. The returned function releases it.
+func lockRunControl(dir string) (func(), error) {
+	path := filepath.Join(dir, template.RunControlFile+".lock")
+	deadline := time.Now().Add(controlLockTimeout)
+	for {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			fmt.Fprintf(f, "%d\n", os.Getpid())
+			f.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to lock %s: %w", template.RunControlFile, err)
+		}
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > controlLockStaleAge {
+			os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("failed to lock %s: another hal process holds %s", template.RunControlFile, path)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// clearControl drops requests left from an earlier run.
+func (r *Runner) clearControl() {
+	path := filepath.Join(r.config.Dir, template.RunControlFile)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		r.display.ShowInfo("   ⚠ Could not clear %s: %v\n", template.RunControlFile, err)
+	}
+}
+
+// applyControl applies pending skip and reprioritize requests before an
+// iteration and waits while the run is paused. It reports whether stories
+// changed and whether the loop should stop.
+func (r *Runner) applyControl(ctx context.Context, status *runstatus.Status) (changed, stop bool, err error) {
+	for {
+		control, err := LoadRunControl(r.config.Dir)
+		if err != nil {
+			r.display.ShowInfo("   ⚠ %v\n", err)
+			return changed, false, nil
+		}
+		if len(control.Skip) > 0 || len(control.Moves) > 0 {
+			r.applyStoryRequests(control.Skip, control.Moves)
+			skips, moves := len(control.Skip), len(control.Moves)
+			// Drop only the requests applied; hal ctl may have appended more.
+			if err := UpdateRunControl(r.config.Dir, func(c *RunControl) {
+				c.Skip = c.Skip[min(skips, len(c.Skip)):]
+				c.Moves = c.Moves[min(moves, len(c.Moves)):]
+			}); err != nil {
+				r.display.ShowInfo("   ⚠ %v\n", err)
+			}
+			changed = true
+		}
+		if control.StopAfterCurrent {
+			return changed, true, nil
+		}
+		if !control.Paused {
+			if status.State == runstatus.StatePaused {
+				r.display.ShowInfo("   ▶ Resumed by hal ctl\n")
+				status.State = runstatus.StateRunning
+				r.writeStatus(status)
+			}
+			return changed, false, nil
+		}
+		if status.State != runstatus.StatePaused {
+			r.display.ShowInfo("   ⏸ Paused by hal ctl; run 'hal ctl resume' to continue\n")
+			status.State = runstatus.StatePaused
+			r.writeStatus(status)
+		}
+		select {
+		case <-ctx.Done():
+			return changed, false, ctx.Err()
+		case <-time.After(controlPollInterval):
+		}
+	}
+}
+
+// applyStoryRequests skips and moves stories in the PRD. Requests that no
+// longer apply, such as skipping a story that already passes, are reported
+// and dropped.
+func (r *Runner) applyStoryRequests(skips []string, moves []StoryMove) {
+	doc, err := engine.LoadPRDFile(r.config.Dir, r.config.PRDFile)
+	if err != nil {
+		r.display.ShowInfo("   ⚠ Could not apply hal ctl requests: %v\n", err)
+		return
+	}
+	for _, id := range skips {
+		if _, err := prd.SkipStory(doc, id, "hal ctl skip"); err != nil {
+			r.display.ShowInfo("   ⚠ hal ctl skip %s: %v\n", id, err)
+			continue
+		}
+		r.display.ShowInfo("   ⏭ Skipped %s (hal ctl)\n", id)
+	}
+	for _, m := range moves {
+		order, err := prd.MoveStory(doc, m.StoryID, prd.MoveTarget{Position: m.Position, Before: m.Before, After: m.After})
+		if err != nil {
+			r.display.ShowInfo("   ⚠ hal ctl reprioritize %s: %v\n", m.StoryID, err)
+			continue
+		}
+		r.display.ShowInfo("   ↕ Reprioritized %s (hal ctl): %s\n", m.StoryID, strings.Join(order, ", "))
+	}
+	if err := engine.SavePRDFile(r.config.Dir, r.config.PRDFile, doc); err != nil {
+		r.display.ShowInfo("   ⚠ Could not save hal ctl changes: %v\n", err)
+	}
+}
+
+// stopAfterIteration reports whether hal ctl stop-after-current was
+// requested. Work the agent left uncommitted is committed so the tree is
+// clean when the loop exits.
+func (r *Runner) stopAfterIteration(storyID string) bool {
+	control, err := LoadRunControl(r.config.Dir)
+	if err != nil || !control.StopAfterCurrent {
+		return false
+	}
+	message := "chore: save work in progress (stopped by hal ctl)"
+	if storyID != "" {
+		message = fmt.Sprintf("chore: [%s] - save work in progress (stopped by hal ctl)", storyID)
+	}
+	committed, err := commitPendingWork(filepath.Dir(r.config.Dir), message)
+	switch {
+	case err != nil:
+		r.display.ShowInfo("   ⚠ Could not commit pending work: %v\n", err)
+	case committed:
+		r.display.ShowInfo("   ✓ Committed pending work: %s\n", message)
+	}
+	r.display.ShowInfo("   ■ Stopped by hal ctl after the current iteration\n")
+	return true
+}
+
+// commitPendingWork commits all changes in the repository at dir and reports
+// whether there was anything to commit. Injectable for testing.
+var commitPendingWork = func(dir, message string) (bool, error) {
+	status := exec.Command("git", "status", "--porcelain")
+	status.Dir = dir
+	out, err := status.Output()
+	if err != nil {
+		return false, fmt.Errorf("git status failed: %w", err)
+	}
+	if strings.TrimSpace(string(out)) == "" {
+		return false, nil
+	}
+	for _, args := range [][]string{{"add", "-A"}, {"commit", "-m", message}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return false, fmt.Errorf("git %s failed: %s", args[0], strings.TrimSpace(string(out)))
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,207 @@
+package loop
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jywlabs/hal/internal/engine"
+	"github.com/jywlabs/hal/internal/runstatus"
+	"github.com/jywlabs/hal/internal/template"
+)
+
+// controlEngine sends hal ctl requests while its first iteration runs.
+type controlEngine struct {
+	fakeEngine
+	halDir  string
+	request func(*RunControl)
+}
+
+func (c *controlEngine) Execute(ctx context.Context, prompt string, display *engine.Display) engine.Result {
+	if c.calls == 0 {
+		_ = UpdateRunControl(c.halDir, c.request)
+	}
+	return c.fakeEngine.Execute(ctx, prompt, display)
+}
+
+func newControlRunner(halDir string, eng engine.Engine, maxIterations int) (*Runner, *bytes.Buffer) {
+	var logBuf bytes.Buffer
+	return &Runner{
+		config: Config{
+			Dir:           halDir,
+			PRDFile:       "prd.json",
+			ProgressFile:  "progress.txt",
+			MaxIterations: maxIterations,
+			Logger:        &logBuf,
+			RetryDelay:    time.Millisecond,
+		},
+		engine:  eng,
+		display: engine.NewDisplay(&logBuf),
+	}, &logBuf
+}
+
+func controlTestStories() []engine.UserStory {
+	return []engine.UserStory{
+		{ID: "US-001", Title: "Schema", Priority: 1},
+		{ID: "US-002", Title: "Endpoint", Priority: 2},
+		{ID: "US-003", Title: "Docs", Priority: 3},
+	}
+}
+
+func TestRun_StopAfterCurrentCommitsAndStops(t *testing.T) {
+	halDir := setupTestHalDir(t, controlTestStories())
+
+	var commits []string
+	origCommit := commitPendingWork
+	commitPendingWork = func(dir, message string) (bool, error) {
+		commits = append(commits, message)
+		return true, nil
+	}
+	t.Cleanup(func() { commitPendingWork = origCommit })
+
+	eng := &controlEngine{halDir: halDir, request: func(c *RunControl) { c.StopAfterCurrent = true }}
+	runner, _ := newControlRunner(halDir, eng, 5)
+
+	result := runner.Run(context.Background())
+
+	if !result.Stopped || !result.Success || result.Complete || result.Iterations != 1 || eng.calls != 1 {
+		t.Fatalf("result = %+v after %d calls, want stopped after 1 iteration", result, eng.calls)
+	}
+	if len(commits) != 1 || !strings.Contains(commits[0], "US-001") {
+		t.Fatalf("commits = %v, want one commit for US-001", commits)
+	}
+	if _, err := os.Stat(filepath.Join(halDir, template.RunControlFile)); !os.IsNotExist(err) {
+		t.Fatalf("control file should be removed when the loop ends, stat err = %v", err)
+	}
+	if status, _ := runstatus.Load(halDir); status == nil || status.State != runstatus.StateStopped {
+		t.Fatalf("run status = %+v, want stopped", status)
+	}
+}
+
+func TestRun_AppliesSkipAndReprioritizeBetweenIterations(t *testing.T) {
+	halDir := setupTestHalDir(t, controlTestStories())
+
+	eng := &controlEngine{halDir: halDir, request: func(c *RunControl) {
+		c.Skip = append(c.Skip, "US-001")
+		c.Moves = append(c.Moves, StoryMove{StoryID: "US-003", Position: 1})
+	}}
+	runner, logBuf := newControlRunner(halDir, eng, 2)
+
+	result := runner.Run(context.Background())
+
+	if result.Error != nil || eng.calls != 2 {
+		t.Fatalf("result = %+v after %d calls, want 2 iterations", result, eng.calls)
+	}
+	if result.LastStoryID != "US-003" {
+		t.Fatalf("second iteration worked %s, want reprioritized US-003\n%s", result.LastStoryID, logBuf.String())
+	}
+	prd, err := engine.LoadPRDFile(halDir, "prd.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := prd.FindStoryByID("US-001"); !s.Skipped || !strings.Contains(s.Notes, "hal ctl") {
+		t.Fatalf("US-001 = %+v, want skipped with a note", s)
+	}
+	if s := prd.FindStoryByID("US-003"); s.Priority != 1 {
+		t.Fatalf("US-003 priority = %d, want 1", s.Priority)
+	}
+}
+
+func TestRun_PauseWaitsForResume(t *testing.T) {
+	halDir := setupTestHalDir(t, controlTestStories())
+
+	origPoll := controlPollInterval
+	controlPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { controlPollInterval = origPoll })
+
+	sawPaused := make(chan bool, 1)
+	go func() {
+		deadline := time.Now().Add(10 * time.Second)
+		for time.Now().Before(deadline) {
+			if status, _ := runstatus.Load(halDir); status != nil && status.State == runstatus.StatePaused {
+				_ = UpdateRunControl(halDir, func(c *RunControl) { c.Paused = false })
+				sawPaused <- true
+				return
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+		sawPaused <- false
+	}()
+
+	eng := &controlEngine{halDir: halDir, request: func(c *RunControl) { c.Paused = true }}
+	runner, logBuf := newControlRunner(halDir, eng, 2)
+
+	result := runner.Run(context.Background())
+
+	if !<-sawPaused {
+		t.Fatalf("loop never reported paused\n%s", logBuf.String())
+	}
+	if result.Error != nil || eng.calls != 2 {
+		t.Fatalf("result = %+v after %d calls, want 2 iterations after resume", result, eng.calls)
+	}
+	if !strings.Contains(logBuf.String(), "Resumed by hal ctl") {
+		t.Fatalf("log missing resume notice:\n%s", logBuf.String())
+	}
+}
+
+func TestApplyControl_StopsWhilePausedOnContextCancel(t *testing.T) {
+	halDir := setupTestHalDir(t, controlTestStories())
+	origPoll := controlPollInterval
+	controlPollInterval = 5 * time.Millisecond
+	t.Cleanup(func() { controlPollInterval = origPoll })
+
+	if err := UpdateRunControl(halDir, func(c *RunControl) { c.Paused = true }); err != nil {
+		t.Fatal(err)
+	}
+	runner, _ := newControlRunner(halDir, &fakeEngine{}, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+
+	_, stop, err := runner.applyControl(ctx, &runstatus.Status{State: runstatus.StateRunning})
+
+	if stop || err == nil {
+		t.Fatalf("applyControl = stop %v, err %v; want context error", stop, err)
+	}
+}
+
+func TestUpdateRunControl_ConcurrentWritersKeepAllRequests(t *testing.T) {
+	halDir := t.TempDir()
+	const writers = 20
+
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- UpdateRunControl(halDir, func(c *RunControl) {
+				c.Skip = append(c.Skip, fmt.Sprintf("US-%03d", i))
+			})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("UpdateRunControl() error = %v", err)
+		}
+	}
+
+	control, err := LoadRunControl(halDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(control.Skip) != writers {
+		t.Fatalf("skip = %v, want %d requests", control.Skip, writers)
+	}
+	entries, _ := os.ReadDir(halDir)
+	if len(entries) != 1 {
+		t.Fatalf("leftover files in .hal: %v", entries)
+	}
+}
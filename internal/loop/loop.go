@@ -6,10 +6,11 @@ import (
 	"io"
 	"os"
 	"path/filepath"
-	"strings"
+	"sync"
 	"time"
 
 	"github.com/jywlabs/hal/internal/engine"
+	"github.com/jywlabs/hal/internal/runstatus"
 	"github.com/jywlabs/hal/internal/standards"
 	"github.com/jywlabs/hal/internal/template"
 )
@@ -18,31 +19,36 @@ const maxFalseCompletes = 2
 
 // Result represents the outcome of the loop execution.
 type Result struct {
-	Iterations       int           // Number of iterations run
-	Complete         bool          // Whether all tasks were completed
-	Success          bool          // Whether the loop finished successfully
-	Error            error         // Any error that occurred
-	Duration         time.Duration // Wall-clock time for the entire loop
-	CompletedStories int           // Number of stories marked as complete
-	TotalStories     int           // Total number of stories in the PRD
-	LastStoryID      string        // ID of the last story worked on
-	LastStoryTitle   string        // Title of the last story worked on
+	Iterations       int             // Number of iterations run
+	Complete         bool            // Whether all tasks were completed
+	Success          bool            // Whether the loop finished successfully
+	Error            error           // Any error that occurred
+	Duration         time.Duration   // Wall-clock time for the entire loop
+	CompletedStories int             // Number of stories marked as complete
+	TotalStories     int             // Total number of stories in the PRD
+	LastStoryID      string          // ID of the last story worked on
+	LastStoryTitle   string          // Title of the last story worked on
+	Splits           []StorySplit    // Stories split into sub-stories after stalling
+	VerifyFailures   []VerifyFailure // Stories reset because their verify commands failed
+	Stopped          bool            // Whether hal ctl stop-after-current ended the loop
 }
 
 // Config holds configuration for the loop.
 type Config struct {
-	Dir           string               // Path to .hal directory
-	PRDFile       string               // PRD file name (default: template.PRDFile)
-	ProgressFile  string               // Progress file name (default: template.ProgressFile)
-	BaseBranch    string               // Base branch for creating PRD branch (injected into prompt)
-	MaxIterations int                  // Maximum iterations (0 = unlimited)
-	Engine        string               // Engine name (claude, codex, pi)
-	EngineConfig  *engine.EngineConfig // Optional per-engine config (model, provider)
-	Logger        io.Writer            // Where to write logs
-	RetryDelay    time.Duration        // Delay between retries on failure
-	MaxRetries    int                  // Max retries per iteration on failure
-	DryRun        bool                 // Show what would execute without running
-	StoryID       string               // Run specific story by ID (e.g., US-001)
+	Dir             string               // Path to .hal directory
+	PRDFile         string               // PRD file name (default: template.PRDFile)
+	ProgressFile    string               // Progress file name (default: template.ProgressFile)
+	BaseBranch      string               // Base branch for creating PRD branch (injected into prompt)
+	MaxIterations   int                  // Maximum iterations (0 = unlimited)
+	Engine          string               // Engine name (claude, codex, gemini, pi)
+	EngineConfig    *engine.EngineConfig // Optional per-engine config (model, provider)
+	Logger          io.Writer            // Where to write logs
+	RetryDelay      time.Duration        // Delay between retries on failure
+	MaxRetries      int                  // Max retries per iteration on failure
+	DryRun          bool                 // Show what would execute without running
+	StoryID         string               // Run specific story by ID (e.g., US-001)
+	SplitAfter      int                  // Split a story after this many iterations without passing (0 = never)
+	StandardsBudget int                  // Max characters of standards injected per iteration (0 = unlimited)
 }
 
 // Runner orchestrates the Hal loop.
@@ -50,6 +56,9 @@ type Runner struct {
 	config  Config
 	engine  engine.Engine
 	display *engine.Display
+
+	statusMu   sync.Mutex // guards lastStatus between the loop and its heartbeat
+	lastStatus runstatus.Status
 }
 
 type progressState struct {
@@ -59,6 +68,21 @@ type progressState struct {
 
 // New creates a new loop Runner.
 func New(cfg Config) (*Runner, error) {
+	cfg = withDefaults(cfg)
+	eng, err := engine.NewWithConfig(cfg.Engine, cfg.EngineConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Runner{
+		config:  cfg,
+		engine:  eng,
+		display: engine.NewDisplay(cfg.Logger),
+	}, nil
+}
+
+// withDefaults fills in unset Config fields.
+func withDefaults(cfg Config) Config {
 	if cfg.Dir == "" {
 		cfg.Dir = template.HalDir
 	}
@@ -83,17 +107,30 @@ func New(cfg Config) (*Runner, error) {
 	if cfg.MaxRetries <= 0 {
 		cfg.MaxRetries = 3
 	}
+	return cfg
+}
 
-	eng, err := engine.NewWithConfig(cfg.Engine, cfg.EngineConfig)
-	if err != nil {
-		return nil, err
+// PreviewPrompt renders the prompt hal run would send on its first
+// iteration, targeting cfg.StoryID or else the next pending story. The
+// returned story is nil when no story is pending.
+func PreviewPrompt(cfg Config) (string, *engine.UserStory, error) {
+	r := &Runner{config: withDefaults(cfg)}
+	var story *engine.UserStory
+	if prd, err := engine.LoadPRDFile(r.config.Dir, r.config.PRDFile); err == nil {
+		if cfg.StoryID != "" {
+			story = prd.FindStoryByID(cfg.StoryID)
+		} else {
+			story = prd.CurrentStory()
+		}
 	}
-
-	return &Runner{
-		config:  cfg,
-		engine:  eng,
-		display: engine.NewDisplay(cfg.Logger),
-	}, nil
+	if cfg.StoryID != "" && story == nil {
+		return "", nil, fmt.Errorf("story not found: %s", cfg.StoryID)
+	}
+	if _, warning, err := parsePrompt(r.config.Dir, r.promptData(story, 1, nil)); err == nil && warning != "" {
+		fmt.Fprintf(r.config.Logger, "⚠ %s\n", warning)
+	}
+	prompt, err := RenderPrompt(r.config.Dir, r.promptData(story, 1, nil))
+	return prompt, story, err
 }
 
 // Run executes the Hal loop.
@@ -107,14 +144,18 @@ func (r *Runner) Run(ctx context.Context) (result Result) {
 		}
 	}()
 
-	// Load prompt
-	prompt, err := r.loadPrompt()
+	// Check the prompt parses before starting; each iteration renders it
+	// with its story, standards and the previous outcome.
+	_, warning, err := parsePrompt(r.config.Dir, r.promptData(nil, 0, nil))
 	if err != nil {
 		return Result{
 			Success: false,
 			Error:   fmt.Errorf("failed to load prompt: %w", err),
 		}
 	}
+	if warning != "" {
+		r.display.ShowInfo("   ⚠ %s\n", warning)
+	}
 
 	// Verify PRD file exists
 	prdPath := filepath.Join(r.config.Dir, r.config.PRDFile)
@@ -181,41 +222,107 @@ func (r *Runner) Run(ctx context.Context) (result Result) {
 		Branch: branch,
 	}, r.config.MaxIterations)
 
+	status := &runstatus.Status{
+		State:         runstatus.StateRunning,
+		Engine:        r.engine.Name(),
+		PRDFile:       r.config.PRDFile,
+		PID:           os.Getpid(),
+		TargetStoryID: r.config.StoryID,
+		MaxIterations: r.config.MaxIterations,
+		StartedAt:     loopStart,
+	}
+	r.writeStatus(status)
+	r.clearControl()
+	stopHeartbeat := r.startHeartbeat()
+	defer func() {
+		stopHeartbeat()
+		r.clearControl()
+		status.State = finalRunState(result)
+		if result.Error != nil {
+			status.Error = result.Error.Error()
+		}
+		r.writeStatus(status)
+	}()
+
 	result = Result{}
 	baseline := progressState{}
 	baseline.completedStories, _ = prd.Progress()
 	if story := prd.CurrentStory(); story != nil {
 		baseline.pendingStoryID = story.ID
 	}
-	falseCompletes := 0 // Track consecutive false COMPLETE signals
+	feedback := ""                 // Gate feedback appended to later iteration prompts
+	falseCompletes := 0            // Track consecutive false COMPLETE signals
+	var previous *IterationOutcome // How the last iteration ended, for the next prompt
+	splits := newSplitTracker()
 
 	for i := 1; i <= r.config.MaxIterations; i++ {
-		// Load PRD to get current story info
+		// Apply hal ctl requests and wait while paused
+		changed, stop, err := r.applyControl(ctx, status)
+		if err != nil {
+			result.Error = err
+			return result
+		}
+		if stop {
+			r.display.ShowInfo("   ■ Stopped by hal ctl\n")
+			result.Stopped = true
+			result.Success = true
+			return result
+		}
+		if changed && r.config.StoryID == "" {
+			if prd, err := engine.LoadPRDFile(r.config.Dir, r.config.PRDFile); err == nil && prd.CurrentStory() == nil {
+				r.display.ShowSuccess("All tasks complete!")
+				result.Complete = true
+				result.Success = true
+				return result
+			}
+		}
+
+		// Load PRD to get current story info. A --story target is looked up
+		// again so edits made by earlier iterations reach the prompt.
 		var storyInfo *engine.StoryInfo
-		if r.config.StoryID != "" {
-			// Running specific story
-			storyInfo = &engine.StoryInfo{
-				ID:    targetStory.ID,
-				Title: targetStory.Title,
+		var currentStory *engine.UserStory
+		if prd, err := engine.LoadPRDFile(r.config.Dir, r.config.PRDFile); err == nil {
+			if r.config.StoryID != "" {
+				currentStory = prd.FindStoryByID(r.config.StoryID)
+			} else {
+				currentStory = prd.CurrentStory()
 			}
-		} else if prd, err := engine.LoadPRDFile(r.config.Dir, r.config.PRDFile); err == nil {
-			if story := prd.CurrentStory(); story != nil {
-				storyInfo = &engine.StoryInfo{
-					ID:    story.ID,
-					Title: story.Title,
-				}
+		}
+		if currentStory == nil && r.config.StoryID != "" {
+			currentStory = targetStory
+		}
+		if currentStory != nil {
+			storyInfo = &engine.StoryInfo{
+				ID:    currentStory.ID,
+				Title: currentStory.Title,
 			}
 		}
 
 		r.display.ShowIterationHeader(i, r.config.MaxIterations, storyInfo)
+		status.Iteration = i
+		status.StoryID, status.StoryTitle = "", ""
+		if storyInfo != nil {
+			status.StoryID, status.StoryTitle = storyInfo.ID, storyInfo.Title
+		}
+		r.writeStatus(status)
 
 		// Track which story this iteration worked on
+		workedStoryID := ""
 		if storyInfo != nil {
+			workedStoryID = storyInfo.ID
 			result.LastStoryID = storyInfo.ID
 			result.LastStoryTitle = storyInfo.Title
 		}
 
+		prompt, err := RenderPrompt(r.config.Dir, r.promptData(currentStory, i, previous))
+		if err != nil {
+			result.Error = fmt.Errorf("failed to load prompt: %w", err)
+			return result
+		}
+		prompt += feedback
+
 		// Execute with retry
+		passingBefore := r.passingStoryIDs()
 		execResult := r.executeWithRetry(ctx, prompt)
 		result.Iterations = i
 
@@ -226,6 +333,16 @@ func (r *Runner) Run(ctx context.Context) (result Result) {
 			return result
 		}
 
+		// Run verify commands of stories the agent just marked passing.
+		// A rejected story makes any COMPLETE signal premature.
+		failures := r.verifyNewlyPassing(ctx, passingBefore)
+		if len(failures) > 0 {
+			result.VerifyFailures = append(result.VerifyFailures, failures...)
+			feedback += verifyFeedback(i, r.config.PRDFile, failures)
+			execResult.Complete = false
+		}
+		previous = r.iterationOutcome(i, workedStoryID, failures)
+
 		if execResult.Complete {
 			// Verify that all stories actually have passes: true before accepting COMPLETE
 			// This guards against LLM reasoning errors where it says COMPLETE prematurely
@@ -238,6 +355,8 @@ func (r *Runner) Run(ctx context.Context) (result Result) {
 					baseline.completedStories = completedStories
 					baseline.pendingStoryID = story.ID
 					falseCompletes++
+					previous.Outcome = OutcomeRejectedComplete
+					previous.Error = fmt.Sprintf("signaled COMPLETE while %s is still pending", story.ID)
 					// There are still pending stories - LLM said COMPLETE incorrectly
 					r.display.ShowInfo("   ⚠ Agent signaled COMPLETE but %s is still pending (attempt %d)\n", story.ID, falseCompletes)
 					if falseCompletes >= maxFalseCompletes {
@@ -249,7 +368,7 @@ func (r *Runner) Run(ctx context.Context) (result Result) {
 					// Inject feedback into the prompt so the next iteration
 					// has different input and can break out of the loop.
 					// See: https://github.com/j-yw/hal/issues/29
-					prompt += fmt.Sprintf(
+					feedback += fmt.Sprintf(
 						"\n\n## IMPORTANT — Iteration %d Feedback\n"+
 							"You signaled COMPLETE but story **%s** still has `passes: false` in `%s`.\n"+
 							"Your COMPLETE signal was REJECTED because the gate check re-read the PRD and found pending stories.\n\n"+
@@ -267,7 +386,13 @@ func (r *Runner) Run(ctx context.Context) (result Result) {
 						r.config.ProgressFile, r.config.PRDFile, r.config.ProgressFile,
 					)
 
+					r.checkStalledStory(ctx, splits, workedStoryID, &result)
 					r.display.ShowIterationComplete(i)
+					if r.stopAfterIteration(workedStoryID) {
+						result.Stopped = true
+						result.Success = true
+						return result
+					}
 					// Continue to next iteration
 					select {
 					case <-ctx.Done():
@@ -285,7 +410,13 @@ func (r *Runner) Run(ctx context.Context) (result Result) {
 			return result
 		}
 
+		r.checkStalledStory(ctx, splits, workedStoryID, &result)
 		r.display.ShowIterationComplete(i)
+		if r.stopAfterIteration(workedStoryID) {
+			result.Stopped = true
+			result.Success = true
+			return result
+		}
 		if prd, err := engine.LoadPRDFile(r.config.Dir, r.config.PRDFile); err == nil {
 			baseline.completedStories, _ = prd.Progress()
 			if story := prd.CurrentStory(); story != nil {
@@ -311,35 +442,16 @@ func (r *Runner) Run(ctx context.Context) (result Result) {
 	return result
 }
 
-// loadPrompt reads the prompt file and replaces placeholders.
-func (r *Runner) loadPrompt() (string, error) {
-	promptPath := filepath.Join(r.config.Dir, template.PromptFile)
-	data, err := os.ReadFile(promptPath)
-	if err != nil {
-		return "", err
-	}
-
-	// Replace placeholders with actual runtime values
-	prompt := string(data)
-	prompt = strings.Replace(prompt, "{{PRD_FILE}}", r.config.PRDFile, -1)
-	prompt = strings.Replace(prompt, "{{PROGRESS_FILE}}", r.config.ProgressFile, -1)
-
-	baseBranch := r.config.BaseBranch
-	if baseBranch == "" {
-		baseBranch = "HEAD"
-	}
-	prompt = strings.Replace(prompt, "{{BASE_BRANCH}}", baseBranch, -1)
-
-	// Inject project standards
-	standardsContent, err := standards.Load(r.config.Dir)
+// selectStandards picks the project standards relevant to story within the
+// configured budget.
+func (r *Runner) selectStandards(story *engine.UserStory) standards.Selection {
+	all, err := standards.LoadAll(r.config.Dir)
 	if err != nil {
 		// Non-fatal — log warning and continue without standards
 		fmt.Fprintf(r.config.Logger, "warning: failed to load standards: %v\n", err)
-		standardsContent = ""
+		return standards.Selection{}
 	}
-	prompt = strings.Replace(prompt, "{{STANDARDS}}", standardsContent, -1)
-
-	return prompt, nil
+	return standards.Select(all, standards.StoryContext(story), r.config.StandardsBudget)
 }
 
 // executeWithRetry runs a single iteration with retry on failure.
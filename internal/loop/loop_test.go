@@ -81,7 +81,7 @@ func TestContainsIgnoreCase(t *testing.T) {
 	}
 }
 
-func TestLoadPrompt_InjectsStandards(t *testing.T) {
+func TestRenderPrompt_InjectsStandards(t *testing.T) {
 	halDir := t.TempDir()
 
 	// Write a prompt template with the {{STANDARDS}} placeholder
@@ -129,9 +129,9 @@ You are an autonomous coding agent.
 		},
 	}
 
-	prompt, err := r.loadPrompt()
+	prompt, err := RenderPrompt(halDir, r.promptData(nil, 0, nil))
 	if err != nil {
-		t.Fatalf("loadPrompt() error: %v", err)
+		t.Fatalf("RenderPrompt() error: %v", err)
 	}
 
 	// Placeholder should be gone
@@ -165,7 +165,7 @@ You are an autonomous coding agent.
 	}
 }
 
-func TestLoadPrompt_NoStandardsGraceful(t *testing.T) {
+func TestRenderPrompt_NoStandardsGraceful(t *testing.T) {
 	halDir := t.TempDir()
 
 	// Write a prompt template with the placeholder but NO standards directory
@@ -184,9 +184,9 @@ func TestLoadPrompt_NoStandardsGraceful(t *testing.T) {
 		},
 	}
 
-	prompt, err := r.loadPrompt()
+	prompt, err := RenderPrompt(halDir, r.promptData(nil, 0, nil))
 	if err != nil {
-		t.Fatalf("loadPrompt() error: %v", err)
+		t.Fatalf("RenderPrompt() error: %v", err)
 	}
 
 	// Placeholder should be replaced with empty string
@@ -203,7 +203,7 @@ func TestLoadPrompt_NoStandardsGraceful(t *testing.T) {
 	}
 }
 
-func TestLoadPrompt_BaseBranchFallback(t *testing.T) {
+func TestRenderPrompt_BaseBranchFallback(t *testing.T) {
 	halDir := t.TempDir()
 
 	promptContent := "# Agent\n\nBase: {{BASE_BRANCH}}\n"
@@ -221,9 +221,9 @@ func TestLoadPrompt_BaseBranchFallback(t *testing.T) {
 		},
 	}
 
-	prompt, err := r.loadPrompt()
+	prompt, err := RenderPrompt(halDir, r.promptData(nil, 0, nil))
 	if err != nil {
-		t.Fatalf("loadPrompt() error: %v", err)
+		t.Fatalf("RenderPrompt() error: %v", err)
 	}
 
 	if strings.Contains(prompt, "{{BASE_BRANCH}}") {
@@ -234,7 +234,7 @@ func TestLoadPrompt_BaseBranchFallback(t *testing.T) {
 	}
 }
 
-func TestLoadPrompt_OldTemplateWithoutPlaceholder(t *testing.T) {
+func TestRenderPrompt_OldTemplateWithoutPlaceholder(t *testing.T) {
 	halDir := t.TempDir()
 
 	// Simulate a prompt.md that hasn't been migrated (no {{STANDARDS}})
@@ -262,9 +262,9 @@ func TestLoadPrompt_OldTemplateWithoutPlaceholder(t *testing.T) {
 		},
 	}
 
-	prompt, err := r.loadPrompt()
+	prompt, err := RenderPrompt(halDir, r.promptData(nil, 0, nil))
 	if err != nil {
-		t.Fatalf("loadPrompt() error: %v", err)
+		t.Fatalf("RenderPrompt() error: %v", err)
 	}
 
 	// Without the placeholder, standards content won't appear — but it should not crash
@@ -278,11 +278,54 @@ func TestLoadPrompt_OldTemplateWithoutPlaceholder(t *testing.T) {
 	}
 }
 
+func TestRenderPrompt_SelectsStandardsForStory(t *testing.T) {
+	halDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(halDir, template.PromptFile), []byte("# Agent\n\n{{STANDARDS}}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{
+		"global/naming.md":  "Use camelCase.",
+		"engine/adapter.md": "---\napplies_to: [\"internal/engine/**\"]\n---\nEngines self-register via init().",
+	} {
+		path := filepath.Join(halDir, template.StandardsDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := &Runner{config: Config{Dir: halDir, PRDFile: "prd.json", ProgressFile: "progress.txt", Logger: &bytes.Buffer{}}}
+
+	engineStory := &engine.UserStory{ID: "US-001", Title: "Retry", Description: "Add retries in internal/engine/claude.go"}
+	prompt, err := RenderPrompt(halDir, r.promptData(engineStory, 0, nil))
+	if err != nil {
+		t.Fatalf("RenderPrompt() error: %v", err)
+	}
+	if !strings.Contains(prompt, "self-register") || !strings.Contains(prompt, "camelCase") {
+		t.Errorf("engine story prompt missing standards:\n%s", prompt)
+	}
+	if strings.Contains(prompt, "applies_to") {
+		t.Errorf("front matter leaked into prompt:\n%s", prompt)
+	}
+
+	docsStory := &engine.UserStory{ID: "US-002", Title: "Docs", Description: "Document the README"}
+	prompt, err = RenderPrompt(halDir, r.promptData(docsStory, 0, nil))
+	if err != nil {
+		t.Fatalf("RenderPrompt() error: %v", err)
+	}
+	if strings.Contains(prompt, "self-register") || !strings.Contains(prompt, "camelCase") {
+		t.Errorf("docs story prompt should only carry the general standard:\n%s", prompt)
+	}
+}
+
 // fakeEngine is a mock engine for testing the loop.
 type fakeEngine struct {
-	calls   int
-	results []engine.Result
-	prompts []string // capture prompts passed to Execute
+	calls          int
+	results        []engine.Result
+	prompts        []string // capture prompts passed to Execute
+	promptResponse string   // returned by Prompt
 }
 
 func (f *fakeEngine) Name() string { return "fake" }
@@ -296,7 +339,7 @@ func (f *fakeEngine) Execute(_ context.Context, prompt string, _ *engine.Display
 	return engine.Result{Success: true}
 }
 func (f *fakeEngine) Prompt(_ context.Context, _ string) (string, error) {
-	return "", nil
+	return f.promptResponse, nil
 }
 func (f *fakeEngine) StreamPrompt(_ context.Context, _ string, _ *engine.Display) (string, error) {
 	return "", nil
@@ -618,3 +661,57 @@ func TestFalseCompleteCounterResetsWhenStoryAdvances(t *testing.T) {
 		t.Fatalf("engine calls = %d, want 3 after progress resets the false COMPLETE counter", fe.calls)
 	}
 }
+
+func TestRun_StoryTargetReloadedEachIteration(t *testing.T) {
+	stories := []engine.UserStory{
+		{ID: "US-001", Title: "Original title", Priority: 1},
+		{ID: "US-002", Title: "Other story", Priority: 2},
+	}
+	halDir := setupTestHalDir(t, stories)
+	if err := os.WriteFile(filepath.Join(halDir, template.PromptFile), []byte("Story: {{.Story.Title}}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var prompts []string
+	fe := &fakeEngineWithHook{
+		fakeEngine: &fakeEngine{results: []engine.Result{{Success: true}, {Success: true}}},
+		hook: func(prompt string) {
+			prompts = append(prompts, prompt)
+			if len(prompts) == 1 {
+				prd := map[string]interface{}{
+					"project":    "test",
+					"branchName": "main",
+					"userStories": []map[string]interface{}{
+						{"id": "US-001", "title": "Edited title", "priority": 1, "passes": false},
+						{"id": "US-002", "title": "Other story", "priority": 2, "passes": false},
+					},
+				}
+				data, _ := json.Marshal(prd)
+				os.WriteFile(filepath.Join(halDir, "prd.json"), data, 0644)
+			}
+		},
+	}
+
+	var logBuf bytes.Buffer
+	runner := &Runner{
+		config: Config{
+			Dir:           halDir,
+			PRDFile:       "prd.json",
+			ProgressFile:  "progress.txt",
+			StoryID:       "US-001",
+			MaxIterations: 2,
+			Logger:        &logBuf,
+			RetryDelay:    time.Millisecond,
+		},
+		engine:  fe,
+		display: engine.NewDisplay(&logBuf),
+	}
+	runner.Run(context.Background())
+
+	if len(prompts) != 2 {
+		t.Fatalf("engine calls = %d, want 2", len(prompts))
+	}
+	if !strings.Contains(prompts[0], "Story: Original title") || !strings.Contains(prompts[1], "Story: Edited title") {
+		t.Fatalf("prompts = %q, want the second iteration to use the edited story", prompts)
+	}
+}
@@ -0,0 +1,14 @@
+//go:build windows
+
+package loop
+
+import (
+	"context"
+	"os/exec"
+)
+
+// shellCommand runs command through cmd /C. Windows has no POSIX process
+// groups; the default cmd.Cancel (os.Process.Kill) is used instead.
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	return exec.CommandContext(ctx, "cmd", "/C", command)
+}
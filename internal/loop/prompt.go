@@ -0,0 +1,238 @@
+package loop
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	texttemplate "text/template"
+	"text/template/parse"
+
+	"github.com/jywlabs/hal/internal/engine"
+	"github.com/jywlabs/hal/internal/template"
+)
+
+// Iteration outcomes reported to the next prompt as .Previous.Outcome.
+const (
+	OutcomePassed           = "passed"            // The worked story now passes
+	OutcomeIncomplete       = "incomplete"        // The story is still pending
+	OutcomeVerifyFailed     = "verify_failed"     // Verify commands reset the story
+	OutcomeRejectedComplete = "rejected_complete" // COMPLETE was signaled with stories pending
+)
+
+// IterationOutcome describes how an iteration ended.
+type IterationOutcome struct {
+	Iteration int
+	StoryID   string
+	Outcome   string
+	Error     string // Why the iteration fell short; empty when it passed
+}
+
+// PromptData is the data prompt.md is rendered with. Standards and DiffStat
+// are methods so they are only computed when the template uses them.
+type PromptData struct {
+	PRDFile          string
+	ProgressFile     string
+	BaseBranch       string
+	Engine           string
+	Story            *engine.UserStory // Target story; nil when none is pending
+	Iteration        int               // 1-based; 0 outside hal run
+	MaxIterations    int
+	RemainingStories int               // Pending stories, excluding skipped ones
+	Previous         *IterationOutcome // nil on the first iteration
+
+	standards func() string
+	diffStat  func() string
+}
+
+// Standards returns the project standards selected for the target story.
+func (d PromptData) Standards() string {
+	if d.standards == nil {
+		return ""
+	}
+	return d.standards()
+}
+
+// DiffStat returns `git diff --stat` of the working tree against the base
+// branch, or "" when it is unavailable.
+func (d PromptData) DiffStat() string {
+	if d.diffStat == nil {
+		return ""
+	}
+	return d.diffStat()
+}
+
+// PromptOverlayFile returns the per-engine prompt overlay name, e.g.
+// prompt.codex.md.
+func PromptOverlayFile(engineName string) string {
+	return strings.TrimSuffix(template.PromptFile, ".md") + "." + engineName + ".md"
+}
+
+// PromptSources returns the prompt files that render for engineName: the
+// base prompt.md and, when present, its engine overlay.
+func PromptSources(dir, engineName string) []string {
+	sources := []string{filepath.Join(dir, template.PromptFile)}
+	if engineName == "" {
+		return sources
+	}
+	overlay := filepath.Join(dir, PromptOverlayFile(engineName))
+	if _, err := os.Stat(overlay); err == nil {
+		sources = append(sources, overlay)
+	}
+	return sources
+}
+
+// RenderPrompt renders prompt.md from dir with text/template. The legacy
+// {{PRD_FILE}}, {{PROGRESS_FILE}}, {{BASE_BRANCH}} and {{STANDARDS}}
+// placeholders keep working as template functions.
+//
+// An engine overlay (prompt.<engine>.md) is parsed after prompt.md. It may
+// redefine {{block}} sections of prompt.md, or provide the whole prompt and
+// include the base with {{template "base" .}}. An overlay with only
+// {{define}} sections renders prompt.md with those sections replaced.
+//
+// A prompt.md that does not parse as a template and uses no {{.Field}}
+// actions is treated as a legacy prompt: only the placeholders are
+// replaced, so literal braces such as JSX style={{...}} survive.
+func RenderPrompt(dir string, data PromptData) (string, error) {
+	tmpl, _, err := parsePrompt(dir, data)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", tmpl.Name(), err)
+	}
+	return b.String(), nil
+}
+
+// templateFieldPattern matches a text/template action referencing prompt
+// data, such as {{.Story.ID}} or {{if .Previous}}.
+var templateFieldPattern = regexp.MustCompile(`\{\{[^}]*\.[A-Z]`)
+
+// parsePrompt parses prompt.md and the overlay for data.Engine, returning
+// the template to execute. The warning is set when prompt.md was rendered
+// as a legacy prompt.
+func parsePrompt(dir string, data PromptData) (*texttemplate.Template, string, error) {
+	sources := PromptSources(dir, data.Engine)
+	base, err := os.ReadFile(sources[0])
+	if err != nil {
+		return nil, "", err
+	}
+
+	funcs := texttemplate.FuncMap{
+		"PRD_FILE":      func() string { return data.PRDFile },
+		"PROGRESS_FILE": func() string { return data.ProgressFile },
+		"BASE_BRANCH":   func() string { return data.BaseBranch },
+		"STANDARDS":     data.Standards,
+	}
+	var warning string
+	tmpl, err := texttemplate.New("base").Funcs(funcs).Parse(string(base))
+	if err != nil {
+		if templateFieldPattern.Match(base) {
+			return nil, "", fmt.Errorf("invalid %s: %w", template.PromptFile, err)
+		}
+		warning = fmt.Sprintf("%s is not a valid template (%v); replacing only the {{PRD_FILE}}-style placeholders", template.PromptFile, err)
+		funcs["legacyPrompt"] = func() string { return replaceLegacyPlaceholders(string(base), data) }
+		tmpl = texttemplate.Must(texttemplate.New("base").Funcs(funcs).Parse("{{legacyPrompt}}"))
+	}
+	if len(sources) == 1 {
+		return tmpl, warning, nil
+	}
+
+	overlay, err := os.ReadFile(sources[1])
+	if err != nil {
+		return nil, "", err
+	}
+	name := filepath.Base(sources[1])
+	t, err := tmpl.New(name).Parse(string(overlay))
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid %s: %w", name, err)
+	}
+	if t.Tree == nil || parse.IsEmptyTree(t.Tree.Root) {
+		return tmpl, warning, nil
+	}
+	return t, warning, nil
+}
+
+// replaceLegacyPlaceholders substitutes the legacy placeholders in prompt
+// and leaves all other text untouched.
+func replaceLegacyPlaceholders(prompt string, data PromptData) string {
+	placeholders := []struct {
+		name  string
+		value func() string
+	}{
+		{"PRD_FILE", func() string { return data.PRDFile }},
+		{"PROGRESS_FILE", func() string { return data.ProgressFile }},
+		{"BASE_BRANCH", func() string { return data.BaseBranch }},
+		{"STANDARDS", data.Standards},
+	}
+	for _, p := range placeholders {
+		if placeholder := "{{" + p.name + "}}"; strings.Contains(prompt, placeholder) {
+			prompt = strings.ReplaceAll(prompt, placeholder, p.value())
+		}
+	}
+	return prompt
+}
+
+// promptData builds the data for rendering the prompt of an iteration.
+func (r *Runner) promptData(story *engine.UserStory, iteration int, previous *IterationOutcome) PromptData {
+	baseBranch := r.config.BaseBranch
+	if baseBranch == "" {
+		baseBranch = "HEAD"
+	}
+	data := PromptData{
+		PRDFile:       r.config.PRDFile,
+		ProgressFile:  r.config.ProgressFile,
+		BaseBranch:    baseBranch,
+		Engine:        r.config.Engine,
+		Story:         story,
+		Iteration:     iteration,
+		MaxIterations: r.config.MaxIterations,
+		Previous:      previous,
+		standards:     func() string { return r.selectStandards(story).Render() },
+		diffStat:      func() string { return gitDiffStat(filepath.Dir(r.config.Dir), baseBranch) },
+	}
+	if prd, err := engine.LoadPRDFile(r.config.Dir, r.config.PRDFile); err == nil {
+		completed, total := prd.Progress()
+		data.RemainingStories = total - completed
+	}
+	return data
+}
+
+// iterationOutcome classifies an iteration by whether the worked story now
+// passes and whether its verify commands rejected it.
+func (r *Runner) iterationOutcome(iteration int, storyID string, failures []VerifyFailure) *IterationOutcome {
+	outcome := &IterationOutcome{Iteration: iteration, StoryID: storyID, Outcome: OutcomeIncomplete}
+	if len(failures) > 0 {
+		outcome.Outcome = OutcomeVerifyFailed
+		var msgs []string
+		for _, f := range failures {
+			msgs = append(msgs, fmt.Sprintf("%s: `%s` failed", f.StoryID, f.Command))
+		}
+		outcome.Error = strings.Join(msgs, "; ")
+		return outcome
+	}
+	if storyID != "" && r.passingStoryIDs()[storyID] {
+		outcome.Outcome = OutcomePassed
+		return outcome
+	}
+	if storyID != "" {
+		outcome.Error = fmt.Sprintf("%s still has passes: false", storyID)
+	}
+	return outcome
+}
+
+// gitDiffStat returns the diff stat of the repository at dir against base.
+// Injectable for testing.
+var gitDiffStat = func(dir, base string) string {
+	cmd := exec.Command("git", "diff", "--stat", base)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimRight(string(out), "\n")
+}
@@ -0,0 +1,221 @@
+package loop
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jywlabs/hal/internal/engine"
+	"github.com/jywlabs/hal/internal/template"
+)
+
+func writePromptFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func writePRD(t *testing.T, halDir string, prd *engine.PRD) {
+	t.Helper()
+	if err := engine.SavePRDFile(halDir, "prd.json", prd); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRenderPrompt_RuntimeData(t *testing.T) {
+	halDir := t.TempDir()
+	writePromptFiles(t, halDir, map[string]string{
+		template.PromptFile: "PRD {{PRD_FILE}} from {{BASE_BRANCH}}\n" +
+			"{{with .Story}}Story {{.ID}}: {{.Title}}\n{{range .AcceptanceCriteria}}- {{.}}\n{{end}}{{end}}" +
+			"Iteration {{.Iteration}}/{{.MaxIterations}}, {{.RemainingStories}} remaining\n" +
+			"{{with .Previous}}Previous: {{.Outcome}} ({{.Error}})\n{{end}}" +
+			"{{.DiffStat}}\n",
+	})
+	writePRD(t, halDir, &engine.PRD{UserStories: []engine.UserStory{
+		{ID: "US-001", Title: "Done", Passes: true},
+		{ID: "US-002", Title: "Add login", AcceptanceCriteria: []string{"Form renders"}},
+		{ID: "US-003", Title: "Add logout"},
+	}})
+
+	origDiffStat := gitDiffStat
+	t.Cleanup(func() { gitDiffStat = origDiffStat })
+	gitDiffStat = func(dir, base string) string {
+		return "diff against " + base
+	}
+
+	r := &Runner{config: Config{
+		Dir: halDir, PRDFile: "prd.json", ProgressFile: "progress.txt",
+		BaseBranch: "develop", MaxIterations: 5, Logger: &bytes.Buffer{},
+	}}
+	story := &engine.UserStory{ID: "US-002", Title: "Add login", AcceptanceCriteria: []string{"Form renders"}}
+	previous := &IterationOutcome{Iteration: 1, StoryID: "US-002", Outcome: OutcomeVerifyFailed, Error: "US-002: `go test` failed"}
+
+	got, err := RenderPrompt(halDir, r.promptData(story, 2, previous))
+	if err != nil {
+		t.Fatalf("RenderPrompt() error: %v", err)
+	}
+	want := "PRD prd.json from develop\n" +
+		"Story US-002: Add login\n- Form renders\n" +
+		"Iteration 2/5, 2 remaining\n" +
+		"Previous: verify_failed (US-002: `go test` failed)\n" +
+		"diff against develop\n"
+	if got != want {
+		t.Errorf("RenderPrompt() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRenderPrompt_EngineOverlay(t *testing.T) {
+	base := "# Agent\n{{block \"rules\" .}}generic rules{{end}}\nEngine {{.Engine}}\n"
+	tests := []struct {
+		name    string
+		engine  string
+		overlay string
+		want    string
+	}{
+		{
+			name:   "no overlay",
+			engine: "codex",
+			want:   "# Agent\ngeneric rules\nEngine codex\n",
+		},
+		{
+			name:    "overlay redefines a block",
+			engine:  "claude",
+			overlay: "{{define \"rules\"}}claude rules{{end}}\n",
+			want:    "# Agent\nclaude rules\nEngine claude\n",
+		},
+		{
+			name:    "overlay wraps the base prompt",
+			engine:  "claude",
+			overlay: "{{template \"base\" .}}Use sub-agents.\n",
+			want:    "# Agent\ngeneric rules\nEngine claude\nUse sub-agents.\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			halDir := t.TempDir()
+			files := map[string]string{template.PromptFile: base}
+			if tt.overlay != "" {
+				files[PromptOverlayFile("claude")] = tt.overlay
+			}
+			writePromptFiles(t, halDir, files)
+
+			got, err := RenderPrompt(halDir, PromptData{Engine: tt.engine})
+			if err != nil {
+				t.Fatalf("RenderPrompt() error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("RenderPrompt() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderPrompt_InvalidTemplate(t *testing.T) {
+	halDir := t.TempDir()
+	writePromptFiles(t, halDir, map[string]string{
+		template.PromptFile:         "{{if .Story}}unterminated\n",
+		PromptOverlayFile("claude"): "ok\n",
+	})
+	if _, err := RenderPrompt(halDir, PromptData{}); err == nil || !strings.Contains(err.Error(), "invalid prompt.md") {
+		t.Errorf("RenderPrompt() error = %v, want invalid prompt.md", err)
+	}
+
+	writePromptFiles(t, halDir, map[string]string{
+		template.PromptFile:         "{{.Story.Title}}\n",
+		PromptOverlayFile("claude"): "{{.Nope}}\n",
+	})
+	if _, err := RenderPrompt(halDir, PromptData{Engine: "claude"}); err == nil || !strings.Contains(err.Error(), "prompt.claude.md") {
+		t.Errorf("RenderPrompt() error = %v, want overlay error", err)
+	}
+}
+
+func TestRenderPrompt_LegacyPromptWithLiteralBraces(t *testing.T) {
+	halDir := t.TempDir()
+	writePromptFiles(t, halDir, map[string]string{
+		template.PromptFile: "Read {{PRD_FILE}} and diff against {{BASE_BRANCH}}.\nUse <div style={{display: 'flex'}}> for layout.\n",
+	})
+	writePRD(t, halDir, &engine.PRD{UserStories: []engine.UserStory{{ID: "US-001", Title: "First", Priority: 1}}})
+
+	want := "Read prd.json and diff against main.\nUse <div style={{display: 'flex'}}> for layout.\n"
+	got, err := RenderPrompt(halDir, PromptData{PRDFile: "prd.json", BaseBranch: "main"})
+	if err != nil {
+		t.Fatalf("RenderPrompt() error: %v", err)
+	}
+	if got != want {
+		t.Errorf("RenderPrompt() = %q, want %q", got, want)
+	}
+
+	var logBuf bytes.Buffer
+	if _, _, err := PreviewPrompt(Config{Dir: halDir, Logger: &logBuf}); err != nil {
+		t.Fatalf("PreviewPrompt() error: %v", err)
+	}
+	if !strings.Contains(logBuf.String(), "prompt.md is not a valid template") {
+		t.Errorf("legacy prompt warning missing: %q", logBuf.String())
+	}
+}
+
+func TestPreviewPrompt(t *testing.T) {
+	halDir := t.TempDir()
+	writePromptFiles(t, halDir, map[string]string{
+		template.PromptFile: "{{with .Story}}{{.ID}}{{else}}none{{end}} #{{.Iteration}}\n",
+	})
+	writePRD(t, halDir, &engine.PRD{UserStories: []engine.UserStory{
+		{ID: "US-001", Title: "First", Priority: 1},
+		{ID: "US-002", Title: "Second", Priority: 2},
+	}})
+
+	for storyID, want := range map[string]string{"": "US-001 #1\n", "US-002": "US-002 #1\n"} {
+		got, story, err := PreviewPrompt(Config{Dir: halDir, StoryID: storyID, Logger: &bytes.Buffer{}})
+		if err != nil {
+			t.Fatalf("PreviewPrompt(%q) error: %v", storyID, err)
+		}
+		if got != want || story == nil {
+			t.Errorf("PreviewPrompt(%q) = %q, %v; want %q", storyID, got, story, want)
+		}
+	}
+	if _, _, err := PreviewPrompt(Config{Dir: halDir, StoryID: "US-404"}); err == nil || !strings.Contains(err.Error(), "story not found") {
+		t.Errorf("PreviewPrompt(US-404) error = %v", err)
+	}
+}
+
+func TestIterationOutcome(t *testing.T) {
+	halDir := t.TempDir()
+	writePRD(t, halDir, &engine.PRD{UserStories: []engine.UserStory{
+		{ID: "US-001", Passes: true},
+		{ID: "US-002"},
+	}})
+	r := &Runner{config: Config{Dir: halDir, PRDFile: "prd.json"}}
+
+	if got := r.iterationOutcome(1, "US-001", nil); got.Outcome != OutcomePassed || got.Error != "" {
+		t.Errorf("passing story outcome = %+v", got)
+	}
+	if got := r.iterationOutcome(2, "US-002", nil); got.Outcome != OutcomeIncomplete || !strings.Contains(got.Error, "US-002 still has passes: false") {
+		t.Errorf("pending story outcome = %+v", got)
+	}
+	failures := []VerifyFailure{{StoryID: "US-002", Command: "go test ./..."}}
+	if got := r.iterationOutcome(3, "US-002", failures); got.Outcome != OutcomeVerifyFailed || got.Error != "US-002: `go test ./...` failed" {
+		t.Errorf("verify failure outcome = %+v", got)
+	}
+}
+
+func TestRun_PromptReceivesPreviousOutcome(t *testing.T) {
+	halDir := setupTestHalDir(t, controlTestStories())
+	writePromptFiles(t, halDir, map[string]string{
+		template.PromptFile: "{{.Story.ID}} iteration {{.Iteration}}{{with .Previous}} after {{.Outcome}}{{end}}\n",
+	})
+	eng := &fakeEngine{}
+	runner, _ := newControlRunner(halDir, eng, 2)
+
+	runner.Run(context.Background())
+
+	want := []string{"US-001 iteration 1\n", "US-001 iteration 2 after incomplete\n"}
+	if strings.Join(eng.prompts, "|") != strings.Join(want, "|") {
+		t.Errorf("prompts = %q, want %q", eng.prompts, want)
+	}
+}
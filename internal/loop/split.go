@@ -0,0 +1,278 @@
+package loop
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jywlabs/hal/internal/engine"
+)
+
+// Sub-story limits requested from the engine when splitting a story.
+const (
+	minSubStories = 2
+	maxSubStories = 5
+)
+
+// StorySplit records a story replaced by sub-stories after it stalled.
+type StorySplit struct {
+	StoryID    string   `json:"storyId"`
+	Iterations int      `json:"iterations"`
+	SubStories []string `json:"subStories"`
+}
+
+// splitTracker counts iterations spent on each story without passing.
+type splitTracker struct {
+	iterations map[string]int
+	attempted  map[string]bool
+}
+
+func newSplitTracker() *splitTracker {
+	return &splitTracker{iterations: make(map[string]int), attempted: make(map[string]bool)}
+}
+
+type subStoryDraft struct {
+	Title              string   `json:"title"`
+	Description        string   `json:"description"`
+	AcceptanceCriteria []string `json:"acceptanceCriteria"`
+}
+
+// checkStalledStory counts an iteration against storyID when the story is
+// still pending and splits it once it reaches SplitAfter iterations.
+// Splitting failures are reported as warnings; the loop keeps going.
+func (r *Runner) checkStalledStory(ctx context.Context, tracker *splitTracker, storyID string, result *Result) {
+	if r.config.SplitAfter <= 0 || r.config.StoryID != "" || storyID == "" {
+		return
+	}
+	prd, err := engine.LoadPRDFile(r.config.Dir, r.config.PRDFile)
+	if err != nil {
+		return
+	}
+	story := prd.FindStoryByID(storyID)
+	if story == nil || story.Passes || story.Skipped {
+		return
+	}
+
+	tracker.iterations[storyID]++
+	iterations := tracker.iterations[storyID]
+	if iterations < r.config.SplitAfter || tracker.attempted[storyID] || isSubStoryID(storyID) {
+		return
+	}
+	tracker.attempted[storyID] = true
+
+	r.display.ShowInfo("   ⚠ %s has not passed after %d iterations; splitting it into smaller stories\n", storyID, iterations)
+	subStories, err := r.splitStory(ctx, prd, story)
+	if err != nil {
+		r.display.ShowInfo("   ⚠ Could not split %s: %v\n", storyID, err)
+		return
+	}
+	if err := engine.SavePRDFile(r.config.Dir, r.config.PRDFile, prd); err != nil {
+		r.display.ShowInfo("   ⚠ Could not save split of %s: %v\n", storyID, err)
+		return
+	}
+
+	ids := make([]string, len(subStories))
+	for i, s := range subStories {
+		ids[i] = s.ID
+	}
+	if err := r.appendSplitProgress(storyID, iterations, ids); err != nil {
+		r.display.ShowInfo("   ⚠ %v\n", err)
+	}
+	r.display.ShowInfo("   ✓ Split %s into %s\n", storyID, strings.Join(ids, ", "))
+	result.Splits = append(result.Splits, StorySplit{StoryID: storyID, Iterations: iterations, SubStories: ids})
+}
+
+// splitStory asks the engine to decompose story and replaces it in prd with
+// the resulting sub-stories. Every original acceptance criterion ends up in
+// at least one sub-story, and the last sub-story keeps the verify commands.
+func (r *Runner) splitStory(ctx context.Context, prd *engine.PRD, story *engine.UserStory) ([]engine.UserStory, error) {
+	response, err := r.engine.Prompt(ctx, splitPrompt(*story))
+	if err != nil {
+		return nil, fmt.Errorf("engine prompt failed: %w", err)
+	}
+	drafts, err := parseSubStories(response)
+	if err != nil {
+		return nil, err
+	}
+
+	// Coverage is checked against the sub-stories' own text rather than
+	// trusted from the engine; anything missing goes to the last sub-story.
+	var drafted []string
+	for _, d := range drafts {
+		for _, c := range d.AcceptanceCriteria {
+			drafted = append(drafted, normalizeCriterion(c))
+		}
+	}
+	last := &drafts[len(drafts)-1]
+	for _, criterion := range story.AcceptanceCriteria {
+		if !criterionCovered(normalizeCriterion(criterion), drafted) {
+			last.AcceptanceCriteria = append(last.AcceptanceCriteria, criterion)
+		}
+	}
+
+	original := *story
+	subStories := make([]engine.UserStory, 0, len(drafts))
+	for _, d := range drafts {
+		id := nextSubStoryID(prd, original.ID, subStories)
+		subStories = append(subStories, engine.UserStory{
+			ID:                 id,
+			Title:              strings.TrimSpace(d.Title),
+			Description:        strings.TrimSpace(d.Description),
+			AcceptanceCriteria: d.AcceptanceCriteria,
+			Priority:           original.Priority,
+			Notes:              "Split from " + original.ID,
+		})
+	}
+	// The original verify commands check the whole story, so they gate the
+	// sub-story that completes it rather than the earlier steps.
+	if len(original.Verify) > 0 {
+		subStories[len(subStories)-1].Verify = append([]string(nil), original.Verify...)
+	}
+
+	for _, stories := range []*[]engine.UserStory{&prd.UserStories, &prd.Tasks} {
+		for i, s := range *stories {
+			if s.ID != original.ID {
+				continue
+			}
+			replaced := append(append([]engine.UserStory(nil), (*stories)[:i]...), subStories...)
+			replaced = append(replaced, (*stories)[i+1:]...)
+			*stories = renumberStories(replaced)
+			for j := range subStories {
+				subStories[j] = *prd.FindStoryByID(subStories[j].ID)
+			}
+			return subStories, nil
+		}
+	}
+	return nil, fmt.Errorf("story not found: %s", original.ID)
+}
+
+// renumberStories orders stories by priority, keeping sub-stories in the
+// place of the story they replace, and renumbers priorities 1..n as
+// hal prd story move does.
+func renumberStories(stories []engine.UserStory) []engine.UserStory {
+	sort.SliceStable(stories, func(i, j int) bool { return stories[i].Priority < stories[j].Priority })
+	for i := range stories {
+		stories[i].Priority = i + 1
+	}
+	return stories
+}
+
+// normalizeCriterion lowercases c and collapses whitespace and trailing
+// punctuation so reworded spacing or casing still counts as covered.
+func normalizeCriterion(c string) string {
+	return strings.TrimRight(strings.Join(strings.Fields(strings.ToLower(c)), " "), ".;:")
+}
+
+// criterionCovered reports whether criterion appears in one of the drafted
+// sub-story criteria.
+func criterionCovered(criterion string, drafted []string) bool {
+	if criterion == "" {
+		return true
+	}
+	for _, d := range drafted {
+		if strings.Contains(d, criterion) {
+			return true
+		}
+	}
+	return false
+}
+
+func splitPrompt(story engine.UserStory) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Story %s has not been completed after several attempts. Split it into %d to %d smaller stories that can each be finished in one iteration.\n\n", story.ID, minSubStories, maxSubStories)
+	fmt.Fprintf(&b, "Title: %s\n", story.Title)
+	if story.Description != "" {
+		fmt.Fprintf(&b, "Description: %s\n", story.Description)
+	}
+	b.WriteString("Acceptance criteria:\n")
+	for i, c := range story.AcceptanceCriteria {
+		fmt.Fprintf(&b, "%d. %s\n", i+1, c)
+	}
+	if story.Notes != "" {
+		fmt.Fprintf(&b, "Notes: %s\n", story.Notes)
+	}
+	b.WriteString("\nList the stories in the order they should be built. Copy every acceptance criterion above verbatim into at least one story; ")
+	b.WriteString("criteria that do not appear word for word are added to the last story.\n\n")
+	b.WriteString("Respond with JSON only:\n")
+	b.WriteString(`{"stories":[{"title":"...","description":"...","acceptanceCriteria":["..."]}]}`)
+	b.WriteString("\n")
+	return b.String()
+}
+
+func parseSubStories(response string) ([]subStoryDraft, error) {
+	start := strings.Index(response, "{")
+	end := strings.LastIndex(response, "}")
+	if start == -1 || end < start {
+		return nil, fmt.Errorf("no JSON found in split response")
+	}
+	var parsed struct {
+		Stories []subStoryDraft `json:"stories"`
+	}
+	if err := json.Unmarshal([]byte(response[start:end+1]), &parsed); err != nil {
+		return nil, fmt.Errorf("invalid split response: %w", err)
+	}
+
+	var drafts []subStoryDraft
+	for _, d := range parsed.Stories {
+		if strings.TrimSpace(d.Title) != "" {
+			drafts = append(drafts, d)
+		}
+	}
+	if len(drafts) < minSubStories {
+		return nil, fmt.Errorf("split response has %d stories, want at least %d", len(drafts), minSubStories)
+	}
+	if len(drafts) > maxSubStories {
+		drafts = drafts[:maxSubStories]
+	}
+	return drafts, nil
+}
+
+// nextSubStoryID returns the first unused ID of the form <id>a, <id>b, ...
+func nextSubStoryID(prd *engine.PRD, id string, pending []engine.UserStory) string {
+	for c := 'a'; c <= 'z'; c++ {
+		candidate := id + string(c)
+		if prd.FindStoryByID(candidate) != nil {
+			continue
+		}
+		taken := false
+		for _, s := range pending {
+			if s.ID == candidate {
+				taken = true
+			}
+		}
+		if !taken {
+			return candidate
+		}
+	}
+	return fmt.Sprintf("%s-%d", id, len(pending)+1)
+}
+
+// isSubStoryID reports whether id was produced by an earlier split.
+func isSubStoryID(id string) bool {
+	if len(id) < 2 {
+		return false
+	}
+	last, prev := id[len(id)-1], id[len(id)-2]
+	return last >= 'a' && last <= 'z' && prev >= '0' && prev <= '9'
+}
+
+func (r *Runner) appendSplitProgress(storyID string, iterations int, subStories []string) error {
+	path := filepath.Join(r.config.Dir, r.config.ProgressFile)
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", r.config.ProgressFile, err)
+	}
+	defer f.Close()
+
+	entry := fmt.Sprintf("\n## %s - %s\n- Split after %d iterations without passing into %s\n---\n",
+		time.Now().Format("2006-01-02 15:04"), storyID, iterations, strings.Join(subStories, ", "))
+	if _, err := f.WriteString(entry); err != nil {
+		return fmt.Errorf("failed to write %s: %w", r.config.ProgressFile, err)
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package loop
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jywlabs/hal/internal/engine"
+)
+
+func TestRun_SplitsStalledStory(t *testing.T) {
+	stories := []engine.UserStory{
+		{ID: "US-001", Title: "Build settings page", AcceptanceCriteria: []string{"form renders", "values persist", "errors shown"}, Priority: 1, Verify: []string{"go test ./settings/..."}},
+		{ID: "US-002", Title: "Add audit log", AcceptanceCriteria: []string{"entries written"}, Priority: 2},
+	}
+	halDir := setupTestHalDir(t, stories)
+
+	fe := &fakeEngine{promptResponse: "```json\n" + `{"stories":[
+		{"title":"Render settings form","description":"Form only","acceptanceCriteria":["The  Form renders."],"covers":[1]},
+		{"title":"Persist settings","acceptanceCriteria":["values persist"],"covers":[2,3]}
+	]}` + "\n```"}
+
+	var logBuf bytes.Buffer
+	runner := &Runner{
+		config: Config{
+			Dir:           halDir,
+			PRDFile:       "prd.json",
+			ProgressFile:  "progress.txt",
+			MaxIterations: 2,
+			Logger:        &logBuf,
+			RetryDelay:    time.Millisecond,
+			SplitAfter:    2,
+		},
+		engine:  fe,
+		display: engine.NewDisplay(&logBuf),
+	}
+
+	result := runner.Run(context.Background())
+	if result.Error != nil {
+		t.Fatalf("Run() error: %v", result.Error)
+	}
+	if len(result.Splits) != 1 {
+		t.Fatalf("Splits = %+v, want one split", result.Splits)
+	}
+	split := result.Splits[0]
+	if split.StoryID != "US-001" || split.Iterations != 2 || strings.Join(split.SubStories, ",") != "US-001a,US-001b" {
+		t.Fatalf("split = %+v", split)
+	}
+
+	prd, err := engine.LoadPRDFile(halDir, "prd.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, s := range prd.UserStories {
+		ids = append(ids, s.ID)
+	}
+	if got := strings.Join(ids, ","); got != "US-001a,US-001b,US-002" {
+		t.Fatalf("story IDs = %s", got)
+	}
+	if first := prd.UserStories[0]; strings.Join(first.AcceptanceCriteria, "|") != "The  Form renders." {
+		t.Errorf("criterion covered by text should not be repeated: %v", first.AcceptanceCriteria)
+	}
+	last := prd.UserStories[1]
+	// The engine claims criterion 3 is covered, but no sub-story says so.
+	if strings.Join(last.AcceptanceCriteria, "|") != "values persist|errors shown" {
+		t.Errorf("uncovered criterion not carried over: %v", last.AcceptanceCriteria)
+	}
+	if last.Notes != "Split from US-001" {
+		t.Errorf("sub-story = %+v", last)
+	}
+	for i, s := range prd.UserStories {
+		if s.Priority != i+1 {
+			t.Errorf("%s priority = %d, want %d", s.ID, s.Priority, i+1)
+		}
+	}
+	if first := prd.UserStories[0]; len(first.Verify) != 0 {
+		t.Errorf("first sub-story verify = %v, want none", first.Verify)
+	}
+	if strings.Join(last.Verify, "|") != "go test ./settings/..." {
+		t.Errorf("last sub-story verify = %v, want the original verify commands", last.Verify)
+	}
+	if current := prd.CurrentStory(); current == nil || current.ID != "US-001a" {
+		t.Errorf("CurrentStory() = %v, want US-001a", current)
+	}
+
+	progress, err := os.ReadFile(filepath.Join(halDir, "progress.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(progress), "- US-001\n- Split after 2 iterations without passing into US-001a, US-001b") {
+		t.Errorf("progress.txt = %q", progress)
+	}
+}
+
+func TestCheckStalledStory_SkipsWhenDisabledOrAlreadySplit(t *testing.T) {
+	stories := []engine.UserStory{
+		{ID: "US-001", Title: "One", Priority: 1},
+		{ID: "US-003a", Title: "Sub", Priority: 2},
+	}
+
+	tests := []struct {
+		name    string
+		config  Config
+		storyID string
+	}{
+		{name: "disabled", config: Config{SplitAfter: 0}, storyID: "US-001"},
+		{name: "single story mode", config: Config{SplitAfter: 1, StoryID: "US-001"}, storyID: "US-001"},
+		{name: "already a sub-story", config: Config{SplitAfter: 1}, storyID: "US-003a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			halDir := setupTestHalDir(t, stories)
+			cfg := tt.config
+			cfg.Dir, cfg.PRDFile, cfg.ProgressFile = halDir, "prd.json", "progress.txt"
+			fe := &fakeEngine{promptResponse: `{"stories":[{"title":"A"},{"title":"B"}]}`}
+			var logBuf bytes.Buffer
+			runner := &Runner{config: cfg, engine: fe, display: engine.NewDisplay(&logBuf)}
+
+			var result Result
+			tracker := newSplitTracker()
+			for i := 0; i < 3; i++ {
+				runner.checkStalledStory(context.Background(), tracker, tt.storyID, &result)
+			}
+			if len(result.Splits) != 0 {
+				t.Fatalf("Splits = %+v, want none", result.Splits)
+			}
+		})
+	}
+}
+
+func TestParseSubStories(t *testing.T) {
+	if _, err := parseSubStories(`{"stories":[{"title":"Only one"}]}`); err == nil {
+		t.Error("expected error for a single sub-story")
+	}
+	if _, err := parseSubStories("no json here"); err == nil {
+		t.Error("expected error for missing JSON")
+	}
+	drafts, err := parseSubStories(`Here you go: {"stories":[{"title":"A"},{"title":" "},{"title":"B"}]}`)
+	if err != nil {
+		t.Fatalf("parseSubStories() error: %v", err)
+	}
+	if len(drafts) != 2 {
+		t.Fatalf("drafts = %+v, want 2 titled stories", drafts)
+	}
+}
@@ -0,0 +1,86 @@
+package loop
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jywlabs/hal/internal/runstatus"
+	"github.com/jywlabs/hal/internal/template"
+)
+
+// statusHeartbeatInterval is how often a running loop refreshes updatedAt
+// while an iteration is in progress. Injectable for testing.
+var statusHeartbeatInterval = 30 * time.Second
+
+// writeStatus records status with the current token count. Write failures
+// are ignored: the status file only feeds the dashboard and hal ctl.
+func (r *Runner) writeStatus(status *runstatus.Status) {
+	status.Tokens = r.display.TotalTokens()
+	status.UpdatedAt = time.Now()
+	r.statusMu.Lock()
+	defer r.statusMu.Unlock()
+	r.lastStatus = *status
+	r.saveStatus()
+}
+
+// startHeartbeat refreshes UpdatedAt in run-status.json while an iteration
+// runs, so hal ctl and hal status can tell a live loop from a crashed one.
+// The returned function stops the heartbeat.
+func (r *Runner) startHeartbeat() func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(statusHeartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				r.statusMu.Lock()
+				if r.lastStatus.State == runstatus.StateRunning || r.lastStatus.State == runstatus.StatePaused {
+					r.lastStatus.Tokens = r.display.TotalTokens()
+					r.lastStatus.UpdatedAt = time.Now()
+					r.saveStatus()
+				}
+				r.statusMu.Unlock()
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// saveStatus writes r.lastStatus to run-status.json. The caller holds
+// r.statusMu.
+func (r *Runner) saveStatus() {
+	data, err := json.MarshalIndent(r.lastStatus, "", "  ")
+	if err != nil {
+		return
+	}
+	path := filepath.Join(r.config.Dir, template.RunStatusFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+	}
+}
+
+// finalRunState maps a finished loop result to a run status state.
+func finalRunState(result Result) string {
+	switch {
+	case result.Complete:
+		return runstatus.StateComplete
+	case result.Error != nil:
+		return runstatus.StateFailed
+	default:
+		return runstatus.StateStopped
+	}
+}
@@ -0,0 +1,117 @@
+package loop
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jywlabs/hal/internal/engine"
+	"github.com/jywlabs/hal/internal/runstatus"
+	"github.com/jywlabs/hal/internal/template"
+)
+
+func TestRun_WritesRunStatus(t *testing.T) {
+	halDir := setupTestHalDir(t, []engine.UserStory{{ID: "US-001", Title: "Login", Priority: 1}})
+
+	if status, err := runstatus.Load(halDir); err != nil || status != nil {
+		t.Fatalf("runstatus.Load before run = %+v, %v; want nil, nil", status, err)
+	}
+
+	tests := []struct {
+		name      string
+		engine    engine.Engine
+		wantState string
+		wantError bool
+	}{
+		{
+			name: "complete",
+			engine: &passingEngine{
+				fakeEngine: fakeEngine{results: []engine.Result{{Success: true, Complete: true}}},
+				halDir:     halDir,
+				storyID:    "US-001",
+			},
+			wantState: runstatus.StateComplete,
+		},
+		{
+			name:      "failed",
+			engine:    &fakeEngine{results: []engine.Result{{Error: errors.New("bad credentials")}}},
+			wantState: runstatus.StateFailed,
+			wantError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logBuf bytes.Buffer
+			runner := &Runner{
+				config: Config{
+					Dir:           halDir,
+					PRDFile:       "prd.json",
+					ProgressFile:  "progress.txt",
+					MaxIterations: 3,
+					Logger:        &logBuf,
+					RetryDelay:    time.Millisecond,
+				},
+				engine:  tt.engine,
+				display: engine.NewDisplay(&logBuf),
+			}
+			runner.Run(context.Background())
+
+			status, err := runstatus.Load(halDir)
+			if err != nil || status == nil {
+				t.Fatalf("runstatus.Load = %+v, %v", status, err)
+			}
+			if status.State != tt.wantState || status.Engine != "fake" || status.Iteration != 1 || status.MaxIterations != 3 {
+				t.Fatalf("status = %+v, want %s after iteration 1/3 of fake", status, tt.wantState)
+			}
+			if status.PID != os.Getpid() {
+				t.Fatalf("status pid = %d, want %d", status.PID, os.Getpid())
+			}
+			if status.StartedAt.IsZero() || status.UpdatedAt.Before(status.StartedAt) {
+				t.Fatalf("status times = %v..%v", status.StartedAt, status.UpdatedAt)
+			}
+			if (status.Error != "") != tt.wantError {
+				t.Fatalf("status error = %q, wantError %v", status.Error, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestStartHeartbeat_RefreshesUpdatedAt(t *testing.T) {
+	orig := statusHeartbeatInterval
+	statusHeartbeatInterval = 5 * time.Millisecond
+	t.Cleanup(func() { statusHeartbeatInterval = orig })
+
+	halDir := t.TempDir()
+	var logBuf bytes.Buffer
+	runner := &Runner{config: Config{Dir: halDir}, display: engine.NewDisplay(&logBuf)}
+	status := &runstatus.Status{State: runstatus.StateRunning}
+	runner.writeStatus(status)
+	first := status.UpdatedAt
+
+	stop := runner.startHeartbeat()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		loaded, err := runstatus.Load(halDir)
+		if err == nil && loaded != nil && loaded.UpdatedAt.After(first) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("heartbeat did not refresh updatedAt")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	stop()
+
+	// Once the run finishes the heartbeat must not resurrect it.
+	status.State = runstatus.StateComplete
+	runner.writeStatus(status)
+	data, _ := os.ReadFile(filepath.Join(halDir, template.RunStatusFile))
+	if !strings.Contains(string(data), `"state": "complete"`) {
+		t.Fatalf("final status = %s", data)
+	}
+}
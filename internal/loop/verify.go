@@ -0,0 +1,126 @@
+package loop
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jywlabs/hal/internal/engine"
+)
+
+// verifyCommandTimeout bounds each story verify command.
+const verifyCommandTimeout = 10 * time.Minute
+
+// verifyWaitDelay bounds how long a killed verify command may keep its
+// output pipes open before Wait gives up on it.
+const verifyWaitDelay = 5 * time.Second
+
+// verifyOutputLimit is how much of a failing command's output is fed back
+// into the next prompt.
+const verifyOutputLimit = 2000
+
+// runVerifyCommand runs a story verify command through the shell (sh, or
+// cmd on Windows) in dir and returns its combined output. Injectable for testing.
+var runVerifyCommand = func(ctx context.Context, dir, command string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, verifyCommandTimeout)
+	defer cancel()
+
+	cmd := shellCommand(ctx, command)
+	cmd.Dir = dir
+	cmd.WaitDelay = verifyWaitDelay
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+// VerifyFailure records a story whose verify command failed after the agent
+// marked it passing.
+type VerifyFailure struct {
+	StoryID string `json:"storyId"`
+	Command string `json:"command"`
+	Output  string `json:"output,omitempty"`
+}
+
+// passingStoryIDs returns the IDs of stories that currently pass.
+func (r *Runner) passingStoryIDs() map[string]bool {
+	passing := make(map[string]bool)
+	prd, err := engine.LoadPRDFile(r.config.Dir, r.config.PRDFile)
+	if err != nil {
+		return passing
+	}
+	for _, stories := range [][]engine.UserStory{prd.UserStories, prd.Tasks} {
+		for _, s := range stories {
+			if s.Passes {
+				passing[s.ID] = true
+			}
+		}
+	}
+	return passing
+}
+
+// verifyNewlyPassing runs the verify commands of stories that started
+// passing during the iteration. Stories whose commands fail are flipped back
+// to passes: false. It returns the failures, one per rejected story.
+func (r *Runner) verifyNewlyPassing(ctx context.Context, passingBefore map[string]bool) []VerifyFailure {
+	prd, err := engine.LoadPRDFile(r.config.Dir, r.config.PRDFile)
+	if err != nil {
+		return nil
+	}
+
+	workDir := filepath.Dir(r.config.Dir)
+	var failures []VerifyFailure
+	for _, stories := range []*[]engine.UserStory{&prd.UserStories, &prd.Tasks} {
+		for i := range *stories {
+			story := &(*stories)[i]
+			if !story.Passes || passingBefore[story.ID] || len(story.Verify) == 0 {
+				continue
+			}
+			for _, command := range story.Verify {
+				if strings.TrimSpace(command) == "" {
+					continue
+				}
+				r.display.ShowInfo("   Verifying %s: %s\n", story.ID, command)
+				output, err := runVerifyCommand(ctx, workDir, command)
+				if err == nil {
+					continue
+				}
+				r.display.ShowInfo("   ⚠ %s verification failed: %v\n", story.ID, err)
+				story.Passes = false
+				failures = append(failures, VerifyFailure{StoryID: story.ID, Command: command, Output: tailOutput(output, verifyOutputLimit)})
+				break
+			}
+		}
+	}
+
+	if len(failures) > 0 {
+		if err := engine.SavePRDFile(r.config.Dir, r.config.PRDFile, prd); err != nil {
+			r.display.ShowInfo("   ⚠ Could not reset failed stories: %v\n", err)
+		}
+	}
+	return failures
+}
+
+// verifyFeedback builds the prompt section telling the next iteration which
+// verify commands failed.
+func verifyFeedback(iteration int, prdFile string, failures []VerifyFailure) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n\n## IMPORTANT — Iteration %d Verification Failed\n", iteration)
+	fmt.Fprintf(&b, "hal ran the `verify` commands of stories marked `passes: true` and reset them to `passes: false` in `%s`:\n\n", prdFile)
+	for _, f := range failures {
+		fmt.Fprintf(&b, "- **%s**: `%s` failed\n", f.StoryID, f.Command)
+		if f.Output != "" {
+			fmt.Fprintf(&b, "\n```\n%s\n```\n\n", strings.TrimRight(f.Output, "\n"))
+		}
+	}
+	b.WriteString("Fix the code so these commands pass, run them yourself, and only then set `passes: true` again.\n")
+	return b.String()
+}
+
+func tailOutput(output string, limit int) string {
+	output = strings.TrimSpace(output)
+	if len(output) <= limit {
+		return output
+	}
+	return "..." + output[len(output)-limit:]
+}
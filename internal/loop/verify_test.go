@@ -0,0 +1,134 @@
+package loop
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jywlabs/hal/internal/engine"
+)
+
+// passingEngine marks storyID as passing in prd.json on every Execute call.
+type passingEngine struct {
+	fakeEngine
+	halDir  string
+	storyID string
+}
+
+func (p *passingEngine) Execute(ctx context.Context, prompt string, display *engine.Display) engine.Result {
+	if prd, err := engine.LoadPRDFile(p.halDir, "prd.json"); err == nil {
+		if story := prd.FindStoryByID(p.storyID); story != nil {
+			story.Passes = true
+			_ = engine.SavePRDFile(p.halDir, "prd.json", prd)
+		}
+	}
+	return p.fakeEngine.Execute(ctx, prompt, display)
+}
+
+func TestRun_VerifyFailureResetsStoryAndFeedsBack(t *testing.T) {
+	stories := []engine.UserStory{{
+		ID:                 "US-001",
+		Title:              "Login",
+		AcceptanceCriteria: []string{"login works"},
+		Priority:           1,
+		Verify:             []string{"go test ./internal/auth/... -run TestLogin"},
+	}}
+	halDir := setupTestHalDir(t, stories)
+
+	var commands []string
+	origRun := runVerifyCommand
+	runVerifyCommand = func(_ context.Context, _ string, command string) (string, error) {
+		commands = append(commands, command)
+		if len(commands) == 1 {
+			return "--- FAIL: TestLogin\nexpected 200, got 500", errors.New("exit status 1")
+		}
+		return "ok", nil
+	}
+	t.Cleanup(func() { runVerifyCommand = origRun })
+
+	pe := &passingEngine{
+		fakeEngine: fakeEngine{results: []engine.Result{
+			{Success: true, Complete: true},
+			{Success: true, Complete: true},
+		}},
+		halDir:  halDir,
+		storyID: "US-001",
+	}
+
+	var logBuf bytes.Buffer
+	runner := &Runner{
+		config: Config{
+			Dir:           halDir,
+			PRDFile:       "prd.json",
+			ProgressFile:  "progress.txt",
+			MaxIterations: 3,
+			Logger:        &logBuf,
+			RetryDelay:    time.Millisecond,
+		},
+		engine:  pe,
+		display: engine.NewDisplay(&logBuf),
+	}
+
+	result := runner.Run(context.Background())
+	if !result.Complete || result.Error != nil {
+		t.Fatalf("result = %+v, want complete after verification passes", result)
+	}
+	if pe.calls != 2 || len(commands) != 2 {
+		t.Fatalf("engine calls = %d, verify runs = %d, want 2 each", pe.calls, len(commands))
+	}
+	if len(result.VerifyFailures) != 1 || result.VerifyFailures[0].StoryID != "US-001" {
+		t.Fatalf("VerifyFailures = %+v", result.VerifyFailures)
+	}
+	feedback := pe.prompts[1]
+	if !strings.Contains(feedback, "Iteration 1 Verification Failed") || !strings.Contains(feedback, "expected 200, got 500") {
+		t.Errorf("second prompt missing verification feedback:\n%s", feedback)
+	}
+	if strings.Contains(logBuf.String(), "signaled COMPLETE but") {
+		t.Error("rejected story should not count as a false COMPLETE")
+	}
+}
+
+func TestVerifyNewlyPassing_IgnoresStoriesAlreadyPassing(t *testing.T) {
+	stories := []engine.UserStory{
+		{ID: "US-001", Title: "Done earlier", Priority: 1, Passes: true, Verify: []string{"false"}},
+		{ID: "US-002", Title: "No commands", Priority: 2, Passes: true},
+	}
+	halDir := setupTestHalDir(t, stories)
+
+	origRun := runVerifyCommand
+	runVerifyCommand = func(context.Context, string, string) (string, error) {
+		t.Fatal("no verify command should run")
+		return "", nil
+	}
+	t.Cleanup(func() { runVerifyCommand = origRun })
+
+	var logBuf bytes.Buffer
+	runner := &Runner{
+		config:  Config{Dir: halDir, PRDFile: "prd.json"},
+		display: engine.NewDisplay(&logBuf),
+	}
+	if failures := runner.verifyNewlyPassing(context.Background(), map[string]bool{"US-001": true}); len(failures) != 0 {
+		t.Fatalf("failures = %+v, want none", failures)
+	}
+}
+
+func TestRunVerifyCommand_CancelKillsChildren(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("process groups are POSIX-only")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := runVerifyCommand(ctx, t.TempDir(), "sleep 30 & sleep 30")
+	if err == nil {
+		t.Fatal("runVerifyCommand() error = nil, want error after timeout")
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Fatalf("runVerifyCommand() took %s, want children killed with the shell", elapsed)
+	}
+}
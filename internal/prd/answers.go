@@ -0,0 +1,254 @@
+package prd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jywlabs/hal/internal/engine"
+	"gopkg.in/yaml.v3"
+)
+
+// Auto-answer modes for non-interactive planning.
+const (
+	AutoAnswerRecommended = "recommended"
+	AutoAnswerEngine      = "engine"
+)
+
+// Answer sources recorded in generated PRDs.
+const (
+	AnswerSourceInteractive = "interactive"
+	AnswerSourceFile        = "answers-file"
+	AnswerSourceRecommended = "recommended"
+	AnswerSourceEngine      = "engine"
+)
+
+// QuestionsContractVersion is the contractVersion of hal plan --questions-out.
+const QuestionsContractVersion = 1
+
+// QuestionsFile is written by hal plan --questions-out. Adding an "answers"
+// map turns it into an answers file that resumes generation with the same
+// questions.
+type QuestionsFile struct {
+	ContractVersion int        `json:"contractVersion"`
+	Description     string     `json:"description"`
+	Questions       []Question `json:"questions"`
+}
+
+// AnswersFile is the input of hal plan --answers. Answers are keyed by
+// question number or by text that matches exactly one question; values are
+// an option letter, an option label, or free text for "Other".
+type AnswersFile struct {
+	Description string            `yaml:"description" json:"description"`
+	Questions   []Question        `yaml:"questions" json:"questions"`
+	Answers     map[string]string `yaml:"answers" json:"answers"`
+}
+
+// LoadAnswersFile reads a YAML or JSON answers file.
+func LoadAnswersFile(path string) (*AnswersFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read answers file: %w", err)
+	}
+	var af AnswersFile
+	if err := yaml.Unmarshal(data, &af); err != nil {
+		return nil, fmt.Errorf("failed to parse answers file %s: %w", path, err)
+	}
+	return &af, nil
+}
+
+// WriteQuestionsFile writes questions for an external UI to answer.
+func WriteQuestionsFile(path, description string, questions []Question) error {
+	data, err := json.MarshalIndent(QuestionsFile{
+		ContractVersion: QuestionsContractVersion,
+		Description:     description,
+		Questions:       questions,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal questions: %w", err)
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// resolveFileAnswers maps answers-file entries onto questions.
+func resolveFileAnswers(questions []Question, entries map[string]string) (map[int]string, error) {
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	answers := make(map[int]string, len(entries))
+	for _, key := range keys {
+		q, err := matchQuestion(questions, key)
+		if err != nil {
+			return nil, err
+		}
+		if _, dup := answers[q.Number]; dup {
+			return nil, fmt.Errorf("question %d is answered more than once", q.Number)
+		}
+		label, err := resolveOption(q, entries[key])
+		if err != nil {
+			return nil, err
+		}
+		answers[q.Number] = label
+	}
+	return answers, nil
+}
+
+// matchQuestion finds the question a key refers to: a question number, or a
+// case-insensitive substring of exactly one question's text.
+func matchQuestion(questions []Question, key string) (Question, error) {
+	key = strings.TrimSpace(key)
+	if n, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(key), "Q")); err == nil {
+		for _, q := range questions {
+			if q.Number == n {
+				return q, nil
+			}
+		}
+		return Question{}, fmt.Errorf("unknown question number %d", n)
+	}
+
+	var matches []Question
+	lower := strings.ToLower(key)
+	for _, q := range questions {
+		if lower != "" && strings.Contains(strings.ToLower(q.Text), lower) {
+			matches = append(matches, q)
+		}
+	}
+	switch len(matches) {
+	case 1:
+		return matches[0], nil
+	case 0:
+		return Question{}, fmt.Errorf("answer key %q does not match any question", key)
+	default:
+		return Question{}, fmt.Errorf("answer key %q matches %d questions; use the question number", key, len(matches))
+	}
+}
+
+// resolveOption turns an answer value into the text passed to the engine.
+func resolveOption(q Question, value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", fmt.Errorf("empty answer for question %d", q.Number)
+	}
+	for _, opt := range q.Options {
+		if strings.EqualFold(opt.Letter, value) || strings.EqualFold(opt.Label, value) {
+			if isOtherOption(opt.Label) {
+				return "", fmt.Errorf("question %d: option %s needs custom text; write the answer itself instead", q.Number, opt.Letter)
+			}
+			return opt.Label, nil
+		}
+	}
+	return value, nil
+}
+
+// recommendedAnswer picks the option labelled as recommended, falling back
+// to the first option that is not "Other".
+func recommendedAnswer(q Question) (string, bool) {
+	for _, opt := range q.Options {
+		if strings.Contains(strings.ToLower(opt.Label), "recommend") {
+			return opt.Label, true
+		}
+	}
+	for _, opt := range q.Options {
+		if !isOtherOption(opt.Label) {
+			return opt.Label, true
+		}
+	}
+	return "", false
+}
+
+// engineAnswers asks the engine to answer the remaining questions itself.
+func engineAnswers(ctx context.Context, eng engine.Engine, description, projectInfo string, questions []Question) (map[int]string, error) {
+	data, err := json.MarshalIndent(questions, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	prompt := fmt.Sprintf(`You are answering clarifying questions for a Product Requirements Document on behalf of the user.
+
+Project context:
+%s
+
+Feature request: %s
+
+Questions:
+%s
+
+Pick the option that best fits the feature request and the codebase for every question. Answer with the option letter, or with short custom text only when no option fits.
+
+IMPORTANT: Do NOT use any tools (no Read, Write, Bash, etc.). Do NOT write any files.
+Return ONLY a JSON object (no markdown, no explanation):
+{"answers": {"1": "B", "2": "A"}}`, projectInfo, description, string(data))
+
+	response, err := eng.Prompt(ctx, prompt)
+	if err != nil {
+		return nil, fmt.Errorf("engine failed to answer questions: %w", err)
+	}
+	start := strings.Index(response, "{")
+	end := strings.LastIndex(response, "}")
+	if start == -1 || end < start {
+		return nil, fmt.Errorf("no JSON found in engine answers")
+	}
+	var parsed struct {
+		Answers map[string]string `json:"answers"`
+	}
+	if err := json.Unmarshal([]byte(response[start:end+1]), &parsed); err != nil {
+		return nil, fmt.Errorf("invalid engine answers JSON: %w", err)
+	}
+	return resolveFileAnswers(questions, parsed.Answers)
+}
+
+// planningAnswers lists answers in question order with their source.
+func planningAnswers(questions []Question, answers map[int]string, sources map[int]string) []engine.PlanningAnswer {
+	var out []engine.PlanningAnswer
+	for _, q := range questions {
+		answer, ok := answers[q.Number]
+		if !ok {
+			continue
+		}
+		out = append(out, engine.PlanningAnswer{Number: q.Number, Question: q.Text, Answer: answer, Source: sources[q.Number]})
+	}
+	return out
+}
+
+// appendPlanningAnswers records the answers at the end of a markdown PRD.
+func appendPlanningAnswers(prdContent string, recorded []engine.PlanningAnswer) string {
+	if len(recorded) == 0 {
+		return prdContent
+	}
+	var b strings.Builder
+	b.WriteString(strings.TrimRight(prdContent, "\n"))
+	b.WriteString("\n\n## Planning Answers\n\n")
+	for _, a := range recorded {
+		fmt.Fprintf(&b, "%d. %s → %s (%s)\n", a.Number, a.Question, a.Answer, a.Source)
+	}
+	return b.String()
+}
+
+// setPRDPlanningAnswers records the answers in a prd.json document.
+func setPRDPlanningAnswers(prdJSON string, recorded []engine.PlanningAnswer) (string, error) {
+	if len(recorded) == 0 {
+		return prdJSON, nil
+	}
+	var prd engine.PRD
+	if err := json.Unmarshal([]byte(prdJSON), &prd); err != nil {
+		return "", err
+	}
+	prd.PlanningAnswers = recorded
+	formatted, err := json.MarshalIndent(prd, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(formatted), nil
+}
@@ -0,0 +1,161 @@
+package prd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jywlabs/hal/internal/engine"
+	"github.com/jywlabs/hal/internal/template"
+)
+
+var answersTestQuestions = []Question{
+	{Number: 1, Text: "What is the primary goal?", Options: []Option{
+		{Letter: "A", Label: "Faster checkout"}, {Letter: "B", Label: "Fewer refunds (recommended)"}, {Letter: "D", Label: "Other (specify)"},
+	}},
+	{Number: 2, Text: "Who are the target users?", Options: []Option{
+		{Letter: "A", Label: "Admins"}, {Letter: "B", Label: "Customers"},
+	}},
+	{Number: 3, Text: "Which users get notified?", Options: []Option{
+		{Letter: "A", Label: "Everyone"}, {Letter: "B", Label: "Nobody"},
+	}},
+}
+
+func TestResolveFileAnswers(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries map[string]string
+		want    map[int]string
+		wantErr string
+	}{
+		{
+			name:    "number, Q-prefix and text keys",
+			entries: map[string]string{"1": "a", "Q2": "Customers", "notified": "Only the buyer"},
+			want:    map[int]string{1: "Faster checkout", 2: "Customers", 3: "Only the buyer"},
+		},
+		{name: "unknown number", entries: map[string]string{"9": "A"}, wantErr: "unknown question number 9"},
+		{name: "ambiguous text", entries: map[string]string{"users": "A"}, wantErr: "matches 2 questions"},
+		{name: "no match", entries: map[string]string{"budget": "A"}, wantErr: "does not match any question"},
+		{name: "other needs text", entries: map[string]string{"1": "D"}, wantErr: "needs custom text"},
+		{name: "duplicate", entries: map[string]string{"1": "A", "primary goal": "B"}, wantErr: "answered more than once"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveFileAnswers(answersTestQuestions, tt.entries)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveFileAnswers: %v", err)
+			}
+			for n, want := range tt.want {
+				if got[n] != want {
+					t.Errorf("answer %d = %q, want %q", n, got[n], want)
+				}
+			}
+		})
+	}
+}
+
+func TestCollectAnswers_NonInteractive(t *testing.T) {
+	opts := GenerateOptions{
+		Answers:    &AnswersFile{Answers: map[string]string{"2": "A"}},
+		AutoAnswer: AutoAnswerRecommended,
+	}
+	answers, sources, err := collectAnswers(context.Background(), nil, "refunds", "", answersTestQuestions, opts, nil)
+	if err != nil {
+		t.Fatalf("collectAnswers: %v", err)
+	}
+	if answers[1] != "Fewer refunds (recommended)" || sources[1] != AnswerSourceRecommended {
+		t.Errorf("Q1 = %q (%s), want recommended option", answers[1], sources[1])
+	}
+	if answers[2] != "Admins" || sources[2] != AnswerSourceFile {
+		t.Errorf("Q2 = %q (%s), want file answer", answers[2], sources[2])
+	}
+	if answers[3] != "Everyone" {
+		t.Errorf("Q3 = %q, want first option when none is recommended", answers[3])
+	}
+
+	eng := &sequenceMockEngine{promptResponses: []string{`{"answers": {"1": "A", "3": "B"}}`}}
+	opts.AutoAnswer = AutoAnswerEngine
+	answers, sources, err = collectAnswers(context.Background(), eng, "refunds", "", answersTestQuestions, opts, nil)
+	if err != nil {
+		t.Fatalf("collectAnswers engine: %v", err)
+	}
+	if answers[1] != "Faster checkout" || sources[1] != AnswerSourceEngine || answers[3] != "Nobody" {
+		t.Errorf("engine answers = %v %v", answers, sources)
+	}
+
+	_, _, err = collectAnswers(context.Background(), nil, "refunds", "", answersTestQuestions, GenerateOptions{Answers: &AnswersFile{Answers: map[string]string{"1": "A"}}}, nil)
+	if err == nil || !strings.Contains(err.Error(), "missing answers for Q2, Q3") {
+		t.Fatalf("error = %v, want missing answers without stdin fallback", err)
+	}
+}
+
+func TestGenerateWithOptions_QuestionsOutThenResume(t *testing.T) {
+	tmpDir := t.TempDir()
+	chdirTo(t, tmpDir)
+	questionsJSON := `{"questions":[{"number":1,"text":"Goal?","options":[{"letter":"A","label":"Option A"},{"letter":"B","label":"Option B"}]}]}`
+
+	questionsPath := filepath.Join(tmpDir, "questions.json")
+	eng := &sequenceMockEngine{promptResponses: []string{questionsJSON}}
+	got, err := GenerateWithOptions(context.Background(), eng, "new feature", GenerateOptions{QuestionsOut: questionsPath}, nil)
+	if err != nil || got != questionsPath {
+		t.Fatalf("GenerateWithOptions = %q, %v", got, err)
+	}
+	if eng.promptCalls != 1 {
+		t.Fatalf("prompt calls = %d, want questions only", eng.promptCalls)
+	}
+
+	// An external UI adds answers to the questions file and resumes.
+	data, err := os.ReadFile(questionsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var qf map[string]any
+	if err := json.Unmarshal(data, &qf); err != nil {
+		t.Fatal(err)
+	}
+	if qf["contractVersion"] != float64(QuestionsContractVersion) || qf["description"] != "new feature" {
+		t.Fatalf("questions file = %v", qf)
+	}
+	qf["answers"] = map[string]string{"1": "B"}
+	data, _ = json.Marshal(qf)
+	if err := os.WriteFile(questionsPath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	answers, err := LoadAnswersFile(questionsPath)
+	if err != nil {
+		t.Fatalf("LoadAnswersFile: %v", err)
+	}
+
+	eng = &sequenceMockEngine{promptResponses: []string{"# PRD: New Feature\n\n## Goal\nShip it.", promptResponseWithBranch(t, "hal/new-feature")}}
+	if _, err := GenerateWithOptions(context.Background(), eng, answers.Description, GenerateOptions{Format: "json", Answers: answers}, nil); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if eng.promptCalls != 2 {
+		t.Errorf("prompt calls = %d, want PRD and conversion only (questions reused)", eng.promptCalls)
+	}
+	prd, err := engine.LoadPRD(template.HalDir)
+	if err != nil {
+		t.Fatalf("LoadPRD: %v", err)
+	}
+	want := engine.PlanningAnswer{Number: 1, Question: "Goal?", Answer: "Option B", Source: AnswerSourceFile}
+	if len(prd.PlanningAnswers) != 1 || prd.PlanningAnswers[0] != want {
+		t.Errorf("planningAnswers = %+v, want %+v", prd.PlanningAnswers, want)
+	}
+}
+
+func TestAppendPlanningAnswers(t *testing.T) {
+	got := appendPlanningAnswers("# PRD\n\n", []engine.PlanningAnswer{{Number: 1, Question: "Goal?", Answer: "Option A", Source: AnswerSourceRecommended}})
+	want := "# PRD\n\n## Planning Answers\n\n1. Goal? → Option A (recommended)\n"
+	if got != want {
+		t.Errorf("appendPlanningAnswers = %q, want %q", got, want)
+	}
+}
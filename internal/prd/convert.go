@@ -16,6 +16,7 @@ import (
 
 	"github.com/jywlabs/hal/internal/archive"
 	"github.com/jywlabs/hal/internal/engine"
+	"github.com/jywlabs/hal/internal/repomap"
 	"github.com/jywlabs/hal/internal/skills"
 	"github.com/jywlabs/hal/internal/template"
 )
@@ -26,6 +27,11 @@ type ConvertOptions struct {
 	Force      bool
 	Granular   bool
 	BranchName string
+	// Merge carries story progress over from the existing output file
+	// instead of replacing it.
+	Merge bool
+	// MergeReport, when non-nil, receives the story diff of a merge.
+	MergeReport *MergeReport
 }
 
 // ConvertWithEngine converts a markdown PRD to JSON using the hal skill via an engine.
@@ -88,7 +94,8 @@ func ConvertWithEngine(ctx context.Context, eng engine.Engine, mdPath, outPath s
 		}
 	}
 
-	prompt := buildConversionPrompt(halSkill, string(mdContent), targetBranchName, opts.Granular)
+	codebase := buildRepoMap(".", repomap.Options{Budget: conversionContextBudget, Focus: string(mdContent)}).Render()
+	prompt := buildConversionPrompt(halSkill, string(mdContent), codebase, targetBranchName, opts.Granular)
 
 	// Execute prompt — AI returns JSON text, but some engines may write the output file directly.
 	var response string
@@ -134,6 +141,22 @@ func ConvertWithEngine(ctx context.Context, eng engine.Engine, mdPath, outPath s
 		}
 	}
 
+	prdJSON, err = setPRDIssues(prdJSON, sourceIssuesFromMarkdown(string(mdContent)))
+	if err != nil {
+		return fmt.Errorf("failed to record source issues: %w", err)
+	}
+	prdJSON, err = setPRDTemplate(prdJSON, templateNameFromMarkdown(string(mdContent)))
+	if err != nil {
+		return fmt.Errorf("failed to record template: %w", err)
+	}
+
+	if opts.Merge {
+		prdJSON, err = mergeWithExistingOutput(prdJSON, beforeOutput, opts, display)
+		if err != nil {
+			return err
+		}
+	}
+
 	if err := enforceBranchMismatchGuardWithRollback(outPath, beforeOutput, prdJSON, opts); err != nil {
 		return err
 	}
@@ -152,7 +175,51 @@ func ConvertWithEngine(ctx context.Context, eng engine.Engine, mdPath, outPath s
 	return nil
 }
 
-func buildConversionPrompt(skill, mdContent, resolvedBranchName string, granular bool) string {
+// mergeWithExistingOutput merges a freshly converted PRD into the output
+// file's current contents and prints the story diff before it is written.
+// The existing branchName is kept unless --branch pinned another one.
+func mergeWithExistingOutput(prdJSON string, beforeOutput *outputSnapshot, opts ConvertOptions, display *engine.Display) (string, error) {
+	out := io.Discard
+	if display != nil {
+		out = display.Writer()
+	}
+	if beforeOutput == nil || len(bytes.TrimSpace(beforeOutput.data)) == 0 {
+		fmt.Fprintln(out, "  no existing prd.json to merge; writing a fresh conversion")
+		return prdJSON, nil
+	}
+
+	var existing, incoming engine.PRD
+	if err := json.Unmarshal(beforeOutput.data, &existing); err != nil {
+		return "", fmt.Errorf("cannot merge: existing prd.json is invalid: %w", err)
+	}
+	if err := json.Unmarshal([]byte(prdJSON), &incoming); err != nil {
+		return "", fmt.Errorf("failed to parse converted PRD for merge: %w", err)
+	}
+
+	merged, report := MergePRD(&existing, &incoming)
+	if pinned := strings.TrimSpace(opts.BranchName); pinned != "" {
+		merged.BranchName = pinned
+	}
+	if opts.MergeReport != nil {
+		*opts.MergeReport = report
+	}
+	report.Render(out)
+
+	formatted, err := json.MarshalIndent(merged, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(formatted), nil
+}
+
+// conversionContextBudget keeps the codebase map in conversion prompts
+// smaller than in planning, where the engine still has to ask questions.
+const conversionContextBudget = 12000
+
+// buildRepoMap assembles the codebase map for prompts. Injectable for testing.
+var buildRepoMap = repomap.Build
+
+func buildConversionPrompt(skill, mdContent, codebase, resolvedBranchName string, granular bool) string {
 	storyRule := "Each story must be completable in ONE iteration (split large stories)"
 	idRule := "IDs are sequential (US-001, US-002, etc.)"
 	modeRule := "Standard mode: produce developer-sized user stories."
@@ -181,7 +248,10 @@ func buildConversionPrompt(skill, mdContent, resolvedBranchName string, granular
 %s
 </markdown>
 
-Convert the markdown PRD to JSON format following the skill rules:
+<codebase>
+%s</codebase>
+
+Convert the markdown PRD to JSON format following the skill rules. Use the codebase map to name real packages, files and commands in story descriptions and acceptance criteria:
 1. %s
 2. Stories ordered by dependency (schema → backend → UI)
 3. Every story has "Typecheck passes" as acceptance criteria
@@ -192,6 +262,7 @@ Convert the markdown PRD to JSON format following the skill rules:
 8. All stories have passes: false and empty notes
 9. %s
 10. %s
+11. When the markdown gives a command that checks a story (for example a "Verify:" line or a test command), copy it into that story's "verify" array; omit "verify" otherwise
 
 IMPORTANT: Do NOT use any tools (no Read, Write, Bash, etc.). Do NOT write any files.
 File saving is handled by the caller. Return ONLY the JSON object (no markdown, no explanation). The format must be:
@@ -210,7 +281,7 @@ File saving is handled by the caller. Return ONLY the JSON object (no markdown,
       "notes": ""
     }
   ]
-}`, skill, mdContent, storyRule, template.BrowserVerificationCriterion, idRule, modeRule, branchRule, branchExample, exampleID)
+}`, skill, mdContent, codebase, storyRule, template.BrowserVerificationCriterion, idRule, modeRule, branchRule, branchExample, exampleID)
 }
 
 var (
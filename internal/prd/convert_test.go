@@ -613,6 +613,32 @@ func TestConvertWithEngine_GranularOptionAddsTaskGuidanceToPrompt(t *testing.T)
 	}
 }
 
+func TestConvertWithEngine_IncludesCodebaseMapInPrompt(t *testing.T) {
+	tmpDir := t.TempDir()
+	chdirTo(t, tmpDir)
+	halDir := filepath.Join(tmpDir, template.HalDir)
+	if err := os.MkdirAll(halDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(tmpDir, "go.mod"), "module example.com/shop\n")
+	writeFile(t, filepath.Join(tmpDir, "billing", "billing.go"), "package billing\n\nfunc Export() {}\n")
+
+	mdPath := filepath.Join(halDir, "prd-new.md")
+	writeFile(t, mdPath, "# PRD: Billing export")
+	eng := &mockEngine{
+		promptResponse: promptResponseWithBranch(t, "hal/billing-export"),
+	}
+
+	if err := ConvertWithEngine(context.Background(), eng, mdPath, filepath.Join(tmpDir, "out.json"), ConvertOptions{}, nil); err != nil {
+		t.Fatalf("ConvertWithEngine failed: %v", err)
+	}
+	for _, want := range []string{"<codebase>", "Go module example.com/shop", "billing: Export()"} {
+		if !strings.Contains(eng.lastPrompt, want) {
+			t.Fatalf("prompt missing %q:\n%s", want, eng.lastPrompt)
+		}
+	}
+}
+
 func TestConvertWithEngine_ExplicitBranchAnnotationsPinOutputAndPrompt(t *testing.T) {
 	tmpDir := t.TempDir()
 	chdirTo(t, tmpDir)
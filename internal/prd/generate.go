@@ -13,37 +13,87 @@ import (
 	"strings"
 
 	"github.com/jywlabs/hal/internal/engine"
+	"github.com/jywlabs/hal/internal/repomap"
 	"github.com/jywlabs/hal/internal/skills"
 	"github.com/jywlabs/hal/internal/template"
 )
 
+// GenerateOptions controls how hal plan answers clarifying questions.
+type GenerateOptions struct {
+	Format string
+	// Answers supplies answers (and optionally the questions) non-interactively.
+	Answers *AnswersFile
+	// AutoAnswer fills unanswered questions: AutoAnswerRecommended or AutoAnswerEngine.
+	AutoAnswer string
+	// QuestionsOut writes the generated questions to this path and stops.
+	QuestionsOut string
+	// Issues links the PRD to the GitHub issues it was planned from.
+	Issues []engine.IssueRef
+	// FeatureName overrides the name derived from the description for the
+	// markdown output file.
+	FeatureName string
+	// Template adds a PRD template's guidance, questions and constraints.
+	Template *Template
+}
+
 // GenerateWithEngine runs the two-phase PRD generation using the prd skill.
 // Phase 1: Generate clarifying questions
 // Phase 2: Collect answers and generate PRD
 func GenerateWithEngine(ctx context.Context, eng engine.Engine, description string, format string, display *engine.Display) (string, error) {
+	return GenerateWithOptions(ctx, eng, description, GenerateOptions{Format: format}, display)
+}
+
+// GenerateWithOptions runs PRD generation with scripted or automatic answers.
+// With QuestionsOut set it stops after phase 1 and returns the questions path.
+func GenerateWithOptions(ctx context.Context, eng engine.Engine, description string, opts GenerateOptions, display *engine.Display) (string, error) {
+	format := opts.Format
+	switch opts.AutoAnswer {
+	case "", AutoAnswerRecommended, AutoAnswerEngine:
+	default:
+		return "", fmt.Errorf("invalid auto-answer mode %q (valid: %s, %s)", opts.AutoAnswer, AutoAnswerRecommended, AutoAnswerEngine)
+	}
+
 	// Load prd skill content
 	prdSkill, err := skills.LoadSkill("prd")
 	if err != nil {
 		return "", fmt.Errorf("failed to load prd skill: %w", err)
 	}
+	templateName := ""
+	if opts.Template != nil {
+		prdSkill += "\n" + opts.Template.promptSection()
+		templateName = opts.Template.Name
+	}
 
 	// Get project context
-	projectInfo := getProjectContext()
+	projectInfo := getProjectContext(description)
 
-	// Phase 1: Generate clarifying questions
-	if display != nil {
-		display.ShowPhase(1, 2, "Questions")
+	// Phase 1: Generate clarifying questions, unless an answers file resumes
+	// from questions written by --questions-out.
+	var questions []Question
+	if opts.Answers != nil && len(opts.Answers.Questions) > 0 {
+		questions = opts.Answers.Questions
+	} else {
+		if display != nil {
+			display.ShowPhase(1, 2, "Questions")
+		}
+		questions, err = generateQuestions(ctx, eng, prdSkill, description, projectInfo, display)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate questions: %w", err)
+		}
 	}
-	questions, err := generateQuestions(ctx, eng, prdSkill, description, projectInfo, display)
-	if err != nil {
-		return "", fmt.Errorf("failed to generate questions: %w", err)
+
+	if opts.QuestionsOut != "" {
+		if err := WriteQuestionsFile(opts.QuestionsOut, description, questions); err != nil {
+			return "", fmt.Errorf("failed to write questions: %w", err)
+		}
+		return opts.QuestionsOut, nil
 	}
 
-	// Collect answers from user (uses styled display)
-	answers, err := collectAnswersStyled(questions, display)
+	answers, sources, err := collectAnswers(ctx, eng, description, projectInfo, questions, opts, display)
 	if err != nil {
 		return "", fmt.Errorf("failed to collect answers: %w", err)
 	}
+	recorded := planningAnswers(questions, answers, sources)
 
 	// Phase 2: Generate PRD
 	if display != nil {
@@ -53,6 +103,9 @@ func GenerateWithEngine(ctx context.Context, eng engine.Engine, description stri
 	if err != nil {
 		return "", fmt.Errorf("failed to generate PRD: %w", err)
 	}
+	prdContent = appendPlanningAnswers(prdContent, recorded)
+	prdContent = appendSourceIssues(prdContent, opts.Issues)
+	prdContent = appendTemplateName(prdContent, templateName)
 
 	// Determine output path and write
 	var outputPath string
@@ -67,6 +120,18 @@ func GenerateWithEngine(ctx context.Context, eng engine.Engine, description stri
 		if err != nil {
 			return "", fmt.Errorf("failed to convert PRD to JSON: %w", err)
 		}
+		jsonContent, err = setPRDPlanningAnswers(jsonContent, recorded)
+		if err != nil {
+			return "", fmt.Errorf("failed to record planning answers: %w", err)
+		}
+		jsonContent, err = setPRDIssues(jsonContent, opts.Issues)
+		if err != nil {
+			return "", fmt.Errorf("failed to record source issues: %w", err)
+		}
+		jsonContent, err = setPRDTemplate(jsonContent, templateName)
+		if err != nil {
+			return "", fmt.Errorf("failed to record template: %w", err)
+		}
 		if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
 			return "", err
 		}
@@ -76,6 +141,9 @@ func GenerateWithEngine(ctx context.Context, eng engine.Engine, description stri
 	} else {
 		// Write markdown to .hal/
 		featureName := extractFeatureNameFromDescription(description)
+		if opts.FeatureName != "" {
+			featureName = extractFeatureNameFromDescription(opts.FeatureName)
+		}
 		outputPath = filepath.Join(template.HalDir, fmt.Sprintf("prd-%s.md", featureName))
 		if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
 			return "", err
@@ -242,6 +310,75 @@ func parseQuestionsResponse(response string) ([]Question, error) {
 	return qr.Questions, nil
 }
 
+// collectAnswers answers every question from the answers file, then the
+// auto-answer mode, and finally the interactive prompt. Scripted runs never
+// fall back to stdin: unanswered questions are an error.
+func collectAnswers(ctx context.Context, eng engine.Engine, description, projectInfo string, questions []Question, opts GenerateOptions, display *engine.Display) (map[int]string, map[int]string, error) {
+	if opts.Answers == nil && opts.AutoAnswer == "" {
+		answers, err := collectAnswersStyled(questions, display)
+		if err != nil {
+			return nil, nil, err
+		}
+		sources := make(map[int]string, len(answers))
+		for n := range answers {
+			sources[n] = AnswerSourceInteractive
+		}
+		return answers, sources, nil
+	}
+
+	answers := map[int]string{}
+	sources := map[int]string{}
+	if opts.Answers != nil {
+		fromFile, err := resolveFileAnswers(questions, opts.Answers.Answers)
+		if err != nil {
+			return nil, nil, err
+		}
+		for n, a := range fromFile {
+			answers[n], sources[n] = a, AnswerSourceFile
+		}
+	}
+
+	missing := findMissingQuestions(questions, answers)
+	if len(missing) > 0 {
+		switch opts.AutoAnswer {
+		case AutoAnswerRecommended:
+			for _, q := range questions {
+				if _, ok := answers[q.Number]; ok {
+					continue
+				}
+				if label, ok := recommendedAnswer(q); ok {
+					answers[q.Number], sources[q.Number] = label, AnswerSourceRecommended
+				}
+			}
+		case AutoAnswerEngine:
+			var remaining []Question
+			for _, q := range questions {
+				if _, ok := answers[q.Number]; !ok {
+					remaining = append(remaining, q)
+				}
+			}
+			fromEngine, err := engineAnswers(ctx, eng, description, projectInfo, remaining)
+			if err != nil {
+				return nil, nil, err
+			}
+			for n, a := range fromEngine {
+				answers[n], sources[n] = a, AnswerSourceEngine
+			}
+		}
+		missing = findMissingQuestions(questions, answers)
+	}
+	if len(missing) > 0 {
+		return nil, nil, fmt.Errorf("missing answers for %s (add them to the answers file or use --auto-answer)", formatMissingQuestions(missing))
+	}
+
+	if display != nil {
+		for _, a := range planningAnswers(questions, answers, sources) {
+			display.ShowInfo("   %d. %s → %s (%s)\n", a.Number, a.Question, a.Answer, a.Source)
+		}
+	}
+	return answers, sources, nil
+}
+
 // collectAnswersStyled displays all questions at once and collects answers in a
 // single batch input like "1A, 2B, 3C, 4B, 5A".
 func collectAnswersStyled(questions []Question, display *engine.Display) (map[int]string, error) {
@@ -520,7 +657,8 @@ func convertPRDToJSON(ctx context.Context, eng engine.Engine, skill, prdContent,
 		}
 	}
 
-	prompt := buildConversionPrompt(skill, prdContent, targetBranchName, false)
+	codebase := buildRepoMap(".", repomap.Options{Budget: conversionContextBudget, Focus: prdContent}).Render()
+	prompt := buildConversionPrompt(skill, prdContent, codebase, targetBranchName, false)
 
 	var response string
 	if display != nil {
@@ -571,39 +709,9 @@ func convertPRDToJSON(ctx context.Context, eng engine.Engine, skill, prdContent,
 	return jsonContent, nil
 }
 
-func getProjectContext() string {
-	var context strings.Builder
-	context.WriteString("Codebase information:\n")
-
-	// Check for common project files
-	if _, err := os.Stat("package.json"); err == nil {
-		context.WriteString("- Node.js/JavaScript project (package.json present)\n")
-	}
-	if _, err := os.Stat("go.mod"); err == nil {
-		context.WriteString("- Go project (go.mod present)\n")
-	}
-	if _, err := os.Stat("Cargo.toml"); err == nil {
-		context.WriteString("- Rust project (Cargo.toml present)\n")
-	}
-	if _, err := os.Stat("requirements.txt"); err == nil {
-		context.WriteString("- Python project (requirements.txt present)\n")
-	}
-	if _, err := os.Stat("pyproject.toml"); err == nil {
-		context.WriteString("- Python project (pyproject.toml present)\n")
-	}
-
-	// Check for common frameworks
-	if _, err := os.Stat("next.config.js"); err == nil {
-		context.WriteString("- Next.js framework detected\n")
-	}
-	if _, err := os.Stat("next.config.ts"); err == nil {
-		context.WriteString("- Next.js framework detected\n")
-	}
-	if _, err := os.Stat("vite.config.ts"); err == nil {
-		context.WriteString("- Vite build tool detected\n")
-	}
-
-	return context.String()
+// getProjectContext returns the codebase map included in planning prompts.
+func getProjectContext(description string) string {
+	return "Codebase information:\n\n" + buildRepoMap(".", repomap.Options{Focus: description}).Render()
 }
 
 func extractFeatureNameFromDescription(description string) string {
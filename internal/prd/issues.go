@@ -0,0 +1,79 @@
+package prd
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jywlabs/hal/internal/engine"
+)
+
+// sourceIssuesHeading marks the markdown PRD section that links it to the
+// GitHub issues it was planned from. hal convert copies the links into
+// prd.json.
+const sourceIssuesHeading = "## Source Issues"
+
+var sourceIssueLinePattern = regexp.MustCompile(`^[-*]\s+([^/\s#]+/[^/\s#]+)#(\d+)(?::\s*(.*?))?(?:\s+<(\S+)>)?\s*$`)
+
+// appendSourceIssues records issue links at the end of a markdown PRD.
+func appendSourceIssues(prdContent string, issues []engine.IssueRef) string {
+	if len(issues) == 0 {
+		return prdContent
+	}
+	var b strings.Builder
+	b.WriteString(strings.TrimRight(prdContent, "\n"))
+	b.WriteString("\n\n" + sourceIssuesHeading + "\n\n")
+	for _, issue := range issues {
+		line := fmt.Sprintf("- %s#%d", issue.Repo, issue.Number)
+		if issue.Title != "" {
+			line += ": " + issue.Title
+		}
+		if issue.URL != "" {
+			line += " <" + issue.URL + ">"
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+// sourceIssuesFromMarkdown reads the links written by appendSourceIssues.
+func sourceIssuesFromMarkdown(mdContent string) []engine.IssueRef {
+	var issues []engine.IssueRef
+	inSection := false
+	for _, line := range strings.Split(mdContent, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "## ") {
+			inSection = strings.EqualFold(trimmed, sourceIssuesHeading)
+			continue
+		}
+		if !inSection {
+			continue
+		}
+		m := sourceIssueLinePattern.FindStringSubmatch(trimmed)
+		if m == nil {
+			continue
+		}
+		number, _ := strconv.Atoi(m[2])
+		issues = append(issues, engine.IssueRef{Repo: m[1], Number: number, Title: m[3], URL: m[4]})
+	}
+	return issues
+}
+
+// setPRDIssues records issue links in a prd.json document.
+func setPRDIssues(prdJSON string, issues []engine.IssueRef) (string, error) {
+	if len(issues) == 0 {
+		return prdJSON, nil
+	}
+	var prd engine.PRD
+	if err := json.Unmarshal([]byte(prdJSON), &prd); err != nil {
+		return "", err
+	}
+	prd.Issues = issues
+	formatted, err := json.MarshalIndent(prd, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(formatted), nil
+}
@@ -0,0 +1,42 @@
+package prd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jywlabs/hal/internal/engine"
+	"github.com/jywlabs/hal/internal/template"
+)
+
+func TestConvertWithEngine_CarriesSourceIssuesIntoPRDJSON(t *testing.T) {
+	tmpDir := t.TempDir()
+	chdirTo(t, tmpDir)
+	halDir := filepath.Join(tmpDir, template.HalDir)
+	if err := os.MkdirAll(halDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	issues := []engine.IssueRef{
+		{Repo: "acme/repo", Number: 42, Title: "Add CSV export", URL: "https://github.com/acme/repo/issues/42"},
+		{Repo: "acme/repo", Number: 43},
+	}
+	mdPath := filepath.Join(halDir, "prd-export.md")
+	writeFile(t, mdPath, appendSourceIssues("# PRD: Export\n\n## Goals\nShip it.\n", issues))
+
+	eng := &mockEngine{promptResponse: promptResponseWithBranch(t, "hal/export")}
+	outPath := filepath.Join(halDir, template.PRDFile)
+	if err := ConvertWithEngine(context.Background(), eng, mdPath, outPath, ConvertOptions{}, nil); err != nil {
+		t.Fatalf("ConvertWithEngine failed: %v", err)
+	}
+
+	prd, err := engine.LoadPRD(halDir)
+	if err != nil {
+		t.Fatalf("LoadPRD: %v", err)
+	}
+	if !reflect.DeepEqual(prd.Issues, issues) {
+		t.Fatalf("issues = %+v, want %+v", prd.Issues, issues)
+	}
+}
@@ -0,0 +1,351 @@
+package prd
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/jywlabs/hal/internal/engine"
+)
+
+// Title similarity thresholds for matching regenerated stories to existing
+// ones. A story keeping its ID needs less title overlap than one matched on
+// title alone.
+const (
+	mergeIDMatchSimilarity    = 0.5
+	mergeTitleMatchSimilarity = 0.6
+)
+
+// reverifyNote is prefixed to the notes of completed stories whose
+// acceptance criteria changed, so the next run re-verifies them.
+const reverifyNote = "Re-verify: acceptance criteria changed since this story passed."
+
+// StoryChange describes one story in a merge report.
+type StoryChange struct {
+	ID string `json:"id"`
+	// PreviousID is set when the regenerated story had a different ID.
+	PreviousID string   `json:"previousId,omitempty"`
+	Title      string   `json:"title"`
+	Fields     []string `json:"fields,omitempty"`
+	Passes     bool     `json:"passes"`
+	// Reverify marks a completed story reset because its criteria changed.
+	Reverify bool `json:"reverify,omitempty"`
+}
+
+// MergeReport summarizes how a regenerated PRD was merged into the existing one.
+type MergeReport struct {
+	Added     []StoryChange `json:"added"`
+	Removed   []StoryChange `json:"removed"`
+	Changed   []StoryChange `json:"changed"`
+	Unchanged int           `json:"unchanged"`
+	Preserved int           `json:"preserved"` // completed stories carried over
+}
+
+// MergePRD carries progress from existing into incoming. Stories are matched
+// by exact title, then by ID with similar titles, then by the most similar
+// title. Matched stories keep their existing ID, passes, skipped flag and
+// notes, and their verify commands when the regenerated story has none.
+// Stories whose acceptance criteria changed are reset: completed ones for
+// re-verification and skipped ones for another attempt.
+// The existing branchName and issue progress are kept as well.
+func MergePRD(existing, incoming *engine.PRD) (*engine.PRD, MergeReport) {
+	report := MergeReport{Added: []StoryChange{}, Removed: []StoryChange{}, Changed: []StoryChange{}}
+	merged := *incoming
+	if existing == nil {
+		return &merged, report
+	}
+
+	oldStories := storyList(existing)
+	newStories := append([]engine.UserStory(nil), storyList(incoming)...)
+	match := matchStories(oldStories, newStories)
+
+	// Existing IDs stay reserved, including removed ones, so progress notes
+	// and issue comments never point at a different story.
+	usedIDs := make(map[string]bool)
+	for _, old := range oldStories {
+		usedIDs[old.ID] = true
+	}
+
+	matchedOld := make(map[int]bool)
+	for i := range newStories {
+		story := &newStories[i]
+		oldIdx := match[i]
+		if oldIdx < 0 {
+			if usedIDs[story.ID] || story.ID == "" {
+				story.ID = nextStoryID(story.ID, usedIDs)
+			}
+			usedIDs[story.ID] = true
+			report.Added = append(report.Added, StoryChange{ID: story.ID, Title: story.Title})
+			continue
+		}
+
+		matchedOld[oldIdx] = true
+		old := oldStories[oldIdx]
+		change := StoryChange{ID: old.ID, Title: story.Title}
+		if story.ID != old.ID {
+			change.PreviousID = story.ID
+			story.ID = old.ID
+		}
+		change.Fields = changedStoryFields(old, *story)
+
+		story.Passes = old.Passes
+		story.Skipped = old.Skipped
+		story.Notes = old.Notes
+		if len(story.Verify) == 0 {
+			story.Verify = old.Verify
+		}
+		if containsString(change.Fields, "acceptanceCriteria") {
+			// New criteria are worth another attempt even if the old ones
+			// were skipped.
+			story.Skipped = false
+			if old.Passes {
+				story.Passes = false
+				story.Notes = strings.TrimSpace(reverifyNote + " " + old.Notes)
+				change.Reverify = true
+			}
+		}
+		change.Passes = story.Passes
+		if story.Passes {
+			report.Preserved++
+		}
+
+		if len(change.Fields) == 0 && change.PreviousID == "" {
+			report.Unchanged++
+			continue
+		}
+		report.Changed = append(report.Changed, change)
+	}
+
+	for i, old := range oldStories {
+		if !matchedOld[i] {
+			report.Removed = append(report.Removed, StoryChange{ID: old.ID, Title: old.Title, Passes: old.Passes})
+		}
+	}
+
+	if len(incoming.UserStories) > 0 || len(incoming.Tasks) == 0 {
+		merged.UserStories = newStories
+	} else {
+		merged.Tasks = newStories
+	}
+	if strings.TrimSpace(existing.BranchName) != "" {
+		merged.BranchName = existing.BranchName
+	}
+	if len(merged.PlanningAnswers) == 0 {
+		merged.PlanningAnswers = existing.PlanningAnswers
+	}
+	if merged.Template == "" {
+		merged.Template = existing.Template
+	}
+	merged.Issues = mergeIssueRefs(existing.Issues, incoming.Issues)
+	return &merged, report
+}
+
+// HasChanges reports whether the merge added, removed or changed stories.
+func (r MergeReport) HasChanges() bool {
+	return len(r.Added)+len(r.Removed)+len(r.Changed) > 0
+}
+
+// Render writes the merge report as a story diff.
+func (r MergeReport) Render(w io.Writer) {
+	fmt.Fprintln(w, engine.StyleBold.Render("Story changes:"))
+	for _, c := range r.Added {
+		fmt.Fprintf(w, "  %s %s %s\n", engine.StyleSuccess.Render("+"), c.ID, c.Title)
+	}
+	for _, c := range r.Removed {
+		label := c.Title
+		if c.Passes {
+			label += engine.StyleWarning.Render(" (was complete)")
+		}
+		fmt.Fprintf(w, "  %s %s %s\n", engine.StyleError.Render("-"), c.ID, label)
+	}
+	for _, c := range r.Changed {
+		var details []string
+		if c.PreviousID != "" {
+			details = append(details, "regenerated as "+c.PreviousID)
+		}
+		details = append(details, c.Fields...)
+		label := c.Title
+		if len(details) > 0 {
+			label += engine.StyleMuted.Render(" (" + strings.Join(details, ", ") + ")")
+		}
+		if c.Reverify {
+			label += engine.StyleWarning.Render(" — re-verify")
+		}
+		fmt.Fprintf(w, "  %s %s %s\n", engine.StyleWarning.Render("~"), c.ID, label)
+	}
+	fmt.Fprintf(w, "  %s\n", engine.StyleMuted.Render(fmt.Sprintf("%d unchanged, %d completed stories preserved", r.Unchanged, r.Preserved)))
+}
+
+func storyList(p *engine.PRD) []engine.UserStory {
+	if len(p.UserStories) > 0 {
+		return p.UserStories
+	}
+	return p.Tasks
+}
+
+// matchStories returns, for each incoming story, the index of the existing
+// story it continues, or -1 for new stories.
+func matchStories(oldStories, newStories []engine.UserStory) []int {
+	match := make([]int, len(newStories))
+	for i := range match {
+		match[i] = -1
+	}
+	taken := make(map[int]bool)
+
+	// Pass 1: identical titles.
+	for i, s := range newStories {
+		key := normalizeStoryTitle(s.Title)
+		for j, old := range oldStories {
+			if !taken[j] && key != "" && normalizeStoryTitle(old.Title) == key {
+				match[i], taken[j] = j, true
+				break
+			}
+		}
+	}
+
+	// Pass 2: same ID with a similar title.
+	for i, s := range newStories {
+		if match[i] >= 0 {
+			continue
+		}
+		for j, old := range oldStories {
+			if !taken[j] && old.ID == s.ID && titleSimilarity(old.Title, s.Title) >= mergeIDMatchSimilarity {
+				match[i], taken[j] = j, true
+				break
+			}
+		}
+	}
+
+	// Pass 3: best remaining title similarity, highest scores first.
+	type candidate struct {
+		newIdx, oldIdx int
+		score          float64
+	}
+	var candidates []candidate
+	for i, s := range newStories {
+		if match[i] >= 0 {
+			continue
+		}
+		for j, old := range oldStories {
+			if taken[j] {
+				continue
+			}
+			if score := titleSimilarity(old.Title, s.Title); score >= mergeTitleMatchSimilarity {
+				candidates = append(candidates, candidate{i, j, score})
+			}
+		}
+	}
+	sort.SliceStable(candidates, func(a, b int) bool { return candidates[a].score > candidates[b].score })
+	for _, c := range candidates {
+		if match[c.newIdx] < 0 && !taken[c.oldIdx] {
+			match[c.newIdx], taken[c.oldIdx] = c.oldIdx, true
+		}
+	}
+	return match
+}
+
+func normalizeStoryTitle(title string) string {
+	return strings.Join(titleWords(title), " ")
+}
+
+func titleWords(title string) []string {
+	return strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// titleSimilarity is the Jaccard similarity of the titles' word sets.
+func titleSimilarity(a, b string) float64 {
+	wordsA, wordsB := make(map[string]bool), make(map[string]bool)
+	for _, w := range titleWords(a) {
+		wordsA[w] = true
+	}
+	for _, w := range titleWords(b) {
+		wordsB[w] = true
+	}
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return 0
+	}
+	shared := 0
+	for w := range wordsA {
+		if wordsB[w] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(wordsA)+len(wordsB)-shared)
+}
+
+func changedStoryFields(old, updated engine.UserStory) []string {
+	var fields []string
+	if normalizeStoryTitle(old.Title) != normalizeStoryTitle(updated.Title) {
+		fields = append(fields, "title")
+	}
+	if strings.TrimSpace(old.Description) != strings.TrimSpace(updated.Description) {
+		fields = append(fields, "description")
+	}
+	if !sameCriteria(old.AcceptanceCriteria, updated.AcceptanceCriteria) {
+		fields = append(fields, "acceptanceCriteria")
+	}
+	if old.Priority != updated.Priority {
+		fields = append(fields, "priority")
+	}
+	return fields
+}
+
+func sameCriteria(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if strings.Join(strings.Fields(a[i]), " ") != strings.Join(strings.Fields(b[i]), " ") {
+			return false
+		}
+	}
+	return true
+}
+
+var storyIDPattern = regexp.MustCompile(`^(.*?)(\d+)$`)
+
+// nextStoryID returns the first unused ID with the same prefix and width as id.
+func nextStoryID(id string, used map[string]bool) string {
+	prefix, width, start := "US-", 3, 1
+	if m := storyIDPattern.FindStringSubmatch(id); m != nil {
+		prefix, width = m[1], len(m[2])
+		start, _ = strconv.Atoi(m[2])
+	}
+	for n := start; ; n++ {
+		candidate := fmt.Sprintf("%s%0*d", prefix, width, n)
+		if !used[candidate] {
+			return candidate
+		}
+	}
+}
+
+// mergeIssueRefs keeps reported-story progress for issues still linked.
+func mergeIssueRefs(existing, incoming []engine.IssueRef) []engine.IssueRef {
+	if len(incoming) == 0 {
+		return existing
+	}
+	merged := append([]engine.IssueRef(nil), incoming...)
+	for i := range merged {
+		for _, old := range existing {
+			if strings.EqualFold(old.Repo, merged[i].Repo) && old.Number == merged[i].Number {
+				merged[i].ReportedStories = old.ReportedStories
+			}
+		}
+	}
+	return merged
+}
+
+func containsString(values []string, want string) bool {
+	for _, v := range values {
+		if v == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,165 @@
+package prd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jywlabs/hal/internal/engine"
+	"github.com/jywlabs/hal/internal/template"
+)
+
+func TestMergePRD(t *testing.T) {
+	existing := &engine.PRD{
+		BranchName: "hal/export",
+		UserStories: []engine.UserStory{
+			{ID: "US-001", Title: "Add export button", AcceptanceCriteria: []string{"Button visible"}, Passes: true, Notes: "done in #3"},
+			{ID: "US-002", Title: "Write CSV file", AcceptanceCriteria: []string{"Valid CSV"}, Passes: true},
+			{ID: "US-003", Title: "Email the export", AcceptanceCriteria: []string{"Email sent"}},
+			{ID: "US-004", Title: "Export audit log", Passes: true},
+		},
+		Issues: []engine.IssueRef{{Repo: "acme/repo", Number: 42, ReportedStories: []string{"US-001"}}},
+	}
+	incoming := &engine.PRD{
+		BranchName: "hal/export-v2",
+		UserStories: []engine.UserStory{
+			{ID: "US-001", Title: "Add export button", AcceptanceCriteria: []string{"Button visible"}},
+			{ID: "US-002", Title: "Choose export format", AcceptanceCriteria: []string{"CSV or JSON"}},
+			{ID: "US-003", Title: "Write the CSV file", AcceptanceCriteria: []string{"Valid CSV", "UTF-8 encoded"}},
+			{ID: "US-004", Title: "Email the export", AcceptanceCriteria: []string{"Email sent"}},
+		},
+		Issues: []engine.IssueRef{{Repo: "acme/repo", Number: 42}},
+	}
+
+	merged, report := MergePRD(existing, incoming)
+
+	wantIDs := []string{"US-001", "US-005", "US-002", "US-003"}
+	for i, want := range wantIDs {
+		if got := merged.UserStories[i].ID; got != want {
+			t.Errorf("story %d ID = %q, want %q", i, got, want)
+		}
+	}
+	if s := merged.UserStories[0]; !s.Passes || s.Notes != "done in #3" {
+		t.Errorf("unchanged story lost progress: %+v", s)
+	}
+	if s := merged.UserStories[2]; s.Passes || !strings.HasPrefix(s.Notes, reverifyNote) {
+		t.Errorf("story with changed criteria = %+v, want reset for re-verification", s)
+	}
+	if merged.BranchName != "hal/export" {
+		t.Errorf("branchName = %q, want existing branch", merged.BranchName)
+	}
+	if got := merged.Issues[0].ReportedStories; len(got) != 1 || got[0] != "US-001" {
+		t.Errorf("reportedStories = %v, want preserved", got)
+	}
+
+	if len(report.Added) != 1 || report.Added[0].ID != "US-005" || report.Added[0].Title != "Choose export format" {
+		t.Errorf("added = %+v", report.Added)
+	}
+	if len(report.Removed) != 1 || report.Removed[0].ID != "US-004" || !report.Removed[0].Passes {
+		t.Errorf("removed = %+v", report.Removed)
+	}
+	if len(report.Changed) != 2 || !report.Changed[0].Reverify || report.Changed[1].PreviousID != "US-004" {
+		t.Errorf("changed = %+v", report.Changed)
+	}
+	if report.Unchanged != 1 || report.Preserved != 1 {
+		t.Errorf("unchanged = %d, preserved = %d; want 1, 1", report.Unchanged, report.Preserved)
+	}
+
+	var buf bytes.Buffer
+	report.Render(&buf)
+	for _, want := range []string{"+ US-005 Choose export format", "- US-004 Export audit log", "~ US-002 Write the CSV file", "re-verify"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("rendered diff missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestMergePRD_KeepsSkippedAndVerify(t *testing.T) {
+	existing := &engine.PRD{
+		UserStories: []engine.UserStory{
+			{ID: "US-001", Title: "Add export button", AcceptanceCriteria: []string{"Button visible"}, Skipped: true, Notes: "Skipped: blocked on design"},
+			{ID: "US-002", Title: "Write CSV file", AcceptanceCriteria: []string{"Valid CSV"}, Skipped: true},
+			{ID: "US-003", Title: "Email the export", AcceptanceCriteria: []string{"Email sent"}, Verify: []string{"go test ./mail/..."}},
+			{ID: "US-004", Title: "Export audit log", AcceptanceCriteria: []string{"Entries listed"}, Verify: []string{"make audit"}},
+		},
+	}
+	incoming := &engine.PRD{
+		UserStories: []engine.UserStory{
+			{ID: "US-001", Title: "Add export button", AcceptanceCriteria: []string{"Button visible"}},
+			{ID: "US-002", Title: "Write CSV file", AcceptanceCriteria: []string{"Valid CSV", "UTF-8 encoded"}},
+			{ID: "US-003", Title: "Email the export", AcceptanceCriteria: []string{"Email sent"}},
+			{ID: "US-004", Title: "Export audit log", AcceptanceCriteria: []string{"Entries listed"}, Verify: []string{"go test ./audit/..."}},
+		},
+	}
+
+	merged, _ := MergePRD(existing, incoming)
+
+	if s := merged.UserStories[0]; !s.Skipped || s.Notes != "Skipped: blocked on design" {
+		t.Errorf("unchanged skipped story = %+v, want still skipped", s)
+	}
+	if s := merged.UserStories[1]; s.Skipped {
+		t.Errorf("skipped story with changed criteria = %+v, want skip cleared", s)
+	}
+	if got := merged.UserStories[2].Verify; len(got) != 1 || got[0] != "go test ./mail/..." {
+		t.Errorf("verify = %v, want the hand-edited commands kept", got)
+	}
+	if got := merged.UserStories[3].Verify; len(got) != 1 || got[0] != "go test ./audit/..." {
+		t.Errorf("verify = %v, want the regenerated commands", got)
+	}
+}
+
+func TestConvertWithEngine_MergeKeepsProgressAndSkipsBranchGuard(t *testing.T) {
+	tmpDir := t.TempDir()
+	chdirTo(t, tmpDir)
+	halDir := filepath.Join(tmpDir, template.HalDir)
+	outPath := filepath.Join(halDir, template.PRDFile)
+
+	existing := engine.PRD{
+		BranchName:  "hal/old-name",
+		UserStories: []engine.UserStory{{ID: "US-001", Title: "Test Story", AcceptanceCriteria: []string{"Typecheck passes"}, Passes: true, Notes: "kept"}},
+	}
+	data, _ := json.Marshal(existing)
+	writeFile(t, outPath, string(data))
+	mdPath := filepath.Join(halDir, "prd-new-name.md")
+	writeFile(t, mdPath, "# PRD: New Name")
+
+	response, _ := json.Marshal(engine.PRD{
+		BranchName: "hal/new-name",
+		UserStories: []engine.UserStory{
+			{ID: "US-001", Title: "Test story", AcceptanceCriteria: []string{"Typecheck passes"}},
+			{ID: "US-002", Title: "Another story"},
+		},
+	})
+	eng := &mockEngine{promptResponse: string(response)}
+	var report MergeReport
+	opts := ConvertOptions{Merge: true, MergeReport: &report}
+	if err := ConvertWithEngine(context.Background(), eng, mdPath, outPath, opts, nil); err != nil {
+		t.Fatalf("ConvertWithEngine failed: %v", err)
+	}
+
+	got, err := engine.LoadPRD(halDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.BranchName != "hal/old-name" {
+		t.Errorf("branchName = %q, want existing branch kept", got.BranchName)
+	}
+	if len(got.UserStories) != 2 || !got.UserStories[0].Passes || got.UserStories[0].Notes != "kept" {
+		t.Fatalf("stories = %+v, want progress carried over", got.UserStories)
+	}
+	if report.Preserved != 1 || len(report.Added) != 1 {
+		t.Errorf("report = %+v, want one preserved and one added story", report)
+	}
+
+	if err := os.WriteFile(outPath, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err = ConvertWithEngine(context.Background(), eng, mdPath, outPath, ConvertOptions{Merge: true}, nil)
+	if err == nil || !strings.Contains(err.Error(), "existing prd.json is invalid") {
+		t.Fatalf("error = %v, want invalid existing PRD", err)
+	}
+}
@@ -0,0 +1,352 @@
+package prd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jywlabs/hal/internal/engine"
+	"github.com/jywlabs/hal/internal/template"
+)
+
+// StoryFields are the fields of a story added with hal prd story add.
+type StoryFields struct {
+	ID                 string
+	Title              string
+	Description        string
+	AcceptanceCriteria []string
+	Verify             []string
+}
+
+// StoryEdit lists the fields hal prd story edit changes; nil fields are kept.
+type StoryEdit struct {
+	Title              *string
+	Description        *string
+	Notes              *string
+	AcceptanceCriteria []string // replaces the criteria when non-nil
+	AddCriteria        []string
+	Verify             []string // replaces the verify commands when non-nil
+}
+
+// MoveTarget is where hal prd story move places a story: a 1-based
+// position in priority order, or before or after another story.
+type MoveTarget struct {
+	Position int
+	Before   string
+	After    string
+}
+
+// storySlice returns the list stories are edited in: userStories, or tasks
+// for granular PRDs.
+func storySlice(p *engine.PRD) *[]engine.UserStory {
+	if len(p.UserStories) == 0 && len(p.Tasks) > 0 {
+		return &p.Tasks
+	}
+	return &p.UserStories
+}
+
+func findStory(p *engine.PRD, id string) (*engine.UserStory, error) {
+	story := p.FindStoryByID(strings.TrimSpace(id))
+	if story == nil {
+		return nil, fmt.Errorf("story not found: %s", id)
+	}
+	return story, nil
+}
+
+// AddStory appends a story with the next free ID and the lowest priority.
+func AddStory(p *engine.PRD, fields StoryFields) (*engine.UserStory, error) {
+	title := strings.TrimSpace(fields.Title)
+	if title == "" {
+		return nil, fmt.Errorf("story title is required")
+	}
+	stories := storySlice(p)
+
+	id := strings.TrimSpace(fields.ID)
+	if id != "" && p.FindStoryByID(id) != nil {
+		return nil, fmt.Errorf("story %s already exists", id)
+	}
+	if id == "" {
+		id = nextAppendedStoryID(*stories, stories == &p.Tasks)
+	}
+
+	priority := 1
+	for _, s := range *stories {
+		if s.Priority >= priority {
+			priority = s.Priority + 1
+		}
+	}
+
+	*stories = append(*stories, engine.UserStory{
+		ID:                 id,
+		Title:              title,
+		Description:        strings.TrimSpace(fields.Description),
+		AcceptanceCriteria: trimCriteria(fields.AcceptanceCriteria),
+		Priority:           priority,
+		Verify:             trimCriteria(fields.Verify),
+	})
+	return &(*stories)[len(*stories)-1], nil
+}
+
+// EditStory applies edit to a story and returns the names of changed fields.
+func EditStory(p *engine.PRD, id string, edit StoryEdit) (*engine.UserStory, []string, error) {
+	story, err := findStory(p, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	var changed []string
+	if edit.Title != nil {
+		title := strings.TrimSpace(*edit.Title)
+		if title == "" {
+			return nil, nil, fmt.Errorf("story title cannot be empty")
+		}
+		if title != story.Title {
+			story.Title = title
+			changed = append(changed, "title")
+		}
+	}
+	if edit.Description != nil && strings.TrimSpace(*edit.Description) != story.Description {
+		story.Description = strings.TrimSpace(*edit.Description)
+		changed = append(changed, "description")
+	}
+	if edit.Notes != nil && strings.TrimSpace(*edit.Notes) != story.Notes {
+		story.Notes = strings.TrimSpace(*edit.Notes)
+		changed = append(changed, "notes")
+	}
+	criteria := story.AcceptanceCriteria
+	if edit.AcceptanceCriteria != nil {
+		criteria = trimCriteria(edit.AcceptanceCriteria)
+	}
+	criteria = append(append([]string(nil), criteria...), trimCriteria(edit.AddCriteria)...)
+	if !sameCriteria(criteria, story.AcceptanceCriteria) {
+		story.AcceptanceCriteria = criteria
+		changed = append(changed, "acceptanceCriteria")
+	}
+	if edit.Verify != nil && !sameCriteria(trimCriteria(edit.Verify), story.Verify) {
+		story.Verify = trimCriteria(edit.Verify)
+		changed = append(changed, "verify")
+	}
+	return story, changed, nil
+}
+
+// RemoveStory deletes a story and returns it.
+func RemoveStory(p *engine.PRD, id string) (engine.UserStory, error) {
+	id = strings.TrimSpace(id)
+	for _, stories := range []*[]engine.UserStory{&p.UserStories, &p.Tasks} {
+		for i, s := range *stories {
+			if s.ID == id {
+				*stories = append((*stories)[:i], (*stories)[i+1:]...)
+				return s, nil
+			}
+		}
+	}
+	return engine.UserStory{}, fmt.Errorf("story not found: %s", id)
+}
+
+// MoveStory moves a story to target and renumbers priorities 1..n in the
+// new order. It returns the story IDs in priority order.
+func MoveStory(p *engine.PRD, id string, target MoveTarget) ([]string, error) {
+	if _, err := findStory(p, id); err != nil {
+		return nil, err
+	}
+	stories := storySlice(p)
+	ordered := append([]engine.UserStory(nil), *stories...)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Priority < ordered[j].Priority })
+
+	var moving engine.UserStory
+	rest := make([]engine.UserStory, 0, len(ordered))
+	for _, s := range ordered {
+		if s.ID == id {
+			moving = s
+			continue
+		}
+		rest = append(rest, s)
+	}
+	if moving.ID == "" {
+		return nil, fmt.Errorf("story %s is not in the %s list", id, storyListName(p))
+	}
+
+	index := -1
+	switch {
+	case target.Before != "" || target.After != "":
+		anchor := target.Before
+		if anchor == "" {
+			anchor = target.After
+		}
+		if anchor == id {
+			return nil, fmt.Errorf("cannot move %s relative to itself", id)
+		}
+		for i, s := range rest {
+			if s.ID == anchor {
+				index = i
+				if target.After != "" {
+					index++
+				}
+			}
+		}
+		if index < 0 {
+			return nil, fmt.Errorf("story not found: %s", anchor)
+		}
+	case target.Position >= 1 && target.Position <= len(ordered):
+		index = target.Position - 1
+	default:
+		return nil, fmt.Errorf("position must be between 1 and %d", len(ordered))
+	}
+
+	reordered := append(append(append([]engine.UserStory(nil), rest[:index]...), moving), rest[index:]...)
+	order := make([]string, len(reordered))
+	for i := range reordered {
+		reordered[i].Priority = i + 1
+		order[i] = reordered[i].ID
+	}
+	*stories = reordered
+	return order, nil
+}
+
+// ResetStory marks a story as not passing and no longer skipped.
+func ResetStory(p *engine.PRD, id string) (*engine.UserStory, error) {
+	story, err := findStory(p, id)
+	if err != nil {
+		return nil, err
+	}
+	story.Passes = false
+	story.Skipped = false
+	return story, nil
+}
+
+// SkipStory marks a pending story as skipped so runs pass over it. The
+// reason, if any, is added to the story notes.
+func SkipStory(p *engine.PRD, id, reason string) (*engine.UserStory, error) {
+	story, err := findStory(p, id)
+	if err != nil {
+		return nil, err
+	}
+	if story.Passes {
+		return nil, fmt.Errorf("story %s already passes; reset it first to skip it", story.ID)
+	}
+	story.Skipped = true
+	if reason = strings.TrimSpace(reason); reason != "" {
+		story.Notes = strings.TrimSpace(story.Notes + "\nSkipped: " + reason)
+	}
+	return story, nil
+}
+
+// ValidateStructure checks a PRD for structural problems without an engine:
+// missing or duplicate story IDs, empty titles and missing criteria.
+func ValidateStructure(p *engine.PRD) *ValidationResult {
+	result := &ValidationResult{Valid: true}
+	addError := func(storyID, field, message string) {
+		result.Errors = append(result.Errors, Issue{StoryID: storyID, Field: field, Message: message, Severity: "error"})
+	}
+	addWarning := func(storyID, field, message string) {
+		result.Warnings = append(result.Warnings, Issue{StoryID: storyID, Field: field, Message: message, Severity: "warning"})
+	}
+
+	if strings.TrimSpace(p.BranchName) == "" {
+		addWarning("", "branchName", "branchName is empty")
+	}
+	if len(p.UserStories)+len(p.Tasks) == 0 {
+		addError("", "userStories", "PRD has no stories")
+	}
+	if len(p.UserStories) > 0 && len(p.Tasks) > 0 {
+		addWarning("", "tasks", "PRD has both userStories and tasks; only userStories run first")
+	}
+
+	ids := make(map[string]bool)
+	priorities := make(map[int]string)
+	for _, stories := range [][]engine.UserStory{p.UserStories, p.Tasks} {
+		for _, s := range stories {
+			id := strings.TrimSpace(s.ID)
+			switch {
+			case id == "":
+				addError("", "id", fmt.Sprintf("story %q has no id", s.Title))
+			case ids[id]:
+				addError(id, "id", "duplicate story id")
+			}
+			ids[id] = true
+			if strings.TrimSpace(s.Title) == "" {
+				addError(id, "title", "story has no title")
+			}
+			if len(s.AcceptanceCriteria) == 0 {
+				addWarning(id, "acceptanceCriteria", "story has no acceptance criteria")
+			}
+			if s.Passes && s.Skipped {
+				addError(id, "skipped", "story is both passing and skipped")
+			}
+			if other, ok := priorities[s.Priority]; ok {
+				addWarning(id, "priority", fmt.Sprintf("priority %d is shared with %s", s.Priority, other))
+			} else {
+				priorities[s.Priority] = id
+			}
+		}
+	}
+	result.Valid = len(result.Errors) == 0
+	return result
+}
+
+// AppendStoryAudit records a story edit in .hal/progress.txt using the
+// progress report entry format.
+func AppendStoryAudit(halDir, storyID, action string, details []string, now time.Time) error {
+	path := filepath.Join(halDir, template.ProgressFile)
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", template.ProgressFile, err)
+	}
+	defer f.Close()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n## %s - %s\n", now.Format("2006-01-02 15:04"), storyID)
+	fmt.Fprintf(&b, "- hal prd story %s\n", action)
+	for _, d := range details {
+		fmt.Fprintf(&b, "- %s\n", d)
+	}
+	b.WriteString("---\n")
+	if _, err := f.WriteString(b.String()); err != nil {
+		return fmt.Errorf("failed to write %s: %w", template.ProgressFile, err)
+	}
+	return nil
+}
+
+// nextAppendedStoryID numbers a new story after the highest existing ID so
+// IDs of removed stories are not reused.
+func nextAppendedStoryID(stories []engine.UserStory, tasks bool) string {
+	prefix, width, highest := "US-", 3, 0
+	if tasks {
+		prefix = "T-"
+	}
+	used := make(map[string]bool)
+	for i, s := range stories {
+		used[s.ID] = true
+		m := storyIDPattern.FindStringSubmatch(s.ID)
+		if m == nil {
+			continue
+		}
+		if i == 0 {
+			prefix, width = m[1], len(m[2])
+		}
+		if n, err := strconv.Atoi(m[2]); err == nil && n > highest {
+			highest = n
+		}
+	}
+	return nextStoryID(fmt.Sprintf("%s%0*d", prefix, width, highest+1), used)
+}
+
+func storyListName(p *engine.PRD) string {
+	if storySlice(p) == &p.Tasks {
+		return "tasks"
+	}
+	return "userStories"
+}
+
+func trimCriteria(criteria []string) []string {
+	var out []string
+	for _, c := range criteria {
+		if c = strings.TrimSpace(c); c != "" {
+			out = append(out, c)
+		}
+	}
+	return out
+}
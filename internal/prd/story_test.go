@@ -0,0 +1,156 @@
+package prd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jywlabs/hal/internal/engine"
+	"github.com/jywlabs/hal/internal/template"
+)
+
+func storyTestPRD() *engine.PRD {
+	return &engine.PRD{
+		BranchName: "hal/export",
+		UserStories: []engine.UserStory{
+			{ID: "US-001", Title: "One", AcceptanceCriteria: []string{"a"}, Priority: 1, Passes: true},
+			{ID: "US-002", Title: "Two", AcceptanceCriteria: []string{"b"}, Priority: 2},
+			{ID: "US-004", Title: "Four", AcceptanceCriteria: []string{"c"}, Priority: 3},
+		},
+	}
+}
+
+func TestAddStory_UsesNextIDAndLowestPriority(t *testing.T) {
+	p := storyTestPRD()
+	story, err := AddStory(p, StoryFields{Title: " Five ", AcceptanceCriteria: []string{"d", " "}})
+	if err != nil {
+		t.Fatalf("AddStory() error = %v", err)
+	}
+	if story.ID != "US-005" || story.Priority != 4 || story.Title != "Five" || len(story.AcceptanceCriteria) != 1 {
+		t.Errorf("story = %+v", story)
+	}
+	if _, err := AddStory(p, StoryFields{ID: "US-002", Title: "Dup"}); err == nil {
+		t.Error("expected duplicate ID error")
+	}
+
+	tasks := &engine.PRD{Tasks: []engine.UserStory{{ID: "T-001", Title: "Task", Priority: 1}}}
+	if story, _ := AddStory(tasks, StoryFields{Title: "Next"}); story == nil || story.ID != "T-002" || len(tasks.Tasks) != 2 {
+		t.Errorf("granular add = %+v, tasks = %d", story, len(tasks.Tasks))
+	}
+}
+
+func TestMoveStory_RenumbersPriorities(t *testing.T) {
+	tests := []struct {
+		name   string
+		target MoveTarget
+		want   []string
+	}{
+		{name: "to position", target: MoveTarget{Position: 1}, want: []string{"US-004", "US-001", "US-002"}},
+		{name: "before", target: MoveTarget{Before: "US-002"}, want: []string{"US-001", "US-004", "US-002"}},
+		{name: "after", target: MoveTarget{After: "US-001"}, want: []string{"US-001", "US-004", "US-002"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := storyTestPRD()
+			order, err := MoveStory(p, "US-004", tt.target)
+			if err != nil {
+				t.Fatalf("MoveStory() error = %v", err)
+			}
+			if !reflect.DeepEqual(order, tt.want) {
+				t.Fatalf("order = %v, want %v", order, tt.want)
+			}
+			for i, s := range p.UserStories {
+				if s.ID != tt.want[i] || s.Priority != i+1 {
+					t.Errorf("story %d = %s (priority %d), want %s (priority %d)", i, s.ID, s.Priority, tt.want[i], i+1)
+				}
+			}
+		})
+	}
+
+	if _, err := MoveStory(storyTestPRD(), "US-001", MoveTarget{Position: 9}); err == nil {
+		t.Error("expected out-of-range position error")
+	}
+}
+
+func TestResetAndSkipStory(t *testing.T) {
+	p := storyTestPRD()
+	if _, err := SkipStory(p, "US-001", ""); err == nil || !strings.Contains(err.Error(), "already passes") {
+		t.Fatalf("skip passing story error = %v", err)
+	}
+	story, err := SkipStory(p, "US-002", "blocked")
+	if err != nil || !story.Skipped || story.Notes != "Skipped: blocked" {
+		t.Fatalf("SkipStory() = %+v, %v", story, err)
+	}
+	if story, err = ResetStory(p, "US-002"); err != nil || story.Skipped || story.Passes {
+		t.Fatalf("ResetStory() = %+v, %v", story, err)
+	}
+	if story, _ = ResetStory(p, "US-001"); story.Passes {
+		t.Error("ResetStory() should clear passes")
+	}
+}
+
+func TestEditStory_ReportsChangedFields(t *testing.T) {
+	p := storyTestPRD()
+	title := "Two, renamed"
+	_, changed, err := EditStory(p, "US-002", StoryEdit{Title: &title, AddCriteria: []string{"Typecheck passes"}, Verify: []string{" go test ./... "}})
+	if err != nil {
+		t.Fatalf("EditStory() error = %v", err)
+	}
+	if !reflect.DeepEqual(changed, []string{"title", "acceptanceCriteria", "verify"}) {
+		t.Errorf("changed = %v", changed)
+	}
+	if got := p.UserStories[1].AcceptanceCriteria; !reflect.DeepEqual(got, []string{"b", "Typecheck passes"}) {
+		t.Errorf("criteria = %v", got)
+	}
+	if got := p.UserStories[1].Verify; !reflect.DeepEqual(got, []string{"go test ./..."}) {
+		t.Errorf("verify = %v", got)
+	}
+}
+
+func TestValidateStructure(t *testing.T) {
+	if result := ValidateStructure(storyTestPRD()); !result.Valid || len(result.Warnings) != 0 {
+		t.Fatalf("valid PRD = %+v", result)
+	}
+
+	p := storyTestPRD()
+	p.UserStories = append(p.UserStories, engine.UserStory{ID: "US-002", Priority: 3})
+	result := ValidateStructure(p)
+	if result.Valid {
+		t.Fatal("expected duplicate ID and missing title to be errors")
+	}
+	var messages []string
+	for _, e := range result.Errors {
+		messages = append(messages, e.Message)
+	}
+	if !reflect.DeepEqual(messages, []string{"duplicate story id", "story has no title"}) {
+		t.Errorf("errors = %v", messages)
+	}
+	if len(result.Warnings) != 2 {
+		t.Errorf("warnings = %+v, want missing criteria and shared priority", result.Warnings)
+	}
+
+	if result := ValidateStructure(&engine.PRD{BranchName: "x"}); result.Valid {
+		t.Error("expected PRD without stories to be invalid")
+	}
+}
+
+func TestAppendStoryAudit(t *testing.T) {
+	halDir := filepath.Join(t.TempDir(), template.HalDir)
+	if err := os.MkdirAll(halDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(halDir, template.ProgressFile), "# Progress\n")
+
+	now := time.Date(2026, 3, 4, 5, 6, 0, 0, time.UTC)
+	if err := AppendStoryAudit(halDir, "US-002", "skip", []string{"Skipped: blocked"}, now); err != nil {
+		t.Fatalf("AppendStoryAudit() error = %v", err)
+	}
+	data, _ := os.ReadFile(filepath.Join(halDir, template.ProgressFile))
+	want := "# Progress\n\n## 2026-03-04 05:06 - US-002\n- hal prd story skip\n- Skipped: blocked\n---\n"
+	if string(data) != want {
+		t.Errorf("progress = %q, want %q", data, want)
+	}
+}
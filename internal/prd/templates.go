@@ -0,0 +1,267 @@
+package prd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jywlabs/hal/internal/engine"
+	"github.com/jywlabs/hal/internal/template"
+	"gopkg.in/yaml.v3"
+)
+
+// prdTemplateHeading marks the markdown PRD section naming the template it
+// was planned from. hal convert copies the name into prd.json.
+const prdTemplateHeading = "## PRD Template"
+
+// requiredStorySimilarity is the title overlap a story needs to satisfy a
+// template's required story.
+const requiredStorySimilarity = 0.5
+
+// Template is a PRD template from .hal/templates/prd/<name>.md: front matter
+// with planning constraints followed by markdown guidance for the PRD. Its
+// name is the file name; a front-matter name must match it.
+type Template struct {
+	Name        string `yaml:"name" json:"name"`
+	Description string `yaml:"description" json:"description,omitempty"`
+	// Questions are always asked during hal plan, before generated ones.
+	Questions []string `yaml:"questions" json:"questions,omitempty"`
+	// RequiredStories are story titles the PRD must contain.
+	RequiredStories []string `yaml:"requiredStories" json:"requiredStories,omitempty"`
+	// AcceptanceCriteria must appear on every story.
+	AcceptanceCriteria []string `yaml:"acceptanceCriteria" json:"acceptanceCriteria,omitempty"`
+	Body               string   `yaml:"-" json:"-"`
+	Path               string   `yaml:"-" json:"path"`
+}
+
+// TemplatesDir returns the PRD templates directory under projectDir.
+func TemplatesDir(projectDir string) string {
+	return filepath.Join(projectDir, template.HalDir, template.PRDTemplatesDir)
+}
+
+// ListTemplates loads every template in .hal/templates/prd/, sorted by name.
+// A missing directory yields no templates.
+func ListTemplates(projectDir string) ([]*Template, error) {
+	dir := TemplatesDir(projectDir)
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+
+	var templates []*Template
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".md" {
+			continue
+		}
+		t, err := loadTemplateFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates, nil
+}
+
+// LoadTemplate loads the template called name from .hal/templates/prd/.
+func LoadTemplate(projectDir, name string) (*Template, error) {
+	name = strings.TrimSuffix(strings.TrimSpace(name), ".md")
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return nil, fmt.Errorf("invalid template name %q", name)
+	}
+	path := filepath.Join(TemplatesDir(projectDir), name+".md")
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		var names []string
+		if templates, listErr := ListTemplates(projectDir); listErr == nil {
+			for _, t := range templates {
+				names = append(names, t.Name)
+			}
+		}
+		if len(names) == 0 {
+			return nil, fmt.Errorf("template %q not found in %s", name, TemplatesDir(projectDir))
+		}
+		return nil, fmt.Errorf("template %q not found (available: %s)", name, strings.Join(names, ", "))
+	}
+	return loadTemplateFile(path)
+}
+
+func loadTemplateFile(path string) (*Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+	t, err := parseTemplate(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid template %s: %w", path, err)
+	}
+	// Templates are loaded by file name, so that is the name listed and
+	// recorded in PRDs. A differing front-matter name would never resolve.
+	fileName := strings.TrimSuffix(filepath.Base(path), ".md")
+	if t.Name != "" && t.Name != fileName {
+		return nil, fmt.Errorf("invalid template %s: front matter name %q must match the file name %q", path, t.Name, fileName)
+	}
+	t.Name = fileName
+	t.Path = path
+	return t, nil
+}
+
+// parseTemplate splits a template into its YAML front matter and body.
+func parseTemplate(content string) (*Template, error) {
+	lines := strings.Split(content, "\n")
+	start, end, ok := markdownFrontmatterBounds(lines)
+	t := &Template{}
+	if !ok {
+		t.Body = strings.TrimSpace(content)
+		return t, nil
+	}
+	if err := yaml.Unmarshal([]byte(strings.Join(lines[start+1:end-1], "\n")), t); err != nil {
+		return nil, fmt.Errorf("front matter: %w", err)
+	}
+	t.Name = strings.TrimSpace(t.Name)
+	t.Questions = trimCriteria(t.Questions)
+	t.RequiredStories = trimCriteria(t.RequiredStories)
+	t.AcceptanceCriteria = trimCriteria(t.AcceptanceCriteria)
+	t.Body = strings.TrimSpace(strings.Join(lines[end:], "\n"))
+	return t, nil
+}
+
+// promptSection renders the template for the question and PRD generation
+// prompts. It is appended to the prd skill.
+func (t *Template) promptSection() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n<template name=%q>\n", t.Name)
+	if t.Body != "" {
+		b.WriteString(t.Body + "\n")
+	}
+	if len(t.Questions) > 0 {
+		b.WriteString("\nWhen generating clarifying questions, ask these first (with A/B/C/D options):\n")
+		for _, q := range t.Questions {
+			fmt.Fprintf(&b, "- %s\n", q)
+		}
+	}
+	if len(t.RequiredStories) > 0 {
+		b.WriteString("\nThe PRD MUST include a user story for each of:\n")
+		for _, s := range t.RequiredStories {
+			fmt.Fprintf(&b, "- %s\n", s)
+		}
+	}
+	if len(t.AcceptanceCriteria) > 0 {
+		b.WriteString("\nEvery user story MUST include these acceptance criteria verbatim:\n")
+		for _, c := range t.AcceptanceCriteria {
+			fmt.Fprintf(&b, "- %s\n", c)
+		}
+	}
+	b.WriteString("</template>\n")
+	return b.String()
+}
+
+// CheckTemplate reports stories and criteria the template requires but the
+// PRD lacks.
+func CheckTemplate(p *engine.PRD, t *Template) []Issue {
+	stories := storyList(p)
+	var issues []Issue
+	for _, required := range t.RequiredStories {
+		found := false
+		for _, s := range stories {
+			if normalizeStoryTitle(s.Title) == normalizeStoryTitle(required) || titleSimilarity(s.Title, required) >= requiredStorySimilarity {
+				found = true
+				break
+			}
+		}
+		if !found {
+			issues = append(issues, Issue{
+				Field:    "userStories",
+				Message:  fmt.Sprintf("template %s requires a story for %q", t.Name, required),
+				Severity: "error",
+			})
+		}
+	}
+	for _, s := range stories {
+		for _, criterion := range t.AcceptanceCriteria {
+			if !hasCriterion(s.AcceptanceCriteria, criterion) {
+				issues = append(issues, Issue{
+					StoryID:  s.ID,
+					Field:    "acceptanceCriteria",
+					Message:  fmt.Sprintf("template %s requires the criterion %q", t.Name, criterion),
+					Severity: "error",
+				})
+			}
+		}
+	}
+	return issues
+}
+
+// ApplyTemplateChecks adds the constraints of the PRD's template to result.
+// A PRD without a template is left unchanged; a template that no longer
+// exists is reported as a warning.
+func ApplyTemplateChecks(projectDir string, p *engine.PRD, result *ValidationResult) {
+	if strings.TrimSpace(p.Template) == "" {
+		return
+	}
+	t, err := LoadTemplate(projectDir, p.Template)
+	if err != nil {
+		result.Warnings = append(result.Warnings, Issue{Field: "template", Message: err.Error(), Severity: "warning"})
+		return
+	}
+	if issues := CheckTemplate(p, t); len(issues) > 0 {
+		result.Errors = append(result.Errors, issues...)
+		result.Valid = false
+	}
+}
+
+func hasCriterion(criteria []string, want string) bool {
+	want = strings.ToLower(strings.Join(strings.Fields(want), " "))
+	for _, c := range criteria {
+		if strings.Contains(strings.ToLower(strings.Join(strings.Fields(c), " ")), want) {
+			return true
+		}
+	}
+	return false
+}
+
+// appendTemplateName records the template at the end of a markdown PRD.
+func appendTemplateName(prdContent, name string) string {
+	if name == "" {
+		return prdContent
+	}
+	return strings.TrimRight(prdContent, "\n") + "\n\n" + prdTemplateHeading + "\n\n" + name + "\n"
+}
+
+// templateNameFromMarkdown reads the name written by appendTemplateName.
+func templateNameFromMarkdown(mdContent string) string {
+	inSection := false
+	for _, line := range strings.Split(mdContent, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "## ") {
+			inSection = strings.EqualFold(trimmed, prdTemplateHeading)
+			continue
+		}
+		if inSection && trimmed != "" {
+			return strings.Trim(trimmed, "-* `")
+		}
+	}
+	return ""
+}
+
+// setPRDTemplate records the template name in a prd.json document.
+func setPRDTemplate(prdJSON, name string) (string, error) {
+	if name == "" {
+		return prdJSON, nil
+	}
+	var prd engine.PRD
+	if err := json.Unmarshal([]byte(prdJSON), &prd); err != nil {
+		return "", err
+	}
+	prd.Template = name
+	formatted, err := json.MarshalIndent(prd, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(formatted), nil
+}
@@ -0,0 +1,165 @@
+package prd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jywlabs/hal/internal/engine"
+	"github.com/jywlabs/hal/internal/template"
+)
+
+func writeTemplate(t *testing.T, dir, name, content string) {
+	t.Helper()
+	templatesDir := TemplatesDir(dir)
+	if err := os.MkdirAll(templatesDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(templatesDir, name+".md"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+const endpointTemplate = `---
+name: api-endpoint
+description: New endpoint
+questions:
+  - Which path?
+requiredStories:
+  - Implement endpoint handler
+  - Add endpoint tests
+acceptanceCriteria:
+  - Typecheck passes
+---
+
+# Guidance
+
+Cover the handler.
+`
+
+func TestLoadTemplate_ParsesFrontMatter(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, "api-endpoint", endpointTemplate)
+
+	tmpl, err := LoadTemplate(dir, "api-endpoint")
+	if err != nil {
+		t.Fatalf("LoadTemplate() error = %v", err)
+	}
+	if tmpl.Name != "api-endpoint" || tmpl.Description != "New endpoint" {
+		t.Errorf("template = %+v", tmpl)
+	}
+	if !reflect.DeepEqual(tmpl.Questions, []string{"Which path?"}) || len(tmpl.RequiredStories) != 2 {
+		t.Errorf("constraints = %+v", tmpl)
+	}
+	if tmpl.Body != "# Guidance\n\nCover the handler." {
+		t.Errorf("body = %q", tmpl.Body)
+	}
+	section := tmpl.promptSection()
+	for _, want := range []string{"Which path?", "Implement endpoint handler", "Typecheck passes", "Cover the handler."} {
+		if !strings.Contains(section, want) {
+			t.Errorf("prompt section missing %q:\n%s", want, section)
+		}
+	}
+
+	if _, err := LoadTemplate(dir, "missing"); err == nil || !strings.Contains(err.Error(), "available: api-endpoint") {
+		t.Errorf("missing template error = %v", err)
+	}
+}
+
+func TestListTemplates_SortedAndEmptyDir(t *testing.T) {
+	dir := t.TempDir()
+	if templates, err := ListTemplates(dir); err != nil || len(templates) != 0 {
+		t.Fatalf("ListTemplates() on missing dir = %v, %v", templates, err)
+	}
+	writeTemplate(t, dir, "zeta", "# no front matter\n")
+	writeTemplate(t, dir, "api-endpoint", endpointTemplate)
+
+	templates, err := ListTemplates(dir)
+	if err != nil {
+		t.Fatalf("ListTemplates() error = %v", err)
+	}
+	if len(templates) != 2 || templates[0].Name != "api-endpoint" || templates[1].Name != "zeta" {
+		t.Fatalf("templates = %+v", templates)
+	}
+}
+
+func TestLoadTemplate_NameMustMatchFileName(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, "endpoint", endpointTemplate)
+
+	if _, err := LoadTemplate(dir, "endpoint"); err == nil || !strings.Contains(err.Error(), `must match the file name "endpoint"`) {
+		t.Fatalf("LoadTemplate() error = %v, want name mismatch", err)
+	}
+	if _, err := ListTemplates(dir); err == nil {
+		t.Fatal("ListTemplates() error = nil, want name mismatch")
+	}
+}
+
+func TestDefaultPRDTemplatesParse(t *testing.T) {
+	defaults := template.DefaultPRDTemplates()
+	if len(defaults) == 0 {
+		t.Fatal("no embedded PRD templates")
+	}
+	for name, content := range defaults {
+		tmpl, err := parseTemplate(content)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if tmpl.Name+".md" != name || len(tmpl.RequiredStories) == 0 || tmpl.Body == "" {
+			t.Errorf("%s: template = %+v", name, tmpl)
+		}
+	}
+}
+
+func TestApplyTemplateChecks(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, "api-endpoint", endpointTemplate)
+
+	p := &engine.PRD{
+		Template: "api-endpoint",
+		UserStories: []engine.UserStory{
+			{ID: "US-001", Title: "Implement the endpoint handler", AcceptanceCriteria: []string{"typecheck passes"}},
+			{ID: "US-002", Title: "Document endpoint", AcceptanceCriteria: []string{"Docs updated"}},
+		},
+	}
+	result := &ValidationResult{Valid: true}
+	ApplyTemplateChecks(dir, p, result)
+	if result.Valid {
+		t.Fatal("expected template violations")
+	}
+	var messages []string
+	for _, e := range result.Errors {
+		messages = append(messages, e.StoryID+": "+e.Message)
+	}
+	want := []string{
+		`: template api-endpoint requires a story for "Add endpoint tests"`,
+		`US-002: template api-endpoint requires the criterion "Typecheck passes"`,
+	}
+	if !reflect.DeepEqual(messages, want) {
+		t.Errorf("errors = %q, want %q", messages, want)
+	}
+
+	p.Template = "gone"
+	result = &ValidationResult{Valid: true}
+	ApplyTemplateChecks(dir, p, result)
+	if !result.Valid || len(result.Warnings) != 1 {
+		t.Errorf("missing template result = %+v, want one warning", result)
+	}
+}
+
+func TestTemplateNameMarkdownRoundTrip(t *testing.T) {
+	md := appendTemplateName("# PRD: Orders\n\n## Goals\n\n- Ship\n", "api-endpoint")
+	if got := templateNameFromMarkdown(md); got != "api-endpoint" {
+		t.Fatalf("templateNameFromMarkdown() = %q", got)
+	}
+	if got := templateNameFromMarkdown("# PRD\n"); got != "" {
+		t.Fatalf("templateNameFromMarkdown() without section = %q", got)
+	}
+	prdJSON, err := setPRDTemplate(`{"project":"x","branchName":"hal/x","description":"","userStories":[]}`, "api-endpoint")
+	if err != nil || !strings.Contains(prdJSON, `"template": "api-endpoint"`) {
+		t.Fatalf("setPRDTemplate() = %s, %v", prdJSON, err)
+	}
+}
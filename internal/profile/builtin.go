@@ -0,0 +1,288 @@
+package profile
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// check is a quality check command tagged with its purpose, so a Makefile
+// target can replace the toolchain's own command for the same purpose.
+type check struct {
+	purpose string
+	command string
+}
+
+// checkOrder is the order quality checks are written to config.yaml.
+var checkOrder = []string{"build", "typecheck", "vet", "lint", "test"}
+
+type builtin struct {
+	name        string
+	description string
+	detect      func(dir string) bool
+	build       func(dir string) ([]check, []string, map[string]string)
+}
+
+// builtins are listed in detection priority order: when several match, the
+// first one names the merged profile.
+var builtins = []builtin{
+	{
+		name:        "go",
+		description: "Go modules (go.mod)",
+		detect:      func(dir string) bool { return exists(dir, "go.mod") },
+		build: func(dir string) ([]check, []string, map[string]string) {
+			return []check{
+				{"build", "go build ./..."},
+				{"vet", "go vet ./..."},
+				{"test", "go test ./..."},
+			}, []string{
+				"Go module: format with gofmt and keep `go vet ./...` clean.",
+				"Tests live next to the code in *_test.go files; prefer table-driven tests.",
+			}, map[string]string{
+				"go/errors.md": goErrorsStandard,
+			}
+		},
+	},
+	{
+		name:        "pnpm",
+		description: "pnpm (pnpm-lock.yaml or pnpm-workspace.yaml)",
+		detect: func(dir string) bool {
+			return exists(dir, "pnpm-lock.yaml") || exists(dir, "pnpm-workspace.yaml")
+		},
+		build: func(dir string) ([]check, []string, map[string]string) {
+			run := func(script string) string { return "pnpm run " + script }
+			hint := "Use pnpm for dependencies (`pnpm add`), never npm or yarn."
+			if exists(dir, "pnpm-workspace.yaml") {
+				run = func(script string) string { return "pnpm -r --if-present run " + script }
+				hint = "pnpm workspace: add dependencies to the package that uses them (`pnpm --filter <pkg> add`)."
+			}
+			return nodeChecks(dir, run), []string{hint}, map[string]string{"typescript/style.md": tsStyleStandard}
+		},
+	},
+	{
+		name:        "yarn",
+		description: "Yarn (yarn.lock)",
+		detect:      func(dir string) bool { return exists(dir, "yarn.lock") },
+		build: func(dir string) ([]check, []string, map[string]string) {
+			run := func(script string) string { return "yarn run " + script }
+			hint := "Use yarn for dependencies (`yarn add`), never npm or pnpm."
+			if len(packageJSON(dir).Workspaces) > 0 {
+				run = func(script string) string { return "yarn workspaces foreach --all run " + script }
+				hint = "Yarn workspace: add dependencies to the workspace that uses them (`yarn workspace <pkg> add`)."
+			}
+			return nodeChecks(dir, run), []string{hint}, map[string]string{"typescript/style.md": tsStyleStandard}
+		},
+	},
+	{
+		name:        "npm",
+		description: "npm (package.json)",
+		detect:      func(dir string) bool { return exists(dir, "package.json") },
+		build: func(dir string) ([]check, []string, map[string]string) {
+			run := func(script string) string { return "npm run " + script }
+			hint := "Use npm for dependencies (`npm install`); keep package-lock.json committed."
+			if len(packageJSON(dir).Workspaces) > 0 {
+				run = func(script string) string { return "npm run " + script + " --workspaces --if-present" }
+				hint = "npm workspace: add dependencies to the workspace that uses them (`npm install <dep> -w <pkg>`)."
+			}
+			return nodeChecks(dir, run), []string{hint}, map[string]string{"typescript/style.md": tsStyleStandard}
+		},
+	},
+	{
+		name:        "cargo",
+		description: "Rust with Cargo (Cargo.toml)",
+		detect:      func(dir string) bool { return exists(dir, "Cargo.toml") },
+		build: func(dir string) ([]check, []string, map[string]string) {
+			scope := ""
+			if fileContains(dir, "Cargo.toml", "[workspace]") {
+				scope = " --workspace"
+			}
+			return []check{
+				{"build", "cargo build" + scope},
+				{"lint", "cargo clippy" + scope + " --all-targets -- -D warnings"},
+				{"test", "cargo test" + scope},
+			}, []string{
+				"Rust: format with `cargo fmt` and keep clippy warnings at zero.",
+			}, map[string]string{
+				"rust/errors.md": rustErrorsStandard,
+			}
+		},
+	},
+	{
+		name:        "uv",
+		description: "Python with uv (uv.lock or [tool.uv])",
+		detect: func(dir string) bool {
+			return exists(dir, "uv.lock") || fileContains(dir, "pyproject.toml", "[tool.uv")
+		},
+		build: func(dir string) ([]check, []string, map[string]string) {
+			return pythonChecks(dir, "uv run "), []string{
+				"Python with uv: add dependencies with `uv add` and run tools through `uv run`.",
+			}, map[string]string{"python/style.md": pythonStyleStandard}
+		},
+	},
+	{
+		name:        "poetry",
+		description: "Python with Poetry (poetry.lock or [tool.poetry])",
+		detect: func(dir string) bool {
+			return exists(dir, "poetry.lock") || fileContains(dir, "pyproject.toml", "[tool.poetry")
+		},
+		build: func(dir string) ([]check, []string, map[string]string) {
+			return pythonChecks(dir, "poetry run "), []string{
+				"Python with Poetry: add dependencies with `poetry add` and run tools through `poetry run`.",
+			}, map[string]string{"python/style.md": pythonStyleStandard}
+		},
+	},
+	{
+		name:        "make",
+		description: "Makefile targets (build, typecheck, vet, lint, test, check)",
+		detect:      func(dir string) bool { return len(makeTargets(dir)) > 0 },
+		build: func(dir string) ([]check, []string, map[string]string) {
+			targets := makeTargets(dir)
+			var checks []check
+			for _, purpose := range checkOrder {
+				if targets[purpose] {
+					checks = append(checks, check{purpose, "make " + purpose})
+				}
+			}
+			if targets["check"] && len(checks) == 0 {
+				checks = append(checks, check{"test", "make check"})
+			}
+			return checks, []string{"Prefer the Makefile targets over invoking tools directly."}, nil
+		},
+	},
+}
+
+// nodeChecks maps package.json scripts to quality checks. Without a
+// package.json the common typecheck, lint and test scripts are assumed.
+func nodeChecks(dir string, run func(script string) string) []check {
+	scripts := packageJSON(dir).Scripts
+	if scripts == nil {
+		scripts = map[string]string{"typecheck": "", "lint": "", "test": ""}
+	}
+	var checks []check
+	for _, purpose := range checkOrder {
+		for _, script := range nodeScriptNames[purpose] {
+			if _, ok := scripts[script]; ok {
+				checks = append(checks, check{purpose, run(script)})
+				break
+			}
+		}
+	}
+	return checks
+}
+
+var nodeScriptNames = map[string][]string{
+	"build":     {"build"},
+	"typecheck": {"typecheck", "type-check", "tsc"},
+	"lint":      {"lint"},
+	"test":      {"test"},
+}
+
+// pythonChecks uses the tools pyproject.toml mentions, defaulting to pytest.
+func pythonChecks(dir, runner string) []check {
+	checks := []check{}
+	if fileContains(dir, "pyproject.toml", "mypy") {
+		checks = append(checks, check{"typecheck", runner + "mypy ."})
+	}
+	if fileContains(dir, "pyproject.toml", "ruff") {
+		checks = append(checks, check{"lint", runner + "ruff check ."})
+	}
+	return append(checks, check{"test", runner + "pytest"})
+}
+
+type packageManifest struct {
+	Scripts    map[string]string `json:"scripts"`
+	Workspaces json.RawMessage   `json:"workspaces"`
+}
+
+func packageJSON(dir string) packageManifest {
+	var m packageManifest
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return m
+	}
+	_ = json.Unmarshal(data, &m)
+	if string(m.Workspaces) == "null" {
+		m.Workspaces = nil
+	}
+	return m
+}
+
+var makeTargetPattern = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9_-]*)\s*:([^=]|$)`)
+
+// makeTargets returns the quality-related targets defined in the Makefile.
+func makeTargets(dir string) map[string]bool {
+	f, err := os.Open(filepath.Join(dir, "Makefile"))
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	wanted := map[string]bool{"check": true}
+	for _, purpose := range checkOrder {
+		wanted[purpose] = true
+	}
+	targets := map[string]bool{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if m := makeTargetPattern.FindStringSubmatch(scanner.Text()); m != nil && wanted[m[1]] {
+			targets[m[1]] = true
+		}
+	}
+	return targets
+}
+
+func exists(dir, name string) bool {
+	_, err := os.Stat(filepath.Join(dir, name))
+	return err == nil
+}
+
+func fileContains(dir, name, substr string) bool {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	return err == nil && strings.Contains(string(data), substr)
+}
+
+const goErrorsStandard = `---
+applies_to: ["**/*.go"]
+tags: [go]
+---
+# Go Error Handling
+
+- Return errors instead of panicking; panic only for programmer errors.
+- Wrap errors with context using ` + "`fmt.Errorf(\"doing x: %w\", err)`" + `.
+- Check errors with ` + "`errors.Is`/`errors.As`" + `, never by comparing strings.
+`
+
+const tsStyleStandard = `---
+applies_to: ["**/*.ts", "**/*.tsx", "**/*.js", "**/*.jsx"]
+tags: [typescript, javascript]
+---
+# TypeScript Style
+
+- Keep ` + "`strict`" + ` type checking on; avoid ` + "`any`" + ` and non-null assertions.
+- Prefer named exports and small modules.
+- Co-locate tests with the code they cover.
+`
+
+const rustErrorsStandard = `---
+applies_to: ["**/*.rs"]
+tags: [rust]
+---
+# Rust Error Handling
+
+- Propagate errors with ` + "`?`" + `; avoid ` + "`unwrap`/`expect`" + ` outside tests.
+- Give library errors a typed enum; add context at application boundaries.
+`
+
+const pythonStyleStandard = `---
+applies_to: ["**/*.py"]
+tags: [python]
+---
+# Python Style
+
+- Add type hints to public functions.
+- Raise specific exceptions; never use a bare ` + "`except:`" + `.
+- Put tests under tests/ and name them test_*.py.
+`
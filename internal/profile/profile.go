@@ -0,0 +1,399 @@
+// Package profile detects a project's toolchain and describes how hal init
+// should tailor .hal/ for it: quality check commands for config.yaml,
+// toolchain hints for prompt.md, and starter standards.
+//
+// Built-in profiles cover Go, npm, pnpm, yarn, Cargo, Poetry, uv and
+// Makefile targets. User-defined profiles live as <name>.yaml files in the
+// profiles/ directory of the global hal config dir.
+package profile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jywlabs/hal/internal/template"
+	"gopkg.in/yaml.v3"
+)
+
+// ProfilesDir is the directory for user-defined profiles inside the global
+// hal config dir.
+const ProfilesDir = "profiles"
+
+// Generic is the profile that leaves hal's defaults untouched.
+const Generic = "generic"
+
+// Source values for profiles.
+const (
+	SourceBuiltin  = "builtin"
+	SourceDetected = "detected"
+)
+
+// Profile describes how hal init tailors .hal/ for a toolchain.
+type Profile struct {
+	Name          string            `yaml:"name" json:"name"`
+	Description   string            `yaml:"description" json:"description"`
+	Extends       string            `yaml:"extends,omitempty" json:"extends,omitempty"`
+	QualityChecks []string          `yaml:"qualityChecks" json:"qualityChecks"`
+	PromptHints   []string          `yaml:"promptHints" json:"promptHints"`
+	Standards     map[string]string `yaml:"standards" json:"standards,omitempty"` // Path under .hal/standards/ -> content
+	// Source is "builtin", "detected", or the path of a user profile.
+	Source string `yaml:"-" json:"source"`
+}
+
+// Detect merges every built-in profile whose markers exist in dir. Makefile
+// targets replace toolchain commands with the same purpose. Returns the
+// generic profile when nothing matches.
+func Detect(dir string) Profile {
+	var names []string
+	var checks []check
+	var hints []string
+	standards := map[string]string{}
+	for _, b := range builtins {
+		if !b.detect(dir) {
+			continue
+		}
+		// One Node package manager is enough; lockfiles decide which.
+		if isNode(b.name) && containsAny(names, "pnpm", "yarn", "npm") {
+			continue
+		}
+		names = append(names, b.name)
+		c, h, s := b.build(dir)
+		checks = mergeChecks(checks, c)
+		hints = append(hints, h...)
+		for path, content := range s {
+			standards[path] = content
+		}
+	}
+	if len(names) == 0 {
+		return genericProfile()
+	}
+	return Profile{
+		Name:          strings.Join(names, "+"),
+		Description:   "Detected: " + strings.Join(names, ", "),
+		QualityChecks: commands(checks),
+		PromptHints:   hints,
+		Standards:     standards,
+		Source:        SourceDetected,
+	}
+}
+
+// Resolve returns the named profile for dir. User profiles in userDir take
+// precedence over built-ins and may extend one.
+func Resolve(dir, name, userDir string) (Profile, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return Detect(dir), nil
+	}
+	if p, ok, err := loadUser(userDir, name); err != nil {
+		return Profile{}, err
+	} else if ok {
+		if p.Extends == "" {
+			return p, nil
+		}
+		base, ok := builtinProfile(dir, p.Extends)
+		if !ok {
+			return Profile{}, fmt.Errorf("profile %q extends unknown built-in profile %q", name, p.Extends)
+		}
+		return extend(base, p), nil
+	}
+	if p, ok := builtinProfile(dir, name); ok {
+		return p, nil
+	}
+	return Profile{}, fmt.Errorf("unknown profile %q (run hal init --list-profiles)", name)
+}
+
+// List returns built-in profiles followed by user profiles from userDir,
+// each sorted by name. User profiles that fail to parse are skipped.
+func List(dir, userDir string) []Profile {
+	profiles := []Profile{genericProfile()}
+	for _, b := range builtins {
+		p, _ := builtinProfile(dir, b.name)
+		profiles = append(profiles, p)
+	}
+	sort.SliceStable(profiles[1:], func(a, b int) bool { return profiles[a+1].Name < profiles[b+1].Name })
+
+	entries, err := os.ReadDir(filepath.Join(userDir, ProfilesDir))
+	if err != nil || userDir == "" {
+		return profiles
+	}
+	var user []Profile
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".yaml")
+		if !ok || e.IsDir() {
+			continue
+		}
+		if p, err := Resolve(dir, name, userDir); err == nil {
+			user = append(user, p)
+		}
+	}
+	sort.Slice(user, func(a, b int) bool { return user[a].Name < user[b].Name })
+	return append(profiles, user...)
+}
+
+func builtinProfile(dir, name string) (Profile, bool) {
+	if name == Generic {
+		return genericProfile(), true
+	}
+	for _, b := range builtins {
+		if b.name != name {
+			continue
+		}
+		c, h, s := b.build(dir)
+		return Profile{
+			Name:          b.name,
+			Description:   b.description,
+			QualityChecks: commands(c),
+			PromptHints:   h,
+			Standards:     s,
+			Source:        SourceBuiltin,
+		}, true
+	}
+	return Profile{}, false
+}
+
+func genericProfile() Profile {
+	return Profile{
+		Name:          Generic,
+		Description:   "No toolchain settings; keep hal defaults",
+		QualityChecks: []string{},
+		PromptHints:   []string{},
+		Source:        SourceBuiltin,
+	}
+}
+
+func loadUser(userDir, name string) (Profile, bool, error) {
+	if userDir == "" {
+		return Profile{}, false, nil
+	}
+	path := filepath.Join(userDir, ProfilesDir, name+".yaml")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Profile{}, false, nil
+		}
+		return Profile{}, false, err
+	}
+	var p Profile
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return Profile{}, false, fmt.Errorf("invalid profile %s: %w", path, err)
+	}
+	p.Name = name
+	p.Source = path
+	if p.QualityChecks == nil {
+		p.QualityChecks = []string{}
+	}
+	if p.PromptHints == nil {
+		p.PromptHints = []string{}
+	}
+	for rel := range p.Standards {
+		if !validStandardPath(rel) {
+			return Profile{}, false, fmt.Errorf("invalid profile %s: standard path %q must be a relative .md path", path, rel)
+		}
+	}
+	return p, true, nil
+}
+
+// extend layers a user profile over a built-in: quality checks replace the
+// base when set, hints are appended and standards are merged.
+func extend(base, p Profile) Profile {
+	out := p
+	if len(p.QualityChecks) == 0 {
+		out.QualityChecks = base.QualityChecks
+	}
+	out.PromptHints = append(append([]string{}, base.PromptHints...), p.PromptHints...)
+	out.Standards = map[string]string{}
+	for k, v := range base.Standards {
+		out.Standards[k] = v
+	}
+	for k, v := range p.Standards {
+		out.Standards[k] = v
+	}
+	if out.Description == "" {
+		out.Description = base.Description
+	}
+	return out
+}
+
+// mergeChecks adds checks, replacing earlier commands with the same purpose.
+func mergeChecks(existing, add []check) []check {
+	for _, c := range add {
+		replaced := false
+		for i := range existing {
+			if existing[i].purpose == c.purpose {
+				existing[i] = c
+				replaced = true
+			}
+		}
+		if !replaced {
+			existing = append(existing, c)
+		}
+	}
+	sort.SliceStable(existing, func(a, b int) bool {
+		return purposeRank(existing[a].purpose) < purposeRank(existing[b].purpose)
+	})
+	return existing
+}
+
+func purposeRank(purpose string) int {
+	for i, p := range checkOrder {
+		if p == purpose {
+			return i
+		}
+	}
+	return len(checkOrder)
+}
+
+func commands(checks []check) []string {
+	out := []string{}
+	for _, c := range checks {
+		out = append(out, c.command)
+	}
+	return out
+}
+
+func isNode(name string) bool {
+	return name == "npm" || name == "pnpm" || name == "yarn"
+}
+
+func containsAny(list []string, values ...string) bool {
+	for _, l := range list {
+		for _, v := range values {
+			if l == v {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func validStandardPath(rel string) bool {
+	clean := filepath.ToSlash(filepath.Clean(rel))
+	return strings.HasSuffix(clean, ".md") && !filepath.IsAbs(rel) && clean != ".." && !strings.HasPrefix(clean, "../")
+}
+
+// ApplyResult reports what Apply changed.
+type ApplyResult struct {
+	QualityChecks bool     `json:"qualityChecks"` // config.yaml quality checks were set
+	PromptHints   bool     `json:"promptHints"`   // prompt.md toolchain section was added
+	Standards     []string `json:"standards"`     // Starter standards written, relative to .hal/
+}
+
+// Apply tailors halDir for p without overwriting user choices: quality
+// checks are set only while auto.qualityChecks is still empty, prompt hints
+// are added only when prompt is true and prompt.md has no toolchain section,
+// and starter standards are written only when missing.
+func Apply(halDir string, p Profile, prompt bool) (ApplyResult, error) {
+	result := ApplyResult{Standards: []string{}}
+
+	if len(p.QualityChecks) > 0 {
+		changed, err := rewriteFile(filepath.Join(halDir, template.ConfigFile), func(content string) string {
+			return setQualityChecks(content, p.QualityChecks)
+		})
+		if err != nil {
+			return result, err
+		}
+		result.QualityChecks = changed
+	}
+
+	if prompt && (len(p.PromptHints) > 0 || len(p.QualityChecks) > 0) {
+		changed, err := rewriteFile(filepath.Join(halDir, template.PromptFile), func(content string) string {
+			return addPromptHints(content, p)
+		})
+		if err != nil {
+			return result, err
+		}
+		result.PromptHints = changed
+	}
+
+	paths := make([]string, 0, len(p.Standards))
+	for rel := range p.Standards {
+		paths = append(paths, rel)
+	}
+	sort.Strings(paths)
+	for _, rel := range paths {
+		path := filepath.Join(halDir, template.StandardsDir, filepath.FromSlash(rel))
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return result, err
+		}
+		if err := os.WriteFile(path, []byte(p.Standards[rel]), 0644); err != nil {
+			return result, err
+		}
+		result.Standards = append(result.Standards, filepath.ToSlash(filepath.Join(template.StandardsDir, rel)))
+	}
+	return result, nil
+}
+
+// emptyQualityChecks matches the template's empty qualityChecks entry.
+const emptyQualityChecks = "  qualityChecks: []"
+
+func setQualityChecks(content string, checks []string) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if strings.TrimRight(line, " \t") != emptyQualityChecks {
+			continue
+		}
+		items := make([]string, 0, len(checks)+1)
+		items = append(items, "  qualityChecks:")
+		for _, c := range checks {
+			quoted, _ := yaml.Marshal(c)
+			items = append(items, "    - "+strings.TrimSpace(string(quoted)))
+		}
+		lines[i] = strings.Join(items, "\n")
+		return strings.Join(lines, "\n")
+	}
+	return content
+}
+
+// toolchainHeading marks the prompt section hal init adds for a profile.
+const toolchainHeading = "## Project Toolchain"
+
+const defaultQualityHint = "(e.g., typecheck, lint, test - use whatever your project requires)"
+
+func addPromptHints(content string, p Profile) string {
+	if strings.Contains(content, toolchainHeading) {
+		return content
+	}
+	if len(p.QualityChecks) > 0 {
+		content = strings.Replace(content, defaultQualityHint, "(see Project Toolchain below)", 1)
+	}
+
+	var b strings.Builder
+	b.WriteString(toolchainHeading + "\n\n")
+	if len(p.QualityChecks) > 0 {
+		b.WriteString("Quality checks for this project (run all of them before committing):\n\n")
+		for _, c := range p.QualityChecks {
+			b.WriteString("- `" + c + "`\n")
+		}
+		b.WriteString("\n")
+	}
+	for _, h := range p.PromptHints {
+		b.WriteString("- " + h + "\n")
+	}
+	section := b.String() + "\n"
+
+	if i := strings.Index(content, "## Progress Report Format"); i >= 0 {
+		return content[:i] + section + content[i:]
+	}
+	return strings.TrimRight(content, "\n") + "\n\n" + strings.TrimRight(section, "\n") + "\n"
+}
+
+func rewriteFile(path string, fn func(string) string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	updated := fn(string(data))
+	if updated == string(data) {
+		return false, nil
+	}
+	return true, os.WriteFile(path, []byte(updated), 0644)
+}
@@ -0,0 +1,185 @@
+package profile
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jywlabs/hal/internal/template"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name       string
+		files      map[string]string
+		wantName   string
+		wantChecks []string
+	}{
+		{
+			name:     "nothing detected",
+			wantName: Generic, wantChecks: []string{},
+		},
+		{
+			name:       "go module",
+			files:      map[string]string{"go.mod": "module x\n"},
+			wantName:   "go",
+			wantChecks: []string{"go build ./...", "go vet ./...", "go test ./..."},
+		},
+		{
+			name:       "go with Makefile targets",
+			files:      map[string]string{"go.mod": "module x\n", "Makefile": ".PHONY: test\ntest:\n\tgo test ./...\nlint: vet\n\tgolangci-lint run\nVERSION := 1\n"},
+			wantName:   "go+make",
+			wantChecks: []string{"go build ./...", "go vet ./...", "make lint", "make test"},
+		},
+		{
+			name: "pnpm workspace",
+			files: map[string]string{
+				"package.json":        `{"scripts": {"lint": "eslint .", "test": "vitest", "typecheck": "tsc"}}`,
+				"pnpm-workspace.yaml": "packages: ['apps/*']\n",
+				"pnpm-lock.yaml":      "",
+			},
+			wantName:   "pnpm",
+			wantChecks: []string{"pnpm -r --if-present run typecheck", "pnpm -r --if-present run lint", "pnpm -r --if-present run test"},
+		},
+		{
+			name:       "npm workspaces",
+			files:      map[string]string{"package.json": `{"workspaces": ["packages/*"], "scripts": {"test": "jest"}}`},
+			wantName:   "npm",
+			wantChecks: []string{"npm run test --workspaces --if-present"},
+		},
+		{
+			name:       "yarn",
+			files:      map[string]string{"package.json": `{"scripts": {"build": "tsc -b", "test": "jest"}}`, "yarn.lock": ""},
+			wantName:   "yarn",
+			wantChecks: []string{"yarn run build", "yarn run test"},
+		},
+		{
+			name:       "cargo workspace",
+			files:      map[string]string{"Cargo.toml": "[workspace]\nmembers = [\"a\"]\n"},
+			wantName:   "cargo",
+			wantChecks: []string{"cargo build --workspace", "cargo clippy --workspace --all-targets -- -D warnings", "cargo test --workspace"},
+		},
+		{
+			name:       "uv",
+			files:      map[string]string{"pyproject.toml": "[tool.uv]\n[tool.ruff]\n", "uv.lock": ""},
+			wantName:   "uv",
+			wantChecks: []string{"uv run ruff check .", "uv run pytest"},
+		},
+		{
+			name:       "poetry",
+			files:      map[string]string{"pyproject.toml": "[tool.poetry]\n[tool.mypy]\n"},
+			wantName:   "poetry",
+			wantChecks: []string{"poetry run mypy .", "poetry run pytest"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, tt.files)
+			p := Detect(dir)
+			if p.Name != tt.wantName {
+				t.Errorf("Name = %q, want %q", p.Name, tt.wantName)
+			}
+			if !reflect.DeepEqual(p.QualityChecks, tt.wantChecks) {
+				t.Errorf("QualityChecks = %q, want %q", p.QualityChecks, tt.wantChecks)
+			}
+		})
+	}
+}
+
+func TestResolve_UserProfiles(t *testing.T) {
+	dir := t.TempDir()
+	userDir := t.TempDir()
+	writeFiles(t, userDir, map[string]string{
+		"profiles/team-go.yaml": "description: Team Go\nextends: go\npromptHints:\n  - Use testify\nstandards:\n  go/logging.md: \"# Logging\\n\"\n",
+		"profiles/bad.yaml":     "standards:\n  ../escape.md: x\n",
+	})
+
+	p, err := Resolve(dir, "team-go", userDir)
+	if err != nil {
+		t.Fatalf("Resolve() error: %v", err)
+	}
+	if p.Name != "team-go" || p.Source != filepath.Join(userDir, "profiles", "team-go.yaml") {
+		t.Errorf("profile = %+v", p)
+	}
+	if strings.Join(p.QualityChecks, ",") != "go build ./...,go vet ./...,go test ./..." {
+		t.Errorf("QualityChecks = %q, want inherited from go", p.QualityChecks)
+	}
+	if p.PromptHints[len(p.PromptHints)-1] != "Use testify" || p.Standards["go/errors.md"] == "" || p.Standards["go/logging.md"] == "" {
+		t.Errorf("extended profile = %+v", p)
+	}
+
+	if _, err := Resolve(dir, "bad", userDir); err == nil || !strings.Contains(err.Error(), "relative .md path") {
+		t.Errorf("bad profile error = %v", err)
+	}
+	if _, err := Resolve(dir, "nope", userDir); err == nil || !strings.Contains(err.Error(), "unknown profile") {
+		t.Errorf("unknown profile error = %v", err)
+	}
+
+	var names []string
+	for _, p := range List(dir, userDir) {
+		names = append(names, p.Name)
+	}
+	if got := strings.Join(names, ","); got != "generic,cargo,go,make,npm,pnpm,poetry,uv,yarn,team-go" {
+		t.Errorf("List() = %s", got)
+	}
+}
+
+func TestApply(t *testing.T) {
+	halDir := t.TempDir()
+	writeFiles(t, halDir, map[string]string{
+		template.ConfigFile:             template.DefaultConfig,
+		template.PromptFile:             template.DefaultPrompt,
+		"standards/go/errors.md":        "# Ours\n",
+		"standards/typescript/style.md": "# Ours\n",
+	})
+	p := Profile{
+		Name:          "go",
+		QualityChecks: []string{"go test ./...", "make lint: strict"},
+		PromptHints:   []string{"Use gofmt."},
+		Standards:     map[string]string{"go/errors.md": "# Errors\n", "go/testing.md": "# Testing\n"},
+	}
+
+	result, err := Apply(halDir, p, true)
+	if err != nil {
+		t.Fatalf("Apply() error: %v", err)
+	}
+	if !result.QualityChecks || !result.PromptHints || strings.Join(result.Standards, ",") != "standards/go/testing.md" {
+		t.Fatalf("result = %+v", result)
+	}
+
+	config, _ := os.ReadFile(filepath.Join(halDir, template.ConfigFile))
+	if !strings.Contains(string(config), "  qualityChecks:\n    - go test ./...\n    - 'make lint: strict'\n") {
+		t.Errorf("config.yaml quality checks not set:\n%s", config)
+	}
+	prompt, _ := os.ReadFile(filepath.Join(halDir, template.PromptFile))
+	for _, want := range []string{"## Project Toolchain", "- `go test ./...`", "- Use gofmt.", "(see Project Toolchain below)"} {
+		if !strings.Contains(string(prompt), want) {
+			t.Errorf("prompt.md missing %q", want)
+		}
+	}
+	if errs, _ := os.ReadFile(filepath.Join(halDir, "standards", "go", "errors.md")); string(errs) != "# Ours\n" {
+		t.Errorf("existing standard overwritten: %q", errs)
+	}
+
+	// A second apply changes nothing.
+	result, err = Apply(halDir, p, true)
+	if err != nil || result.QualityChecks || result.PromptHints || len(result.Standards) != 0 {
+		t.Fatalf("second Apply() = %+v, %v", result, err)
+	}
+}
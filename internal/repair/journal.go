@@ -0,0 +1,425 @@
+// Package repair journals the files touched by hal repair so a repair can be
+// rolled back with hal repair --undo.
+//
+// A Journal snapshots tracked paths before repair steps run. Commit compares
+// them with the current state and writes only what changed to
+// .hal/repair-backups/<id>/: a journal.json manifest plus a copy of every
+// modified or deleted file. Undo restores the most recent journal.
+package repair
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jywlabs/hal/internal/template"
+)
+
+// ManifestFile is the journal manifest inside each backup directory.
+const ManifestFile = "journal.json"
+
+// Change values for journal entries.
+const (
+	ChangeCreated  = "created"
+	ChangeModified = "modified"
+	ChangeDeleted  = "deleted"
+)
+
+// Kind values for journal entries.
+const (
+	KindFile    = "file"
+	KindDir     = "dir"
+	KindSymlink = "symlink"
+)
+
+// Entry records one path changed by a repair.
+type Entry struct {
+	// Path is slash-separated and relative to the project, or absolute for
+	// paths outside it (e.g. ~/.codex links).
+	Path   string      `json:"path"`
+	Change string      `json:"change"`
+	Kind   string      `json:"kind"`             // Kind before the repair; after it for created paths
+	Mode   fs.FileMode `json:"mode,omitempty"`   // Permissions before the repair
+	Target string      `json:"target,omitempty"` // Symlink target before the repair
+	Backup string      `json:"backup,omitempty"` // File copy inside the journal directory
+}
+
+// Manifest describes a committed repair journal.
+type Manifest struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	Steps     []string  `json:"steps"`
+	Entries   []Entry   `json:"entries"`
+}
+
+type snapshot struct {
+	kind   string
+	mode   fs.FileMode
+	target string
+	data   []byte
+}
+
+// Journal captures the state of tracked paths before repair steps run.
+type Journal struct {
+	projectDir string
+	now        time.Time
+	roots      map[string]bool
+	before     map[string]snapshot
+	steps      []string
+}
+
+// Begin starts a journal for projectDir. Nothing is written until Commit.
+func Begin(projectDir string, now time.Time) *Journal {
+	return &Journal{
+		projectDir: projectDir,
+		now:        now,
+		roots:      map[string]bool{},
+		before:     map[string]snapshot{},
+	}
+}
+
+// Dir returns the directory holding repair journals for projectDir.
+func Dir(projectDir string) string {
+	return filepath.Join(projectDir, template.HalDir, template.RepairBackupsDir)
+}
+
+// Track snapshots paths (files or directory trees) that a repair step may
+// change. Relative paths are resolved against the project directory. Paths
+// already tracked keep their first snapshot.
+func (j *Journal) Track(paths ...string) error {
+	for _, p := range paths {
+		if p == "" {
+			continue
+		}
+		abs := j.abs(p)
+		if j.roots[abs] {
+			continue
+		}
+		j.roots[abs] = true
+		if err := j.walk(abs, j.before); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Step records a repair command in the journal.
+func (j *Journal) Step(command string) {
+	j.steps = append(j.steps, command)
+}
+
+// Commit compares tracked paths with their snapshots and writes a journal of
+// everything that changed. It returns nil when nothing changed.
+func (j *Journal) Commit() (*Manifest, error) {
+	after := map[string]snapshot{}
+	for _, root := range sortedKeys(j.roots) {
+		if err := j.walk(root, after); err != nil {
+			return nil, err
+		}
+	}
+
+	var entries []Entry
+	var backups []snapshot
+	for _, p := range sortedKeys(j.before) {
+		prev := j.before[p]
+		cur, ok := after[p]
+		change := ""
+		switch {
+		case !ok:
+			change = ChangeDeleted
+		case !sameSnapshot(prev, cur):
+			change = ChangeModified
+		default:
+			continue
+		}
+		entries = append(entries, Entry{Path: j.rel(p), Change: change, Kind: prev.kind, Mode: prev.mode.Perm(), Target: prev.target})
+		backups = append(backups, prev)
+	}
+	for _, p := range sortedKeys(after) {
+		if _, ok := j.before[p]; ok || createdParent(p, j.before, after) {
+			continue
+		}
+		entries = append(entries, Entry{Path: j.rel(p), Change: ChangeCreated, Kind: after[p].kind})
+		backups = append(backups, snapshot{})
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	id, dir, err := j.newJournalDir()
+	if err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		if entries[i].Change == ChangeCreated || entries[i].Kind != KindFile {
+			continue
+		}
+		name := filepath.ToSlash(filepath.Join("files", fmt.Sprintf("%04d", i)))
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create repair journal: %w", err)
+		}
+		if err := os.WriteFile(path, backups[i].data, 0600); err != nil {
+			return nil, fmt.Errorf("failed to back up %s: %w", entries[i].Path, err)
+		}
+		entries[i].Backup = name
+	}
+
+	m := &Manifest{ID: id, CreatedAt: j.now, Steps: j.steps, Entries: entries}
+	if m.Steps == nil {
+		m.Steps = []string{}
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal repair journal: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ManifestFile), append(data, '\n'), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write repair journal: %w", err)
+	}
+	return m, nil
+}
+
+// List returns committed journals, oldest first.
+func List(projectDir string) ([]Manifest, error) {
+	dirEntries, err := os.ReadDir(Dir(projectDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var manifests []Manifest
+	for _, e := range dirEntries {
+		if !e.IsDir() {
+			continue
+		}
+		m, err := loadManifest(filepath.Join(Dir(projectDir), e.Name()))
+		if err != nil {
+			continue
+		}
+		manifests = append(manifests, *m)
+	}
+	sort.Slice(manifests, func(a, b int) bool { return manifests[a].ID < manifests[b].ID })
+	return manifests, nil
+}
+
+// Undo restores the most recent journal and removes it, so repeated calls
+// walk back through earlier repairs. It returns nil when there is nothing to
+// undo.
+func Undo(projectDir string) (*Manifest, error) {
+	manifests, err := List(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(manifests) == 0 {
+		return nil, nil
+	}
+	m := manifests[len(manifests)-1]
+	dir := filepath.Join(Dir(projectDir), m.ID)
+
+	// Remove what the repair created, deepest paths first.
+	for i := len(m.Entries) - 1; i >= 0; i-- {
+		e := m.Entries[i]
+		if e.Change != ChangeCreated {
+			continue
+		}
+		if err := os.RemoveAll(resolve(projectDir, e.Path)); err != nil {
+			return nil, fmt.Errorf("failed to remove %s: %w", e.Path, err)
+		}
+	}
+
+	// Restore modified and deleted paths, parents before children.
+	for _, e := range m.Entries {
+		if e.Change == ChangeCreated {
+			continue
+		}
+		if err := restoreEntry(projectDir, dir, e); err != nil {
+			return nil, fmt.Errorf("failed to restore %s: %w", e.Path, err)
+		}
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, fmt.Errorf("failed to remove repair journal %s: %w", m.ID, err)
+	}
+	return &m, nil
+}
+
+func restoreEntry(projectDir, journalDir string, e Entry) error {
+	path := resolve(projectDir, e.Path)
+	switch e.Kind {
+	case KindDir:
+		if info, err := os.Lstat(path); err == nil && !info.IsDir() {
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+		}
+		if err := os.MkdirAll(path, 0755); err != nil {
+			return err
+		}
+		return os.Chmod(path, e.Mode)
+	case KindSymlink:
+		if err := os.RemoveAll(path); err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		return os.Symlink(e.Target, path)
+	default:
+		data, err := os.ReadFile(filepath.Join(journalDir, filepath.FromSlash(e.Backup)))
+		if err != nil {
+			return err
+		}
+		if err := os.RemoveAll(path); err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		return os.WriteFile(path, data, e.Mode)
+	}
+}
+
+func loadManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	m.ID = filepath.Base(dir)
+	return &m, nil
+}
+
+func (j *Journal) newJournalDir() (string, string, error) {
+	base := j.now.UTC().Format("20060102-150405")
+	id := base
+	for n := 2; ; n++ {
+		dir := filepath.Join(Dir(j.projectDir), id)
+		err := os.MkdirAll(filepath.Dir(dir), 0755)
+		if err == nil {
+			err = os.Mkdir(dir, 0755)
+		}
+		if err == nil {
+			return id, dir, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return "", "", fmt.Errorf("failed to create repair journal: %w", err)
+		}
+		id = fmt.Sprintf("%s-%d", base, n)
+	}
+}
+
+// walk records root and, for directories, everything beneath it. Symlinks
+// are recorded, not followed. Repair journals and archived runs are skipped.
+func (j *Journal) walk(root string, into map[string]snapshot) error {
+	skip := map[string]bool{
+		filepath.Clean(Dir(j.abs("."))):                                 true,
+		filepath.Join(j.abs(template.HalDir), "archive"):                true,
+		filepath.Join(j.abs(template.HalDir), "reports"):                true,
+		filepath.Join(j.abs(template.HalDir), template.EngineProbeFile): true,
+		filepath.Join(j.abs(template.HalDir), template.RunStatusFile):   true,
+		filepath.Join(j.abs(template.HalDir), template.RunControlFile):  true,
+	}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == root {
+				return nil
+			}
+			return err
+		}
+		if skip[path] {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		s := snapshot{mode: info.Mode()}
+		switch {
+		case info.Mode()&fs.ModeSymlink != 0:
+			s.kind = KindSymlink
+			s.target, err = os.Readlink(path)
+		case info.IsDir():
+			s.kind = KindDir
+		default:
+			s.kind = KindFile
+			s.data, err = os.ReadFile(path)
+		}
+		if err != nil {
+			return err
+		}
+		into[path] = s
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to snapshot %s: %w", j.rel(root), err)
+	}
+	return nil
+}
+
+func (j *Journal) abs(p string) string {
+	if filepath.IsAbs(p) {
+		return filepath.Clean(p)
+	}
+	base, err := filepath.Abs(j.projectDir)
+	if err != nil {
+		base = j.projectDir
+	}
+	return filepath.Join(base, p)
+}
+
+func (j *Journal) rel(abs string) string {
+	rel, err := filepath.Rel(j.abs("."), abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return abs
+	}
+	return filepath.ToSlash(rel)
+}
+
+func resolve(projectDir, p string) string {
+	if filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(projectDir, filepath.FromSlash(p))
+}
+
+func sameSnapshot(a, b snapshot) bool {
+	if a.kind != b.kind || a.target != b.target {
+		return false
+	}
+	if a.kind != KindFile {
+		return true
+	}
+	return a.mode.Perm() == b.mode.Perm() && bytes.Equal(a.data, b.data)
+}
+
+// createdParent reports whether an ancestor of p was also created, so only
+// the top-most created path is journaled.
+func createdParent(p string, before, after map[string]snapshot) bool {
+	parent := filepath.Dir(p)
+	_, existed := before[parent]
+	_, exists := after[parent]
+	return exists && !existed
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
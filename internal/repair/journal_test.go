@@ -0,0 +1,160 @@
+package repair
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	return string(data)
+}
+
+func TestJournal_CommitAndUndo(t *testing.T) {
+	dir := t.TempDir()
+	global := t.TempDir()
+	writeFile(t, filepath.Join(dir, ".hal", "prompt.md"), "custom prompt\n")
+	writeFile(t, filepath.Join(dir, ".hal", "config.yaml"), "engine: pi\n")
+	writeFile(t, filepath.Join(dir, ".hal", "rules", "old.md"), "legacy\n")
+	writeFile(t, filepath.Join(dir, ".hal", "archive", "run", "prd.json"), "{}\n")
+	if err := os.Symlink("../../.hal/skills/hal", filepath.Join(dir, ".hal", "link")); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(global, "skills", "keep.md"), "keep\n")
+
+	now := time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC)
+	j := Begin(dir, now)
+	if err := j.Track(".hal", ".gitignore", filepath.Join(global, "skills")); err != nil {
+		t.Fatalf("Track() error: %v", err)
+	}
+
+	// Simulate repair steps.
+	j.Step("hal init --refresh-templates")
+	writeFile(t, filepath.Join(dir, ".hal", "prompt.md"), "fresh prompt\n")
+	writeFile(t, filepath.Join(dir, ".hal", "skills", "hal", "SKILL.md"), "# hal\n")
+	writeFile(t, filepath.Join(dir, ".gitignore"), ".hal/\n")
+	j.Step("hal cleanup")
+	if err := os.RemoveAll(filepath.Join(dir, ".hal", "rules")); err != nil {
+		t.Fatal(err)
+	}
+	os.Remove(filepath.Join(dir, ".hal", "link"))
+	writeFile(t, filepath.Join(dir, ".hal", "archive", "run", "prd.json"), "changed\n")
+	writeFile(t, filepath.Join(global, "skills", "new.md"), "new\n")
+
+	m, err := j.Commit()
+	if err != nil {
+		t.Fatalf("Commit() error: %v", err)
+	}
+	if m == nil || m.ID != "20261018-093000" || len(m.Steps) != 2 {
+		t.Fatalf("manifest = %+v", m)
+	}
+	got := map[string]string{}
+	for _, e := range m.Entries {
+		got[e.Path] = e.Change
+	}
+	want := map[string]string{
+		".hal/prompt.md":    ChangeModified,
+		".hal/rules":        ChangeDeleted,
+		".hal/rules/old.md": ChangeDeleted,
+		".hal/link":         ChangeDeleted,
+		".hal/skills":       ChangeCreated,
+		".gitignore":        ChangeCreated,
+		filepath.Join(global, "skills", "new.md"): ChangeCreated,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("entries = %v, want %v", got, want)
+	}
+	for path, change := range want {
+		if got[path] != change {
+			t.Errorf("entry %s = %q, want %q", path, got[path], change)
+		}
+	}
+
+	undone, err := Undo(dir)
+	if err != nil {
+		t.Fatalf("Undo() error: %v", err)
+	}
+	if undone == nil || undone.ID != m.ID {
+		t.Fatalf("Undo() = %+v", undone)
+	}
+	if s := readFile(t, filepath.Join(dir, ".hal", "prompt.md")); s != "custom prompt\n" {
+		t.Errorf("prompt.md = %q", s)
+	}
+	if s := readFile(t, filepath.Join(dir, ".hal", "rules", "old.md")); s != "legacy\n" {
+		t.Errorf("rules/old.md = %q", s)
+	}
+	if target, err := os.Readlink(filepath.Join(dir, ".hal", "link")); err != nil || target != "../../.hal/skills/hal" {
+		t.Errorf("link = %q, %v", target, err)
+	}
+	for _, gone := range []string{filepath.Join(dir, ".hal", "skills"), filepath.Join(dir, ".gitignore"), filepath.Join(global, "skills", "new.md")} {
+		if _, err := os.Lstat(gone); !os.IsNotExist(err) {
+			t.Errorf("%s should be removed, err = %v", gone, err)
+		}
+	}
+	// Archived runs are not journaled.
+	if s := readFile(t, filepath.Join(dir, ".hal", "archive", "run", "prd.json")); s != "changed\n" {
+		t.Errorf("archive changed by undo: %q", s)
+	}
+	if manifests, _ := List(dir); len(manifests) != 0 {
+		t.Errorf("journal should be removed after undo: %+v", manifests)
+	}
+}
+
+func TestJournal_NoChanges(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ".hal", "config.yaml"), "engine: pi\n")
+	j := Begin(dir, time.Now())
+	if err := j.Track(".hal"); err != nil {
+		t.Fatal(err)
+	}
+	m, err := j.Commit()
+	if err != nil || m != nil {
+		t.Fatalf("Commit() = %+v, %v; want nil", m, err)
+	}
+	if m, err := Undo(dir); err != nil || m != nil {
+		t.Fatalf("Undo() = %+v, %v; want nothing to undo", m, err)
+	}
+}
+
+func TestUndo_LatestFirst(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".hal", "prompt.md")
+	writeFile(t, path, "v1\n")
+
+	start := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	for i, content := range []string{"v2\n", "v3\n"} {
+		j := Begin(dir, start.Add(time.Duration(i)*time.Minute))
+		if err := j.Track(".hal/prompt.md"); err != nil {
+			t.Fatal(err)
+		}
+		writeFile(t, path, content)
+		if _, err := j.Commit(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, want := range []string{"v2\n", "v1\n"} {
+		if _, err := Undo(dir); err != nil {
+			t.Fatalf("Undo() error: %v", err)
+		}
+		if s := readFile(t, path); s != want {
+			t.Fatalf("prompt.md = %q, want %q", s, want)
+		}
+	}
+}
@@ -0,0 +1,468 @@
+// Package repomap builds a size-budgeted map of a repository that engine
+// prompts (plan, convert, analyze, report) use as codebase context.
+package repomap
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jywlabs/hal/internal/standards"
+	"github.com/jywlabs/hal/internal/template"
+)
+
+// DefaultBudget is the default size of a rendered pack in bytes.
+const DefaultBudget = 24000
+
+// Section names, in render order.
+const (
+	SectionStack     = "stack"
+	SectionTree      = "tree"
+	SectionSymbols   = "symbols"
+	SectionReadme    = "readme"
+	SectionAgents    = "agents"
+	SectionStandards = "standards"
+	SectionCommits   = "commits"
+	SectionPRDs      = "prds"
+)
+
+// sectionShares splits the budget between sections (percent). The stack
+// section is a handful of lines and is not budgeted.
+var sectionShares = []struct {
+	name  string
+	title string
+	share int
+}{
+	{SectionStack, "Stack", 0},
+	{SectionTree, "Directory Tree", 18},
+	{SectionSymbols, "Exported Symbols", 30},
+	{SectionReadme, "README (excerpt)", 12},
+	{SectionAgents, "AGENTS.md (excerpt)", 14},
+	{SectionStandards, "Standards Index", 8},
+	{SectionCommits, "Recent Commits", 8},
+	{SectionPRDs, "Existing PRDs", 10},
+}
+
+// skipDirs are never walked for the tree or symbols.
+var skipDirs = map[string]bool{
+	"node_modules": true, "vendor": true, "dist": true, "build": true,
+	"target": true, "testdata": true, "__pycache__": true, "coverage": true,
+}
+
+// gitLog returns recent commit subjects. Injectable for testing.
+var gitLog = func(dir string, n int) (string, error) {
+	cmd := exec.Command("git", "log", fmt.Sprintf("-n%d", n), "--pretty=format:%s")
+	cmd.Dir = dir
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// Options controls how a pack is built.
+type Options struct {
+	// Budget caps the rendered size in bytes. Zero uses DefaultBudget.
+	Budget int
+	// Focus is free text (usually the feature description) used to rank
+	// which packages get their exported symbols listed.
+	Focus string
+}
+
+// Section is one part of the pack.
+type Section struct {
+	Name      string `json:"name"`
+	Title     string `json:"title"`
+	Content   string `json:"content"`
+	Truncated bool   `json:"truncated,omitempty"`
+}
+
+// Pack is the assembled repository map.
+type Pack struct {
+	Root     string    `json:"root"`
+	Budget   int       `json:"budget"`
+	Sections []Section `json:"sections"`
+}
+
+// Build assembles a pack for the repository at dir. It is best-effort:
+// sources that are missing or unreadable are left out rather than failing.
+func Build(dir string, opts Options) *Pack {
+	budget := opts.Budget
+	if budget <= 0 {
+		budget = DefaultBudget
+	}
+	halDir := filepath.Join(dir, template.HalDir)
+
+	sources := map[string]func(limit int) string{
+		SectionStack:     func(int) string { return detectStack(dir) },
+		SectionTree:      func(limit int) string { return directoryTree(dir, 3, limit) },
+		SectionSymbols:   func(limit int) string { return exportedSymbols(dir, opts.Focus, limit) },
+		SectionReadme:    func(int) string { return readFile(filepath.Join(dir, "README.md")) },
+		SectionAgents:    func(int) string { return readFile(filepath.Join(dir, "AGENTS.md")) },
+		SectionStandards: func(int) string { return standardsIndex(halDir) },
+		SectionCommits:   func(int) string { return recentCommits(dir) },
+		SectionPRDs:      func(int) string { return existingPRDs(halDir) },
+	}
+
+	pack := &Pack{Root: dir, Budget: budget}
+	for _, s := range sectionShares {
+		limit := budget * s.share / 100
+		content := strings.TrimSpace(sources[s.name](limit))
+		if content == "" {
+			continue
+		}
+		section := Section{Name: s.name, Title: s.title}
+		section.Content, section.Truncated = truncate(content, limit)
+		pack.Sections = append(pack.Sections, section)
+	}
+	return pack
+}
+
+// Render formats the pack for inclusion in a prompt.
+func (p *Pack) Render() string {
+	if p == nil || len(p.Sections) == 0 {
+		return "No codebase information available.\n"
+	}
+	var b strings.Builder
+	for i, s := range p.Sections {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "#### %s\n\n%s\n", s.Title, s.Content)
+	}
+	return b.String()
+}
+
+// Section returns the named section, or nil when it is absent.
+func (p *Pack) Section(name string) *Section {
+	for i := range p.Sections {
+		if p.Sections[i].Name == name {
+			return &p.Sections[i]
+		}
+	}
+	return nil
+}
+
+// truncate cuts content to limit bytes on a line boundary. A zero limit
+// means unbudgeted.
+func truncate(content string, limit int) (string, bool) {
+	if limit <= 0 || len(content) <= limit {
+		return content, false
+	}
+	cut := content[:limit]
+	if i := strings.LastIndex(cut, "\n"); i > limit/2 {
+		cut = cut[:i]
+	}
+	return cut + "\n… (truncated)", true
+}
+
+func readFile(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// stackMarkers maps well-known project files to a short description.
+var stackMarkers = []struct {
+	file string
+	desc string
+}{
+	{"go.mod", "Go module"},
+	{"package.json", "Node.js/JavaScript project"},
+	{"tsconfig.json", "TypeScript"},
+	{"Cargo.toml", "Rust crate"},
+	{"pyproject.toml", "Python project"},
+	{"requirements.txt", "Python requirements"},
+	{"Gemfile", "Ruby project"},
+	{"pom.xml", "Maven project"},
+	{"build.gradle", "Gradle project"},
+	{"next.config.js", "Next.js framework"},
+	{"next.config.ts", "Next.js framework"},
+	{"vite.config.ts", "Vite build tool"},
+	{"Makefile", "Makefile targets"},
+	{"Dockerfile", "Docker image"},
+}
+
+func detectStack(dir string) string {
+	var lines []string
+	for _, m := range stackMarkers {
+		if _, err := os.Stat(filepath.Join(dir, m.file)); err != nil {
+			continue
+		}
+		line := fmt.Sprintf("- %s (%s)", m.desc, m.file)
+		if m.file == "go.mod" {
+			if module := goModulePath(filepath.Join(dir, m.file)); module != "" {
+				line = fmt.Sprintf("- %s %s (go.mod)", m.desc, module)
+			}
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func goModulePath(path string) string {
+	for _, line := range strings.Split(readFile(path), "\n") {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+			return strings.TrimSpace(rest)
+		}
+	}
+	return ""
+}
+
+func skipDir(name string) bool {
+	return strings.HasPrefix(name, ".") || skipDirs[name]
+}
+
+// directoryTree lists directories up to maxDepth with their file counts.
+func directoryTree(dir string, maxDepth, limit int) string {
+	var b strings.Builder
+	var walk func(path, indent string, depth int)
+	walk = func(path, indent string, depth int) {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return
+		}
+		for _, e := range entries {
+			if !e.IsDir() || skipDir(e.Name()) {
+				continue
+			}
+			if limit > 0 && b.Len() > limit {
+				return
+			}
+			sub := filepath.Join(path, e.Name())
+			fmt.Fprintf(&b, "%s%s/ (%d files)\n", indent, e.Name(), countFiles(sub))
+			if depth < maxDepth {
+				walk(sub, indent+"  ", depth+1)
+			}
+		}
+	}
+	walk(dir, "", 1)
+	return b.String()
+}
+
+func countFiles(dir string) int {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0
+	}
+	n := 0
+	for _, e := range entries {
+		if !e.IsDir() {
+			n++
+		}
+	}
+	return n
+}
+
+// maxSymbolsPerPackage keeps one large package from crowding out the rest.
+const maxSymbolsPerPackage = 60
+
+type goPackage struct {
+	path    string
+	symbols []string
+	score   int
+}
+
+// exportedSymbols lists exported Go declarations for the packages most
+// relevant to focus. Packages whose path or symbols mention focus terms rank
+// first; the rest are ordered by how much API they export.
+func exportedSymbols(dir, focus string, limit int) string {
+	terms := focusTerms(focus)
+	var pkgs []goPackage
+	_ = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if path != dir && skipDir(d.Name()) {
+			return filepath.SkipDir
+		}
+		symbols := packageSymbols(path)
+		if len(symbols) == 0 {
+			return nil
+		}
+		rel, _ := filepath.Rel(dir, path)
+		rel = filepath.ToSlash(rel)
+		pkg := goPackage{path: rel, symbols: symbols, score: len(symbols)}
+		haystack := strings.ToLower(rel + " " + strings.Join(symbols, " "))
+		for _, term := range terms {
+			if strings.Contains(haystack, term) {
+				pkg.score += 1000
+			}
+		}
+		pkgs = append(pkgs, pkg)
+		return nil
+	})
+
+	sort.SliceStable(pkgs, func(i, j int) bool {
+		if pkgs[i].score != pkgs[j].score {
+			return pkgs[i].score > pkgs[j].score
+		}
+		return pkgs[i].path < pkgs[j].path
+	})
+
+	var b strings.Builder
+	for _, pkg := range pkgs {
+		symbols := pkg.symbols
+		if len(symbols) > maxSymbolsPerPackage {
+			symbols = append(symbols[:maxSymbolsPerPackage:maxSymbolsPerPackage], fmt.Sprintf("… %d more", len(pkg.symbols)-maxSymbolsPerPackage))
+		}
+		entry := fmt.Sprintf("%s: %s\n", pkg.path, strings.Join(symbols, ", "))
+		if limit > 0 && b.Len()+len(entry) > limit && b.Len() > 0 {
+			break
+		}
+		b.WriteString(entry)
+	}
+	return b.String()
+}
+
+// packageSymbols returns the exported top-level declarations of the Go
+// package in dir, skipping tests. Functions are suffixed with "()".
+func packageSymbols(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	fset := token.NewFileSet()
+	seen := map[string]bool{}
+	var symbols []string
+	add := func(name string) {
+		if ast.IsExported(strings.TrimSuffix(name, "()")) && !seen[name] {
+			seen[name] = true
+			symbols = append(symbols, name)
+		}
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+		for _, decl := range file.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				if d.Recv == nil {
+					add(d.Name.Name + "()")
+				}
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					switch s := spec.(type) {
+					case *ast.TypeSpec:
+						add(s.Name.Name)
+					case *ast.ValueSpec:
+						for _, n := range s.Names {
+							add(n.Name)
+						}
+					}
+				}
+			}
+		}
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// focusTerms splits focus text into lowercase words worth matching.
+func focusTerms(focus string) []string {
+	var terms []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(focus), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	}) {
+		if len(word) >= 4 {
+			terms = append(terms, word)
+		}
+	}
+	return terms
+}
+
+func standardsIndex(halDir string) string {
+	if index, err := standards.ListIndex(halDir); err == nil && strings.TrimSpace(index) != "" {
+		return index
+	}
+	var names []string
+	root := filepath.Join(halDir, template.StandardsDir)
+	_ = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && filepath.Ext(path) == ".md" {
+			rel, _ := filepath.Rel(root, path)
+			names = append(names, "- "+strings.TrimSuffix(filepath.ToSlash(rel), ".md"))
+		}
+		return nil
+	})
+	return strings.Join(names, "\n")
+}
+
+func recentCommits(dir string) string {
+	log, err := gitLog(dir, 20)
+	if err != nil {
+		return ""
+	}
+	var lines []string
+	for _, line := range strings.Split(log, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, "- "+line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// existingPRDs lists markdown PRDs in .hal with their first heading, plus
+// the project and description of the active prd.json.
+func existingPRDs(halDir string) string {
+	matches, _ := filepath.Glob(filepath.Join(halDir, "prd-*.md"))
+	sort.Strings(matches)
+	var lines []string
+	for _, path := range matches {
+		line := "- " + filepath.Base(path)
+		if title := firstHeading(readFile(path)); title != "" {
+			line += ": " + title
+		}
+		lines = append(lines, line)
+	}
+	for _, name := range []string{template.PRDFile, template.AutoPRDFile} {
+		if summary := prdJSONSummary(filepath.Join(halDir, name)); summary != "" {
+			lines = append(lines, fmt.Sprintf("- %s: %s", name, summary))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func firstHeading(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(line), "#"); ok {
+			return strings.TrimSpace(strings.TrimLeft(rest, "#"))
+		}
+	}
+	return ""
+}
+
+// prdJSONSummary returns "project — description" for a prd.json file.
+func prdJSONSummary(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	var prd struct {
+		Project     string `json:"project"`
+		Description string `json:"description"`
+	}
+	if json.Unmarshal(data, &prd) != nil || prd.Project == "" {
+		return ""
+	}
+	if prd.Description == "" {
+		return prd.Project
+	}
+	return prd.Project + " — " + prd.Description
+}
@@ -0,0 +1,113 @@
+package repomap
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func stubGitLog(t *testing.T, log string, err error) {
+	t.Helper()
+	orig := gitLog
+	t.Cleanup(func() { gitLog = orig })
+	gitLog = func(string, int) (string, error) { return log, err }
+}
+
+func TestBuild_CollectsSections(t *testing.T) {
+	dir := t.TempDir()
+	stubGitLog(t, "Add billing export\nFix login redirect\n", nil)
+	writeFile(t, filepath.Join(dir, "go.mod"), "module example.com/shop\n\ngo 1.22\n")
+	writeFile(t, filepath.Join(dir, "README.md"), "# Shop\n\nAn online shop.\n")
+	writeFile(t, filepath.Join(dir, "AGENTS.md"), "Run make test before committing.\n")
+	writeFile(t, filepath.Join(dir, "internal", "billing", "billing.go"), `package billing
+
+type Invoice struct{}
+
+func Export() {}
+
+func (Invoice) Total() int { return 0 }
+
+func helper() {}
+`)
+	writeFile(t, filepath.Join(dir, "internal", "billing", "billing_test.go"), "package billing\n\nfunc TestOnly() {}\n")
+	writeFile(t, filepath.Join(dir, "node_modules", "left", "pad.go"), "package left\n\nfunc Pad() {}\n")
+	writeFile(t, filepath.Join(dir, ".hal", "standards", "api", "errors.md"), "# Errors\n")
+	writeFile(t, filepath.Join(dir, ".hal", "prd-billing-export.md"), "# PRD: Billing Export\n")
+	writeFile(t, filepath.Join(dir, ".hal", "prd.json"), `{"project":"Shop","description":"Billing export"}`)
+
+	pack := Build(dir, Options{})
+	if pack.Budget != DefaultBudget {
+		t.Errorf("Budget = %d, want %d", pack.Budget, DefaultBudget)
+	}
+
+	want := map[string][]string{
+		SectionStack:     {"Go module example.com/shop"},
+		SectionTree:      {"internal/ (0 files)", "  billing/ (2 files)"},
+		SectionSymbols:   {"internal/billing: Export(), Invoice"},
+		SectionReadme:    {"An online shop."},
+		SectionAgents:    {"make test"},
+		SectionStandards: {"errors"},
+		SectionCommits:   {"- Add billing export"},
+		SectionPRDs:      {"- prd-billing-export.md: PRD: Billing Export", "- prd.json: Shop — Billing export"},
+	}
+	for name, substrings := range want {
+		section := pack.Section(name)
+		if section == nil {
+			t.Errorf("missing section %q", name)
+			continue
+		}
+		for _, sub := range substrings {
+			if !strings.Contains(section.Content, sub) {
+				t.Errorf("section %q missing %q:\n%s", name, sub, section.Content)
+			}
+		}
+	}
+
+	rendered := pack.Render()
+	for _, absent := range []string{"node_modules", "Pad()", "TestOnly", "helper", "Total"} {
+		if strings.Contains(rendered, absent) {
+			t.Errorf("rendered pack should not contain %q:\n%s", absent, rendered)
+		}
+	}
+}
+
+func TestBuild_FocusRanksPackagesAndBudgetTruncates(t *testing.T) {
+	dir := t.TempDir()
+	stubGitLog(t, "", errors.New("not a git repository"))
+	writeFile(t, filepath.Join(dir, "big", "big.go"), "package big\n\nfunc A() {}\nfunc B() {}\nfunc C() {}\n")
+	writeFile(t, filepath.Join(dir, "payments", "payments.go"), "package payments\n\nfunc Charge() {}\n")
+	writeFile(t, filepath.Join(dir, "README.md"), strings.Repeat("lorem ipsum dolor\n", 200))
+
+	pack := Build(dir, Options{Budget: 2000, Focus: "Add refunds to payments"})
+	symbols := pack.Section(SectionSymbols)
+	if symbols == nil || !strings.HasPrefix(symbols.Content, "payments:") {
+		t.Fatalf("symbols = %+v, want focused package first", symbols)
+	}
+	readme := pack.Section(SectionReadme)
+	if readme == nil || !readme.Truncated || len(readme.Content) > 2000*12/100+len("\n… (truncated)") {
+		t.Fatalf("readme = %+v, want truncated to its share", readme)
+	}
+	if pack.Section(SectionCommits) != nil {
+		t.Error("commits section should be omitted when git log fails")
+	}
+}
+
+func TestRender_Empty(t *testing.T) {
+	stubGitLog(t, "", errors.New("no git"))
+	got := Build(t.TempDir(), Options{}).Render()
+	if got != "No codebase information available.\n" {
+		t.Errorf("Render = %q", got)
+	}
+}
@@ -0,0 +1,16 @@
+//go:build windows
+
+package runstatus
+
+import "os"
+
+// processAlive reports whether a process with pid exists. On Windows
+// FindProcess fails for processes that have exited.
+func processAlive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	p.Release()
+	return true
+}
@@ -0,0 +1,89 @@
+// Package runstatus defines .hal/run-status.json, the live state of a hal run
+// loop. The loop writes it; hal status, hal ctl and hal dashboard read it.
+package runstatus
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/jywlabs/hal/internal/template"
+)
+
+// Run status states written to .hal/run-status.json.
+const (
+	StateRunning  = "running"
+	StatePaused   = "paused" // hal ctl pause holds the loop
+	StateComplete = "complete"
+	StateStopped  = "stopped"
+	StateFailed   = "failed"
+)
+
+// StaleAfter is how long a running loop may go without refreshing
+// run-status.json before it is presumed dead.
+const StaleAfter = 5 * time.Minute
+
+// Status is the live state of a loop run. The loop rewrites it at every
+// iteration, and refreshes UpdatedAt in between, so hal dashboard can follow
+// a run from another terminal.
+type Status struct {
+	State         string    `json:"state"`
+	Engine        string    `json:"engine"`
+	PRDFile       string    `json:"prdFile"`
+	PID           int       `json:"pid,omitempty"`
+	TargetStoryID string    `json:"targetStoryId,omitempty"`
+	Iteration     int       `json:"iteration"`
+	MaxIterations int       `json:"maxIterations"`
+	StoryID       string    `json:"storyId,omitempty"`
+	StoryTitle    string    `json:"storyTitle,omitempty"`
+	Tokens        int       `json:"tokens"`
+	StartedAt     time.Time `json:"startedAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+	Error         string    `json:"error,omitempty"`
+}
+
+// Active reports whether the status records a loop that is running or paused.
+func (s *Status) Active() bool {
+	return s != nil && (s.State == StateRunning || s.State == StatePaused)
+}
+
+// StaleReason explains why a loop recorded as running or paused is presumed
+// dead: its process has exited or it stopped refreshing the status file. It
+// returns "" for a live loop and for finished runs.
+func (s *Status) StaleReason(now time.Time) string {
+	if !s.Active() {
+		return ""
+	}
+	if s.PID > 0 && !processAlive(s.PID) {
+		return "hal run (pid " + strconv.Itoa(s.PID) + ") is no longer running"
+	}
+	if !s.UpdatedAt.IsZero() && now.Sub(s.UpdatedAt) > StaleAfter {
+		return "hal run has not updated " + template.RunStatusFile + " since " + s.UpdatedAt.Format(time.RFC3339)
+	}
+	return ""
+}
+
+// Controllable reports whether a live loop in state can take hal ctl
+// requests. A loop whose process died is not controllable; see StaleReason.
+func (s *Status) Controllable() bool {
+	return s.Active() && s.StaleReason(time.Now()) == ""
+}
+
+// Load reads .hal/run-status.json. A missing file returns nil.
+func Load(dir string) (*Status, error) {
+	data, err := os.ReadFile(filepath.Join(dir, template.RunStatusFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", template.RunStatusFile, err)
+	}
+	var status Status
+	if err := json.Unmarshal(data, &status); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", template.RunStatusFile, err)
+	}
+	return &status, nil
+}
@@ -0,0 +1,63 @@
+package runstatus
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jywlabs/hal/internal/template"
+)
+
+func TestStaleReason(t *testing.T) {
+	exited := exec.Command("go", "version")
+	if err := exited.Run(); err != nil {
+		t.Skipf("cannot start a child process: %v", err)
+	}
+	now := time.Now()
+
+	tests := []struct {
+		name   string
+		status Status
+		want   string
+	}{
+		{name: "live", status: Status{State: StateRunning, PID: os.Getpid(), UpdatedAt: now}},
+		{name: "legacy without pid", status: Status{State: StatePaused, UpdatedAt: now}},
+		{name: "exited process", status: Status{State: StateRunning, PID: exited.Process.Pid, UpdatedAt: now}, want: "is no longer running"},
+		{name: "no heartbeat", status: Status{State: StateRunning, PID: os.Getpid(), UpdatedAt: now.Add(-StaleAfter - time.Minute)}, want: "has not updated"},
+		{name: "finished", status: Status{State: StateComplete, PID: exited.Process.Pid}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.status.StaleReason(now)
+			if (tt.want == "") != (got == "") || !strings.Contains(got, tt.want) {
+				t.Fatalf("StaleReason() = %q, want %q", got, tt.want)
+			}
+			if wantControllable := tt.want == "" && tt.status.State != StateComplete; tt.status.Controllable() != wantControllable {
+				t.Fatalf("Controllable() = %v, want %v", tt.status.Controllable(), wantControllable)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	if status, err := Load(dir); err != nil || status != nil {
+		t.Fatalf("Load() on missing file = %+v, %v; want nil, nil", status, err)
+	}
+	path := filepath.Join(dir, template.RunStatusFile)
+	if err := os.WriteFile(path, []byte(`{"state":"running","iteration":2}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if status, err := Load(dir); err != nil || status == nil || status.State != StateRunning || status.Iteration != 2 {
+		t.Fatalf("Load() = %+v, %v", status, err)
+	}
+	if err := os.WriteFile(path, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(dir); err == nil {
+		t.Fatal("Load() error = nil, want parse error")
+	}
+}
@@ -40,14 +40,34 @@ func globalConfigPath() (string, error) {
 
 // GlobalConfig stores sandbox provider settings in the global hal config dir.
 type GlobalConfig struct {
-	Provider          string                   `yaml:"provider"`
-	Defaults          GlobalDefaults           `yaml:"defaults"`
-	Env               map[string]string        `yaml:"env"`
-	TailscaleLockdown bool                     `yaml:"tailscaleLockdown"`
-	Daytona           DaytonaGlobalConfig      `yaml:"daytona"`
-	DigitalOcean      DigitalOceanGlobalConfig `yaml:"digitalocean"`
-	Hetzner           HetznerGlobalConfig      `yaml:"hetzner"`
-	Lightsail         LightsailGlobalConfig    `yaml:"lightsail"`
+	Provider          string                    `yaml:"provider"`
+	Defaults          GlobalDefaults            `yaml:"defaults"`
+	Env               map[string]string         `yaml:"env"`
+	TailscaleLockdown bool                      `yaml:"tailscaleLockdown"`
+	Daytona           DaytonaGlobalConfig       `yaml:"daytona"`
+	DigitalOcean      DigitalOceanGlobalConfig  `yaml:"digitalocean"`
+	Hetzner           HetznerGlobalConfig       `yaml:"hetzner"`
+	Lightsail         LightsailGlobalConfig     `yaml:"lightsail"`
+	Plugins           map[string]map[string]any `yaml:"plugins,omitempty"`
+	Pricing           PricingConfig             `yaml:"pricing"`
+	Budget            BudgetConfig              `yaml:"budget"`
+}
+
+// PricingConfig overrides the embedded hourly rates used for cost estimates.
+type PricingConfig struct {
+	// Rates maps provider → size → hourly USD. Entries replace or extend the
+	// embedded rates; Daytona and other unsized sandboxes use the "default" size.
+	Rates RateTable `yaml:"rates,omitempty"`
+}
+
+// BudgetConfig configures the monthly sandbox spend budget.
+type BudgetConfig struct {
+	// Monthly is the budget in USD; zero disables budget checks.
+	Monthly float64 `yaml:"monthly"`
+	// WarnPercent is the share of Monthly at which warnings start.
+	WarnPercent int `yaml:"warnPercent"`
+	// BlockCreate refuses sandbox create when the projection would exceed Monthly.
+	BlockCreate bool `yaml:"blockCreate"`
 }
 
 // GlobalDefaults contains default sandbox lifecycle settings.
@@ -94,6 +114,9 @@ type rawGlobalConfig struct {
 	DigitalOcean      rawDigitalOceanGlobalConfig `yaml:"digitalocean"`
 	Hetzner           rawHetznerGlobalConfig      `yaml:"hetzner"`
 	Lightsail         rawLightsailGlobalConfig    `yaml:"lightsail"`
+	Plugins           map[string]map[string]any   `yaml:"plugins"`
+	Pricing           rawPricingConfig            `yaml:"pricing"`
+	Budget            rawBudgetConfig             `yaml:"budget"`
 }
 
 type rawGlobalDefaults struct {
@@ -124,6 +147,16 @@ type rawLightsailGlobalConfig struct {
 	KeyPairName      *string `yaml:"keyPairName"`
 }
 
+type rawPricingConfig struct {
+	Rates RateTable `yaml:"rates"`
+}
+
+type rawBudgetConfig struct {
+	Monthly     *float64 `yaml:"monthly"`
+	WarnPercent *int     `yaml:"warnPercent"`
+	BlockCreate *bool    `yaml:"blockCreate"`
+}
+
 // DefaultGlobalConfig returns default sandbox global configuration.
 func DefaultGlobalConfig() GlobalConfig {
 	return GlobalConfig{
@@ -133,6 +166,9 @@ func DefaultGlobalConfig() GlobalConfig {
 			IdleHours:    48,
 		},
 		Env: map[string]string{},
+		Budget: BudgetConfig{
+			WarnPercent: 80,
+		},
 	}
 }
 
@@ -207,6 +243,39 @@ func LoadGlobalConfig() (*GlobalConfig, error) {
 	if raw.Lightsail.KeyPairName != nil {
 		cfg.Lightsail.KeyPairName = *raw.Lightsail.KeyPairName
 	}
+	if raw.Plugins != nil {
+		cfg.Plugins = make(map[string]map[string]any, len(raw.Plugins))
+		for name, settings := range raw.Plugins {
+			if IsBuiltinProvider(name) || !pluginNamePattern.MatchString(name) {
+				return nil, fmt.Errorf("parse global sandbox config: invalid provider plugin name %q", name)
+			}
+			if settings == nil {
+				settings = map[string]any{}
+			}
+			cfg.Plugins[name] = settings
+		}
+	}
+	if raw.Pricing.Rates != nil {
+		if err := raw.Pricing.Rates.Validate(); err != nil {
+			return nil, fmt.Errorf("parse global sandbox config: %w", err)
+		}
+		cfg.Pricing.Rates = raw.Pricing.Rates.clone()
+	}
+	if raw.Budget.Monthly != nil {
+		if *raw.Budget.Monthly < 0 {
+			return nil, fmt.Errorf("parse global sandbox config: budget.monthly must not be negative, got %v", *raw.Budget.Monthly)
+		}
+		cfg.Budget.Monthly = *raw.Budget.Monthly
+	}
+	if raw.Budget.WarnPercent != nil {
+		if *raw.Budget.WarnPercent < 0 || *raw.Budget.WarnPercent > 100 {
+			return nil, fmt.Errorf("parse global sandbox config: budget.warnPercent must be between 0 and 100, got %d", *raw.Budget.WarnPercent)
+		}
+		cfg.Budget.WarnPercent = *raw.Budget.WarnPercent
+	}
+	if raw.Budget.BlockCreate != nil {
+		cfg.Budget.BlockCreate = *raw.Budget.BlockCreate
+	}
 
 	return &cfg, nil
 }
@@ -271,6 +340,15 @@ func saveGlobalConfigFile(tmpPath, path string) error {
 	return nil
 }
 
+// Rates returns the effective hourly rate table: embedded rates with the
+// configured pricing overrides applied.
+func (c *GlobalConfig) Rates() RateTable {
+	if c == nil {
+		return DefaultRates()
+	}
+	return RatesWithOverrides(c.Pricing.Rates)
+}
+
 func copyStringMap(values map[string]string) map[string]string {
 	out := make(map[string]string, len(values))
 	for key, value := range values {
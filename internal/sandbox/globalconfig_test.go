@@ -185,6 +185,142 @@ func TestLoadGlobalConfig_InvalidYAML(t *testing.T) {
 	}
 }
 
+func TestLoadGlobalConfig_PricingAndBudget(t *testing.T) {
+	home := setGlobalConfigHome(t)
+	if err := EnsureGlobalDir(); err != nil {
+		t.Fatalf("EnsureGlobalDir() failed: %v", err)
+	}
+
+	configPath := filepath.Join(home, globalConfigFileName)
+	yaml := `pricing:
+  rates:
+    hetzner:
+      cx22: 0.01
+    daytona:
+      default: 0.1
+budget:
+  monthly: 50
+  blockCreate: true
+`
+	if err := os.WriteFile(configPath, []byte(yaml), 0o600); err != nil {
+		t.Fatalf("write global config: %v", err)
+	}
+
+	cfg, err := LoadGlobalConfig()
+	if err != nil {
+		t.Fatalf("LoadGlobalConfig() unexpected error: %v", err)
+	}
+	if cfg.Budget.Monthly != 50 || !cfg.Budget.BlockCreate {
+		t.Fatalf("Budget = %+v, want monthly 50 with blockCreate", cfg.Budget)
+	}
+	if cfg.Budget.WarnPercent != 80 {
+		t.Fatalf("Budget.WarnPercent = %d, want default 80", cfg.Budget.WarnPercent)
+	}
+	rates := cfg.Rates()
+	if rate, _ := rates.HourlyRate("hetzner", "cx22"); rate != 0.01 {
+		t.Fatalf("hetzner/cx22 rate = %v, want override 0.01", rate)
+	}
+	if rate, _ := rates.HourlyRate("hetzner", "cx32"); rate != 0.013 {
+		t.Fatalf("hetzner/cx32 rate = %v, want embedded 0.013", rate)
+	}
+	if rate, _ := rates.HourlyRate("daytona", ""); rate != 0.1 {
+		t.Fatalf("daytona rate = %v, want override 0.1", rate)
+	}
+}
+
+func TestLoadGlobalConfig_PluginSettings(t *testing.T) {
+	home := setGlobalConfigHome(t)
+	if err := EnsureGlobalDir(); err != nil {
+		t.Fatalf("EnsureGlobalDir() failed: %v", err)
+	}
+
+	yaml := `provider: vultr
+plugins:
+  vultr:
+    region: ewr
+    size: vc2-1c-2gb
+    tags: [hal, dev]
+  proxmox:
+`
+	if err := os.WriteFile(filepath.Join(home, globalConfigFileName), []byte(yaml), 0o600); err != nil {
+		t.Fatalf("write global config: %v", err)
+	}
+
+	cfg, err := LoadGlobalConfig()
+	if err != nil {
+		t.Fatalf("LoadGlobalConfig() unexpected error: %v", err)
+	}
+	if cfg.Provider != "vultr" || cfg.Plugins["vultr"]["region"] != "ewr" {
+		t.Fatalf("cfg = provider %q plugins %+v", cfg.Provider, cfg.Plugins)
+	}
+	if tags, ok := cfg.Plugins["vultr"]["tags"].([]any); !ok || len(tags) != 2 {
+		t.Fatalf("plugin settings should keep nested values, got %#v", cfg.Plugins["vultr"]["tags"])
+	}
+	if settings, ok := cfg.Plugins["proxmox"]; !ok || settings == nil {
+		t.Fatalf("empty plugin section should load as an empty map, got %#v", settings)
+	}
+
+	if err := SaveGlobalConfig(cfg); err != nil {
+		t.Fatalf("SaveGlobalConfig() failed: %v", err)
+	}
+	reloaded, err := LoadGlobalConfig()
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if reloaded.Plugins["vultr"]["size"] != "vc2-1c-2gb" {
+		t.Fatalf("plugin settings should round-trip, got %+v", reloaded.Plugins)
+	}
+}
+
+func TestLoadGlobalConfig_InvalidPricingAndBudget(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{
+			name:    "negative rate",
+			yaml:    "pricing:\n  rates:\n    hetzner:\n      cx22: -0.5\n",
+			wantErr: "must not be negative",
+		},
+		{
+			name:    "negative budget",
+			yaml:    "budget:\n  monthly: -10\n",
+			wantErr: "budget.monthly",
+		},
+		{
+			name:    "warn percent over 100",
+			yaml:    "budget:\n  warnPercent: 120\n",
+			wantErr: "budget.warnPercent",
+		},
+		{
+			name:    "plugin shadows built-in provider",
+			yaml:    "plugins:\n  hetzner:\n    region: fsn1\n",
+			wantErr: "invalid provider plugin name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := setGlobalConfigHome(t)
+			if err := EnsureGlobalDir(); err != nil {
+				t.Fatalf("EnsureGlobalDir() failed: %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(home, globalConfigFileName), []byte(tt.yaml), 0o600); err != nil {
+				t.Fatalf("write global config: %v", err)
+			}
+
+			_, err := LoadGlobalConfig()
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %q, want substring %q", err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestSaveGlobalConfig(t *testing.T) {
 	home := filepath.Join(t.TempDir(), "hal-global")
 	t.Setenv(halConfigHomeEnv, home)
@@ -0,0 +1,148 @@
+package sandbox
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// idleProbeScript prints one key=value activity signal per line. Every probe
+// degrades to an empty value when the tool or file is unavailable so a single
+// missing utility never fails the whole probe.
+const idleProbeScript = `now=$(date +%s)
+echo "now=$now"
+echo "uptime=$(cut -d. -f1 /proc/uptime 2>/dev/null)"
+echo "ssh_sessions=$(who 2>/dev/null | wc -l)"
+echo "tmux_sessions=$(tmux list-sessions 2>/dev/null | wc -l)"
+echo "tmux_activity=$(tmux list-sessions -F '#{session_activity}' 2>/dev/null | sort -n | tail -n 1)"
+echo "hal_processes=$(pgrep -x hal 2>/dev/null | wc -l)"
+echo "load1=$(cut -d' ' -f1 /proc/loadavg 2>/dev/null)"
+echo "tty_activity=$(stat -c %X /dev/pts/[0-9]* 2>/dev/null | sort -n | tail -n 1)"
+echo "login_activity=$(stat -c %Y /var/log/wtmp 2>/dev/null)"
+`
+
+// IdleProbeArgs returns the remote command that collects activity signals.
+// Callers run it through Provider.Exec and parse the output with
+// ParseActivityReport.
+func IdleProbeArgs() []string {
+	return []string{"sh", "-c", idleProbeScript}
+}
+
+// ActivityReport holds the activity signals collected from inside a sandbox.
+// Timestamps use the sandbox clock so idle durations are immune to skew
+// between the sandbox and the machine running hal.
+type ActivityReport struct {
+	Now          time.Time
+	BootedAt     time.Time
+	SSHSessions  int
+	TmuxSessions int
+	HalProcesses int
+	Load1        float64
+	LastActivity time.Time
+}
+
+// ParseActivityReport parses idle probe output. Unknown lines (e.g. SSH
+// banners) are ignored; a missing or malformed "now" value is an error.
+func ParseActivityReport(output string) (*ActivityReport, error) {
+	values := map[string]string{}
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok {
+			continue
+		}
+		values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+
+	nowUnix, err := strconv.ParseInt(values["now"], 10, 64)
+	if err != nil || nowUnix <= 0 {
+		return nil, fmt.Errorf("idle probe output missing sandbox clock")
+	}
+
+	report := &ActivityReport{
+		Now:          time.Unix(nowUnix, 0).UTC(),
+		SSHSessions:  parseProbeInt(values["ssh_sessions"]),
+		TmuxSessions: parseProbeInt(values["tmux_sessions"]),
+		HalProcesses: parseProbeInt(values["hal_processes"]),
+	}
+	if load, err := strconv.ParseFloat(values["load1"], 64); err == nil {
+		report.Load1 = load
+	}
+	if uptime := parseProbeInt(values["uptime"]); uptime > 0 {
+		report.BootedAt = report.Now.Add(-time.Duration(uptime) * time.Second)
+	}
+
+	// Boot counts as activity: a freshly started sandbox is never idle.
+	report.LastActivity = report.BootedAt
+	for _, key := range []string{"tmux_activity", "tty_activity", "login_activity"} {
+		ts := parseProbeInt(values[key])
+		if ts <= 0 {
+			continue
+		}
+		at := time.Unix(int64(ts), 0).UTC()
+		if at.After(report.LastActivity) && !at.After(report.Now) {
+			report.LastActivity = at
+		}
+	}
+
+	return report, nil
+}
+
+func parseProbeInt(value string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// DefaultIdleLoadThreshold is the 1-minute load average at or above which a
+// sandbox is considered busy regardless of session activity.
+const DefaultIdleLoadThreshold = 0.5
+
+// IdleDecision is the outcome of applying an idle policy to an ActivityReport.
+type IdleDecision struct {
+	Idle    bool
+	IdleFor time.Duration
+	Reason  string
+}
+
+// EvaluateIdle applies the idle policy: a sandbox is idle when it has no
+// logged-in sessions, no hal processes, load below loadThreshold, and no
+// recorded activity for at least idleHours.
+func EvaluateIdle(report *ActivityReport, idleHours int, loadThreshold float64) IdleDecision {
+	if report == nil {
+		return IdleDecision{Reason: "no activity report"}
+	}
+
+	decision := IdleDecision{}
+	if !report.LastActivity.IsZero() {
+		decision.IdleFor = report.Now.Sub(report.LastActivity)
+	}
+
+	switch {
+	case report.SSHSessions > 0:
+		decision.Reason = fmt.Sprintf("%d active login session(s)", report.SSHSessions)
+	case report.HalProcesses > 0:
+		decision.Reason = fmt.Sprintf("%d hal process(es) running", report.HalProcesses)
+	case report.Load1 >= loadThreshold:
+		decision.Reason = fmt.Sprintf("load average %.2f", report.Load1)
+	case report.LastActivity.IsZero():
+		decision.Reason = "last activity unknown"
+	case decision.IdleFor < time.Duration(idleHours)*time.Hour:
+		decision.Reason = fmt.Sprintf("idle %s of %dh", formatIdleDuration(decision.IdleFor), idleHours)
+	default:
+		decision.Idle = true
+		decision.Reason = fmt.Sprintf("idle %s (limit %dh)", formatIdleDuration(decision.IdleFor), idleHours)
+	}
+	return decision
+}
+
+func formatIdleDuration(d time.Duration) string {
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%.1fh", d.Hours())
+}
@@ -0,0 +1,147 @@
+package sandbox
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseActivityReport(t *testing.T) {
+	now := int64(1_800_000_000)
+	output := strings.Join([]string{
+		"Welcome to Ubuntu",
+		"now=1800000000",
+		"uptime=86400",
+		"ssh_sessions=0",
+		"tmux_sessions=2",
+		"tmux_activity=1799990000",
+		"hal_processes=",
+		"load1=0.12",
+		"tty_activity=1799900000",
+		"login_activity=1900000000", // future timestamps are ignored
+	}, "\n")
+
+	report, err := ParseActivityReport(output)
+	if err != nil {
+		t.Fatalf("ParseActivityReport: %v", err)
+	}
+	if !report.Now.Equal(time.Unix(now, 0)) {
+		t.Errorf("Now = %v, want %v", report.Now, time.Unix(now, 0))
+	}
+	if report.TmuxSessions != 2 || report.SSHSessions != 0 || report.HalProcesses != 0 {
+		t.Errorf("sessions = ssh %d tmux %d hal %d", report.SSHSessions, report.TmuxSessions, report.HalProcesses)
+	}
+	if report.Load1 != 0.12 {
+		t.Errorf("Load1 = %v, want 0.12", report.Load1)
+	}
+	if !report.LastActivity.Equal(time.Unix(1799990000, 0)) {
+		t.Errorf("LastActivity = %v, want tmux activity", report.LastActivity)
+	}
+}
+
+func TestParseActivityReport_BootIsActivity(t *testing.T) {
+	report, err := ParseActivityReport("now=1800000000\nuptime=600\n")
+	if err != nil {
+		t.Fatalf("ParseActivityReport: %v", err)
+	}
+	if want := time.Unix(1800000000-600, 0); !report.LastActivity.Equal(want) {
+		t.Errorf("LastActivity = %v, want boot time %v", report.LastActivity, want)
+	}
+}
+
+func TestParseActivityReport_MissingClock(t *testing.T) {
+	if _, err := ParseActivityReport("ssh_sessions=0\n"); err == nil {
+		t.Fatal("expected error when now is missing")
+	}
+}
+
+func TestEvaluateIdle(t *testing.T) {
+	now := time.Unix(1_800_000_000, 0).UTC()
+	quiet := func(idleFor time.Duration) *ActivityReport {
+		return &ActivityReport{Now: now, LastActivity: now.Add(-idleFor)}
+	}
+
+	tests := []struct {
+		name     string
+		report   *ActivityReport
+		wantIdle bool
+		wantText string
+	}{
+		{name: "idle past limit", report: quiet(5 * time.Hour), wantIdle: true, wantText: "limit 4h"},
+		{name: "recent activity", report: quiet(30 * time.Minute), wantText: "idle 30m of 4h"},
+		{name: "ssh session", report: &ActivityReport{Now: now, SSHSessions: 1, LastActivity: now.Add(-10 * time.Hour)}, wantText: "login session"},
+		{name: "hal running", report: &ActivityReport{Now: now, HalProcesses: 1, LastActivity: now.Add(-10 * time.Hour)}, wantText: "hal process"},
+		{name: "busy load", report: &ActivityReport{Now: now, Load1: 1.5, LastActivity: now.Add(-10 * time.Hour)}, wantText: "load average 1.50"},
+		{name: "unknown activity", report: &ActivityReport{Now: now}, wantText: "unknown"},
+		{name: "nil report", report: nil, wantText: "no activity report"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EvaluateIdle(tt.report, 4, DefaultIdleLoadThreshold)
+			if got.Idle != tt.wantIdle {
+				t.Errorf("Idle = %v, want %v (%s)", got.Idle, tt.wantIdle, got.Reason)
+			}
+			if !strings.Contains(got.Reason, tt.wantText) {
+				t.Errorf("Reason = %q, want to contain %q", got.Reason, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestAcquireReapLock(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HAL_CONFIG_HOME", dir)
+	now := time.Now()
+
+	release, err := AcquireReapLock()
+	if err != nil {
+		t.Fatalf("AcquireReapLock: %v", err)
+	}
+	if _, err := AcquireReapLock(); !errors.Is(err, ErrReapLocked) {
+		t.Fatalf("second AcquireReapLock error = %v, want ErrReapLocked", err)
+	}
+	release()
+
+	// A stale lock left by a crashed run is taken over.
+	lockPath := filepath.Join(dir, reapLockFileName)
+	if err := os.WriteFile(lockPath, []byte("1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	stale := now.Add(-2 * ReapStaleLockAge)
+	if err := os.Chtimes(lockPath, stale, stale); err != nil {
+		t.Fatal(err)
+	}
+	release, err = AcquireReapLock()
+	if err != nil {
+		t.Fatalf("AcquireReapLock over stale lock: %v", err)
+	}
+	release()
+	if _, err := os.Stat(lockPath); !os.IsNotExist(err) {
+		t.Fatalf("lock should be removed on release, stat err = %v", err)
+	}
+}
+
+func TestAppendReapLog(t *testing.T) {
+	t.Setenv("HAL_CONFIG_HOME", t.TempDir())
+	checkedAt := time.Date(2026, 4, 11, 0, 0, 0, 0, time.UTC)
+
+	for _, name := range []string{"a", "b"} {
+		entry := ReapLogEntry{Name: name, Provider: "hetzner", ReapRecord: ReapRecord{CheckedAt: checkedAt, Action: ReapActionStop}}
+		if err := AppendReapLog(entry); err != nil {
+			t.Fatalf("AppendReapLog: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(ReapLogPath())
+	if err != nil {
+		t.Fatalf("read reap log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[1], `"name":"b"`) || !strings.Contains(lines[1], `"action":"stop"`) {
+		t.Fatalf("unexpected reap log:\n%s", data)
+	}
+}
@@ -184,6 +184,9 @@ func expectedMigratedGlobalConfig() *GlobalConfig {
 			Bundle:           "small_3_0",
 			KeyPairName:      "ls-local-key",
 		},
+		Budget: BudgetConfig{
+			WarnPercent: 80,
+		},
 	}
 }
 
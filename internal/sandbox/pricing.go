@@ -1,39 +1,132 @@
 package sandbox
 
-import "time"
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
 
-// hourlyRates maps provider → size → hourly cost in USD.
-var hourlyRates = map[string]map[string]float64{
+// DefaultSizeKey is the rate table key used for sandboxes that were created
+// without an explicit size (e.g., Daytona, which sizes sandboxes server-side).
+const DefaultSizeKey = "default"
+
+// RateTable maps provider → size → hourly cost in USD.
+type RateTable map[string]map[string]float64
+
+// defaultHourlyRates holds the embedded list prices used when the global
+// sandbox config does not override them.
+var defaultHourlyRates = RateTable{
+	"daytona": {
+		// 1 vCPU / 1 GiB RAM / 3 GiB disk default sandbox.
+		DefaultSizeKey: 0.067,
+	},
 	"digitalocean": {
-		"s-1vcpu-2gb": 0.018,
-		"s-2vcpu-4gb": 0.036,
-		"s-4vcpu-8gb": 0.071,
+		"s-1vcpu-1gb":  0.009,
+		"s-1vcpu-2gb":  0.018,
+		"s-2vcpu-4gb":  0.036,
+		"s-4vcpu-8gb":  0.071,
+		"s-8vcpu-16gb": 0.143,
 	},
 	"hetzner": {
 		"cx22": 0.007,
 		"cx32": 0.013,
 		"cx42": 0.025,
+		"cx52": 0.049,
 	},
 	"lightsail": {
+		"micro_3_0":  0.008,
 		"small_3_0":  0.012,
 		"medium_3_0": 0.024,
 		"large_3_0":  0.047,
+		"xlarge_3_0": 0.094,
 	},
 }
 
+// DefaultRates returns a copy of the embedded hourly rate table.
+func DefaultRates() RateTable {
+	return defaultHourlyRates.clone()
+}
+
+// RatesWithOverrides returns the embedded rate table with overrides applied.
+// Override entries replace or extend individual provider/size rates; sizes
+// not mentioned in overrides keep their embedded rate.
+func RatesWithOverrides(overrides RateTable) RateTable {
+	rates := DefaultRates()
+	for provider, sizes := range overrides {
+		provider = strings.TrimSpace(provider)
+		if provider == "" {
+			continue
+		}
+		if rates[provider] == nil {
+			rates[provider] = make(map[string]float64, len(sizes))
+		}
+		for size, rate := range sizes {
+			rates[provider][strings.TrimSpace(size)] = rate
+		}
+	}
+	return rates
+}
+
+// HourlyRate returns the hourly rate for a provider/size pair. Empty sizes
+// resolve to DefaultSizeKey.
+func (r RateTable) HourlyRate(provider, size string) (float64, bool) {
+	providerRates, ok := r[strings.TrimSpace(provider)]
+	if !ok {
+		return 0, false
+	}
+	size = strings.TrimSpace(size)
+	if size == "" {
+		size = DefaultSizeKey
+	}
+	rate, ok := providerRates[size]
+	return rate, ok
+}
+
+// Validate reports the first negative or empty-keyed rate in the table.
+func (r RateTable) Validate() error {
+	providers := make([]string, 0, len(r))
+	for provider := range r {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+	for _, provider := range providers {
+		if strings.TrimSpace(provider) == "" {
+			return fmt.Errorf("pricing rate provider must not be empty")
+		}
+		for size, rate := range r[provider] {
+			if strings.TrimSpace(size) == "" {
+				return fmt.Errorf("pricing rate size for provider %q must not be empty", provider)
+			}
+			if rate < 0 {
+				return fmt.Errorf("pricing rate for %s/%s must not be negative, got %v", provider, size, rate)
+			}
+		}
+	}
+	return nil
+}
+
+func (r RateTable) clone() RateTable {
+	out := make(RateTable, len(r))
+	for provider, sizes := range r {
+		copied := make(map[string]float64, len(sizes))
+		for size, rate := range sizes {
+			copied[size] = rate
+		}
+		out[provider] = copied
+	}
+	return out
+}
+
 // EstimatedCost returns the estimated cost in USD for a sandbox instance
-// based on hours since creation multiplied by the hourly rate.
+// based on hours since creation multiplied by the receiver's hourly rate.
 // Returns -1 if the provider or size is unknown.
 // Cost always accrues from CreatedAt (stopped sandboxes still charge).
-func EstimatedCost(instance *SandboxState, now func() time.Time) float64 {
+func (r RateTable) EstimatedCost(instance *SandboxState, now func() time.Time) float64 {
 	if instance == nil || instance.CreatedAt.IsZero() {
 		return -1
 	}
-	providerRates, ok := hourlyRates[instance.Provider]
-	if !ok {
-		return -1
-	}
-	rate, ok := providerRates[instance.Size]
+	rate, ok := r.HourlyRate(instance.Provider, instance.Size)
 	if !ok {
 		return -1
 	}
@@ -43,3 +136,102 @@ func EstimatedCost(instance *SandboxState, now func() time.Time) float64 {
 	}
 	return hours * rate
 }
+
+// CostEstimate breaks a sandbox's spend down over its lifetime and the
+// current calendar month.
+type CostEstimate struct {
+	Known            bool
+	HourlyRate       float64
+	Accrued          float64
+	MonthToDate      float64
+	ProjectedMonthly float64
+}
+
+// Estimate computes accrued, month-to-date, and projected monthly spend for
+// an instance. The projection assumes the sandbox keeps existing until the
+// end of the month containing now; months follow now's location.
+func (r RateTable) Estimate(instance *SandboxState, now time.Time) CostEstimate {
+	if instance == nil || instance.CreatedAt.IsZero() {
+		return CostEstimate{}
+	}
+	rate, ok := r.HourlyRate(instance.Provider, instance.Size)
+	if !ok {
+		return CostEstimate{}
+	}
+
+	monthStart, monthEnd := MonthBounds(now)
+	billedFrom := instance.CreatedAt
+	if billedFrom.Before(monthStart) {
+		billedFrom = monthStart
+	}
+
+	return CostEstimate{
+		Known:            true,
+		HourlyRate:       rate,
+		Accrued:          rate * hoursBetween(instance.CreatedAt, now),
+		MonthToDate:      rate * hoursBetween(billedFrom, now),
+		ProjectedMonthly: rate * hoursBetween(billedFrom, monthEnd),
+	}
+}
+
+// RemainingMonthCost returns what a sandbox billed at rate would cost if it
+// were created at now and kept until the end of the month.
+func RemainingMonthCost(rate float64, now time.Time) float64 {
+	_, monthEnd := MonthBounds(now)
+	return rate * hoursBetween(now, monthEnd)
+}
+
+// MonthBounds returns the start of the month containing t and the start of
+// the following month, both in t's location.
+func MonthBounds(t time.Time) (time.Time, time.Time) {
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	return start, start.AddDate(0, 1, 0)
+}
+
+func hoursBetween(from, to time.Time) float64 {
+	hours := to.Sub(from).Hours()
+	if hours < 0 {
+		return 0
+	}
+	return hours
+}
+
+// Budget levels reported by EvaluateBudget.
+const (
+	BudgetDisabled = "disabled"
+	BudgetOK       = "ok"
+	BudgetWarning  = "warning"
+	BudgetExceeded = "exceeded"
+)
+
+// BudgetStatus describes projected monthly spend relative to a budget.
+type BudgetStatus struct {
+	Level     string
+	Monthly   float64
+	Projected float64
+	Threshold float64
+}
+
+// EvaluateBudget compares projected monthly spend against the configured
+// budget. A zero monthly budget disables budget checks.
+func EvaluateBudget(budget BudgetConfig, projected float64) BudgetStatus {
+	status := BudgetStatus{
+		Level:     BudgetDisabled,
+		Monthly:   budget.Monthly,
+		Projected: projected,
+	}
+	if budget.Monthly <= 0 {
+		return status
+	}
+
+	status.Threshold = budget.Monthly * float64(budget.WarnPercent) / 100
+	switch {
+	case projected > budget.Monthly:
+		status.Level = BudgetExceeded
+	case budget.WarnPercent > 0 && projected >= status.Threshold:
+		status.Level = BudgetWarning
+	default:
+		status.Level = BudgetOK
+	}
+	return status
+}
@@ -126,17 +126,17 @@ func TestEstimatedCost(t *testing.T) {
 			want: -1,
 		},
 		{
-			name: "daytona provider returns -1",
+			name: "daytona provider uses default size rate",
 			instance: &SandboxState{
 				Provider:  "daytona",
 				Size:      "",
 				CreatedAt: baseTime,
 			},
 			now:  func() time.Time { return baseTime.Add(10 * time.Hour) },
-			want: -1,
+			want: 10 * 0.067,
 		},
 		{
-			name: "empty size returns -1",
+			name: "empty size without default rate returns -1",
 			instance: &SandboxState{
 				Provider:  "hetzner",
 				Size:      "",
@@ -170,7 +170,7 @@ func TestEstimatedCost(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := EstimatedCost(tt.instance, tt.now)
+			got := DefaultRates().EstimatedCost(tt.instance, tt.now)
 			if tt.want == -1 {
 				if got != -1 {
 					t.Errorf("EstimatedCost() = %v, want -1", got)
@@ -185,17 +185,18 @@ func TestEstimatedCost(t *testing.T) {
 }
 
 func TestEstimatedCost_AllProvidersAndSizes(t *testing.T) {
-	// Verify all expected provider/size combinations are present in hourlyRates.
+	// Verify all expected provider/size combinations are present in defaultHourlyRates.
 	expected := map[string][]string{
-		"digitalocean": {"s-1vcpu-2gb", "s-2vcpu-4gb", "s-4vcpu-8gb"},
-		"hetzner":      {"cx22", "cx32", "cx42"},
-		"lightsail":    {"small_3_0", "medium_3_0", "large_3_0"},
+		"daytona":      {DefaultSizeKey},
+		"digitalocean": {"s-1vcpu-1gb", "s-1vcpu-2gb", "s-2vcpu-4gb", "s-4vcpu-8gb", "s-8vcpu-16gb"},
+		"hetzner":      {"cx22", "cx32", "cx42", "cx52"},
+		"lightsail":    {"micro_3_0", "small_3_0", "medium_3_0", "large_3_0", "xlarge_3_0"},
 	}
 
 	for provider, sizes := range expected {
-		providerRates, ok := hourlyRates[provider]
+		providerRates, ok := defaultHourlyRates[provider]
 		if !ok {
-			t.Errorf("missing provider %q in hourlyRates", provider)
+			t.Errorf("missing provider %q in defaultHourlyRates", provider)
 			continue
 		}
 		if len(providerRates) != len(sizes) {
@@ -213,13 +214,13 @@ func TestEstimatedCost_AllProvidersAndSizes(t *testing.T) {
 		}
 	}
 
-	if len(hourlyRates) != len(expected) {
-		t.Errorf("hourlyRates has %d providers, want %d", len(hourlyRates), len(expected))
+	if len(defaultHourlyRates) != len(expected) {
+		t.Errorf("defaultHourlyRates has %d providers, want %d", len(defaultHourlyRates), len(expected))
 	}
 }
 
 func TestEstimatedCost_RatesArePositive(t *testing.T) {
-	for provider, sizes := range hourlyRates {
+	for provider, sizes := range defaultHourlyRates {
 		for size, rate := range sizes {
 			if rate <= 0 {
 				t.Errorf("rate for %s/%s must be positive, got %v", provider, size, rate)
@@ -227,3 +228,129 @@ func TestEstimatedCost_RatesArePositive(t *testing.T) {
 		}
 	}
 }
+
+func TestRatesWithOverrides(t *testing.T) {
+	rates := RatesWithOverrides(RateTable{
+		"hetzner": {"cx22": 0.01, "cpx11": 0.006},
+		"custom":  {"big": 1.5},
+	})
+
+	tests := []struct {
+		provider string
+		size     string
+		want     float64
+		wantOK   bool
+	}{
+		{provider: "hetzner", size: "cx22", want: 0.01, wantOK: true},
+		{provider: "hetzner", size: "cpx11", want: 0.006, wantOK: true},
+		{provider: "hetzner", size: "cx32", want: 0.013, wantOK: true},
+		{provider: "custom", size: "big", want: 1.5, wantOK: true},
+		{provider: "daytona", size: "", want: 0.067, wantOK: true},
+		{provider: "custom", size: "small", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.provider+"/"+tt.size, func(t *testing.T) {
+			got, ok := rates.HourlyRate(tt.provider, tt.size)
+			if ok != tt.wantOK {
+				t.Fatalf("HourlyRate() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if math.Abs(got-tt.want) > 0.0001 {
+				t.Errorf("HourlyRate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if rate, _ := DefaultRates().HourlyRate("hetzner", "cx22"); rate != 0.007 {
+		t.Errorf("overrides must not mutate embedded rates, got cx22 = %v", rate)
+	}
+}
+
+func TestRateTableValidate(t *testing.T) {
+	if err := (RateTable{"hetzner": {"cx22": 0}}).Validate(); err != nil {
+		t.Fatalf("zero rate should be valid: %v", err)
+	}
+	if err := (RateTable{"hetzner": {"cx22": -1}}).Validate(); err == nil {
+		t.Fatal("expected error for negative rate")
+	}
+	if err := (RateTable{"hetzner": {" ": 1}}).Validate(); err == nil {
+		t.Fatal("expected error for empty size")
+	}
+}
+
+func TestRateTableEstimate(t *testing.T) {
+	rates := RateTable{"hetzner": {"cx22": 1}}
+	now := time.Date(2026, 4, 11, 0, 0, 0, 0, time.UTC) // 10 days into a 30-day month
+
+	tests := []struct {
+		name      string
+		createdAt time.Time
+		wantAcc   float64
+		wantMTD   float64
+		wantProj  float64
+	}{
+		{
+			name:      "created this month",
+			createdAt: time.Date(2026, 4, 6, 0, 0, 0, 0, time.UTC),
+			wantAcc:   5 * 24,
+			wantMTD:   5 * 24,
+			wantProj:  25 * 24,
+		},
+		{
+			name:      "created last month",
+			createdAt: time.Date(2026, 3, 30, 0, 0, 0, 0, time.UTC),
+			wantAcc:   12 * 24,
+			wantMTD:   10 * 24,
+			wantProj:  30 * 24,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rates.Estimate(&SandboxState{Provider: "hetzner", Size: "cx22", CreatedAt: tt.createdAt}, now)
+			if !got.Known {
+				t.Fatal("Estimate().Known = false, want true")
+			}
+			if math.Abs(got.Accrued-tt.wantAcc) > 0.0001 {
+				t.Errorf("Accrued = %v, want %v", got.Accrued, tt.wantAcc)
+			}
+			if math.Abs(got.MonthToDate-tt.wantMTD) > 0.0001 {
+				t.Errorf("MonthToDate = %v, want %v", got.MonthToDate, tt.wantMTD)
+			}
+			if math.Abs(got.ProjectedMonthly-tt.wantProj) > 0.0001 {
+				t.Errorf("ProjectedMonthly = %v, want %v", got.ProjectedMonthly, tt.wantProj)
+			}
+		})
+	}
+
+	if got := rates.Estimate(&SandboxState{Provider: "aws", CreatedAt: now}, now); got.Known {
+		t.Errorf("Estimate() for unknown provider should be unknown, got %+v", got)
+	}
+	if got := RemainingMonthCost(1, now); math.Abs(got-20*24) > 0.0001 {
+		t.Errorf("RemainingMonthCost() = %v, want %v", got, 20*24)
+	}
+}
+
+func TestEvaluateBudget(t *testing.T) {
+	tests := []struct {
+		name      string
+		budget    BudgetConfig
+		projected float64
+		want      string
+	}{
+		{name: "zero budget disables checks", budget: BudgetConfig{}, projected: 1000, want: BudgetDisabled},
+		{name: "under threshold", budget: BudgetConfig{Monthly: 100, WarnPercent: 80}, projected: 50, want: BudgetOK},
+		{name: "at threshold warns", budget: BudgetConfig{Monthly: 100, WarnPercent: 80}, projected: 80, want: BudgetWarning},
+		{name: "at budget warns", budget: BudgetConfig{Monthly: 100, WarnPercent: 80}, projected: 100, want: BudgetWarning},
+		{name: "over budget exceeds", budget: BudgetConfig{Monthly: 100, WarnPercent: 80}, projected: 100.01, want: BudgetExceeded},
+		{name: "zero warn percent only reports exceeded", budget: BudgetConfig{Monthly: 100}, projected: 99, want: BudgetOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EvaluateBudget(tt.budget, tt.projected); got.Level != tt.want {
+				t.Errorf("EvaluateBudget().Level = %q, want %q", got.Level, tt.want)
+			}
+		})
+	}
+}
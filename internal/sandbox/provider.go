@@ -159,7 +159,8 @@ func RunCmd(cmd *exec.Cmd, out io.Writer) error {
 }
 
 // ProviderFromConfig returns the Provider implementation matching the given
-// provider name. Known providers: "daytona", "hetzner", "digitalocean", "lightsail".
+// provider name. Built-in providers: "daytona", "hetzner", "digitalocean",
+// "lightsail". Any other name resolves to a hal-provider-<name> plugin on PATH.
 func ProviderFromConfig(provider string, cfg ProviderConfig) (Provider, error) {
 	switch provider {
 	case "daytona":
@@ -189,7 +190,16 @@ func ProviderFromConfig(provider string, cfg ProviderConfig) (Provider, error) {
 			TailscaleLockdown: cfg.TailscaleLockdown,
 		}, nil
 	default:
-		return nil, fmt.Errorf("unknown sandbox provider: %q (supported: daytona, hetzner, digitalocean, lightsail)", provider)
+		plugin, err := LookupPlugin(provider)
+		if err != nil {
+			return nil, fmt.Errorf("unknown sandbox provider: %q (supported: daytona, hetzner, digitalocean, lightsail, or a %s<name> plugin on PATH)", provider, PluginExecutablePrefix)
+		}
+		return &PluginProvider{
+			Name:              plugin.Name,
+			Path:              plugin.Path,
+			Config:            cfg.Plugins[plugin.Name],
+			TailscaleLockdown: cfg.TailscaleLockdown,
+		}, nil
 	}
 }
 
@@ -208,4 +218,7 @@ type ProviderConfig struct {
 	LightsailBundle           string
 	LightsailKeyPairName      string
 	TailscaleLockdown         bool
+	// Plugins holds provider plugin settings keyed by plugin name; each map
+	// is passed through verbatim as the plugin request config.
+	Plugins map[string]map[string]any
 }
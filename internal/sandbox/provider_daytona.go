@@ -3,6 +3,7 @@ package sandbox
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -322,3 +323,51 @@ func (d *DaytonaProvider) Status(ctx context.Context, info *ConnectInfo, out io.
 	}
 	return nil
 }
+
+// daytonaSandbox is the subset of `daytona list --format json` used for
+// reconcile. Daytona sandboxes have no public IP or size.
+type daytonaSandbox struct {
+	ID        string            `json:"id"`
+	Name      string            `json:"name"`
+	State     string            `json:"state"`
+	Snapshot  string            `json:"snapshot"`
+	Labels    map[string]string `json:"labels"`
+	CreatedAt remoteTime        `json:"createdAt"`
+}
+
+// List returns every sandbox visible to the configured API key. Sandboxes
+// built from the hal template snapshot count as hal-managed.
+func (d *DaytonaProvider) List(ctx context.Context) ([]RemoteInstance, error) {
+	if err := d.validateCredentials(); err != nil {
+		return nil, err
+	}
+	cmd := d.commandContext(ctx, "daytona", "list", "--format", "json")
+	d.applyCredentials(cmd)
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, wrapDaytonaError("list", err, stderr.String())
+	}
+
+	var sandboxes []daytonaSandbox
+	if err := json.Unmarshal(stdout.Bytes(), &sandboxes); err != nil {
+		return nil, fmt.Errorf("parse daytona list: %w", err)
+	}
+	instances := make([]RemoteInstance, 0, len(sandboxes))
+	for _, s := range sandboxes {
+		name := s.Name
+		if name == "" {
+			name = s.ID
+		}
+		instances = append(instances, RemoteInstance{
+			ID:        s.ID,
+			Name:      name,
+			Status:    normalizeRemoteStatus(s.State, []string{"started", "running"}, []string{"stopped"}),
+			Managed:   s.Snapshot == templateSnapshotName || s.Labels[ManagedLabelKey] == ManagedLabelValue,
+			CreatedAt: s.CreatedAt.Time,
+		})
+	}
+	return instances, nil
+}
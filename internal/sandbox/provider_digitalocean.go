@@ -4,11 +4,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -242,6 +244,7 @@ func buildDOCreateArgs(name, size, sshKey, userDataFile string) []string {
 		"--image", "ubuntu-24-04-x64",
 		"--ssh-keys", sshKey,
 		"--user-data-file", userDataFile,
+		"--tag-name", ManagedLabelValue,
 		"--wait",
 	}
 }
@@ -694,3 +697,64 @@ func (d *DigitalOceanProvider) Exec(info *ConnectInfo, args []string) (*exec.Cmd
 	cmd.Stderr = os.Stderr
 	return cmd, nil
 }
+
+// doctlDroplet is the subset of `doctl compute droplet list --output json`
+// used for reconcile.
+type doctlDroplet struct {
+	ID        int64      `json:"id"`
+	Name      string     `json:"name"`
+	Status    string     `json:"status"`
+	SizeSlug  string     `json:"size_slug"`
+	Tags      []string   `json:"tags"`
+	CreatedAt remoteTime `json:"created_at"`
+	Networks  struct {
+		V4 []struct {
+			IPAddress string `json:"ip_address"`
+			Type      string `json:"type"`
+		} `json:"v4"`
+	} `json:"networks"`
+}
+
+// List returns every droplet in the doctl account context.
+func (d *DigitalOceanProvider) List(ctx context.Context) ([]RemoteInstance, error) {
+	if err := d.ensureDoctl(); err != nil {
+		return nil, err
+	}
+
+	cmd := d.commandContext(ctx, "doctl", "compute", "droplet", "list", "--output", "json")
+	var stdoutBuf bytes.Buffer
+	var stderrBuf bytes.Buffer
+	cmd.Stdout = &stdoutBuf
+	cmd.Stderr = &stderrBuf
+	if err := cmd.Run(); err != nil {
+		return nil, wrapDoctlError("compute droplet list", err, stderrBuf.String())
+	}
+
+	var droplets []doctlDroplet
+	if err := json.Unmarshal(stdoutBuf.Bytes(), &droplets); err != nil {
+		return nil, fmt.Errorf("parse doctl droplet list: %w", err)
+	}
+	instances := make([]RemoteInstance, 0, len(droplets))
+	for _, droplet := range droplets {
+		inst := RemoteInstance{
+			ID:        strconv.FormatInt(droplet.ID, 10),
+			Name:      droplet.Name,
+			Status:    normalizeRemoteStatus(droplet.Status, []string{"active"}, []string{"off"}),
+			Size:      droplet.SizeSlug,
+			CreatedAt: droplet.CreatedAt.Time,
+		}
+		for _, network := range droplet.Networks.V4 {
+			if network.Type == "public" {
+				inst.IP = network.IPAddress
+				break
+			}
+		}
+		for _, tag := range droplet.Tags {
+			if tag == ManagedLabelValue {
+				inst.Managed = true
+			}
+		}
+		instances = append(instances, inst)
+	}
+	return instances, nil
+}
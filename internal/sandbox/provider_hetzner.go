@@ -4,11 +4,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -149,6 +151,7 @@ func (h *HetznerProvider) Create(ctx context.Context, name string, env map[strin
 		"--image", h.Image,
 		"--ssh-key", h.SSHKey,
 		"--user-data-file", tmpFile.Name(),
+		"--label", ManagedLabelKey+"="+ManagedLabelValue,
 	)
 	var createOut bytes.Buffer
 	var createErr bytes.Buffer
@@ -352,3 +355,50 @@ func (h *HetznerProvider) Status(ctx context.Context, info *ConnectInfo, out io.
 	}
 	return nil
 }
+
+// hcloudServer is the subset of `hcloud server list -o json` used for reconcile.
+type hcloudServer struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	PublicNet struct {
+		IPv4 struct {
+			IP string `json:"ip"`
+		} `json:"ipv4"`
+	} `json:"public_net"`
+	ServerType struct {
+		Name string `json:"name"`
+	} `json:"server_type"`
+	Labels  map[string]string `json:"labels"`
+	Created remoteTime        `json:"created"`
+}
+
+// List returns every server in the hcloud project.
+func (h *HetznerProvider) List(ctx context.Context) ([]RemoteInstance, error) {
+	cmd := h.commandContext(ctx, "hcloud", "server", "list", "-o", "json")
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, wrapHcloudError("server list", err, "", stderr.String())
+	}
+
+	var servers []hcloudServer
+	if err := json.Unmarshal(stdout.Bytes(), &servers); err != nil {
+		return nil, fmt.Errorf("parse hcloud server list: %w", err)
+	}
+	instances := make([]RemoteInstance, 0, len(servers))
+	for _, s := range servers {
+		instances = append(instances, RemoteInstance{
+			ID:        strconv.FormatInt(s.ID, 10),
+			Name:      s.Name,
+			IP:        s.PublicNet.IPv4.IP,
+			Status:    normalizeRemoteStatus(s.Status, []string{"running"}, []string{"off"}),
+			Size:      s.ServerType.Name,
+			Managed:   s.Labels[ManagedLabelKey] == ManagedLabelValue,
+			CreatedAt: s.Created.Time,
+		})
+	}
+	return instances, nil
+}
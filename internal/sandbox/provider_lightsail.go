@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -129,6 +130,7 @@ func buildLightsailCreateArgs(name, az, bundle, keyPair, userDataFilePath string
 		"--bundle-id", bundle,
 		"--key-pair-name", keyPair,
 		"--user-data", "file://" + userDataFilePath,
+		"--tags", "key=" + ManagedLabelKey + ",value=" + ManagedLabelValue,
 	}
 }
 
@@ -469,3 +471,61 @@ func (l *LightsailProvider) Exec(info *ConnectInfo, args []string) (*exec.Cmd, e
 	cmd.Stderr = os.Stderr
 	return cmd, nil
 }
+
+// lightsailInstances is the subset of `aws lightsail get-instances` used for
+// reconcile.
+type lightsailInstances struct {
+	Instances []struct {
+		Name            string     `json:"name"`
+		PublicIPAddress string     `json:"publicIpAddress"`
+		BundleID        string     `json:"bundleId"`
+		CreatedAt       remoteTime `json:"createdAt"`
+		State           struct {
+			Name string `json:"name"`
+		} `json:"state"`
+		Tags []struct {
+			Key   string `json:"key"`
+			Value string `json:"value"`
+		} `json:"tags"`
+	} `json:"instances"`
+}
+
+// List returns every Lightsail instance in the CLI's default region.
+// Lightsail instances are identified by name, so ID mirrors Name.
+func (l *LightsailProvider) List(ctx context.Context) ([]RemoteInstance, error) {
+	if err := l.ensureAWS(); err != nil {
+		return nil, err
+	}
+
+	cmd := l.commandContext(ctx, "aws", "lightsail", "get-instances", "--output", "json")
+	var stdoutBuf bytes.Buffer
+	var stderrBuf bytes.Buffer
+	cmd.Stdout = &stdoutBuf
+	cmd.Stderr = &stderrBuf
+	if err := cmd.Run(); err != nil {
+		return nil, wrapAWSError("get-instances", err, stderrBuf.String())
+	}
+
+	var parsed lightsailInstances
+	if err := json.Unmarshal(stdoutBuf.Bytes(), &parsed); err != nil {
+		return nil, fmt.Errorf("parse aws lightsail get-instances: %w", err)
+	}
+	instances := make([]RemoteInstance, 0, len(parsed.Instances))
+	for _, li := range parsed.Instances {
+		inst := RemoteInstance{
+			ID:        li.Name,
+			Name:      li.Name,
+			IP:        li.PublicIPAddress,
+			Status:    normalizeRemoteStatus(li.State.Name, []string{"running"}, []string{"stopped"}),
+			Size:      li.BundleID,
+			CreatedAt: li.CreatedAt.Time,
+		}
+		for _, tag := range li.Tags {
+			if tag.Key == ManagedLabelKey && tag.Value == ManagedLabelValue {
+				inst.Managed = true
+			}
+		}
+		instances = append(instances, inst)
+	}
+	return instances, nil
+}
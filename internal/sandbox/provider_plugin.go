@@ -0,0 +1,415 @@
+package sandbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// PluginProtocolVersion is the JSON-over-stdio protocol spoken with external
+// provider plugins. Plugins must echo it in every response.
+const PluginProtocolVersion = "hal-provider-v1"
+
+// PluginExecutablePrefix is the executable name prefix hal searches PATH for.
+// A plugin for provider "vultr" is the executable "hal-provider-vultr".
+const PluginExecutablePrefix = "hal-provider-"
+
+// Plugin protocol methods.
+const (
+	PluginMethodDescribe = "describe"
+	PluginMethodCreate   = "create"
+	PluginMethodStart    = "start"
+	PluginMethodStop     = "stop"
+	PluginMethodDelete   = "delete"
+	PluginMethodStatus   = "status"
+	PluginMethodSSH      = "ssh"
+	PluginMethodExec     = "exec"
+	PluginMethodList     = "list"
+)
+
+var builtinProviders = []string{"daytona", "hetzner", "digitalocean", "lightsail"}
+
+var pluginNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+var (
+	// pluginLookPath resolves plugin executables. Override in tests.
+	pluginLookPath = exec.LookPath
+	// pluginPathEnv returns the PATH searched by DiscoverPlugins.
+	pluginPathEnv = func() string { return os.Getenv("PATH") }
+)
+
+// BuiltinProviders returns the names of providers compiled into hal.
+func BuiltinProviders() []string {
+	return append([]string(nil), builtinProviders...)
+}
+
+// IsBuiltinProvider reports whether name is a provider compiled into hal.
+func IsBuiltinProvider(name string) bool {
+	for _, builtin := range builtinProviders {
+		if name == builtin {
+			return true
+		}
+	}
+	return false
+}
+
+// PluginInfo describes a provider plugin executable found on PATH.
+type PluginInfo struct {
+	Name string
+	Path string
+}
+
+// LookupPlugin resolves the hal-provider-<name> executable for a provider.
+func LookupPlugin(name string) (PluginInfo, error) {
+	if !pluginNamePattern.MatchString(name) || IsBuiltinProvider(name) {
+		return PluginInfo{}, fmt.Errorf("invalid provider plugin name %q", name)
+	}
+	path, err := pluginLookPath(PluginExecutablePrefix + name)
+	if err != nil {
+		return PluginInfo{}, fmt.Errorf("provider plugin %s%s not found on PATH: %w", PluginExecutablePrefix, name, err)
+	}
+	return PluginInfo{Name: name, Path: path}, nil
+}
+
+// DiscoverPlugins lists hal-provider-* executables on PATH, sorted by name.
+// Earlier PATH entries shadow later ones, matching shell lookup.
+func DiscoverPlugins() []PluginInfo {
+	seen := map[string]bool{}
+	var plugins []PluginInfo
+	for _, dir := range filepath.SplitList(pluginPathEnv()) {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := strings.CutPrefix(entry.Name(), PluginExecutablePrefix)
+			if !ok || entry.IsDir() {
+				continue
+			}
+			name = strings.TrimSuffix(name, filepath.Ext(name))
+			if seen[name] || !pluginNamePattern.MatchString(name) || IsBuiltinProvider(name) {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			if !isExecutableFile(path) {
+				continue
+			}
+			seen[name] = true
+			plugins = append(plugins, PluginInfo{Name: name, Path: path})
+		}
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	return plugins
+}
+
+func isExecutableFile(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	if filepath.Ext(path) == ".exe" {
+		return true
+	}
+	return info.Mode().Perm()&0o111 != 0
+}
+
+// PluginRequest is the JSON document written to a plugin's stdin.
+type PluginRequest struct {
+	Protocol          string            `json:"protocol"`
+	Method            string            `json:"method"`
+	Config            map[string]any    `json:"config"`
+	TailscaleLockdown bool              `json:"tailscaleLockdown,omitempty"`
+	Name              string            `json:"name,omitempty"`
+	Env               map[string]string `json:"env,omitempty"`
+	Target            *PluginTarget     `json:"target,omitempty"`
+	Args              []string          `json:"args,omitempty"`
+}
+
+// PluginTarget identifies an existing sandbox in a plugin request.
+type PluginTarget struct {
+	Name              string `json:"name"`
+	ID                string `json:"id,omitempty"`
+	IP                string `json:"ip,omitempty"`
+	PublicIP          string `json:"publicIp,omitempty"`
+	TailscaleIP       string `json:"tailscaleIp,omitempty"`
+	TailscaleHostname string `json:"tailscaleHostname,omitempty"`
+	TailscaleLockdown bool   `json:"tailscaleLockdown,omitempty"`
+}
+
+// PluginResponse is the JSON document a plugin writes to stdout. Progress
+// and diagnostics go to stderr, which hal streams to the user.
+type PluginResponse struct {
+	Protocol string          `json:"protocol"`
+	Error    string          `json:"error,omitempty"`
+	Result   json.RawMessage `json:"result,omitempty"`
+}
+
+// PluginDescribeResult is the result of the describe method.
+type PluginDescribeResult struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Sizes       []string `json:"sizes,omitempty"`
+}
+
+// PluginCreateResult is the result of the create method.
+type PluginCreateResult struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	IP          string `json:"ip"`
+	TailscaleIP string `json:"tailscaleIp,omitempty"`
+}
+
+// PluginStatusResult is the result of the start and status methods.
+type PluginStatusResult struct {
+	Status string `json:"status"`
+	IP     string `json:"ip,omitempty"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// PluginCommandResult is the result of the ssh and exec methods: the local
+// command hal should run to reach the sandbox.
+type PluginCommandResult struct {
+	Argv []string          `json:"argv"`
+	Env  map[string]string `json:"env,omitempty"`
+}
+
+// PluginListResult is the result of the optional list method. Plugins mark
+// machines they created for hal with managed: true.
+type PluginListResult struct {
+	Instances []RemoteInstance `json:"instances"`
+}
+
+// PluginProvider implements Provider by exchanging JSON documents with an
+// external hal-provider-<name> executable.
+type PluginProvider struct {
+	Name              string
+	Path              string
+	Config            map[string]any
+	TailscaleLockdown bool
+
+	// cmdContext builds an *exec.Cmd. Defaults to exec.CommandContext.
+	// Override in tests to run a fake plugin.
+	cmdContext func(ctx context.Context, name string, args ...string) *exec.Cmd
+}
+
+func (p *PluginProvider) commandContext(ctx context.Context, name string, args ...string) *exec.Cmd {
+	if p.cmdContext != nil {
+		return p.cmdContext(ctx, name, args...)
+	}
+	return exec.CommandContext(ctx, name, args...)
+}
+
+// call runs one protocol exchange. Plugin stderr is streamed to out; the
+// decoded result is stored in result when non-nil.
+func (p *PluginProvider) call(ctx context.Context, req PluginRequest, out io.Writer, result any) error {
+	req.Protocol = PluginProtocolVersion
+	req.Config = p.Config
+	if req.Config == nil {
+		req.Config = map[string]any{}
+	}
+	req.TailscaleLockdown = p.TailscaleLockdown
+
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("%s plugin %s: encode request: %w", p.Name, req.Method, err)
+	}
+
+	if out == nil {
+		out = io.Discard
+	}
+	var stdout bytes.Buffer
+	cmd := p.commandContext(ctx, p.Path, req.Method)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stdout = &stdout
+	cmd.Stderr = synchronizedWriter(out)
+	runErr := cmd.Run()
+
+	var resp PluginResponse
+	if decodeErr := json.Unmarshal(bytes.TrimSpace(stdout.Bytes()), &resp); decodeErr != nil {
+		if runErr != nil {
+			return fmt.Errorf("%s plugin %s failed: %w", p.Name, req.Method, runErr)
+		}
+		return fmt.Errorf("%s plugin %s: invalid response: %w", p.Name, req.Method, decodeErr)
+	}
+	if resp.Protocol != PluginProtocolVersion {
+		return fmt.Errorf("%s plugin speaks protocol %q; hal requires %q", p.Name, resp.Protocol, PluginProtocolVersion)
+	}
+	if resp.Error != "" {
+		return fmt.Errorf("%s plugin %s failed: %s", p.Name, req.Method, resp.Error)
+	}
+	if runErr != nil {
+		return fmt.Errorf("%s plugin %s failed: %w", p.Name, req.Method, runErr)
+	}
+	if result != nil && len(resp.Result) > 0 {
+		if err := json.Unmarshal(resp.Result, result); err != nil {
+			return fmt.Errorf("%s plugin %s: invalid result: %w", p.Name, req.Method, err)
+		}
+	}
+	return nil
+}
+
+func pluginTarget(info *ConnectInfo) (*PluginTarget, error) {
+	if info == nil || strings.TrimSpace(info.Name) == "" {
+		return nil, fmt.Errorf("sandbox name is required")
+	}
+	return &PluginTarget{
+		Name:              strings.TrimSpace(info.Name),
+		ID:                strings.TrimSpace(info.WorkspaceID),
+		IP:                preferredConnectAddress(info, false),
+		PublicIP:          strings.TrimSpace(info.PublicIP),
+		TailscaleIP:       strings.TrimSpace(info.TailscaleIP),
+		TailscaleHostname: strings.TrimSpace(info.TailscaleHostname),
+		TailscaleLockdown: info.TailscaleLockdown,
+	}, nil
+}
+
+// Describe asks the plugin for its metadata.
+func (p *PluginProvider) Describe(ctx context.Context) (*PluginDescribeResult, error) {
+	var result PluginDescribeResult
+	if err := p.call(ctx, PluginRequest{Method: PluginMethodDescribe}, io.Discard, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Create provisions a new sandbox through the plugin.
+func (p *PluginProvider) Create(ctx context.Context, name string, env map[string]string, out io.Writer) (*SandboxResult, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, fmt.Errorf("sandbox name is required")
+	}
+	var result PluginCreateResult
+	req := PluginRequest{Method: PluginMethodCreate, Name: name, Env: withLockdownEnv(env, p.TailscaleLockdown)}
+	if err := p.call(ctx, req, out, &result); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(result.Name) == "" {
+		result.Name = name
+	}
+	return &SandboxResult{
+		ID:          strings.TrimSpace(result.ID),
+		Name:        strings.TrimSpace(result.Name),
+		IP:          strings.TrimSpace(result.IP),
+		TailscaleIP: strings.TrimSpace(result.TailscaleIP),
+	}, nil
+}
+
+// Stop halts a running sandbox through the plugin.
+func (p *PluginProvider) Stop(ctx context.Context, info *ConnectInfo, out io.Writer) error {
+	target, err := pluginTarget(info)
+	if err != nil {
+		return err
+	}
+	return p.call(ctx, PluginRequest{Method: PluginMethodStop, Target: target}, out, nil)
+}
+
+// Start powers on a stopped sandbox through the plugin.
+func (p *PluginProvider) Start(ctx context.Context, info *ConnectInfo, out io.Writer) (*LifecycleResult, error) {
+	target, err := pluginTarget(info)
+	if err != nil {
+		return nil, err
+	}
+	var result PluginStatusResult
+	if err := p.call(ctx, PluginRequest{Method: PluginMethodStart, Target: target}, out, &result); err != nil {
+		return nil, err
+	}
+	status := strings.TrimSpace(result.Status)
+	if status == "" {
+		status = StatusRunning
+	}
+	return &LifecycleResult{Status: status, IP: strings.TrimSpace(result.IP)}, nil
+}
+
+// Delete removes a sandbox through the plugin. Plugins must treat deleting
+// an already-missing sandbox as success.
+func (p *PluginProvider) Delete(ctx context.Context, info *ConnectInfo, out io.Writer) error {
+	target, err := pluginTarget(info)
+	if err != nil {
+		return err
+	}
+	return p.call(ctx, PluginRequest{Method: PluginMethodDelete, Target: target}, out, nil)
+}
+
+// List asks the plugin for its inventory. list is optional in
+// hal-provider-v1; plugins without it answer with an error.
+func (p *PluginProvider) List(ctx context.Context) ([]RemoteInstance, error) {
+	var result PluginListResult
+	if err := p.call(ctx, PluginRequest{Method: PluginMethodList}, io.Discard, &result); err != nil {
+		return nil, err
+	}
+	instances := make([]RemoteInstance, 0, len(result.Instances))
+	for _, inst := range result.Instances {
+		inst.Status = normalizeRemoteStatus(inst.Status, []string{StatusRunning}, []string{StatusStopped})
+		instances = append(instances, inst)
+	}
+	return instances, nil
+}
+
+// Status prints the plugin-reported status in the labeled "Status:"/"IP:"
+// form that live status parsing understands.
+func (p *PluginProvider) Status(ctx context.Context, info *ConnectInfo, out io.Writer) error {
+	target, err := pluginTarget(info)
+	if err != nil {
+		return err
+	}
+	var result PluginStatusResult
+	if err := p.call(ctx, PluginRequest{Method: PluginMethodStatus, Target: target}, io.Discard, &result); err != nil {
+		return err
+	}
+	safeOut := synchronizedWriter(out)
+	fmt.Fprintf(safeOut, "Status: %s\n", strings.TrimSpace(result.Status))
+	if ip := strings.TrimSpace(result.IP); ip != "" {
+		fmt.Fprintf(safeOut, "IP: %s\n", ip)
+	}
+	if detail := strings.TrimSpace(result.Detail); detail != "" {
+		fmt.Fprintln(safeOut, detail)
+	}
+	return nil
+}
+
+// SSH asks the plugin for the local command that opens an interactive shell.
+func (p *PluginProvider) SSH(info *ConnectInfo) (*exec.Cmd, error) {
+	return p.command(PluginMethodSSH, info, nil)
+}
+
+// Exec asks the plugin for the local command that runs args in the sandbox.
+func (p *PluginProvider) Exec(info *ConnectInfo, args []string) (*exec.Cmd, error) {
+	return p.command(PluginMethodExec, info, args)
+}
+
+func (p *PluginProvider) command(method string, info *ConnectInfo, args []string) (*exec.Cmd, error) {
+	target, err := pluginTarget(info)
+	if err != nil {
+		return nil, err
+	}
+	var result PluginCommandResult
+	if err := p.call(context.Background(), PluginRequest{Method: method, Target: target, Args: args}, os.Stderr, &result); err != nil {
+		return nil, err
+	}
+	if len(result.Argv) == 0 || strings.TrimSpace(result.Argv[0]) == "" {
+		return nil, fmt.Errorf("%s plugin %s returned an empty command", p.Name, method)
+	}
+	cmd := exec.Command(result.Argv[0], result.Argv[1:]...)
+	if len(result.Env) > 0 {
+		cmd.Env = os.Environ()
+		keys := make([]string, 0, len(result.Env))
+		for key := range result.Env {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			cmd.Env = append(cmd.Env, key+"="+result.Env[key])
+		}
+	}
+	return cmd, nil
+}
@@ -0,0 +1,188 @@
+package sandbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// fakePluginScript records each request to $HAL_PLUGIN_LOG/<method>.json and
+// answers with canned hal-provider-v1 responses.
+const fakePluginScript = `#!/bin/sh
+cat > "$HAL_PLUGIN_LOG/$1.json"
+case "$1" in
+describe) echo '{"protocol":"hal-provider-v1","result":{"name":"fake","description":"Fake VMs","sizes":["small","large"]}}' ;;
+create)
+  echo "provisioning..." >&2
+  echo '{"protocol":"hal-provider-v1","result":{"id":"vm-42","ip":"203.0.113.9"}}' ;;
+start) echo '{"protocol":"hal-provider-v1","result":{"status":"running","ip":"203.0.113.10"}}' ;;
+status) echo '{"protocol":"hal-provider-v1","result":{"status":"stopped","ip":"203.0.113.9"}}' ;;
+stop) echo '{"protocol":"hal-provider-v1"}' ;;
+delete) echo '{"protocol":"hal-provider-v1","error":"quota API unavailable"}' ;;
+exec) echo '{"protocol":"hal-provider-v1","result":{"argv":["ssh","root@203.0.113.9","--","echo","hi"],"env":{"FAKE_TOKEN":"t"}}}' ;;
+ssh) echo '{"protocol":"hal-provider-v2","result":{}}' ;;
+list) echo '{"protocol":"hal-provider-v1","result":{"instances":[{"id":"vm-42","name":"box","status":"RUNNING","managed":true}]}}' ;;
+esac
+`
+
+func installFakePlugin(t *testing.T) (binDir, logDir string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script fixture is unix-only")
+	}
+	binDir = t.TempDir()
+	logDir = t.TempDir()
+	if err := os.WriteFile(filepath.Join(binDir, "hal-provider-fake"), []byte(fakePluginScript), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// Non-executable files and built-in names are never treated as plugins.
+	if err := os.WriteFile(filepath.Join(binDir, "hal-provider-notes"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(binDir, "hal-provider-hetzner"), []byte(fakePluginScript), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("HAL_PLUGIN_LOG", logDir)
+	return binDir, logDir
+}
+
+func readPluginRequest(t *testing.T, logDir, method string) PluginRequest {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(logDir, method+".json"))
+	if err != nil {
+		t.Fatalf("read %s request: %v", method, err)
+	}
+	var req PluginRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		t.Fatalf("decode %s request: %v\n%s", method, err, data)
+	}
+	return req
+}
+
+func TestDiscoverPlugins(t *testing.T) {
+	binDir, _ := installFakePlugin(t)
+	t.Setenv("PATH", binDir)
+
+	plugins := DiscoverPlugins()
+	if len(plugins) != 1 || plugins[0].Name != "fake" || plugins[0].Path != filepath.Join(binDir, "hal-provider-fake") {
+		t.Fatalf("DiscoverPlugins() = %+v, want only fake", plugins)
+	}
+}
+
+func TestProviderFromConfig_Plugin(t *testing.T) {
+	_, logDir := installFakePlugin(t)
+
+	p, err := ProviderFromConfig("fake", ProviderConfig{
+		Plugins:           map[string]map[string]any{"fake": {"region": "ewr"}},
+		TailscaleLockdown: true,
+	})
+	if err != nil {
+		t.Fatalf("ProviderFromConfig: %v", err)
+	}
+
+	var out bytes.Buffer
+	result, err := p.Create(context.Background(), "box", map[string]string{"GIT_TOKEN": "x"}, &out)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if result.ID != "vm-42" || result.Name != "box" || result.IP != "203.0.113.9" {
+		t.Errorf("Create result = %+v", result)
+	}
+	if !strings.Contains(out.String(), "provisioning...") {
+		t.Errorf("plugin stderr should stream to out, got %q", out.String())
+	}
+
+	req := readPluginRequest(t, logDir, "create")
+	if req.Protocol != PluginProtocolVersion || req.Method != "create" || req.Name != "box" {
+		t.Errorf("create request header = %+v", req)
+	}
+	if req.Config["region"] != "ewr" || !req.TailscaleLockdown || req.Env["TAILSCALE_LOCKDOWN"] != "true" {
+		t.Errorf("create request should pass config and lockdown through, got %+v", req)
+	}
+
+	if _, err := ProviderFromConfig("missing", ProviderConfig{}); err == nil || !strings.Contains(err.Error(), "hal-provider-<name>") {
+		t.Errorf("unknown provider error = %v, want plugin hint", err)
+	}
+}
+
+func TestPluginProvider_Lifecycle(t *testing.T) {
+	_, logDir := installFakePlugin(t)
+	p, err := ProviderFromConfig("fake", ProviderConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := &ConnectInfo{Name: "box", IP: "203.0.113.9", WorkspaceID: "vm-42"}
+	ctx := context.Background()
+
+	if err := p.Stop(ctx, info, nil); err != nil {
+		t.Errorf("Stop: %v", err)
+	}
+	if req := readPluginRequest(t, logDir, "stop"); req.Target == nil || req.Target.ID != "vm-42" || req.Config == nil {
+		t.Errorf("stop request = %+v, want target id and empty config object", req)
+	}
+
+	started, err := p.Start(ctx, info, nil)
+	if err != nil || started.Status != StatusRunning || started.IP != "203.0.113.10" {
+		t.Errorf("Start = %+v, %v", started, err)
+	}
+
+	var status bytes.Buffer
+	if err := p.Status(ctx, info, &status); err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if !strings.Contains(status.String(), "Status: stopped") || !strings.Contains(status.String(), "IP: 203.0.113.9") {
+		t.Errorf("Status output = %q", status.String())
+	}
+
+	if err := p.Delete(ctx, info, nil); err == nil || !strings.Contains(err.Error(), "quota API unavailable") {
+		t.Errorf("Delete error = %v, want plugin error", err)
+	}
+
+	cmd, err := p.Exec(info, []string{"echo", "hi"})
+	if err != nil {
+		t.Fatalf("Exec: %v", err)
+	}
+	if got := strings.Join(cmd.Args, " "); got != "ssh root@203.0.113.9 -- echo hi" {
+		t.Errorf("Exec args = %q", got)
+	}
+	if cmd.Env[len(cmd.Env)-1] != "FAKE_TOKEN=t" {
+		t.Errorf("Exec env should include plugin env, got tail %q", cmd.Env[len(cmd.Env)-1])
+	}
+	if req := readPluginRequest(t, logDir, "exec"); strings.Join(req.Args, " ") != "echo hi" {
+		t.Errorf("exec request args = %v", req.Args)
+	}
+
+	listed, err := p.(Lister).List(ctx)
+	if err != nil || len(listed) != 1 || listed[0].Status != StatusRunning || !listed[0].Managed {
+		t.Errorf("List = %+v, %v", listed, err)
+	}
+
+	if _, err := p.SSH(info); err == nil || !strings.Contains(err.Error(), `protocol "hal-provider-v2"`) {
+		t.Errorf("SSH error = %v, want protocol mismatch", err)
+	}
+}
+
+func TestPluginProvider_Describe(t *testing.T) {
+	installFakePlugin(t)
+	plugin, err := LookupPlugin("fake")
+	if err != nil {
+		t.Fatal(err)
+	}
+	described, err := (&PluginProvider{Name: plugin.Name, Path: plugin.Path}).Describe(context.Background())
+	if err != nil {
+		t.Fatalf("Describe: %v", err)
+	}
+	if described.Description != "Fake VMs" || len(described.Sizes) != 2 {
+		t.Errorf("Describe = %+v", described)
+	}
+
+	if _, err := LookupPlugin("../evil"); err == nil {
+		t.Error("LookupPlugin should reject path-like names")
+	}
+}
@@ -0,0 +1,116 @@
+package sandbox
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	reapLogFileName  = "reap.log"
+	reapLockFileName = "reap.lock"
+)
+
+// Reap actions recorded in ReapRecord.Action.
+const (
+	ReapActionNone    = "none"
+	ReapActionStop    = "stop"
+	ReapActionDelete  = "delete"
+	ReapActionSkipped = "skipped"
+	ReapActionFailed  = "failed"
+)
+
+// ReapStaleLockAge is how old a reap lock must be before another reaper may
+// take it over. It guards against a crashed cron run wedging future runs.
+const ReapStaleLockAge = time.Hour
+
+// ErrReapLocked is returned by AcquireReapLock when another reaper holds a
+// fresh lock.
+var ErrReapLocked = errors.New("another sandbox reap is already running")
+
+// ReapRecord captures the outcome of the most recent idle check for a sandbox.
+type ReapRecord struct {
+	CheckedAt      time.Time  `json:"checkedAt"`
+	LastActivityAt *time.Time `json:"lastActivityAt,omitempty"`
+	Action         string     `json:"action"`
+	Reason         string     `json:"reason,omitempty"`
+	DryRun         bool       `json:"dryRun,omitempty"`
+}
+
+// ReapLogEntry is one line of the append-only reap history. It outlives the
+// registry entry so deletions performed by the reaper remain auditable.
+type ReapLogEntry struct {
+	Name     string `json:"name"`
+	Provider string `json:"provider"`
+	ReapRecord
+}
+
+// ReapLogPath returns the path of the reap history file.
+func ReapLogPath() string {
+	dir := GlobalDir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, reapLogFileName)
+}
+
+// AppendReapLog appends entry to the reap history as a JSON line.
+func AppendReapLog(entry ReapLogEntry) error {
+	if err := EnsureGlobalDir(); err != nil {
+		return err
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshal reap log entry: %w", err)
+	}
+	f, err := os.OpenFile(ReapLogPath(), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("open reap log: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("write reap log: %w", err)
+	}
+	return f.Close()
+}
+
+// AcquireReapLock takes the global reap lock so overlapping cron runs never
+// act on the same sandbox twice. Locks older than ReapStaleLockAge are
+// replaced. The returned release function removes the lock.
+func AcquireReapLock() (func(), error) {
+	if err := EnsureGlobalDir(); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(GlobalDir(), reapLockFileName)
+
+	for attempt := 0; attempt < 2; attempt++ {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if err == nil {
+			fmt.Fprintf(f, "%d\n", os.Getpid())
+			f.Close()
+			return func() { _ = os.Remove(path) }, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, fmt.Errorf("create reap lock: %w", err)
+		}
+
+		info, statErr := os.Stat(path)
+		if statErr != nil {
+			if errors.Is(statErr, fs.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("check reap lock: %w", statErr)
+		}
+		if time.Since(info.ModTime()) < ReapStaleLockAge {
+			return nil, ErrReapLocked
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("remove stale reap lock: %w", err)
+		}
+	}
+	return nil, ErrReapLocked
+}
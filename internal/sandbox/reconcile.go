@@ -0,0 +1,256 @@
+package sandbox
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Labels hal attaches to provider resources at create time so reconcile can
+// tell hal-managed machines apart from anything else in the account.
+const (
+	ManagedLabelKey   = "managed-by"
+	ManagedLabelValue = "hal"
+)
+
+// Reconcile finding kinds.
+const (
+	ReconcileOrphan = "orphan" // exists at the provider, unknown to hal
+	ReconcileGhost  = "ghost"  // in the registry, gone at the provider
+	ReconcileDrift  = "drift"  // in both, but registry fields are stale
+	ReconcileInSync = "in-sync"
+)
+
+// RemoteInstance is a machine as reported by a provider's inventory.
+type RemoteInstance struct {
+	ID     string `json:"id,omitempty"`
+	Name   string `json:"name"`
+	IP     string `json:"ip,omitempty"`
+	Status string `json:"status,omitempty"`
+	Size   string `json:"size,omitempty"`
+	// Managed is true when the instance carries hal's label or tag.
+	Managed bool `json:"managed"`
+	// CreatedAt is when the provider created the instance; zero when the
+	// provider does not report it.
+	CreatedAt time.Time `json:"createdAt,omitzero"`
+}
+
+// remoteTime decodes a provider creation timestamp: an RFC 3339 string or
+// Unix seconds. Values it cannot parse decode to the zero time rather than
+// failing the whole inventory.
+type remoteTime struct{ time.Time }
+
+func (t *remoteTime) UnmarshalJSON(data []byte) error {
+	var s string
+	if json.Unmarshal(data, &s) == nil {
+		if parsed, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(s)); err == nil {
+			t.Time = parsed.UTC()
+		}
+		return nil
+	}
+	var seconds float64
+	if json.Unmarshal(data, &seconds) == nil && seconds > 0 {
+		t.Time = time.Unix(0, int64(seconds*float64(time.Second))).UTC()
+	}
+	return nil
+}
+
+// Lister is implemented by providers that can enumerate their instances.
+// Implementations normalize Status to StatusRunning or StatusStopped when the
+// provider state maps onto them and return the lowercase provider state
+// otherwise (for example "pending").
+type Lister interface {
+	List(ctx context.Context) ([]RemoteInstance, error)
+}
+
+// FieldDrift is one registry field that disagrees with the provider.
+type FieldDrift struct {
+	Field    string `json:"field"`
+	Registry string `json:"registry"`
+	Provider string `json:"provider"`
+}
+
+// ReconcileFinding describes how one sandbox compares between the registry
+// and the provider.
+type ReconcileFinding struct {
+	Kind     string          `json:"kind"`
+	Name     string          `json:"name"`
+	Provider string          `json:"provider"`
+	Registry *SandboxState   `json:"-"`
+	Remote   *RemoteInstance `json:"remote,omitempty"`
+	Drift    []FieldDrift    `json:"drift,omitempty"`
+	// Unlabeled marks an orphan found by hal's naming scheme because it
+	// lacks hal's label, for example one created before labels existed.
+	Unlabeled bool `json:"unlabeled,omitempty"`
+}
+
+// Reconcile compares registry entries for providerName with the provider's
+// inventory. Registry entries match remote instances by workspace ID first
+// and by name otherwise. Unmatched remote instances are reported as orphans
+// when they carry hal's label or their name follows hal's naming scheme (see
+// matchesNamingScheme); the latter are flagged Unlabeled. Other machines are
+// left alone. Findings are sorted by name.
+func Reconcile(providerName string, registry []*SandboxState, remote []RemoteInstance) []ReconcileFinding {
+	byID := make(map[string]int, len(remote))
+	byName := make(map[string]int, len(remote))
+	for i, inst := range remote {
+		if id := strings.TrimSpace(inst.ID); id != "" {
+			byID[id] = i
+		}
+		byName[strings.TrimSpace(inst.Name)] = i
+	}
+
+	matched := make(map[int]bool, len(remote))
+	var findings []ReconcileFinding
+	for _, entry := range registry {
+		if entry == nil || entry.Provider != providerName {
+			continue
+		}
+		idx, ok := -1, false
+		if id := strings.TrimSpace(entry.WorkspaceID); id != "" {
+			idx, ok = byID[id]
+		}
+		if !ok {
+			idx, ok = byName[entry.Name]
+		}
+		if !ok || matched[idx] {
+			findings = append(findings, ReconcileFinding{Kind: ReconcileGhost, Name: entry.Name, Provider: providerName, Registry: entry})
+			continue
+		}
+		matched[idx] = true
+		inst := remote[idx]
+		finding := ReconcileFinding{Kind: ReconcileInSync, Name: entry.Name, Provider: providerName, Registry: entry, Remote: &inst}
+		if drift := registryDrift(entry, inst); len(drift) > 0 {
+			finding.Kind = ReconcileDrift
+			finding.Drift = drift
+		}
+		findings = append(findings, finding)
+	}
+
+	for i, inst := range remote {
+		if matched[i] {
+			continue
+		}
+		unlabeled := !inst.Managed
+		if unlabeled && !matchesNamingScheme(inst.Name, registry) {
+			continue
+		}
+		inst := inst
+		findings = append(findings, ReconcileFinding{Kind: ReconcileOrphan, Name: inst.Name, Provider: providerName, Remote: &inst, Unlabeled: unlabeled})
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].Name < findings[j].Name })
+	return findings
+}
+
+// matchesNamingScheme reports whether name looks like a sandbox hal created:
+// the default name "sandbox", or a batch name {base}-NN whose base is
+// "sandbox" or the name or batch base of a registered sandbox.
+func matchesNamingScheme(name string, registry []*SandboxState) bool {
+	if ValidateName(name) != nil {
+		return false
+	}
+	base := batchBase(name)
+	if base == defaultSandboxName {
+		return true
+	}
+	if base == name {
+		return false
+	}
+	for _, entry := range registry {
+		if entry != nil && batchBase(entry.Name) == base {
+			return true
+		}
+	}
+	return false
+}
+
+// batchBase strips a BatchNames suffix (-NN, two or more digits) from name.
+func batchBase(name string) string {
+	i := strings.LastIndexByte(name, '-')
+	if i <= 0 || len(name)-i-1 < 2 {
+		return name
+	}
+	for _, c := range name[i+1:] {
+		if c < '0' || c > '9' {
+			return name
+		}
+	}
+	return name[:i]
+}
+
+// registryDrift lists fields where the provider disagrees with the registry.
+// Fields the provider does not report are never treated as drift, and status
+// is only compared when both sides are a settled running/stopped state.
+func registryDrift(entry *SandboxState, inst RemoteInstance) []FieldDrift {
+	var drift []FieldDrift
+	if ip := strings.TrimSpace(inst.IP); ip != "" && ip != strings.TrimSpace(entry.IP) {
+		drift = append(drift, FieldDrift{Field: "ip", Registry: entry.IP, Provider: ip})
+	}
+	if settledStatus(inst.Status) && settledStatus(entry.Status) && inst.Status != entry.Status {
+		drift = append(drift, FieldDrift{Field: "status", Registry: entry.Status, Provider: inst.Status})
+	}
+	if size := strings.TrimSpace(inst.Size); size != "" && strings.TrimSpace(entry.Size) != "" && size != entry.Size {
+		drift = append(drift, FieldDrift{Field: "size", Registry: entry.Size, Provider: size})
+	}
+	return drift
+}
+
+func settledStatus(status string) bool {
+	return status == StatusRunning || status == StatusStopped
+}
+
+// ApplyDrift updates entry with the provider's view of every drifted field.
+func ApplyDrift(entry *SandboxState, drift []FieldDrift) {
+	for _, d := range drift {
+		switch d.Field {
+		case "ip":
+			entry.IP = d.Provider
+		case "status":
+			entry.Status = d.Provider
+		case "size":
+			entry.Size = d.Provider
+		}
+	}
+}
+
+// AdoptedState builds a registry entry for an orphaned remote instance.
+// CreatedAt is the provider's creation time so cost estimates cover the
+// instance's whole life, or now when the provider does not report it.
+func AdoptedState(providerName string, inst RemoteInstance, now time.Time) *SandboxState {
+	status := inst.Status
+	if !settledStatus(status) {
+		status = StatusUnknown
+	}
+	createdAt := inst.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = now
+	}
+	return &SandboxState{
+		Name:        inst.Name,
+		Provider:    providerName,
+		WorkspaceID: inst.ID,
+		IP:          inst.IP,
+		Status:      status,
+		Size:        inst.Size,
+		CreatedAt:   createdAt,
+	}
+}
+
+// normalizeRemoteStatus maps provider-specific states onto running/stopped.
+func normalizeRemoteStatus(state string, running, stopped []string) string {
+	state = strings.ToLower(strings.TrimSpace(state))
+	for _, s := range running {
+		if state == s {
+			return StatusRunning
+		}
+	}
+	for _, s := range stopped {
+		if state == s {
+			return StatusStopped
+		}
+	}
+	return state
+}
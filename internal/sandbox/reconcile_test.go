@@ -0,0 +1,164 @@
+package sandbox
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReconcile(t *testing.T) {
+	registry := []*SandboxState{
+		{Name: "same", Provider: "hetzner", IP: "203.0.113.1", Status: StatusRunning, Size: "cx22"},
+		{Name: "moved", Provider: "hetzner", IP: "203.0.113.2", Status: StatusRunning, Size: "cx22"},
+		{Name: "gone", Provider: "hetzner", IP: "203.0.113.3", Status: StatusStopped},
+		{Name: "renamed", Provider: "hetzner", WorkspaceID: "77", Status: StatusRunning},
+		{Name: "elsewhere", Provider: "digitalocean"},
+	}
+	remote := []RemoteInstance{
+		{ID: "1", Name: "same", IP: "203.0.113.1", Status: StatusRunning, Size: "cx22", Managed: true},
+		{ID: "2", Name: "moved", IP: "198.51.100.2", Status: StatusStopped, Size: "cx32", Managed: true},
+		{ID: "77", Name: "renamed-in-console", Status: "starting", Managed: true},
+		{ID: "3", Name: "crashed-create", IP: "198.51.100.9", Status: StatusRunning, Managed: true},
+		{ID: "4", Name: "unrelated-web", Status: StatusRunning},
+	}
+
+	got := map[string]ReconcileFinding{}
+	for _, f := range Reconcile("hetzner", registry, remote) {
+		got[f.Name] = f
+	}
+	if len(got) != 5 {
+		t.Fatalf("findings = %+v, want 5 (unlabeled and other-provider machines ignored)", got)
+	}
+	if got["same"].Kind != ReconcileInSync {
+		t.Errorf("same = %+v, want in-sync", got["same"])
+	}
+	if got["gone"].Kind != ReconcileGhost || got["gone"].Registry == nil {
+		t.Errorf("gone = %+v, want ghost with registry entry", got["gone"])
+	}
+	if got["crashed-create"].Kind != ReconcileOrphan || got["crashed-create"].Remote.ID != "3" {
+		t.Errorf("crashed-create = %+v, want orphan", got["crashed-create"])
+	}
+	if got["renamed"].Kind != ReconcileInSync {
+		t.Errorf("renamed = %+v, want matched by workspace ID with unsettled status ignored", got["renamed"])
+	}
+
+	moved := got["moved"]
+	if moved.Kind != ReconcileDrift || len(moved.Drift) != 3 {
+		t.Fatalf("moved = %+v, want ip, status and size drift", moved)
+	}
+	updated := *moved.Registry
+	ApplyDrift(&updated, moved.Drift)
+	if updated.IP != "198.51.100.2" || updated.Status != StatusStopped || updated.Size != "cx32" {
+		t.Errorf("ApplyDrift = %+v", updated)
+	}
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	adopted := AdoptedState("hetzner", RemoteInstance{ID: "9", Name: "x", Status: "starting"}, now)
+	if adopted.Status != StatusUnknown || adopted.WorkspaceID != "9" || adopted.Provider != "hetzner" || !adopted.CreatedAt.Equal(now) {
+		t.Errorf("AdoptedState = %+v", adopted)
+	}
+	created := now.Add(-72 * time.Hour)
+	if adopted := AdoptedState("hetzner", RemoteInstance{ID: "9", Name: "x", CreatedAt: created}, now); !adopted.CreatedAt.Equal(created) {
+		t.Errorf("AdoptedState CreatedAt = %v, want the provider's %v", adopted.CreatedAt, created)
+	}
+}
+
+func TestReconcile_UnlabeledNamingScheme(t *testing.T) {
+	registry := []*SandboxState{
+		{Name: "worker-01", Provider: "hetzner"},
+	}
+	remote := []RemoteInstance{
+		{ID: "1", Name: "worker-01", Managed: true},
+		{ID: "2", Name: "worker-02"},
+		{ID: "3", Name: "sandbox"},
+		{ID: "4", Name: "sandbox-03"},
+		{ID: "5", Name: "web-01"},
+		{ID: "6", Name: "worker"},
+		{ID: "7", Name: "labeled", Managed: true},
+	}
+
+	got := map[string]ReconcileFinding{}
+	for _, f := range Reconcile("hetzner", registry, remote) {
+		got[f.Name] = f
+	}
+	for _, name := range []string{"worker-02", "sandbox", "sandbox-03"} {
+		if f := got[name]; f.Kind != ReconcileOrphan || !f.Unlabeled {
+			t.Errorf("%s = %+v, want unlabeled orphan", name, f)
+		}
+	}
+	for _, name := range []string{"web-01", "worker"} {
+		if f, ok := got[name]; ok {
+			t.Errorf("%s = %+v, want ignored", name, f)
+		}
+	}
+	if f := got["labeled"]; f.Kind != ReconcileOrphan || f.Unlabeled {
+		t.Errorf("labeled = %+v, want labeled orphan", f)
+	}
+}
+
+func TestProviderList_ParsesInventory(t *testing.T) {
+	fake := func(output string, calls *[]string) func(context.Context, string, ...string) *exec.Cmd {
+		return func(ctx context.Context, name string, args ...string) *exec.Cmd {
+			*calls = append(*calls, name+" "+strings.Join(args, " "))
+			return exec.CommandContext(ctx, "printf", "%s", output)
+		}
+	}
+	lookPath := func(string) (string, error) { return "/usr/bin/fake", nil }
+
+	tests := []struct {
+		name     string
+		lister   func(calls *[]string) Lister
+		wantCall string
+		want     RemoteInstance
+	}{
+		{
+			name: "hetzner",
+			lister: func(calls *[]string) Lister {
+				return &HetznerProvider{cmdContext: fake(`[{"id":42,"name":"dev","status":"off","public_net":{"ipv4":{"ip":"203.0.113.5"}},"server_type":{"name":"cx22"},"labels":{"managed-by":"hal"},"created":"2026-10-01T08:00:00+00:00"}]`, calls)}
+			},
+			wantCall: "hcloud server list -o json",
+			want:     RemoteInstance{ID: "42", Name: "dev", IP: "203.0.113.5", Status: StatusStopped, Size: "cx22", Managed: true, CreatedAt: time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)},
+		},
+		{
+			name: "digitalocean",
+			lister: func(calls *[]string) Lister {
+				return &DigitalOceanProvider{lookPath: lookPath, cmdContext: fake(`[{"id":7,"name":"dev","status":"active","size_slug":"s-1vcpu-1gb","tags":["hal"],"created_at":"2026-10-02T09:30:00Z","networks":{"v4":[{"ip_address":"10.0.0.2","type":"private"},{"ip_address":"203.0.113.6","type":"public"}]}}]`, calls)}
+			},
+			wantCall: "doctl compute droplet list --output json",
+			want:     RemoteInstance{ID: "7", Name: "dev", IP: "203.0.113.6", Status: StatusRunning, Size: "s-1vcpu-1gb", Managed: true, CreatedAt: time.Date(2026, 10, 2, 9, 30, 0, 0, time.UTC)},
+		},
+		{
+			name: "lightsail",
+			lister: func(calls *[]string) Lister {
+				return &LightsailProvider{lookPath: lookPath, cmdContext: fake(`{"instances":[{"name":"dev","publicIpAddress":"203.0.113.7","bundleId":"small_3_0","createdAt":1790000000.0,"state":{"name":"pending"},"tags":[]}]}`, calls)}
+			},
+			wantCall: "aws lightsail get-instances --output json",
+			want:     RemoteInstance{ID: "dev", Name: "dev", IP: "203.0.113.7", Status: "pending", Size: "small_3_0", CreatedAt: time.Unix(1790000000, 0).UTC()},
+		},
+		{
+			name: "daytona",
+			lister: func(calls *[]string) Lister {
+				return &DaytonaProvider{APIKey: "k", cmdContext: fake(`[{"id":"abc","name":"dev","state":"started","snapshot":"hal"}]`, calls)}
+			},
+			wantCall: "daytona list --format json",
+			want:     RemoteInstance{ID: "abc", Name: "dev", Status: StatusRunning, Managed: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			got, err := tt.lister(&calls).List(context.Background())
+			if err != nil {
+				t.Fatalf("List: %v", err)
+			}
+			if len(calls) != 1 || calls[0] != tt.wantCall {
+				t.Errorf("calls = %q, want %q", calls, tt.wantCall)
+			}
+			if len(got) != 1 || got[0] != tt.want {
+				t.Errorf("List = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
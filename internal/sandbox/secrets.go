@@ -0,0 +1,241 @@
+package sandbox
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SecretRefPrefix marks a sandbox env value as a secret reference. Only
+// values starting with it are resolved, so plain values such as
+// "file:./dev.db" pass through unchanged.
+const SecretRefPrefix = "secret:"
+
+// Secret reference schemes accepted after SecretRefPrefix. A value such as
+// "secret:env:ANTHROPIC_API_KEY" is stored verbatim in config and only
+// resolved when a sandbox is created.
+const (
+	SecretRefEnv     = "env:"
+	SecretRefFile    = "file:"
+	SecretRefCmd     = "cmd:"
+	SecretRefKeyring = "keyring:"
+)
+
+// KeyringService is the default keyring service name used by hal sandbox setup.
+const KeyringService = "hal"
+
+// secretCommandTimeout bounds cmd: and keyring: lookups so a hung password
+// manager cannot block sandbox creation forever.
+const secretCommandTimeout = 30 * time.Second
+
+var (
+	secretLookupEnv = os.LookupEnv
+	secretReadFile  = os.ReadFile
+	secretHomeDir   = homeDir
+	// secretCommand builds commands for cmd: and keyring: references.
+	secretCommand = exec.CommandContext
+	secretGOOS    = runtime.GOOS
+)
+
+// IsSecretRef reports whether value is a secret reference.
+func IsSecretRef(value string) bool {
+	_, _, ok := parseSecretRef(value)
+	return ok
+}
+
+// parseSecretRef splits a "secret:<scheme><target>" value. A marked value
+// with an unknown scheme or no target is still a reference, with scheme or
+// target empty, so resolving it fails instead of leaking it as plaintext.
+func parseSecretRef(value string) (scheme, target string, ok bool) {
+	ref, found := strings.CutPrefix(strings.TrimSpace(value), SecretRefPrefix)
+	if !found {
+		return "", "", false
+	}
+	for _, scheme := range []string{SecretRefEnv, SecretRefFile, SecretRefCmd, SecretRefKeyring} {
+		if rest, found := strings.CutPrefix(ref, scheme); found {
+			return scheme, strings.TrimSpace(rest), true
+		}
+	}
+	return "", ref, true
+}
+
+// ResolveSecretRef resolves a single env value. Plain values are returned
+// unchanged with isRef false.
+func ResolveSecretRef(value string) (resolved string, isRef bool, err error) {
+	scheme, target, ok := parseSecretRef(value)
+	if !ok {
+		return value, false, nil
+	}
+	if scheme == "" {
+		return "", true, fmt.Errorf("unknown secret reference scheme; use %senv:, %sfile:, %scmd: or %skeyring:", SecretRefPrefix, SecretRefPrefix, SecretRefPrefix, SecretRefPrefix)
+	}
+	if target == "" {
+		return "", true, fmt.Errorf("secret reference has no %s target", strings.TrimSuffix(scheme, ":"))
+	}
+
+	switch scheme {
+	case SecretRefEnv:
+		v, found := secretLookupEnv(target)
+		if !found || v == "" {
+			return "", true, fmt.Errorf("environment variable %s is not set", target)
+		}
+		return v, true, nil
+	case SecretRefFile:
+		path := target
+		if rest, found := strings.CutPrefix(path, "~/"); found {
+			path = filepath.Join(secretHomeDir(), rest)
+		}
+		data, err := secretReadFile(path)
+		if err != nil {
+			return "", true, fmt.Errorf("read secret file: %w", err)
+		}
+		return strings.TrimRight(string(data), "\r\n"), true, nil
+	case SecretRefCmd:
+		v, err := runSecretCommand(shellCommand(target))
+		return v, true, err
+	default:
+		service, account := splitKeyringRef(target)
+		v, err := runSecretCommand(keyringLookupCommand(service, account))
+		return v, true, err
+	}
+}
+
+// ResolveEnvSecrets returns a copy of env with every secret reference
+// resolved, plus the resolved values so callers can redact them. Errors name
+// the env key but never the secret.
+func ResolveEnvSecrets(env map[string]string) (map[string]string, []string, error) {
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	resolved := make(map[string]string, len(env))
+	var secrets []string
+	for _, key := range keys {
+		value, isRef, err := ResolveSecretRef(env[key])
+		if err != nil {
+			return nil, nil, fmt.Errorf("resolving secret reference for %s: %w", key, err)
+		}
+		if isRef {
+			if value == "" {
+				return nil, nil, fmt.Errorf("resolving secret reference for %s: reference resolved to an empty value", key)
+			}
+			secrets = append(secrets, value)
+		}
+		resolved[key] = value
+	}
+	return resolved, secrets, nil
+}
+
+// KeyringRef returns the keyring reference stored in config for account.
+func KeyringRef(account string) string {
+	return SecretRefPrefix + SecretRefKeyring + KeyringService + "/" + account
+}
+
+// KeyringAvailable reports whether an OS keyring backend exists on this
+// platform; lookPath checks the backend CLI is installed.
+func KeyringAvailable(lookPath func(string) (string, error)) bool {
+	name := keyringBackend()
+	if name == "" {
+		return false
+	}
+	_, err := lookPath(name)
+	return err == nil
+}
+
+// StoreKeyringSecret saves value in the OS keyring under the hal service.
+// macOS uses the login keychain via security(1); Linux uses the Secret
+// Service via secret-tool(1). The value is written to stdin on both, so it
+// never shows up in the process list.
+func StoreKeyringSecret(account, value string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), secretCommandTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	switch secretGOOS {
+	case "darwin":
+		// A trailing -w without a value makes security prompt for the
+		// password and its confirmation.
+		cmd = secretCommand(ctx, "security", "add-generic-password", "-U", "-s", KeyringService, "-a", account, "-w")
+		cmd.Stdin = strings.NewReader(value + "\n" + value + "\n")
+	case "linux":
+		cmd = secretCommand(ctx, "secret-tool", "store", "--label=hal "+account, "service", KeyringService, "account", account)
+		cmd.Stdin = strings.NewReader(value)
+	default:
+		return fmt.Errorf("OS keyring is not supported on %s", secretGOOS)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("store keyring secret %s: %s: %w", account, msg, err)
+		}
+		return fmt.Errorf("store keyring secret %s: %w", account, err)
+	}
+	return nil
+}
+
+func keyringBackend() string {
+	switch secretGOOS {
+	case "darwin":
+		return "security"
+	case "linux":
+		return "secret-tool"
+	default:
+		return ""
+	}
+}
+
+// splitKeyringRef parses "service/account"; a bare account uses KeyringService.
+func splitKeyringRef(target string) (service, account string) {
+	if service, account, ok := strings.Cut(target, "/"); ok {
+		return service, account
+	}
+	return KeyringService, target
+}
+
+func keyringLookupCommand(service, account string) []string {
+	switch secretGOOS {
+	case "darwin":
+		return []string{"security", "find-generic-password", "-s", service, "-a", account, "-w"}
+	case "linux":
+		return []string{"secret-tool", "lookup", "service", service, "account", account}
+	default:
+		return nil
+	}
+}
+
+func shellCommand(script string) []string {
+	if secretGOOS == "windows" {
+		return []string{"cmd", "/C", script}
+	}
+	return []string{"sh", "-c", script}
+}
+
+func runSecretCommand(argv []string) (string, error) {
+	if len(argv) == 0 {
+		return "", fmt.Errorf("OS keyring is not supported on %s", secretGOOS)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), secretCommandTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := secretCommand(ctx, argv[0], argv[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s: %s: %w", argv[0], msg, err)
+		}
+		return "", fmt.Errorf("%s: %w", argv[0], err)
+	}
+	return strings.TrimRight(stdout.String(), "\r\n"), nil
+}
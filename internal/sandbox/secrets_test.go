@@ -0,0 +1,158 @@
+package sandbox
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestResolveEnvSecrets(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("cmd: references use sh in this test")
+	}
+	home := t.TempDir()
+	origHome := secretHomeDir
+	t.Cleanup(func() { secretHomeDir = origHome })
+	secretHomeDir = func() string { return home }
+	if err := os.WriteFile(filepath.Join(home, "anthropic"), []byte("sk-ant-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HAL_TEST_GITHUB", "ghp-env")
+
+	resolved, secrets, err := ResolveEnvSecrets(map[string]string{
+		"ANTHROPIC_API_KEY": "secret:file:~/anthropic",
+		"GITHUB_TOKEN":      "secret:env:HAL_TEST_GITHUB",
+		"OPENAI_API_KEY":    "secret:cmd: printf 'sk-openai-cmd\\n'",
+		"GIT_USER_NAME":     "Hal",
+		"NOTES":             "env:",
+		"DATABASE_URL":      "file:./dev.db",
+	})
+	if err != nil {
+		t.Fatalf("ResolveEnvSecrets: %v", err)
+	}
+	want := map[string]string{
+		"ANTHROPIC_API_KEY": "sk-ant-file",
+		"GITHUB_TOKEN":      "ghp-env",
+		"OPENAI_API_KEY":    "sk-openai-cmd",
+		"GIT_USER_NAME":     "Hal",
+		"NOTES":             "env:",
+		"DATABASE_URL":      "file:./dev.db",
+	}
+	for k, v := range want {
+		if resolved[k] != v {
+			t.Errorf("resolved[%s] = %q, want %q", k, resolved[k], v)
+		}
+	}
+	if got := strings.Join(secrets, ","); got != "sk-ant-file,ghp-env,sk-openai-cmd" {
+		t.Errorf("secrets = %q, want resolved reference values in key order", got)
+	}
+}
+
+func TestResolveEnvSecrets_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{name: "unset env", env: map[string]string{"TAILSCALE_AUTHKEY": "secret:env:HAL_TEST_MISSING"}, want: "TAILSCALE_AUTHKEY: environment variable HAL_TEST_MISSING is not set"},
+		{name: "missing file", env: map[string]string{"GITHUB_TOKEN": "secret:file:" + filepath.Join(t.TempDir(), "nope")}, want: "GITHUB_TOKEN: read secret file"},
+		{name: "empty file", env: map[string]string{"GITHUB_TOKEN": "secret:file:" + emptyFile(t)}, want: "resolved to an empty value"},
+		{name: "unknown scheme", env: map[string]string{"GITHUB_TOKEN": "secret:vault:gh"}, want: "unknown secret reference scheme"},
+		{name: "missing target", env: map[string]string{"GITHUB_TOKEN": "secret:env:"}, want: "has no env target"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := ResolveEnvSecrets(tt.env)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveSecretRef_Keyring(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake keyring backend uses sh")
+	}
+	var gotArgs []string
+	origCommand, origGOOS := secretCommand, secretGOOS
+	t.Cleanup(func() { secretCommand, secretGOOS = origCommand, origGOOS })
+	secretGOOS = "linux"
+	secretCommand = func(ctx context.Context, name string, args ...string) *exec.Cmd {
+		gotArgs = append([]string{name}, args...)
+		return exec.CommandContext(ctx, "sh", "-c", "echo tskey-from-keyring")
+	}
+
+	value, isRef, err := ResolveSecretRef("secret:keyring:TAILSCALE_AUTHKEY")
+	if err != nil || !isRef || value != "tskey-from-keyring" {
+		t.Fatalf("ResolveSecretRef = %q, %v, %v", value, isRef, err)
+	}
+	if got := strings.Join(gotArgs, " "); got != "secret-tool lookup service hal account TAILSCALE_AUTHKEY" {
+		t.Errorf("lookup command = %q", got)
+	}
+
+	if err := StoreKeyringSecret("GITHUB_TOKEN", "ghp-x"); err != nil {
+		t.Fatalf("StoreKeyringSecret: %v", err)
+	}
+	if !strings.HasPrefix(strings.Join(gotArgs, " "), "secret-tool store") {
+		t.Errorf("store command = %q", strings.Join(gotArgs, " "))
+	}
+
+	secretGOOS = "plan9"
+	if _, _, err := ResolveSecretRef("secret:keyring:hal/X"); err == nil || !strings.Contains(err.Error(), "not supported") {
+		t.Errorf("unsupported platform error = %v", err)
+	}
+	if KeyringAvailable(func(string) (string, error) { return "/bin/true", nil }) {
+		t.Error("KeyringAvailable should be false on unsupported platforms")
+	}
+}
+
+func TestStoreKeyringSecret_KeepsValueOffArgv(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake keyring backend uses sh")
+	}
+	origCommand, origGOOS := secretCommand, secretGOOS
+	t.Cleanup(func() { secretCommand, secretGOOS = origCommand, origGOOS })
+
+	const secret = "ghp-super-secret"
+	for _, goos := range []string{"darwin", "linux"} {
+		t.Run(goos, func(t *testing.T) {
+			secretGOOS = goos
+			stdinFile := filepath.Join(t.TempDir(), "stdin")
+			var gotArgs []string
+			secretCommand = func(ctx context.Context, name string, args ...string) *exec.Cmd {
+				gotArgs = append([]string{name}, args...)
+				return exec.CommandContext(ctx, "sh", "-c", `cat > "$0"`, stdinFile)
+			}
+
+			if err := StoreKeyringSecret("GITHUB_TOKEN", secret); err != nil {
+				t.Fatalf("StoreKeyringSecret: %v", err)
+			}
+			for _, arg := range gotArgs {
+				if strings.Contains(arg, secret) {
+					t.Fatalf("secret passed on argv: %q", gotArgs)
+				}
+			}
+			stdin, err := os.ReadFile(stdinFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(stdin), secret) {
+				t.Errorf("stdin = %q, want the secret", stdin)
+			}
+		})
+	}
+}
+
+func emptyFile(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "empty")
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
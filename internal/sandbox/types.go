@@ -37,4 +37,7 @@ type SandboxState struct {
 	// Labels
 	Repo       string `json:"repo,omitempty"`
 	SnapshotID string `json:"snapshotId,omitempty"`
+
+	// Reaper
+	LastReap *ReapRecord `json:"lastReap,omitempty"`
 }
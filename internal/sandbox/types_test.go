@@ -28,7 +28,7 @@ func TestSandboxStateJSONTags(t *testing.T) {
 				AutoShutdown: false,
 			},
 			wantPresent: []string{"id", "name", "provider", "ip", "status", "createdAt", "autoShutdown"},
-			wantAbsent:  []string{"workspaceId", "tailscaleIp", "tailscaleHostname", "tailscaleLockdown", "stoppedAt", "idleHours", "size", "repo", "snapshotId"},
+			wantAbsent:  []string{"workspaceId", "tailscaleIp", "tailscaleHostname", "tailscaleLockdown", "stoppedAt", "idleHours", "size", "repo", "snapshotId", "lastReap"},
 		},
 		{
 			name: "full state includes optional fields with camelCase keys",
@@ -0,0 +1,169 @@
+package skills
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jywlabs/hal/internal/template"
+)
+
+// GeminiLinker creates symlinks for Gemini CLI skill discovery.
+type GeminiLinker struct{}
+
+func init() {
+	RegisterLinker(&GeminiLinker{})
+}
+
+// Name returns the engine identifier.
+func (g *GeminiLinker) Name() string {
+	return "gemini"
+}
+
+// SkillsDir returns where Gemini CLI looks for project-level skills.
+func (g *GeminiLinker) SkillsDir() string {
+	return ".gemini/skills"
+}
+
+// CommandsDir returns where Gemini CLI looks for hal custom commands.
+// Commands in this subdirectory are namespaced as /hal:<name>.
+func (g *GeminiLinker) CommandsDir() string {
+	return ".gemini/commands/hal"
+}
+
+// LinkCommands writes a .gemini/commands/hal/<name>.toml wrapper for each
+// .hal/commands/<name>.md.
+//
+// Gemini custom commands are TOML files, so a symlink to the markdown would
+// not be discovered. Each wrapper injects the markdown with @{...} so edits to
+// .hal/commands take effect without relinking. The directory is hal-owned and
+// regenerated from scratch, which also drops wrappers for removed commands.
+func (g *GeminiLinker) LinkCommands(projectDir string) error {
+	commandsDir := filepath.Join(projectDir, g.CommandsDir())
+	halCommandsDir := filepath.Join(projectDir, template.HalDir, template.CommandsDir)
+
+	if err := os.RemoveAll(commandsDir); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(commandsDir, 0755); err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(halCommandsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".md" {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(halCommandsDir, entry.Name()))
+		if err != nil {
+			return err
+		}
+		name := strings.TrimSuffix(entry.Name(), ".md")
+		source := filepath.ToSlash(filepath.Join(template.HalDir, template.CommandsDir, entry.Name()))
+		wrapper := fmt.Sprintf(
+			"# Generated by hal from %s; edit that file instead.\ndescription = %s\nprompt = \"\"\"\n@{%s}\n\n{{args}}\n\"\"\"\n",
+			source, tomlString(commandDescription(content, name)), source,
+		)
+		if err := os.WriteFile(filepath.Join(commandsDir, name+".toml"), []byte(wrapper), 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Link creates symlinks from .gemini/skills/ to .hal/skills/.
+func (g *GeminiLinker) Link(projectDir string, skills []string) error {
+	skillsDir := filepath.Join(projectDir, g.SkillsDir())
+	if err := os.MkdirAll(skillsDir, 0755); err != nil {
+		return err
+	}
+
+	for _, skill := range skills {
+		// Use relative path for symlink (portable across machines)
+		target := filepath.Join("..", "..", template.HalDir, "skills", skill)
+		link := filepath.Join(skillsDir, skill)
+
+		// Remove existing link/dir if present
+		os.RemoveAll(link)
+
+		if err := os.Symlink(target, link); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Unlink removes skill symlinks and generated commands from .gemini/.
+func (g *GeminiLinker) Unlink(projectDir string) error {
+	skillsDir := filepath.Join(projectDir, g.SkillsDir())
+
+	for _, skill := range ManagedSkillNames {
+		link := filepath.Join(skillsDir, skill)
+		os.RemoveAll(link)
+	}
+
+	// Remove generated command wrappers
+	os.RemoveAll(filepath.Join(projectDir, g.CommandsDir()))
+
+	return nil
+}
+
+// commandDescription returns the first prose line of a command's markdown,
+// falling back to its title and then to its name.
+func commandDescription(content []byte, name string) string {
+	title := ""
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			if title == "" {
+				title = strings.TrimSpace(strings.TrimLeft(line, "#"))
+			}
+			continue
+		}
+		return line
+	}
+	if title != "" {
+		return title
+	}
+	return name
+}
+
+// tomlString quotes s as a TOML basic string.
+func tomlString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04X`, r)
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
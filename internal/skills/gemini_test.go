@@ -0,0 +1,152 @@
+package skills
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGeminiLinkerDirs(t *testing.T) {
+	linker := &GeminiLinker{}
+	if got := linker.Name(); got != "gemini" {
+		t.Errorf("Name() = %q, want %q", got, "gemini")
+	}
+	if got := linker.SkillsDir(); got != ".gemini/skills" {
+		t.Errorf("SkillsDir() = %q, want %q", got, ".gemini/skills")
+	}
+	if got := linker.CommandsDir(); got != ".gemini/commands/hal" {
+		t.Errorf("CommandsDir() = %q, want %q", got, ".gemini/commands/hal")
+	}
+}
+
+func TestGeminiLinkerLink(t *testing.T) {
+	projectDir := t.TempDir()
+	halSkillsDir := filepath.Join(projectDir, ".hal", "skills", "prd")
+	if err := os.MkdirAll(halSkillsDir, 0755); err != nil {
+		t.Fatalf("failed to create .hal/skills/prd: %v", err)
+	}
+
+	linker := &GeminiLinker{}
+	// Linking twice must be idempotent.
+	for i := 0; i < 2; i++ {
+		if err := linker.Link(projectDir, []string{"prd"}); err != nil {
+			t.Fatalf("Link() error = %v", err)
+		}
+	}
+
+	linkPath := filepath.Join(projectDir, ".gemini", "skills", "prd")
+	target, err := os.Readlink(linkPath)
+	if err != nil {
+		t.Fatalf("Could not read symlink: %v", err)
+	}
+	expected := filepath.Join("..", "..", ".hal", "skills", "prd")
+	if target != expected {
+		t.Errorf("Symlink target = %q, want %q", target, expected)
+	}
+	if _, err := os.Stat(linkPath); err != nil {
+		t.Errorf("symlink does not resolve: %v", err)
+	}
+}
+
+func TestGeminiLinkerLinkCommands(t *testing.T) {
+	projectDir := t.TempDir()
+	halCommandsDir := filepath.Join(projectDir, ".hal", "commands")
+	if err := os.MkdirAll(halCommandsDir, 0755); err != nil {
+		t.Fatalf("failed to create .hal/commands: %v", err)
+	}
+	command := "# Index Standards\n\nRebuild the \"index\" file.\n"
+	if err := os.WriteFile(filepath.Join(halCommandsDir, "index-standards.md"), []byte(command), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(halCommandsDir, "title-only.md"), []byte("# Title Only\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	commandsDir := filepath.Join(projectDir, ".gemini", "commands", "hal")
+	if err := os.MkdirAll(commandsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	stale := filepath.Join(commandsDir, "removed.toml")
+	if err := os.WriteFile(stale, []byte("prompt = \"old\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	linker := &GeminiLinker{}
+	if err := linker.LinkCommands(projectDir); err != nil {
+		t.Fatalf("LinkCommands() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(commandsDir, "index-standards.toml"))
+	if err != nil {
+		t.Fatalf("command wrapper not written: %v", err)
+	}
+	for _, want := range []string{
+		`description = "Rebuild the \"index\" file."`,
+		"@{.hal/commands/index-standards.md}",
+		"{{args}}",
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("wrapper missing %q:\n%s", want, data)
+		}
+	}
+
+	data, err = os.ReadFile(filepath.Join(commandsDir, "title-only.toml"))
+	if err != nil {
+		t.Fatalf("command wrapper not written: %v", err)
+	}
+	if !strings.Contains(string(data), `description = "Title Only"`) {
+		t.Errorf("wrapper should fall back to the title:\n%s", data)
+	}
+
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Error("stale wrapper should have been removed")
+	}
+}
+
+func TestGeminiLinkerUnlink(t *testing.T) {
+	projectDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(projectDir, ".hal", "skills", "prd"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	halCommandsDir := filepath.Join(projectDir, ".hal", "commands")
+	if err := os.MkdirAll(halCommandsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(halCommandsDir, "discover-standards.md"), []byte("discover"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	userCommand := filepath.Join(projectDir, ".gemini", "commands", "mine.toml")
+	if err := os.MkdirAll(filepath.Dir(userCommand), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(userCommand, []byte("prompt = \"mine\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	linker := &GeminiLinker{}
+	if err := linker.Link(projectDir, []string{"prd"}); err != nil {
+		t.Fatalf("Link() error = %v", err)
+	}
+	if err := linker.LinkCommands(projectDir); err != nil {
+		t.Fatalf("LinkCommands() error = %v", err)
+	}
+	if err := linker.Unlink(projectDir); err != nil {
+		t.Fatalf("Unlink() error = %v", err)
+	}
+
+	if _, err := os.Lstat(filepath.Join(projectDir, ".gemini", "skills", "prd")); !os.IsNotExist(err) {
+		t.Error("skill symlink should have been removed after Unlink")
+	}
+	if _, err := os.Stat(filepath.Join(projectDir, ".gemini", "commands", "hal")); !os.IsNotExist(err) {
+		t.Error("hal command wrappers should have been removed after Unlink")
+	}
+	if _, err := os.Stat(userCommand); err != nil {
+		t.Errorf("user command should be preserved: %v", err)
+	}
+}
+
+func TestGeminiLinkerRegistered(t *testing.T) {
+	if GetLinker("gemini") == nil {
+		t.Fatal("gemini linker not registered")
+	}
+}
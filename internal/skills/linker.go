@@ -1,5 +1,7 @@
 package skills
 
+import "sort"
+
 // EngineLinker handles skill and command installation for a specific engine.
 type EngineLinker interface {
 	// Name returns the engine identifier (e.g., "claude").
@@ -34,3 +36,17 @@ func RegisterLinker(l EngineLinker) {
 func GetLinker(name string) EngineLinker {
 	return linkers[name]
 }
+
+// Linkers returns all registered linkers sorted by name.
+func Linkers() []EngineLinker {
+	names := make([]string, 0, len(linkers))
+	for name := range linkers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	result := make([]EngineLinker, 0, len(names))
+	for _, name := range names {
+		result = append(result, linkers[name])
+	}
+	return result
+}
@@ -0,0 +1,332 @@
+package skills
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jywlabs/hal/internal/template"
+)
+
+// LockFile records, inside .hal/, the embedded version of every installed
+// skill and command file.
+const LockFile = "skills-lock.json"
+
+// HalVersion is the hal version recorded for installed files. The CLI sets
+// it from its build version.
+var HalVersion = "dev"
+
+// Kinds of installed files.
+const (
+	KindSkill   = "skill"
+	KindCommand = "command"
+)
+
+// File states reported by Status.
+const (
+	StateCurrent   = "current"   // Matches the embedded version
+	StateOutdated  = "outdated"  // Unmodified, and hal embeds a newer version
+	StateModified  = "modified"  // Edited locally; the embedded version is unchanged
+	StateDiverged  = "diverged"  // Edited locally, and hal embeds a newer version
+	StateMissing   = "missing"   // Not installed
+	StateUntracked = "untracked" // Differs from the embedded version, with no recorded install
+)
+
+// LockEntry records the embedded content an installed file came from. Base
+// is kept so upgrades can three-way merge local edits.
+type LockEntry struct {
+	Version string `json:"version"`
+	Hash    string `json:"hash"`
+	Base    string `json:"base"`
+}
+
+// Lock is the content of skills-lock.json. Files are keyed by their path
+// under .hal/, e.g. skills/prd/SKILL.md or commands/index-standards.md.
+type Lock struct {
+	Files map[string]LockEntry `json:"files"`
+}
+
+// LoadLock reads the lock file from halDir. A missing lock yields an empty
+// lock.
+func LoadLock(halDir string) (*Lock, error) {
+	lock := &Lock{Files: map[string]LockEntry{}}
+	data, err := os.ReadFile(filepath.Join(halDir, LockFile))
+	if os.IsNotExist(err) {
+		return lock, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", LockFile, err)
+	}
+	if err := json.Unmarshal(data, lock); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", LockFile, err)
+	}
+	if lock.Files == nil {
+		lock.Files = map[string]LockEntry{}
+	}
+	return lock, nil
+}
+
+// SaveLock writes the lock file to halDir.
+func SaveLock(halDir string, lock *Lock) error {
+	data, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(halDir, LockFile), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", LockFile, err)
+	}
+	return nil
+}
+
+func (l *Lock) record(rel string, content []byte) {
+	l.Files[rel] = LockEntry{Version: HalVersion, Hash: hashContent(content), Base: string(content)}
+}
+
+func hashContent(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// embeddedFile is a skill or command file hal installs into .hal/.
+type embeddedFile struct {
+	Path    string // Path under .hal/, slash-separated
+	Kind    string
+	Name    string // Skill or command name
+	Content []byte
+}
+
+// embeddedFiles lists every embedded skill and command file, sorted by path.
+func embeddedFiles() ([]embeddedFile, error) {
+	var files []embeddedFile
+	err := fs.WalkDir(skillsFS, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		content, err := fs.ReadFile(skillsFS, p)
+		if err != nil {
+			return fmt.Errorf("failed to read embedded file %s: %w", p, err)
+		}
+		name, _, _ := strings.Cut(p, "/")
+		files = append(files, embeddedFile{Path: path.Join("skills", p), Kind: KindSkill, Name: name, Content: content})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range CommandNames {
+		content, err := fs.ReadFile(commandsFS, "commands/"+name+".md")
+		if err != nil {
+			return nil, fmt.Errorf("failed to read embedded command %s: %w", name, err)
+		}
+		files = append(files, embeddedFile{Path: path.Join(template.CommandsDir, name+".md"), Kind: KindCommand, Name: name, Content: content})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
+
+// FileStatus describes one installed skill or command file.
+type FileStatus struct {
+	Path             string `json:"path"` // Under .hal/
+	Kind             string `json:"kind"`
+	Name             string `json:"name"`
+	State            string `json:"state"`
+	InstalledVersion string `json:"installedVersion,omitempty"` // hal version the file was installed from
+
+	Local    []byte `json:"-"` // nil when missing
+	Base     []byte `json:"-"` // nil when untracked
+	Embedded []byte `json:"-"`
+}
+
+// Status compares every installed skill and command file in projectDir
+// with the embedded version and the version it was installed from.
+func Status(projectDir string) ([]FileStatus, error) {
+	halDir := filepath.Join(projectDir, template.HalDir)
+	lock, err := LoadLock(halDir)
+	if err != nil {
+		return nil, err
+	}
+	files, err := embeddedFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]FileStatus, 0, len(files))
+	for _, f := range files {
+		s := FileStatus{Path: f.Path, Kind: f.Kind, Name: f.Name, Embedded: f.Content}
+		entry, tracked := lock.Files[f.Path]
+		if tracked {
+			s.InstalledVersion = entry.Version
+			s.Base = []byte(entry.Base)
+		}
+		local, err := os.ReadFile(filepath.Join(halDir, filepath.FromSlash(f.Path)))
+		switch {
+		case os.IsNotExist(err):
+			s.State = StateMissing
+		case err != nil:
+			return nil, fmt.Errorf("failed to read %s: %w", f.Path, err)
+		default:
+			s.Local = local
+			s.State = fileState(local, s.Base, tracked, f.Content)
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses, nil
+}
+
+func fileState(local, base []byte, tracked bool, embedded []byte) string {
+	switch {
+	case bytes.Equal(local, embedded):
+		return StateCurrent
+	case !tracked:
+		return StateUntracked
+	case bytes.Equal(local, base):
+		return StateOutdated
+	case bytes.Equal(base, embedded):
+		return StateModified
+	default:
+		return StateDiverged
+	}
+}
+
+// UpgradeOptions controls Upgrade.
+type UpgradeOptions struct {
+	Names  []string // Skill or command names to upgrade; empty means all
+	Prefer string   // "", PreferOurs or PreferTheirs: how to resolve conflicts
+	DryRun bool     // Report what would change without writing
+}
+
+// Upgrade actions.
+const (
+	ActionUnchanged = "unchanged" // Already current, or local edits with no new version
+	ActionInstalled = "installed" // Missing file written
+	ActionUpdated   = "updated"   // Replaced with the embedded version
+	ActionMerged    = "merged"    // Local edits merged with the embedded version
+	ActionConflict  = "conflict"  // Merged with conflict markers to resolve by hand
+	ActionKept      = "kept"      // Local version kept (--ours)
+	ActionSkipped   = "skipped"   // Untracked file left alone; needs --ours or --theirs
+)
+
+// UpgradeResult reports what Upgrade did to one file.
+type UpgradeResult struct {
+	Path      string `json:"path"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	State     string `json:"state"`  // State before the upgrade
+	Action    string `json:"action"` // One of the Action* values
+	Conflicts int    `json:"conflicts,omitempty"`
+}
+
+// Upgrade brings installed skills and commands up to the embedded versions.
+// Unmodified files are replaced; locally edited files are three-way merged
+// against the version they were installed from. Conflicting hunks get
+// conflict markers unless opts.Prefer picks a side. Untracked files that
+// differ from the embedded version are skipped unless opts.Prefer is set.
+func Upgrade(projectDir string, opts UpgradeOptions) ([]UpgradeResult, error) {
+	if opts.Prefer != "" && opts.Prefer != PreferOurs && opts.Prefer != PreferTheirs {
+		return nil, fmt.Errorf("invalid conflict preference %q (want %q or %q)", opts.Prefer, PreferOurs, PreferTheirs)
+	}
+	statuses, err := Status(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	statuses, err = FilterStatus(statuses, opts.Names)
+	if err != nil {
+		return nil, err
+	}
+
+	halDir := filepath.Join(projectDir, template.HalDir)
+	lock, err := LoadLock(halDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []UpgradeResult
+	for _, s := range statuses {
+		result := UpgradeResult{Path: s.Path, Kind: s.Kind, Name: s.Name, State: s.State, Action: ActionUnchanged}
+		var content []byte
+		switch s.State {
+		case StateCurrent, StateModified:
+		case StateMissing:
+			result.Action, content = ActionInstalled, s.Embedded
+		case StateOutdated:
+			result.Action, content = ActionUpdated, s.Embedded
+		case StateDiverged:
+			labels := MergeLabels{Ours: "local", Base: "installed (hal " + lock.Files[s.Path].Version + ")", Theirs: "hal " + HalVersion}
+			content, result.Conflicts = Merge3(s.Base, s.Local, s.Embedded, opts.Prefer, labels)
+			result.Action = ActionMerged
+			if result.Conflicts > 0 {
+				result.Action = ActionConflict
+			}
+		case StateUntracked:
+			switch opts.Prefer {
+			case PreferTheirs:
+				result.Action, content = ActionUpdated, s.Embedded
+			case PreferOurs:
+				result.Action = ActionKept
+			default:
+				result.Action = ActionSkipped
+			}
+		}
+		results = append(results, result)
+
+		if opts.DryRun || result.Action == ActionSkipped {
+			continue
+		}
+		if content != nil && !bytes.Equal(content, s.Local) {
+			dest := filepath.Join(halDir, filepath.FromSlash(s.Path))
+			if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+				return results, err
+			}
+			if err := os.WriteFile(dest, content, 0644); err != nil {
+				return results, fmt.Errorf("failed to write %s: %w", s.Path, err)
+			}
+		}
+		// The embedded version becomes the merge base for the next upgrade.
+		if s.State != StateModified {
+			lock.record(s.Path, s.Embedded)
+		}
+	}
+
+	if opts.DryRun {
+		return results, nil
+	}
+	return results, SaveLock(halDir, lock)
+}
+
+// FilterStatus keeps the files of the named skills and commands. It returns
+// an error for names hal does not install.
+func FilterStatus(statuses []FileStatus, names []string) ([]FileStatus, error) {
+	if len(names) == 0 {
+		return statuses, nil
+	}
+	wanted := map[string]bool{}
+	for _, n := range names {
+		wanted[n] = true
+	}
+	var out []FileStatus
+	for _, s := range statuses {
+		if wanted[s.Name] {
+			out = append(out, s)
+		}
+	}
+	for _, s := range out {
+		delete(wanted, s.Name)
+	}
+	if len(wanted) > 0 {
+		var unknown []string
+		for n := range wanted {
+			unknown = append(unknown, n)
+		}
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("unknown skill or command: %s", unknown[0])
+	}
+	return out, nil
+}
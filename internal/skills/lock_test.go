@@ -0,0 +1,283 @@
+package skills
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jywlabs/hal/internal/template"
+)
+
+const prdSkillPath = "skills/prd/SKILL.md"
+
+// installOlder installs every skill and command, then rewinds the prd skill
+// to an older release: local content and its recorded base become old.
+func installOlder(t *testing.T, projectDir string, old string) string {
+	t.Helper()
+	if err := InstallSkills(projectDir); err != nil {
+		t.Fatalf("InstallSkills() error = %v", err)
+	}
+	if err := InstallCommands(projectDir); err != nil {
+		t.Fatalf("InstallCommands() error = %v", err)
+	}
+	halDir := filepath.Join(projectDir, template.HalDir)
+	lock, err := LoadLock(halDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lock.Files[prdSkillPath] = LockEntry{Version: "v0.1.0", Hash: hashContent([]byte(old)), Base: old}
+	if err := SaveLock(halDir, lock); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(halDir, filepath.FromSlash(prdSkillPath))
+	if err := os.WriteFile(path, []byte(old), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func fileStates(t *testing.T, projectDir string) map[string]string {
+	t.Helper()
+	statuses, err := Status(projectDir)
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	states := map[string]string{}
+	for _, s := range statuses {
+		states[s.Path] = s.State
+	}
+	return states
+}
+
+func TestInstallRecordsLock(t *testing.T) {
+	projectDir := t.TempDir()
+	if err := InstallSkills(projectDir); err != nil {
+		t.Fatalf("InstallSkills() error = %v", err)
+	}
+	if err := InstallCommands(projectDir); err != nil {
+		t.Fatalf("InstallCommands() error = %v", err)
+	}
+
+	lock, err := LoadLock(filepath.Join(projectDir, template.HalDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, ok := lock.Files[prdSkillPath]
+	if !ok {
+		t.Fatalf("lock has no entry for %s: %v", prdSkillPath, lock.Files)
+	}
+	if entry.Version != HalVersion || !strings.HasPrefix(entry.Hash, "sha256:") {
+		t.Errorf("entry = %+v, want version %s and a sha256 hash", entry, HalVersion)
+	}
+	if _, ok := lock.Files["commands/index-standards.md"]; !ok {
+		t.Error("lock has no entry for commands/index-standards.md")
+	}
+	for path, state := range fileStates(t, projectDir) {
+		if state != StateCurrent {
+			t.Errorf("%s state = %s, want current", path, state)
+		}
+	}
+}
+
+func TestInstallCommandsKeepsLocalEdits(t *testing.T) {
+	projectDir := t.TempDir()
+	if err := InstallCommands(projectDir); err != nil {
+		t.Fatalf("InstallCommands() error = %v", err)
+	}
+	path := filepath.Join(projectDir, template.HalDir, template.CommandsDir, "index-standards.md")
+	if err := os.WriteFile(path, []byte("my edits\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := InstallCommands(projectDir); err != nil {
+		t.Fatalf("InstallCommands() error = %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "my edits\n" {
+		t.Errorf("edited command was overwritten: %q", data)
+	}
+}
+
+func TestStatusStates(t *testing.T) {
+	embedded, err := LoadSkill("prd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		local string // empty removes the file
+		base  string // empty drops the lock entry
+		want  string
+	}{
+		{name: "outdated", local: "old\n", base: "old\n", want: StateOutdated},
+		{name: "diverged", local: "old\nmine\n", base: "old\n", want: StateDiverged},
+		{name: "modified", local: embedded + "mine\n", base: embedded, want: StateModified},
+		{name: "untracked", local: "old\n", want: StateUntracked},
+		{name: "missing", base: "old\n", want: StateMissing},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectDir := t.TempDir()
+			path := installOlder(t, projectDir, tt.base)
+			halDir := filepath.Join(projectDir, template.HalDir)
+			if tt.base == "" {
+				lock, _ := LoadLock(halDir)
+				delete(lock.Files, prdSkillPath)
+				if err := SaveLock(halDir, lock); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.local == "" {
+				os.Remove(path)
+			} else if err := os.WriteFile(path, []byte(tt.local), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := fileStates(t, projectDir)[prdSkillPath]; got != tt.want {
+				t.Errorf("state = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpgrade(t *testing.T) {
+	embedded, err := LoadSkill("prd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.SplitAfter(embedded, "\n")
+	// The older release lacked the skill's last line.
+	old := strings.Join(lines[:len(lines)-2], "")
+
+	t.Run("outdated file is replaced", func(t *testing.T) {
+		projectDir := t.TempDir()
+		path := installOlder(t, projectDir, old)
+
+		results, err := Upgrade(projectDir, UpgradeOptions{Names: []string{"prd"}})
+		if err != nil {
+			t.Fatalf("Upgrade() error = %v", err)
+		}
+		if results[0].Path != prdSkillPath || results[0].Action != ActionUpdated {
+			t.Fatalf("results = %+v, want %s updated", results, prdSkillPath)
+		}
+		data, _ := os.ReadFile(path)
+		if string(data) != embedded {
+			t.Error("prd skill was not replaced with the embedded version")
+		}
+		if got := fileStates(t, projectDir)[prdSkillPath]; got != StateCurrent {
+			t.Errorf("state after upgrade = %s, want current", got)
+		}
+	})
+
+	t.Run("local edits are merged", func(t *testing.T) {
+		projectDir := t.TempDir()
+		path := installOlder(t, projectDir, old)
+		if err := os.WriteFile(path, []byte("Local preamble\n"+old), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		results, err := Upgrade(projectDir, UpgradeOptions{Names: []string{"prd"}})
+		if err != nil {
+			t.Fatalf("Upgrade() error = %v", err)
+		}
+		if results[0].Action != ActionMerged {
+			t.Fatalf("action = %s, want merged", results[0].Action)
+		}
+		data, _ := os.ReadFile(path)
+		if string(data) != "Local preamble\n"+embedded {
+			t.Errorf("merged content =\n%s", data)
+		}
+		if got := fileStates(t, projectDir)[prdSkillPath]; got != StateModified {
+			t.Errorf("state after merge = %s, want modified", got)
+		}
+	})
+
+	t.Run("conflicts get markers unless a side is preferred", func(t *testing.T) {
+		for _, prefer := range []string{"", PreferOurs, PreferTheirs} {
+			projectDir := t.TempDir()
+			path := installOlder(t, projectDir, old)
+			local := old + "Local ending\n"
+			if err := os.WriteFile(path, []byte(local), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			results, err := Upgrade(projectDir, UpgradeOptions{Names: []string{"prd"}, Prefer: prefer})
+			if err != nil {
+				t.Fatalf("Upgrade() error = %v", err)
+			}
+			data, _ := os.ReadFile(path)
+			switch prefer {
+			case "":
+				if results[0].Action != ActionConflict || results[0].Conflicts != 1 {
+					t.Errorf("result = %+v, want one conflict", results[0])
+				}
+				if !strings.Contains(string(data), "<<<<<<< local\nLocal ending\n||||||| installed (hal v0.1.0)\n") {
+					t.Errorf("conflict markers missing:\n%s", data)
+				}
+			case PreferOurs:
+				if string(data) != local {
+					t.Errorf("--ours content =\n%s", data)
+				}
+			case PreferTheirs:
+				if string(data) != embedded {
+					t.Errorf("--theirs content =\n%s", data)
+				}
+			}
+		}
+	})
+
+	t.Run("untracked files need a preference", func(t *testing.T) {
+		projectDir := t.TempDir()
+		path := installOlder(t, projectDir, old)
+		halDir := filepath.Join(projectDir, template.HalDir)
+		os.Remove(filepath.Join(halDir, LockFile))
+
+		results, err := Upgrade(projectDir, UpgradeOptions{Names: []string{"prd"}})
+		if err != nil {
+			t.Fatalf("Upgrade() error = %v", err)
+		}
+		if results[0].Action != ActionSkipped {
+			t.Fatalf("action = %s, want skipped", results[0].Action)
+		}
+
+		results, err = Upgrade(projectDir, UpgradeOptions{Names: []string{"prd"}, Prefer: PreferOurs})
+		if err != nil {
+			t.Fatalf("Upgrade(--ours) error = %v", err)
+		}
+		if results[0].Action != ActionKept {
+			t.Fatalf("action = %s, want kept", results[0].Action)
+		}
+		data, _ := os.ReadFile(path)
+		if string(data) != old {
+			t.Error("--ours changed the untracked file")
+		}
+		if got := fileStates(t, projectDir)[prdSkillPath]; got != StateModified {
+			t.Errorf("state after --ours = %s, want modified", got)
+		}
+	})
+
+	t.Run("dry run writes nothing", func(t *testing.T) {
+		projectDir := t.TempDir()
+		path := installOlder(t, projectDir, old)
+
+		results, err := Upgrade(projectDir, UpgradeOptions{DryRun: true})
+		if err != nil {
+			t.Fatalf("Upgrade() error = %v", err)
+		}
+		if len(results) == 0 {
+			t.Fatal("expected results for every installed file")
+		}
+		data, _ := os.ReadFile(path)
+		if string(data) != old {
+			t.Error("dry run changed the prd skill")
+		}
+	})
+
+	t.Run("unknown name", func(t *testing.T) {
+		_, err := Upgrade(t.TempDir(), UpgradeOptions{Names: []string{"nope"}})
+		if err == nil || !strings.Contains(err.Error(), "unknown skill or command: nope") {
+			t.Fatalf("error = %v, want unknown skill", err)
+		}
+	})
+}
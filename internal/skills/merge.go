@@ -0,0 +1,141 @@
+package skills
+
+import (
+	"slices"
+	"strings"
+)
+
+// Conflict resolution preferences for Merge3 and Upgrade.
+const (
+	PreferOurs   = "ours"   // Keep the local side of conflicting hunks
+	PreferTheirs = "theirs" // Take hal's side of conflicting hunks
+)
+
+// MergeLabels name the three sides in conflict markers.
+type MergeLabels struct {
+	Ours   string
+	Base   string
+	Theirs string
+}
+
+// Merge3 merges local edits (ours) and a new embedded version (theirs) of a
+// file installed from base, line by line like diff3. Hunks changed on only
+// one side are taken from that side. Hunks changed differently on both sides
+// are conflicts: they are resolved by prefer when set, and otherwise written
+// with conflict markers. It returns the merged content and the number of
+// conflict hunks left in it.
+func Merge3(base, ours, theirs []byte, prefer string, labels MergeLabels) ([]byte, int) {
+	o := splitAfterLines(base)
+	a := splitAfterLines(ours)
+	b := splitAfterLines(theirs)
+	ma := matchLines(o, a)
+	mb := matchLines(o, b)
+
+	var out []string
+	conflicts := 0
+	io, ia, ib := 0, 0, 0
+	for {
+		// The next base line kept unchanged on both sides ends the hunk.
+		// Matches are monotonic, so it always lies past ia and ib.
+		next := -1
+		for k := io; k < len(o); k++ {
+			if ma[k] >= 0 && mb[k] >= 0 {
+				next = k
+				break
+			}
+		}
+		eo, ea, eb := len(o), len(a), len(b)
+		if next >= 0 {
+			eo, ea, eb = next, ma[next], mb[next]
+		}
+
+		hunkO, hunkA, hunkB := o[io:eo], a[ia:ea], b[ib:eb]
+		switch {
+		case slices.Equal(hunkA, hunkO):
+			out = append(out, hunkB...)
+		case slices.Equal(hunkB, hunkO), slices.Equal(hunkA, hunkB):
+			out = append(out, hunkA...)
+		case prefer == PreferOurs:
+			out = append(out, hunkA...)
+		case prefer == PreferTheirs:
+			out = append(out, hunkB...)
+		default:
+			conflicts++
+			out = append(out, "<<<<<<< "+labels.Ours+"\n")
+			out = appendTerminated(out, hunkA)
+			out = append(out, "||||||| "+labels.Base+"\n")
+			out = appendTerminated(out, hunkO)
+			out = append(out, "=======\n")
+			out = appendTerminated(out, hunkB)
+			out = append(out, ">>>>>>> "+labels.Theirs+"\n")
+		}
+
+		if next < 0 {
+			break
+		}
+		out = append(out, o[next])
+		io, ia, ib = next+1, ma[next]+1, mb[next]+1
+	}
+	return []byte(strings.Join(out, "")), conflicts
+}
+
+// matchLines pairs lines of a with lines of b along a longest common
+// subsequence. It returns, for each line of a, the index of its match in b
+// or -1. Skill files are small, so a plain LCS table is cheap enough.
+func matchLines(a, b []string) []int {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	match := make([]int, len(a))
+	for i := range match {
+		match[i] = -1
+	}
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			match[i] = j
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return match
+}
+
+// splitAfterLines splits data into lines that keep their trailing newline,
+// so joining them restores data exactly.
+func splitAfterLines(data []byte) []string {
+	if len(data) == 0 {
+		return nil
+	}
+	lines := strings.SplitAfter(string(data), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// appendTerminated appends lines, ending the last one with a newline so a
+// conflict marker always starts on its own line.
+func appendTerminated(out, lines []string) []string {
+	out = append(out, lines...)
+	if n := len(out); len(lines) > 0 && !strings.HasSuffix(out[n-1], "\n") {
+		out[n-1] += "\n"
+	}
+	return out
+}
@@ -0,0 +1,80 @@
+package skills
+
+import "testing"
+
+func TestMerge3(t *testing.T) {
+	labels := MergeLabels{Ours: "local", Base: "installed", Theirs: "new"}
+	base := "# Skill\nintro\nstep one\nstep two\noutro\n"
+
+	tests := []struct {
+		name          string
+		ours, theirs  string
+		prefer        string
+		want          string
+		wantConflicts int
+	}{
+		{
+			name:   "only upstream changed",
+			ours:   base,
+			theirs: "# Skill\nintro\nstep one (improved)\nstep two\noutro\n",
+			want:   "# Skill\nintro\nstep one (improved)\nstep two\noutro\n",
+		},
+		{
+			name:   "only local changed",
+			ours:   "# Skill\nintro\nstep one\nstep two\nlocal note\noutro\n",
+			theirs: base,
+			want:   "# Skill\nintro\nstep one\nstep two\nlocal note\noutro\n",
+		},
+		{
+			name:   "separate hunks merge cleanly",
+			ours:   "# Skill\nlocal intro\nstep one\nstep two\noutro\n",
+			theirs: "# Skill\nintro\nstep one\nstep two\noutro\nnew appendix\n",
+			want:   "# Skill\nlocal intro\nstep one\nstep two\noutro\nnew appendix\n",
+		},
+		{
+			name:   "same change on both sides",
+			ours:   "# Skill\nintro\nstep 1\nstep two\noutro\n",
+			theirs: "# Skill\nintro\nstep 1\nstep two\noutro\n",
+			want:   "# Skill\nintro\nstep 1\nstep two\noutro\n",
+		},
+		{
+			name:          "conflicting hunk gets markers",
+			ours:          "# Skill\nintro\nstep one (mine)\nstep two\noutro\n",
+			theirs:        "# Skill\nintro\nstep one (theirs)\nstep two\noutro\n",
+			want:          "# Skill\nintro\n<<<<<<< local\nstep one (mine)\n||||||| installed\nstep one\n=======\nstep one (theirs)\n>>>>>>> new\nstep two\noutro\n",
+			wantConflicts: 1,
+		},
+		{
+			name:   "prefer ours",
+			ours:   "# Skill\nintro\nstep one (mine)\nstep two\noutro\n",
+			theirs: "# Skill\nintro\nstep one (theirs)\nstep two\noutro\nnew appendix\n",
+			prefer: PreferOurs,
+			want:   "# Skill\nintro\nstep one (mine)\nstep two\noutro\nnew appendix\n",
+		},
+		{
+			name:   "prefer theirs",
+			ours:   "# Skill\nintro\nstep one (mine)\nstep two\noutro\n",
+			theirs: "# Skill\nintro\nstep one (theirs)\nstep two\noutro\n",
+			prefer: PreferTheirs,
+			want:   "# Skill\nintro\nstep one (theirs)\nstep two\noutro\n",
+		},
+		{
+			name:          "conflict without trailing newline",
+			ours:          "# Skill\nintro\nstep one\nstep two\nmine",
+			theirs:        "# Skill\nintro\nstep one\nstep two\ntheirs",
+			want:          "# Skill\nintro\nstep one\nstep two\n<<<<<<< local\nmine\n||||||| installed\noutro\n=======\ntheirs\n>>>>>>> new\n",
+			wantConflicts: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, conflicts := Merge3([]byte(base), []byte(tt.ours), []byte(tt.theirs), tt.prefer, labels)
+			if string(got) != tt.want {
+				t.Errorf("Merge3() =\n%s\nwant\n%s", got, tt.want)
+			}
+			if conflicts != tt.wantConflicts {
+				t.Errorf("conflicts = %d, want %d", conflicts, tt.wantConflicts)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package skills
 
 import (
+	"bytes"
 	"fmt"
 	"io/fs"
 	"os"
@@ -19,62 +20,73 @@ func LoadSkill(name string) (string, error) {
 }
 
 // InstallSkills writes embedded skills to .hal/skills/ directory.
-// Existing files are preserved to keep user customizations.
+// Existing files are preserved to keep user customizations; use Upgrade to
+// bring them up to date. Installed versions are recorded in the skills lock.
 func InstallSkills(projectDir string) error {
-	skillsDir := filepath.Join(projectDir, template.HalDir, "skills")
-
-	return fs.WalkDir(skillsFS, ".", func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-		if path == "." {
-			return nil
-		}
-
-		destPath := filepath.Join(skillsDir, path)
-
-		if d.IsDir() {
-			return os.MkdirAll(destPath, 0755)
-		}
-
-		// Preserve existing files (user customizations)
-		if _, err := os.Stat(destPath); err == nil {
-			return nil
-		}
-
-		content, err := fs.ReadFile(skillsFS, path)
-		if err != nil {
-			return fmt.Errorf("failed to read embedded file %s: %w", path, err)
-		}
-
-		return os.WriteFile(destPath, content, 0644)
+	return installFiles(projectDir, KindSkill, func(local []byte, entry LockEntry, tracked bool) bool {
+		return false
 	})
 }
 
 // InstallCommands writes embedded commands to .hal/commands/ directory.
 // Commands are interactive tools users invoke directly in their agent.
+// Unmodified commands are overwritten with the embedded version; local edits
+// are kept for Upgrade to merge.
 // After installation, call LinkAllCommands to create engine-specific links.
 func InstallCommands(projectDir string) error {
 	commandsDir := filepath.Join(projectDir, template.HalDir, template.CommandsDir)
 	if err := os.MkdirAll(commandsDir, 0755); err != nil {
 		return fmt.Errorf("failed to create commands dir: %w", err)
 	}
+	return installFiles(projectDir, KindCommand, func(local []byte, entry LockEntry, tracked bool) bool {
+		// Untracked commands predate the lock, when hal always overwrote them.
+		return !tracked || string(local) == entry.Base
+	})
+}
 
-	for _, name := range CommandNames {
-		srcPath := "commands/" + name + ".md"
-		content, err := fs.ReadFile(commandsFS, srcPath)
-		if err != nil {
-			return fmt.Errorf("failed to read embedded command %s: %w", name, err)
+// installFiles writes the embedded files of kind that are missing, or that
+// overwrite approves, and records what it wrote in the skills lock. Existing
+// files identical to the embedded version are recorded as installed.
+func installFiles(projectDir, kind string, overwrite func(local []byte, entry LockEntry, tracked bool) bool) error {
+	halDir := filepath.Join(projectDir, template.HalDir)
+	lock, err := LoadLock(halDir)
+	if err != nil {
+		return err
+	}
+	files, err := embeddedFiles()
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		if f.Kind != kind {
+			continue
+		}
+		destPath := filepath.Join(halDir, filepath.FromSlash(f.Path))
+		entry, tracked := lock.Files[f.Path]
+		local, err := os.ReadFile(destPath)
+		switch {
+		case err == nil && bytes.Equal(local, f.Content):
+			if !tracked || entry.Hash != hashContent(f.Content) {
+				lock.record(f.Path, f.Content)
+			}
+			continue
+		case err == nil && !overwrite(local, entry, tracked):
+			continue
+		case err != nil && !os.IsNotExist(err):
+			return fmt.Errorf("failed to read %s: %w", f.Path, err)
 		}
 
-		destPath := filepath.Join(commandsDir, name+".md")
-		// Always overwrite (commands are hal-managed, not user-customized)
-		if err := os.WriteFile(destPath, content, 0644); err != nil {
-			return fmt.Errorf("failed to write command %s: %w", name, err)
+		if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(destPath, f.Content, 0644); err != nil {
+			return fmt.Errorf("failed to write %s %s: %w", kind, f.Name, err)
 		}
+		lock.record(f.Path, f.Content)
 	}
 
-	return nil
+	return SaveLock(halDir, lock)
 }
 
 // LinkAllCommands creates command links for all registered engines.
@@ -0,0 +1,394 @@
+package standards
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jywlabs/hal/internal/template"
+)
+
+// Rule kinds.
+const (
+	RuleForbid       = "forbid"
+	RuleRequire      = "require"
+	RuleForbidImport = "forbid_import"
+	RuleCompanion    = "companion"
+)
+
+// Rule is a machine-checkable rule declared under checks: in a standard's
+// front matter. Exactly one of Forbid, Require, ForbidImport and Companion
+// is set:
+//
+//	checks:
+//	  - forbid: 'fmt\.Print'            # regex added lines must not match
+//	    paths: ["internal/**/*.go"]
+//	  - require: '^// Package '         # regex every changed file must contain
+//	    paths: ["**/doc.go"]
+//	  - forbid_import: github.com/pkg/errors
+//	  - companion: "{dir}/{name}_test.go" # file every new file needs
+//	    paths: ["**/*.go"]
+//	    exclude: ["**/*_test.go"]
+//
+// Paths default to the standard's applies_to globs, then to every file.
+type Rule struct {
+	ID           string   `yaml:"id" json:"id,omitempty"`
+	Forbid       string   `yaml:"forbid" json:"forbid,omitempty"`
+	Require      string   `yaml:"require" json:"require,omitempty"`
+	ForbidImport string   `yaml:"forbid_import" json:"forbidImport,omitempty"`
+	Companion    string   `yaml:"companion" json:"companion,omitempty"`
+	Paths        []string `yaml:"paths" json:"paths,omitempty"`
+	Exclude      []string `yaml:"exclude" json:"exclude,omitempty"`
+	Message      string   `yaml:"message" json:"message,omitempty"`
+}
+
+// Kind returns the rule kind, or "" when no kind or several are set.
+func (r Rule) Kind() string {
+	kind := ""
+	for k, v := range map[string]string{
+		RuleForbid:       r.Forbid,
+		RuleRequire:      r.Require,
+		RuleForbidImport: r.ForbidImport,
+		RuleCompanion:    r.Companion,
+	} {
+		if strings.TrimSpace(v) == "" {
+			continue
+		}
+		if kind != "" {
+			return ""
+		}
+		kind = k
+	}
+	return kind
+}
+
+// Violation is a rule broken by the branch diff.
+type Violation struct {
+	Standard string `json:"standard"`
+	Rule     string `json:"rule"`
+	File     string `json:"file"`
+	Line     int    `json:"line,omitempty"`
+	Message  string `json:"message"`
+}
+
+// String formats the violation as "file:line: message (standard)".
+func (v Violation) String() string {
+	loc := v.File
+	if v.Line > 0 {
+		loc += ":" + strconv.Itoa(v.Line)
+	}
+	return fmt.Sprintf("%s: %s (%s)", loc, v.Message, v.Standard)
+}
+
+// File statuses in a DiffFile.
+const (
+	FileAdded    = "A"
+	FileModified = "M"
+	FileRenamed  = "R"
+	FileDeleted  = "D"
+)
+
+// DiffFile is one file changed on the branch with the lines it adds.
+type DiffFile struct {
+	Path   string
+	Status string
+	Added  []AddedLine
+}
+
+// AddedLine is a line added by the diff, numbered in the new file.
+type AddedLine struct {
+	Line int
+	Text string
+}
+
+// HasRules reports whether any standard declares checks.
+func HasRules(all []Standard) bool {
+	for _, s := range all {
+		if len(s.Checks) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckBranch evaluates the rules of the standards in repoDir/.hal against
+// the changes since the merge base with base, including uncommitted and
+// untracked files. Returns nil without running git when no standard
+// declares checks.
+func CheckBranch(repoDir, base string) ([]Violation, error) {
+	all, err := LoadAll(filepath.Join(repoDir, template.HalDir))
+	if err != nil {
+		return nil, err
+	}
+	if !HasRules(all) {
+		return nil, nil
+	}
+	files, err := BranchDiff(repoDir, base)
+	if err != nil {
+		return nil, err
+	}
+	return Check(repoDir, all, files)
+}
+
+// Check evaluates the rules of all against the changed files. repoDir is
+// used to read whole files for require rules and to look up companions.
+// Invalid rules are reported as errors.
+func Check(repoDir string, all []Standard, files []DiffFile) ([]Violation, error) {
+	var violations []Violation
+	for _, s := range all {
+		for i, rule := range s.Checks {
+			found, err := checkRule(repoDir, s, rule, files)
+			if err != nil {
+				return nil, fmt.Errorf("standard %s check %d: %w", s.Key, i+1, err)
+			}
+			violations = append(violations, found...)
+		}
+	}
+	return violations, nil
+}
+
+func checkRule(repoDir string, s Standard, rule Rule, files []DiffFile) ([]Violation, error) {
+	kind := rule.Kind()
+	if kind == "" {
+		return nil, fmt.Errorf("set exactly one of forbid, require, forbid_import and companion")
+	}
+	id := rule.ID
+	if id == "" {
+		id = kind
+	}
+	scope := rule.Paths
+	if len(scope) == 0 {
+		scope = s.AppliesTo
+	}
+	inScope, err := pathFilter(scope, rule.Exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	var pattern *regexp.Regexp
+	switch kind {
+	case RuleForbid:
+		pattern, err = regexp.Compile(rule.Forbid)
+	case RuleRequire:
+		pattern, err = regexp.Compile("(?m)" + rule.Require)
+	case RuleForbidImport:
+		pattern, err = importPattern(strings.TrimSpace(rule.ForbidImport))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s pattern: %w", kind, err)
+	}
+
+	violation := func(file string, line int, message string) Violation {
+		if rule.Message != "" {
+			message = rule.Message
+		}
+		return Violation{Standard: s.Key, Rule: id, File: file, Line: line, Message: message}
+	}
+
+	var violations []Violation
+	for _, f := range files {
+		if f.Status == FileDeleted || !inScope(f.Path) {
+			continue
+		}
+		switch kind {
+		case RuleForbid, RuleForbidImport:
+			for _, added := range f.Added {
+				if !pattern.MatchString(added.Text) {
+					continue
+				}
+				message := fmt.Sprintf("added line matches forbidden pattern %q", rule.Forbid)
+				if kind == RuleForbidImport {
+					message = fmt.Sprintf("imports forbidden package %s", rule.ForbidImport)
+				}
+				violations = append(violations, violation(f.Path, added.Line, message))
+			}
+		case RuleRequire:
+			data, err := os.ReadFile(filepath.Join(repoDir, filepath.FromSlash(f.Path)))
+			if err != nil {
+				continue
+			}
+			if !pattern.Match(data) {
+				violations = append(violations, violation(f.Path, 0, fmt.Sprintf("file does not contain required pattern %q", rule.Require)))
+			}
+		case RuleCompanion:
+			if f.Status != FileAdded {
+				continue
+			}
+			companion := companionPath(rule.Companion, f.Path)
+			if companion == f.Path {
+				continue
+			}
+			if _, err := os.Stat(filepath.Join(repoDir, filepath.FromSlash(companion))); err != nil {
+				violations = append(violations, violation(f.Path, 0, fmt.Sprintf("new file has no companion %s", companion)))
+			}
+		}
+	}
+	return violations, nil
+}
+
+// pathFilter returns a matcher for paths in scope (all paths when scope is
+// empty) and outside exclude.
+func pathFilter(scope, exclude []string) (func(string) bool, error) {
+	compile := func(globs []string) ([]*regexp.Regexp, error) {
+		var res []*regexp.Regexp
+		for _, g := range globs {
+			re, err := globRegexp(g)
+			if err != nil {
+				return nil, fmt.Errorf("invalid glob %q: %w", g, err)
+			}
+			res = append(res, re)
+		}
+		return res, nil
+	}
+	include, err := compile(scope)
+	if err != nil {
+		return nil, err
+	}
+	skip, err := compile(exclude)
+	if err != nil {
+		return nil, err
+	}
+	anyMatch := func(res []*regexp.Regexp, p string) bool {
+		for _, re := range res {
+			if re.MatchString(p) {
+				return true
+			}
+		}
+		return false
+	}
+	return func(p string) bool {
+		return (len(include) == 0 || anyMatch(include, p)) && !anyMatch(skip, p)
+	}, nil
+}
+
+// importPattern matches lines importing pkg or a package beneath it in Go,
+// JavaScript/TypeScript and Python sources.
+func importPattern(pkg string) (*regexp.Regexp, error) {
+	if pkg == "" {
+		return nil, fmt.Errorf("empty package")
+	}
+	q := regexp.QuoteMeta(pkg)
+	return regexp.Compile(
+		`^\s*(?:import\s+)?(?:[\w.]+\s+)?"` + q + `(?:/[^"]*)?"` + // Go import spec
+			`|\b(?:from|import|require\()\s*['"` + "`" + `]` + q + `(?:/[^'"` + "`" + `]*)?['"` + "`" + `]` + // JS/TS
+			`|^\s*(?:from|import)\s+` + q + `(?:[.\s]|$)`) // Python
+}
+
+// companionPath expands {dir}, {name}, {ext} and {path} in tmpl for file.
+func companionPath(tmpl, file string) string {
+	ext := path.Ext(file)
+	r := strings.NewReplacer(
+		"{dir}", path.Dir(file),
+		"{name}", strings.TrimSuffix(path.Base(file), ext),
+		"{ext}", ext,
+		"{path}", file,
+	)
+	return path.Clean(r.Replace(tmpl))
+}
+
+// BranchDiff returns the files changed in repoDir since the merge base with
+// base, including uncommitted changes and untracked files. Injectable for
+// testing.
+var BranchDiff = func(repoDir, base string) ([]DiffFile, error) {
+	mergeBase, err := gitOutput(repoDir, "merge-base", base, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	diff, err := gitOutput(repoDir, "diff", "--no-color", "--no-ext-diff", "--unified=0", "--find-renames", strings.TrimSpace(mergeBase))
+	if err != nil {
+		return nil, err
+	}
+	files := ParseDiff(diff)
+
+	untracked, err := gitOutput(repoDir, "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range strings.Split(strings.TrimSpace(untracked), "\n") {
+		if p == "" {
+			continue
+		}
+		f := DiffFile{Path: p, Status: FileAdded}
+		if data, err := os.ReadFile(filepath.Join(repoDir, filepath.FromSlash(p))); err == nil && !bytes.Contains(data, []byte{0}) {
+			for i, line := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
+				f.Added = append(f.Added, AddedLine{Line: i + 1, Text: line})
+			}
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+func gitOutput(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s failed: %w (stderr: %s)", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+var hunkHeaderPattern = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
+
+// ParseDiff parses unified git diff output into changed files and their
+// added lines.
+func ParseDiff(diff string) []DiffFile {
+	var files []DiffFile
+	var cur *DiffFile
+	line := 0
+	inHunk := false
+	scanner := bufio.NewScanner(strings.NewReader(diff))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		text := scanner.Text()
+		if strings.HasPrefix(text, "diff --git ") {
+			files = append(files, DiffFile{Status: FileModified})
+			cur = &files[len(files)-1]
+			inHunk = false
+			if i := strings.LastIndex(text, " b/"); i >= 0 {
+				cur.Path = text[i+3:]
+			}
+			continue
+		}
+		if cur == nil {
+			continue
+		}
+		if m := hunkHeaderPattern.FindStringSubmatch(text); m != nil {
+			line, _ = strconv.Atoi(m[1])
+			inHunk = true
+			continue
+		}
+		if inHunk {
+			switch {
+			case strings.HasPrefix(text, "+"):
+				cur.Added = append(cur.Added, AddedLine{Line: line, Text: text[1:]})
+				line++
+			case strings.HasPrefix(text, " "):
+				line++
+			}
+			continue
+		}
+		switch {
+		case strings.HasPrefix(text, "new file mode"):
+			cur.Status = FileAdded
+		case strings.HasPrefix(text, "deleted file mode"):
+			cur.Status = FileDeleted
+		case strings.HasPrefix(text, "rename to "):
+			cur.Status = FileRenamed
+			cur.Path = strings.TrimPrefix(text, "rename to ")
+		case strings.HasPrefix(text, "+++ b/"):
+			cur.Path = strings.TrimPrefix(text, "+++ b/")
+		}
+	}
+	return files
+}
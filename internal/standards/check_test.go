@@ -0,0 +1,147 @@
+package standards
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const sampleDiff = `diff --git a/internal/api/handler.go b/internal/api/handler.go
+new file mode 100644
+index 0000000..1111111
+--- /dev/null
++++ b/internal/api/handler.go
+@@ -0,0 +1,6 @@
++package api
++
++import (
++	"github.com/pkg/errors"
++)
++func Handle() { fmt.Println("hi") }
+diff --git a/internal/api/routes.go b/internal/api/routes.go
+index 2222222..3333333 100644
+--- a/internal/api/routes.go
++++ b/internal/api/routes.go
+@@ -10,0 +11,2 @@ func Routes() {
++	fmt.Println("route")
++	log.Print("ok")
+diff --git a/old.go b/old.go
+deleted file mode 100644
+--- a/old.go
++++ /dev/null
+@@ -1 +0,0 @@
+-package old
+`
+
+func TestParseDiff(t *testing.T) {
+	files := ParseDiff(sampleDiff)
+	if len(files) != 3 {
+		t.Fatalf("ParseDiff() = %d files, want 3", len(files))
+	}
+	if f := files[0]; f.Path != "internal/api/handler.go" || f.Status != FileAdded || len(f.Added) != 6 || f.Added[5].Line != 6 {
+		t.Errorf("handler.go = %+v", f)
+	}
+	if f := files[1]; f.Status != FileModified || len(f.Added) != 2 || f.Added[0].Line != 11 || f.Added[1].Line != 12 {
+		t.Errorf("routes.go = %+v", f)
+	}
+	if f := files[2]; f.Path != "old.go" || f.Status != FileDeleted || len(f.Added) != 0 {
+		t.Errorf("old.go = %+v", f)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	repo := t.TempDir()
+	writeStandard(t, repo, "internal/api/handler.go", "package api\n")
+	writeStandard(t, repo, "internal/api/routes.go", "// Package api serves HTTP.\npackage api\n")
+	files := ParseDiff(sampleDiff)
+
+	tests := []struct {
+		name    string
+		rule    Rule
+		applies []string
+		want    []string // file:line of each violation
+		wantErr string
+	}{
+		{name: "forbid", rule: Rule{Forbid: `fmt\.Print`}, want: []string{"internal/api/handler.go:6", "internal/api/routes.go:11"}},
+		{name: "forbid scoped by applies_to", rule: Rule{Forbid: `fmt\.Print`}, applies: []string{"**/routes.go"}, want: []string{"internal/api/routes.go:11"}},
+		{name: "forbid excluded", rule: Rule{Forbid: `fmt\.Print`, Exclude: []string{"internal/api/**"}}},
+		{name: "forbid import", rule: Rule{ForbidImport: "github.com/pkg/errors"}, want: []string{"internal/api/handler.go:4"}},
+		{name: "require", rule: Rule{Require: `^// Package `, Paths: []string{"internal/**/*.go"}}, want: []string{"internal/api/handler.go:0"}},
+		{name: "companion", rule: Rule{Companion: "{dir}/{name}_test.go", Paths: []string{"**/*.go"}}, want: []string{"internal/api/handler.go:0"}},
+		{name: "no kind", rule: Rule{Paths: []string{"**"}}, wantErr: "exactly one"},
+		{name: "two kinds", rule: Rule{Forbid: "x", Require: "y"}, wantErr: "exactly one"},
+		{name: "bad regex", rule: Rule{Forbid: "("}, wantErr: "invalid forbid pattern"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			std := Standard{Key: "go/style", AppliesTo: tt.applies, Checks: []Rule{tt.rule}}
+			violations, err := Check(repo, []Standard{std}, files)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Check() err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Check() error: %v", err)
+			}
+			var got []string
+			for _, v := range violations {
+				if v.Standard != "go/style" || v.Message == "" {
+					t.Errorf("violation = %+v", v)
+				}
+				got = append(got, filepath.ToSlash(v.File)+":"+strconv.Itoa(v.Line))
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("violations = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheck_CompanionPresent(t *testing.T) {
+	repo := t.TempDir()
+	writeStandard(t, repo, "internal/api/handler_test.go", "package api\n")
+	std := Standard{Key: "testing/coverage", Checks: []Rule{{Companion: "{dir}/{name}_test.go", Paths: []string{"**/*.go"}}}}
+
+	violations, err := Check(repo, []Standard{std}, ParseDiff(sampleDiff))
+	if err != nil {
+		t.Fatalf("Check() error: %v", err)
+	}
+	if len(violations) != 0 {
+		t.Fatalf("violations = %+v, want none when the companion exists", violations)
+	}
+}
+
+func TestCheckBranch_NoRulesSkipsGit(t *testing.T) {
+	repo := t.TempDir()
+	writeStandard(t, repo, ".hal/standards/global/naming.md", "Use camelCase.")
+
+	orig := BranchDiff
+	BranchDiff = func(string, string) ([]DiffFile, error) {
+		t.Fatal("BranchDiff should not run without rules")
+		return nil, nil
+	}
+	t.Cleanup(func() { BranchDiff = orig })
+
+	violations, err := CheckBranch(repo, "main")
+	if err != nil || violations != nil {
+		t.Fatalf("CheckBranch() = %v, %v; want nil, nil", violations, err)
+	}
+}
+
+func TestLoadAll_ParsesChecks(t *testing.T) {
+	halDir := t.TempDir()
+	writeStandard(t, halDir, "standards/go/errors.md",
+		"---\nchecks:\n  - id: no-pkg-errors\n    forbid_import: github.com/pkg/errors\n    message: use fmt.Errorf with %w\n---\nWrap errors with fmt.Errorf.")
+
+	all, err := LoadAll(halDir)
+	if err != nil {
+		t.Fatalf("LoadAll() error: %v", err)
+	}
+	if len(all) != 1 || len(all[0].Checks) != 1 || all[0].Checks[0].Kind() != RuleForbidImport || all[0].Checks[0].ID != "no-pkg-errors" {
+		t.Fatalf("LoadAll() = %+v, want one forbid_import check", all)
+	}
+}
@@ -0,0 +1,648 @@
+package standards
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jywlabs/hal/internal/template"
+	"gopkg.in/yaml.v3"
+)
+
+// LockFile records imported standards packs inside the standards directory.
+const LockFile = "standards-lock.yml"
+
+// Pack is a set of standards imported from another repository or directory.
+// Imported files are read-only by convention: hal standards update refuses
+// to overwrite local edits without --force.
+type Pack struct {
+	Source   string            `yaml:"source" json:"source"`
+	Ref      string            `yaml:"ref,omitempty" json:"ref,omitempty"`
+	Revision string            `yaml:"revision" json:"revision"`
+	Prefix   string            `yaml:"prefix,omitempty" json:"prefix,omitempty"`
+	Files    map[string]string `yaml:"files" json:"files"` // Path under .hal/standards -> sha256 of imported content
+}
+
+// Name identifies the pack as source[@ref].
+func (p Pack) Name() string {
+	if p.Ref == "" {
+		return p.Source
+	}
+	return p.Source + "@" + p.Ref
+}
+
+// ShortRevision returns the revision abbreviated for display.
+func (p Pack) ShortRevision() string {
+	rev := strings.TrimPrefix(p.Revision, "sha256:")
+	if len(rev) > 12 {
+		rev = rev[:12]
+	}
+	return rev
+}
+
+// Lock is the content of standards-lock.yml.
+type Lock struct {
+	Packs []Pack `yaml:"packs"`
+}
+
+// Origin returns the pack a standard key was imported from, or nil.
+func (l *Lock) Origin(key string) *Pack {
+	if l == nil {
+		return nil
+	}
+	for i := range l.Packs {
+		if _, ok := l.Packs[i].Files[key+".md"]; ok {
+			return &l.Packs[i]
+		}
+	}
+	return nil
+}
+
+// owner returns the index of the pack that owns path, or -1.
+func (l *Lock) owner(path string) int {
+	for i, p := range l.Packs {
+		if _, ok := p.Files[path]; ok {
+			return i
+		}
+	}
+	return -1
+}
+
+// LoadLock reads the lock file. A missing lock yields an empty lock.
+func LoadLock(halDir string) (*Lock, error) {
+	data, err := os.ReadFile(filepath.Join(halDir, template.StandardsDir, LockFile))
+	if os.IsNotExist(err) {
+		return &Lock{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", LockFile, err)
+	}
+	var lock Lock
+	if err := yaml.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", LockFile, err)
+	}
+	return &lock, nil
+}
+
+// SaveLock writes the lock file.
+func SaveLock(halDir string, lock *Lock) error {
+	data, err := yaml.Marshal(lock)
+	if err != nil {
+		return err
+	}
+	header := "# Standards packs imported with 'hal standards import'. Managed by hal; do not edit.\n"
+	path := filepath.Join(halDir, template.StandardsDir, LockFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append([]byte(header), data...), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", LockFile, err)
+	}
+	return nil
+}
+
+var schemeUserInfoPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]*://[^/]*$`)
+
+// ParseSource splits "<git-url|path>[@ref]" into source and ref. The "@" in
+// scp-style ("git@host:org/repo") and URL user info is not a ref separator.
+func ParseSource(arg string) (source, ref string) {
+	arg = strings.TrimSpace(arg)
+	i := strings.LastIndex(arg, "@")
+	if i <= 0 || i == len(arg)-1 {
+		return arg, ""
+	}
+	before, after := arg[:i], arg[i+1:]
+	if strings.Contains(after, ":") || strings.Contains(after, "/") && !strings.Contains(before, "/") || schemeUserInfoPattern.MatchString(before) {
+		return arg, ""
+	}
+	return before, after
+}
+
+// normalizePrefix validates --prefix and ensures a trailing slash.
+func normalizePrefix(prefix string) (string, error) {
+	prefix = strings.Trim(filepath.ToSlash(strings.TrimSpace(prefix)), "/")
+	if prefix == "" {
+		return "", nil
+	}
+	for _, part := range strings.Split(prefix, "/") {
+		if part == "" || part == "." || part == ".." {
+			return "", fmt.Errorf("invalid prefix %q", prefix)
+		}
+	}
+	return prefix + "/", nil
+}
+
+// Fetched is a pack source checked out at a revision.
+type Fetched struct {
+	Dir      string // Directory holding the checkout
+	Revision string // Commit SHA, or sha256 of the content for plain directories
+	Cleanup  func()
+}
+
+// FetchPack checks out source at ref. Git URLs and repositories are cloned;
+// plain directories are read in place and need an empty ref. Injectable for
+// testing.
+var FetchPack = func(source, ref string) (*Fetched, error) {
+	if info, err := os.Stat(source); err == nil && info.IsDir() {
+		if !isGitRepoRoot(source) {
+			if ref != "" {
+				return nil, fmt.Errorf("%s is not a git repository; @%s cannot be resolved", source, ref)
+			}
+			files, err := readPackFiles(source)
+			if err != nil {
+				return nil, err
+			}
+			return &Fetched{Dir: source, Revision: contentRevision(files), Cleanup: func() {}}, nil
+		}
+	}
+
+	tmp, err := os.MkdirTemp("", "hal-standards-*")
+	if err != nil {
+		return nil, err
+	}
+	cleanup := func() { os.RemoveAll(tmp) }
+	if _, err := gitOutput("", "clone", "--quiet", source, tmp); err != nil {
+		cleanup()
+		return nil, err
+	}
+	if ref != "" {
+		if _, err := gitOutput(tmp, "checkout", "--quiet", ref); err != nil {
+			cleanup()
+			return nil, err
+		}
+	}
+	rev, err := gitOutput(tmp, "rev-parse", "HEAD")
+	if err != nil {
+		cleanup()
+		return nil, err
+	}
+	return &Fetched{Dir: tmp, Revision: strings.TrimSpace(rev), Cleanup: cleanup}, nil
+}
+
+// isGitRepoRoot reports whether dir is the top of a git worktree or a bare
+// repository. A plain directory nested inside some other worktree is not:
+// cloning it would fail, so it is read in place instead.
+func isGitRepoRoot(dir string) bool {
+	if out, err := gitOutput(dir, "rev-parse", "--is-bare-repository"); err == nil && strings.TrimSpace(out) == "true" {
+		return true
+	}
+	top, err := gitOutput(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return false
+	}
+	return sameDir(strings.TrimSpace(top), dir)
+}
+
+// sameDir reports whether a and b name the same directory once made
+// absolute and symlinks are resolved.
+func sameDir(a, b string) bool {
+	resolve := func(p string) string {
+		if abs, err := filepath.Abs(p); err == nil {
+			p = abs
+		}
+		if real, err := filepath.EvalSymlinks(p); err == nil {
+			p = real
+		}
+		return filepath.Clean(p)
+	}
+	return resolve(a) == resolve(b)
+}
+
+// packRoot returns the directory in a checkout that holds its standards:
+// .hal/standards, then standards/, then the checkout itself.
+func packRoot(dir string) (string, bool) {
+	for _, sub := range []string{filepath.Join(template.HalDir, template.StandardsDir), template.StandardsDir} {
+		if info, err := os.Stat(filepath.Join(dir, sub)); err == nil && info.IsDir() {
+			return filepath.Join(dir, sub), false
+		}
+	}
+	return dir, true
+}
+
+// repoRootDocs are markdown files at a repository root that are not standards.
+var repoRootDocs = map[string]bool{"README.md": true, "CHANGELOG.md": true, "LICENSE.md": true, "CONTRIBUTING.md": true}
+
+// readPackFiles returns the .md standards of a checkout keyed by path
+// relative to the pack root.
+func readPackFiles(dir string) (map[string][]byte, error) {
+	root, isRepoRoot := packRoot(dir)
+	files := map[string][]byte{}
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".md" {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, path)
+		rel = filepath.ToSlash(rel)
+		if isRepoRoot && repoRootDocs[rel] {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		files[rel] = data
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read standards pack: %w", err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no .md standards found in pack")
+	}
+	return files, nil
+}
+
+func contentRevision(files map[string][]byte) string {
+	h := sha256.New()
+	for _, name := range sortedKeys(files) {
+		fmt.Fprintf(h, "%s\x00%x\x00", name, sha256.Sum256(files[name]))
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))
+}
+
+func hashContent(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ImportOptions controls hal standards import.
+type ImportOptions struct {
+	Prefix string // Folder under .hal/standards to import into, e.g. "org/"
+	Force  bool   // Overwrite existing files not owned by the pack
+}
+
+// Import copies the standards of a pack into .hal/standards and records it
+// in the lock file.
+func Import(halDir, arg string, opts ImportOptions) (*Pack, error) {
+	source, ref := ParseSource(arg)
+	if source == "" {
+		return nil, fmt.Errorf("source is required")
+	}
+	prefix, err := normalizePrefix(opts.Prefix)
+	if err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(source); err == nil && info.IsDir() {
+		if abs, err := filepath.Abs(source); err == nil {
+			source = abs
+		}
+	}
+
+	lock, err := LoadLock(halDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range lock.Packs {
+		if p.Source == source && p.Prefix == prefix {
+			return nil, fmt.Errorf("%s is already imported; use 'hal standards update'", p.Name())
+		}
+	}
+
+	fetched, err := FetchPack(source, ref)
+	if err != nil {
+		return nil, err
+	}
+	defer fetched.Cleanup()
+	upstream, err := readPackFiles(fetched.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	standardsDir := filepath.Join(halDir, template.StandardsDir)
+	pack := Pack{Source: source, Ref: ref, Revision: fetched.Revision, Prefix: prefix, Files: map[string]string{}}
+	var conflicts []string
+	for _, rel := range sortedKeys(upstream) {
+		dest := prefix + rel
+		if i := lock.owner(dest); i >= 0 {
+			conflicts = append(conflicts, fmt.Sprintf("%s (owned by %s)", dest, lock.Packs[i].Name()))
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(standardsDir, filepath.FromSlash(dest))); err == nil && !opts.Force {
+			conflicts = append(conflicts, dest)
+		}
+	}
+	if len(conflicts) > 0 {
+		return nil, &ConflictError{Paths: conflicts, Hint: "use --force to overwrite local files or --prefix to import elsewhere"}
+	}
+
+	for _, rel := range sortedKeys(upstream) {
+		dest := prefix + rel
+		if err := writeStandardFile(standardsDir, dest, upstream[rel]); err != nil {
+			return nil, err
+		}
+		pack.Files[dest] = hashContent(upstream[rel])
+	}
+	lock.Packs = append(lock.Packs, pack)
+	if err := SaveLock(halDir, lock); err != nil {
+		return nil, err
+	}
+	return &pack, nil
+}
+
+func writeStandardFile(standardsDir, rel string, data []byte) error {
+	path := filepath.Join(standardsDir, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", rel, err)
+	}
+	return nil
+}
+
+// ConflictError lists files an import or update would overwrite.
+type ConflictError struct {
+	Paths []string
+	Hint  string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("refusing to overwrite %s; %s", strings.Join(e.Paths, ", "), e.Hint)
+}
+
+// File change kinds in an update.
+const (
+	ChangeAdded    = "added"
+	ChangeModified = "modified"
+	ChangeRemoved  = "removed"
+)
+
+// FileChange is a file an update adds, modifies or removes.
+type FileChange struct {
+	Path string `json:"path"`
+	Kind string `json:"kind"`
+	Diff string `json:"diff,omitempty"`
+}
+
+// UpdateOptions controls hal standards update.
+type UpdateOptions struct {
+	Force  bool // Overwrite local edits to files that changed upstream
+	DryRun bool // Show changes without writing them
+}
+
+// UpdateResult is the outcome of updating one pack.
+type UpdateResult struct {
+	Pack        Pack         `json:"pack"`
+	OldRevision string       `json:"oldRevision"`
+	Changes     []FileChange `json:"changes"`
+	LocalEdits  []string     `json:"localEdits,omitempty"` // Files edited locally that upstream also changed
+	Applied     bool         `json:"applied"`
+}
+
+// UpToDate reports whether the pack had no upstream changes.
+func (r UpdateResult) UpToDate() bool {
+	return r.OldRevision == r.Pack.Revision && len(r.Changes) == 0
+}
+
+// Update fetches newer revisions of the packs matching selectors (source,
+// source@ref or prefix; all packs when empty) and applies their changes.
+// Files edited locally that also changed upstream block a pack's update
+// unless opts.Force is set; the blocked result is returned along with a
+// *ConflictError. Local edits to files upstream did not change are kept,
+// even with opts.Force.
+func Update(halDir string, selectors []string, opts UpdateOptions) ([]UpdateResult, error) {
+	lock, err := LoadLock(halDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(lock.Packs) == 0 {
+		return nil, fmt.Errorf("no standards packs imported; use 'hal standards import'")
+	}
+
+	selected, err := selectPacks(lock, selectors)
+	if err != nil {
+		return nil, err
+	}
+
+	standardsDir := filepath.Join(halDir, template.StandardsDir)
+	var results []UpdateResult
+	var conflicts []string
+	for _, i := range selected {
+		pack := lock.Packs[i]
+		fetched, err := FetchPack(pack.Source, pack.Ref)
+		if err != nil {
+			return results, fmt.Errorf("%s: %w", pack.Name(), err)
+		}
+		upstream, err := readPackFiles(fetched.Dir)
+		fetched.Cleanup()
+		if err != nil {
+			return results, fmt.Errorf("%s: %w", pack.Name(), err)
+		}
+
+		result := UpdateResult{OldRevision: pack.Revision, Changes: []FileChange{}}
+		local := map[string][]byte{}
+		edited := map[string]bool{}
+		for path, hash := range pack.Files {
+			data, err := os.ReadFile(filepath.Join(standardsDir, filepath.FromSlash(path)))
+			edited[path] = err != nil || hashContent(data) != hash
+			if err == nil {
+				local[path] = data
+			}
+		}
+
+		// A file conflicts only when it was edited locally and also changed
+		// upstream; local edits to files upstream left alone are kept.
+		next := Pack{Source: pack.Source, Ref: pack.Ref, Revision: fetched.Revision, Prefix: pack.Prefix, Files: map[string]string{}}
+		for _, rel := range sortedKeys(upstream) {
+			dest := pack.Prefix + rel
+			hash := hashContent(upstream[rel])
+			next.Files[dest] = hash
+			old, had := local[dest]
+			switch {
+			case pack.Files[dest] == "":
+				if owner := lock.owner(dest); owner >= 0 && owner != i {
+					return results, fmt.Errorf("%s: %s is owned by %s", pack.Name(), dest, lock.Packs[owner].Name())
+				}
+				if data, err := os.ReadFile(filepath.Join(standardsDir, filepath.FromSlash(dest))); err == nil {
+					if string(data) == string(upstream[rel]) {
+						continue
+					}
+					result.LocalEdits = append(result.LocalEdits, dest)
+				}
+				result.Changes = append(result.Changes, FileChange{Path: dest, Kind: ChangeAdded})
+			case hash == pack.Files[dest] || had && string(old) == string(upstream[rel]):
+				// Unchanged upstream, or the local copy already matches.
+			default:
+				if edited[dest] {
+					result.LocalEdits = append(result.LocalEdits, dest)
+				}
+				result.Changes = append(result.Changes, FileChange{Path: dest, Kind: ChangeModified, Diff: LineDiff(dest, old, upstream[rel])})
+			}
+		}
+		for _, path := range sortedKeys(pack.Files) {
+			if _, ok := next.Files[path]; !ok {
+				if edited[path] {
+					if _, ok := local[path]; ok {
+						result.LocalEdits = append(result.LocalEdits, path)
+					}
+				}
+				result.Changes = append(result.Changes, FileChange{Path: path, Kind: ChangeRemoved})
+			}
+		}
+		sort.Strings(result.LocalEdits)
+		result.Pack = next
+
+		if len(result.LocalEdits) > 0 && !opts.Force {
+			conflicts = append(conflicts, result.LocalEdits...)
+			results = append(results, result)
+			continue
+		}
+		if !opts.DryRun {
+			for _, c := range result.Changes {
+				if c.Kind == ChangeRemoved {
+					os.Remove(filepath.Join(standardsDir, filepath.FromSlash(c.Path)))
+					continue
+				}
+				rel := strings.TrimPrefix(c.Path, pack.Prefix)
+				if err := writeStandardFile(standardsDir, c.Path, upstream[rel]); err != nil {
+					return results, err
+				}
+			}
+			lock.Packs[i] = next
+			result.Applied = true
+		}
+		results = append(results, result)
+	}
+
+	if !opts.DryRun {
+		if err := SaveLock(halDir, lock); err != nil {
+			return results, err
+		}
+	}
+	if len(conflicts) > 0 {
+		return results, &ConflictError{Paths: conflicts, Hint: "these imported files were edited locally and changed upstream; use --force to discard the edits"}
+	}
+	return results, nil
+}
+
+// selectPacks returns the indexes of the packs matching selectors.
+func selectPacks(lock *Lock, selectors []string) ([]int, error) {
+	if len(selectors) == 0 {
+		indexes := make([]int, len(lock.Packs))
+		for i := range indexes {
+			indexes[i] = i
+		}
+		return indexes, nil
+	}
+	var indexes []int
+	for _, sel := range selectors {
+		found := false
+		prefix, _ := normalizePrefix(sel)
+		for i, p := range lock.Packs {
+			if p.Source == sel || p.Name() == sel || (prefix != "" && p.Prefix == prefix) {
+				indexes = append(indexes, i)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("no imported pack matches %q", sel)
+		}
+	}
+	return indexes, nil
+}
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+// LineDiff returns a unified-style line diff of a file's old and new content,
+// or "" when they are equal. Standards are small, so a plain LCS table is
+// cheap enough.
+func LineDiff(path string, old, new []byte) string {
+	if string(old) == string(new) {
+		return ""
+	}
+	a := splitLines(old)
+	b := splitLines(new)
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type op struct {
+		kind byte // ' ', '-' or '+'
+		text string
+	}
+	var ops []op
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, op{' ', a[i]})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, op{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, op{'+', b[j]})
+			j++
+		}
+	}
+
+	// Keep changed lines and up to diffContext unchanged lines around them.
+	keep := make([]bool, len(ops))
+	for k, o := range ops {
+		if o.kind == ' ' {
+			continue
+		}
+		for c := max(0, k-diffContext); c <= min(len(ops)-1, k+diffContext); c++ {
+			keep[c] = true
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- a/%s\n+++ b/%s\n", path, path)
+	for k, o := range ops {
+		if !keep[k] {
+			continue
+		}
+		if k > 0 && !keep[k-1] {
+			sb.WriteString("@@\n")
+		}
+		sb.WriteByte(o.kind)
+		sb.WriteString(o.text)
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+func splitLines(data []byte) []string {
+	if len(data) == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
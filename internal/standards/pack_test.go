@@ -0,0 +1,239 @@
+package standards
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jywlabs/hal/internal/template"
+)
+
+func TestParseSource(t *testing.T) {
+	tests := []struct {
+		arg        string
+		wantSource string
+		wantRef    string
+	}{
+		{"../shared", "../shared", ""},
+		{"../shared@v1.2.0", "../shared", "v1.2.0"},
+		{"https://github.com/org/standards.git", "https://github.com/org/standards.git", ""},
+		{"https://github.com/org/standards.git@main", "https://github.com/org/standards.git", "main"},
+		{"https://user@github.com/org/standards.git", "https://user@github.com/org/standards.git", ""},
+		{"git@github.com:org/standards.git", "git@github.com:org/standards.git", ""},
+		{"git@github.com:org/standards.git@release/v2", "git@github.com:org/standards.git", "release/v2"},
+		{"pack@", "pack@", ""},
+	}
+	for _, tt := range tests {
+		source, ref := ParseSource(tt.arg)
+		if source != tt.wantSource || ref != tt.wantRef {
+			t.Errorf("ParseSource(%q) = (%q, %q), want (%q, %q)", tt.arg, source, ref, tt.wantSource, tt.wantRef)
+		}
+	}
+}
+
+func TestLineDiff(t *testing.T) {
+	if got := LineDiff("a.md", []byte("x\n"), []byte("x\n")); got != "" {
+		t.Fatalf("LineDiff(equal) = %q, want empty", got)
+	}
+	got := LineDiff("go/errors.md", []byte("one\ntwo\nthree\n"), []byte("one\n2\nthree\nfour\n"))
+	want := "--- a/go/errors.md\n+++ b/go/errors.md\n one\n-two\n+2\n three\n+four\n"
+	if got != want {
+		t.Fatalf("LineDiff() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+// stubFetchPack serves pack checkouts from local directories keyed by source.
+func stubFetchPack(t *testing.T, revisions map[string]string) {
+	t.Helper()
+	orig := FetchPack
+	t.Cleanup(func() { FetchPack = orig })
+	FetchPack = func(source, ref string) (*Fetched, error) {
+		rev, ok := revisions[source]
+		if !ok {
+			return nil, errors.New("unknown source " + source)
+		}
+		return &Fetched{Dir: source, Revision: rev, Cleanup: func() {}}, nil
+	}
+}
+
+func TestImportAndUpdate(t *testing.T) {
+	pack := t.TempDir()
+	writeStandard(t, pack, "standards/go/errors.md", "Wrap errors with %w.\n")
+	writeStandard(t, pack, "standards/testing/tables.md", "Use table tests.\n")
+	writeStandard(t, pack, "README.md", "not a standard")
+	revisions := map[string]string{pack: "aaaa"}
+	stubFetchPack(t, revisions)
+
+	halDir := filepath.Join(t.TempDir(), template.HalDir)
+	standardsDir := filepath.Join(halDir, template.StandardsDir)
+
+	imported, err := Import(halDir, pack+"@v1", ImportOptions{Prefix: "org"})
+	if err != nil {
+		t.Fatalf("Import() error: %v", err)
+	}
+	if imported.Ref != "v1" || imported.Prefix != "org/" || len(imported.Files) != 2 {
+		t.Fatalf("Import() = %+v", imported)
+	}
+	if data, err := os.ReadFile(filepath.Join(standardsDir, "org", "go", "errors.md")); err != nil || string(data) != "Wrap errors with %w.\n" {
+		t.Fatalf("imported file = %q, %v", data, err)
+	}
+
+	lock, err := LoadLock(halDir)
+	if err != nil {
+		t.Fatalf("LoadLock() error: %v", err)
+	}
+	if origin := lock.Origin("org/go/errors"); origin == nil || origin.Revision != "aaaa" {
+		t.Fatalf("Origin() = %+v", origin)
+	}
+	if _, err := Import(halDir, pack, ImportOptions{Prefix: "org/"}); err == nil || !strings.Contains(err.Error(), "already imported") {
+		t.Fatalf("re-Import() error = %v, want already imported", err)
+	}
+
+	// Upstream changes one file, removes one and adds one.
+	writeStandard(t, pack, "standards/go/errors.md", "Wrap errors with %w.\nNever panic.\n")
+	os.Remove(filepath.Join(pack, "standards", "testing", "tables.md"))
+	writeStandard(t, pack, "standards/ci/lint.md", "Run golangci-lint.\n")
+	revisions[pack] = "bbbb"
+
+	results, err := Update(halDir, nil, UpdateOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("Update(dry run) error: %v", err)
+	}
+	if len(results) != 1 || results[0].Applied || len(results[0].Changes) != 3 {
+		t.Fatalf("Update(dry run) = %+v", results)
+	}
+	kinds := map[string]string{}
+	for _, c := range results[0].Changes {
+		kinds[c.Path] = c.Kind
+	}
+	if kinds["org/ci/lint.md"] != ChangeAdded || kinds["org/go/errors.md"] != ChangeModified || kinds["org/testing/tables.md"] != ChangeRemoved {
+		t.Fatalf("changes = %v", kinds)
+	}
+
+	// A local edit blocks the update until --force.
+	writeStandard(t, standardsDir, "org/go/errors.md", "Local tweak.\n")
+	results, err = Update(halDir, []string{"org"}, UpdateOptions{})
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) || len(conflict.Paths) != 1 || conflict.Paths[0] != "org/go/errors.md" {
+		t.Fatalf("Update() error = %v, want conflict on org/go/errors.md", err)
+	}
+	if len(results) != 1 || results[0].Applied {
+		t.Fatalf("Update() = %+v, want unapplied result", results)
+	}
+	if data, _ := os.ReadFile(filepath.Join(standardsDir, "org", "go", "errors.md")); string(data) != "Local tweak.\n" {
+		t.Fatalf("local edit overwritten: %q", data)
+	}
+
+	results, err = Update(halDir, nil, UpdateOptions{Force: true})
+	if err != nil {
+		t.Fatalf("Update(force) error: %v", err)
+	}
+	if !results[0].Applied || results[0].OldRevision != "aaaa" || results[0].Pack.Revision != "bbbb" {
+		t.Fatalf("Update(force) = %+v", results[0])
+	}
+	if _, err := os.Stat(filepath.Join(standardsDir, "org", "testing", "tables.md")); !os.IsNotExist(err) {
+		t.Errorf("removed standard still present: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(standardsDir, "org", "ci", "lint.md")); err != nil {
+		t.Errorf("added standard missing: %v", err)
+	}
+
+	results, err = Update(halDir, nil, UpdateOptions{})
+	if err != nil || !results[0].UpToDate() {
+		t.Fatalf("Update(again) = %+v, %v; want up to date", results, err)
+	}
+}
+
+func TestUpdate_KeepsLocalEditsUpstreamDidNotChange(t *testing.T) {
+	pack := t.TempDir()
+	writeStandard(t, pack, "go/errors.md", "Wrap errors.\n")
+	writeStandard(t, pack, "testing/tables.md", "Use table tests.\n")
+	revisions := map[string]string{pack: "aaaa"}
+	stubFetchPack(t, revisions)
+
+	halDir := filepath.Join(t.TempDir(), template.HalDir)
+	standardsDir := filepath.Join(halDir, template.StandardsDir)
+	if _, err := Import(halDir, pack, ImportOptions{}); err != nil {
+		t.Fatalf("Import() error: %v", err)
+	}
+
+	// Upstream changes errors.md; tables.md is only edited locally.
+	writeStandard(t, pack, "go/errors.md", "Wrap errors with %w.\n")
+	revisions[pack] = "bbbb"
+	writeStandard(t, standardsDir, "testing/tables.md", "Use table tests with t.Run.\n")
+
+	for _, force := range []bool{false, true} {
+		results, err := Update(halDir, nil, UpdateOptions{Force: force})
+		if err != nil {
+			t.Fatalf("Update(force=%v) error = %v, want no conflict", force, err)
+		}
+		if len(results[0].LocalEdits) != 0 {
+			t.Errorf("Update(force=%v) localEdits = %v, want none", force, results[0].LocalEdits)
+		}
+		if data, _ := os.ReadFile(filepath.Join(standardsDir, "testing", "tables.md")); string(data) != "Use table tests with t.Run.\n" {
+			t.Errorf("Update(force=%v) overwrote local edit: %q", force, data)
+		}
+		if data, _ := os.ReadFile(filepath.Join(standardsDir, "go", "errors.md")); string(data) != "Wrap errors with %w.\n" {
+			t.Errorf("Update(force=%v) errors.md = %q, want upstream change", force, data)
+		}
+	}
+}
+
+func TestImport_Conflicts(t *testing.T) {
+	pack := t.TempDir()
+	writeStandard(t, pack, "go/errors.md", "Wrap errors.\n")
+	stubFetchPack(t, map[string]string{pack: "aaaa"})
+
+	halDir := filepath.Join(t.TempDir(), template.HalDir)
+	writeStandard(t, filepath.Join(halDir, template.StandardsDir), "go/errors.md", "Ours.\n")
+
+	_, err := Import(halDir, pack, ImportOptions{})
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) || conflict.Paths[0] != "go/errors.md" {
+		t.Fatalf("Import() error = %v, want conflict", err)
+	}
+	if _, err := Import(halDir, pack, ImportOptions{Force: true}); err != nil {
+		t.Fatalf("Import(force) error: %v", err)
+	}
+}
+
+func TestFetchPack_PlainDirectory(t *testing.T) {
+	dir := t.TempDir()
+	writeStandard(t, dir, "go/errors.md", "Wrap errors.\n")
+
+	fetched, err := FetchPack(dir, "")
+	if err != nil {
+		t.Fatalf("FetchPack() error: %v", err)
+	}
+	defer fetched.Cleanup()
+	if fetched.Dir != dir || !strings.HasPrefix(fetched.Revision, "sha256:") {
+		t.Fatalf("FetchPack() = %+v", fetched)
+	}
+	if _, err := FetchPack(dir, "v1"); err == nil {
+		t.Fatal("FetchPack(dir@ref) error = nil, want error for non-git directory")
+	}
+}
+
+func TestFetchPack_PlainDirectoryInsideWorktree(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := t.TempDir()
+	if _, err := gitOutput(repo, "init", "--quiet"); err != nil {
+		t.Fatalf("git init: %v", err)
+	}
+	dir := filepath.Join(repo, "shared", "standards")
+	writeStandard(t, dir, "go/errors.md", "Wrap errors.\n")
+
+	fetched, err := FetchPack(dir, "")
+	if err != nil {
+		t.Fatalf("FetchPack() error: %v", err)
+	}
+	defer fetched.Cleanup()
+	if fetched.Dir != dir || !strings.HasPrefix(fetched.Revision, "sha256:") {
+		t.Fatalf("FetchPack() = %+v, want plain directory read in place", fetched)
+	}
+}
@@ -0,0 +1,430 @@
+package standards
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jywlabs/hal/internal/engine"
+	"github.com/jywlabs/hal/internal/template"
+	"gopkg.in/yaml.v3"
+)
+
+// IndexFile is the standards catalog inside the standards directory.
+const IndexFile = "index.yml"
+
+// Standard is one .hal/standards/*.md file with its selection metadata.
+// Metadata comes from the file's front matter and its index.yml entry;
+// front matter wins when both set a field.
+type Standard struct {
+	Key         string   `json:"key"` // Path under .hal/standards without .md, e.g. "engine/adapter"
+	Description string   `json:"description,omitempty"`
+	AppliesTo   []string `json:"appliesTo,omitempty"` // Path globs, e.g. "internal/engine/**"
+	Tags        []string `json:"tags,omitempty"`
+	Priority    int      `json:"priority,omitempty"` // Higher priorities are injected first
+	Checks      []Rule   `json:"checks,omitempty"`   // Rules hal standards check enforces
+	Content     string   `json:"-"`                  // Body without front matter
+}
+
+// Scoped reports whether the standard only applies to some stories.
+// Standards without applies_to or tags apply everywhere.
+func (s Standard) Scoped() bool {
+	return len(s.AppliesTo) > 0 || len(s.Tags) > 0
+}
+
+// meta is the selection metadata a standard may declare in front matter or
+// in its index.yml entry.
+type meta struct {
+	Description string   `yaml:"description"`
+	AppliesTo   []string `yaml:"applies_to"`
+	Tags        []string `yaml:"tags"`
+	Priority    *int     `yaml:"priority"`
+	Checks      []Rule   `yaml:"checks"`
+}
+
+// LoadAll reads every standard with its metadata, sorted by key.
+// Returns nil (not error) if no standards exist.
+func LoadAll(halDir string) ([]Standard, error) {
+	standardsDir := filepath.Join(halDir, template.StandardsDir)
+	if _, err := os.Stat(standardsDir); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	index := loadIndexMeta(standardsDir)
+
+	var all []Standard
+	err := filepath.WalkDir(standardsDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".md" {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read standard %s: %w", path, err)
+		}
+
+		rel, _ := filepath.Rel(standardsDir, path)
+		key := strings.TrimSuffix(filepath.ToSlash(rel), ".md")
+
+		front, body := splitFrontMatter(string(data))
+		body = strings.TrimSpace(body)
+		if body == "" {
+			return nil
+		}
+
+		s := Standard{Key: key, Content: body}
+		for _, m := range []meta{index[key], front} {
+			if m.Description != "" {
+				s.Description = m.Description
+			}
+			if len(m.AppliesTo) > 0 {
+				s.AppliesTo = m.AppliesTo
+			}
+			if len(m.Tags) > 0 {
+				s.Tags = m.Tags
+			}
+			if m.Priority != nil {
+				s.Priority = *m.Priority
+			}
+			if len(m.Checks) > 0 {
+				s.Checks = m.Checks
+			}
+		}
+		all = append(all, s)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load standards: %w", err)
+	}
+
+	sort.Slice(all, func(i, j int) bool { return all[i].Key < all[j].Key })
+	return all, nil
+}
+
+// loadIndexMeta reads index.yml entries keyed by standard key. The index is
+// a catalog, so a missing or malformed index is ignored.
+func loadIndexMeta(standardsDir string) map[string]meta {
+	data, err := os.ReadFile(filepath.Join(standardsDir, IndexFile))
+	if err != nil {
+		return nil
+	}
+	var folders map[string]map[string]meta
+	if err := yaml.Unmarshal(data, &folders); err != nil {
+		return nil
+	}
+	index := make(map[string]meta)
+	for folder, files := range folders {
+		for file, m := range files {
+			index[folder+"/"+file] = m
+		}
+	}
+	return index
+}
+
+// splitFrontMatter separates a leading "---" YAML block from the body.
+// Content without valid front matter is returned unchanged.
+func splitFrontMatter(content string) (meta, string) {
+	trimmed := strings.TrimPrefix(content, "\ufeff")
+	if !strings.HasPrefix(trimmed, "---\n") && !strings.HasPrefix(trimmed, "---\r\n") {
+		return meta{}, content
+	}
+	rest := trimmed[strings.Index(trimmed, "\n")+1:]
+	end := strings.Index(rest, "\n---")
+	if end < 0 {
+		return meta{}, content
+	}
+	var m meta
+	if err := yaml.Unmarshal([]byte(rest[:end]), &m); err != nil {
+		return meta{}, content
+	}
+	body := rest[end+len("\n---"):]
+	if i := strings.Index(body, "\n"); i >= 0 {
+		body = body[i+1:]
+	} else {
+		body = ""
+	}
+	return m, body
+}
+
+// Context is the work standards are selected for: the words of a story and
+// the file paths it mentions.
+type Context struct {
+	Text  string
+	Paths []string
+}
+
+// Empty reports whether there is nothing to select against.
+func (c Context) Empty() bool {
+	return strings.TrimSpace(c.Text) == "" && len(c.Paths) == 0
+}
+
+var pathTokenPattern = regexp.MustCompile("[A-Za-z0-9_.*/-]+")
+
+// NewContext builds a selection context from story text such as the title,
+// description, acceptance criteria and notes.
+func NewContext(texts ...string) Context {
+	text := strings.Join(texts, "\n")
+	ctx := Context{Text: text}
+	seen := map[string]bool{}
+	for _, token := range pathTokenPattern.FindAllString(text, -1) {
+		token = strings.Trim(token, ".-")
+		token = strings.TrimPrefix(token, "./")
+		if !looksLikePath(token) || seen[token] {
+			continue
+		}
+		seen[token] = true
+		ctx.Paths = append(ctx.Paths, token)
+	}
+	return ctx
+}
+
+// StoryContext returns the selection context for a story: its title,
+// description, acceptance criteria and notes. A nil story yields an empty
+// context, which selects every standard.
+func StoryContext(story *engine.UserStory) Context {
+	if story == nil {
+		return Context{}
+	}
+	texts := append([]string{story.Title, story.Description}, story.AcceptanceCriteria...)
+	return NewContext(append(texts, story.Notes)...)
+}
+
+// looksLikePath reports whether a token names a file or directory:
+// it contains a slash or ends in a file extension.
+func looksLikePath(token string) bool {
+	if strings.Contains(token, "/") {
+		return strings.Trim(token, "/*") != ""
+	}
+	ext := filepath.Ext(token)
+	base := strings.TrimSuffix(token, ext)
+	return len(ext) >= 2 && len(ext) <= 6 && len(base) >= 2 && !strings.ContainsAny(base, ".*")
+}
+
+// Selected is a standard chosen for injection and why.
+type Selected struct {
+	Standard
+	Reasons []string `json:"reasons"`
+	Size    int      `json:"size"`
+	score   int
+}
+
+// Excluded is a standard left out of the injection and why.
+type Excluded struct {
+	Key    string `json:"key"`
+	Reason string `json:"reason"`
+}
+
+// Selection is the outcome of choosing standards for a context.
+type Selection struct {
+	Included []Selected `json:"included"`
+	Excluded []Excluded `json:"excluded"`
+	Size     int        `json:"size"`   // Characters of standards content injected
+	Budget   int        `json:"budget"` // Size limit; 0 means unlimited
+}
+
+// Select chooses the standards relevant to ctx within budget characters
+// (0 = unlimited). Standards without applies_to or tags always apply;
+// scoped standards apply when a glob matches a path in ctx, a tag appears
+// in its text, or their key and description share enough words with it.
+// An empty context selects every standard. Selected standards are ordered
+// by priority, then relevance, then key, and those that overflow the
+// budget are excluded.
+func Select(all []Standard, ctx Context, budget int) Selection {
+	words := wordSet(ctx.Text)
+	var candidates []Selected
+	sel := Selection{Budget: budget}
+
+	for _, s := range all {
+		var reasons []string
+		score := 0
+		relevant := false
+		switch {
+		case ctx.Empty():
+			reasons = append(reasons, "no story context")
+			relevant = true
+		case !s.Scoped():
+			reasons = append(reasons, "general standard (no applies_to or tags)")
+			relevant = true
+		}
+		if !ctx.Empty() {
+			if glob, path := matchPaths(s.AppliesTo, ctx.Paths); glob != "" {
+				reasons = append(reasons, fmt.Sprintf("applies_to %s matches %s", glob, path))
+				score += 100
+				relevant = true
+			}
+			for _, tag := range s.Tags {
+				if containsWords(words, tag) {
+					reasons = append(reasons, fmt.Sprintf("tag %q", tag))
+					score += 10
+					relevant = true
+				}
+			}
+			if hits := keywordHits(s, words); len(hits) > 0 {
+				score += len(hits)
+				if len(hits) >= 2 || !s.Scoped() {
+					reasons = append(reasons, "keywords "+strings.Join(hits, ", "))
+					relevant = relevant || len(hits) >= 2
+				}
+			}
+		}
+		if !relevant {
+			sel.Excluded = append(sel.Excluded, Excluded{Key: s.Key, Reason: "no matching paths, tags or keywords"})
+			continue
+		}
+		candidates = append(candidates, Selected{Standard: s, Reasons: reasons, Size: len(s.Content), score: score})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.Priority != b.Priority {
+			return a.Priority > b.Priority
+		}
+		if a.score != b.score {
+			return a.score > b.score
+		}
+		return a.Key < b.Key
+	})
+
+	for _, c := range candidates {
+		if budget > 0 && sel.Size+c.Size > budget {
+			sel.Excluded = append(sel.Excluded, Excluded{
+				Key:    c.Key,
+				Reason: fmt.Sprintf("over budget (%d chars, %d of %d used)", c.Size, sel.Size, budget),
+			})
+			continue
+		}
+		sel.Size += c.Size
+		sel.Included = append(sel.Included, c)
+	}
+	return sel
+}
+
+// Render formats the selected standards for the {{STANDARDS}} placeholder.
+// Returns empty string if nothing was selected.
+func (s Selection) Render() string {
+	if len(s.Included) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("## Project Standards\n\n")
+	b.WriteString("You MUST follow these project-specific standards when implementing:\n\n")
+	for i, std := range s.Included {
+		if i > 0 {
+			b.WriteString("\n\n---\n\n")
+		}
+		b.WriteString(fmt.Sprintf("### %s\n\n%s", std.Key, std.Content))
+	}
+	return b.String()
+}
+
+// matchPaths returns the first glob that matches one of paths, and the path.
+// A directory path such as "internal/engine" also matches globs for files
+// beneath it.
+func matchPaths(globs, paths []string) (string, string) {
+	for _, glob := range globs {
+		re, err := globRegexp(glob)
+		if err != nil {
+			continue
+		}
+		for _, path := range paths {
+			if re.MatchString(path) {
+				return glob, path
+			}
+			if filepath.Ext(path) == "" && re.MatchString(strings.TrimSuffix(path, "/")+"/x") {
+				return glob, path
+			}
+		}
+	}
+	return "", ""
+}
+
+// globRegexp compiles a path glob where * matches within a path segment,
+// ** matches across segments and ? matches one character. A glob without a
+// slash matches the file name in any directory.
+func globRegexp(glob string) (*regexp.Regexp, error) {
+	glob = strings.TrimPrefix(filepath.ToSlash(strings.TrimSpace(glob)), "./")
+	if !strings.Contains(glob, "/") {
+		glob = "**/" + glob
+	}
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				i++
+				if i+1 < len(glob) && glob[i+1] == '/' {
+					i++
+					b.WriteString("(?:.*/)?")
+				} else {
+					b.WriteString(".*")
+				}
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+var wordPattern = regexp.MustCompile("[a-z0-9]+")
+
+// stopWords are too common in stories to signal relevance.
+var stopWords = map[string]bool{
+	"about": true, "after": true, "before": true, "should": true, "that": true,
+	"their": true, "there": true, "these": true, "this": true, "when": true,
+	"where": true, "which": true, "with": true, "without": true, "from": true,
+	"into": true, "must": true, "never": true, "always": true, "uses": true,
+	"using": true, "each": true, "every": true, "only": true, "have": true,
+}
+
+// wordSet returns the lowercase words of text.
+func wordSet(text string) map[string]bool {
+	words := map[string]bool{}
+	for _, w := range wordPattern.FindAllString(strings.ToLower(text), -1) {
+		words[w] = true
+	}
+	return words
+}
+
+// containsWords reports whether every word of phrase is in words.
+func containsWords(words map[string]bool, phrase string) bool {
+	parts := wordPattern.FindAllString(strings.ToLower(phrase), -1)
+	if len(parts) == 0 {
+		return false
+	}
+	for _, p := range parts {
+		if !words[p] {
+			return false
+		}
+	}
+	return true
+}
+
+// keywordHits returns the distinctive words of a standard's key and
+// description that also appear in the context words.
+func keywordHits(s Standard, words map[string]bool) []string {
+	var hits []string
+	seen := map[string]bool{}
+	for _, w := range wordPattern.FindAllString(strings.ToLower(s.Key+" "+s.Description), -1) {
+		if len(w) < 4 || stopWords[w] || seen[w] {
+			continue
+		}
+		seen[w] = true
+		if words[w] {
+			hits = append(hits, w)
+		}
+	}
+	return hits
+}
@@ -0,0 +1,137 @@
+package standards
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadAll_FrontMatterAndIndex(t *testing.T) {
+	halDir := t.TempDir()
+	writeStandard(t, halDir, "standards/engine/adapter.md",
+		"---\napplies_to: [\"internal/engine/**\"]\npriority: 5\n---\n# Adapter\n\nEngines self-register via init().")
+	writeStandard(t, halDir, "standards/db/migrations.md", "Migrations are reversible.")
+	writeStandard(t, halDir, "standards/index.yml",
+		"db:\n  migrations:\n    description: Reversible schema migrations\n    tags: [migration]\n    priority: 2\n")
+
+	all, err := LoadAll(halDir)
+	if err != nil {
+		t.Fatalf("LoadAll() error: %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("LoadAll() = %d standards, want 2", len(all))
+	}
+
+	db, eng := all[0], all[1]
+	if db.Key != "db/migrations" || db.Description != "Reversible schema migrations" || db.Priority != 2 || len(db.Tags) != 1 {
+		t.Errorf("db/migrations = %+v, want index metadata", db)
+	}
+	if eng.Key != "engine/adapter" || eng.Priority != 5 || len(eng.AppliesTo) != 1 {
+		t.Errorf("engine/adapter = %+v, want front matter metadata", eng)
+	}
+	if strings.Contains(eng.Content, "applies_to") || !strings.HasPrefix(eng.Content, "# Adapter") {
+		t.Errorf("front matter not stripped from content: %q", eng.Content)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	all := []Standard{
+		{Key: "global/naming", Content: "Use camelCase."},
+		{Key: "engine/adapter", AppliesTo: []string{"internal/engine/**"}, Content: "Engines self-register."},
+		{Key: "db/migrations", Tags: []string{"migration"}, Priority: 3, Content: "Migrations are reversible."},
+		{Key: "frontend/forms", Description: "Form validation with zod schemas", AppliesTo: []string{"web/**"}, Content: "Validate with zod."},
+	}
+
+	tests := []struct {
+		name     string
+		ctx      Context
+		budget   int
+		want     []string
+		excluded []string
+	}{
+		{
+			name: "empty context selects everything",
+			ctx:  Context{},
+			want: []string{"db/migrations", "engine/adapter", "frontend/forms", "global/naming"},
+		},
+		{
+			name:     "path glob",
+			ctx:      NewContext("Add retry to internal/engine/claude.go"),
+			want:     []string{"engine/adapter", "global/naming"},
+			excluded: []string{"db/migrations", "frontend/forms"},
+		},
+		{
+			name: "directory mention matches globs beneath it",
+			ctx:  NewContext("Refactor `internal/engine`"),
+			want: []string{"engine/adapter", "global/naming"},
+		},
+		{
+			name: "tag ranks by priority first",
+			ctx:  NewContext("Add a migration for the users table"),
+			want: []string{"db/migrations", "global/naming"},
+		},
+		{
+			name: "description keywords",
+			ctx:  NewContext("Signup form", "Add validation to the signup form"),
+			want: []string{"frontend/forms", "global/naming"},
+		},
+		{
+			name:     "budget excludes overflow",
+			ctx:      NewContext("Add a migration in internal/engine/db.go"),
+			budget:   len("Migrations are reversible.") + len("Engines self-register."),
+			want:     []string{"db/migrations", "engine/adapter"},
+			excluded: []string{"frontend/forms", "global/naming"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sel := Select(all, tt.ctx, tt.budget)
+			var got []string
+			for _, s := range sel.Included {
+				got = append(got, s.Key)
+				if len(s.Reasons) == 0 {
+					t.Errorf("%s selected without a reason", s.Key)
+				}
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("included = %v, want %v", got, tt.want)
+			}
+			var excluded []string
+			for _, e := range sel.Excluded {
+				excluded = append(excluded, e.Key)
+			}
+			for _, key := range tt.excluded {
+				if !strings.Contains(strings.Join(excluded, ","), key) {
+					t.Errorf("excluded = %v, want %s", excluded, key)
+				}
+			}
+			if tt.budget > 0 && sel.Size > tt.budget {
+				t.Errorf("size %d exceeds budget %d", sel.Size, tt.budget)
+			}
+		})
+	}
+}
+
+func TestGlobRegexp(t *testing.T) {
+	tests := []struct {
+		glob, path string
+		want       bool
+	}{
+		{"internal/engine/**", "internal/engine/claude/claude.go", true},
+		{"internal/*/loop.go", "internal/loop/loop.go", true},
+		{"internal/*.go", "internal/loop/loop.go", false},
+		{"*.sql", "db/migrations/001_users.sql", true},
+		{"cmd/**/*_test.go", "cmd/run_test.go", true},
+		{"cmd/**/*_test.go", "cmd/run.go", false},
+	}
+	for _, tt := range tests {
+		re, err := globRegexp(tt.glob)
+		if err != nil {
+			t.Fatalf("globRegexp(%q) error: %v", tt.glob, err)
+		}
+		if got := re.MatchString(filepath.ToSlash(tt.path)); got != tt.want {
+			t.Errorf("glob %q on %q = %v, want %v", tt.glob, tt.path, got, tt.want)
+		}
+	}
+}
@@ -15,7 +15,9 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/jywlabs/hal/internal/runstatus"
 	"github.com/jywlabs/hal/internal/template"
 )
 
@@ -71,6 +73,7 @@ type StatusResult struct {
 	Compound   *CompoundDetail   `json:"compound,omitempty"`
 	ReviewLoop *ReviewLoopDetail `json:"reviewLoop,omitempty"`
 	Paths      *StatusPaths      `json:"paths,omitempty"`
+	Run        *RunDetail        `json:"run,omitempty"`
 }
 
 // ManualDetail provides story-level detail for manual workflows.
@@ -99,6 +102,33 @@ type ReviewLoopDetail struct {
 	LatestReport string `json:"latestReport,omitempty"`
 }
 
+// RunDetail describes the latest hal run loop and the hal ctl requests it
+// has not applied yet.
+type RunDetail struct {
+	State         string `json:"state"`
+	Iteration     int    `json:"iteration"`
+	MaxIterations int    `json:"maxIterations"`
+	StoryID       string `json:"storyId,omitempty"`
+	// Controllable is true while the loop is running or paused and accepts
+	// hal ctl commands.
+	Controllable bool `json:"controllable"`
+	// StaleReason is set when the state says running or paused but the loop
+	// process has exited or stopped updating run-status.json.
+	StaleReason      string    `json:"staleReason,omitempty"`
+	Paused           bool      `json:"paused"`
+	StopAfterCurrent bool      `json:"stopAfterCurrent"`
+	PendingSkips     []string  `json:"pendingSkips,omitempty"`
+	PendingMoves     []RunMove `json:"pendingMoves,omitempty"`
+}
+
+// RunMove is a pending hal ctl reprioritize request.
+type RunMove struct {
+	StoryID  string `json:"storyId"`
+	Position int    `json:"position,omitempty"`
+	Before   string `json:"before,omitempty"`
+	After    string `json:"after,omitempty"`
+}
+
 // StatusPaths lists canonical file paths relevant to the current state.
 type StatusPaths struct {
 	PRDJson   string `json:"prdJson,omitempty"`
@@ -145,6 +175,14 @@ type prdStory struct {
 
 // Get inspects the filesystem at dir (project root) and returns the current workflow status.
 func Get(dir string) StatusResult {
+	result := classify(dir)
+	if result.Artifacts.HalDir {
+		result.Run = detectRun(filepath.Join(dir, template.HalDir))
+	}
+	return result
+}
+
+func classify(dir string) StatusResult {
 	halDir := filepath.Join(dir, template.HalDir)
 
 	artifacts := detectArtifacts(dir, halDir)
@@ -224,6 +262,45 @@ func Get(dir string) StatusResult {
 	return classifyManual(dir, halDir, artifacts)
 }
 
+// detectRun reads .hal/run-status.json and .hal/run-control.json. It returns
+// nil when no run has been recorded.
+func detectRun(halDir string) *RunDetail {
+	data, err := os.ReadFile(filepath.Join(halDir, template.RunStatusFile))
+	if err != nil {
+		return nil
+	}
+	var run runstatus.Status
+	if json.Unmarshal(data, &run) != nil {
+		return nil
+	}
+	detail := &RunDetail{
+		State:         run.State,
+		Iteration:     run.Iteration,
+		MaxIterations: run.MaxIterations,
+		StoryID:       run.StoryID,
+		StaleReason:   run.StaleReason(time.Now()),
+	}
+	detail.Controllable = run.Active() && detail.StaleReason == ""
+	if !detail.Controllable {
+		return detail
+	}
+	if data, err := os.ReadFile(filepath.Join(halDir, template.RunControlFile)); err == nil {
+		var control struct {
+			Paused           bool      `json:"paused"`
+			StopAfterCurrent bool      `json:"stopAfterCurrent"`
+			Skip             []string  `json:"skip"`
+			Moves            []RunMove `json:"moves"`
+		}
+		if json.Unmarshal(data, &control) == nil {
+			detail.Paused = control.Paused
+			detail.StopAfterCurrent = control.StopAfterCurrent
+			detail.PendingSkips = control.Skip
+			detail.PendingMoves = control.Moves
+		}
+	}
+	return detail
+}
+
 func detectArtifacts(dir, halDir string) Artifacts {
 	a := Artifacts{}
 
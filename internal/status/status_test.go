@@ -697,3 +697,37 @@ func TestGet_GracefulWithMinimalSetup(t *testing.T) {
 		t.Fatal("summary should not be empty")
 	}
 }
+
+func TestGet_RunDetail(t *testing.T) {
+	dir := t.TempDir()
+	halDir := filepath.Join(dir, template.HalDir)
+	os.MkdirAll(halDir, 0755)
+	os.WriteFile(filepath.Join(halDir, template.PRDFile), []byte(`{"userStories":[{"id":"US-001","title":"A","passes":false}]}`), 0644)
+
+	if result := Get(dir); result.Run != nil {
+		t.Fatalf("run = %+v, want nil without run-status.json", result.Run)
+	}
+
+	os.WriteFile(filepath.Join(halDir, template.RunStatusFile), []byte(`{"state":"paused","iteration":3,"maxIterations":10,"storyId":"US-001"}`), 0644)
+	os.WriteFile(filepath.Join(halDir, template.RunControlFile), []byte(`{"paused":true,"skip":["US-001"],"moves":[{"storyId":"US-002","position":1}]}`), 0644)
+
+	run := Get(dir).Run
+	if run == nil || run.State != "paused" || !run.Controllable || !run.Paused || run.Iteration != 3 {
+		t.Fatalf("run = %+v, want controllable paused run at iteration 3", run)
+	}
+	if len(run.PendingSkips) != 1 || len(run.PendingMoves) != 1 || run.PendingMoves[0].StoryID != "US-002" {
+		t.Fatalf("pending = %v %v", run.PendingSkips, run.PendingMoves)
+	}
+
+	os.WriteFile(filepath.Join(halDir, template.RunStatusFile), []byte(`{"state":"running","iteration":3,"maxIterations":10,"updatedAt":"2020-01-01T00:00:00Z"}`), 0644)
+	run = Get(dir).Run
+	if run == nil || run.Controllable || run.Paused || !strings.Contains(run.StaleReason, "has not updated") {
+		t.Fatalf("run = %+v, want stale run without control state", run)
+	}
+
+	os.WriteFile(filepath.Join(halDir, template.RunStatusFile), []byte(`{"state":"complete","iteration":4,"maxIterations":10}`), 0644)
+	run = Get(dir).Run
+	if run == nil || run.Controllable || run.Paused || len(run.PendingSkips) != 0 || run.StaleReason != "" {
+		t.Fatalf("run = %+v, want finished run without control state", run)
+	}
+}
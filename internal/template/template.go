@@ -1,7 +1,9 @@
 package template
 
 import (
-	_ "embed"
+	"embed"
+	"io/fs"
+	"strings"
 )
 
 //go:embed prompt.md
@@ -13,20 +15,30 @@ var DefaultProgress string
 //go:embed config.yaml
 var DefaultConfig string
 
+//go:embed prdtemplates/*.md
+var prdTemplatesFS embed.FS
+
 // HalDir is the name of the hal configuration directory.
 const HalDir = ".hal"
 
 // File name constants for consistent usage across the codebase.
 const (
-	PRDFile       = "prd.json"      // Manual flow (plan, convert, validate, run)
-	AutoPRDFile   = "auto-prd.json" // Auto flow (auto, explode)
-	PromptFile    = "prompt.md"
-	ProgressFile  = "progress.txt"    // Unified progress for both flows
-	AutoStateFile = "auto-state.json" // Auto flow pipeline state
-	ConfigFile    = "config.yaml"
-	SandboxFile   = "sandbox.json" // Sandbox state (not archived)
-	StandardsDir  = "standards"    // Project standards directory
-	CommandsDir   = "commands"     // Agent commands directory
+	PRDFile         = "prd.json"      // Manual flow (plan, convert, validate, run)
+	AutoPRDFile     = "auto-prd.json" // Auto flow (auto, explode)
+	PromptFile      = "prompt.md"
+	ProgressFile    = "progress.txt"    // Unified progress for both flows
+	AutoStateFile   = "auto-state.json" // Auto flow pipeline state
+	ConfigFile      = "config.yaml"
+	SandboxFile     = "sandbox.json"      // Sandbox state (not archived)
+	RunStatusFile   = "run-status.json"   // Live state of the current hal run loop
+	RunControlFile  = "run-control.json"  // hal ctl requests for the running loop
+	EngineProbeFile = "engine-probe.json" // Cached hal doctor --deep results
+	StandardsDir    = "standards"         // Project standards directory
+	CommandsDir     = "commands"          // Agent commands directory
+	// PRDTemplatesDir holds PRD templates for hal plan --template.
+	PRDTemplatesDir = "templates/prd"
+	// RepairBackupsDir holds hal repair journals used by hal repair --undo.
+	RepairBackupsDir = "repair-backups"
 )
 
 // BrowserVerificationCriterion is the canonical acceptance criterion for UI stories.
@@ -41,3 +53,24 @@ func DefaultFiles() map[string]string {
 		ConfigFile:   DefaultConfig,
 	}
 }
+
+// DefaultPRDTemplates returns the example PRD templates hal init installs
+// into .hal/templates/prd/, keyed by file name.
+func DefaultPRDTemplates() map[string]string {
+	files := make(map[string]string)
+	entries, err := fs.ReadDir(prdTemplatesFS, "prdtemplates")
+	if err != nil {
+		return files
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
+			continue
+		}
+		data, err := fs.ReadFile(prdTemplatesFS, "prdtemplates/"+entry.Name())
+		if err != nil {
+			continue
+		}
+		files[entry.Name()] = string(data)
+	}
+	return files
+}